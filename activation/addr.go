@@ -0,0 +1,53 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"fmt"
+)
+
+// VsockAddr is the address of an AF_VSOCK socket, as passed by systemd for
+// ListenStream=vsock:CID:PORT, used for communication between virtual
+// machines and their host.
+type VsockAddr struct {
+	CID  uint32 // The context ID, 2 is the host
+	Port uint32
+}
+
+// Network returns "vsock".
+func (a *VsockAddr) Network() string {
+	return "vsock"
+}
+
+func (a *VsockAddr) String() string {
+	return fmt.Sprintf("vsock:%d:%d", a.CID, a.Port)
+}
+
+// NetlinkAddr is the address of an AF_NETLINK socket, as passed by systemd
+// for ListenNetlink=.
+type NetlinkAddr struct {
+	Protocol int    // The netlink family, e.g. NETLINK_ROUTE
+	PID      uint32 // The port ID, 0 is the kernel
+	Groups   uint32 // The multicast groups
+}
+
+// Network returns "netlink".
+func (a *NetlinkAddr) Network() string {
+	return "netlink"
+}
+
+func (a *NetlinkAddr) String() string {
+	return fmt.Sprintf("netlink:%d:%d:%d", a.Protocol, a.PID, a.Groups)
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package activation
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// listenPIDWrapper sets LISTEN_PID to the PID of the shell, which exec
+// keeps for the program, as it is not known before cmd is started.
+const listenPIDWrapper = `LISTEN_PID=$$ exec "$0" "$@"`
+
+// Launch starts cmd with files passed to it by the socket activation
+// protocol, the way systemd passes sockets to services, so that they are
+// returned by Files in the child. names, if not nil, are their names
+// returned by ListenersWithNames and must not contain colons.
+//
+// LISTEN_PID has to be the PID of the child, which is unknown until it is
+// started, so cmd is run through /bin/sh setting it before it executes the
+// program. As a result, the program sees cmd.Path rather than cmd.Args[0] as
+// its argv[0]. cmd must not have ExtraFiles, as the files are passed
+// starting from file descriptor 3.
+func Launch(cmd *exec.Cmd, files []*os.File, names []string) error {
+	if names != nil && len(names) != len(files) {
+		return errors.New("activation: number of names and files differs")
+	}
+	for _, n := range names {
+		if strings.ContainsRune(n, ':') {
+			return errors.New("activation: file name " + strconv.Quote(n) + " contains a colon")
+		}
+	}
+	if len(cmd.ExtraFiles) > 0 {
+		return errors.New("activation: command already has extra files")
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = nil
+	for _, e := range env {
+		if !strings.HasPrefix(e, "LISTEN_PID=") && !strings.HasPrefix(e, "LISTEN_FDS=") && !strings.HasPrefix(e, "LISTEN_FDNAMES=") {
+			cmd.Env = append(cmd.Env, e)
+		}
+	}
+	cmd.Env = append(cmd.Env, "LISTEN_FDS="+strconv.Itoa(len(files)))
+	if names != nil {
+		cmd.Env = append(cmd.Env, "LISTEN_FDNAMES="+strings.Join(names, ":"))
+	}
+	cmd.ExtraFiles = files
+
+	args := []string{"sh", "-c", listenPIDWrapper, cmd.Path}
+	if len(cmd.Args) > 1 {
+		args = append(args, cmd.Args[1:]...)
+	}
+	cmd.Path, cmd.Args = "/bin/sh", args
+	return cmd.Start()
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package activation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestLaunchChild(t *testing.T) {
+	if os.Getenv("LAUNCH_CHILD") == "" {
+		t.Skip("only run as child of TestLaunch")
+	}
+	files := Files(true)
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+		fmt.Fprintf(f, "hello %s", f.Name())
+		f.Close()
+	}
+	fmt.Print(strings.Join(names, " "), " ", os.Getenv("LISTEN_FDS") == "")
+}
+
+func TestLaunch(t *testing.T) {
+	var readers, writers []*os.File
+	for i := 0; i < 2; i++ {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		readers, writers = append(readers, r), append(writers, w)
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestLaunchChild", "-test.count=1")
+	cmd.Env = append(os.Environ(), "LAUNCH_CHILD=1", "LISTEN_PID=1", "LISTEN_FDS=5")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Launch(cmd, writers, []string{"http", "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range writers {
+		w.Close()
+	}
+	stdout, _ := ioutil.ReadAll(out)
+	if err := cmd.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(stdout), "http admin true") {
+		t.Errorf("unexpected child output %q", stdout)
+	}
+	for i, want := range []string{"hello http", "hello admin"} {
+		if b, err := ioutil.ReadAll(readers[i]); err != nil || string(b) != want {
+			t.Errorf("file %d: got %q, %v", i, b, err)
+		}
+	}
+}
+
+func TestLaunchErrors(t *testing.T) {
+	f, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	for i, tt := range []struct {
+		extra []*os.File
+		names []string
+	}{
+		{nil, []string{"a", "b"}},
+		{nil, []string{"a:b"}},
+		{[]*os.File{f}, nil},
+	} {
+		cmd := exec.Command("true")
+		cmd.ExtraFiles = tt.extra
+		if err := Launch(cmd, []*os.File{f}, tt.names); err == nil {
+			cmd.Wait()
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
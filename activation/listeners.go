@@ -17,10 +17,25 @@ package activation
 import (
 	"crypto/tls"
 	"net"
+	"os"
 )
 
+// fileListener is like net.FileListener, but also supports AF_VSOCK
+// sockets on Linux.
+func fileListener(f *os.File) (net.Listener, error) {
+	l, err := net.FileListener(f)
+	if err == nil {
+		return l, nil
+	}
+	if l, serr := socketListener(f); serr == nil {
+		return l, nil
+	}
+	return nil, err
+}
+
 // Listeners returns a slice containing a net.Listener for each matching socket type
-// passed to this process.
+// passed to this process. AF_VSOCK sockets are supported on Linux, their
+// listeners and connections have *VsockAddr addresses.
 //
 // The order of the file descriptors is preserved in the returned slice.
 // Nil values are used to fill any gaps. For example if systemd were to return file descriptors
@@ -30,7 +45,7 @@ func Listeners() ([]net.Listener, error) {
 	listeners := make([]net.Listener, len(files))
 
 	for i, f := range files {
-		if pc, err := net.FileListener(f); err == nil {
+		if pc, err := fileListener(f); err == nil {
 			listeners[i] = pc
 			f.Close()
 		}
@@ -44,7 +59,7 @@ func ListenersWithNames() (map[string][]net.Listener, error) {
 	listeners := map[string][]net.Listener{}
 
 	for _, f := range files {
-		if pc, err := net.FileListener(f); err == nil {
+		if pc, err := fileListener(f); err == nil {
 			current, ok := listeners[f.Name()]
 			if !ok {
 				listeners[f.Name()] = []net.Listener{pc}
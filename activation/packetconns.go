@@ -16,10 +16,25 @@ package activation
 
 import (
 	"net"
+	"os"
 )
 
+// filePacketConn is like net.FilePacketConn, but also supports AF_NETLINK
+// sockets on Linux.
+func filePacketConn(f *os.File) (net.PacketConn, error) {
+	pc, err := net.FilePacketConn(f)
+	if err == nil {
+		return pc, nil
+	}
+	if pc, serr := socketPacketConn(f); serr == nil {
+		return pc, nil
+	}
+	return nil, err
+}
+
 // PacketConns returns a slice containing a net.PacketConn for each matching socket type
-// passed to this process.
+// passed to this process. AF_NETLINK sockets are supported on Linux, their
+// connections use *NetlinkAddr addresses.
 //
 // The order of the file descriptors is preserved in the returned slice.
 // Nil values are used to fill any gaps. For example if systemd were to return file descriptors
@@ -29,7 +44,7 @@ func PacketConns() ([]net.PacketConn, error) {
 	conns := make([]net.PacketConn, len(files))
 
 	for i, f := range files {
-		if pc, err := net.FilePacketConn(f); err == nil {
+		if pc, err := filePacketConn(f); err == nil {
 			conns[i] = pc
 			f.Close()
 		}
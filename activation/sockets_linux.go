@@ -0,0 +1,180 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+const afVsock = 40
+
+// socketListener wraps sockets of the families the net package does not
+// support as listeners.
+func socketListener(f *os.File) (net.Listener, error) {
+	domain, err := getsockoptInt(f, syscall.SO_DOMAIN)
+	if err != nil {
+		return nil, err
+	}
+	if domain != afVsock {
+		return nil, errors.New("unsupported socket family")
+	}
+	if accepting, err := getsockoptInt(f, syscall.SO_ACCEPTCONN); err != nil || accepting == 0 {
+		return nil, errors.New("socket is not listening")
+	}
+	return newVsockListener(f)
+}
+
+// socketPacketConn wraps sockets of the families the net package does not
+// support as packet connections.
+func socketPacketConn(f *os.File) (net.PacketConn, error) {
+	domain, err := getsockoptInt(f, syscall.SO_DOMAIN)
+	if err != nil {
+		return nil, err
+	}
+	if domain != syscall.AF_NETLINK {
+		return nil, errors.New("unsupported socket family")
+	}
+	return newNetlinkConn(f)
+}
+
+func getsockoptInt(f *os.File, opt int) (int, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var v int
+	var serr error
+	if err := rc.Control(func(fd uintptr) {
+		v, serr = syscall.GetsockoptInt(int(fd), syscall.SOL_SOCKET, opt)
+	}); err != nil {
+		return 0, err
+	}
+	return v, serr
+}
+
+// pollable returns a non-blocking duplicate of the socket that uses the
+// runtime poller, leaving f untouched like net.FileListener does.
+func pollable(f *os.File) (*os.File, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	nfd := -1
+	var serr error
+	if err := rc.Control(func(fd uintptr) {
+		syscall.ForkLock.RLock()
+		nfd, serr = syscall.Dup(int(fd))
+		if serr == nil {
+			syscall.CloseOnExec(nfd)
+		}
+		syscall.ForkLock.RUnlock()
+	}); err != nil {
+		return nil, err
+	}
+	if serr != nil {
+		return nil, os.NewSyscallError("dup", serr)
+	}
+	if err := syscall.SetNonblock(nfd, true); err != nil {
+		syscall.Close(nfd)
+		return nil, os.NewSyscallError("setnonblock", err)
+	}
+	return os.NewFile(uintptr(nfd), f.Name()), nil
+}
+
+// netlinkConn is a net.PacketConn for AF_NETLINK sockets, with NetlinkAddr
+// addresses.
+type netlinkConn struct {
+	f     *os.File
+	rc    syscall.RawConn
+	local *NetlinkAddr
+}
+
+func newNetlinkConn(f *os.File) (*netlinkConn, error) {
+	protocol, err := getsockoptInt(f, syscall.SO_PROTOCOL)
+	if err != nil {
+		return nil, err
+	}
+	nf, err := pollable(f)
+	if err != nil {
+		return nil, err
+	}
+	c := &netlinkConn{f: nf, local: &NetlinkAddr{Protocol: protocol}}
+	if c.rc, err = nf.SyscallConn(); err != nil {
+		nf.Close()
+		return nil, err
+	}
+	c.rc.Control(func(fd uintptr) {
+		if sa, err := syscall.Getsockname(int(fd)); err == nil {
+			if nl, ok := sa.(*syscall.SockaddrNetlink); ok {
+				c.local.PID, c.local.Groups = nl.Pid, nl.Groups
+			}
+		}
+	})
+	return c, nil
+}
+
+func (c *netlinkConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	var n int
+	var from syscall.Sockaddr
+	var serr error
+	err := c.rc.Read(func(fd uintptr) bool {
+		n, from, serr = syscall.Recvfrom(int(fd), b, 0)
+		return serr != syscall.EAGAIN
+	})
+	if err == nil {
+		err = serr
+	}
+	if err != nil {
+		return 0, nil, &net.OpError{Op: "read", Net: "netlink", Addr: c.local, Err: err}
+	}
+	addr := &NetlinkAddr{Protocol: c.local.Protocol}
+	if nl, ok := from.(*syscall.SockaddrNetlink); ok {
+		addr.PID, addr.Groups = nl.Pid, nl.Groups
+	}
+	return n, addr, nil
+}
+
+func (c *netlinkConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	a, ok := addr.(*NetlinkAddr)
+	if !ok {
+		return 0, &net.OpError{Op: "write", Net: "netlink", Addr: addr, Err: syscall.EINVAL}
+	}
+	sa := &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK, Pid: a.PID, Groups: a.Groups}
+	var serr error
+	err := c.rc.Write(func(fd uintptr) bool {
+		serr = syscall.Sendto(int(fd), b, 0, sa)
+		return serr != syscall.EAGAIN
+	})
+	if err == nil {
+		err = serr
+	}
+	if err != nil {
+		return 0, &net.OpError{Op: "write", Net: "netlink", Addr: addr, Err: err}
+	}
+	return len(b), nil
+}
+
+func (c *netlinkConn) Close() error                       { return c.f.Close() }
+func (c *netlinkConn) LocalAddr() net.Addr                { return c.local }
+func (c *netlinkConn) SetDeadline(t time.Time) error      { return c.f.SetDeadline(t) }
+func (c *netlinkConn) SetReadDeadline(t time.Time) error  { return c.f.SetReadDeadline(t) }
+func (c *netlinkConn) SetWriteDeadline(t time.Time) error { return c.f.SetWriteDeadline(t) }
+
+// SyscallConn returns the raw connection, e.g. to join multicast groups.
+func (c *netlinkConn) SyscallConn() (syscall.RawConn, error) { return c.rc, nil }
@@ -0,0 +1,96 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNetlinkPacketConn(t *testing.T) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		t.Skipf("cannot create netlink socket: %v", err)
+	}
+	f := os.NewFile(uintptr(fd), "netlink")
+	defer f.Close()
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		t.Fatal(err)
+	}
+
+	pc, err := filePacketConn(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pc.Close()
+	local, ok := pc.LocalAddr().(*NetlinkAddr)
+	if !ok || local.Protocol != syscall.NETLINK_ROUTE || local.PID == 0 || local.Network() != "netlink" {
+		t.Errorf("unexpected local address %v", pc.LocalAddr())
+	}
+
+	// Dump the links, which every network namespace has at least one of.
+	req := make([]byte, syscall.NLMSG_HDRLEN+syscall.SizeofIfInfomsg)
+	binary.LittleEndian.PutUint32(req[0:], uint32(len(req)))
+	binary.LittleEndian.PutUint16(req[4:], syscall.RTM_GETLINK)
+	binary.LittleEndian.PutUint16(req[6:], syscall.NLM_F_REQUEST|syscall.NLM_F_DUMP)
+	binary.LittleEndian.PutUint32(req[8:], 1)
+	req[syscall.NLMSG_HDRLEN] = syscall.AF_UNSPEC
+	if _, err := pc.WriteTo(req, &NetlinkAddr{}); err != nil {
+		t.Fatal(err)
+	}
+
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	buf := make([]byte, 1<<16)
+	n, from, err := pc.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, ok := from.(*NetlinkAddr); !ok || a.PID != 0 {
+		t.Errorf("reply not from kernel: %v", from)
+	}
+	if n < syscall.NLMSG_HDRLEN || binary.LittleEndian.Uint16(buf[4:]) != syscall.RTM_NEWLINK {
+		t.Errorf("unexpected reply %x", buf[:n])
+	}
+
+	if _, err := pc.WriteTo(req, &net.UDPAddr{}); err == nil {
+		t.Error("expected error for non-netlink address")
+	}
+}
+
+func TestUnsupportedSockets(t *testing.T) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_ROUTE)
+	if err != nil {
+		t.Skipf("cannot create netlink socket: %v", err)
+	}
+	f := os.NewFile(uintptr(fd), "netlink")
+	defer f.Close()
+	if _, err := fileListener(f); err == nil {
+		t.Error("expected error for netlink listener")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	if _, err := filePacketConn(r); err == nil {
+		t.Error("expected error for pipe")
+	}
+}
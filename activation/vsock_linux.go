@@ -0,0 +1,139 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && !386
+// +build linux,!386
+
+package activation
+
+import (
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// rawSockaddrVM is struct sockaddr_vm, which the syscall package does not
+// know, so accept4 and getsockname are called directly.
+type rawSockaddrVM struct {
+	Family    uint16
+	Reserved1 uint16
+	Port      uint32
+	CID       uint32
+	Flags     uint8
+	Zero      [3]uint8
+}
+
+func (sa *rawSockaddrVM) addr() *VsockAddr {
+	return &VsockAddr{CID: sa.CID, Port: sa.Port}
+}
+
+func vsockName(fd uintptr, trap uintptr) (*VsockAddr, error) {
+	var sa rawSockaddrVM
+	l := uint32(unsafe.Sizeof(sa))
+	if _, _, errno := syscall.Syscall(trap, fd, uintptr(unsafe.Pointer(&sa)), uintptr(unsafe.Pointer(&l))); errno != 0 {
+		return nil, errno
+	}
+	return sa.addr(), nil
+}
+
+// vsockListener is a net.Listener for AF_VSOCK sockets, which accepts
+// vsockConns with VsockAddr addresses.
+type vsockListener struct {
+	f    *os.File
+	rc   syscall.RawConn
+	addr *VsockAddr
+}
+
+func newVsockListener(f *os.File) (net.Listener, error) {
+	nf, err := pollable(f)
+	if err != nil {
+		return nil, err
+	}
+	l := &vsockListener{f: nf}
+	if l.rc, err = nf.SyscallConn(); err != nil {
+		nf.Close()
+		return nil, err
+	}
+	var serr error
+	l.rc.Control(func(fd uintptr) {
+		l.addr, serr = vsockName(fd, syscall.SYS_GETSOCKNAME)
+	})
+	if serr != nil {
+		nf.Close()
+		return nil, os.NewSyscallError("getsockname", serr)
+	}
+	return l, nil
+}
+
+func (l *vsockListener) Accept() (net.Conn, error) {
+	var sa rawSockaddrVM
+	nfd := -1
+	var serr error
+	err := l.rc.Read(func(fd uintptr) bool {
+		n := uint32(unsafe.Sizeof(sa))
+		r, _, errno := syscall.Syscall6(syscall.SYS_ACCEPT4, fd, uintptr(unsafe.Pointer(&sa)), uintptr(unsafe.Pointer(&n)),
+			syscall.SOCK_NONBLOCK|syscall.SOCK_CLOEXEC, 0, 0)
+		if errno == syscall.EAGAIN {
+			return false
+		}
+		if errno != 0 {
+			serr = errno
+		} else {
+			nfd = int(r)
+		}
+		return true
+	})
+	if err == nil && serr != nil {
+		err = os.NewSyscallError("accept4", serr)
+	}
+	if err != nil {
+		return nil, &net.OpError{Op: "accept", Net: "vsock", Addr: l.addr, Err: err}
+	}
+	return &vsockConn{f: os.NewFile(uintptr(nfd), "vsock"), local: l.addr, remote: sa.addr()}, nil
+}
+
+func (l *vsockListener) Close() error   { return l.f.Close() }
+func (l *vsockListener) Addr() net.Addr { return l.addr }
+
+// vsockConn is a connection accepted by a vsockListener.
+type vsockConn struct {
+	f             *os.File
+	local, remote *VsockAddr
+}
+
+func (c *vsockConn) Read(b []byte) (int, error) {
+	n, err := c.f.Read(b)
+	if err != nil && err != io.EOF {
+		return n, &net.OpError{Op: "read", Net: "vsock", Addr: c.remote, Err: err}
+	}
+	return n, err
+}
+
+func (c *vsockConn) Write(b []byte) (int, error) {
+	n, err := c.f.Write(b)
+	if err != nil {
+		return n, &net.OpError{Op: "write", Net: "vsock", Addr: c.remote, Err: err}
+	}
+	return n, nil
+}
+
+func (c *vsockConn) Close() error                       { return c.f.Close() }
+func (c *vsockConn) LocalAddr() net.Addr                { return c.local }
+func (c *vsockConn) RemoteAddr() net.Addr               { return c.remote }
+func (c *vsockConn) SetDeadline(t time.Time) error      { return c.f.SetDeadline(t) }
+func (c *vsockConn) SetReadDeadline(t time.Time) error  { return c.f.SetReadDeadline(t) }
+func (c *vsockConn) SetWriteDeadline(t time.Time) error { return c.f.SetWriteDeadline(t) }
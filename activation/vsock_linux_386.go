@@ -0,0 +1,27 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package activation
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// Socket calls go through socketcall on 386, which the syscall package does
+// not expose for accept4 and getsockname with foreign address families.
+func newVsockListener(f *os.File) (net.Listener, error) {
+	return nil, errors.New("vsock sockets are not supported on linux/386")
+}
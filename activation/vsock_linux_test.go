@@ -0,0 +1,109 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && !386
+// +build linux,!386
+
+package activation
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+const (
+	vmaddrCIDAny   = ^uint32(0)
+	vmaddrCIDLocal = 1
+	vmaddrPortAny  = ^uint32(0)
+)
+
+func vsockSocket(t *testing.T) int {
+	fd, err := syscall.Socket(afVsock, syscall.SOCK_STREAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		t.Skipf("cannot create vsock socket: %v", err)
+	}
+	return fd
+}
+
+func vsockCall(trap uintptr, fd int, sa *rawSockaddrVM) error {
+	_, _, errno := syscall.Syscall(trap, uintptr(fd), uintptr(unsafe.Pointer(sa)), unsafe.Sizeof(*sa))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+func TestVsockListener(t *testing.T) {
+	fd := vsockSocket(t)
+	f := os.NewFile(uintptr(fd), "vsock")
+	defer f.Close()
+	// Bind to any CID with a port chosen by the kernel.
+	if err := vsockCall(syscall.SYS_BIND, fd, &rawSockaddrVM{Family: afVsock, CID: vmaddrCIDAny, Port: vmaddrPortAny}); err != nil {
+		t.Skipf("cannot bind vsock socket: %v", err)
+	}
+	if err := syscall.Listen(fd, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := fileListener(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	addr, ok := l.Addr().(*VsockAddr)
+	if !ok || addr.CID != vmaddrCIDAny || addr.Port == vmaddrPortAny || addr.Network() != "vsock" {
+		t.Fatalf("unexpected listener address %v", l.Addr())
+	}
+
+	t.Run("Accept", func(t *testing.T) {
+		client := vsockSocket(t)
+		defer syscall.Close(client)
+		// Connecting to the local CID needs the vsock_loopback transport.
+		if err := vsockCall(syscall.SYS_CONNECT, client, &rawSockaddrVM{Family: afVsock, CID: vmaddrCIDLocal, Port: addr.Port}); err != nil {
+			t.Skipf("cannot connect to local vsock socket: %v", err)
+		}
+		conn, err := l.Accept()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		if a, ok := conn.RemoteAddr().(*VsockAddr); !ok || a.CID != vmaddrCIDLocal {
+			t.Errorf("unexpected remote address %v", conn.RemoteAddr())
+		}
+		if _, err := syscall.Write(client, []byte("hello")); err != nil {
+			t.Fatal(err)
+		}
+		syscall.Shutdown(client, syscall.SHUT_WR)
+		b, err := ioutil.ReadAll(conn)
+		if err != nil || string(b) != "hello" {
+			t.Errorf("read %q, %v", b, err)
+		}
+	})
+
+	// Close unblocks a pending Accept.
+	done := make(chan error)
+	go func() {
+		_, err := l.Accept()
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	l.Close()
+	if err := <-done; err == nil {
+		t.Error("expected error from Accept after Close")
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package askpassword implements the password agent protocol, both as an
+// agent answering the password queries of systemd-cryptsetup and other
+// services, and as a requester asking agents for secrets like
+// systemd-ask-password does.  See https://systemd.io/PASSWORD_AGENTS/
+package askpassword
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// Dir is the directory system wide queries are placed in, a variable so it
+// can be changed by tests.
+var Dir = "/run/systemd/ask-password"
+
+// UserDir returns the directory the queries of the user's service manager
+// are placed in.
+func UserDir() (string, error) {
+	runtime := os.Getenv("XDG_RUNTIME_DIR")
+	if runtime == "" {
+		return "", errors.New("XDG_RUNTIME_DIR is not set")
+	}
+	return filepath.Join(runtime, "systemd/ask-password"), nil
+}
+
+var (
+	// ErrCanceled is returned by Ask if an agent canceled the query, and
+	// may be returned by agent handlers to cancel it.
+	ErrCanceled = errors.New("password query canceled")
+	// ErrTimeout is returned by Ask if no agent answered in time.
+	ErrTimeout = errors.New("password query timed out")
+)
+
+// Question is a password query, as read from an ask.* file.
+type Question struct {
+	// Path is the file the query was read from.
+	Path string
+
+	PID    int    // The process asking
+	Socket string // Where replies are sent
+	// NotAfter is the CLOCK_MONOTONIC time in microseconds after which
+	// the query is no longer answered, 0 if it does not time out.
+	NotAfter     uint64
+	AcceptCached bool // Cached passwords may be sent
+	Echo         bool // The password may be shown while typed
+	Silent       bool // Nothing is shown while the password is typed
+	Message      string
+	Icon         string
+	ID           string // Identifies the query, e.g. "cryptsetup:/dev/sda2"
+}
+
+func parseBool(s string) bool {
+	switch strings.ToLower(s) {
+	case "1", "yes", "y", "true", "t", "on":
+		return true
+	}
+	return false
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
+
+// ParseQuestion parses the contents of an ask.* file.
+func ParseQuestion(r io.Reader) (*Question, error) {
+	opts, err := unit.DeserializeOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	q := &Question{}
+	for _, o := range opts {
+		if o.Section != "Ask" {
+			continue
+		}
+		switch o.Name {
+		case "PID":
+			if q.PID, err = strconv.Atoi(o.Value); err != nil {
+				return nil, fmt.Errorf("invalid PID %q", o.Value)
+			}
+		case "Socket":
+			q.Socket = o.Value
+		case "NotAfter":
+			if q.NotAfter, err = strconv.ParseUint(o.Value, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid NotAfter %q", o.Value)
+			}
+		case "AcceptCached":
+			q.AcceptCached = parseBool(o.Value)
+		case "Echo":
+			q.Echo = parseBool(o.Value)
+		case "Silent":
+			q.Silent = parseBool(o.Value)
+		case "Message":
+			q.Message = o.Value
+		case "Icon":
+			q.Icon = o.Value
+		case "Id":
+			q.ID = o.Value
+		}
+	}
+	if q.Socket == "" {
+		return nil, errors.New("query has no socket")
+	}
+	return q, nil
+}
+
+// Serialize encodes the query in the format of ask.* files.
+func (q *Question) Serialize() io.Reader {
+	s := &unit.UnitSection{Section: "Ask"}
+	add := func(name, value string) {
+		s.Entries = append(s.Entries, &unit.UnitEntry{Name: name, Value: value})
+	}
+	add("PID", strconv.Itoa(q.PID))
+	add("Socket", q.Socket)
+	add("AcceptCached", formatBool(q.AcceptCached))
+	add("Echo", formatBool(q.Echo))
+	add("Silent", formatBool(q.Silent))
+	add("NotAfter", strconv.FormatUint(q.NotAfter, 10))
+	if q.Message != "" {
+		add("Message", q.Message)
+	}
+	if q.Icon != "" {
+		add("Icon", q.Icon)
+	}
+	if q.ID != "" {
+		add("Id", q.ID)
+	}
+	return unit.SerializeSections([]*unit.UnitSection{s})
+}
+
+func (q *Question) send(msg []byte) error {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: q.Socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Write(msg)
+	return err
+}
+
+// Reply answers the query with one or more passwords.
+func (q *Question) Reply(passwords ...string) error {
+	if len(passwords) == 0 {
+		return errors.New("no password given")
+	}
+	return q.send([]byte("+" + strings.Join(passwords, "\x00")))
+}
+
+// Cancel tells the requester that no password will be given.
+func (q *Question) Cancel() error {
+	return q.send([]byte("-"))
+}
+
+// parseReply parses a reply sent by Reply or Cancel.
+func parseReply(msg []byte) ([]string, error) {
+	if len(msg) == 0 {
+		return nil, errors.New("empty reply")
+	}
+	switch msg[0] {
+	case '-':
+		return nil, ErrCanceled
+	case '+':
+		body := strings.TrimSuffix(string(msg[1:]), "\x00")
+		return strings.Split(body, "\x00"), nil
+	}
+	return nil, fmt.Errorf("invalid reply %q", msg[:1])
+}
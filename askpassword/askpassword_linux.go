@@ -0,0 +1,304 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package askpassword
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/coreos/go-systemd/v22/id128"
+)
+
+const clockMonotonic = 1
+
+// monotonic returns the CLOCK_MONOTONIC time in microseconds, which
+// NotAfter is based on.
+func monotonic() uint64 {
+	var ts syscall.Timespec
+	syscall.Syscall(syscall.SYS_CLOCK_GETTIME, clockMonotonic, uintptr(unsafe.Pointer(&ts)), 0)
+	return uint64(ts.Sec)*1e6 + uint64(ts.Nsec)/1e3
+}
+
+// Expired returns whether the query timed out or the process asking exited,
+// in which case it should not be answered anymore.
+func (q *Question) Expired() bool {
+	if q.NotAfter != 0 && monotonic() > q.NotAfter {
+		return true
+	}
+	return q.PID > 0 && syscall.Kill(q.PID, 0) == syscall.ESRCH
+}
+
+// Questions returns the pending queries in dir, skipping files that cannot
+// be parsed and expired queries.
+func Questions(dir string) ([]*Question, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "ask.*"))
+	if err != nil {
+		return nil, err
+	}
+	var questions []*Question
+	for _, p := range paths {
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		q, err := ParseQuestion(bytes.NewReader(b))
+		if err != nil || q.Expired() {
+			continue
+		}
+		q.Path = p
+		questions = append(questions, q)
+	}
+	return questions, nil
+}
+
+// Agent answers password queries.
+type Agent struct {
+	// Dir is the directory watched for queries, defaulting to Dir.
+	Dir string
+	// Handler returns the passwords to reply to a query with. If it
+	// returns ErrCanceled, the query is canceled, other errors leave it to
+	// other agents.
+	Handler func(ctx context.Context, q *Question) ([]string, error)
+}
+
+// Run answers pending queries and those that are placed in the directory
+// until ctx is done. Every query is handed to Handler once, in the order
+// they appear.
+func (a *Agent) Run(ctx context.Context) error {
+	dir := a.Dir
+	if dir == "" {
+		dir = Dir
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return os.NewSyscallError("inotify_init1", err)
+	}
+	// A non-blocking file is read through the runtime poller and closing
+	// it interrupts pending reads.
+	f := os.NewFile(uintptr(fd), "inotify")
+	defer f.Close()
+	if _, err := syscall.InotifyAddWatch(fd, dir, syscall.IN_CLOSE_WRITE|syscall.IN_MOVED_TO); err != nil {
+		return os.NewSyscallError("inotify_add_watch", err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			f.Close()
+		case <-stop:
+		}
+	}()
+
+	seen := make(map[string]bool)
+	buf := make([]byte, 4096)
+	for {
+		questions, err := Questions(dir)
+		if err != nil {
+			return err
+		}
+		current := make(map[string]bool)
+		for _, q := range questions {
+			current[q.Path] = true
+			if seen[q.Path] {
+				continue
+			}
+			seen[q.Path] = true
+			a.handle(ctx, q)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+		// Forget answered queries, so their names can be reused.
+		for p := range seen {
+			if !current[p] {
+				delete(seen, p)
+			}
+		}
+
+		if _, err := f.Read(buf); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+	}
+}
+
+func (a *Agent) handle(ctx context.Context, q *Question) {
+	passwords, err := a.Handler(ctx, q)
+	if q.Expired() {
+		return
+	}
+	switch {
+	case err == ErrCanceled:
+		q.Cancel()
+	case err == nil:
+		q.Reply(passwords...)
+	}
+}
+
+// Request is a query for a password.
+type Request struct {
+	Message      string
+	Icon         string
+	ID           string
+	AcceptCached bool
+	Echo         bool
+	Silent       bool
+	// Timeout defaults to 90s, like that of systemd-ask-password.
+	Timeout time.Duration
+	// Dir is the directory the query is placed in, defaulting to Dir.
+	Dir string
+}
+
+// Ask places a query for agents to answer and waits for their reply. It
+// returns the passwords, ErrCanceled if an agent canceled the query and
+// ErrTimeout if none answered in time. Only replies from root and the
+// calling user are accepted.
+func Ask(ctx context.Context, r Request) ([]string, error) {
+	dir := r.Dir
+	if dir == "" {
+		dir = Dir
+	}
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = 90 * time.Second
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	id, err := id128.Randomize()
+	if err != nil {
+		return nil, err
+	}
+
+	sock := filepath.Join(dir, "sck."+id.String())
+	fd, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC|syscall.SOCK_NONBLOCK, 0)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	f := os.NewFile(uintptr(fd), sock)
+	defer f.Close()
+	if err := syscall.Bind(fd, &syscall.SockaddrUnix{Name: sock}); err != nil {
+		return nil, os.NewSyscallError("bind", err)
+	}
+	defer os.Remove(sock)
+	if err := syscall.SetsockoptInt(fd, syscall.SOL_SOCKET, syscall.SO_PASSCRED, 1); err != nil {
+		return nil, os.NewSyscallError("setsockopt", err)
+	}
+
+	q := &Question{
+		PID:          os.Getpid(),
+		Socket:       sock,
+		NotAfter:     monotonic() + uint64(timeout/time.Microsecond),
+		AcceptCached: r.AcceptCached,
+		Echo:         r.Echo,
+		Silent:       r.Silent,
+		Message:      r.Message,
+		Icon:         r.Icon,
+		ID:           r.ID,
+	}
+	content, err := ioutil.ReadAll(q.Serialize())
+	if err != nil {
+		return nil, err
+	}
+	// Agents must only see complete files.
+	ask := filepath.Join(dir, "ask."+id.String())
+	tmp := filepath.Join(dir, ".tmp."+id.String())
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, ask); err != nil {
+		os.Remove(tmp)
+		return nil, err
+	}
+	defer os.Remove(ask)
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	f.SetReadDeadline(deadline)
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			f.SetReadDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+
+	return receiveReply(ctx, f)
+}
+
+func receiveReply(ctx context.Context, f *os.File) ([]string, error) {
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 64*1024)
+	oob := make([]byte, syscall.CmsgSpace(syscall.SizeofUcred))
+	for {
+		var n, oobn int
+		var rerr error
+		err := rc.Read(func(fd uintptr) bool {
+			n, oobn, _, _, rerr = syscall.Recvmsg(int(fd), buf, oob, syscall.MSG_CMSG_CLOEXEC)
+			return rerr != syscall.EAGAIN
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if os.IsTimeout(err) {
+				return nil, ErrTimeout
+			}
+			return nil, err
+		}
+		if rerr != nil {
+			return nil, os.NewSyscallError("recvmsg", rerr)
+		}
+		if !trustedSender(oob[:oobn]) {
+			continue
+		}
+		return parseReply(buf[:n])
+	}
+}
+
+// trustedSender returns whether the credentials of a reply are those of
+// root or the calling user.
+func trustedSender(oob []byte) bool {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return false
+	}
+	for _, m := range msgs {
+		if cred, err := syscall.ParseUnixCredentials(&m); err == nil {
+			return cred.Uid == 0 || int(cred.Uid) == os.Getuid()
+		}
+	}
+	return false
+}
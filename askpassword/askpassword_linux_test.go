@@ -0,0 +1,124 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package askpassword
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAskAgent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ask-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	asked := make(chan *Question, 2)
+	agent := &Agent{Dir: dir, Handler: func(ctx context.Context, q *Question) ([]string, error) {
+		asked <- q
+		if q.ID == "cancel" {
+			return nil, ErrCanceled
+		}
+		return []string{"hunter2"}, nil
+	}}
+	done := make(chan error)
+	go func() {
+		done <- agent.Run(ctx)
+	}()
+
+	passwords, err := Ask(ctx, Request{Message: "Passphrase?", ID: "disk", Dir: dir, AcceptCached: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(passwords, []string{"hunter2"}) {
+		t.Errorf("unexpected passwords %q", passwords)
+	}
+	q := <-asked
+	if q.Message != "Passphrase?" || q.PID != os.Getpid() || !q.AcceptCached || q.NotAfter == 0 || q.Expired() {
+		t.Errorf("unexpected query %+v", q)
+	}
+
+	if _, err := Ask(ctx, Request{ID: "cancel", Dir: dir}); err != ErrCanceled {
+		t.Errorf("unexpected error %v", err)
+	}
+	<-asked
+
+	// The files of answered queries are removed.
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("files left behind: %v", files)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("unexpected agent result %v", err)
+	}
+}
+
+func TestAskTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ask-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := Ask(context.Background(), Request{Dir: dir, Timeout: 50 * time.Millisecond}); err != ErrTimeout {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	if _, err := Ask(ctx, Request{Dir: dir}); err != context.Canceled {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestQuestionsExpired(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ask-password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, q := range map[string]*Question{
+		"ask.live":    {PID: os.Getpid(), Socket: "/s", NotAfter: monotonic() + 60e6},
+		"ask.expired": {PID: os.Getpid(), Socket: "/s", NotAfter: 1},
+		"sck.other":   {PID: os.Getpid(), Socket: "/s"},
+	} {
+		b, _ := ioutil.ReadAll(q.Serialize())
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ioutil.WriteFile(filepath.Join(dir, "ask.broken"), []byte("garbage"), 0644)
+
+	questions, err := Questions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(questions) != 1 || filepath.Base(questions[0].Path) != "ask.live" {
+		t.Errorf("unexpected questions %v", questions)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package askpassword
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var errUnsupported = errors.New("password agents are only supported on Linux")
+
+// Expired returns whether the query timed out, which cannot be determined
+// outside of Linux.
+func (q *Question) Expired() bool {
+	return false
+}
+
+// Questions returns the pending queries in dir, which is only supported on
+// Linux.
+func Questions(dir string) ([]*Question, error) {
+	return nil, errUnsupported
+}
+
+// Agent answers password queries, which is only supported on Linux.
+type Agent struct {
+	Dir     string
+	Handler func(ctx context.Context, q *Question) ([]string, error)
+}
+
+// Run answers password queries, which is only supported on Linux.
+func (a *Agent) Run(ctx context.Context) error {
+	return errUnsupported
+}
+
+// Request is a query for a password.
+type Request struct {
+	Message      string
+	Icon         string
+	ID           string
+	AcceptCached bool
+	Echo         bool
+	Silent       bool
+	Timeout      time.Duration
+	Dir          string
+}
+
+// Ask asks agents for a password, which is only supported on Linux.
+func Ask(ctx context.Context, r Request) ([]string, error) {
+	return nil, errUnsupported
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package askpassword
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseQuestion(t *testing.T) {
+	const ask = `[Ask]
+PID=1234
+Socket=/run/systemd/ask-password/sck.1b2c
+AcceptCached=1
+Echo=0
+NotAfter=123456789
+Message=Please enter passphrase for disk root:
+Icon=drive-harddisk
+Id=cryptsetup:/dev/sda2
+`
+	q, err := ParseQuestion(strings.NewReader(ask))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Question{
+		PID:          1234,
+		Socket:       "/run/systemd/ask-password/sck.1b2c",
+		NotAfter:     123456789,
+		AcceptCached: true,
+		Message:      "Please enter passphrase for disk root:",
+		Icon:         "drive-harddisk",
+		ID:           "cryptsetup:/dev/sda2",
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Errorf("got %+v", q)
+	}
+
+	b, err := ioutil.ReadAll(q.Serialize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	q2, err := ParseQuestion(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(q2, want) {
+		t.Errorf("query changed in round trip:\n%s", b)
+	}
+
+	for i, s := range []string{
+		"[Ask]\nPID=1\n",
+		"[Ask]\nPID=x\nSocket=/s\n",
+		"[Ask]\nSocket=/s\nNotAfter=-1\n",
+	} {
+		if _, err := ParseQuestion(strings.NewReader(s)); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestParseReply(t *testing.T) {
+	for i, tt := range []struct {
+		in   string
+		want []string
+		err  error
+	}{
+		{"+secret", []string{"secret"}, nil},
+		{"+", []string{""}, nil},
+		{"+one\x00two\x00", []string{"one", "two"}, nil},
+		{"-", nil, ErrCanceled},
+	} {
+		got, err := parseReply([]byte(tt.in))
+		if err != tt.err || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("case %d: got %q, %v", i, got, err)
+		}
+	}
+	if _, err := parseReply([]byte("?")); err == nil {
+		t.Error("expected error for invalid reply")
+	}
+}
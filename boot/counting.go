@@ -0,0 +1,177 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultRoots are the mount points searched for the ESP and XBOOTLDR
+// partitions by MarkBootSuccessful and MarkBootBad if none are given, a
+// variable so it can be changed by tests.
+var DefaultRoots = []string{"/efi", "/boot", "/boot/efi"}
+
+// BootCounter is the boot assessment counter encoded in the file name of a
+// boot entry or unified kernel image, as in "fedora+2-1.conf". The boot
+// loader decrements Left and increments Done each time it boots the entry,
+// and deprioritizes the entry once Left reaches 0 unless the boot was marked
+// successful before.
+type BootCounter struct {
+	Left int
+	Done int
+}
+
+// Bad returns whether the entry has no tries left and is considered bad.
+func (c *BootCounter) Bad() bool {
+	return c != nil && c.Left == 0
+}
+
+// ParseBootCounter splits the boot counter off the file name name, returning
+// the name without the counter and the counter, or nil if there is none.
+func ParseBootCounter(name string) (string, *BootCounter) {
+	stem, suffix := splitSuffix(name)
+	i := strings.LastIndexByte(stem, '+')
+	if i < 0 {
+		return name, nil
+	}
+
+	counter, done := stem[i+1:], ""
+	j := strings.IndexByte(counter, '-')
+	if j >= 0 {
+		counter, done = counter[:j], counter[j+1:]
+	}
+	c := &BootCounter{}
+	var err error
+	if c.Left, err = parseCount(counter); err != nil {
+		return name, nil
+	}
+	if j >= 0 {
+		if c.Done, err = parseCount(done); err != nil {
+			return name, nil
+		}
+	}
+	return stem[:i] + suffix, c
+}
+
+// FormatBootCounter inserts the counter c into the file name name before its
+// suffix. A nil counter leaves name as it is.
+func FormatBootCounter(name string, c *BootCounter) string {
+	if c == nil {
+		return name
+	}
+	stem, suffix := splitSuffix(name)
+	if c.Done == 0 {
+		return fmt.Sprintf("%s+%d%s", stem, c.Left, suffix)
+	}
+	return fmt.Sprintf("%s+%d-%d%s", stem, c.Left, c.Done, suffix)
+}
+
+// splitSuffix splits name into the part before its last dot and the suffix,
+// such as ".conf" or ".efi".
+func splitSuffix(name string) (string, string) {
+	if i := strings.LastIndexByte(name, '.'); i > 0 {
+		return name[:i], name[i:]
+	}
+	return name, ""
+}
+
+func parseCount(s string) (int, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid boot counter %q", s)
+	}
+	n, err := strconv.ParseUint(s, 10, 31)
+	return int(n), err
+}
+
+// EnableBootCounting renames the boot entry or unified kernel image at path
+// to give it tries boot attempts, as kernel-install does when
+// /etc/kernel/tries exists. The new path is returned.
+func EnableBootCounting(path string, tries int) (string, error) {
+	if tries <= 0 {
+		return "", fmt.Errorf("invalid number of tries %d", tries)
+	}
+	name, _ := ParseBootCounter(filepath.Base(path))
+	newPath := filepath.Join(filepath.Dir(path), FormatBootCounter(name, &BootCounter{Left: tries}))
+	if err := os.Rename(path, newPath); err != nil {
+		return "", err
+	}
+	return newPath, nil
+}
+
+// BootCountPath returns the path of the boot entry or unified kernel image
+// the system was booted with if it has a boot counter, relative to the root
+// of its partition, or an empty string if boot counting is not in effect.
+func BootCountPath() (string, error) {
+	s, err := loaderString("LoaderBootCountPath")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(strings.Replace(s, "\\", "/", -1), "/"), nil
+}
+
+// MarkBootSuccessful marks the current boot as successful by removing the
+// boot counter from the file name of the booted entry, like
+// systemd-bless-boot does from systemd-boot-complete.target. roots are the
+// mount points of the ESP and XBOOTLDR partitions, DefaultRoots if empty.
+// It does nothing if boot counting is not in effect.
+func MarkBootSuccessful(roots ...string) error {
+	return markBoot(roots, func(name string, c *BootCounter) string {
+		return name
+	})
+}
+
+// MarkBootBad marks the booted entry as bad by setting its tries left to 0,
+// so that the boot loader falls back to another entry on the next boot. See
+// MarkBootSuccessful.
+func MarkBootBad(roots ...string) error {
+	return markBoot(roots, func(name string, c *BootCounter) string {
+		return FormatBootCounter(name, &BootCounter{Left: 0, Done: c.Done})
+	})
+}
+
+func markBoot(roots []string, rename func(name string, c *BootCounter) string) error {
+	p, err := BootCountPath()
+	if err != nil || p == "" {
+		return err
+	}
+	name, c := ParseBootCounter(filepath.Base(p))
+	if c == nil {
+		return fmt.Errorf("boot counted entry %s has no counter", p)
+	}
+	newName := rename(name, c)
+
+	if len(roots) == 0 {
+		roots = DefaultRoots
+	}
+	for _, root := range roots {
+		dir := filepath.Join(root, filepath.Dir(p))
+		oldPath := filepath.Join(dir, filepath.Base(p))
+		if _, err := os.Stat(oldPath); os.IsNotExist(err) {
+			// Already renamed, by an earlier call or systemd-bless-boot.
+			if _, err := os.Stat(filepath.Join(dir, newName)); err == nil {
+				return nil
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		return os.Rename(oldPath, filepath.Join(dir, newName))
+	}
+	return fmt.Errorf("booted entry %s not found", p)
+}
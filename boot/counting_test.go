@@ -0,0 +1,158 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseBootCounter(t *testing.T) {
+	tests := []struct {
+		in      string
+		name    string
+		counter *BootCounter
+	}{
+		{"fedora.conf", "fedora.conf", nil},
+		{"fedora+3.conf", "fedora.conf", &BootCounter{Left: 3}},
+		{"fedora+0-5.conf", "fedora.conf", &BootCounter{Left: 0, Done: 5}},
+		{"linux-6.8+2-1.efi", "linux-6.8.efi", &BootCounter{Left: 2, Done: 1}},
+		{"linux+deb1.conf", "linux+deb1.conf", nil},
+		{"linux+1-.conf", "linux+1-.conf", nil},
+		{"linux+-1.conf", "linux+-1.conf", nil},
+		{"noext+4", "noext", &BootCounter{Left: 4}},
+	}
+
+	for i, tt := range tests {
+		name, counter := ParseBootCounter(tt.in)
+		if name != tt.name || !reflect.DeepEqual(counter, tt.counter) {
+			t.Errorf("case %d: expected %q %+v, got %q %+v", i, tt.name, tt.counter, name, counter)
+			continue
+		}
+		if counter != nil && tt.in != "fedora+0-5.conf" {
+			if out := FormatBootCounter(name, counter); out != tt.in {
+				t.Errorf("case %d: expected formatted name %q, got %q", i, tt.in, out)
+			}
+		}
+	}
+	if out := FormatBootCounter("fedora.conf", &BootCounter{Left: 0, Done: 5}); out != "fedora+0-5.conf" {
+		t.Errorf("unexpected formatted name %q", out)
+	}
+}
+
+func TestSortEntriesCounted(t *testing.T) {
+	entries := []*Entry{
+		{ID: "b", SortKey: "os", Counter: &BootCounter{Left: 0, Done: 3}},
+		{ID: "a", SortKey: "os", Counter: &BootCounter{Left: 1, Done: 2}},
+		{ID: "c", SortKey: "os"},
+		{ID: "a", SortKey: "os", Counter: &BootCounter{Left: 2, Done: 1}},
+	}
+	SortEntries(entries)
+
+	var order []string
+	for _, e := range entries {
+		order = append(order, FormatBootCounter(e.ID, e.Counter))
+	}
+	if expected := []string{"c", "a+2-1", "a+1-2", "b+0-3"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("expected order %v, got %v", expected, order)
+	}
+}
+
+func TestMarkBoot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boot-counting-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	efivars := filepath.Join(dir, "efivars")
+	esp := filepath.Join(dir, "efi")
+	xbootldr := filepath.Join(dir, "boot")
+	for _, d := range []string{efivars, filepath.Join(esp, EntriesDir), filepath.Join(xbootldr, EntriesDir)} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	savedVars := EFIVarsDir
+	EFIVarsDir = efivars
+	defer func() { EFIVarsDir = savedVars }()
+	savedRoots := DefaultRoots
+	DefaultRoots = []string{esp, xbootldr}
+	defer func() { DefaultRoots = savedRoots }()
+
+	// Without boot counting there is nothing to do.
+	if err := MarkBootSuccessful(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteEntry(xbootldr, &Entry{ID: "fedora", Title: "Fedora"}); err != nil {
+		t.Fatal(err)
+	}
+	path, err := EnableBootCounting(filepath.Join(xbootldr, EntriesDir, "fedora.conf"), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(path) != "fedora+3.conf" {
+		t.Fatalf("unexpected counted path %s", path)
+	}
+
+	// The boot loader counts down on boot.
+	if err := os.Rename(path, filepath.Join(xbootldr, EntriesDir, "fedora+2-1.conf")); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteStringVariable("LoaderBootCountPath", LoaderGUID, `\loader\entries\fedora+2-1.conf`); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadEntries(xbootldr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != "fedora" || !reflect.DeepEqual(entries[0].Counter, &BootCounter{Left: 2, Done: 1}) {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+
+	if err := MarkBootBad(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(xbootldr, EntriesDir, "fedora+0-1.conf")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteStringVariable("LoaderBootCountPath", LoaderGUID, `\loader\entries\fedora+0-1.conf`); err != nil {
+		t.Fatal(err)
+	}
+	if err := MarkBootSuccessful(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(xbootldr, EntriesDir, "fedora.conf")); err != nil {
+		t.Fatal(err)
+	}
+	// Marking the boot again is fine.
+	if err := MarkBootSuccessful(); err != nil {
+		t.Errorf("unexpected error marking twice: %v", err)
+	}
+
+	if err := WriteStringVariable("LoaderBootCountPath", LoaderGUID, `\loader\entries\missing+1.conf`); err != nil {
+		t.Fatal(err)
+	}
+	if err := MarkBootSuccessful(); err == nil {
+		t.Error("expected error for missing entry")
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// EFIVarsDir is where efivarfs is mounted, a variable so it can be changed
+// by tests.
+var EFIVarsDir = "/sys/firmware/efi/efivars"
+
+// LoaderGUID is the vendor GUID of the variables of the Boot Loader
+// Interface, implemented by systemd-boot and systemd-stub.
+const LoaderGUID = "4a67b082-0a4c-41cf-b6c7-440b29bb8c4f"
+
+// Attributes of EFI variables.
+const (
+	AttrNonVolatile       uint32 = 0x1
+	AttrBootserviceAccess uint32 = 0x2
+	AttrRuntimeAccess     uint32 = 0x4
+
+	// attrsDefault are the attributes systemd sets on the variables it
+	// writes.
+	attrsDefault = AttrNonVolatile | AttrBootserviceAccess | AttrRuntimeAccess
+)
+
+// Features reported by the boot loader in LoaderFeatures.
+const (
+	LoaderFeatureConfigTimeout        uint64 = 1 << 0
+	LoaderFeatureConfigTimeoutOneShot uint64 = 1 << 1
+	LoaderFeatureEntryDefault         uint64 = 1 << 2
+	LoaderFeatureEntryOneShot         uint64 = 1 << 3
+	LoaderFeatureBootCounting         uint64 = 1 << 4
+	LoaderFeatureXBOOTLDR             uint64 = 1 << 5
+	LoaderFeatureRandomSeed           uint64 = 1 << 6
+	LoaderFeatureLoadDriver           uint64 = 1 << 7
+	LoaderFeatureSortKey              uint64 = 1 << 8
+	LoaderFeatureSavedEntry           uint64 = 1 << 9
+	LoaderFeatureDevicetree           uint64 = 1 << 10
+	LoaderFeatureSecureBootEnroll     uint64 = 1 << 11
+	LoaderFeatureRetainShim           uint64 = 1 << 12
+	LoaderFeatureMenuDisable          uint64 = 1 << 13
+)
+
+// IsEFIBoot returns whether the system was booted through UEFI with the EFI
+// variables available.
+func IsEFIBoot() bool {
+	fi, err := os.Stat(EFIVarsDir)
+	return err == nil && fi.IsDir()
+}
+
+func variablePath(name, guid string) string {
+	return filepath.Join(EFIVarsDir, name+"-"+guid)
+}
+
+// ReadVariable returns the attributes and value of the EFI variable name of
+// the vendor guid. A missing variable results in an error satisfying
+// os.IsNotExist.
+func ReadVariable(name, guid string) (uint32, []byte, error) {
+	b, err := ioutil.ReadFile(variablePath(name, guid))
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(b) < 4 {
+		return 0, nil, fmt.Errorf("EFI variable %s is too short", name)
+	}
+	return binary.LittleEndian.Uint32(b), b[4:], nil
+}
+
+// WriteVariable sets the EFI variable name of the vendor guid, or removes it
+// if value is empty. Variables protected with the immutable flag by
+// efivarfs are made writable for the update.
+func WriteVariable(name, guid string, attrs uint32, value []byte) error {
+	p := variablePath(name, guid)
+	restore := makeMutable(p)
+	defer restore()
+
+	if len(value) == 0 {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	// efivarfs requires the attributes and the value in a single write.
+	b := make([]byte, 4+len(value))
+	binary.LittleEndian.PutUint32(b, attrs)
+	copy(b[4:], value)
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadStringVariable returns the value of an EFI variable holding a UTF-16
+// string, as used by the Boot Loader Interface.
+func ReadStringVariable(name, guid string) (string, error) {
+	_, b, err := ReadVariable(name, guid)
+	if err != nil {
+		return "", err
+	}
+	return decodeUTF16(b), nil
+}
+
+// WriteStringVariable sets an EFI variable to a UTF-16 string, or removes it
+// if value is empty.
+func WriteStringVariable(name, guid, value string) error {
+	var b []byte
+	if value != "" {
+		b = encodeUTF16(value)
+	}
+	return WriteVariable(name, guid, attrsDefault, b)
+}
+
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	for len(u) > 0 && u[len(u)-1] == 0 {
+		u = u[:len(u)-1]
+	}
+	return string(utf16.Decode(u))
+}
+
+// encodeUTF16 encodes s as NUL-terminated UTF-16.
+func encodeUTF16(s string) []byte {
+	u := append(utf16.Encode([]rune(s)), 0)
+	b := make([]byte, 2*len(u))
+	for i, c := range u {
+		binary.LittleEndian.PutUint16(b[2*i:], c)
+	}
+	return b
+}
+
+// loaderString reads a string variable of the boot loader, returning an
+// empty string if it is not set.
+func loaderString(name string) (string, error) {
+	s, err := ReadStringVariable(name, LoaderGUID)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return s, err
+}
+
+// LoaderEntries returns the IDs of the boot entries the boot loader found,
+// including the automatically generated ones.
+func LoaderEntries() ([]string, error) {
+	_, b, err := ReadVariable("LoaderEntries", LoaderGUID)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	entries := []string{}
+	for _, s := range strings.Split(decodeUTF16(b), "\x00") {
+		if s != "" {
+			entries = append(entries, s)
+		}
+	}
+	return entries, nil
+}
+
+// LoaderEntrySelected returns the ID of the boot entry the system was
+// booted with.
+func LoaderEntrySelected() (string, error) {
+	return loaderString("LoaderEntrySelected")
+}
+
+// LoaderEntryDefault returns the ID of the default boot entry set from the
+// operating system, overriding loader.conf, or an empty string if unset.
+func LoaderEntryDefault() (string, error) {
+	return loaderString("LoaderEntryDefault")
+}
+
+// SetLoaderEntryDefault sets the default boot entry, or removes the override
+// if id is empty.
+func SetLoaderEntryDefault(id string) error {
+	return WriteStringVariable("LoaderEntryDefault", LoaderGUID, id)
+}
+
+// LoaderEntryOneShot returns the ID of the boot entry to boot next, or an
+// empty string if unset.
+func LoaderEntryOneShot() (string, error) {
+	return loaderString("LoaderEntryOneShot")
+}
+
+// SetLoaderEntryOneShot makes the boot loader boot the entry id on the next
+// boot only, or cancels that if id is empty.
+func SetLoaderEntryOneShot(id string) error {
+	return WriteStringVariable("LoaderEntryOneShot", LoaderGUID, id)
+}
+
+// LoaderConfigTimeout returns the menu timeout set from the operating
+// system, overriding loader.conf, in the format of LoaderConfig.Timeout.
+func LoaderConfigTimeout() (string, error) {
+	return loaderString("LoaderConfigTimeout")
+}
+
+// SetLoaderConfigTimeout sets the menu timeout, in the format of
+// LoaderConfig.Timeout, or removes the override if timeout is empty.
+func SetLoaderConfigTimeout(timeout string) error {
+	return WriteStringVariable("LoaderConfigTimeout", LoaderGUID, timeout)
+}
+
+// SetLoaderConfigTimeoutOneShot sets the menu timeout for the next boot
+// only, such as "0" to skip the menu, or cancels that if timeout is empty.
+func SetLoaderConfigTimeoutOneShot(timeout string) error {
+	return WriteStringVariable("LoaderConfigTimeoutOneShot", LoaderGUID, timeout)
+}
+
+// LoaderInfo returns the name and version of the boot loader, such as
+// "systemd-boot 255".
+func LoaderInfo() (string, error) {
+	return loaderString("LoaderInfo")
+}
+
+// LoaderFirmwareInfo returns the name and version of the firmware.
+func LoaderFirmwareInfo() (string, error) {
+	return loaderString("LoaderFirmwareInfo")
+}
+
+// LoaderDevicePartUUID returns the partition UUID of the ESP the boot loader
+// was started from, in lowercase.
+func LoaderDevicePartUUID() (string, error) {
+	s, err := loaderString("LoaderDevicePartUUID")
+	return strings.ToLower(s), err
+}
+
+// LoaderFeatures returns the LoaderFeature flags of the boot loader, or 0
+// if it does not report any.
+func LoaderFeatures() (uint64, error) {
+	return loaderUint64("LoaderFeatures")
+}
+
+// loaderUint64 reads a 64-bit variable of the boot loader, returning 0 if it
+// is not set.
+func loaderUint64(name string) (uint64, error) {
+	_, b, err := ReadVariable(name, LoaderGUID)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, fmt.Errorf("invalid %s size %d", name, len(b))
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// LoaderTimes holds the time spent in the firmware and the boot loader,
+// measured from when the CPU was reset.
+type LoaderTimes struct {
+	// Init is when the boot loader was started.
+	Init time.Duration
+	// Exec is when the boot loader started the kernel.
+	Exec time.Duration
+}
+
+// GetLoaderTimes returns the boot time measurements of the boot loader, as
+// shown by systemd-analyze. Missing measurements are 0.
+func GetLoaderTimes() (*LoaderTimes, error) {
+	t := &LoaderTimes{}
+	for _, v := range []struct {
+		name string
+		d    *time.Duration
+	}{
+		{"LoaderTimeInitUSec", &t.Init},
+		{"LoaderTimeExecUSec", &t.Exec},
+	} {
+		s, err := loaderString(v.name)
+		if err != nil {
+			return nil, err
+		}
+		if s == "" {
+			continue
+		}
+		usec, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", v.name, s)
+		}
+		*v.d = time.Duration(usec) * time.Microsecond
+	}
+	return t, nil
+}
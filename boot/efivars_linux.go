@@ -0,0 +1,59 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const fsImmutableFL = 0x10
+
+// The FS_IOC_GETFLAGS and FS_IOC_SETFLAGS ioctls, which are declared with
+// the size of a long, in the generic ioctl encoding.
+var (
+	fsIocGetFlags = uintptr(2<<30 | unsafe.Sizeof(uintptr(0))<<16 | 'f'<<8 | 1)
+	fsIocSetFlags = uintptr(1<<30 | unsafe.Sizeof(uintptr(0))<<16 | 'f'<<8 | 2)
+)
+
+// makeMutable clears the immutable flag efivarfs sets on most variables and
+// returns a function restoring it. Errors are ignored, as writing the
+// variable fails anyway if the flag could not be cleared.
+func makeMutable(path string) func() {
+	f, err := os.Open(path)
+	if err != nil {
+		return func() {}
+	}
+	defer f.Close()
+
+	var flags int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fsIocGetFlags, uintptr(unsafe.Pointer(&flags))); errno != 0 || flags&fsImmutableFL == 0 {
+		return func() {}
+	}
+	cleared := flags &^ fsImmutableFL
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fsIocSetFlags, uintptr(unsafe.Pointer(&cleared))); errno != 0 {
+		return func() {}
+	}
+
+	return func() {
+		f, err := os.Open(path)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fsIocSetFlags, uintptr(unsafe.Pointer(&flags)))
+	}
+}
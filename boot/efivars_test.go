@@ -0,0 +1,124 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEFIVariables(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boot-efivars-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	saved := EFIVarsDir
+	EFIVarsDir = dir
+	defer func() { EFIVarsDir = saved }()
+
+	if !IsEFIBoot() {
+		t.Error("expected EFI boot")
+	}
+
+	write := func(name string, value []byte) {
+		b := append([]byte{0x06, 0, 0, 0}, value...)
+		if err := ioutil.WriteFile(filepath.Join(dir, name+"-"+LoaderGUID), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("LoaderEntries", encodeUTF16("arch.conf\x00fedora.conf\x00auto-reboot-to-firmware-setup"))
+	write("LoaderEntrySelected", encodeUTF16("fedora.conf"))
+	write("LoaderDevicePartUUID", encodeUTF16("0B2E6F7C-1111-4A4A-9C9C-123456789ABC"))
+	write("LoaderTimeInitUSec", encodeUTF16("1500000"))
+	write("LoaderTimeExecUSec", encodeUTF16("2250000"))
+	write("LoaderFeatures", []byte{0x0f, 0x01, 0, 0, 0, 0, 0, 0})
+
+	entries, err := LoaderEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"arch.conf", "fedora.conf", "auto-reboot-to-firmware-setup"}; !reflect.DeepEqual(entries, expected) {
+		t.Errorf("expected entries %v, got %v", expected, entries)
+	}
+	if s, err := LoaderEntrySelected(); err != nil || s != "fedora.conf" {
+		t.Errorf("unexpected selected entry %q, %v", s, err)
+	}
+	if s, err := LoaderDevicePartUUID(); err != nil || s != "0b2e6f7c-1111-4a4a-9c9c-123456789abc" {
+		t.Errorf("unexpected partition UUID %q, %v", s, err)
+	}
+	if features, err := LoaderFeatures(); err != nil || features != 0x10f || features&LoaderFeatureSortKey == 0 {
+		t.Errorf("unexpected features %x, %v", features, err)
+	}
+	times, err := GetLoaderTimes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if times.Init != 1500*time.Millisecond || times.Exec != 2250*time.Millisecond {
+		t.Errorf("unexpected loader times %+v", times)
+	}
+
+	// Unset variables are empty.
+	if s, err := LoaderEntryOneShot(); err != nil || s != "" {
+		t.Errorf("unexpected one-shot entry %q, %v", s, err)
+	}
+	if s, err := LoaderInfo(); err != nil || s != "" {
+		t.Errorf("unexpected loader info %q, %v", s, err)
+	}
+
+	if err := SetLoaderEntryOneShot("arch.conf"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "LoaderEntryOneShot-"+LoaderGUID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := append([]byte{0x07, 0, 0, 0}, encodeUTF16("arch.conf")...); !reflect.DeepEqual(b, expected) {
+		t.Errorf("expected variable %x, got %x", expected, b)
+	}
+	if s, err := LoaderEntryOneShot(); err != nil || s != "arch.conf" {
+		t.Errorf("unexpected one-shot entry %q, %v", s, err)
+	}
+
+	if err := SetLoaderEntryOneShot(""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ReadVariable("LoaderEntryOneShot", LoaderGUID); !os.IsNotExist(err) {
+		t.Errorf("expected removed variable, got %v", err)
+	}
+	if err := SetLoaderEntryOneShot(""); err != nil {
+		t.Errorf("unexpected error removing missing variable: %v", err)
+	}
+
+	if err := SetLoaderConfigTimeoutOneShot("0"); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := ReadStringVariable("LoaderConfigTimeoutOneShot", LoaderGUID); err != nil || s != "0" {
+		t.Errorf("unexpected one-shot timeout %q, %v", s, err)
+	}
+}
+
+func TestUTF16(t *testing.T) {
+	for i, s := range []string{"", "fedora.conf", "Ünïcödé \U0001F600"} {
+		if out := decodeUTF16(encodeUTF16(s)); out != s {
+			t.Errorf("case %d: expected %q, got %q", i, s, out)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// EntriesDir is the directory of Type #1 boot entries below the root of the
+// ESP or XBOOTLDR partition.
+const EntriesDir = "loader/entries"
+
+// Entry is a Boot Loader Specification Type #1 entry, a file in
+// /loader/entries/ ending in ".conf".
+type Entry struct {
+	// ID is the file name of the entry without the ".conf" suffix and
+	// the boot counter, Path the file it was read from, if any.
+	ID   string
+	Path string
+	// Counter is the boot counter in the file name, or nil if boot
+	// counting is not used for the entry.
+	Counter *BootCounter
+
+	Title   string
+	Version string
+	// MachineID is the machine ID of the installation the entry belongs
+	// to, as 32 lowercase hexadecimal characters.
+	MachineID string
+	// SortKey orders the entries of different installations, such as the
+	// ID of the operating system.
+	SortKey string
+
+	Linux  string
+	Initrd []string
+	EFI    string
+	UKI    string
+	// Options are the kernel command line options, one element for each
+	// options line.
+	Options           []string
+	Devicetree        string
+	DevicetreeOverlay []string
+	Architecture      string
+
+	// Extra holds the lines with keys not listed above, in order.
+	Extra []Field
+}
+
+// DisplayTitle returns the title shown in the boot menu for the entry,
+// falling back to its ID.
+func (e *Entry) DisplayTitle() string {
+	if e.Title != "" {
+		return e.Title
+	}
+	return e.ID
+}
+
+// String returns the entry in the format of a boot entry file.
+func (e *Entry) String() string {
+	var b strings.Builder
+	line := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s %s\n", key, value)
+		}
+	}
+
+	line("title", e.Title)
+	line("version", e.Version)
+	line("machine-id", e.MachineID)
+	line("sort-key", e.SortKey)
+	for _, o := range e.Options {
+		line("options", o)
+	}
+	line("linux", e.Linux)
+	for _, i := range e.Initrd {
+		line("initrd", i)
+	}
+	line("efi", e.EFI)
+	line("uki", e.UKI)
+	line("devicetree", e.Devicetree)
+	line("devicetree-overlay", strings.Join(e.DevicetreeOverlay, " "))
+	line("architecture", e.Architecture)
+	for _, f := range e.Extra {
+		fmt.Fprintf(&b, "%s %s\n", f.Key, f.Value)
+	}
+	return b.String()
+}
+
+// ParseEntry parses the contents of a boot entry file. Lines with unknown
+// keys are kept in Extra.
+func ParseEntry(r io.Reader) (*Entry, error) {
+	e := &Entry{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		key, value := splitLine(line)
+		switch key {
+		case "title":
+			e.Title = value
+		case "version":
+			e.Version = value
+		case "machine-id":
+			if !validMachineID(value) {
+				return nil, fmt.Errorf("line %d: invalid machine ID %q", n, value)
+			}
+			e.MachineID = value
+		case "sort-key":
+			e.SortKey = value
+		case "linux":
+			e.Linux = value
+		case "initrd":
+			e.Initrd = append(e.Initrd, value)
+		case "efi":
+			e.EFI = value
+		case "uki":
+			e.UKI = value
+		case "options":
+			e.Options = append(e.Options, value)
+		case "devicetree":
+			e.Devicetree = value
+		case "devicetree-overlay":
+			e.DevicetreeOverlay = append(e.DevicetreeOverlay, strings.Fields(value)...)
+		case "architecture":
+			e.Architecture = strings.ToLower(value)
+		default:
+			e.Extra = append(e.Extra, Field{Key: key, Value: value})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ReadEntry reads the boot entry file at path.
+func ReadEntry(path string) (*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	e, err := ParseEntry(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	name, counter := ParseBootCounter(filepath.Base(path))
+	e.ID = strings.TrimSuffix(name, ".conf")
+	e.Path = path
+	e.Counter = counter
+	return e, nil
+}
+
+// ReadEntries reads the boot entries below root, the mount point of the ESP
+// or XBOOTLDR partition, sorted in the order of the boot menu.
+func ReadEntries(root string) ([]*Entry, error) {
+	files, err := ioutil.ReadDir(filepath.Join(root, EntriesDir))
+	if os.IsNotExist(err) {
+		return []*Entry{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	entries := []*Entry{}
+	for _, fi := range files {
+		if strings.HasPrefix(fi.Name(), ".") || !strings.HasSuffix(fi.Name(), ".conf") || !fi.Mode().IsRegular() {
+			continue
+		}
+		e, err := ReadEntry(filepath.Join(root, EntriesDir, fi.Name()))
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	SortEntries(entries)
+	return entries, nil
+}
+
+// WriteEntry writes e to the file for its ID and boot counter below root,
+// replacing an existing entry of the same name atomically.
+func WriteEntry(root string, e *Entry) error {
+	if e.ID == "" || strings.ContainsAny(e.ID, "/\x00") || strings.HasPrefix(e.ID, ".") {
+		return fmt.Errorf("invalid boot entry ID %q", e.ID)
+	}
+	dir := filepath.Join(root, EntriesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, FormatBootCounter(e.ID+".conf", e.Counter)), strings.NewReader(e.String()))
+}
+
+// SortEntries sorts entries in the order systemd-boot shows them: entries
+// with a sort key first, ordered by sort key, machine ID and then newest
+// version first, followed by the rest ordered by ID, newest first. Entries
+// marked bad by boot counting come last.
+func SortEntries(entries []*Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return compareEntries(entries[i], entries[j]) < 0
+	})
+}
+
+func compareEntries(a, b *Entry) int {
+	if r := compareBool(a.Counter.Bad(), b.Counter.Bad()); r != 0 {
+		return r
+	}
+	if r := compareBool(a.SortKey == "", b.SortKey == ""); r != 0 {
+		return r
+	}
+	if a.SortKey != "" && b.SortKey != "" {
+		if r := strings.Compare(a.SortKey, b.SortKey); r != 0 {
+			return r
+		}
+		if r := strings.Compare(a.MachineID, b.MachineID); r != 0 {
+			return r
+		}
+		if r := CompareVersions(a.Version, b.Version); r != 0 {
+			return -r
+		}
+	}
+	if r := CompareVersions(a.ID, b.ID); r != 0 {
+		return -r
+	}
+
+	// Prefer the entry with more tries left, then fewer tries done.
+	if a.Counter == nil || b.Counter == nil {
+		return 0
+	}
+	if r := b.Counter.Left - a.Counter.Left; r != 0 {
+		return r
+	}
+	return a.Counter.Done - b.Counter.Done
+}
+
+func validMachineID(s string) bool {
+	if len(s) != 32 {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const fedoraEntry = `# Boot Loader Specification type#1 entry
+title      Fedora Linux 40 (Workstation Edition)
+version    6.8.5-301.fc40.x86_64
+machine-id 0123456789abcdef0123456789abcdef
+sort-key   fedora
+options    root=UUID=1234 ro
+options    rhgb quiet
+linux      /0123456789abcdef0123456789abcdef/6.8.5-301.fc40.x86_64/linux
+initrd     /0123456789abcdef0123456789abcdef/6.8.5-301.fc40.x86_64/microcode
+initrd     /0123456789abcdef0123456789abcdef/6.8.5-301.fc40.x86_64/initrd
+devicetree-overlay /a.dtbo /b.dtbo
+architecture X64
+grub_users $grub_users
+`
+
+func TestParseEntry(t *testing.T) {
+	e, err := ParseEntry(strings.NewReader(fedoraEntry))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Entry{
+		Title:     "Fedora Linux 40 (Workstation Edition)",
+		Version:   "6.8.5-301.fc40.x86_64",
+		MachineID: "0123456789abcdef0123456789abcdef",
+		SortKey:   "fedora",
+		Options:   []string{"root=UUID=1234 ro", "rhgb quiet"},
+		Linux:     "/0123456789abcdef0123456789abcdef/6.8.5-301.fc40.x86_64/linux",
+		Initrd: []string{
+			"/0123456789abcdef0123456789abcdef/6.8.5-301.fc40.x86_64/microcode",
+			"/0123456789abcdef0123456789abcdef/6.8.5-301.fc40.x86_64/initrd",
+		},
+		DevicetreeOverlay: []string{"/a.dtbo", "/b.dtbo"},
+		Architecture:      "x64",
+		Extra:             []Field{{Key: "grub_users", Value: "$grub_users"}},
+	}
+	if !reflect.DeepEqual(e, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, e)
+	}
+
+	again, err := ParseEntry(strings.NewReader(e.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, e) {
+		t.Errorf("entry changed after round trip: %+v", again)
+	}
+
+	if _, err := ParseEntry(strings.NewReader("machine-id 1234\n")); err == nil {
+		t.Error("expected error for invalid machine ID")
+	}
+}
+
+func TestSortEntries(t *testing.T) {
+	entries := []*Entry{
+		{ID: "memtest"},
+		{ID: "fedora-6.7", SortKey: "fedora", Version: "6.7.0"},
+		{ID: "arch", SortKey: "arch", Version: "6.8"},
+		{ID: "fedora-6.10", SortKey: "fedora", Version: "6.10.0"},
+		{ID: "windows"},
+		{ID: "fedora-other", SortKey: "fedora", MachineID: "ffffffffffffffffffffffffffffffff", Version: "7.0"},
+		{ID: "linux-5.9"},
+		{ID: "linux-5.10"},
+	}
+	SortEntries(entries)
+
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	expected := []string{"arch", "fedora-6.10", "fedora-6.7", "fedora-other", "windows", "memtest", "linux-5.10", "linux-5.9"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected order %v, got %v", expected, ids)
+	}
+}
+
+func TestReadWriteEntries(t *testing.T) {
+	root, err := ioutil.TempDir("", "boot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	if entries, err := ReadEntries(root); err != nil || len(entries) != 0 {
+		t.Fatalf("expected no entries, got %v, %v", entries, err)
+	}
+
+	for _, e := range []*Entry{
+		{ID: "old", Title: "Old", SortKey: "os", Version: "1"},
+		{ID: "new", Title: "New", SortKey: "os", Version: "2", Options: []string{"quiet"}},
+	} {
+		if err := WriteEntry(root, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, EntriesDir, ".hidden.conf"), []byte("title Hidden\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteEntry(root, &Entry{ID: "../escape"}); err == nil {
+		t.Error("expected error for invalid ID")
+	}
+
+	entries, err := ReadEntries(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != "new" || entries[1].ID != "old" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if entries[0].Path != filepath.Join(root, EntriesDir, "new.conf") || entries[0].Options[0] != "quiet" || entries[0].DisplayTitle() != "New" {
+		t.Errorf("unexpected entry %+v", entries[0])
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoaderConfigFile is the systemd-boot configuration file below the root of
+// the ESP.
+const LoaderConfigFile = "loader/loader.conf"
+
+// Special values of LoaderConfig.Timeout.
+const (
+	TimeoutMenuForce    = "menu-force"
+	TimeoutMenuHidden   = "menu-hidden"
+	TimeoutMenuDisabled = "menu-disabled"
+)
+
+// LoaderConfig is the contents of loader.conf, the configuration of
+// systemd-boot. Boolean settings are nil if not set, leaving the default.
+type LoaderConfig struct {
+	// Default is the ID of the default entry, which may contain glob
+	// patterns like "fedora-*".
+	Default string
+	// Timeout is the menu timeout in seconds, or one of the Timeout
+	// constants.
+	Timeout string
+	// ConsoleMode is one of 0, 1, 2, auto, max or keep.
+	ConsoleMode        string
+	Editor             *bool
+	AutoEntries        *bool
+	AutoFirmware       *bool
+	AutoReboot         *bool
+	AutoPoweroff       *bool
+	Beep               *bool
+	RebootForBitlocker *bool
+	// SecureBootEnroll is one of off, manual, if-safe or force.
+	SecureBootEnroll string
+
+	// Extra holds the lines with keys not listed above, in order.
+	Extra []Field
+}
+
+// TimeoutSeconds returns the menu timeout in seconds, and false if Timeout
+// is not set or not a number.
+func (c *LoaderConfig) TimeoutSeconds() (uint64, bool) {
+	n, err := strconv.ParseUint(c.Timeout, 10, 64)
+	return n, err == nil
+}
+
+type boolSetting struct {
+	key   string
+	value **bool
+}
+
+// bools returns the boolean settings of c, in the order they are written.
+func (c *LoaderConfig) bools() []boolSetting {
+	return []boolSetting{
+		{"editor", &c.Editor},
+		{"auto-entries", &c.AutoEntries},
+		{"auto-firmware", &c.AutoFirmware},
+		{"auto-reboot", &c.AutoReboot},
+		{"auto-poweroff", &c.AutoPoweroff},
+		{"beep", &c.Beep},
+		{"reboot-for-bitlocker", &c.RebootForBitlocker},
+	}
+}
+
+// String returns the configuration in the format of loader.conf.
+func (c *LoaderConfig) String() string {
+	var b strings.Builder
+	line := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "%s %s\n", key, value)
+		}
+	}
+
+	line("default", c.Default)
+	line("timeout", c.Timeout)
+	line("console-mode", c.ConsoleMode)
+	for _, f := range c.bools() {
+		if *f.value != nil {
+			line(f.key, strconv.FormatBool(**f.value))
+		}
+	}
+	line("secure-boot-enroll", c.SecureBootEnroll)
+	for _, f := range c.Extra {
+		fmt.Fprintf(&b, "%s %s\n", f.Key, f.Value)
+	}
+	return b.String()
+}
+
+// ParseLoaderConfig parses the contents of loader.conf. Lines with unknown
+// keys are kept in Extra.
+func ParseLoaderConfig(r io.Reader) (*LoaderConfig, error) {
+	c := &LoaderConfig{}
+	scanner := bufio.NewScanner(r)
+lines:
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		key, value := splitLine(line)
+		switch key {
+		case "default":
+			c.Default = value
+			continue
+		case "timeout":
+			c.Timeout = value
+			continue
+		case "console-mode":
+			c.ConsoleMode = value
+			continue
+		case "secure-boot-enroll":
+			c.SecureBootEnroll = value
+			continue
+		}
+		for _, f := range c.bools() {
+			if key == f.key {
+				v, err := parseBool(value)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %v", n, err)
+				}
+				*f.value = &v
+				continue lines
+			}
+		}
+		c.Extra = append(c.Extra, Field{Key: key, Value: value})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ReadLoaderConfig reads loader.conf below root, the mount point of the
+// ESP. A missing file results in an empty configuration.
+func ReadLoaderConfig(root string) (*LoaderConfig, error) {
+	f, err := os.Open(filepath.Join(root, LoaderConfigFile))
+	if os.IsNotExist(err) {
+		return &LoaderConfig{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseLoaderConfig(f)
+}
+
+// WriteLoaderConfig writes c to loader.conf below root, replacing the
+// previous configuration atomically.
+func WriteLoaderConfig(root string, c *LoaderConfig) error {
+	p := filepath.Join(root, LoaderConfigFile)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(p, strings.NewReader(c.String()))
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLoaderConfig(t *testing.T) {
+	c, err := ParseLoaderConfig(strings.NewReader(`# systemd-boot configuration
+default fedora-*
+timeout 5
+console-mode max
+editor no
+auto-entries 1
+secure-boot-enroll if-safe
+random-seed-mode always
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	no, yes := false, true
+	expected := &LoaderConfig{
+		Default:          "fedora-*",
+		Timeout:          "5",
+		ConsoleMode:      "max",
+		Editor:           &no,
+		AutoEntries:      &yes,
+		SecureBootEnroll: "if-safe",
+		Extra:            []Field{{Key: "random-seed-mode", Value: "always"}},
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, c)
+	}
+	if n, ok := c.TimeoutSeconds(); !ok || n != 5 {
+		t.Errorf("unexpected timeout %d, %v", n, ok)
+	}
+
+	again, err := ParseLoaderConfig(strings.NewReader(c.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, c) {
+		t.Errorf("configuration changed after round trip: %+v", again)
+	}
+
+	if _, err := ParseLoaderConfig(strings.NewReader("editor maybe\n")); err == nil {
+		t.Error("expected error for invalid boolean")
+	}
+	c = &LoaderConfig{Timeout: TimeoutMenuForce}
+	if _, ok := c.TimeoutSeconds(); ok {
+		t.Error("expected no timeout in seconds for menu-force")
+	}
+}
+
+func TestReadWriteLoaderConfig(t *testing.T) {
+	root, err := ioutil.TempDir("", "boot-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	c, err := ReadLoaderConfig(root)
+	if err != nil || !reflect.DeepEqual(c, &LoaderConfig{}) {
+		t.Fatalf("expected empty configuration, got %+v, %v", c, err)
+	}
+
+	c.Default = "arch.conf"
+	if err := WriteLoaderConfig(root, c); err != nil {
+		t.Fatal(err)
+	}
+	c, err = ReadLoaderConfig(root)
+	if err != nil || c.Default != "arch.conf" {
+		t.Errorf("unexpected configuration %+v, %v", c, err)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"debug/pe"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// StubExtraDir is where systemd-stub places the credentials, extension
+// images and PCR signatures it passes to the initrd, a variable so it can be
+// changed by tests.
+var StubExtraDir = "/.extra"
+
+// Directories below the root of the ESP with the addons and credentials
+// systemd-stub picks up for all unified kernel images.
+const (
+	GlobalAddonsDir      = "loader/addons"
+	GlobalCredentialsDir = "loader/credentials"
+)
+
+// Features reported by systemd-stub in StubFeatures.
+const (
+	StubFeatureReportBootPartition uint64 = 1 << 0
+	StubFeaturePickUpCredentials   uint64 = 1 << 1
+	StubFeaturePickUpSysexts       uint64 = 1 << 2
+	StubFeatureThreePCRs           uint64 = 1 << 3
+	StubFeatureRandomSeed          uint64 = 1 << 4
+	StubFeatureCmdlineAddons       uint64 = 1 << 5
+	StubFeatureCmdlineSMBIOS       uint64 = 1 << 6
+	StubFeatureDevicetreeAddons    uint64 = 1 << 7
+	StubFeaturePickUpConfexts      uint64 = 1 << 8
+)
+
+// Addon is a PE addon of a unified kernel image, extending its kernel
+// command line or devicetree.
+type Addon struct {
+	Path string
+	// Global is set for addons applying to all unified kernel images.
+	Global     bool
+	Cmdline    string
+	Devicetree []byte
+}
+
+// Credential is a credential file systemd-stub passes to the system.
+type Credential struct {
+	// Name is the file name without the ".cred" suffix, which becomes the
+	// name of the credential.
+	Name string
+	Path string
+	// Global is set for credentials not specific to a unified kernel
+	// image, which are not measured into the TPM.
+	Global bool
+}
+
+// ExtraDir returns the directory with the addons, credentials and extension
+// images specific to the unified kernel image at ukiPath.
+func ExtraDir(ukiPath string) string {
+	return ukiPath + ".extra.d"
+}
+
+// ReadAddon reads the sections of the PE addon at path.
+func ReadAddon(path string) (*Addon, error) {
+	f, err := pe.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	a := &Addon{Path: path}
+	if b, err := peSection(f, ".cmdline"); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	} else if b != nil {
+		a.Cmdline = strings.TrimSpace(strings.TrimRight(string(b), "\x00"))
+	}
+	if a.Devicetree, err = peSection(f, ".dtb"); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return a, nil
+}
+
+// peSection returns the contents of the section name, or nil if there is none.
+func peSection(f *pe.File, name string) ([]byte, error) {
+	s := f.Section(name)
+	if s == nil {
+		return nil, nil
+	}
+	b, err := s.Data()
+	if err != nil {
+		return nil, err
+	}
+	// The raw data is padded to the file alignment.
+	if s.VirtualSize != 0 && int(s.VirtualSize) < len(b) {
+		b = b[:s.VirtualSize]
+	}
+	return b, nil
+}
+
+// FindAddons returns the addons systemd-stub applies to the unified kernel
+// image at ukiPath below root, the mount point of its partition. Global
+// addons from the ESP at espRoot come first, followed by those specific to
+// the image, each ordered by file name as systemd-stub applies them.
+func FindAddons(espRoot, root, ukiPath string) ([]*Addon, error) {
+	addons := []*Addon{}
+	for _, dir := range []struct {
+		path   string
+		global bool
+	}{
+		{filepath.Join(espRoot, GlobalAddonsDir), true},
+		{filepath.Join(root, ExtraDir(ukiPath)), false},
+	} {
+		names, err := findFiles(dir.path, ".addon.efi")
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			a, err := ReadAddon(filepath.Join(dir.path, name))
+			if err != nil {
+				return nil, err
+			}
+			a.Global = dir.global
+			addons = append(addons, a)
+		}
+	}
+	return addons, nil
+}
+
+// AddonCmdline returns the kernel command line cmdline of a unified kernel
+// image extended by the command lines of addons.
+func AddonCmdline(cmdline string, addons []*Addon) string {
+	parts := []string{}
+	if s := strings.TrimSpace(cmdline); s != "" {
+		parts = append(parts, s)
+	}
+	for _, a := range addons {
+		if a.Cmdline != "" {
+			parts = append(parts, a.Cmdline)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// FindCredentials returns the credentials systemd-stub passes along when
+// booting the unified kernel image at ukiPath, given as in FindAddons.
+func FindCredentials(espRoot, root, ukiPath string) ([]Credential, error) {
+	creds := []Credential{}
+	for _, dir := range []struct {
+		path   string
+		global bool
+	}{
+		{filepath.Join(espRoot, GlobalCredentialsDir), true},
+		{filepath.Join(root, ExtraDir(ukiPath)), false},
+	} {
+		found, err := findCredentials(dir.path, dir.global)
+		if err != nil {
+			return nil, err
+		}
+		creds = append(creds, found...)
+	}
+	return creds, nil
+}
+
+// InitrdCredentials returns the credentials systemd-stub passed to the
+// initrd in StubExtraDir, which systemd imports on boot.
+func InitrdCredentials() ([]Credential, error) {
+	creds, err := findCredentials(filepath.Join(StubExtraDir, "credentials"), false)
+	if err != nil {
+		return nil, err
+	}
+	global, err := findCredentials(filepath.Join(StubExtraDir, "global_credentials"), true)
+	if err != nil {
+		return nil, err
+	}
+	return append(creds, global...), nil
+}
+
+func findCredentials(dir string, global bool) ([]Credential, error) {
+	names, err := findFiles(dir, ".cred")
+	if err != nil {
+		return nil, err
+	}
+	creds := []Credential{}
+	for _, name := range names {
+		creds = append(creds, Credential{
+			Name:   strings.TrimSuffix(name, ".cred"),
+			Path:   filepath.Join(dir, name),
+			Global: global,
+		})
+	}
+	return creds, nil
+}
+
+// findFiles returns the names of the regular files in dir ending in suffix,
+// sorted. A missing directory has no files.
+func findFiles(dir, suffix string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, fi := range files {
+		if fi.Mode().IsRegular() && strings.HasSuffix(fi.Name(), suffix) && !strings.HasPrefix(fi.Name(), ".") {
+			names = append(names, fi.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// StubInfo returns the name and version of systemd-stub if the system was
+// booted from a unified kernel image, such as "systemd-stub 255".
+func StubInfo() (string, error) {
+	return loaderString("StubInfo")
+}
+
+// StubFeatures returns the StubFeature flags of systemd-stub, or 0 if it
+// does not report any.
+func StubFeatures() (uint64, error) {
+	return loaderUint64("StubFeatures")
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writePE writes a minimal PE file with the given sections, enough for
+// debug/pe to read them.
+func writePE(t *testing.T, path string, sections map[string][]byte) {
+	var names []string
+	for name := range sections {
+		names = append(names, name)
+	}
+
+	var b bytes.Buffer
+	dos := make([]byte, 0x40)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3c:], 0x40)
+	b.Write(dos)
+	b.WriteString("PE\x00\x00")
+	binary.Write(&b, binary.LittleEndian, pe.FileHeader{
+		Machine:          pe.IMAGE_FILE_MACHINE_AMD64,
+		NumberOfSections: uint16(len(names)),
+	})
+
+	offset := uint32(b.Len() + 40*len(names))
+	var data bytes.Buffer
+	for _, name := range names {
+		h := pe.SectionHeader32{
+			VirtualSize:      uint32(len(sections[name])),
+			SizeOfRawData:    uint32(len(sections[name]) + 3),
+			PointerToRawData: offset + uint32(data.Len()),
+		}
+		copy(h.Name[:], name)
+		binary.Write(&b, binary.LittleEndian, h)
+		data.Write(sections[name])
+		data.Write([]byte{0, 0, 0})
+	}
+	b.Write(data.Bytes())
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, b.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindAddons(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boot-stub-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	esp := filepath.Join(dir, "efi")
+	xbootldr := filepath.Join(dir, "boot")
+	uki := "EFI/Linux/fedora.efi"
+	writePE(t, filepath.Join(esp, GlobalAddonsDir, "10-debug.addon.efi"), map[string][]byte{".cmdline": []byte("debug\n\x00")})
+	writePE(t, filepath.Join(xbootldr, ExtraDir(uki), "b.addon.efi"), map[string][]byte{".dtb": {0xd0, 0x0d, 0xfe, 0xed}})
+	writePE(t, filepath.Join(xbootldr, ExtraDir(uki), "a.addon.efi"), map[string][]byte{".cmdline": []byte("console=ttyS0"), ".sbat": []byte("sbat,1\n")})
+	for _, p := range []string{
+		filepath.Join(esp, GlobalCredentialsDir, "passwd.hashed-password.root.cred"),
+		filepath.Join(esp, GlobalCredentialsDir, "README"),
+		filepath.Join(xbootldr, ExtraDir(uki), "ssh.authorized_keys.root.cred"),
+	} {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	addons, err := FindAddons(esp, xbootldr, uki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*Addon{
+		{Path: filepath.Join(esp, GlobalAddonsDir, "10-debug.addon.efi"), Global: true, Cmdline: "debug"},
+		{Path: filepath.Join(xbootldr, ExtraDir(uki), "a.addon.efi"), Cmdline: "console=ttyS0"},
+		{Path: filepath.Join(xbootldr, ExtraDir(uki), "b.addon.efi"), Devicetree: []byte{0xd0, 0x0d, 0xfe, 0xed}},
+	}
+	if !reflect.DeepEqual(addons, expected) {
+		t.Fatalf("expected addons %+v, got %+v", expected, addons)
+	}
+	if cmdline := AddonCmdline("root=/dev/sda1 ", addons); cmdline != "root=/dev/sda1 debug console=ttyS0" {
+		t.Errorf("unexpected command line %q", cmdline)
+	}
+
+	creds, err := FindCredentials(esp, xbootldr, uki)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCreds := []Credential{
+		{Name: "passwd.hashed-password.root", Path: filepath.Join(esp, GlobalCredentialsDir, "passwd.hashed-password.root.cred"), Global: true},
+		{Name: "ssh.authorized_keys.root", Path: filepath.Join(xbootldr, ExtraDir(uki), "ssh.authorized_keys.root.cred")},
+	}
+	if !reflect.DeepEqual(creds, expectedCreds) {
+		t.Errorf("expected credentials %+v, got %+v", expectedCreds, creds)
+	}
+
+	if addons, err := FindAddons(esp, xbootldr, "EFI/Linux/other.efi"); err != nil || len(addons) != 1 {
+		t.Errorf("expected only the global addon, got %+v, %v", addons, err)
+	}
+}
+
+func TestInitrdCredentials(t *testing.T) {
+	dir, err := ioutil.TempDir("", "boot-stub-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	saved := StubExtraDir
+	StubExtraDir = dir
+	defer func() { StubExtraDir = saved }()
+
+	if creds, err := InitrdCredentials(); err != nil || len(creds) != 0 {
+		t.Fatalf("expected no credentials, got %+v, %v", creds, err)
+	}
+
+	for _, p := range []string{"credentials/b.cred", "credentials/a.cred", "global_credentials/c.cred"} {
+		p = filepath.Join(dir, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, nil, 0400); err != nil {
+			t.Fatal(err)
+		}
+	}
+	creds, err := InitrdCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range creds {
+		names = append(names, c.Name)
+	}
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(names, expected) || !creds[2].Global || creds[0].Global {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+}
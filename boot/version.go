@@ -0,0 +1,130 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import "strings"
+
+const (
+	digits  = "0123456789"
+	letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// CompareVersions compares two version strings like systemd does when
+// ordering boot entries, following the UAPI version format specification.
+// It returns a negative number if a is older than b, a positive number if a
+// is newer and 0 if they are equal.
+//
+// Versions are compared segment by segment. Numbers compare numerically and
+// are newer than letters, "~" marks pre-releases and sorts before anything,
+// even the end of the version, "-" separates the release and "^" marks
+// patched versions.
+func CompareVersions(a, b string) int {
+	if a == "" || b == "" {
+		return strings.Compare(a, b)
+	}
+
+	for {
+		// Drop leading invalid characters.
+		a = strings.TrimLeftFunc(a, func(r rune) bool { return !isVersionChar(r) })
+		b = strings.TrimLeftFunc(b, func(r rune) bool { return !isVersionChar(r) })
+
+		if r := compareSeparator(&a, &b, '~'); r != 0 {
+			return r
+		}
+
+		// Apart from "~" segments, the version with more segments is
+		// newer.
+		if a == "" || b == "" {
+			return strings.Compare(a, b)
+		}
+
+		for _, sep := range []byte{'-', '^', '.'} {
+			if r := compareSeparator(&a, &b, sep); r != 0 {
+				return r
+			}
+		}
+
+		var sa, sb string
+		if startsWith(a, digits) || startsWith(b, digits) {
+			// Letters are older than numbers.
+			if r := compareBool(startsWith(a, digits), startsWith(b, digits)); r != 0 {
+				return r
+			}
+			a = strings.TrimLeft(a, "0")
+			b = strings.TrimLeft(b, "0")
+			sa, sb = leading(a, digits), leading(b, digits)
+			if r := len(sa) - len(sb); r != 0 {
+				return r
+			}
+			if r := strings.Compare(sa, sb); r != 0 {
+				return r
+			}
+		} else {
+			sa, sb = leading(a, letters), leading(b, letters)
+			n := len(sa)
+			if len(sb) < n {
+				n = len(sb)
+			}
+			if r := strings.Compare(sa[:n], sb[:n]); r != 0 {
+				return r
+			}
+			if r := len(sa) - len(sb); r != 0 {
+				return r
+			}
+		}
+		a, b = a[len(sa):], b[len(sb):]
+	}
+}
+
+// compareSeparator handles a segment separator at the start of a or b. The
+// version without the separator is newer; if both have it, it is dropped.
+func compareSeparator(a, b *string, sep byte) int {
+	ha, hb := len(*a) > 0 && (*a)[0] == sep, len(*b) > 0 && (*b)[0] == sep
+	if !ha && !hb {
+		return 0
+	}
+	if r := compareBool(!ha, !hb); r != 0 {
+		return r
+	}
+	*a, *b = (*a)[1:], (*b)[1:]
+	return 0
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+func isVersionChar(r rune) bool {
+	return r < 0x80 && strings.ContainsRune(digits+letters+"~-^.", r)
+}
+
+func startsWith(s, chars string) bool {
+	return s != "" && strings.IndexByte(chars, s[0]) >= 0
+}
+
+func leading(s, chars string) string {
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(chars, s[i]) < 0 {
+			return s[:i]
+		}
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package boot
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+	// Each version is older than the next.
+	ordered := []string{
+		"",
+		"a",
+		"ab",
+		"b",
+		"0~rc1",
+		"0",
+		"1~",
+		"1~rc1",
+		"1~rc2",
+		"1",
+		"1-1",
+		"1-2",
+		"1^post1",
+		"1.0",
+		"1.0a",
+		"1.1",
+		"1.9",
+		"1.10",
+		"1.10.0",
+		"2",
+		"10",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			r := CompareVersions(ordered[i], ordered[j])
+			switch {
+			case i < j && r >= 0:
+				t.Errorf("expected %q to be older than %q, got %d", ordered[i], ordered[j], r)
+			case i > j && r <= 0:
+				t.Errorf("expected %q to be newer than %q, got %d", ordered[i], ordered[j], r)
+			case i == j && r != 0:
+				t.Errorf("expected %q to equal itself, got %d", ordered[i], r)
+			}
+		}
+	}
+
+	equal := [][2]string{
+		{"001", "1"},
+		{"1.02", "1.2"},
+		{"_1", "1"},
+		{"1+2", "1_2"},
+	}
+	for i, tt := range equal {
+		if r := CompareVersions(tt[0], tt[1]); r != 0 {
+			t.Errorf("case %d: expected %q to equal %q, got %d", i, tt[0], tt[1], r)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cgroup maps between systemd units and the control groups systemd
+// places them in, following the layout described in
+// https://systemd.io/CGROUP_DELEGATION/
+package cgroup
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// Root is the mount point of the cgroup hierarchy, a variable so it can be
+// changed by tests.
+var Root = "/sys/fs/cgroup"
+
+// procDir is where /proc/<pid>/cgroup is read from.
+var procDir = "/proc"
+
+// ErrNoUnit is returned for cgroup paths that do not belong to a unit, such
+// as the root cgroup or a slice.
+var ErrNoUnit = errors.New("cgroup does not belong to a unit")
+
+// controllers are the names systemd reserves to avoid clashes between cgroup
+// names and the attribute files of controllers.
+var controllers = []string{
+	"cpu", "cpuacct", "cpuset", "io", "blkio", "memory", "devices", "pids",
+	"bpf-firewall", "bpf-devices", "bpf-foreign", "bpf-socket-bind",
+	"bpf-restrict-network-interfaces",
+}
+
+// Escape escapes a unit name for use as a cgroup name. Names that could be
+// confused with attribute files, or that start with an underscore or a dot,
+// are prefixed with an underscore.
+func Escape(name string) string {
+	if name == "" || name[0] == '_' || name[0] == '.' ||
+		name == "notify_on_release" || name == "release_agent" || name == "tasks" ||
+		strings.HasPrefix(name, "cgroup.") {
+		return "_" + name
+	}
+	if dot := strings.LastIndexByte(name, '.'); dot > 0 {
+		for _, c := range controllers {
+			if name[:dot] == c {
+				return "_" + name
+			}
+		}
+	}
+	return name
+}
+
+// Unescape reverses Escape.
+func Unescape(name string) string {
+	return strings.TrimPrefix(name, "_")
+}
+
+// SlicePath returns the cgroup path of a slice, which is nested in the
+// cgroups of its parent slices: a-b.slice lives in /a.slice/a-b.slice and
+// the root slice -.slice is the root cgroup.
+func SlicePath(slice string) (string, error) {
+	if slice == "-.slice" {
+		return "/", nil
+	}
+	if !unit.UnitNameIsValid(slice) || !strings.HasSuffix(slice, ".slice") || unit.UnitNameIsTemplate(slice) {
+		return "", fmt.Errorf("invalid slice name %q", slice)
+	}
+
+	prefix := strings.TrimSuffix(slice, ".slice")
+	if strings.HasPrefix(prefix, "-") || strings.HasSuffix(prefix, "-") || strings.Contains(prefix, "--") {
+		return "", fmt.Errorf("invalid slice name %q", slice)
+	}
+
+	var b strings.Builder
+	parts := strings.Split(prefix, "-")
+	for i := range parts {
+		b.WriteString("/")
+		b.WriteString(Escape(strings.Join(parts[:i+1], "-") + ".slice"))
+	}
+	return b.String(), nil
+}
+
+// UnitPath returns the cgroup path of a unit of the system manager placed in
+// slice, or system.slice if slice is empty. Slices are placed according to
+// their name.
+func UnitPath(name, slice string) (string, error) {
+	if strings.HasSuffix(name, ".slice") {
+		return SlicePath(name)
+	}
+	if !unit.UnitNameIsValid(name) || unit.UnitNameIsTemplate(name) {
+		return "", fmt.Errorf("invalid unit name %q", name)
+	}
+	if slice == "" {
+		slice = "system.slice"
+	}
+	p, err := SlicePath(slice)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(p, Escape(name)), nil
+}
+
+// UserManagerPath returns the cgroup path of the service manager of the user
+// with the given UID, which roots the cgroups of the user's units.
+func UserManagerPath(uid uint32) string {
+	id := strconv.FormatUint(uint64(uid), 10)
+	return "/user.slice/user-" + id + ".slice/user@" + id + ".service"
+}
+
+// UserUnitPath returns the cgroup path of a unit of the user manager of uid
+// placed in slice, or app.slice if slice is empty.
+func UserUnitPath(uid uint32, name, slice string) (string, error) {
+	if !strings.HasSuffix(name, ".slice") && slice == "" {
+		slice = "app.slice"
+	}
+	p, err := UnitPath(name, slice)
+	if err != nil {
+		return "", err
+	}
+	if p == "/" {
+		return UserManagerPath(uid), nil
+	}
+	return UserManagerPath(uid) + p, nil
+}
+
+// splitPath returns the unescaped components of a cgroup path.
+func splitPath(p string) []string {
+	var parts []string
+	for _, c := range strings.Split(p, "/") {
+		if c != "" {
+			parts = append(parts, Unescape(c))
+		}
+	}
+	return parts
+}
+
+// unitOf returns the first unit below the slices at the start of parts, and
+// the components following it.
+func unitOf(parts []string) (string, []string, error) {
+	for i, c := range parts {
+		if strings.HasSuffix(c, ".slice") {
+			continue
+		}
+		if !unit.UnitNameIsValid(c) || unit.UnitNameIsTemplate(c) {
+			return "", nil, ErrNoUnit
+		}
+		return c, parts[i+1:], nil
+	}
+	return "", nil, ErrNoUnit
+}
+
+// PathUnit returns the unit of the system manager a cgroup path belongs to.
+// Cgroups of user units belong to the user@.service of their user.
+func PathUnit(p string) (string, error) {
+	name, _, err := unitOf(splitPath(p))
+	return name, err
+}
+
+// PathUserUnit returns the unit of a user manager a cgroup path belongs to.
+func PathUserUnit(p string) (string, error) {
+	name, rest, err := unitOf(splitPath(p))
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(name, "user@") || !strings.HasSuffix(name, ".service") {
+		return "", ErrNoUnit
+	}
+	name, _, err = unitOf(rest)
+	return name, err
+}
+
+// PathSlice returns the slice of the system manager a cgroup path belongs
+// to, -.slice for cgroups outside of any slice.
+func PathSlice(p string) string {
+	slice := "-.slice"
+	for _, c := range splitPath(p) {
+		if !strings.HasSuffix(c, ".slice") {
+			break
+		}
+		slice = c
+	}
+	return slice
+}
+
+// PIDPath returns the cgroup path of a process as tracked by systemd, from
+// /proc/<pid>/cgroup.
+func PIDPath(pid int) (string, error) {
+	data, err := ioutil.ReadFile(path.Join(procDir, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return "", err
+	}
+	return parseProcCgroup(data)
+}
+
+// parseProcCgroup extracts the path systemd tracks a process under from the
+// contents of /proc/<pid>/cgroup: the name=systemd hierarchy in legacy and
+// hybrid mode, the unified hierarchy otherwise.
+func parseProcCgroup(data []byte) (string, error) {
+	unified := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		if fields[1] == "name=systemd" {
+			return fields[2], nil
+		}
+		if fields[0] == "0" && fields[1] == "" {
+			unified = fields[2]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if unified == "" {
+		return "", errors.New("no systemd cgroup found")
+	}
+	return unified, nil
+}
+
+// PIDUnit returns the unit of the system manager a process belongs to.
+func PIDUnit(pid int) (string, error) {
+	p, err := PIDPath(pid)
+	if err != nil {
+		return "", err
+	}
+	return PathUnit(p)
+}
+
+// PIDUserUnit returns the unit of a user manager a process belongs to.
+func PIDUserUnit(pid int) (string, error) {
+	p, err := PIDPath(pid)
+	if err != nil {
+		return "", err
+	}
+	return PathUserUnit(p)
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"foo.service", "foo.service"},
+		{"_foo.service", "__foo.service"},
+		{".hidden", "_.hidden"},
+		{"tasks", "_tasks"},
+		{"cgroup.procs", "_cgroup.procs"},
+		{"cpu.service", "_cpu.service"},
+		{"memory.scope", "_memory.scope"},
+		{"cpu-foo.service", "cpu-foo.service"},
+	}
+
+	for i, tt := range tests {
+		if out := Escape(tt.in); out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+		if in := Unescape(tt.out); in != tt.in {
+			t.Errorf("case %d: expected %q unescaped, got %q", i, tt.in, in)
+		}
+	}
+}
+
+func TestUnitPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		slice string
+		out   string
+	}{
+		{"-.slice", "", "/"},
+		{"system.slice", "", "/system.slice"},
+		{"foo-bar-baz.slice", "", "/foo.slice/foo-bar.slice/foo-bar-baz.slice"},
+		{"sshd.service", "", "/system.slice/sshd.service"},
+		{"getty@tty1.service", "system-getty.slice", "/system.slice/system-getty.slice/getty@tty1.service"},
+		{"init.scope", "-.slice", "/init.scope"},
+		{"cpu.service", "", "/system.slice/_cpu.service"},
+	}
+
+	for i, tt := range tests {
+		out, err := UnitPath(tt.name, tt.slice)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+	}
+
+	for i, tt := range []struct{ name, slice string }{
+		{"foo", ""},
+		{"foo@.service", ""},
+		{"-foo.slice", ""},
+		{"foo-.slice", ""},
+		{"foo--bar.slice", ""},
+		{"foo.service", "foo.service"},
+	} {
+		if _, err := UnitPath(tt.name, tt.slice); err == nil {
+			t.Errorf("case %d: expected error for %q in %q", i, tt.name, tt.slice)
+		}
+	}
+}
+
+func TestUserUnitPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		slice string
+		out   string
+	}{
+		{"foo.service", "", "/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service"},
+		{"dbus.socket", "-.slice", "/user.slice/user-1000.slice/user@1000.service/dbus.socket"},
+		{"app-gnome.slice", "", "/user.slice/user-1000.slice/user@1000.service/app.slice/app-gnome.slice"},
+		{"-.slice", "", "/user.slice/user-1000.slice/user@1000.service"},
+	}
+
+	for i, tt := range tests {
+		out, err := UserUnitPath(1000, tt.name, tt.slice)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+	}
+}
+
+func TestPathUnit(t *testing.T) {
+	tests := []struct {
+		path     string
+		unit     string
+		userUnit string
+		slice    string
+	}{
+		{"/system.slice/sshd.service", "sshd.service", "", "system.slice"},
+		{"/system.slice/system-getty.slice/getty@tty1.service", "getty@tty1.service", "", "system-getty.slice"},
+		{"/system.slice/_cpu.service/payload", "cpu.service", "", "system.slice"},
+		{"/init.scope", "init.scope", "", "-.slice"},
+		{"/user.slice/user-1000.slice/user@1000.service/app.slice/foo.service", "user@1000.service", "foo.service", "user-1000.slice"},
+		{"/user.slice/user-1000.slice/session-2.scope", "session-2.scope", "", "user-1000.slice"},
+		{"/", "", "", "-.slice"},
+		{"/system.slice", "", "", "system.slice"},
+	}
+
+	for i, tt := range tests {
+		u, err := PathUnit(tt.path)
+		if tt.unit == "" {
+			if err != ErrNoUnit {
+				t.Errorf("case %d: expected ErrNoUnit, got %q, %v", i, u, err)
+			}
+		} else if err != nil || u != tt.unit {
+			t.Errorf("case %d: expected unit %q, got %q, %v", i, tt.unit, u, err)
+		}
+
+		uu, err := PathUserUnit(tt.path)
+		if tt.userUnit == "" {
+			if err != ErrNoUnit {
+				t.Errorf("case %d: expected ErrNoUnit, got user unit %q, %v", i, uu, err)
+			}
+		} else if err != nil || uu != tt.userUnit {
+			t.Errorf("case %d: expected user unit %q, got %q, %v", i, tt.userUnit, uu, err)
+		}
+
+		if s := PathSlice(tt.path); s != tt.slice {
+			t.Errorf("case %d: expected slice %q, got %q", i, tt.slice, s)
+		}
+	}
+}
+
+func TestPIDUnit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(p string) { procDir = p }(procDir)
+	procDir = dir
+
+	tests := []struct {
+		content string
+		unit    string
+	}{
+		{"0::/system.slice/sshd.service\n", "sshd.service"},
+		{"12:pids:/system.slice/foo.service\n1:name=systemd:/system.slice/bar.service\n0::/\n", "bar.service"},
+	}
+
+	for i, tt := range tests {
+		if err := os.MkdirAll(filepath.Join(dir, "42"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "42", "cgroup"), []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		u, err := PIDUnit(42)
+		if err != nil || u != tt.unit {
+			t.Errorf("case %d: expected %q, got %q, %v", i, tt.unit, u, err)
+		}
+	}
+
+	if _, err := PIDUnit(43); err == nil {
+		t.Error("expected error for missing process")
+	}
+}
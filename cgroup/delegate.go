@@ -0,0 +1,243 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInternalProcesses is returned when enabling controllers for the
+	// children of a cgroup that has processes, which the unified hierarchy
+	// does not allow.
+	ErrInternalProcesses = errors.New("cgroup has processes, move them to a leaf cgroup first")
+	// ErrNotLeaf is returned when adding a process to a cgroup that has
+	// controllers enabled for its children.
+	ErrNotLeaf = errors.New("processes can only be added to leaf cgroups")
+)
+
+// Subtree is a cgroup in the unified hierarchy below a unit that has
+// Delegate= enabled. systemd leaves the cgroups below such a unit to its
+// processes, but the unified hierarchy only allows processes in leaf
+// cgroups: a cgroup that enables controllers for its children in
+// cgroup.subtree_control must not have processes itself. See
+// https://systemd.io/CGROUP_DELEGATION/
+type Subtree struct {
+	// Path is the path of the cgroup below Root.
+	Path string
+}
+
+// OwnSubtree returns the cgroup of the calling process, which is the root of
+// the delegated subtree when called early by the main process of a service
+// with Delegate=yes.
+func OwnSubtree() (*Subtree, error) {
+	p, err := PIDPath(os.Getpid())
+	if err != nil {
+		return nil, err
+	}
+	return &Subtree{Path: p}, nil
+}
+
+func (s *Subtree) dir() string {
+	return filepath.Join(Root, s.Path)
+}
+
+// Child returns the child cgroup called name, which need not exist.
+func (s *Subtree) Child(name string) (*Subtree, error) {
+	if name == "" || name == "." || name == ".." || strings.Contains(name, "/") ||
+		(Escape(name) != name && name[0] != '_') {
+		return nil, fmt.Errorf("invalid cgroup name %q", name)
+	}
+	return &Subtree{Path: path.Join(s.Path, name)}, nil
+}
+
+// Create creates the child cgroup called name, if it does not exist yet.
+func (s *Subtree) Create(name string) (*Subtree, error) {
+	c, err := s.Child(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Mkdir(c.dir(), 0755); err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Remove removes the cgroup, which must have neither processes nor children.
+func (s *Subtree) Remove() error {
+	return os.Remove(s.dir())
+}
+
+// Children returns the names of the child cgroups.
+func (s *Subtree) Children() ([]string, error) {
+	entries, err := ioutil.ReadDir(s.dir())
+	if err != nil {
+		return nil, err
+	}
+	var children []string
+	for _, e := range entries {
+		if e.IsDir() {
+			children = append(children, e.Name())
+		}
+	}
+	return children, nil
+}
+
+func (s *Subtree) readList(name string) ([]string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir(), name))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
+
+// Controllers returns the controllers available to the cgroup, which may be
+// enabled for its children.
+func (s *Subtree) Controllers() ([]string, error) {
+	return s.readList("cgroup.controllers")
+}
+
+// EnabledControllers returns the controllers enabled for the children of the
+// cgroup.
+func (s *Subtree) EnabledControllers() ([]string, error) {
+	return s.readList("cgroup.subtree_control")
+}
+
+// Processes returns the PIDs of the processes in the cgroup itself.
+func (s *Subtree) Processes() ([]int, error) {
+	fields, err := s.readList("cgroup.procs")
+	if err != nil {
+		return nil, err
+	}
+	pids := make([]int, 0, len(fields))
+	for _, f := range fields {
+		pid, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PID %q in cgroup.procs", f)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+// AddProcess moves a process into the cgroup.
+func (s *Subtree) AddProcess(pid int) error {
+	enabled, err := s.EnabledControllers()
+	if err != nil {
+		return err
+	}
+	if len(enabled) > 0 {
+		return ErrNotLeaf
+	}
+	return s.write("cgroup.procs", strconv.Itoa(pid))
+}
+
+// MoveProcesses moves all processes of the cgroup to the cgroup to.
+func (s *Subtree) MoveProcesses(to *Subtree) error {
+	pids, err := s.Processes()
+	if err != nil {
+		return err
+	}
+	for _, pid := range pids {
+		// Processes may exit while they are moved.
+		if err := to.AddProcess(pid); err != nil && !isESRCH(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnableControllers enables controllers for the children of the cgroup. The
+// cgroup must not have processes, see Partition.
+func (s *Subtree) EnableControllers(controllers ...string) error {
+	pids, err := s.Processes()
+	if err != nil {
+		return err
+	}
+	if len(pids) > 0 {
+		return ErrInternalProcesses
+	}
+	return s.setControllers("+", controllers)
+}
+
+// DisableControllers disables controllers for the children of the cgroup.
+func (s *Subtree) DisableControllers(controllers ...string) error {
+	return s.setControllers("-", controllers)
+}
+
+func (s *Subtree) setControllers(op string, controllers []string) error {
+	if len(controllers) == 0 {
+		return nil
+	}
+	if op == "+" {
+		available, err := s.Controllers()
+		if err != nil {
+			return err
+		}
+		for _, c := range controllers {
+			if !contains(available, c) {
+				return fmt.Errorf("controller %s is not available in %s", c, s.Path)
+			}
+		}
+	}
+	return s.write("cgroup.subtree_control", op+strings.Join(controllers, " "+op))
+}
+
+// Partition moves the processes of the cgroup into its child leaf, creating
+// it if needed, and enables controllers for the children. This is the usual
+// first step of a service that manages its delegated subtree itself.
+func (s *Subtree) Partition(leaf string, controllers ...string) (*Subtree, error) {
+	l, err := s.Create(leaf)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.MoveProcesses(l); err != nil {
+		return nil, err
+	}
+	// The kernel refuses with EBUSY if processes were forked into the cgroup
+	// in the meantime.
+	if err := s.setControllers("+", controllers); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (s *Subtree) write(name, value string) error {
+	f, err := os.OpenFile(filepath.Join(s.dir(), name), os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(value); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
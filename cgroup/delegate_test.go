@@ -0,0 +1,99 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSubtree(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(r string) { Root = r }(Root)
+	Root = dir
+
+	cgroupFiles := map[string]string{
+		"cgroup.controllers":     "cpu memory pids\n",
+		"cgroup.subtree_control": "",
+		"cgroup.procs":           "",
+	}
+	unitDir := filepath.Join(dir, "system.slice/foo.service")
+	writeCgroup(t, unitDir, cgroupFiles)
+	if err := ioutil.WriteFile(filepath.Join(unitDir, "cgroup.procs"), []byte("100\n101\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeCgroup(t, filepath.Join(unitDir, "supervisor"), cgroupFiles)
+
+	s := &Subtree{Path: "/system.slice/foo.service"}
+	if pids, err := s.Processes(); err != nil || !reflect.DeepEqual(pids, []int{100, 101}) {
+		t.Errorf("unexpected processes %v, %v", pids, err)
+	}
+	if c, err := s.Controllers(); err != nil || !reflect.DeepEqual(c, []string{"cpu", "memory", "pids"}) {
+		t.Errorf("unexpected controllers %v, %v", c, err)
+	}
+	if err := s.EnableControllers("cpu"); err != ErrInternalProcesses {
+		t.Errorf("expected ErrInternalProcesses, got %v", err)
+	}
+
+	for i, name := range []string{"", ".", "..", "a/b", "cgroup.procs", "memory.max", "tasks"} {
+		if _, err := s.Create(name); err == nil {
+			t.Errorf("case %d: expected error for %q", i, name)
+		}
+	}
+
+	leaf, err := s.Partition("supervisor", "cpu", "memory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if leaf.Path != "/system.slice/foo.service/supervisor" {
+		t.Errorf("unexpected leaf %q", leaf.Path)
+	}
+	// The fake cgroup.procs keeps the last write, the kernel would list both.
+	if pids, err := leaf.Processes(); err != nil || !reflect.DeepEqual(pids, []int{101}) {
+		t.Errorf("unexpected processes in leaf %v, %v", pids, err)
+	}
+	if enabled, err := s.EnabledControllers(); err != nil || !reflect.DeepEqual(enabled, []string{"+cpu", "+memory"}) {
+		t.Errorf("unexpected subtree_control %v, %v", enabled, err)
+	}
+	if err := s.AddProcess(102); err != ErrNotLeaf {
+		t.Errorf("expected ErrNotLeaf, got %v", err)
+	}
+	writeCgroup(t, filepath.Join(unitDir, "other"), cgroupFiles)
+	if err := s.DisableControllers("cpu", "memory"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Partition("other", "io"); err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Errorf("expected error for unavailable controller, got %v", err)
+	}
+
+	workload, err := s.Create("workload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if children, err := s.Children(); err != nil || !reflect.DeepEqual(children, []string{"other", "supervisor", "workload"}) {
+		t.Errorf("unexpected children %v, %v", children, err)
+	}
+	if err := workload.Remove(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+// Mode describes how the cgroup hierarchies are set up.
+type Mode int
+
+const (
+	// Legacy is the cgroup v1 setup with one hierarchy per controller.
+	Legacy Mode = iota
+	// Hybrid is a cgroup v1 setup with the unified hierarchy mounted at
+	// /sys/fs/cgroup/unified, without controllers.
+	Hybrid
+	// Unified is the cgroup v2 setup.
+	Unified
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Legacy:
+		return "legacy"
+	case Hybrid:
+		return "hybrid"
+	case Unified:
+		return "unified"
+	}
+	return "unknown"
+}
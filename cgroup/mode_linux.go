@@ -0,0 +1,45 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+)
+
+const (
+	cgroup2SuperMagic = 0x63677270
+	tmpfsMagic        = 0x01021994
+)
+
+// DetectMode returns the cgroup setup of the system, as systemd determines
+// it from the file systems mounted at Root.
+func DetectMode() (Mode, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(Root, &fs); err != nil {
+		return Legacy, err
+	}
+	switch int64(fs.Type) {
+	case cgroup2SuperMagic:
+		return Unified, nil
+	case tmpfsMagic:
+		if err := syscall.Statfs(filepath.Join(Root, "unified"), &fs); err == nil && int64(fs.Type) == cgroup2SuperMagic {
+			return Hybrid, nil
+		}
+		return Legacy, nil
+	}
+	return Legacy, fmt.Errorf("unknown file system type %#x on %s", fs.Type, Root)
+}
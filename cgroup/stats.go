@@ -0,0 +1,303 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stats holds the resource usage of a cgroup in the unified hierarchy, as
+// shown by systemd-cgtop. Statistics of controllers that are not enabled for
+// the cgroup are nil. Like the kernel's counters, they include all cgroups
+// below.
+type Stats struct {
+	Path   string
+	Memory *MemoryStats
+	CPU    *CPUStats
+	IO     *IOStats
+	PIDs   *PIDStats
+}
+
+// MemoryStats is read from memory.current, memory.peak and
+// memory.swap.current. Peak is zero on kernels that do not track it.
+type MemoryStats struct {
+	Current     uint64
+	Peak        uint64
+	SwapCurrent uint64
+}
+
+// CPUStats is read from cpu.stat.
+type CPUStats struct {
+	Usage         time.Duration
+	User          time.Duration
+	System        time.Duration
+	Periods       uint64
+	Throttled     uint64
+	ThrottledTime time.Duration
+}
+
+// IOStats is read from io.stat, keyed by "major:minor" device numbers.
+type IOStats struct {
+	Devices map[string]IODeviceStats
+}
+
+// IODeviceStats holds the I/O statistics of a single block device.
+type IODeviceStats struct {
+	ReadBytes    uint64
+	WriteBytes   uint64
+	ReadIOs      uint64
+	WriteIOs     uint64
+	DiscardBytes uint64
+	DiscardIOs   uint64
+}
+
+// PIDStats is read from pids.current.
+type PIDStats struct {
+	Current uint64
+}
+
+// ReadBytes returns the bytes read from all devices.
+func (s *IOStats) ReadBytes() uint64 {
+	var n uint64
+	for _, d := range s.Devices {
+		n += d.ReadBytes
+	}
+	return n
+}
+
+// WriteBytes returns the bytes written to all devices.
+func (s *IOStats) WriteBytes() uint64 {
+	var n uint64
+	for _, d := range s.Devices {
+		n += d.WriteBytes
+	}
+	return n
+}
+
+// StatsForUnit returns the resource usage of a unit of the system manager.
+// The unit is looked up in system.slice first, then in the whole hierarchy.
+func StatsForUnit(name string) (*Stats, error) {
+	p, err := findUnit(name)
+	if err != nil {
+		return nil, err
+	}
+	return StatsForPath(p)
+}
+
+// StatsForUnitChildren returns the resource usage of the cgroups directly
+// below a unit, keyed by their unescaped names. For a slice these are the
+// units and slices it contains.
+func StatsForUnitChildren(name string) (map[string]*Stats, error) {
+	p, err := findUnit(name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ioutil.ReadDir(filepath.Join(Root, p))
+	if err != nil {
+		return nil, err
+	}
+
+	children := map[string]*Stats{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		s, err := StatsForPath(filepath.ToSlash(filepath.Join(p, e.Name())))
+		if err != nil {
+			return nil, err
+		}
+		children[Unescape(e.Name())] = s
+	}
+	return children, nil
+}
+
+// findUnit returns the cgroup path of a unit that has one.
+func findUnit(name string) (string, error) {
+	p, err := UnitPath(name, "")
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(Root, p)); err == nil || strings.HasSuffix(name, ".slice") {
+		return p, err
+	}
+
+	escaped := Escape(name)
+	found := ""
+	err = filepath.Walk(Root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || !fi.IsDir() {
+			return nil
+		}
+		if fi.Name() == escaped {
+			found = path
+			return filepath.SkipDir
+		}
+		// Units are only placed in slices, not below other units.
+		if path != Root && !strings.HasSuffix(fi.Name(), ".slice") {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("no cgroup found for unit %s", name)
+	}
+	rel, err := filepath.Rel(Root, found)
+	if err != nil {
+		return "", err
+	}
+	return "/" + filepath.ToSlash(rel), nil
+}
+
+// StatsForPath returns the resource usage of the cgroup at path p in the
+// unified hierarchy below Root.
+func StatsForPath(p string) (*Stats, error) {
+	dir := filepath.Join(Root, p)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	s := &Stats{Path: p}
+
+	current, err := readUint(dir, "memory.current")
+	if err == nil {
+		s.Memory = &MemoryStats{Current: current}
+		if s.Memory.Peak, err = readUint(dir, "memory.peak"); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if s.Memory.SwapCurrent, err = readUint(dir, "memory.swap.current"); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if s.CPU, err = readCPUStats(dir); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if s.IO, err = readIOStats(dir); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	if current, err := readUint(dir, "pids.current"); err == nil {
+		s.PIDs = &PIDStats{Current: current}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func readUint(dir, name string) (uint64, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %v", name, err)
+	}
+	return v, nil
+}
+
+// readKeyed calls fn for each of the fields that is a key and a value
+// separated by sep, as in the lines of cpu.stat and the entries of io.stat.
+func readKeyed(fields []string, sep string, fn func(key string, value uint64)) error {
+	for _, f := range fields {
+		kv := strings.SplitN(f, sep, 2)
+		if len(kv) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(kv[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %v", kv[0], err)
+		}
+		fn(kv[0], v)
+	}
+	return nil
+}
+
+func readCPUStats(dir string) (*CPUStats, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "cpu.stat"))
+	if err != nil {
+		return nil, err
+	}
+	s := &CPUStats{}
+	err = readKeyed(strings.Split(strings.TrimSpace(string(data)), "\n"), " ", func(key string, v uint64) {
+		switch key {
+		case "usage_usec":
+			s.Usage = time.Duration(v) * time.Microsecond
+		case "user_usec":
+			s.User = time.Duration(v) * time.Microsecond
+		case "system_usec":
+			s.System = time.Duration(v) * time.Microsecond
+		case "nr_periods":
+			s.Periods = v
+		case "nr_throttled":
+			s.Throttled = v
+		case "throttled_usec":
+			s.ThrottledTime = time.Duration(v) * time.Microsecond
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cpu.stat: %v", err)
+	}
+	return s, nil
+}
+
+func readIOStats(dir string) (*IOStats, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "io.stat"))
+	if err != nil {
+		return nil, err
+	}
+	s := &IOStats{Devices: map[string]IODeviceStats{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var d IODeviceStats
+		err := readKeyed(fields[1:], "=", func(key string, v uint64) {
+			switch key {
+			case "rbytes":
+				d.ReadBytes = v
+			case "wbytes":
+				d.WriteBytes = v
+			case "rios":
+				d.ReadIOs = v
+			case "wios":
+				d.WriteIOs = v
+			case "dbytes":
+				d.DiscardBytes = v
+			case "dios":
+				d.DiscardIOs = v
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse io.stat: %v", err)
+		}
+		s.Devices[fields[0]] = d
+	}
+	return s, scanner.Err()
+}
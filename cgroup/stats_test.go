@@ -0,0 +1,114 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeCgroup(t *testing.T, dir string, files map[string]string) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStatsForUnit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(r string) { Root = r }(Root)
+	Root = dir
+
+	writeCgroup(t, filepath.Join(dir, "system.slice"), map[string]string{
+		"memory.current": "3072\n",
+		"cpu.stat":       "usage_usec 3000\nuser_usec 2000\nsystem_usec 1000\n",
+		"pids.current":   "3\n",
+	})
+	writeCgroup(t, filepath.Join(dir, "system.slice/sshd.service"), map[string]string{
+		"memory.current":      "1024\n",
+		"memory.peak":         "4096\n",
+		"memory.swap.current": "0\n",
+		"cpu.stat":            "usage_usec 1500\nuser_usec 1000\nsystem_usec 500\nnr_periods 10\nnr_throttled 2\nthrottled_usec 300\n",
+		"io.stat":             "8:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0\n253:0 rbytes=512 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n",
+		"pids.current":        "1\n",
+	})
+	writeCgroup(t, filepath.Join(dir, "system.slice/system-getty.slice/getty@tty1.service"), map[string]string{
+		"cpu.stat": "usage_usec 10\n",
+	})
+	writeCgroup(t, filepath.Join(dir, "system.slice/_cpu.service"), map[string]string{
+		"cpu.stat": "usage_usec 20\n",
+	})
+
+	s, err := StatsForUnit("sshd.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Path != "/system.slice/sshd.service" {
+		t.Errorf("unexpected path %q", s.Path)
+	}
+	if s.Memory == nil || *s.Memory != (MemoryStats{Current: 1024, Peak: 4096}) {
+		t.Errorf("unexpected memory stats %+v", s.Memory)
+	}
+	if s.CPU == nil || *s.CPU != (CPUStats{
+		Usage:         1500 * time.Microsecond,
+		User:          1000 * time.Microsecond,
+		System:        500 * time.Microsecond,
+		Periods:       10,
+		Throttled:     2,
+		ThrottledTime: 300 * time.Microsecond,
+	}) {
+		t.Errorf("unexpected CPU stats %+v", s.CPU)
+	}
+	if s.IO == nil || s.IO.Devices["8:0"] != (IODeviceStats{ReadBytes: 4096, WriteBytes: 8192, ReadIOs: 1, WriteIOs: 2}) || s.IO.ReadBytes() != 4608 || s.IO.WriteBytes() != 8192 {
+		t.Errorf("unexpected IO stats %+v", s.IO)
+	}
+	if s.PIDs == nil || s.PIDs.Current != 1 {
+		t.Errorf("unexpected PID stats %+v", s.PIDs)
+	}
+
+	s, err = StatsForUnit("getty@tty1.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Path != "/system.slice/system-getty.slice/getty@tty1.service" || s.Memory != nil || s.IO != nil || s.PIDs != nil || s.CPU.Usage != 10*time.Microsecond {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	children, err := StatsForUnitChildren("system.slice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 3 || children["sshd.service"] == nil || children["system-getty.slice"] == nil || children["cpu.service"] == nil {
+		t.Errorf("unexpected children %v", children)
+	}
+	if children["cpu.service"].CPU.Usage != 20*time.Microsecond {
+		t.Errorf("unexpected stats for escaped cgroup %+v", children["cpu.service"].CPU)
+	}
+
+	if _, err := StatsForUnit("nonexistent.service"); err == nil {
+		t.Error("expected error for unit without cgroup")
+	}
+}
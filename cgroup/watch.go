@@ -0,0 +1,218 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRemoved is returned by Watcher.Err when the watched cgroup was removed,
+// e.g. because its unit stopped.
+var ErrRemoved = errors.New("cgroup was removed")
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+const (
+	// EventMemoryHigh means the cgroup was throttled for exceeding
+	// MemoryHigh=.
+	EventMemoryHigh EventKind = iota
+	// EventMemoryMax means the cgroup was about to exceed MemoryMax=.
+	EventMemoryMax
+	// EventOOM means the cgroup reached its memory limit and the OOM killer
+	// was invoked.
+	EventOOM
+	// EventOOMKill means a process in the cgroup was killed by the OOM
+	// killer.
+	EventOOMKill
+	// EventOOMGroupKill means the whole cgroup was killed by the OOM killer,
+	// see OOMPolicy=.
+	EventOOMGroupKill
+	// EventPressure means a PressureTrigger fired.
+	EventPressure
+)
+
+// memoryEvents maps the keys of memory.events to event kinds.
+var memoryEvents = map[string]EventKind{
+	"high":           EventMemoryHigh,
+	"max":            EventMemoryMax,
+	"oom":            EventOOM,
+	"oom_kill":       EventOOMKill,
+	"oom_group_kill": EventOOMGroupKill,
+}
+
+func (k EventKind) String() string {
+	switch k {
+	case EventMemoryHigh:
+		return "memory-high"
+	case EventMemoryMax:
+		return "memory-max"
+	case EventOOM:
+		return "oom"
+	case EventOOMKill:
+		return "oom-kill"
+	case EventOOMGroupKill:
+		return "oom-group-kill"
+	case EventPressure:
+		return "pressure"
+	}
+	return "unknown"
+}
+
+// Event is delivered by a Watcher.
+type Event struct {
+	Kind EventKind
+	Path string
+	// Count is the number of times the event occurred for the lifetime of
+	// the cgroup and Delta by how much it increased since the last event.
+	// They are zero for EventPressure.
+	Count uint64
+	Delta uint64
+	// Trigger and Pressure are the trigger that fired and the pressure of
+	// its resource for EventPressure.
+	Trigger  *PressureTrigger
+	Pressure *Pressure
+}
+
+// PressureTrigger asks a Watcher for an event whenever the tasks of the
+// cgroup stall on Resource, which is "memory", "cpu" or "io", for Stall
+// within Window. If Full is set, only stalls of all tasks are counted.
+// Unprivileged processes can only use windows that are multiples of two
+// seconds.
+type PressureTrigger struct {
+	Resource string
+	Full     bool
+	Stall    time.Duration
+	Window   time.Duration
+}
+
+// String returns the trigger in the format written to the pressure file.
+func (t PressureTrigger) String() string {
+	kind := "some"
+	if t.Full {
+		kind = "full"
+	}
+	return fmt.Sprintf("%s %d %d", kind, t.Stall/time.Microsecond, t.Window/time.Microsecond)
+}
+
+// Pressure is the pressure stall information of a resource.
+type Pressure struct {
+	Some PressureStats
+	Full PressureStats
+}
+
+// PressureStats holds the share of time in percent tasks were stalled over
+// the last 10, 60 and 300 seconds, and the total stall time.
+type PressureStats struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  time.Duration
+}
+
+// ReadPressure reads the pressure stall information of a resource, "memory",
+// "cpu" or "io", for the cgroup at path p below Root.
+func ReadPressure(p, resource string) (*Pressure, error) {
+	data, err := ioutil.ReadFile(filepath.Join(Root, p, resource+".pressure"))
+	if err != nil {
+		return nil, err
+	}
+	return parsePressure(data)
+}
+
+func parsePressure(data []byte) (*Pressure, error) {
+	pressure := &Pressure{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var s *PressureStats
+		switch fields[0] {
+		case "some":
+			s = &pressure.Some
+		case "full":
+			s = &pressure.Full
+		default:
+			continue
+		}
+		for _, f := range fields[1:] {
+			kv := strings.SplitN(f, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid pressure field %q", f)
+			}
+			var err error
+			switch kv[0] {
+			case "avg10":
+				s.Avg10, err = strconv.ParseFloat(kv[1], 64)
+			case "avg60":
+				s.Avg60, err = strconv.ParseFloat(kv[1], 64)
+			case "avg300":
+				s.Avg300, err = strconv.ParseFloat(kv[1], 64)
+			case "total":
+				var total uint64
+				total, err = strconv.ParseUint(kv[1], 10, 64)
+				s.Total = time.Duration(total) * time.Microsecond
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid pressure field %q: %v", f, err)
+			}
+		}
+	}
+	return pressure, scanner.Err()
+}
+
+// readMemoryEvents returns the counters in memory.events of dir.
+func readMemoryEvents(dir string) (map[string]uint64, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, "memory.events"))
+	if err != nil {
+		return nil, err
+	}
+	counters := map[string]uint64{}
+	err = readKeyed(strings.Split(strings.TrimSpace(string(data)), "\n"), " ", func(key string, v uint64) {
+		counters[key] = v
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse memory.events: %v", err)
+	}
+	return counters, nil
+}
+
+// memoryEventsSince returns the events for the counters that increased from
+// last to now.
+func memoryEventsSince(p string, last, now map[string]uint64) []Event {
+	var events []Event
+	// Report in a fixed order, so an OOM is seen before the kill it causes.
+	for _, key := range []string{"high", "max", "oom", "oom_kill", "oom_group_kill"} {
+		if now[key] > last[key] {
+			events = append(events, Event{
+				Kind:  memoryEvents[key],
+				Path:  p,
+				Count: now[key],
+				Delta: now[key] - last[key],
+			})
+		}
+	}
+	return events
+}
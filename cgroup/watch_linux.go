@@ -0,0 +1,249 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Watcher delivers memory events and pressure notifications of a cgroup as
+// they happen, using inotify on memory.events and PSI triggers.
+type Watcher struct {
+	// Events delivers the events until the Watcher is closed or fails, then
+	// it is closed.
+	Events <-chan Event
+
+	events   chan Event
+	path     string
+	dir      string
+	epfd     int
+	inotify  int
+	wake     [2]int
+	triggers map[int32]PressureTrigger
+	last     map[string]uint64
+	err      error
+
+	done      chan struct{}
+	closeOnce sync.Once
+	// mu guards the file descriptors against Close writing to wake after
+	// run closed them.
+	mu     sync.Mutex
+	closed bool
+}
+
+// WatchUnit watches the cgroup of a unit of the system manager for memory
+// events, and for pressure if triggers are given.
+func WatchUnit(name string, triggers ...PressureTrigger) (*Watcher, error) {
+	p, err := findUnit(name)
+	if err != nil {
+		return nil, err
+	}
+	return WatchPath(p, triggers...)
+}
+
+// WatchPath watches the cgroup at path p below Root for memory events, and
+// for pressure if triggers are given.
+func WatchPath(p string, triggers ...PressureTrigger) (*Watcher, error) {
+	events := make(chan Event)
+	w := &Watcher{
+		Events:   events,
+		events:   events,
+		path:     p,
+		dir:      filepath.Join(Root, p),
+		epfd:     -1,
+		inotify:  -1,
+		wake:     [2]int{-1, -1},
+		triggers: map[int32]PressureTrigger{},
+		done:     make(chan struct{}),
+	}
+	if err := w.setup(triggers); err != nil {
+		w.closeFds()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+func (w *Watcher) setup(triggers []PressureTrigger) error {
+	var err error
+	if w.last, err = readMemoryEvents(w.dir); err != nil {
+		return err
+	}
+	if w.epfd, err = syscall.EpollCreate1(syscall.EPOLL_CLOEXEC); err != nil {
+		return os.NewSyscallError("epoll_create1", err)
+	}
+
+	if w.inotify, err = syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK); err != nil {
+		return os.NewSyscallError("inotify_init1", err)
+	}
+	if _, err := syscall.InotifyAddWatch(w.inotify, filepath.Join(w.dir, "memory.events"), syscall.IN_MODIFY); err != nil {
+		return os.NewSyscallError("inotify_add_watch", err)
+	}
+	if err := w.add(w.inotify, syscall.EPOLLIN); err != nil {
+		return err
+	}
+
+	if err := syscall.Pipe2(w.wake[:], syscall.O_CLOEXEC|syscall.O_NONBLOCK); err != nil {
+		return os.NewSyscallError("pipe2", err)
+	}
+	if err := w.add(w.wake[0], syscall.EPOLLIN); err != nil {
+		return err
+	}
+
+	for _, t := range triggers {
+		name := filepath.Join(w.dir, t.Resource+".pressure")
+		fd, err := syscall.Open(name, syscall.O_RDWR|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+		if err != nil {
+			return &os.PathError{Op: "open", Path: name, Err: err}
+		}
+		w.triggers[int32(fd)] = t
+		if _, err := syscall.Write(fd, []byte(t.String()+"\x00")); err != nil {
+			return &os.PathError{Op: "write", Path: name, Err: err}
+		}
+		if err := w.add(fd, syscall.EPOLLPRI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *Watcher) add(fd int, events uint32) error {
+	ev := syscall.EpollEvent{Events: events, Fd: int32(fd)}
+	if err := syscall.EpollCtl(w.epfd, syscall.EPOLL_CTL_ADD, fd, &ev); err != nil {
+		return os.NewSyscallError("epoll_ctl", err)
+	}
+	return nil
+}
+
+func (w *Watcher) closeFds() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	for _, fd := range []int{w.epfd, w.inotify, w.wake[0], w.wake[1]} {
+		if fd >= 0 {
+			syscall.Close(fd)
+		}
+	}
+	for fd := range w.triggers {
+		syscall.Close(int(fd))
+	}
+}
+
+func (w *Watcher) run() {
+	defer close(w.events)
+	defer w.closeFds()
+
+	ready := make([]syscall.EpollEvent, 8)
+	for {
+		n, err := syscall.EpollWait(w.epfd, ready, -1)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			w.err = os.NewSyscallError("epoll_wait", err)
+			return
+		}
+
+		for _, ev := range ready[:n] {
+			var events []Event
+			switch {
+			case int(ev.Fd) == w.wake[0]:
+				return
+			case int(ev.Fd) == w.inotify:
+				if events, err = w.readInotify(); err != nil {
+					w.err = err
+					return
+				}
+			default:
+				if ev.Events&syscall.EPOLLERR != 0 {
+					w.err = ErrRemoved
+					return
+				}
+				t := w.triggers[ev.Fd]
+				pressure, err := ReadPressure(w.path, t.Resource)
+				if err != nil {
+					w.err = err
+					return
+				}
+				events = []Event{{Kind: EventPressure, Path: w.path, Trigger: &t, Pressure: pressure}}
+			}
+
+			for _, e := range events {
+				select {
+				case w.events <- e:
+				case <-w.done:
+					return
+				}
+			}
+		}
+	}
+}
+
+// readInotify drains the inotify events and returns the memory events since
+// the last call.
+func (w *Watcher) readInotify() ([]Event, error) {
+	buf := make([]byte, 4096)
+	for {
+		n, err := syscall.Read(w.inotify, buf)
+		if err == syscall.EAGAIN {
+			break
+		}
+		if err != nil {
+			return nil, os.NewSyscallError("read", err)
+		}
+		for off := 0; off+syscall.SizeofInotifyEvent <= n; {
+			ev := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+			if ev.Mask&syscall.IN_IGNORED != 0 {
+				return nil, ErrRemoved
+			}
+			off += syscall.SizeofInotifyEvent + int(ev.Len)
+		}
+	}
+
+	now, err := readMemoryEvents(w.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrRemoved
+		}
+		return nil, err
+	}
+	events := memoryEventsSince(w.path, w.last, now)
+	w.last = now
+	return events, nil
+}
+
+// Close stops the Watcher. Events is closed once pending events are
+// discarded.
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+		w.mu.Lock()
+		if !w.closed {
+			syscall.Write(w.wake[1], []byte{0})
+		}
+		w.mu.Unlock()
+	})
+	return nil
+}
+
+// Err returns the error that stopped the Watcher, once Events is closed. It
+// is nil if the Watcher was closed.
+func (w *Watcher) Err() error {
+	return w.err
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatchPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(r string) { Root = r }(Root)
+	Root = dir
+
+	events := filepath.Join(dir, "system.slice/foo.service/memory.events")
+	writeCgroup(t, filepath.Dir(events), map[string]string{
+		"memory.events": "low 0\nhigh 0\nmax 0\noom 0\noom_kill 0\noom_group_kill 0\n",
+	})
+
+	w, err := WatchUnit("foo.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	if err := ioutil.WriteFile(events, []byte("low 0\nhigh 0\nmax 0\noom 1\noom_kill 1\noom_group_kill 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, kind := range []EventKind{EventOOM, EventOOMKill} {
+		select {
+		case e := <-w.Events:
+			if e.Kind != kind || e.Count != 1 || e.Path != "/system.slice/foo.service" {
+				t.Errorf("expected %s event, got %+v", kind, e)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %s event", kind)
+		}
+	}
+
+	if err := os.Remove(events); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case e, ok := <-w.Events:
+		if ok {
+			t.Fatalf("unexpected event %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the watcher to stop")
+	}
+	if w.Err() != ErrRemoved {
+		t.Errorf("expected ErrRemoved, got %v", w.Err())
+	}
+}
+
+func TestWatcherClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeCgroup(t, dir, map[string]string{"memory.events": "oom 0\n"})
+	defer func(r string) { Root = r }(Root)
+	Root = dir
+
+	w, err := WatchPath("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case _, ok := <-w.Events:
+		if ok {
+			t.Fatal("unexpected event")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the watcher to stop")
+	}
+	if w.Err() != nil {
+		t.Errorf("unexpected error %v", w.Err())
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package cgroup
+
+import "errors"
+
+// Watcher delivers memory events and pressure notifications of a cgroup,
+// which is only supported on Linux.
+type Watcher struct {
+	Events <-chan Event
+}
+
+// WatchUnit watches the cgroup of a unit, which is only supported on Linux.
+func WatchUnit(name string, triggers ...PressureTrigger) (*Watcher, error) {
+	return nil, errors.New("cgroups are only supported on Linux")
+}
+
+// WatchPath watches the cgroup at path p, which is only supported on Linux.
+func WatchPath(p string, triggers ...PressureTrigger) (*Watcher, error) {
+	return nil, errors.New("cgroups are only supported on Linux")
+}
+
+// Close stops the Watcher.
+func (w *Watcher) Close() error {
+	return nil
+}
+
+// Err returns the error that stopped the Watcher.
+func (w *Watcher) Err() error {
+	return nil
+}
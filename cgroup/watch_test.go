@@ -0,0 +1,74 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cgroup
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePressure(t *testing.T) {
+	p, err := parsePressure([]byte("some avg10=1.50 avg60=0.25 avg300=0.00 total=123456\nfull avg10=0.10 avg60=0.00 avg300=0.00 total=789\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Pressure{
+		Some: PressureStats{Avg10: 1.5, Avg60: 0.25, Total: 123456 * time.Microsecond},
+		Full: PressureStats{Avg10: 0.1, Total: 789 * time.Microsecond},
+	}
+	if *p != expected {
+		t.Errorf("expected %+v, got %+v", expected, *p)
+	}
+
+	if _, err := parsePressure([]byte("some avg10=x\n")); err == nil {
+		t.Error("expected error for invalid pressure")
+	}
+}
+
+func TestPressureTrigger(t *testing.T) {
+	tests := []struct {
+		trigger PressureTrigger
+		out     string
+	}{
+		{PressureTrigger{Resource: "memory", Stall: 150 * time.Millisecond, Window: time.Second}, "some 150000 1000000"},
+		{PressureTrigger{Resource: "io", Full: true, Stall: 50 * time.Millisecond, Window: 2 * time.Second}, "full 50000 2000000"},
+	}
+
+	for i, tt := range tests {
+		if out := tt.trigger.String(); out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+	}
+}
+
+func TestMemoryEventsSince(t *testing.T) {
+	events := memoryEventsSince("/system.slice/foo.service",
+		map[string]uint64{"low": 0, "high": 4, "max": 0, "oom": 1, "oom_kill": 1},
+		map[string]uint64{"low": 2, "high": 4, "max": 1, "oom": 2, "oom_kill": 3, "oom_group_kill": 0})
+
+	expected := []Event{
+		{Kind: EventMemoryMax, Path: "/system.slice/foo.service", Count: 1, Delta: 1},
+		{Kind: EventOOM, Path: "/system.slice/foo.service", Count: 2, Delta: 1},
+		{Kind: EventOOMKill, Path: "/system.slice/foo.service", Count: 3, Delta: 2},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %+v", len(expected), events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("case %d: expected %+v, got %+v", i, expected[i], events[i])
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cmdline parses the kernel command line the way systemd does, for
+// generators and early boot services honoring the same options as systemd's
+// own. See https://www.freedesktop.org/software/systemd/man/kernel-command-line.html
+package cmdline
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	efiLoaderVendor = "8cf2644b-4b0b-428f-9387-6d876050dc67"
+	efiGlobalVendor = "8be4df61-93ca-11d2-aa0d-00e098032b8c"
+)
+
+// The files read by Read, variables so they can be changed by tests.
+var (
+	procCmdline       = "/proc/cmdline"
+	pid1Cmdline       = "/proc/1/cmdline"
+	containerFile     = "/run/systemd/container"
+	initrdReleaseFile = "/etc/initrd-release"
+	efivarsDir        = "/sys/firmware/efi/efivars"
+)
+
+// ErrUnterminatedQuote is returned by Parse for a command line ending inside quotes.
+var ErrUnterminatedQuote = errors.New("unterminated quote")
+
+// Parameter is a parameter of the kernel command line, either a key=value
+// pair or a lone key.
+type Parameter struct {
+	Key      string
+	Value    string
+	HasValue bool
+}
+
+func (p Parameter) String() string {
+	if !p.HasValue {
+		return p.Key
+	}
+	return p.Key + "=" + p.Value
+}
+
+// Cmdline is a parsed kernel command line.
+type Cmdline []Parameter
+
+// Parse splits a kernel command line into its parameters. Words are
+// separated by whitespace; single and double quotes, which may appear
+// anywhere in a word, preserve whitespace and are removed, while backslashes
+// are kept as they are.
+func Parse(s string) (Cmdline, error) {
+	c := Cmdline{}
+	for {
+		s = strings.TrimLeft(s, " \t\n\r")
+		if s == "" {
+			return c, nil
+		}
+
+		var word strings.Builder
+		var quote byte
+		for s != "" {
+			ch := s[0]
+			if quote == 0 && strings.IndexByte(" \t\n\r", ch) >= 0 {
+				break
+			}
+			switch {
+			case quote != 0 && ch == quote:
+				quote = 0
+			case quote == 0 && (ch == '\'' || ch == '"'):
+				quote = ch
+			default:
+				word.WriteByte(ch)
+			}
+			s = s[1:]
+		}
+		if quote != 0 {
+			return nil, ErrUnterminatedQuote
+		}
+		c = append(c, newParameter(word.String()))
+	}
+}
+
+func newParameter(word string) Parameter {
+	if i := strings.IndexByte(word, '='); i >= 0 {
+		return Parameter{Key: word[:i], Value: word[i+1:], HasValue: true}
+	}
+	return Parameter{Key: word}
+}
+
+// Read returns the command line systemd would use. In containers, the
+// arguments of PID 1 are used instead of the kernel command line. The
+// SystemdOptions EFI variable set by bootctl is prepended unless Secure
+// Boot is enabled. As in systemd, $SYSTEMD_PROC_CMDLINE and
+// $SYSTEMD_EFI_OPTIONS override the kernel command line and the EFI
+// variable respectively.
+//
+// The result is filtered for the current boot phase, see Filter.
+func Read() (Cmdline, error) {
+	c, err := efiOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	kernel, err := kernelCmdline()
+	if err != nil {
+		return nil, err
+	}
+	return append(c, kernel...).Filter(InInitrd()), nil
+}
+
+func kernelCmdline() (Cmdline, error) {
+	if e, ok := os.LookupEnv("SYSTEMD_PROC_CMDLINE"); ok {
+		return Parse(e)
+	}
+
+	if _, err := os.Stat(containerFile); err == nil {
+		b, err := ioutil.ReadFile(pid1Cmdline)
+		if err != nil {
+			return nil, err
+		}
+		args := strings.Split(strings.TrimRight(string(b), "\x00"), "\x00")
+		c := Cmdline{}
+		for _, arg := range args[1:] {
+			c = append(c, newParameter(arg))
+		}
+		return c, nil
+	}
+
+	b, err := ioutil.ReadFile(procCmdline)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(b))
+}
+
+func efiOptions() (Cmdline, error) {
+	if e, ok := os.LookupEnv("SYSTEMD_EFI_OPTIONS"); ok {
+		return Parse(e)
+	}
+
+	// Like systemd, don't let the unsigned variable extend a command line
+	// which is protected by Secure Boot.
+	if secureBoot, err := readEFIVariable("SecureBoot", efiGlobalVendor); err == nil && len(secureBoot) > 0 && secureBoot[0] == 1 {
+		return Cmdline{}, nil
+	}
+
+	b, err := readEFIVariable("SystemdOptions", efiLoaderVendor)
+	if os.IsNotExist(err) {
+		return Cmdline{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	s, err := decodeUTF16(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SystemdOptions EFI variable: %v", err)
+	}
+	return Parse(s)
+}
+
+// readEFIVariable returns the data of an EFI variable, without the leading
+// attributes.
+func readEFIVariable(name, vendor string) ([]byte, error) {
+	b, err := ioutil.ReadFile(filepath.Join(efivarsDir, name+"-"+vendor))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < 4 {
+		return nil, fmt.Errorf("EFI variable %s too short", name)
+	}
+	return b[4:], nil
+}
+
+func decodeUTF16(b []byte) (string, error) {
+	if len(b)%2 != 0 {
+		return "", errors.New("odd length")
+	}
+	u := make([]uint16, len(b)/2)
+	if err := binary.Read(bytes.NewReader(b), binary.LittleEndian, u); err != nil {
+		return "", err
+	}
+	for i, c := range u {
+		if c == 0 {
+			u = u[:i]
+			break
+		}
+	}
+	return string(utf16.Decode(u)), nil
+}
+
+// InInitrd returns whether the system is running in the initrd.
+func InInitrd() bool {
+	if e, ok := os.LookupEnv("SYSTEMD_IN_INITRD"); ok {
+		b, err := parseBool(e)
+		return err == nil && b
+	}
+	_, err := os.Stat(initrdReleaseFile)
+	return err == nil
+}
+
+// Filter returns the parameters for the boot phase. Parameters prefixed
+// with "rd." apply only to the initrd: in the initrd they are returned with
+// the prefix removed, otherwise they are dropped.
+func (c Cmdline) Filter(initrd bool) Cmdline {
+	filtered := Cmdline{}
+	for _, p := range c {
+		if strings.HasPrefix(p.Key, "rd.") {
+			if !initrd {
+				continue
+			}
+			p.Key = strings.TrimPrefix(p.Key, "rd.")
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered
+}
+
+// KeyEqual returns whether two parameter keys are equal. As in systemd,
+// dashes and underscores are equivalent.
+func KeyEqual(a, b string) bool {
+	return strings.Replace(a, "_", "-", -1) == strings.Replace(b, "_", "-", -1)
+}
+
+// Values returns the values of all parameters with the given key, in order.
+// Parameters without a value are skipped.
+func (c Cmdline) Values(key string) []string {
+	var values []string
+	for _, p := range c {
+		if p.HasValue && KeyEqual(p.Key, key) {
+			values = append(values, p.Value)
+		}
+	}
+	return values
+}
+
+// Value returns the value of the last parameter with the given key, which
+// takes precedence over earlier ones. The boolean reports whether the key is
+// present at all, with or without a value.
+func (c Cmdline) Value(key string) (string, bool) {
+	for i := len(c) - 1; i >= 0; i-- {
+		if KeyEqual(c[i].Key, key) {
+			return c[i].Value, true
+		}
+	}
+	return "", false
+}
+
+// Bool returns the boolean value of the last parameter with the given key.
+// A key without a value, e.g. "quiet", is true. If the key is not present,
+// def is returned.
+func (c Cmdline) Bool(key string, def bool) (bool, error) {
+	for i := len(c) - 1; i >= 0; i-- {
+		if !KeyEqual(c[i].Key, key) {
+			continue
+		}
+		if !c[i].HasValue {
+			return true, nil
+		}
+		return parseBool(c[i].Value)
+	}
+	return def, nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "1", "yes", "y", "true", "t", "on":
+		return true, nil
+	case "0", "no", "n", "false", "f", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
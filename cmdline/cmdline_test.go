@@ -0,0 +1,194 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmdline
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in     string
+		output Cmdline
+	}{
+		{"", Cmdline{}},
+		{
+			"BOOT_IMAGE=/vmlinuz root=UUID=1234 ro quiet\n",
+			Cmdline{
+				{Key: "BOOT_IMAGE", Value: "/vmlinuz", HasValue: true},
+				{Key: "root", Value: "UUID=1234", HasValue: true},
+				{Key: "ro"},
+				{Key: "quiet"},
+			},
+		},
+		{
+			`foo="bar baz" "quoted=a b" x='' back\slash=1 empty=`,
+			Cmdline{
+				{Key: "foo", Value: "bar baz", HasValue: true},
+				{Key: "quoted", Value: "a b", HasValue: true},
+				{Key: "x", HasValue: true},
+				{Key: `back\slash`, Value: "1", HasValue: true},
+				{Key: "empty", HasValue: true},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		output, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.output, output) {
+			t.Errorf("case %d: expected %v, got %v", i, tt.output, output)
+		}
+	}
+
+	if _, err := Parse(`foo="bar`); err != ErrUnterminatedQuote {
+		t.Errorf("expected ErrUnterminatedQuote, got %v", err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	c, err := Parse("systemd.unit=rescue.target quiet systemd.log_level=info systemd.log-level=debug " +
+		"rd.systemd.unit=emergency.target rd.luks=no systemd.mask=a.service systemd.mask=b.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := c.Value("systemd.log_level"); !ok || v != "debug" {
+		t.Errorf("expected last log level to win, got %q", v)
+	}
+	if v := c.Values("systemd.mask"); !reflect.DeepEqual(v, []string{"a.service", "b.service"}) {
+		t.Errorf("unexpected masks %v", v)
+	}
+	if _, ok := c.Value("missing"); ok {
+		t.Error("expected missing key to be absent")
+	}
+	if b, err := c.Bool("quiet", false); err != nil || !b {
+		t.Errorf("expected lone key to be true, got %v, %v", b, err)
+	}
+	if b, err := c.Bool("rd.luks", true); err != nil || b {
+		t.Errorf("expected rd.luks=no to be false, got %v, %v", b, err)
+	}
+	if b, err := c.Bool("missing", true); err != nil || !b {
+		t.Errorf("expected default, got %v, %v", b, err)
+	}
+	if _, err := c.Bool("systemd.unit", false); err == nil {
+		t.Error("expected error for non-boolean value")
+	}
+
+	host := c.Filter(false)
+	if v, _ := host.Value("systemd.unit"); v != "rescue.target" {
+		t.Errorf("expected rd. parameters to be dropped on the host, got %q", v)
+	}
+	if _, ok := host.Value("luks"); ok {
+		t.Error("expected rd.luks to be dropped on the host")
+	}
+	initrd := c.Filter(true)
+	if v, _ := initrd.Value("systemd.unit"); v != "emergency.target" {
+		t.Errorf("expected rd.systemd.unit to take precedence in the initrd, got %q", v)
+	}
+	if _, ok := initrd.Value("luks"); !ok {
+		t.Error("expected rd.luks to apply as luks in the initrd")
+	}
+}
+
+func writeEFIVariable(t *testing.T, dir, name string, data []byte) {
+	b := append([]byte{7, 0, 0, 0}, data...)
+	if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-cmdline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(cmdline, container, initrd, efivars string) {
+		procCmdline, containerFile, initrdReleaseFile, efivarsDir = cmdline, container, initrd, efivars
+	}(procCmdline, containerFile, initrdReleaseFile, efivarsDir)
+	procCmdline = filepath.Join(dir, "cmdline")
+	containerFile = filepath.Join(dir, "container")
+	initrdReleaseFile = filepath.Join(dir, "initrd-release")
+	efivarsDir = dir
+	os.Unsetenv("SYSTEMD_PROC_CMDLINE")
+	os.Unsetenv("SYSTEMD_EFI_OPTIONS")
+	os.Unsetenv("SYSTEMD_IN_INITRD")
+
+	if err := ioutil.WriteFile(procCmdline, []byte("ro rd.break=pre-mount systemd.log_level=info\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var options []byte
+	for _, u := range utf16.Encode([]rune("systemd.log_level=debug quiet\x00")) {
+		options = append(options, byte(u), byte(u>>8))
+	}
+	writeEFIVariable(t, dir, "SystemdOptions-"+efiLoaderVendor, options)
+
+	c, err := Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Cmdline{
+		{Key: "systemd.log_level", Value: "debug", HasValue: true},
+		{Key: "quiet"},
+		{Key: "ro"},
+		{Key: "systemd.log_level", Value: "info", HasValue: true},
+	}
+	if !reflect.DeepEqual(expected, c) {
+		t.Errorf("expected %v, got %v", expected, c)
+	}
+
+	// In the initrd, rd. parameters apply.
+	if err := ioutil.WriteFile(initrdReleaseFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	c, err = Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Value("break"); v != "pre-mount" {
+		t.Errorf("expected rd.break in the initrd, got %v", c)
+	}
+	os.Remove(initrdReleaseFile)
+
+	// With Secure Boot, the EFI variable is ignored.
+	writeEFIVariable(t, dir, "SecureBoot-"+efiGlobalVendor, []byte{1})
+	c, err = Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Value("systemd.log_level"); v != "info" || len(c) != 2 {
+		t.Errorf("expected EFI options to be ignored with Secure Boot, got %v", c)
+	}
+
+	os.Setenv("SYSTEMD_PROC_CMDLINE", "foo=bar")
+	defer os.Unsetenv("SYSTEMD_PROC_CMDLINE")
+	c, err = Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(Cmdline{{Key: "foo", Value: "bar", HasValue: true}}, c) {
+		t.Errorf("expected $SYSTEMD_PROC_CMDLINE to override, got %v", c)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coredump reads the core dumps systemd-coredump collects, from the
+// journal entries it logs for every crash and from its storage directory,
+// like coredumpctl.  See
+// https://www.freedesktop.org/software/systemd/man/systemd-coredump.html
+package coredump
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// MessageID is the MESSAGE_ID of the journal entries systemd-coredump logs
+// for core dumps.
+const MessageID = "fc2e22bc6ee647b6b90729ab34a250b1"
+
+// Journal fields of core dump entries.
+const (
+	FieldPID        = "COREDUMP_PID"
+	FieldUID        = "COREDUMP_UID"
+	FieldGID        = "COREDUMP_GID"
+	FieldSignal     = "COREDUMP_SIGNAL"
+	FieldSignalName = "COREDUMP_SIGNAL_NAME"
+	FieldTimestamp  = "COREDUMP_TIMESTAMP"
+	FieldRlimit     = "COREDUMP_RLIMIT"
+	FieldHostname   = "COREDUMP_HOSTNAME"
+	FieldComm       = "COREDUMP_COMM"
+	FieldExe        = "COREDUMP_EXE"
+	FieldCmdline    = "COREDUMP_CMDLINE"
+	FieldCgroup     = "COREDUMP_CGROUP"
+	FieldUnit       = "COREDUMP_UNIT"
+	FieldUserUnit   = "COREDUMP_USER_UNIT"
+	FieldSlice      = "COREDUMP_SLICE"
+	FieldOwnerUID   = "COREDUMP_OWNER_UID"
+	FieldFilename   = "COREDUMP_FILENAME"
+	FieldTruncated  = "COREDUMP_TRUNCATED"
+	FieldPackage    = "COREDUMP_PACKAGE_NAME"
+	FieldVersion    = "COREDUMP_PACKAGE_VERSION"
+	FieldCore       = "COREDUMP" // The core itself, if stored in the journal
+)
+
+// ErrNoCore is returned when opening the core of a dump that was not
+// stored or has been removed since.
+var ErrNoCore = errors.New("core is not available")
+
+// Coredump describes a core dump of a crashed process.
+type Coredump struct {
+	PID       int
+	UID       uint32
+	GID       uint32
+	Signal    int
+	Timestamp time.Time
+	Comm      string
+	Exe       string
+	Cmdline   string
+	Unit      string
+	Hostname  string
+	BootID    string
+	// Filename is the path of the stored core, if any.
+	Filename string
+	// Truncated is set if the core was cut off at the configured size
+	// limit.
+	Truncated bool
+	// Embedded is set if the core is stored in the journal entry itself.
+	Embedded bool
+	// Message is the message of the journal entry, including the stack
+	// trace of the crashing thread if it could be generated.
+	Message string
+	// Cursor is the cursor of the journal entry.
+	Cursor string
+	// Fields are the fields of the journal entry, except for the core.
+	Fields map[string]string
+}
+
+// FromFields returns the core dump described by the fields of a journal
+// entry, as sdjournal.JournalEntry has them.
+func FromFields(fields map[string]string) (*Coredump, error) {
+	c := &Coredump{
+		Comm:      fields[FieldComm],
+		Exe:       fields[FieldExe],
+		Cmdline:   fields[FieldCmdline],
+		Unit:      fields[FieldUnit],
+		Hostname:  fields[FieldHostname],
+		BootID:    fields["_BOOT_ID"],
+		Filename:  fields[FieldFilename],
+		Truncated: fields[FieldTruncated] == "1",
+		Message:   fields["MESSAGE"],
+		Fields:    map[string]string{},
+	}
+	if c.Unit == "" {
+		c.Unit = fields[FieldUserUnit]
+	}
+	for k, v := range fields {
+		if k == FieldCore {
+			c.Embedded = true
+			continue
+		}
+		c.Fields[k] = v
+	}
+
+	var err error
+	if c.PID, err = intField(fields, FieldPID); err != nil {
+		return nil, err
+	}
+	if c.Signal, err = intField(fields, FieldSignal); err != nil {
+		return nil, err
+	}
+	for _, f := range []struct {
+		name string
+		v    *uint32
+	}{{FieldUID, &c.UID}, {FieldGID, &c.GID}} {
+		if s, ok := fields[f.name]; ok {
+			n, err := strconv.ParseUint(s, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			*f.v = uint32(n)
+		}
+	}
+	if s, ok := fields[FieldTimestamp]; ok {
+		usec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		c.Timestamp = time.Unix(0, usec*int64(time.Microsecond))
+	}
+	return c, nil
+}
+
+func intField(fields map[string]string, name string) (int, error) {
+	s, ok := fields[name]
+	if !ok {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
+
+// Open opens the stored core, decompressing it if necessary. Cores embedded
+// in the journal are read with OpenCore instead.
+func (c *Coredump) Open() (io.ReadCloser, error) {
+	if c.Filename == "" {
+		return nil, ErrNoCore
+	}
+	f, err := os.Open(c.Filename)
+	if os.IsNotExist(err) {
+		return nil, ErrNoCore
+	} else if err != nil {
+		return nil, err
+	}
+	r, err := Decompress(f, compression(c.Filename))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &readCloser{Reader: r, closers: []io.Closer{r, f}}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *readCloser) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFromFields(t *testing.T) {
+	c, err := FromFields(map[string]string{
+		"MESSAGE":       "Process 1234 (crash) of user 1000 dumped core.",
+		"_BOOT_ID":      "2d0ed8586e8d4d7b9f0b72e8a3d45e0b",
+		FieldPID:        "1234",
+		FieldUID:        "1000",
+		FieldGID:        "100",
+		FieldSignal:     "11",
+		FieldTimestamp:  "1700000000123456",
+		FieldComm:       "crash",
+		FieldExe:        "/usr/bin/crash",
+		FieldUserUnit:   "app.service",
+		FieldTruncated:  "1",
+		FieldCore:       "\x7fELF",
+		"COREDUMP_CWD":  "/home/user",
+		FieldSignalName: "SIGSEGV",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.PID != 1234 || c.UID != 1000 || c.GID != 100 || c.Signal != 11 {
+		t.Errorf("unexpected IDs %+v", c)
+	}
+	if !c.Timestamp.Equal(time.Unix(1700000000, 123456000)) {
+		t.Errorf("unexpected timestamp %v", c.Timestamp)
+	}
+	if c.Comm != "crash" || c.Exe != "/usr/bin/crash" || c.Unit != "app.service" || c.BootID != "2d0ed8586e8d4d7b9f0b72e8a3d45e0b" {
+		t.Errorf("unexpected metadata %+v", c)
+	}
+	if !c.Truncated || !c.Embedded {
+		t.Errorf("expected truncated embedded core, got %+v", c)
+	}
+	if _, ok := c.Fields[FieldCore]; ok {
+		t.Error("core was kept in fields")
+	}
+	if c.Fields["COREDUMP_CWD"] != "/home/user" {
+		t.Errorf("unexpected fields %v", c.Fields)
+	}
+
+	if _, err := FromFields(map[string]string{FieldPID: "x"}); err == nil {
+		t.Error("expected error for invalid PID")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coredump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for i, tt := range []struct {
+		name string
+		data []byte
+	}{
+		{"core.crash.1000.2d0ed8586e8d4d7b9f0b72e8a3d45e0b.1234.1700000000123456", []byte("core")},
+		{"core.crash.1000.2d0ed8586e8d4d7b9f0b72e8a3d45e0b.1234.1700000000123456.lz4", lz4Frame(0x60, []byte{4, 0, 0, 0x80, 'c', 'o', 'r', 'e'})},
+	} {
+		path := filepath.Join(dir, tt.name)
+		if err := ioutil.WriteFile(path, tt.data, 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := &Coredump{Filename: path}
+		r, err := c.Open()
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+			continue
+		}
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil || string(b) != "core" {
+			t.Errorf("case %d: unexpected core %q, %v", i, b, err)
+		}
+	}
+
+	if _, err := (&Coredump{}).Open(); err != ErrNoCore {
+		t.Errorf("expected ErrNoCore without file, got %v", err)
+	}
+	if _, err := (&Coredump{Filename: filepath.Join(dir, "missing")}).Open(); err != ErrNoCore {
+		t.Errorf("expected ErrNoCore for missing file, got %v", err)
+	}
+}
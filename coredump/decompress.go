@@ -0,0 +1,57 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Decompressors decompress stored cores by file extension. LZ4 is built
+// in, zstd and xz use libzstd and liblzma if the package is built with cgo.
+// Other implementations can be registered here.
+var Decompressors = map[string]func(io.Reader) (io.ReadCloser, error){
+	"":     func(r io.Reader) (io.ReadCloser, error) { return ioutil.NopCloser(r), nil },
+	".lz4": newLZ4Reader,
+}
+
+// Decompress returns a reader of the decompressed contents of r, a core
+// stored with the file extension ext, e.g. ".zst".
+func Decompress(r io.Reader, ext string) (io.ReadCloser, error) {
+	d, ok := Decompressors[ext]
+	if !ok {
+		return nil, fmt.Errorf("unsupported compression %q", ext)
+	}
+	return d(r)
+}
+
+// compression returns the extension of the compressed core file name, or an
+// empty string if it is not compressed. The names of uncompressed cores end
+// in a timestamp, which must not be taken for an extension.
+func compression(name string) string {
+	for _, ext := range []string{".zst", ".xz", ".lz4"} {
+		if strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+	for ext := range Decompressors {
+		if ext != "" && strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+	return ""
+}
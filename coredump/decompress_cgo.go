@@ -0,0 +1,346 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && cgo
+// +build linux,cgo
+
+package coredump
+
+// #include <stdint.h>
+// #include <stdlib.h>
+//
+// typedef struct {
+//   const void *src;
+//   size_t size;
+//   size_t pos;
+// } my_zstd_in;
+//
+// typedef struct {
+//   void *dst;
+//   size_t size;
+//   size_t pos;
+// } my_zstd_out;
+//
+// void *
+// my_zstd_create(void *f)
+// {
+//   void *(*ZSTD_createDStream)(void);
+//
+//   ZSTD_createDStream = f;
+//   return ZSTD_createDStream();
+// }
+//
+// size_t
+// my_zstd_free(void *f, void *ds)
+// {
+//   size_t (*ZSTD_freeDStream)(void *);
+//
+//   ZSTD_freeDStream = f;
+//   return ZSTD_freeDStream(ds);
+// }
+//
+// size_t
+// my_zstd_decompress(void *f, void *ds, my_zstd_out *out, my_zstd_in *in)
+// {
+//   size_t (*ZSTD_decompressStream)(void *, my_zstd_out *, my_zstd_in *);
+//
+//   ZSTD_decompressStream = f;
+//   return ZSTD_decompressStream(ds, out, in);
+// }
+//
+// unsigned
+// my_zstd_is_error(void *f, size_t code)
+// {
+//   unsigned (*ZSTD_isError)(size_t);
+//
+//   ZSTD_isError = f;
+//   return ZSTD_isError(code);
+// }
+//
+// const char *
+// my_zstd_error_name(void *f, size_t code)
+// {
+//   const char *(*ZSTD_getErrorName)(size_t);
+//
+//   ZSTD_getErrorName = f;
+//   return ZSTD_getErrorName(code);
+// }
+//
+// typedef struct {
+//   const uint8_t *next_in;
+//   size_t avail_in;
+//   uint64_t total_in;
+//   uint8_t *next_out;
+//   size_t avail_out;
+//   uint64_t total_out;
+//   const void *allocator;
+//   void *internal;
+//   void *reserved_ptr1;
+//   void *reserved_ptr2;
+//   void *reserved_ptr3;
+//   void *reserved_ptr4;
+//   uint64_t seek_pos;
+//   uint64_t reserved_int2;
+//   size_t reserved_int3;
+//   size_t reserved_int4;
+//   int reserved_enum1;
+//   int reserved_enum2;
+// } my_lzma_stream;
+//
+// int
+// my_lzma_stream_decoder(void *f, my_lzma_stream *strm)
+// {
+//   int (*lzma_stream_decoder)(my_lzma_stream *, uint64_t, uint32_t);
+//
+//   lzma_stream_decoder = f;
+//   return lzma_stream_decoder(strm, UINT64_MAX, 0);
+// }
+//
+// int
+// my_lzma_code(void *f, my_lzma_stream *strm, int action)
+// {
+//   int (*lzma_code)(my_lzma_stream *, int);
+//
+//   lzma_code = f;
+//   return lzma_code(strm, action);
+// }
+//
+// void
+// my_lzma_end(void *f, my_lzma_stream *strm)
+// {
+//   void (*lzma_end)(my_lzma_stream *);
+//
+//   lzma_end = f;
+//   lzma_end(strm);
+// }
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"unsafe"
+
+	"github.com/coreos/go-systemd/v22/internal/dlopen"
+)
+
+const cBufSize = 128 << 10
+
+func init() {
+	Decompressors[".zst"] = newZstdReader
+	Decompressors[".xz"] = newXzReader
+}
+
+// library lazily opens a shared library and resolves its symbols.
+type library struct {
+	names []string
+
+	once sync.Once
+	syms map[string]unsafe.Pointer
+	err  error
+}
+
+var (
+	libzstd = &library{names: []string{"libzstd.so.1", "libzstd.so"}}
+	liblzma = &library{names: []string{"liblzma.so.5", "liblzma.so"}}
+)
+
+func (l *library) symbols(names ...string) (map[string]unsafe.Pointer, error) {
+	l.once.Do(func() {
+		h, err := dlopen.GetHandle(l.names)
+		if err != nil {
+			l.err = fmt.Errorf("%s: %v", l.names[0], err)
+			return
+		}
+		l.syms = map[string]unsafe.Pointer{}
+		for _, n := range names {
+			p, err := h.GetSymbolPointer(n)
+			if err != nil {
+				l.err = err
+				return
+			}
+			l.syms[n] = p
+		}
+	})
+	return l.syms, l.err
+}
+
+// cBytes returns a slice of n bytes of C memory at p.
+func cBytes(p unsafe.Pointer, n int) []byte {
+	return (*[1 << 30]byte)(p)[:n:n]
+}
+
+type zstdReader struct {
+	r    io.Reader
+	syms map[string]unsafe.Pointer
+	ds   unsafe.Pointer
+	in   C.my_zstd_in
+	out  C.my_zstd_out
+	eof  bool
+	ret  C.size_t
+}
+
+func newZstdReader(r io.Reader) (io.ReadCloser, error) {
+	syms, err := libzstd.symbols("ZSTD_createDStream", "ZSTD_freeDStream", "ZSTD_decompressStream", "ZSTD_isError", "ZSTD_getErrorName")
+	if err != nil {
+		return nil, err
+	}
+	// ret is the hint of ZSTD_decompressStream for the next read, zero
+	// once a frame is complete.
+	z := &zstdReader{r: r, syms: syms, ret: 1}
+	z.ds = C.my_zstd_create(syms["ZSTD_createDStream"])
+	if z.ds == nil {
+		return nil, errors.New("failed to create zstd decompression stream")
+	}
+	z.in.src = C.malloc(cBufSize)
+	z.out.dst = C.malloc(cBufSize)
+	return z, nil
+}
+
+func (z *zstdReader) Read(p []byte) (int, error) {
+	for {
+		if z.in.pos == z.in.size && !z.eof {
+			n, err := z.r.Read(cBytes(unsafe.Pointer(z.in.src), cBufSize))
+			if err == io.EOF {
+				z.eof = true
+			} else if err != nil {
+				return 0, err
+			}
+			z.in.size, z.in.pos = C.size_t(n), 0
+		}
+
+		if z.eof && z.in.pos == z.in.size && z.ret == 0 {
+			return 0, io.EOF
+		}
+		size := len(p)
+		if size > cBufSize {
+			size = cBufSize
+		}
+		z.out.size, z.out.pos = C.size_t(size), 0
+		z.ret = C.my_zstd_decompress(z.syms["ZSTD_decompressStream"], z.ds, &z.out, &z.in)
+		if C.my_zstd_is_error(z.syms["ZSTD_isError"], z.ret) != 0 {
+			return 0, fmt.Errorf("zstd: %s", C.GoString(C.my_zstd_error_name(z.syms["ZSTD_getErrorName"], z.ret)))
+		}
+		if z.out.pos > 0 {
+			return copy(p, cBytes(z.out.dst, int(z.out.pos))), nil
+		}
+		if z.eof && z.in.pos == z.in.size {
+			// A return value of zero means the frame is complete.
+			if z.ret != 0 {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, io.EOF
+		}
+	}
+}
+
+func (z *zstdReader) Close() error {
+	if z.ds != nil {
+		C.my_zstd_free(z.syms["ZSTD_freeDStream"], z.ds)
+		C.free(unsafe.Pointer(z.in.src))
+		C.free(z.out.dst)
+		z.ds = nil
+	}
+	return nil
+}
+
+// liblzma return values and actions.
+const (
+	lzmaOK        = 0
+	lzmaStreamEnd = 1
+	lzmaBufError  = 10
+	lzmaRun       = 0
+	lzmaFinish    = 3
+)
+
+type xzReader struct {
+	r     io.Reader
+	syms  map[string]unsafe.Pointer
+	strm  *C.my_lzma_stream
+	inBuf unsafe.Pointer
+	eof   bool
+	done  bool
+}
+
+func newXzReader(r io.Reader) (io.ReadCloser, error) {
+	syms, err := liblzma.symbols("lzma_stream_decoder", "lzma_code", "lzma_end")
+	if err != nil {
+		return nil, err
+	}
+	// The stream lives in C memory, liblzma keeps referring to it.
+	z := &xzReader{r: r, syms: syms}
+	z.strm = (*C.my_lzma_stream)(C.calloc(1, C.sizeof_my_lzma_stream))
+	if ret := C.my_lzma_stream_decoder(syms["lzma_stream_decoder"], z.strm); ret != lzmaOK {
+		C.free(unsafe.Pointer(z.strm))
+		return nil, fmt.Errorf("failed to create xz decoder: %d", ret)
+	}
+	z.inBuf = C.malloc(cBufSize)
+	return z, nil
+}
+
+func (z *xzReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	out := C.malloc(C.size_t(len(p)))
+	defer C.free(out)
+
+	for {
+		if z.done {
+			return 0, io.EOF
+		}
+		if z.strm.avail_in == 0 && !z.eof {
+			n, err := z.r.Read(cBytes(z.inBuf, cBufSize))
+			if err == io.EOF {
+				z.eof = true
+			} else if err != nil {
+				return 0, err
+			}
+			z.strm.next_in, z.strm.avail_in = (*C.uint8_t)(z.inBuf), C.size_t(n)
+		}
+
+		z.strm.next_out, z.strm.avail_out = (*C.uint8_t)(out), C.size_t(len(p))
+		action := lzmaRun
+		if z.eof {
+			action = lzmaFinish
+		}
+		ret := C.my_lzma_code(z.syms["lzma_code"], z.strm, C.int(action))
+		n := len(p) - int(z.strm.avail_out)
+		switch ret {
+		case lzmaOK:
+		case lzmaStreamEnd:
+			z.done = true
+		case lzmaBufError:
+			if z.eof {
+				return 0, io.ErrUnexpectedEOF
+			}
+		default:
+			return 0, fmt.Errorf("xz: decoding failed: %d", ret)
+		}
+		if n > 0 {
+			return copy(p, cBytes(out, n)), nil
+		}
+	}
+}
+
+func (z *xzReader) Close() error {
+	if z.strm != nil {
+		C.my_lzma_end(z.syms["lzma_end"], z.strm)
+		C.free(unsafe.Pointer(z.strm))
+		C.free(z.inBuf)
+		z.strm = nil
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux && cgo
+// +build linux,cgo
+
+package coredump
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestDecompressCgo(t *testing.T) {
+	want := strings.Repeat("core dump contents\n", 3)
+	xz, _ := hex.DecodeString("fd377a585a000004e6d6b4460200210116000000742fe5a3e00038001a5d00319bcaaae4bb8224843c57bd0ff647f45d168f89d09a6c6c00000000008b1c3eaf0228d5540001363956ac9ff61fb6f37d010000000004595a")
+	// A single segment frame with a raw block.
+	zst := append([]byte{0x28, 0xb5, 0x2f, 0xfd, 0x20, byte(len(want)), byte(1 | len(want)<<3), byte(len(want) >> 5), 0}, want...)
+
+	for i, tt := range []struct {
+		ext  string
+		data []byte
+	}{
+		{".xz", xz},
+		{".zst", zst},
+	} {
+		r, err := Decompress(bytes.NewReader(tt.data), tt.ext)
+		if err != nil {
+			t.Skipf("case %d: %v", i, err)
+		}
+		b, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		} else if string(b) != want {
+			t.Errorf("case %d: unexpected contents %q", i, b)
+		}
+
+		r, err = Decompress(bytes.NewReader(tt.data[:len(tt.data)-10]), tt.ext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ioutil.ReadAll(r); err == nil {
+			t.Errorf("case %d: expected error for truncated data", i)
+		}
+		r.Close()
+	}
+}
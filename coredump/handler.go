@@ -0,0 +1,184 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/cgroup"
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+// CorePatternArgs are the core_pattern(5) specifiers a core dump helper
+// registered in /proc/sys/kernel/core_pattern must be passed, in the order
+// systemd-coredump expects them and ParseCorePatternArgs parses them, e.g.
+// "|/usr/local/bin/helper %P %u %g %s %t %c %h %d".
+const CorePatternArgs = "%P %u %g %s %t %c %h %d"
+
+// procDir is where process information is read from, a variable so it can
+// be changed by tests.
+var procDir = "/proc"
+
+// Report is a core dump received by a handler, with the metadata in the
+// journal fields systemd-coredump uses and the uncompressed core.
+type Report struct {
+	Fields map[string]string
+	// Core is the core, which is a pipe when received from the kernel
+	// and may be nil if the sender did not pass one.
+	Core *os.File
+}
+
+// Coredump returns the metadata of the report.
+func (r *Report) Coredump() (*Coredump, error) {
+	return FromFields(r.Fields)
+}
+
+// Close closes the core.
+func (r *Report) Close() error {
+	if r.Core == nil {
+		return nil
+	}
+	return r.Core.Close()
+}
+
+// ParseCorePatternArgs returns the fields for the arguments the kernel
+// passes a core dump helper, the CorePatternArgs specifiers.
+func ParseCorePatternArgs(args []string) (map[string]string, error) {
+	names := []string{FieldPID, FieldUID, FieldGID, FieldSignal, FieldTimestamp, FieldRlimit, FieldHostname, "COREDUMP_DUMPABLE"}
+	if len(args) < 7 {
+		return nil, fmt.Errorf("expected at least 7 arguments, got %d", len(args))
+	}
+
+	fields := map[string]string{}
+	for i, name := range names {
+		if i >= len(args) {
+			break
+		}
+		fields[name] = args[i]
+	}
+	for _, name := range []string{FieldPID, FieldUID, FieldGID, FieldSignal, FieldTimestamp} {
+		if _, err := strconv.ParseUint(fields[name], 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid %s %q", name, fields[name])
+		}
+	}
+	// The kernel passes seconds, the journal field holds microseconds.
+	fields[FieldTimestamp] += "000000"
+	return fields, nil
+}
+
+// ProcessFields returns the fields systemd-coredump records about the
+// crashed process pid, read from /proc while the kernel keeps it around
+// for the core dump helper. Information that cannot be read is left out.
+func ProcessFields(pid int) map[string]string {
+	dir := filepath.Join(procDir, strconv.Itoa(pid))
+	fields := map[string]string{}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		fields[FieldComm] = strings.TrimSuffix(string(b), "\n")
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "cmdline")); err == nil && len(b) > 0 {
+		fields[FieldCmdline] = string(bytes.Replace(bytes.TrimSuffix(b, []byte{0}), []byte{0}, []byte{' '}, -1))
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "status")); err == nil {
+		fields["COREDUMP_PROC_STATUS"] = string(b)
+	}
+	for name, field := range map[string]string{"exe": FieldExe, "cwd": "COREDUMP_CWD", "root": "COREDUMP_ROOT"} {
+		if v, err := os.Readlink(filepath.Join(dir, name)); err == nil {
+			fields[field] = v
+		}
+	}
+
+	if b, err := ioutil.ReadFile(filepath.Join(dir, "cgroup")); err == nil {
+		for _, line := range strings.Split(string(b), "\n") {
+			if !strings.HasPrefix(line, "0::") {
+				continue
+			}
+			p := line[len("0::"):]
+			fields[FieldCgroup] = p
+			if s := cgroup.PathSlice(p); s != "" {
+				fields[FieldSlice] = s
+			}
+			if u, err := cgroup.PathUserUnit(p); err == nil {
+				fields[FieldUserUnit] = u
+			} else if u, err := cgroup.PathUnit(p); err == nil {
+				fields[FieldUnit] = u
+			}
+		}
+	}
+	return fields
+}
+
+// ReadKernelReport returns the report of a core dump helper run by the
+// kernel with the CorePatternArgs args and the core on stdin.
+func ReadKernelReport(args []string, stdin *os.File) (*Report, error) {
+	fields, err := ParseCorePatternArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	pid, _ := strconv.Atoi(fields[FieldPID])
+	for k, v := range ProcessFields(pid) {
+		fields[k] = v
+	}
+	return &Report{Fields: fields, Core: stdin}, nil
+}
+
+// Message returns the journal message systemd-coredump logs for the core
+// dump described by fields.
+func Message(fields map[string]string) string {
+	sig := fields[FieldSignalName]
+	if sig == "" {
+		sig = fields[FieldSignal]
+	}
+	if sig == "" {
+		return fmt.Sprintf("Process %s (%s) of user %s dumped core.", fields[FieldPID], fields[FieldComm], fields[FieldUID])
+	}
+	return fmt.Sprintf("Process %s (%s) of user %s terminated abnormally with signal %s.", fields[FieldPID], fields[FieldComm], fields[FieldUID], sig)
+}
+
+// LogToJournal logs the report to the journal with MessageID, so
+// coredumpctl and List find it, embedding up to max bytes of the core
+// in the COREDUMP field as systemd-coredump does with Storage=journal.
+// Larger cores are truncated and marked as such, a max of zero logs only
+// the metadata.
+func LogToJournal(r *Report, max int64) error {
+	vars := map[string]string{"MESSAGE_ID": MessageID}
+	for k, v := range r.Fields {
+		vars[k] = v
+	}
+	if r.Core != nil && max > 0 {
+		b, err := ioutil.ReadAll(io.LimitReader(r.Core, max+1))
+		if err != nil {
+			return err
+		}
+		if int64(len(b)) > max {
+			b = b[:max]
+			vars[FieldTruncated] = "1"
+		}
+		vars[FieldCore] = string(b)
+	}
+	msg := vars["MESSAGE"]
+	if msg == "" {
+		msg = Message(r.Fields)
+	}
+	delete(vars, "MESSAGE")
+	return journal.Send(msg, journal.PriCrit, vars)
+}
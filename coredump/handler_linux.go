@@ -0,0 +1,248 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Socket is where systemd-coredump running as core_pattern helper forwards
+// cores to the systemd-coredump@.service instances, a variable so it can be
+// changed by tests. A custom handler takes it over by replacing
+// systemd-coredump.socket.
+var Socket = "/run/systemd/coredump"
+
+// Listen listens on the SOCK_SEQPACKET unix socket at path cores are
+// forwarded to, replacing a stale socket left by an earlier instance.
+func Listen(path string) (*net.UnixListener, error) {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	return net.ListenUnix("unixpacket", &net.UnixAddr{Name: path, Net: "unixpacket"})
+}
+
+// Server receives the cores systemd-coredump forwards, one per connection.
+type Server struct {
+	// Handler processes a report, which is closed once it returns.
+	Handler func(ctx context.Context, r *Report) error
+}
+
+// Serve accepts connections on l, for example the socket passed by
+// systemd-coredump.socket, and hands their reports to the handler until ctx
+// is done. It returns the error that stopped it, nil when ctx is done.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			uc, ok := conn.(*net.UnixConn)
+			if !ok {
+				return
+			}
+			r, err := ReadReport(uc)
+			if err != nil {
+				return
+			}
+			defer r.Close()
+			s.Handler(ctx, r)
+		}()
+	}
+}
+
+// ReadReport reads a forwarded core dump from conn: a datagram per field,
+// then zero-length datagrams passing the core and possibly further file
+// descriptors, which are closed, ending with one without or the end of the
+// connection.
+func ReadReport(conn *net.UnixConn) (*Report, error) {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{Fields: map[string]string{}}
+	for {
+		data, fds, err := recv(rc)
+		if err != nil {
+			r.Close()
+			return nil, err
+		}
+		if len(data) == 0 {
+			if len(fds) == 0 {
+				break
+			}
+			for _, fd := range fds {
+				if r.Core == nil {
+					r.Core = os.NewFile(uintptr(fd), "core")
+				} else {
+					syscall.Close(fd)
+				}
+			}
+			continue
+		}
+		for _, fd := range fds {
+			syscall.Close(fd)
+		}
+		i := strings.IndexByte(string(data), '=')
+		if i <= 0 {
+			r.Close()
+			return nil, errors.New("invalid field")
+		}
+		r.Fields[string(data[:i])] = string(data[i+1:])
+	}
+	return r, nil
+}
+
+// recv receives a datagram and the file descriptors passed with it.
+func recv(rc syscall.RawConn) ([]byte, []int, error) {
+	var (
+		data  []byte
+		fds   []int
+		opErr error
+	)
+	err := rc.Read(func(fd uintptr) bool {
+		// Peek at the size of the datagram first, fields can be large.
+		var b [1]byte
+		n, _, err := syscall.Recvfrom(int(fd), b[:], syscall.MSG_PEEK|syscall.MSG_TRUNC)
+		if err == syscall.EAGAIN {
+			return false
+		} else if err != nil {
+			opErr = err
+			return true
+		}
+
+		data = make([]byte, n)
+		oob := make([]byte, syscall.CmsgSpace(4*4))
+		n, oobn, _, _, err := syscall.Recvmsg(int(fd), data, oob, syscall.MSG_CMSG_CLOEXEC)
+		if err == syscall.EAGAIN {
+			return false
+		} else if err != nil {
+			opErr = err
+			return true
+		}
+		data = data[:n]
+
+		msgs, err := syscall.ParseSocketControlMessage(oob[:oobn])
+		if err != nil {
+			opErr = err
+			return true
+		}
+		for _, m := range msgs {
+			if f, err := syscall.ParseUnixRights(&m); err == nil {
+				fds = append(fds, f...)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, fds, opErr
+}
+
+// Forward passes a core dump to the handler listening on path, usually
+// Socket, as systemd-coredump does, so a core_pattern helper can hand cores
+// to systemd-coredump or a Server. Fields too big for a datagram are
+// truncated.
+func Forward(path string, fields map[string]string, core *os.File) error {
+	conn, err := net.DialUnix("unixpacket", nil, &net.UnixAddr{Name: path, Net: "unixpacket"})
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	for k, v := range fields {
+		for {
+			_, err := conn.Write([]byte(k + "=" + v))
+			if err == nil {
+				break
+			}
+			if !isMsgSize(err) || len(v) == 0 {
+				return err
+			}
+			v = v[:len(v)/2]
+		}
+	}
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	if core != nil {
+		if err := sendSentinel(rc, int(core.Fd())); err != nil {
+			return err
+		}
+	}
+	return sendSentinel(rc)
+}
+
+// sendSentinel sends a zero-length datagram passing fds. Unlike
+// syscall.Sendmsg it does not add a dummy byte to pass them, which
+// systemd-coredump would take for a field.
+func sendSentinel(rc syscall.RawConn, fds ...int) error {
+	var msg syscall.Msghdr
+	if len(fds) > 0 {
+		oob := syscall.UnixRights(fds...)
+		msg.Control = &oob[0]
+		msg.SetControllen(len(oob))
+	}
+	var opErr error
+	err := rc.Write(func(fd uintptr) bool {
+		_, _, e := syscall.Syscall(syscall.SYS_SENDMSG, fd, uintptr(unsafe.Pointer(&msg)), 0)
+		if e == syscall.EAGAIN {
+			return false
+		} else if e != 0 {
+			opErr = e
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return opErr
+}
+
+func isMsgSize(err error) bool {
+	if op, ok := err.(*net.OpError); ok {
+		if se, ok := op.Err.(*os.SyscallError); ok {
+			return se.Err == syscall.EMSGSIZE
+		}
+	}
+	return false
+}
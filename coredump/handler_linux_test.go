@@ -0,0 +1,90 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestForward(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coredump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "coredump")
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reports := make(chan *Report, 1)
+	cores := make(chan string, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &Server{Handler: func(ctx context.Context, r *Report) error {
+		reports <- r
+		b, err := ioutil.ReadAll(r.Core)
+		if err != nil {
+			return err
+		}
+		cores <- string(b)
+		return nil
+	}}
+	done := make(chan error, 1)
+	go func() { done <- s.Serve(ctx, l) }()
+
+	corePath := filepath.Join(dir, "core")
+	if err := ioutil.WriteFile(corePath, []byte("\x7fELF core"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	core, err := os.Open(corePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer core.Close()
+
+	big := strings.Repeat("x", 8<<20)
+	fields := map[string]string{FieldPID: "1234", FieldComm: "crash", "COREDUMP_PROC_MAPS": big}
+	if err := Forward(path, fields, core); err != nil {
+		t.Fatal(err)
+	}
+
+	r := <-reports
+	if c := <-cores; c != "\x7fELF core" {
+		t.Errorf("unexpected core %q", c)
+	}
+	if r.Fields[FieldPID] != "1234" || r.Fields[FieldComm] != "crash" {
+		t.Errorf("unexpected fields %v", r.Fields)
+	}
+	if m := r.Fields["COREDUMP_PROC_MAPS"]; len(m) == 0 || len(m) >= len(big) || strings.Trim(m, "x") != "" {
+		t.Errorf("expected truncated field, got %d bytes", len(m))
+	}
+	c, err := r.Coredump()
+	if err != nil || c.PID != 1234 {
+		t.Errorf("unexpected core dump %+v, %v", c, err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseCorePatternArgs(t *testing.T) {
+	fields, err := ParseCorePatternArgs([]string{"1234", "1000", "100", "11", "1700000000", "18446744073709551615", "host", "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		FieldPID:            "1234",
+		FieldUID:            "1000",
+		FieldGID:            "100",
+		FieldSignal:         "11",
+		FieldTimestamp:      "1700000000000000",
+		FieldRlimit:         "18446744073709551615",
+		FieldHostname:       "host",
+		"COREDUMP_DUMPABLE": "1",
+	}
+	if len(fields) != len(want) {
+		t.Errorf("unexpected fields %v", fields)
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("expected %s=%s, got %q", k, v, fields[k])
+		}
+	}
+
+	for i, args := range [][]string{
+		{"1234", "1000", "100", "11", "1700000000", "0"},
+		{"pid", "1000", "100", "11", "1700000000", "0", "host"},
+	} {
+		if _, err := ParseCorePatternArgs(args); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestProcessFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coredump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d string) { procDir = d }(procDir)
+	procDir = dir
+
+	p := filepath.Join(dir, "1234")
+	if err := os.Mkdir(p, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{
+		"comm":    "crash\n",
+		"cmdline": "/usr/bin/crash\x00--flag\x00",
+		"cgroup":  "0::/system.slice/crash.service\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(p, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("/usr/bin/crash", filepath.Join(p, "exe")); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := ProcessFields(1234)
+	for k, v := range map[string]string{
+		FieldComm:    "crash",
+		FieldCmdline: "/usr/bin/crash --flag",
+		FieldExe:     "/usr/bin/crash",
+		FieldCgroup:  "/system.slice/crash.service",
+		FieldUnit:    "crash.service",
+		FieldSlice:   "system.slice",
+	} {
+		if fields[k] != v {
+			t.Errorf("expected %s=%s, got %q", k, v, fields[k])
+		}
+	}
+	if _, ok := fields["COREDUMP_CWD"]; ok {
+		t.Error("unexpected cwd field")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	fields := map[string]string{FieldPID: "1", FieldComm: "crash", FieldUID: "0"}
+	if m := Message(fields); m != "Process 1 (crash) of user 0 dumped core." {
+		t.Errorf("unexpected message %q", m)
+	}
+	fields[FieldSignalName] = "SIGSEGV"
+	if m := Message(fields); m != "Process 1 (crash) of user 0 terminated abnormally with signal SIGSEGV." {
+		t.Errorf("unexpected message %q", m)
+	}
+}
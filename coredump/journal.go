@@ -0,0 +1,137 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+)
+
+// Journal is the part of *sdjournal.Journal used to read core dumps from
+// the journal, so this package does not depend on libsystemd itself.
+type Journal interface {
+	FlushMatches()
+	AddMatch(match string) error
+	SeekHead() error
+	Next() (uint64, error)
+	GetCursor() (string, error)
+	SeekCursor(cursor string) error
+	SetDataThreshold(threshold uint64) error
+	GetDataValueBytes(field string) ([]byte, error)
+}
+
+// journalFields are the fields List reads besides the core.
+var journalFields = []string{
+	"MESSAGE",
+	"_BOOT_ID",
+	"_MACHINE_ID",
+	"_HOSTNAME",
+	FieldPID,
+	FieldUID,
+	FieldGID,
+	FieldSignal,
+	FieldSignalName,
+	FieldTimestamp,
+	FieldRlimit,
+	FieldHostname,
+	FieldComm,
+	FieldExe,
+	FieldCmdline,
+	FieldCgroup,
+	FieldUnit,
+	FieldUserUnit,
+	FieldSlice,
+	FieldOwnerUID,
+	FieldFilename,
+	FieldTruncated,
+	FieldPackage,
+	FieldVersion,
+	"COREDUMP_PACKAGE_JSON",
+	"COREDUMP_SESSION",
+	"COREDUMP_CWD",
+	"COREDUMP_ROOT",
+	"COREDUMP_CONTAINER_CMDLINE",
+}
+
+// List returns the core dumps logged to the journal j, oldest first. It
+// replaces the matches of j.
+func List(j Journal) ([]*Coredump, error) {
+	j.FlushMatches()
+	if err := j.AddMatch("MESSAGE_ID=" + MessageID); err != nil {
+		return nil, err
+	}
+	if err := j.SeekHead(); err != nil {
+		return nil, err
+	}
+
+	var dumps []*Coredump
+	for {
+		n, err := j.Next()
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return dumps, nil
+		}
+
+		// Missing fields are reported as errors, which cannot be told apart
+		// from others, so only the cursor is required.
+		cursor, err := j.GetCursor()
+		if err != nil {
+			return nil, err
+		}
+		fields := map[string]string{}
+		for _, f := range journalFields {
+			if v, err := j.GetDataValueBytes(f); err == nil {
+				fields[f] = string(v)
+			}
+		}
+		c, err := FromFields(fields)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := j.GetDataValueBytes(FieldCore); err == nil {
+			c.Embedded = true
+		}
+		c.Cursor = cursor
+		dumps = append(dumps, c)
+	}
+}
+
+// OpenCore opens the core of c, from the storage directory or, if it is
+// embedded, from the journal entry c was read from. Reading embedded cores
+// removes the data threshold of j, as they are usually bigger.
+func OpenCore(j Journal, c *Coredump) (io.ReadCloser, error) {
+	if !c.Embedded {
+		return c.Open()
+	}
+	if err := j.SeekCursor(c.Cursor); err != nil {
+		return nil, err
+	}
+	if n, err := j.Next(); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, ErrNoCore
+	}
+	if err := j.SetDataThreshold(0); err != nil {
+		return nil, err
+	}
+	b, err := j.GetDataValueBytes(FieldCore)
+	if err != nil {
+		return nil, ErrNoCore
+	}
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"testing"
+)
+
+// fakeJournal holds the entries matching the core dump message ID.
+type fakeJournal struct {
+	entries   []map[string]string
+	pos       int
+	threshold uint64
+	matches   []string
+}
+
+func (j *fakeJournal) FlushMatches()               { j.matches = nil }
+func (j *fakeJournal) AddMatch(match string) error { j.matches = append(j.matches, match); return nil }
+func (j *fakeJournal) SeekHead() error             { j.pos = -1; return nil }
+
+func (j *fakeJournal) Next() (uint64, error) {
+	if j.pos+1 >= len(j.entries) {
+		return 0, nil
+	}
+	j.pos++
+	return 1, nil
+}
+
+func (j *fakeJournal) GetCursor() (string, error) { return strconv.Itoa(j.pos), nil }
+
+func (j *fakeJournal) SeekCursor(cursor string) error {
+	n, err := strconv.Atoi(cursor)
+	j.pos = n - 1
+	return err
+}
+
+func (j *fakeJournal) SetDataThreshold(threshold uint64) error {
+	j.threshold = threshold
+	return nil
+}
+
+func (j *fakeJournal) GetDataValueBytes(field string) ([]byte, error) {
+	v, ok := j.entries[j.pos][field]
+	if !ok {
+		return nil, errors.New("failed to read message: no such file or directory")
+	}
+	if j.threshold != 0 && uint64(len(v)) > j.threshold {
+		v = v[:j.threshold]
+	}
+	return []byte(v), nil
+}
+
+func TestList(t *testing.T) {
+	j := &fakeJournal{
+		threshold: 8,
+		entries: []map[string]string{
+			{FieldPID: "1", FieldComm: "stored", FieldFilename: "/nonexistent/core.stored"},
+			{FieldPID: "2", FieldComm: "embedded", FieldCore: "\x7fELF core contents"},
+		},
+	}
+	dumps, err := List(j)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(j.matches) != 1 || j.matches[0] != "MESSAGE_ID="+MessageID {
+		t.Errorf("unexpected matches %v", j.matches)
+	}
+	if len(dumps) != 2 || dumps[0].Comm != "stored" || dumps[1].Comm != "embedded" {
+		t.Fatalf("unexpected core dumps %+v", dumps)
+	}
+	if dumps[0].Embedded || !dumps[1].Embedded {
+		t.Errorf("unexpected embedded flags %+v", dumps)
+	}
+
+	if _, err := OpenCore(j, dumps[0]); err != ErrNoCore {
+		t.Errorf("expected ErrNoCore for removed core, got %v", err)
+	}
+	r, err := OpenCore(j, dumps[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil || string(b) != "\x7fELF core contents" {
+		t.Errorf("unexpected core %q, %v", b, err)
+	}
+}
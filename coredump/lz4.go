@@ -0,0 +1,241 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+const lz4Magic = 0x184d2204
+
+var errLZ4Corrupt = errors.New("corrupt LZ4 data")
+
+// lz4Reader decodes the LZ4 frame format systemd compresses cores with.
+// Checksums are skipped rather than verified.
+type lz4Reader struct {
+	r            *bufio.Reader
+	blockSum     bool
+	contentSum   bool
+	independent  bool
+	maxBlockSize int
+	buf          []byte // decoded output, kept as dictionary for dependent blocks
+	out          []byte // pending part of buf
+	done         bool
+}
+
+func newLZ4Reader(r io.Reader) (io.ReadCloser, error) {
+	z := &lz4Reader{r: bufio.NewReader(r)}
+	if err := z.readHeader(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+func (z *lz4Reader) readHeader() error {
+	for {
+		var magic uint32
+		if err := binary.Read(z.r, binary.LittleEndian, &magic); err != nil {
+			return err
+		}
+		// Skippable frames may precede the data.
+		if magic&0xfffffff0 == 0x184d2a50 {
+			var n uint32
+			if err := binary.Read(z.r, binary.LittleEndian, &n); err != nil {
+				return err
+			}
+			if _, err := io.CopyN(ioutil.Discard, z.r, int64(n)); err != nil {
+				return err
+			}
+			continue
+		}
+		if magic != lz4Magic {
+			return errors.New("not LZ4 frame data")
+		}
+		break
+	}
+
+	var d [2]byte
+	if _, err := io.ReadFull(z.r, d[:]); err != nil {
+		return err
+	}
+	flg, bd := d[0], d[1]
+	if flg>>6 != 1 {
+		return fmt.Errorf("unsupported LZ4 frame version %d", flg>>6)
+	}
+	z.independent = flg&0x20 != 0
+	z.blockSum = flg&0x10 != 0
+	z.contentSum = flg&0x04 != 0
+	skip := 1 // header checksum
+	if flg&0x08 != 0 {
+		skip += 8 // content size
+	}
+	if flg&0x01 != 0 {
+		skip += 4 // dictionary ID
+	}
+	if _, err := z.r.Discard(skip); err != nil {
+		return err
+	}
+	switch (bd >> 4) & 7 {
+	case 4:
+		z.maxBlockSize = 64 << 10
+	case 5:
+		z.maxBlockSize = 256 << 10
+	case 6:
+		z.maxBlockSize = 1 << 20
+	case 7:
+		z.maxBlockSize = 4 << 20
+	default:
+		return errLZ4Corrupt
+	}
+	return nil
+}
+
+func (z *lz4Reader) Read(p []byte) (int, error) {
+	for len(z.out) == 0 {
+		if z.done {
+			return 0, io.EOF
+		}
+		if err := z.readBlock(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, z.out)
+	z.out = z.out[n:]
+	return n, nil
+}
+
+func (z *lz4Reader) readBlock() error {
+	var size uint32
+	if err := binary.Read(z.r, binary.LittleEndian, &size); err != nil {
+		return unexpectedEOF(err)
+	}
+	if size == 0 {
+		if z.contentSum {
+			if _, err := z.r.Discard(4); err != nil {
+				return unexpectedEOF(err)
+			}
+		}
+		z.done = true
+		return nil
+	}
+	uncompressed := size&0x80000000 != 0
+	size &^= 0x80000000
+	if int(size) > z.maxBlockSize {
+		return errLZ4Corrupt
+	}
+	block := make([]byte, size)
+	if _, err := io.ReadFull(z.r, block); err != nil {
+		return unexpectedEOF(err)
+	}
+	if z.blockSum {
+		if _, err := z.r.Discard(4); err != nil {
+			return unexpectedEOF(err)
+		}
+	}
+
+	// Dependent blocks may refer to the last 64 KiB of earlier output.
+	if z.independent {
+		z.buf = z.buf[:0]
+	} else if len(z.buf) > 64<<10 {
+		z.buf = append(z.buf[:0], z.buf[len(z.buf)-64<<10:]...)
+	}
+	start := len(z.buf)
+	if uncompressed {
+		z.buf = append(z.buf, block...)
+	} else {
+		var err error
+		if z.buf, err = lz4DecodeBlock(z.buf, block, z.maxBlockSize); err != nil {
+			return err
+		}
+	}
+	z.out = z.buf[start:]
+	return nil
+}
+
+func (z *lz4Reader) Close() error {
+	return nil
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// lz4DecodeBlock appends the decoded LZ4 block src to dst, whose contents
+// serve as dictionary. Blocks decoding to more than max bytes are corrupt.
+func lz4DecodeBlock(dst, src []byte, max int) ([]byte, error) {
+	limit := len(dst) + max
+	length := func(n int, i *int) (int, error) {
+		if n != 15 {
+			return n, nil
+		}
+		for {
+			if *i >= len(src) {
+				return 0, errLZ4Corrupt
+			}
+			b := src[*i]
+			*i++
+			n += int(b)
+			if b != 255 {
+				return n, nil
+			}
+		}
+	}
+
+	for i := 0; i < len(src); {
+		token := src[i]
+		i++
+		lits, err := length(int(token>>4), &i)
+		if err != nil {
+			return nil, err
+		}
+		if i+lits > len(src) || len(dst)+lits > limit {
+			return nil, errLZ4Corrupt
+		}
+		dst = append(dst, src[i:i+lits]...)
+		i += lits
+		if i == len(src) {
+			// The last sequence has literals only.
+			break
+		}
+
+		if i+2 > len(src) {
+			return nil, errLZ4Corrupt
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		n, err := length(int(token&15), &i)
+		if err != nil {
+			return nil, err
+		}
+		n += 4
+		if offset == 0 || offset > len(dst) || len(dst)+n > limit {
+			return nil, errLZ4Corrupt
+		}
+		// Matches may overlap the output they produce.
+		pos := len(dst) - offset
+		for j := 0; j < n; j++ {
+			dst = append(dst, dst[pos+j])
+		}
+	}
+	return dst, nil
+}
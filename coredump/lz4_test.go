@@ -0,0 +1,93 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// lz4Bomb returns a block with a single match decoding to more than the
+// 64 KiB block size.
+func lz4Bomb() []byte {
+	seq := []byte{0x1f, 'a', 1, 0}
+	for i := 0; i < 300; i++ {
+		seq = append(seq, 255)
+	}
+	seq = append(seq, 0, 0x10, 'b')
+	return append([]byte{byte(len(seq)), byte(len(seq) >> 8), 0, 0}, seq...)
+}
+
+// lz4Frame returns an LZ4 frame with the given flags, 64 KiB blocks and the
+// given blocks, followed by the end mark.
+func lz4Frame(flg byte, blocks ...[]byte) []byte {
+	b := []byte{0x04, 0x22, 0x4d, 0x18, flg, 0x40, 0x00}
+	for _, block := range blocks {
+		b = append(b, block...)
+	}
+	return append(b, 0, 0, 0, 0)
+}
+
+func TestLZ4(t *testing.T) {
+	// "abc", then a match of 9 bytes at offset 3 overlapping its output,
+	// then the literals "xyz".
+	compressed := []byte{10, 0, 0, 0, 0x35, 'a', 'b', 'c', 3, 0, 0x30, 'x', 'y', 'z'}
+	// A match referring to the previous block.
+	dependent := []byte{3, 0, 0, 0, 0x00, 3, 0}
+	skippable := []byte{0x50, 0x2a, 0x4d, 0x18, 2, 0, 0, 0, 'h', 'i'}
+
+	for i, tt := range []struct {
+		data []byte
+		want string
+	}{
+		{lz4Frame(0x60, []byte{5, 0, 0, 0x80, 'h', 'e', 'l', 'l', 'o'}), "hello"},
+		{lz4Frame(0x60, compressed), "abcabcabcabcxyz"},
+		{lz4Frame(0x40, compressed, dependent), "abcabcabcabcxyzxyzx"},
+		// Block checksums are skipped.
+		{lz4Frame(0x70, []byte{2, 0, 0, 0x80, 'h', 'i', 1, 2, 3, 4}), "hi"},
+		{append(skippable, lz4Frame(0x60, []byte{2, 0, 0, 0x80, 'h', 'i'})...), "hi"},
+	} {
+		r, err := newLZ4Reader(bytes.NewReader(tt.data))
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+			continue
+		}
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		} else if string(b) != tt.want {
+			t.Errorf("case %d: expected %q, got %q", i, tt.want, b)
+		}
+	}
+
+	for i, data := range [][]byte{
+		[]byte("not lz4"),
+		// Truncated block.
+		lz4Frame(0x60, []byte{5, 0, 0, 0x80, 'h'})[:12],
+		// Match before the start of the output.
+		lz4Frame(0x60, []byte{3, 0, 0, 0, 0x00, 3, 0}),
+		// Output exceeding the maximum block size.
+		lz4Frame(0x60, lz4Bomb()),
+	} {
+		r, err := newLZ4Reader(bytes.NewReader(data))
+		if err == nil {
+			_, err = ioutil.ReadAll(r)
+		}
+		if err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StorageDir is where systemd-coredump stores cores, a variable so it can
+// be changed by tests.
+var StorageDir = "/var/lib/systemd/coredump"
+
+// ParseFilename returns the core dump described by the name of a stored
+// core, core.<comm>.<uid>.<boot id>.<pid>.<timestamp> followed by the
+// compression extension.
+func ParseFilename(name string) (*Coredump, error) {
+	errInvalid := errors.New("invalid core file name " + strconv.Quote(name))
+	base := filepath.Base(name)
+	if !strings.HasPrefix(base, "core.") {
+		return nil, errInvalid
+	}
+	parts := strings.Split(strings.TrimSuffix(base[len("core."):], compression(base)), ".")
+	if len(parts) != 5 {
+		return nil, errInvalid
+	}
+
+	comm, err := unescape(parts[0])
+	if err != nil {
+		return nil, errInvalid
+	}
+	uid, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, errInvalid
+	}
+	pid, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return nil, errInvalid
+	}
+	usec, err := strconv.ParseInt(parts[4], 10, 64)
+	if err != nil {
+		return nil, errInvalid
+	}
+	return &Coredump{
+		PID:       pid,
+		UID:       uint32(uid),
+		Timestamp: time.Unix(0, usec*int64(time.Microsecond)),
+		Comm:      comm,
+		BootID:    parts[2],
+		Filename:  name,
+	}, nil
+}
+
+// unescape reverses the \xNN escaping systemd-coredump applies to the
+// command name in file names.
+func unescape(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+3 >= len(s) || s[i+1] != 'x' {
+			return "", errors.New("invalid escape")
+		}
+		n, err := strconv.ParseUint(s[i+2:i+4], 16, 8)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte(n))
+		i += 3
+	}
+	return b.String(), nil
+}
+
+// ListStored returns the cores in StorageDir, oldest first. Only the
+// metadata encoded in the file names and, on Linux, their extended
+// attributes is available; the journal has the full metadata.
+func ListStored() ([]*Coredump, error) {
+	entries, err := ioutil.ReadDir(StorageDir)
+	if err != nil {
+		return nil, err
+	}
+	var dumps []*Coredump
+	for _, e := range entries {
+		if !e.Mode().IsRegular() {
+			continue
+		}
+		c, err := ParseFilename(filepath.Join(StorageDir, e.Name()))
+		if err != nil {
+			continue
+		}
+		readXattrs(c)
+		dumps = append(dumps, c)
+	}
+	sort.SliceStable(dumps, func(i, j int) bool {
+		return dumps[i].Timestamp.Before(dumps[j].Timestamp)
+	})
+	return dumps, nil
+}
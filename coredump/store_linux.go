@@ -0,0 +1,53 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"strconv"
+	"syscall"
+)
+
+// readXattrs fills in the metadata systemd-coredump attaches to stored
+// cores as user.coredump.* extended attributes.
+func readXattrs(c *Coredump) {
+	get := func(name string) (string, bool) {
+		buf := make([]byte, 4096)
+		n, err := syscall.Getxattr(c.Filename, "user.coredump."+name, buf)
+		if err != nil {
+			return "", false
+		}
+		return string(buf[:n]), true
+	}
+
+	if v, ok := get("gid"); ok {
+		if n, err := strconv.ParseUint(v, 10, 32); err == nil {
+			c.GID = uint32(n)
+		}
+	}
+	if v, ok := get("signal"); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.Signal = n
+		}
+	}
+	if v, ok := get("exe"); ok {
+		c.Exe = v
+	}
+	if v, ok := get("hostname"); ok {
+		c.Hostname = v
+	}
+	if v, ok := get("comm"); ok {
+		c.Comm = v
+	}
+}
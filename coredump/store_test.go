@@ -0,0 +1,79 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coredump
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFilename(t *testing.T) {
+	c, err := ParseFilename("/var/lib/systemd/coredump/core.my\\x2eapp.1000.2d0ed8586e8d4d7b9f0b72e8a3d45e0b.1234.1700000000123456.zst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Comm != "my.app" || c.UID != 1000 || c.PID != 1234 || c.BootID != "2d0ed8586e8d4d7b9f0b72e8a3d45e0b" {
+		t.Errorf("unexpected core dump %+v", c)
+	}
+	if c.Timestamp.UnixNano() != 1700000000123456000 {
+		t.Errorf("unexpected timestamp %v", c.Timestamp)
+	}
+
+	for _, name := range []string{
+		"vmcore",
+		"core.app.1000.1234.1700000000123456",
+		"core.app.x.2d0ed8586e8d4d7b9f0b72e8a3d45e0b.1234.1700000000123456",
+		"core.a\\x2.1000.2d0ed8586e8d4d7b9f0b72e8a3d45e0b.1234.1700000000123456",
+	} {
+		if _, err := ParseFilename(name); err == nil {
+			t.Errorf("expected error for %q", name)
+		}
+	}
+}
+
+func TestListStored(t *testing.T) {
+	dir, err := ioutil.TempDir("", "coredump")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d string) { StorageDir = d }(StorageDir)
+	StorageDir = dir
+
+	for _, name := range []string{
+		"core.b.0.2d0ed8586e8d4d7b9f0b72e8a3d45e0b.2.1700000000000002.xz",
+		"core.a.0.2d0ed8586e8d4d7b9f0b72e8a3d45e0b.1.1700000000000001",
+		"core.c.0.2d0ed8586e8d4d7b9f0b72e8a3d45e0b.3.1700000000000003.lz4",
+		".#core.tmp",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dumps, err := ListStored()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var comms string
+	for _, c := range dumps {
+		comms += c.Comm
+	}
+	if comms != "abc" {
+		t.Errorf("unexpected cores %q", comms)
+	}
+}
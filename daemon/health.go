@@ -0,0 +1,185 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HealthCheck is a named liveness check of a service.
+type HealthCheck struct {
+	Name string
+	// Check returns an error if the service is unhealthy. It should return
+	// once ctx is done.
+	Check func(ctx context.Context) error
+	// Timeout is how long Check may take before the service is considered
+	// unhealthy, defaulting to half of the check interval. With the default
+	// interval, a hanging check thus delays the next watchdog ping by at most
+	// three quarters of the watchdog timeout; longer timeouts risk missing it.
+	Timeout time.Duration
+}
+
+// Watchdog pings the service manager's watchdog only while all registered
+// health checks pass, so that systemd restarts the service according to its
+// WatchdogSec= and Restart= settings when it stops being healthy, and reports
+// the failed checks in the service status.
+type Watchdog struct {
+	// Interval is how often the checks run and the watchdog is pinged,
+	// defaulting to half of the timeout systemd passes in WATCHDOG_USEC.
+	Interval time.Duration
+	// Status is sent as service status once all checks pass again after a
+	// failure.
+	Status string
+
+	mu      sync.Mutex
+	checks  []HealthCheck
+	lastErr error
+}
+
+// Register adds a health check. Checks may be registered while the watchdog
+// runs.
+func (w *Watchdog) Register(name string, timeout time.Duration, check func(ctx context.Context) error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.checks = append(w.checks, HealthCheck{Name: name, Check: check, Timeout: timeout})
+}
+
+// HealthError is returned by Watchdog.Check for failed health checks.
+type HealthError struct {
+	Failures map[string]error // The errors of the failed checks by name
+	order    []string
+}
+
+func (e *HealthError) Error() string {
+	msgs := make([]string, 0, len(e.order))
+	for _, name := range e.order {
+		msgs = append(msgs, fmt.Sprintf("health check %s failed: %v", name, e.Failures[name]))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Check runs all health checks concurrently, each bounded by its timeout,
+// and returns a *HealthError if any of them failed. A check that does not
+// return within its timeout fails, though it keeps running until it returns
+// by itself.
+func (w *Watchdog) Check(ctx context.Context) error {
+	w.mu.Lock()
+	checks := append([]HealthCheck(nil), w.checks...)
+	interval := w.Interval
+	w.mu.Unlock()
+
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		timeout := c.Timeout
+		if timeout <= 0 {
+			timeout = interval / 2
+		}
+		if timeout <= 0 {
+			timeout = time.Minute
+		}
+		wg.Add(1)
+		go func(i int, c HealthCheck, timeout time.Duration) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			done := make(chan error, 1)
+			go func() {
+				done <- c.Check(ctx)
+			}()
+			select {
+			case errs[i] = <-done:
+			case <-ctx.Done():
+				errs[i] = fmt.Errorf("no result after %v", timeout)
+			}
+		}(i, c, timeout)
+	}
+	wg.Wait()
+
+	herr := &HealthError{Failures: make(map[string]error)}
+	for i, err := range errs {
+		if err != nil {
+			herr.Failures[checks[i].Name] = err
+			herr.order = append(herr.order, checks[i].Name)
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(herr.order) == 0 {
+		w.lastErr = nil
+		return nil
+	}
+	w.lastErr = herr
+	return herr
+}
+
+// Healthy returns the result of the last run of the checks, nil if they all
+// passed. It can be used to answer health probes, e.g. over HTTP, without
+// running the checks again.
+func (w *Watchdog) Healthy() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
+
+// Run runs the checks every interval until ctx is done, pinging the watchdog
+// when they pass and setting the service status to the failure reasons when
+// they do not. It returns immediately if neither Interval is set nor the
+// service manager enabled the watchdog, and returns ctx.Err() once ctx is
+// done.
+func (w *Watchdog) Run(ctx context.Context) error {
+	w.mu.Lock()
+	interval := w.Interval
+	w.mu.Unlock()
+	if interval <= 0 {
+		timeout, err := SdWatchdogEnabled(false)
+		if err != nil || timeout == 0 {
+			return err
+		}
+		interval = timeout / 2
+		w.mu.Lock()
+		w.Interval = interval
+		w.mu.Unlock()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failing := false
+	for {
+		if err := w.Check(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failing = true
+			SdNotify(false, "STATUS="+strings.Replace(err.Error(), "\n", " ", -1))
+		} else if failing {
+			failing = false
+			SdNotify(false, SdNotifyWatchdog+"\nSTATUS="+w.Status)
+		} else {
+			SdNotify(false, SdNotifyWatchdog)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
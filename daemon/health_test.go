@@ -0,0 +1,148 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchdogCheck(t *testing.T) {
+	var w Watchdog
+	if err := w.Check(context.Background()); err != nil {
+		t.Errorf("unexpected error without checks: %v", err)
+	}
+
+	w.Register("ok", 0, func(context.Context) error { return nil })
+	w.Register("db", 0, func(context.Context) error { return errors.New("connection refused") })
+	w.Register("slow", 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	})
+	start := time.Now()
+	err := w.Check(context.Background())
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("check waited for slow check")
+	}
+	herr, ok := err.(*HealthError)
+	if !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(herr.Failures) != 2 || herr.Failures["db"] == nil || herr.Failures["slow"] == nil {
+		t.Errorf("unexpected failures %v", herr.Failures)
+	}
+	want := "health check db failed: connection refused; health check slow failed: no result after 10ms"
+	if err.Error() != want {
+		t.Errorf("unexpected message %q", err)
+	}
+	if w.Healthy() != err {
+		t.Errorf("unexpected last result %v", w.Healthy())
+	}
+}
+
+func TestWatchdogCheckDefaultTimeout(t *testing.T) {
+	// Hanging checks must leave time to ping the watchdog within the
+	// interval.
+	w := &Watchdog{Interval: 40 * time.Millisecond}
+	w.Register("hang", 0, func(ctx context.Context) error {
+		<-ctx.Done()
+		time.Sleep(time.Second)
+		return nil
+	})
+	want := "health check hang failed: no result after 20ms"
+	if err := w.Check(context.Background()); err == nil || err.Error() != want {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestWatchdogRun(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "watchdog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+
+	notifySocket := testDir + "/notify-socket.sock"
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: notifySocket, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	must(os.Setenv("NOTIFY_SOCKET", notifySocket))
+	defer os.Unsetenv("NOTIFY_SOCKET")
+
+	var healthy int32 = 1
+	w := &Watchdog{Interval: 10 * time.Millisecond, Status: "serving"}
+	w.Register("db", 0, func(context.Context) error {
+		if atomic.LoadInt32(&healthy) == 0 {
+			return errors.New("down")
+		}
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	read := func() string {
+		buf := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf[:n])
+	}
+	if msg := read(); msg != SdNotifyWatchdog {
+		t.Errorf("unexpected message %q", msg)
+	}
+	atomic.StoreInt32(&healthy, 0)
+	msg := read()
+	for msg == SdNotifyWatchdog {
+		msg = read()
+	}
+	if msg != "STATUS=health check db failed: down" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	atomic.StoreInt32(&healthy, 1)
+	for !strings.HasPrefix(msg, SdNotifyWatchdog) {
+		msg = read()
+	}
+	if msg != SdNotifyWatchdog+"\nSTATUS=serving" {
+		t.Errorf("unexpected message %q", msg)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("unexpected result %v", err)
+	}
+}
+
+func TestWatchdogRunDisabled(t *testing.T) {
+	must(os.Unsetenv("WATCHDOG_USEC"))
+	var w Watchdog
+	if err := w.Run(context.Background()); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
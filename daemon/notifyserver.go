@@ -0,0 +1,181 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// notifyBufferMax is the size of the largest notification accepted, larger
+// ones are dropped like systemd does.
+const notifyBufferMax = 4096
+
+// NotifyMessage is a notification received by a NotifyServer.
+type NotifyMessage struct {
+	PID    int               // The sender, 0 if unknown
+	UID    int               // The user of the sender, -1 if unknown
+	GID    int               // The group of the sender, -1 if unknown
+	Fields map[string]string // The assignments, e.g. READY=1
+	Raw    string
+	// Files are the file descriptors passed along, e.g. with FDSTORE=1.
+	Files []*os.File
+}
+
+// NotifyServer is the receiving side of SdNotify, for programs managing
+// services like an init system or test harness does. Senders are identified
+// by the credentials the kernel attaches to their messages, which is only
+// supported on Linux.
+type NotifyServer struct {
+	// Path is the socket to bind. If it starts with @, it is bound in the
+	// abstract namespace. If empty, Listen creates the socket in a new
+	// temporary directory and sets Path.
+	Path string
+	// Authorize, if set, is called with every notification and drops it
+	// unless it returns true, for example to only accept notifications of
+	// the main process of a service like NotifyAccess=main does.
+	Authorize func(NotifyMessage) bool
+	// Handler is called with every notification accepted and owns the files
+	// passed with it. If unset, the files are closed.
+	Handler func(NotifyMessage)
+
+	mu   sync.Mutex
+	conn *net.UnixConn // nil once closed
+	dir  string        // created by Listen, if any
+}
+
+// Listen binds the socket. Children are passed it with Env.
+func (s *NotifyServer) Listen() error {
+	path := s.Path
+	var dir string
+	if path == "" {
+		var err error
+		if dir, err = ioutil.TempDir("", "notify"); err != nil {
+			return err
+		}
+		path = filepath.Join(dir, "notify")
+	}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		if dir != "" {
+			os.RemoveAll(dir)
+		}
+		return err
+	}
+	if err := passCred(conn); err != nil {
+		conn.Close()
+		if dir != "" {
+			os.RemoveAll(dir)
+		}
+		return err
+	}
+	s.mu.Lock()
+	s.Path, s.conn, s.dir = path, conn, dir
+	s.mu.Unlock()
+	return nil
+}
+
+// Env returns the NOTIFY_SOCKET environment variable for children.
+func (s *NotifyServer) Env() string {
+	return "NOTIFY_SOCKET=" + s.Path
+}
+
+// Serve receives notifications until ctx is done, then closes the socket
+// and returns nil. Truncated notifications are dropped.
+func (s *NotifyServer) Serve(ctx context.Context) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return errors.New("notify socket is not listening")
+	}
+	defer s.Close()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		conn.Close()
+	}()
+
+	buf := make([]byte, notifyBufferMax)
+	oob := make([]byte, oobSize)
+	for {
+		n, oobn, flags, _, err := conn.ReadMsgUnix(buf, oob)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		m := parseNotify(string(buf[:n]))
+		m.PID, m.UID, m.GID, m.Files = parseControl(oob[:oobn])
+		if truncated(flags) {
+			closeFiles(m.Files)
+			continue
+		}
+		if s.Authorize != nil && !s.Authorize(m) {
+			closeFiles(m.Files)
+			continue
+		}
+		if s.Handler == nil {
+			closeFiles(m.Files)
+			continue
+		}
+		s.Handler(m)
+	}
+}
+
+// Close closes the socket and removes it. Closing it again does nothing, in
+// particular it does not remove a socket bound at Path since.
+func (s *NotifyServer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	if s.dir != "" {
+		os.RemoveAll(s.dir)
+	} else if !strings.HasPrefix(s.Path, "@") {
+		os.Remove(s.Path)
+	}
+	return err
+}
+
+func parseNotify(raw string) NotifyMessage {
+	m := NotifyMessage{UID: -1, GID: -1, Fields: make(map[string]string), Raw: raw}
+	for _, line := range strings.Split(raw, "\n") {
+		if i := strings.IndexByte(line, '='); i > 0 {
+			m.Fields[line[:i]] = line[i+1:]
+		}
+	}
+	return m
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// maxNotifyFds is the most file descriptors received with a notification,
+// the limit of the kernel.
+const maxNotifyFds = 253
+
+var oobSize = syscall.CmsgSpace(syscall.SizeofUcred) + syscall.CmsgSpace(4*maxNotifyFds)
+
+// passCred makes the kernel attach the credentials of senders to received
+// notifications.
+func passCred(conn *net.UnixConn) error {
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	if err := rc.Control(func(fd uintptr) {
+		serr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_PASSCRED, 1)
+	}); err != nil {
+		return err
+	}
+	return serr
+}
+
+// parseControl returns the credentials of the sender and the files passed
+// with a notification.
+func parseControl(oob []byte) (pid, uid, gid int, files []*os.File) {
+	uid, gid = -1, -1
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return
+	}
+	for _, m := range msgs {
+		if cred, err := syscall.ParseUnixCredentials(&m); err == nil {
+			pid, uid, gid = int(cred.Pid), int(cred.Uid), int(cred.Gid)
+		} else if fds, err := syscall.ParseUnixRights(&m); err == nil {
+			for _, fd := range fds {
+				files = append(files, os.NewFile(uintptr(fd), "notify"))
+			}
+		}
+	}
+	return
+}
+
+// truncated returns whether a notification or its control data did not fit
+// into the buffers.
+func truncated(flags int) bool {
+	return flags&(syscall.MSG_TRUNC|syscall.MSG_CTRUNC) != 0
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyServer(t *testing.T) {
+	for _, path := range []string{"", fmt.Sprintf("@go-systemd-notify-%d", os.Getpid())} {
+		received := make(chan NotifyMessage, 10)
+		s := &NotifyServer{
+			Path:      path,
+			Authorize: func(m NotifyMessage) bool { return m.Fields["REJECT"] != "1" },
+			Handler:   func(m NotifyMessage) { received <- m },
+		}
+		if err := s.Listen(); err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		done := make(chan error)
+		go func() {
+			done <- s.Serve(ctx)
+		}()
+
+		addr := &net.UnixAddr{Name: s.Path, Net: "unixgram"}
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.WriteToUnix([]byte("REJECT=1"), addr); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := conn.WriteMsgUnix([]byte("FDSTORE=1\nFDNAME=pipe"), syscall.UnixRights(int(w.Fd())), addr); err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+		conn.Close()
+
+		m := <-received
+		if m.PID != os.Getpid() || m.UID != os.Getuid() || m.GID != os.Getgid() {
+			t.Errorf("unexpected sender %d %d:%d", m.PID, m.UID, m.GID)
+		}
+		if m.Fields["FDNAME"] != "pipe" || len(m.Files) != 1 {
+			t.Fatalf("unexpected message %+v", m)
+		}
+		m.Files[0].Write([]byte("hello"))
+		m.Files[0].Close()
+		if b, err := ioutil.ReadAll(r); err != nil || string(b) != "hello" {
+			t.Errorf("unexpected data from passed file %q, %v", b, err)
+		}
+		r.Close()
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("unexpected result %v", err)
+		}
+		if len(received) != 0 {
+			t.Errorf("rejected message %+v received", <-received)
+		}
+		if path == "" {
+			if _, err := os.Stat(s.Path); !os.IsNotExist(err) {
+				t.Errorf("socket left behind: %v", err)
+			}
+		}
+	}
+}
+
+func TestNotifyServerCloseTwice(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := &NotifyServer{Path: filepath.Join(dir, "notify")}
+	if err := s.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Serve(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process binds the path after Serve removed the socket.
+	other, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: s.Path, Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Path); err != nil {
+		t.Errorf("socket of another process removed: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package daemon
+
+import (
+	"net"
+	"os"
+)
+
+// Senders of notifications can only be identified on Linux, and files are
+// not received either.
+const oobSize = 0
+
+func passCred(conn *net.UnixConn) error {
+	return nil
+}
+
+func parseControl(oob []byte) (pid, uid, gid int, files []*os.File) {
+	return 0, -1, -1, nil
+}
+
+func truncated(flags int) bool {
+	return false
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// NotifyState is the state of a child process accumulated from its
+// notifications.
+type NotifyState struct {
+	Ready     bool
+	Reloading bool
+	Stopping  bool
+	Status    string
+	Errno     int
+	MainPID   int
+	// Watchdog is when the child last sent WATCHDOG=1.
+	Watchdog time.Time
+}
+
+// NotifyProxy provides a private notification socket for child processes,
+// so that supervisors can run programs expecting to be started as
+// Type=notify services and track their readiness.
+type NotifyProxy struct {
+	// Forward sends every notification on to the service manager of this
+	// process. File descriptors are not forwarded.
+	Forward bool
+	// Handler, if set, is called with every notification. The files passed
+	// with it are closed when it returns.
+	Handler func(NotifyMessage)
+
+	// Path is the socket children send to, set by Listen.
+	Path string
+
+	srv *NotifyServer
+
+	mu      sync.Mutex
+	states  map[int]*NotifyState
+	changed chan struct{}
+}
+
+// Listen creates the socket in a new temporary directory.
+func (p *NotifyProxy) Listen() error {
+	srv := &NotifyServer{}
+	if err := srv.Listen(); err != nil {
+		return err
+	}
+	p.Path, p.srv = srv.Path, srv
+	p.states = make(map[int]*NotifyState)
+	p.changed = make(chan struct{})
+	return nil
+}
+
+// Env returns the NOTIFY_SOCKET environment variable for children.
+func (p *NotifyProxy) Env() string {
+	return "NOTIFY_SOCKET=" + p.Path
+}
+
+// SetEnv sets NOTIFY_SOCKET in the environment of cmd, which defaults to
+// that of this process, replacing the one of the service manager.
+func (p *NotifyProxy) SetEnv(cmd *exec.Cmd) {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = []string{p.Env()}
+	for _, e := range env {
+		if !strings.HasPrefix(e, "NOTIFY_SOCKET=") {
+			cmd.Env = append(cmd.Env, e)
+		}
+	}
+}
+
+// Serve receives notifications until ctx is done, then closes the socket
+// and returns nil.
+func (p *NotifyProxy) Serve(ctx context.Context) error {
+	if p.srv == nil {
+		return errors.New("proxy is not listening")
+	}
+	p.srv.Handler = func(m NotifyMessage) {
+		defer closeFiles(m.Files)
+		p.update(m)
+		if p.Forward {
+			SdNotify(false, m.Raw)
+		}
+		if p.Handler != nil {
+			p.Handler(m)
+		}
+	}
+	return p.srv.Serve(ctx)
+}
+
+func (p *NotifyProxy) update(m NotifyMessage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.states[m.PID]
+	if s == nil {
+		s = &NotifyState{}
+		p.states[m.PID] = s
+	}
+	for k, v := range m.Fields {
+		switch k {
+		case "READY":
+			s.Ready = v == "1"
+			if s.Ready {
+				s.Reloading = false
+			}
+		case "RELOADING":
+			s.Reloading = v == "1"
+		case "STOPPING":
+			s.Stopping = v == "1"
+		case "STATUS":
+			s.Status = v
+		case "ERRNO":
+			s.Errno, _ = strconv.Atoi(v)
+		case "MAINPID":
+			s.MainPID, _ = strconv.Atoi(v)
+		case "WATCHDOG":
+			if v == "1" {
+				s.Watchdog = time.Now()
+			}
+		}
+	}
+	close(p.changed)
+	p.changed = make(chan struct{})
+}
+
+// State returns the state of the child with the given PID, which is 0 on
+// systems where senders cannot be identified.
+func (p *NotifyProxy) State(pid int) (NotifyState, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s, ok := p.states[pid]
+	if !ok {
+		return NotifyState{}, false
+	}
+	return *s, true
+}
+
+// WaitReady waits until the child with the given PID sent READY=1. It fails
+// if the child reported an error with ERRNO= first.
+func (p *NotifyProxy) WaitReady(ctx context.Context, pid int) error {
+	for {
+		p.mu.Lock()
+		s, changed := p.states[pid], p.changed
+		var state NotifyState
+		if s != nil {
+			state = *s
+		}
+		p.mu.Unlock()
+
+		if state.Ready {
+			return nil
+		}
+		if state.Errno != 0 {
+			return fmt.Errorf("process %d failed: %v", pid, syscall.Errno(state.Errno))
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
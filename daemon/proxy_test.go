@@ -0,0 +1,127 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package daemon
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifyProxyChild(t *testing.T) {
+	if os.Getenv("NOTIFY_PROXY_CHILD") == "" {
+		t.Skip("only run as child of TestNotifyProxy")
+	}
+	SdNotify(false, "STATUS=starting")
+	if os.Getenv("NOTIFY_PROXY_CHILD") == "fail" {
+		SdNotify(false, "ERRNO=2")
+		return
+	}
+	SdNotify(false, "READY=1\nSTATUS=up")
+}
+
+func TestNotifyProxy(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("senders can only be told apart on Linux")
+	}
+
+	// The notifications forwarded to the service manager.
+	testDir, err := ioutil.TempDir("", "notify-proxy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+	upstream, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: testDir + "/notify", Net: "unixgram"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer upstream.Close()
+	must(os.Setenv("NOTIFY_SOCKET", testDir+"/notify"))
+	defer os.Unsetenv("NOTIFY_SOCKET")
+
+	received := make(chan NotifyMessage, 10)
+	p := &NotifyProxy{Forward: true, Handler: func(m NotifyMessage) { received <- m }}
+	if err := p.Listen(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- p.Serve(ctx)
+	}()
+
+	start := func(mode string) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=TestNotifyProxyChild", "-test.count=1")
+		cmd.Env = append(os.Environ(), "NOTIFY_PROXY_CHILD="+mode)
+		p.SetEnv(cmd)
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+	ok := start("ok")
+	fail := start("fail")
+	defer ok.Wait()
+	defer fail.Wait()
+
+	if err := p.WaitReady(ctx, ok.Process.Pid); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := p.State(ok.Process.Pid); !s.Ready || s.Status != "up" {
+		t.Errorf("unexpected state %+v", s)
+	}
+	if err := p.WaitReady(ctx, fail.Process.Pid); err == nil || !strings.Contains(err.Error(), "no such file") {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, ok := p.State(12345678); ok {
+		t.Error("state of unknown process")
+	}
+
+	m := <-received
+	if m.PID != ok.Process.Pid && m.PID != fail.Process.Pid || m.Fields["STATUS"] != "starting" {
+		t.Errorf("unexpected message %+v", m)
+	}
+	buf := make([]byte, 4096)
+	upstream.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, err := upstream.Read(buf)
+	if err != nil || string(buf[:n]) != "STATUS=starting" {
+		t.Errorf("unexpected forwarded message %q, %v", buf[:n], err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected result %v", err)
+	}
+	if _, err := os.Stat(p.Path); !os.IsNotExist(err) {
+		t.Errorf("socket left behind: %v", err)
+	}
+}
+
+func TestSetEnv(t *testing.T) {
+	p := &NotifyProxy{Path: "/run/proxy"}
+	cmd := exec.Command("true")
+	cmd.Env = []string{"A=1", "NOTIFY_SOCKET=/run/systemd/notify"}
+	p.SetEnv(cmd)
+	if strings.Join(cmd.Env, " ") != "NOTIFY_SOCKET=/run/proxy A=1" {
+		t.Errorf("unexpected environment %q", cmd.Env)
+	}
+}
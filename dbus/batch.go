@@ -0,0 +1,214 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Batch collects unit jobs to run them together with Run. Its methods
+// return the batch, so jobs can be chained like
+//
+//	conn.NewBatch().Stop("a.service", "replace").Start("b.service", "replace").Run(ctx)
+type Batch struct {
+	// FailFast runs the jobs one after another and stops at the first one
+	// that fails, skipping the rest. Otherwise all jobs are enqueued at once
+	// and run independently, and the failures are reported together.
+	FailFast bool
+
+	conn *Conn
+	ops  []batchOp
+}
+
+type batchOp struct {
+	method string
+	unit   string
+	mode   string
+}
+
+// BatchResult is the outcome of a job of a Batch.
+type BatchResult struct {
+	Method string // The manager method, such as StartUnit
+	Unit   string
+	JobID  int
+	// Result is the job result, such as "done", "failed" or "canceled", or
+	// "skipped" for jobs not run after an earlier failure in fail-fast mode.
+	Result string
+	Err    error // The error enqueueing the job, if any
+}
+
+func (r *BatchResult) failed() bool {
+	return r.Err != nil || (r.Result != "done" && r.Result != "skipped")
+}
+
+func (r *BatchResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s %s: %v", r.Method, r.Unit, r.Err)
+	}
+	return fmt.Sprintf("%s %s: %s", r.Method, r.Unit, r.Result)
+}
+
+// BatchError is returned by Batch.Run if jobs failed.
+type BatchError struct {
+	Failed []BatchResult
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i := range e.Failed {
+		msgs[i] = e.Failed[i].String()
+	}
+	return "jobs failed: " + strings.Join(msgs, "; ")
+}
+
+// NewBatch returns an empty batch of jobs.
+func (c *Conn) NewBatch() *Batch {
+	return &Batch{conn: c}
+}
+
+func (b *Batch) add(method, unit, mode string) *Batch {
+	b.ops = append(b.ops, batchOp{method: method, unit: unit, mode: mode})
+	return b
+}
+
+// Start adds a job starting unit, see StartUnitContext.
+func (b *Batch) Start(unit, mode string) *Batch {
+	return b.add("StartUnit", unit, mode)
+}
+
+// Stop adds a job stopping unit, see StopUnitContext.
+func (b *Batch) Stop(unit, mode string) *Batch {
+	return b.add("StopUnit", unit, mode)
+}
+
+// Restart adds a job restarting unit, see RestartUnitContext.
+func (b *Batch) Restart(unit, mode string) *Batch {
+	return b.add("RestartUnit", unit, mode)
+}
+
+// TryRestart adds a job restarting unit if it is running, see
+// TryRestartUnitContext.
+func (b *Batch) TryRestart(unit, mode string) *Batch {
+	return b.add("TryRestartUnit", unit, mode)
+}
+
+// Reload adds a job reloading unit, see ReloadUnitContext.
+func (b *Batch) Reload(unit, mode string) *Batch {
+	return b.add("ReloadUnit", unit, mode)
+}
+
+// ReloadOrRestart adds a job reloading unit if supported or restarting it
+// otherwise, see ReloadOrRestartUnitContext.
+func (b *Batch) ReloadOrRestart(unit, mode string) *Batch {
+	return b.add("ReloadOrRestartUnit", unit, mode)
+}
+
+// Run runs the jobs of the batch and waits for them to complete. It returns
+// the results of all jobs in the order they were added and a *BatchError if
+// any failed. If ctx is done while waiting, the jobs keep running and the
+// results not known yet are empty.
+func (b *Batch) Run(ctx context.Context) ([]BatchResult, error) {
+	results := make([]BatchResult, len(b.ops))
+	for i, op := range b.ops {
+		results[i] = BatchResult{Method: op.method, Unit: op.unit}
+	}
+
+	var err error
+	if b.FailFast {
+		err = b.runSequential(ctx, results)
+	} else {
+		err = b.runParallel(ctx, results)
+	}
+	if err != nil {
+		return results, err
+	}
+
+	var failed []BatchResult
+	for _, r := range results {
+		if r.failed() {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) > 0 {
+		return results, &BatchError{Failed: failed}
+	}
+	return results, nil
+}
+
+func (b *Batch) runSequential(ctx context.Context, results []BatchResult) error {
+	for i, op := range b.ops {
+		ch := make(chan string, 1)
+		results[i].JobID, results[i].Err = b.conn.startJob(ctx, ch, "org.freedesktop.systemd1.Manager."+op.method, op.unit, op.mode)
+		if results[i].Err == nil {
+			select {
+			case results[i].Result = <-ch:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if results[i].failed() {
+			for j := i + 1; j < len(results); j++ {
+				results[j].Result = "skipped"
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// runParallel sends all calls before waiting for their replies, saving the
+// round trips of enqueueing one job after another.
+func (b *Batch) runParallel(ctx context.Context, results []BatchResult) error {
+	c := b.conn
+	chans := make([]chan string, len(b.ops))
+
+	// The job listener stays locked until all jobs are registered, so
+	// none completes unnoticed.
+	c.jobListener.Lock()
+	calls := make([]*dbus.Call, len(b.ops))
+	for i, op := range b.ops {
+		calls[i] = c.sysobj.GoWithContext(ctx, "org.freedesktop.systemd1.Manager."+op.method, 0, make(chan *dbus.Call, 1), op.unit, op.mode)
+	}
+	for i, call := range calls {
+		<-call.Done
+		var p dbus.ObjectPath
+		if err := call.Store(&p); err != nil {
+			results[i].Err = err
+			continue
+		}
+		chans[i] = make(chan string, 1)
+		c.jobListener.jobs[p] = chans[i]
+		results[i].JobID, _ = strconv.Atoi(path.Base(string(p)))
+	}
+	c.jobListener.Unlock()
+
+	for i, ch := range chans {
+		if ch == nil {
+			continue
+		}
+		select {
+		case results[i].Result = <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
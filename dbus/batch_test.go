@@ -0,0 +1,68 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	for _, target := range []string{"start-stop.service", "start-failed.service"} {
+		setupUnit(target, conn, t)
+		linkUnit(target, conn, t)
+	}
+	defer conn.StopUnit("start-stop.service", "replace", nil)
+
+	results, err := conn.NewBatch().
+		Start("start-stop.service", "replace").
+		Start("start-failed.service", "replace").
+		Run(context.Background())
+	e, ok := err.(*BatchError)
+	if !ok || len(e.Failed) != 1 || e.Failed[0].Unit != "start-failed.service" {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if results[0].Result != "done" || results[0].JobID == 0 {
+		t.Errorf("unexpected result %+v", results[0])
+	}
+
+	b := conn.NewBatch().Start("start-failed.service", "replace").Stop("start-stop.service", "replace")
+	b.FailFast = true
+	results, err = b.Run(context.Background())
+	if err == nil {
+		t.Fatal("failing job did not fail the batch")
+	}
+	if results[1].Result != "skipped" || results[1].JobID != 0 {
+		t.Errorf("unexpected result %+v", results[1])
+	}
+	if u := getUnitStatusSingle(conn, "start-stop.service"); u == nil || u.ActiveState != "active" {
+		t.Errorf("skipped job was run")
+	}
+}
+
+func TestBatchError(t *testing.T) {
+	err := &BatchError{Failed: []BatchResult{
+		{Method: "StartUnit", Unit: "a.service", Result: "failed"},
+		{Method: "ReloadOrRestartUnit", Unit: "b.service", Err: errors.New("no such unit")},
+	}}
+	want := "jobs failed: StartUnit a.service: failed; ReloadOrRestartUnit b.service: no such unit"
+	if err.Error() != want {
+		t.Errorf("got %q, want %q", err.Error(), want)
+	}
+}
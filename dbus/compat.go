@@ -0,0 +1,174 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// The systemd versions that introduced the List* variants which filter on
+// the server side. With older versions the filtering is done by the client.
+const (
+	versionListUnitsFiltered       = 218
+	versionListUnitsByPatterns     = 230
+	versionListUnitsByNames        = 230
+	versionListUnitFilesByPatterns = 230
+)
+
+// SystemdVersion returns the major version of the service manager,
+// such as 219 for "219-78.el7". The version is cached by the connection.
+func (c *Conn) SystemdVersion(ctx context.Context) (int, error) {
+	c.version.Lock()
+	defer c.version.Unlock()
+	if c.version.n != 0 {
+		return c.version.n, nil
+	}
+
+	var v dbus.Variant
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.freedesktop.systemd1.Manager", "Version").Store(&v)
+	if err != nil {
+		return 0, err
+	}
+	s, _ := v.Value().(string)
+	n, err := parseSystemdVersion(s)
+	if err != nil {
+		return 0, err
+	}
+	c.version.n = n
+	return n, nil
+}
+
+// parseSystemdVersion returns the number leading a version string such as
+// "v252.4-2", "219-78.el7" or "255".
+func parseSystemdVersion(s string) (int, error) {
+	v := strings.TrimPrefix(s, "v")
+	i := 0
+	for i < len(v) && v[i] >= '0' && v[i] <= '9' {
+		i++
+	}
+	return strconv.Atoi(v[:i])
+}
+
+// serverSide returns whether the method added in systemd version min
+// should be called. If the version cannot be determined, it is called and
+// falls back to filtering on the client when the method is unknown.
+func (c *Conn) serverSide(ctx context.Context, min int) bool {
+	v, err := c.SystemdVersion(ctx)
+	return err != nil || v >= min
+}
+
+// isUnknownMethod returns whether err is the reply to a method the service
+// does not implement.
+func isUnknownMethod(err error) bool {
+	e, ok := err.(dbus.Error)
+	return ok && e.Name == "org.freedesktop.DBus.Error.UnknownMethod"
+}
+
+// matchPattern reports whether name matches one of patterns, or patterns is
+// empty, using shell globs like systemd with backslashes taken literally.
+func matchPattern(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.Replace(p, `\`, `\\`, -1), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// filterUnits returns the units whose load, active or sub state is one of
+// states and whose name matches one of patterns, where empty lists match
+// all units, as ListUnitsByPatterns does.
+func filterUnits(units []UnitStatus, states, patterns []string) []UnitStatus {
+	var out []UnitStatus
+	for _, u := range units {
+		if len(states) > 0 && !containsString(states, u.LoadState) && !containsString(states, u.ActiveState) && !containsString(states, u.SubState) {
+			continue
+		}
+		if !matchPattern(patterns, u.Name) {
+			continue
+		}
+		out = append(out, u)
+	}
+	return out
+}
+
+// filterUnitFiles returns the unit files whose enablement state is one of
+// states and whose name matches one of patterns, where empty lists match
+// all unit files, as ListUnitFilesByPatterns does.
+func filterUnitFiles(files []UnitFile, states, patterns []string) []UnitFile {
+	var out []UnitFile
+	for _, f := range files {
+		if len(states) > 0 && !containsString(states, f.Type) {
+			continue
+		}
+		if !matchPattern(patterns, path.Base(f.Path)) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// listUnitsByNamesFallback loads every unit and reads its state from its
+// properties, for managers without ListUnitsByNames.
+func (c *Conn) listUnitsByNamesFallback(ctx context.Context, units []string) ([]UnitStatus, error) {
+	status := make([]UnitStatus, 0, len(units))
+	for _, name := range units {
+		var p dbus.ObjectPath
+		if err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.LoadUnit", 0, name).Store(&p); err != nil {
+			return nil, err
+		}
+		props, err := c.getProperties(ctx, p, "org.freedesktop.systemd1.Unit")
+		if err != nil {
+			return nil, err
+		}
+		u := UnitStatus{Name: name, Path: p}
+		u.Description, _ = props["Description"].(string)
+		u.LoadState, _ = props["LoadState"].(string)
+		u.ActiveState, _ = props["ActiveState"].(string)
+		u.SubState, _ = props["SubState"].(string)
+		u.Followed, _ = props["Following"].(string)
+		if job, ok := props["Job"].([]interface{}); ok && len(job) == 2 {
+			u.JobId, _ = job[0].(uint32)
+			u.JobPath, _ = job[1].(dbus.ObjectPath)
+		}
+		if u.JobId != 0 {
+			var v dbus.Variant
+			obj := c.sysconn.Object("org.freedesktop.systemd1", u.JobPath)
+			if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, "org.freedesktop.systemd1.Job", "JobType").Store(&v); err == nil {
+				u.JobType, _ = v.Value().(string)
+			}
+		}
+		status = append(status, u)
+	}
+	return status, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSystemdVersion(t *testing.T) {
+	for i, tt := range []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"255", 255, true},
+		{"v252.4-2", 252, true},
+		{"219-78.el7_9.9", 219, true},
+		{"", 0, false},
+		{"unknown", 0, false},
+	} {
+		got, err := parseSystemdVersion(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("case %d: got %d, %v", i, got, err)
+		}
+	}
+}
+
+func TestFilterUnits(t *testing.T) {
+	units := []UnitStatus{
+		{Name: "sshd.service", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+		{Name: "dev-disk-by\\x2duuid.device", LoadState: "loaded", ActiveState: "active", SubState: "plugged"},
+		{Name: "cups.socket", LoadState: "loaded", ActiveState: "inactive", SubState: "dead"},
+		{Name: "gone.service", LoadState: "not-found", ActiveState: "inactive", SubState: "dead"},
+	}
+	names := func(units []UnitStatus) []string {
+		var out []string
+		for _, u := range units {
+			out = append(out, u.Name)
+		}
+		return out
+	}
+	for i, tt := range []struct {
+		states, patterns []string
+		want             []string
+	}{
+		{nil, nil, []string{"sshd.service", "dev-disk-by\\x2duuid.device", "cups.socket", "gone.service"}},
+		{[]string{"running"}, nil, []string{"sshd.service"}},
+		{[]string{"inactive"}, []string{"*.service"}, []string{"gone.service"}},
+		{[]string{"not-found", "active"}, []string{"*.service", "*.socket"}, []string{"sshd.service", "gone.service"}},
+		{nil, []string{"dev-disk-by\\x2d*"}, []string{"dev-disk-by\\x2duuid.device"}},
+		{nil, []string{"c?ps.[st]ocket"}, []string{"cups.socket"}},
+		{[]string{"failed"}, nil, nil},
+	} {
+		if got := names(filterUnits(units, tt.states, tt.patterns)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("case %d: got %q, want %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestFilterUnitFiles(t *testing.T) {
+	files := []UnitFile{
+		{Path: "/usr/lib/systemd/system/sshd.service", Type: "enabled"},
+		{Path: "/usr/lib/systemd/system/getty@.service", Type: "static"},
+		{Path: "/etc/systemd/system/cups.socket", Type: "masked"},
+	}
+	got := filterUnitFiles(files, []string{"enabled", "static"}, []string{"*@.service", "cups.*"})
+	if !reflect.DeepEqual(got, files[1:2]) {
+		t.Errorf("unexpected unit files %v", got)
+	}
+	if got := filterUnitFiles(files, nil, nil); !reflect.DeepEqual(got, files) {
+		t.Errorf("unexpected unit files %v", got)
+	}
+}
@@ -109,6 +109,10 @@ type Conn struct {
 		errCh    chan<- error
 		sync.Mutex
 	}
+	version struct {
+		n int // The major systemd version once known
+		sync.Mutex
+	}
 }
 
 // Deprecated: use NewWithContext instead.
@@ -0,0 +1,172 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// cgroupRoot is where the unified cgroup hierarchy is mounted.
+var cgroupRoot = "/sys/fs/cgroup"
+
+// delegateUnitTypes are the interfaces of the unit types having a control
+// group, by unit name suffix.
+var delegateUnitTypes = map[string]string{
+	".service": "Service",
+	".scope":   "Scope",
+	".slice":   "Slice",
+	".socket":  "Socket",
+	".mount":   "Mount",
+	".swap":    "Swap",
+}
+
+// Delegation describes the cgroup controllers systemd delegates to a unit,
+// see https://systemd.io/CGROUP_DELEGATION/
+type Delegation struct {
+	Unit string
+	// Delegate is set if Delegate= is enabled for the unit, which leaves the
+	// cgroups below the unit's to its processes.
+	Delegate bool
+	// Requested are the controllers of DelegateControllers=, all controllers
+	// systemd supports if Delegate=yes was given without a list.
+	Requested []string
+	// Subgroup is the cgroup of DelegateSubgroup= below the unit's cgroup
+	// that the unit's processes are started in, if any.
+	// Note: Requires systemd v254 or higher
+	Subgroup string
+	// ControlGroup is the unit's cgroup below the cgroup root, empty if the
+	// unit is not running.
+	ControlGroup string
+	// Available are the controllers the kernel makes available to the
+	// unit's cgroup, or to the manager's root cgroup if the unit is not
+	// running. It is nil if the hierarchy is not the unified one, where
+	// controllers cannot be delegated safely.
+	Available []string
+}
+
+// Manageable returns the controllers the unit's processes can safely manage
+// themselves: those delegated to the unit that are also available, in the
+// order of Requested. It is empty unless delegation is enabled.
+func (d *Delegation) Manageable() []string {
+	if !d.Delegate {
+		return nil
+	}
+	var controllers []string
+	for _, c := range d.Requested {
+		for _, a := range d.Available {
+			if c == a {
+				controllers = append(controllers, c)
+				break
+			}
+		}
+	}
+	return controllers
+}
+
+// Missing returns the controllers requested for the unit that the kernel
+// does not make available to it, such as controllers bound to the legacy
+// hierarchy or not enabled by the parent slice.
+func (d *Delegation) Missing() []string {
+	if !d.Delegate {
+		return nil
+	}
+	manageable := d.Manageable()
+	var controllers []string
+	for _, c := range d.Requested {
+		found := false
+		for _, m := range manageable {
+			found = found || c == m
+		}
+		if !found {
+			controllers = append(controllers, c)
+		}
+	}
+	return controllers
+}
+
+// GetDelegationContext returns the cgroup delegation settings of a unit and
+// the controllers available to it, so that container runtimes and other
+// services managing cgroups themselves can decide which of them to use. The
+// unit must be of a type with a cgroup, such as a service or scope.
+func (c *Conn) GetDelegationContext(ctx context.Context, unit string) (*Delegation, error) {
+	iface, ok := delegateUnitTypes[filepath.Ext(unit)]
+	if !ok {
+		return nil, fmt.Errorf("unit %s has no control group", unit)
+	}
+	props, err := c.GetUnitTypePropertiesContext(ctx, unit, iface)
+	if err != nil {
+		return nil, err
+	}
+	return delegationFromProperties(unit, props)
+}
+
+func delegationFromProperties(unit string, props map[string]interface{}) (*Delegation, error) {
+	d := &Delegation{Unit: unit}
+	d.Delegate, _ = props["Delegate"].(bool)
+	d.Requested, _ = props["DelegateControllers"].([]string)
+	d.Subgroup, _ = props["DelegateSubgroup"].(string)
+	d.ControlGroup, _ = props["ControlGroup"].(string)
+
+	dir := cgroupRoot
+	if d.ControlGroup != "" {
+		dir = filepath.Join(cgroupRoot, d.ControlGroup)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, "cgroup.controllers"))
+	if os.IsNotExist(err) && d.ControlGroup != "" {
+		// The unit stopped since its properties were read.
+		d.ControlGroup = ""
+		data, err = ioutil.ReadFile(filepath.Join(cgroupRoot, "cgroup.controllers"))
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		d.Available = strings.Fields(string(data))
+		if d.Available == nil {
+			d.Available = []string{}
+		}
+	}
+	return d, nil
+}
+
+// PropDelegate sets the Delegate property, which leaves the cgroups below
+// the unit's to its processes. See
+// https://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#Delegate=
+func PropDelegate(b bool) Property {
+	return Property{
+		Name:  "Delegate",
+		Value: dbus.MakeVariant(b),
+	}
+}
+
+// PropDelegateControllers enables delegation limited to the given
+// controllers. See
+// https://www.freedesktop.org/software/systemd/man/systemd.resource-control.html#Delegate=
+func PropDelegateControllers(controllers ...string) Property {
+	if controllers == nil {
+		controllers = []string{}
+	}
+	return Property{
+		Name:  "DelegateControllers",
+		Value: dbus.MakeVariant(controllers),
+	}
+}
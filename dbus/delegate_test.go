@@ -0,0 +1,135 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDelegationFromProperties(t *testing.T) {
+	root, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	defer func(prev string) { cgroupRoot = prev }(cgroupRoot)
+	cgroupRoot = root
+
+	unitDir := filepath.Join(root, "system.slice", "runtime.service")
+	if err := os.MkdirAll(unitDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpuset cpu io memory hugetlb pids rdma misc\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(unitDir, "cgroup.controllers"), []byte("cpu memory pids\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tt := range []struct {
+		props      map[string]interface{}
+		manageable []string
+		missing    []string
+		cgroup     string
+	}{
+		{
+			map[string]interface{}{"Delegate": true, "DelegateControllers": []string{"cpu", "io", "memory", "pids"}, "ControlGroup": "/system.slice/runtime.service"},
+			[]string{"cpu", "memory", "pids"},
+			[]string{"io"},
+			"/system.slice/runtime.service",
+		},
+		{
+			map[string]interface{}{"Delegate": true, "DelegateControllers": []string{"cpu", "io"}, "ControlGroup": ""},
+			[]string{"cpu", "io"},
+			nil,
+			"",
+		},
+		{
+			// The unit stopped after its properties were read.
+			map[string]interface{}{"Delegate": true, "DelegateControllers": []string{"rdma"}, "ControlGroup": "/system.slice/gone.service"},
+			[]string{"rdma"},
+			nil,
+			"",
+		},
+		{
+			map[string]interface{}{"Delegate": false, "DelegateControllers": []string{}, "ControlGroup": "/system.slice/runtime.service"},
+			nil,
+			nil,
+			"/system.slice/runtime.service",
+		},
+	} {
+		d, err := delegationFromProperties("runtime.service", tt.props)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+			continue
+		}
+		if m := d.Manageable(); !reflect.DeepEqual(m, tt.manageable) {
+			t.Errorf("case %d: got manageable %v, want %v", i, m, tt.manageable)
+		}
+		if m := d.Missing(); !reflect.DeepEqual(m, tt.missing) {
+			t.Errorf("case %d: got missing %v, want %v", i, m, tt.missing)
+		}
+		if d.ControlGroup != tt.cgroup {
+			t.Errorf("case %d: got cgroup %q, want %q", i, d.ControlGroup, tt.cgroup)
+		}
+	}
+
+	// Without the unified hierarchy nothing is available.
+	cgroupRoot = filepath.Join(root, "legacy")
+	d, err := delegationFromProperties("runtime.service", map[string]interface{}{"Delegate": true, "DelegateControllers": []string{"cpu"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Available != nil || d.Manageable() != nil || !reflect.DeepEqual(d.Missing(), []string{"cpu"}) {
+		t.Errorf("unexpected delegation %+v", d)
+	}
+}
+
+func TestGetDelegation(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	ctx := context.Background()
+	const target = "testing-transient-delegate.service"
+	props := []Property{
+		PropExecStart([]string{"/bin/sleep", "60"}, false),
+		PropDelegateControllers("pids", "memory"),
+	}
+	reschan := make(chan string)
+	if _, err := conn.StartTransientUnitContext(ctx, target, "replace", props, reschan); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.StopUnitContext(ctx, target, "replace", nil)
+	if job := <-reschan; job != "done" {
+		t.Fatalf("job is not done: %s", job)
+	}
+
+	d, err := conn.GetDelegationContext(ctx, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Delegate || !reflect.DeepEqual(d.Requested, []string{"memory", "pids"}) || d.ControlGroup == "" {
+		t.Errorf("unexpected delegation %+v", d)
+	}
+
+	if _, err := conn.GetDelegationContext(ctx, "timers.target"); err == nil {
+		t.Error("expected error for unit without control group")
+	}
+}
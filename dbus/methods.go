@@ -18,9 +18,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"path"
 	"strconv"
+	"time"
 
+	"github.com/coreos/go-systemd/v22/id128"
 	"github.com/godbus/dbus/v5"
 )
 
@@ -440,6 +443,56 @@ func (c *Conn) GetUnitNameByPID(ctx context.Context, pid uint32) (string, error)
 	return unitName(path), nil
 }
 
+// GetUnitByInvocationID returns the unit object path of the unit with the
+// given invocation ID, which changes with every start of a unit.
+func (c *Conn) GetUnitByInvocationID(ctx context.Context, id id128.ID) (dbus.ObjectPath, error) {
+	var result dbus.ObjectPath
+
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.GetUnitByInvocationID", 0, id[:]).Store(&result)
+
+	return result, err
+}
+
+// GetUnitNameByInvocationID returns the name of the unit with the given
+// invocation ID.
+func (c *Conn) GetUnitNameByInvocationID(ctx context.Context, id id128.ID) (string, error) {
+	path, err := c.GetUnitByInvocationID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return unitName(path), nil
+}
+
+// GetUnitByControlGroup returns the unit object path of the unit owning a
+// control group, given by its path relative to the cgroup root such as
+// /system.slice/sshd.service. Control groups below a unit's one, such as
+// those it delegates, belong to the unit.
+func (c *Conn) GetUnitByControlGroup(ctx context.Context, cgroup string) (dbus.ObjectPath, error) {
+	var result dbus.ObjectPath
+
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.GetUnitByControlGroup", 0, cgroup).Store(&result)
+
+	return result, err
+}
+
+// GetUnitNameByControlGroup returns the name of the unit owning a control
+// group.
+func (c *Conn) GetUnitNameByControlGroup(ctx context.Context, cgroup string) (string, error) {
+	path, err := c.GetUnitByControlGroup(ctx, cgroup)
+	if err != nil {
+		return "", err
+	}
+
+	return unitName(path), nil
+}
+
+// GetOwnUnitName returns the name of the unit the calling process belongs
+// to, such as the service it runs as, to tag data with its origin.
+func (c *Conn) GetOwnUnitName(ctx context.Context) (string, error) {
+	return c.GetUnitNameByPID(ctx, uint32(os.Getpid()))
+}
+
 // Deprecated: use ListUnitsContext instead.
 func (c *Conn) ListUnits() ([]UnitStatus, error) {
 	return c.ListUnitsContext(context.Background())
@@ -461,8 +514,20 @@ func (c *Conn) ListUnitsFiltered(states []string) ([]UnitStatus, error) {
 
 // ListUnitsFilteredContext returns an array with units filtered by state.
 // It takes a list of units' statuses to filter.
+//
+// With systemd versions before v218 the units are filtered by the client.
 func (c *Conn) ListUnitsFilteredContext(ctx context.Context, states []string) ([]UnitStatus, error) {
-	return c.listUnitsInternal(c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnitsFiltered", 0, states).Store)
+	if c.serverSide(ctx, versionListUnitsFiltered) {
+		units, err := c.listUnitsInternal(c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnitsFiltered", 0, states).Store)
+		if !isUnknownMethod(err) {
+			return units, err
+		}
+	}
+	units, err := c.ListUnitsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterUnits(units, states, nil), nil
 }
 
 // Deprecated: use ListUnitsByPatternsContext instead.
@@ -474,8 +539,20 @@ func (c *Conn) ListUnitsByPatterns(states []string, patterns []string) ([]UnitSt
 // It takes a list of units' statuses and names to filter.
 // Note that units may be known by multiple names at the same time,
 // and hence there might be more unit names loaded than actual units behind them.
+//
+// With systemd versions before v230 the units are filtered by the client.
 func (c *Conn) ListUnitsByPatternsContext(ctx context.Context, states []string, patterns []string) ([]UnitStatus, error) {
-	return c.listUnitsInternal(c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnitsByPatterns", 0, states, patterns).Store)
+	if c.serverSide(ctx, versionListUnitsByPatterns) {
+		units, err := c.listUnitsInternal(c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnitsByPatterns", 0, states, patterns).Store)
+		if !isUnknownMethod(err) {
+			return units, err
+		}
+	}
+	units, err := c.ListUnitsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterUnits(units, states, patterns), nil
 }
 
 // Deprecated: use ListUnitsByNamesContext instead.
@@ -488,9 +565,16 @@ func (c *Conn) ListUnitsByNames(units []string) ([]UnitStatus, error) {
 // method, this method returns statuses even for inactive or non-existing
 // units. Input array should contain exact unit names, but not patterns.
 //
-// Requires systemd v230 or higher.
+// With systemd versions before v230 every unit is loaded and its state is
+// read from its properties, which takes several calls per unit.
 func (c *Conn) ListUnitsByNamesContext(ctx context.Context, units []string) ([]UnitStatus, error) {
-	return c.listUnitsInternal(c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnitsByNames", 0, units).Store)
+	if c.serverSide(ctx, versionListUnitsByNames) {
+		status, err := c.listUnitsInternal(c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnitsByNames", 0, units).Store)
+		if !isUnknownMethod(err) {
+			return status, err
+		}
+	}
+	return c.listUnitsByNamesFallback(ctx, units)
 }
 
 type UnitFile struct {
@@ -540,8 +624,19 @@ func (c *Conn) ListUnitFilesByPatterns(states []string, patterns []string) ([]Un
 }
 
 // ListUnitFilesByPatternsContext returns an array of all available units on disk matched the patterns.
+// With systemd versions before v230 the unit files are filtered by the client.
 func (c *Conn) ListUnitFilesByPatternsContext(ctx context.Context, states []string, patterns []string) ([]UnitFile, error) {
-	return c.listUnitFilesInternal(c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnitFilesByPatterns", 0, states, patterns).Store)
+	if c.serverSide(ctx, versionListUnitFilesByPatterns) {
+		files, err := c.listUnitFilesInternal(c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ListUnitFilesByPatterns", 0, states, patterns).Store)
+		if !isUnknownMethod(err) {
+			return files, err
+		}
+	}
+	files, err := c.ListUnitFilesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return filterUnitFiles(files, states, patterns), nil
 }
 
 type LinkUnitFileChange EnableUnitFileChange
@@ -862,3 +957,57 @@ func (c *Conn) FreezeUnit(ctx context.Context, unit string) error {
 func (c *Conn) ThawUnit(ctx context.Context, unit string) error {
 	return c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.ThawUnit", 0, unit).Store()
 }
+
+// EnqueueMarkedJobs enqueues restart or reload jobs for all units that carry
+// the needs-restart or needs-reload marker (see PropMarkers), clearing the
+// markers in the process. It returns the object paths of the enqueued jobs.
+// This is the equivalent of systemctl reload-or-restart --marked.
+//
+// Requires systemd v246 or higher.
+func (c *Conn) EnqueueMarkedJobs(ctx context.Context) ([]dbus.ObjectPath, error) {
+	var jobs []dbus.ObjectPath
+	err := c.sysobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.EnqueueMarkedJobs", 0).Store(&jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// SetUnitMarkers sets the Markers property of the unit at runtime. Markers
+// prefixed with "+" are added to and markers prefixed with "-" are removed
+// from the current set, otherwise the current set is replaced.
+// See PropMarkers for the recognized marker names.
+func (c *Conn) SetUnitMarkers(ctx context.Context, unit string, markers ...string) error {
+	return c.SetUnitPropertiesContext(ctx, unit, true, PropMarkers(markers...))
+}
+
+func (c *Conn) setManagerProperty(ctx context.Context, propertyName string, value interface{}) error {
+	return c.sysobj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Set", 0, "org.freedesktop.systemd1.Manager", propertyName, dbus.MakeVariant(value)).Store()
+}
+
+// SetServiceWatchdogs enables or disables the service runtime watchdogs
+// (WatchdogSec=) of all units at once, without changing their configuration.
+// This is the equivalent of systemctl service-watchdogs.
+func (c *Conn) SetServiceWatchdogs(ctx context.Context, enabled bool) error {
+	return c.setManagerProperty(ctx, "ServiceWatchdogs", enabled)
+}
+
+// SetRuntimeWatchdog changes the timeout of the hardware watchdog systemd
+// pings while the system is running (RuntimeWatchdogSec=). A zero duration
+// disables the watchdog.
+func (c *Conn) SetRuntimeWatchdog(ctx context.Context, timeout time.Duration) error {
+	return c.setManagerProperty(ctx, "RuntimeWatchdogUSec", uint64(timeout/time.Microsecond))
+}
+
+// SetRebootWatchdog changes the hardware watchdog timeout used during
+// reboot (RebootWatchdogSec=). A zero duration disables the watchdog.
+func (c *Conn) SetRebootWatchdog(ctx context.Context, timeout time.Duration) error {
+	return c.setManagerProperty(ctx, "RebootWatchdogUSec", uint64(timeout/time.Microsecond))
+}
+
+// SetKExecWatchdog changes the hardware watchdog timeout used during kexec
+// (KExecWatchdogSec=). A zero duration disables the watchdog.
+func (c *Conn) SetKExecWatchdog(ctx context.Context, timeout time.Duration) error {
+	return c.setManagerProperty(ctx, "KExecWatchdogUSec", uint64(timeout/time.Microsecond))
+}
@@ -26,6 +26,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/coreos/go-systemd/v22/id128"
 	"github.com/godbus/dbus/v5"
 )
 
@@ -482,6 +483,63 @@ func TestGetUnitNameByPID(t *testing.T) {
 	}
 }
 
+// Ensure that GetUnitNameByControlGroup works.
+func TestGetUnitNameByControlGroup(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	name, err := conn.GetUnitNameByControlGroup(context.Background(), "/init.scope")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name != "init.scope" {
+		t.Fatalf("unexpected unit %q", name)
+	}
+}
+
+// Ensure that GetUnitNameByInvocationID works.
+func TestGetUnitNameByInvocationID(t *testing.T) {
+	target := "systemd-journald.service"
+	conn := setupConn(t)
+	defer conn.Close()
+
+	prop, err := conn.GetUnitPropertyContext(context.Background(), target, "InvocationID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := prop.Value.Value().([]byte)
+	var id id128.ID
+	if len(b) != len(id) {
+		t.Skipf("%s has no invocation ID", target)
+	}
+	copy(id[:], b)
+
+	name, err := conn.GetUnitNameByInvocationID(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name != target {
+		t.Fatalf("unexpected unit %q", name)
+	}
+}
+
+// Ensure that GetOwnUnitName works.
+func TestGetOwnUnitName(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	name, err := conn.GetOwnUnitName(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if name == "" {
+		t.Fatal("name is empty")
+	}
+}
+
 // Ensure that ListUnitsByNames works.
 func TestListUnitsByNames(t *testing.T) {
 	target1 := "systemd-journald.service"
@@ -1689,3 +1747,58 @@ func TestFreezer(t *testing.T) {
 
 	runStopUnit(t, conn, TrUnitProp{target, nil})
 }
+
+func TestEnqueueMarkedJobs(t *testing.T) {
+	target := "start-stop.service"
+	conn := setupConn(t)
+	defer conn.Close()
+
+	setupUnit(target, conn, t)
+	linkUnit(target, conn, t)
+
+	reschan := make(chan string)
+	_, err := conn.StartUnit(target, "replace", reschan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := <-reschan
+	if job != "done" {
+		t.Fatal("Job is not done:", job)
+	}
+
+	if err := conn.SetUnitMarkers(context.Background(), target, MarkerNeedsRestart); err != nil {
+		// Markers are only supported since systemd v246.
+		e, ok := err.(dbus.Error)
+		if ok && e.Name == "org.freedesktop.DBus.Error.PropertyReadOnly" {
+			t.SkipNow()
+		}
+		t.Fatalf("failed to mark unit %s: %s", target, err)
+	}
+
+	p, err := conn.GetUnitProperty(target, "Markers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := p.Value.Value().([]string); len(m) != 1 || m[0] != MarkerNeedsRestart {
+		t.Fatalf("unexpected Markers after SetUnitMarkers(): %v", m)
+	}
+
+	jobs, err := conn.EnqueueMarkedJobs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) == 0 {
+		t.Fatal("expected at least one job to be enqueued")
+	}
+
+	p, err = conn.GetUnitProperty(target, "Markers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := p.Value.Value().([]string); len(m) != 0 {
+		t.Fatalf("expected Markers to be cleared, got %v", m)
+	}
+
+	runStopUnit(t, conn, TrUnitProp{target, nil})
+}
@@ -0,0 +1,295 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics exports the state of systemd units in the Prometheus text
+// exposition format. The state is kept current from the unit property change
+// signals of a dbus connection instead of polling systemd. The package does
+// not depend on the Prometheus client library: Collector is an http.Handler
+// that can be scraped directly, and Collect returns the metric families for
+// adapting them to other registries. The separate
+// github.com/coreos/go-systemd/v22/dbus/metrics/prometheus module provides a
+// prometheus.Collector on top of it.
+//
+// See https://prometheus.io/docs/instrumenting/exposition_formats/
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	sd_dbus "github.com/coreos/go-systemd/v22/dbus"
+)
+
+// unitStates are the active states each unit has a state series for, others
+// get one while a unit is in them.
+var unitStates = []string{"active", "reloading", "inactive", "failed", "activating", "deactivating"}
+
+// updateBuffer is the size of the channel for property updates, a full
+// channel makes the collector reload all units.
+const updateBuffer = 1024
+
+// Collector tracks the active state of units and restart count of services.
+type Collector struct {
+	conn   *sd_dbus.Conn
+	filter func(string) bool
+
+	mu    sync.Mutex
+	units map[string]*unit
+}
+
+type unit struct {
+	activeState string
+	restarts    uint32
+	hasRestarts bool // NRestarts is only known for services with systemd 235 or newer
+}
+
+// NewCollector returns a collector for the units of conn. If filter is not
+// nil, only units it returns true for are tracked. The collector sets the
+// properties subscriber of conn while it runs, so conn should not be used
+// with SetPropertiesSubscriber otherwise.
+func NewCollector(conn *sd_dbus.Conn, filter func(name string) bool) *Collector {
+	return &Collector{
+		conn:   conn,
+		filter: filter,
+		units:  make(map[string]*unit),
+	}
+}
+
+// Run loads the currently known units and then follows their changes until
+// ctx is done, which is the only error it returns unless loading fails. If
+// updates are dropped because they come faster than they are processed, all
+// units are loaded again.
+func (c *Collector) Run(ctx context.Context) error {
+	updates := make(chan *sd_dbus.PropertiesUpdate, updateBuffer)
+	errs := make(chan error, 1)
+	if err := c.conn.Subscribe(); err != nil {
+		return err
+	}
+	c.conn.SetPropertiesSubscriber(updates, errs)
+	defer c.conn.SetPropertiesSubscriber(nil, nil)
+
+	if err := c.load(ctx); err != nil {
+		return err
+	}
+	for {
+		select {
+		case u := <-updates:
+			c.update(ctx, u)
+		case <-errs:
+			if err := c.load(ctx); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// load replaces the tracked units by the ones currently known to systemd.
+func (c *Collector) load(ctx context.Context) error {
+	statuses, err := c.conn.ListUnitsContext(ctx)
+	if err != nil {
+		return err
+	}
+	units := make(map[string]*unit, len(statuses))
+	for _, s := range statuses {
+		if c.filter != nil && !c.filter(s.Name) {
+			continue
+		}
+		u := &unit{activeState: s.ActiveState}
+		c.loadRestarts(ctx, s.Name, u)
+		units[s.Name] = u
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.units = units
+	return nil
+}
+
+// loadRestarts sets the restart count of u if it is a service. Restarts are
+// not announced by property changes of the unit, so they are queried after
+// each change of its active state.
+func (c *Collector) loadRestarts(ctx context.Context, name string, u *unit) {
+	if !strings.HasSuffix(name, ".service") {
+		return
+	}
+	p, err := c.conn.GetUnitTypePropertyContext(ctx, name, "Service", "NRestarts")
+	if err != nil {
+		return
+	}
+	if n, ok := p.Value.Value().(uint32); ok {
+		u.restarts, u.hasRestarts = n, true
+	}
+}
+
+func (c *Collector) update(ctx context.Context, p *sd_dbus.PropertiesUpdate) {
+	if c.filter != nil && !c.filter(p.UnitName) {
+		return
+	}
+	v, ok := p.Changed["ActiveState"]
+	if !ok {
+		return
+	}
+	state, ok := v.Value().(string)
+	if !ok {
+		return
+	}
+	u := &unit{activeState: state}
+	c.loadRestarts(ctx, p.UnitName, u)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.units[p.UnitName] = u
+}
+
+// Family is a named group of metrics of the same type.
+type Family struct {
+	Name    string
+	Help    string
+	Type    string // "gauge" or "counter"
+	Metrics []Metric
+}
+
+// Metric is a single sample of a family.
+type Metric struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Collect returns the current metrics, which are
+//
+//	systemd_unit_state{name, type, state}: 1 for the active state of a
+//	    unit, 0 for the other states
+//	systemd_service_restarts_total{name}: the NRestarts of a service
+//	systemd_units_failed: the number of failed units
+func (c *Collector) Collect() []Family {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.units))
+	for name := range c.units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	state := Family{
+		Name: "systemd_unit_state",
+		Help: "Systemd unit active state.",
+		Type: "gauge",
+	}
+	restarts := Family{
+		Name: "systemd_service_restarts_total",
+		Help: "Service restarts triggered by the Restart= setting.",
+		Type: "counter",
+	}
+	var failed int
+	for _, name := range names {
+		u := c.units[name]
+		typ := name[strings.LastIndexByte(name, '.')+1:]
+		states := unitStates
+		if !contains(states, u.activeState) {
+			states = append(states[:len(states):len(states)], u.activeState)
+		}
+		for _, s := range states {
+			var v float64
+			if s == u.activeState {
+				v = 1
+			}
+			state.Metrics = append(state.Metrics, Metric{
+				Labels: map[string]string{"name": name, "type": typ, "state": s},
+				Value:  v,
+			})
+		}
+		if u.hasRestarts {
+			restarts.Metrics = append(restarts.Metrics, Metric{
+				Labels: map[string]string{"name": name},
+				Value:  float64(u.restarts),
+			})
+		}
+		if u.activeState == "failed" {
+			failed++
+		}
+	}
+
+	return []Family{state, restarts, {
+		Name:    "systemd_units_failed",
+		Help:    "Number of units in the failed state.",
+		Type:    "gauge",
+		Metrics: []Metric{{Value: float64(failed)}},
+	}}
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteTo writes the current metrics to w in the text exposition format.
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, f := range c.Collect() {
+		if len(f.Metrics) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.Name, helpEscaper.Replace(f.Help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.Name, f.Type)
+		for _, m := range f.Metrics {
+			b.WriteString(f.Name)
+			writeLabels(&b, m.Labels)
+			fmt.Fprintf(&b, " %g\n", m.Value)
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func writeLabels(b *strings.Builder, labels map[string]string) {
+	if len(labels) == 0 {
+		return
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(b, "%s=\"%s\"", name, labelEscaper.Replace(labels[name]))
+	}
+	b.WriteByte('}')
+}
+
+// ServeHTTP serves the current metrics for scraping by Prometheus.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
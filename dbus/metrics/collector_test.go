@@ -0,0 +1,133 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sd_dbus "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/godbus/dbus/v5"
+)
+
+func TestWriteTo(t *testing.T) {
+	c := NewCollector(nil, func(name string) bool { return !strings.HasPrefix(name, "ignored") })
+	c.units = map[string]*unit{
+		"b.service": {activeState: "failed", restarts: 3, hasRestarts: true},
+		"a.socket":  {activeState: "maintenance"},
+		"c.mount":   {activeState: "refreshing"},
+	}
+	c.update(context.Background(), &sd_dbus.PropertiesUpdate{
+		UnitName: "a.socket",
+		Changed:  map[string]dbus.Variant{"ActiveState": dbus.MakeVariant("active")},
+	})
+	c.update(context.Background(), &sd_dbus.PropertiesUpdate{
+		UnitName: "ignored.socket",
+		Changed:  map[string]dbus.Variant{"ActiveState": dbus.MakeVariant("active")},
+	})
+
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	want := `# HELP systemd_unit_state Systemd unit active state.
+# TYPE systemd_unit_state gauge
+systemd_unit_state{name="a.socket",state="active",type="socket"} 1
+systemd_unit_state{name="a.socket",state="reloading",type="socket"} 0
+systemd_unit_state{name="a.socket",state="inactive",type="socket"} 0
+systemd_unit_state{name="a.socket",state="failed",type="socket"} 0
+systemd_unit_state{name="a.socket",state="activating",type="socket"} 0
+systemd_unit_state{name="a.socket",state="deactivating",type="socket"} 0
+systemd_unit_state{name="b.service",state="active",type="service"} 0
+systemd_unit_state{name="b.service",state="reloading",type="service"} 0
+systemd_unit_state{name="b.service",state="inactive",type="service"} 0
+systemd_unit_state{name="b.service",state="failed",type="service"} 1
+systemd_unit_state{name="b.service",state="activating",type="service"} 0
+systemd_unit_state{name="b.service",state="deactivating",type="service"} 0
+systemd_unit_state{name="c.mount",state="active",type="mount"} 0
+systemd_unit_state{name="c.mount",state="reloading",type="mount"} 0
+systemd_unit_state{name="c.mount",state="inactive",type="mount"} 0
+systemd_unit_state{name="c.mount",state="failed",type="mount"} 0
+systemd_unit_state{name="c.mount",state="activating",type="mount"} 0
+systemd_unit_state{name="c.mount",state="deactivating",type="mount"} 0
+systemd_unit_state{name="c.mount",state="refreshing",type="mount"} 1
+# HELP systemd_service_restarts_total Service restarts triggered by the Restart= setting.
+# TYPE systemd_service_restarts_total counter
+systemd_service_restarts_total{name="b.service"} 3
+# HELP systemd_units_failed Number of units in the failed state.
+# TYPE systemd_units_failed gauge
+systemd_units_failed 1
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type %q", ct)
+	}
+}
+
+func TestWriteLabels(t *testing.T) {
+	for i, tt := range []struct {
+		labels map[string]string
+		want   string
+	}{
+		{nil, ""},
+		{map[string]string{"name": `a"b\c` + "\n"}, `{name="a\"b\\c\n"}`},
+		{map[string]string{"b": "2", "a": "1"}, `{a="1",b="2"}`},
+	} {
+		var b strings.Builder
+		writeLabels(&b, tt.labels)
+		if b.String() != tt.want {
+			t.Errorf("case %d: got %s", i, b.String())
+		}
+	}
+}
+
+func TestCollectorRun(t *testing.T) {
+	conn, err := sd_dbus.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	c := NewCollector(conn, func(name string) bool { return strings.HasSuffix(name, ".target") })
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- c.Run(ctx)
+	}()
+
+	for {
+		if f := c.Collect(); len(f[0].Metrics) > 0 {
+			for _, m := range f[0].Metrics {
+				if !strings.HasSuffix(m.Labels["name"], ".target") {
+					t.Errorf("unit %s not filtered", m.Labels["name"])
+				}
+			}
+			break
+		}
+		select {
+		case err := <-done:
+			t.Fatal(err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/coreos/go-systemd/v22/dbus/metrics/prometheus
+
+go 1.13
+
+require (
+	github.com/coreos/go-systemd/v22 v22.5.0
+	github.com/prometheus/client_golang v1.11.1
+)
+
+replace github.com/coreos/go-systemd/v22 => ../../..
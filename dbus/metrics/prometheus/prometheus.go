@@ -0,0 +1,90 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package prometheus registers the unit metrics of a metrics.Collector with
+// the Prometheus client library. It is a separate module, so that the
+// go-systemd module itself does not depend on the client library.
+package prometheus
+
+import (
+	"sort"
+
+	"github.com/coreos/go-systemd/v22/dbus/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	unitStateDesc = prometheus.NewDesc("systemd_unit_state",
+		"Systemd unit active state.", []string{"name", "type", "state"}, nil)
+	serviceRestartsDesc = prometheus.NewDesc("systemd_service_restarts_total",
+		"Service restarts triggered by the Restart= setting.", []string{"name"}, nil)
+	unitsFailedDesc = prometheus.NewDesc("systemd_units_failed",
+		"Number of units in the failed state.", nil, nil)
+)
+
+// descs maps the families returned by metrics.Collector.Collect to their
+// descriptions and variable labels.
+var descs = map[string]struct {
+	desc   *prometheus.Desc
+	labels []string
+	typ    prometheus.ValueType
+}{
+	"systemd_unit_state":             {unitStateDesc, []string{"name", "type", "state"}, prometheus.GaugeValue},
+	"systemd_service_restarts_total": {serviceRestartsDesc, []string{"name"}, prometheus.CounterValue},
+	"systemd_units_failed":           {unitsFailedDesc, nil, prometheus.GaugeValue},
+}
+
+// Collector is a prometheus.Collector for the metrics of a
+// metrics.Collector, which must be running to keep them current.
+type Collector struct {
+	c *metrics.Collector
+}
+
+// NewCollector returns a prometheus.Collector for c.
+func NewCollector(c *metrics.Collector) *Collector {
+	return &Collector{c: c}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	names := make([]string, 0, len(descs))
+	for name := range descs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ch <- descs[name].desc
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	for _, f := range c.c.Collect() {
+		d, ok := descs[f.Name]
+		if !ok {
+			continue
+		}
+		for _, m := range f.Metrics {
+			values := make([]string, len(d.labels))
+			for i, l := range d.labels {
+				values[i] = m.Labels[l]
+			}
+			metric, err := prometheus.NewConstMetric(d.desc, d.typ, m.Value, values...)
+			if err != nil {
+				metric = prometheus.NewInvalidMetric(d.desc, err)
+			}
+			ch <- metric
+		}
+	}
+}
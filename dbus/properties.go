@@ -235,3 +235,21 @@ func PropPids(pids ...uint32) Property {
 		Value: dbus.MakeVariant(pids),
 	}
 }
+
+const (
+	// MarkerNeedsRestart marks a unit to be restarted by EnqueueMarkedJobs.
+	MarkerNeedsRestart = "needs-restart"
+	// MarkerNeedsReload marks a unit to be reloaded by EnqueueMarkedJobs.
+	MarkerNeedsReload = "needs-reload"
+)
+
+// PropMarkers sets the Markers unit property. Each marker may be prefixed
+// with "+" or "-" to add it to or remove it from the current set, in which
+// case the set is not replaced. See
+// https://www.freedesktop.org/software/systemd/man/org.freedesktop.systemd1.html#Properties1
+func PropMarkers(markers ...string) Property {
+	return Property{
+		Name:  "Markers",
+		Value: dbus.MakeVariant(markers),
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// ProtectSystem is a value of the ProtectSystem service property.
+type ProtectSystem string
+
+const (
+	ProtectSystemNo     ProtectSystem = "no"
+	ProtectSystemYes    ProtectSystem = "yes"    // /usr and /boot are read-only
+	ProtectSystemFull   ProtectSystem = "full"   // /etc is read-only as well
+	ProtectSystemStrict ProtectSystem = "strict" // the whole file system is read-only
+)
+
+// capabilities are the Linux capabilities by their number.
+var capabilities = []string{
+	"CAP_CHOWN", "CAP_DAC_OVERRIDE", "CAP_DAC_READ_SEARCH", "CAP_FOWNER",
+	"CAP_FSETID", "CAP_KILL", "CAP_SETGID", "CAP_SETUID", "CAP_SETPCAP",
+	"CAP_LINUX_IMMUTABLE", "CAP_NET_BIND_SERVICE", "CAP_NET_BROADCAST",
+	"CAP_NET_ADMIN", "CAP_NET_RAW", "CAP_IPC_LOCK", "CAP_IPC_OWNER",
+	"CAP_SYS_MODULE", "CAP_SYS_RAWIO", "CAP_SYS_CHROOT", "CAP_SYS_PTRACE",
+	"CAP_SYS_PACCT", "CAP_SYS_ADMIN", "CAP_SYS_BOOT", "CAP_SYS_NICE",
+	"CAP_SYS_RESOURCE", "CAP_SYS_TIME", "CAP_SYS_TTY_CONFIG", "CAP_MKNOD",
+	"CAP_LEASE", "CAP_AUDIT_WRITE", "CAP_AUDIT_CONTROL", "CAP_SETFCAP",
+	"CAP_MAC_OVERRIDE", "CAP_MAC_ADMIN", "CAP_SYSLOG", "CAP_WAKE_ALARM",
+	"CAP_BLOCK_SUSPEND", "CAP_AUDIT_READ", "CAP_PERFMON", "CAP_BPF",
+	"CAP_CHECKPOINT_RESTORE",
+}
+
+// CapabilityMask returns the bit mask of the named capabilities, which may
+// be given with or without the CAP_ prefix and in any case.
+func CapabilityMask(caps ...string) (uint64, error) {
+	var mask uint64
+outer:
+	for _, c := range caps {
+		name := strings.ToUpper(c)
+		if !strings.HasPrefix(name, "CAP_") {
+			name = "CAP_" + name
+		}
+		for i, known := range capabilities {
+			if known == name {
+				mask |= 1 << uint(i)
+				continue outer
+			}
+		}
+		return 0, fmt.Errorf("unknown capability %q", c)
+	}
+	return mask, nil
+}
+
+// CapabilityNames returns the names of the capabilities in mask.
+func CapabilityNames(mask uint64) []string {
+	var names []string
+	for i := uint(0); i < 64; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		if int(i) < len(capabilities) {
+			names = append(names, capabilities[i])
+		} else {
+			names = append(names, fmt.Sprintf("%d", i))
+		}
+	}
+	return names
+}
+
+// PropCapabilityBoundingSet sets the CapabilityBoundingSet service property
+// to the named capabilities, see CapabilityMask. See
+// https://www.freedesktop.org/software/systemd/man/systemd.exec.html#CapabilityBoundingSet=
+func PropCapabilityBoundingSet(caps ...string) (Property, error) {
+	mask, err := CapabilityMask(caps...)
+	if err != nil {
+		return Property{}, err
+	}
+	return Property{
+		Name:  "CapabilityBoundingSet",
+		Value: dbus.MakeVariant(mask),
+	}, nil
+}
+
+var syscallName = regexp.MustCompile(`^(@[a-z][a-z0-9-]*|[a-z_][a-z0-9_]*)$`)
+
+// PropSystemCallFilter sets the SystemCallFilter service property. If allow
+// is true, only the listed system calls are permitted, otherwise they are
+// denied. Groups such as @system-service may be given along with the names
+// of system calls. See
+// https://www.freedesktop.org/software/systemd/man/systemd.exec.html#SystemCallFilter=
+func PropSystemCallFilter(allow bool, syscalls ...string) (Property, error) {
+	for _, s := range syscalls {
+		if !syscallName.MatchString(s) {
+			return Property{}, fmt.Errorf("invalid system call %q", s)
+		}
+	}
+	if syscalls == nil {
+		syscalls = []string{}
+	}
+	return Property{
+		Name: "SystemCallFilter",
+		Value: dbus.MakeVariant(struct {
+			Allow    bool
+			Syscalls []string
+		}{allow, syscalls}),
+	}, nil
+}
+
+// PropProtectSystem sets the ProtectSystem service property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.exec.html#ProtectSystem=
+func PropProtectSystem(p ProtectSystem) Property {
+	return Property{
+		Name:  "ProtectSystem",
+		Value: dbus.MakeVariant(string(p)),
+	}
+}
+
+// PropPrivateTmp sets the PrivateTmp service property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.exec.html#PrivateTmp=
+func PropPrivateTmp(b bool) Property {
+	return Property{
+		Name:  "PrivateTmp",
+		Value: dbus.MakeVariant(b),
+	}
+}
+
+// PropNoNewPrivileges sets the NoNewPrivileges service property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.exec.html#NoNewPrivileges=
+func PropNoNewPrivileges(b bool) Property {
+	return Property{
+		Name:  "NoNewPrivileges",
+		Value: dbus.MakeVariant(b),
+	}
+}
+
+// sandboxVersions are the systemd versions accepting the sandboxing
+// properties for transient units.
+var sandboxVersions = map[string]int{
+	"CapabilityBoundingSet": 235,
+	"NoNewPrivileges":       235,
+	"PrivateTmp":            235,
+	"ProtectSystem":         235,
+	"SystemCallFilter":      235,
+}
+
+// CheckSandboxProperties returns an error if the running systemd does not
+// accept one of the sandboxing properties among props for transient units,
+// so that callers can leave it out instead of failing to start the unit.
+// Other properties are not checked.
+func (c *Conn) CheckSandboxProperties(ctx context.Context, props ...Property) error {
+	version, err := c.SystemdVersion(ctx)
+	if err != nil {
+		return err
+	}
+	return checkSandboxProperties(version, props)
+}
+
+func checkSandboxProperties(version int, props []Property) error {
+	for _, p := range props {
+		min, ok := sandboxVersions[p.Name]
+		if !ok {
+			continue
+		}
+		if version < min {
+			return fmt.Errorf("systemd %d does not support %s for transient units, %d is required", version, p.Name, min)
+		}
+		switch p.Name {
+		case "ProtectSystem":
+			switch v, _ := p.Value.Value().(string); ProtectSystem(v) {
+			case ProtectSystemNo, ProtectSystemYes, ProtectSystemFull, ProtectSystemStrict:
+			default:
+				return fmt.Errorf("invalid ProtectSystem value %q", v)
+			}
+		case "CapabilityBoundingSet":
+			if mask, _ := p.Value.Value().(uint64); mask>>uint(len(capabilities)) != 0 {
+				return fmt.Errorf("unknown capabilities in %v", CapabilityNames(mask))
+			}
+		}
+	}
+	return nil
+}
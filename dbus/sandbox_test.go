@@ -0,0 +1,132 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCapabilityMask(t *testing.T) {
+	mask, err := CapabilityMask("CAP_CHOWN", "net_bind_service", "cap_checkpoint_restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := uint64(1 | 1<<10 | 1<<40); mask != want {
+		t.Errorf("got mask %#x, want %#x", mask, want)
+	}
+	if names := CapabilityNames(mask | 1<<50); !reflect.DeepEqual(names, []string{"CAP_CHOWN", "CAP_NET_BIND_SERVICE", "CAP_CHECKPOINT_RESTORE", "50"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+	if _, err := CapabilityMask("CAP_FLY"); err == nil {
+		t.Error("expected error for unknown capability")
+	}
+}
+
+func TestSandboxProperties(t *testing.T) {
+	caps, err := PropCapabilityBoundingSet("CAP_NET_BIND_SERVICE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := PropSystemCallFilter(true, "@system-service", "ioctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	empty, err := PropSystemCallFilter(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range []struct {
+		prop      Property
+		signature string
+	}{
+		{caps, "t"},
+		{filter, "(bas)"},
+		{empty, "(bas)"},
+		{PropProtectSystem(ProtectSystemStrict), "s"},
+		{PropPrivateTmp(true), "b"},
+		{PropNoNewPrivileges(true), "b"},
+	} {
+		if sig := tt.prop.Value.Signature().String(); sig != tt.signature {
+			t.Errorf("case %d: %s has signature %s, want %s", i, tt.prop.Name, sig, tt.signature)
+		}
+	}
+	if _, err := PropSystemCallFilter(true, "read; rm"); err == nil {
+		t.Error("expected error for invalid system call")
+	}
+
+	for i, tt := range []struct {
+		version int
+		props   []Property
+		ok      bool
+	}{
+		{245, []Property{caps, filter, PropProtectSystem(ProtectSystemFull), PropDescription("x")}, true},
+		{219, []Property{PropDescription("x"), PropExecStart([]string{"/bin/true"}, false)}, true},
+		{219, []Property{PropPrivateTmp(true)}, false},
+		{245, []Property{PropProtectSystem("maybe")}, false},
+		{245, []Property{{Name: "CapabilityBoundingSet", Value: caps.Value}}, true},
+	} {
+		if err := checkSandboxProperties(tt.version, tt.props); (err == nil) != tt.ok {
+			t.Errorf("case %d: got %v", i, err)
+		}
+	}
+}
+
+func TestStartSandboxedTransientUnit(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	filter, err := PropSystemCallFilter(true, "@system-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	caps, err := PropCapabilityBoundingSet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	props := []Property{
+		PropExecStart([]string{"/bin/true"}, false),
+		PropType("oneshot"),
+		PropRemainAfterExit(true),
+		filter,
+		caps,
+		PropProtectSystem(ProtectSystemStrict),
+		PropPrivateTmp(true),
+		PropNoNewPrivileges(true),
+	}
+	ctx := context.Background()
+	if err := conn.CheckSandboxProperties(ctx, props...); err != nil {
+		t.Skip(err)
+	}
+
+	const target = "testing-transient-sandboxed.service"
+	reschan := make(chan string)
+	if _, err := conn.StartTransientUnitContext(ctx, target, "replace", props, reschan); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.StopUnitContext(ctx, target, "replace", nil)
+	if job := <-reschan; job != "done" {
+		t.Fatalf("job is not done: %s", job)
+	}
+
+	p, err := conn.GetServicePropertyContext(ctx, target, "NoNewPrivileges")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := p.Value.Value().(bool); !v {
+		t.Errorf("NoNewPrivileges is %v", p.Value)
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/unit"
+	"github.com/godbus/dbus/v5"
+)
+
+// StartTransientUnitAuxContext is like StartTransientUnitContext, but creates
+// aux as transient units along with it, such as the service a transient
+// timer or socket activates.
+func (c *Conn) StartTransientUnitAuxContext(ctx context.Context, name string, mode string, properties []Property, aux []PropertyCollection, ch chan<- string) (int, error) {
+	if aux == nil {
+		aux = make([]PropertyCollection, 0)
+	}
+	return c.startJob(ctx, ch, "org.freedesktop.systemd1.Manager.StartTransientUnit", name, mode, properties, aux)
+}
+
+// withSuffix returns name with its unit type replaced by suffix.
+func withSuffix(name, suffix string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	return name + suffix
+}
+
+// startTransientPair starts the transient unit name triggering a service of
+// the same name, as timers, paths and sockets do by default.
+func (c *Conn) startTransientPair(ctx context.Context, name, suffix, mode string, properties, service []Property, ch chan<- string) (int, error) {
+	if !strings.HasSuffix(name, suffix) {
+		return 0, fmt.Errorf("transient unit %s does not end in %s", name, suffix)
+	}
+	var aux []PropertyCollection
+	if service != nil {
+		aux = []PropertyCollection{{Name: withSuffix(name, ".service"), Properties: service}}
+	}
+	return c.StartTransientUnitAuxContext(ctx, name, mode, properties, aux, ch)
+}
+
+// StartTransientTimerContext creates and starts the transient timer name,
+// ending in .timer, with the given properties, such as those of
+// PropOnCalendar. Unless service is nil, it also creates the service of the
+// same name it activates with the service properties.
+func (c *Conn) StartTransientTimerContext(ctx context.Context, name string, mode string, timer []Property, service []Property, ch chan<- string) (int, error) {
+	return c.startTransientPair(ctx, name, ".timer", mode, timer, service, ch)
+}
+
+// StartTransientPathContext creates and starts the transient path unit
+// name, ending in .path, with the given properties, such as those of
+// PropPath. Unless service is nil, it also creates the service of the same
+// name it activates with the service properties.
+func (c *Conn) StartTransientPathContext(ctx context.Context, name string, mode string, path []Property, service []Property, ch chan<- string) (int, error) {
+	return c.startTransientPair(ctx, name, ".path", mode, path, service, ch)
+}
+
+// StartTransientSocketContext creates and starts the transient socket name,
+// ending in .socket, with the given properties, such as those of PropListen.
+// Unless service is nil, it also creates the service of the same name it
+// activates with the service properties; with PropAccept a template
+// service would be needed instead, which cannot be transient.
+func (c *Conn) StartTransientSocketContext(ctx context.Context, name string, mode string, socket []Property, service []Property, ch chan<- string) (int, error) {
+	return c.startTransientPair(ctx, name, ".socket", mode, socket, service, ch)
+}
+
+// MountUnitName returns the name of the mount or automount unit for the
+// mount point where, depending on suffix.
+func MountUnitName(where, suffix string) string {
+	return unit.UnitNamePathEscape(where) + suffix
+}
+
+// StartTransientMountContext mounts what on where, with the file system
+// type fstype and options, which may be empty, by creating and starting a
+// transient mount unit like systemd-mount does. More properties may be
+// given, such as PropDescription.
+func (c *Conn) StartTransientMountContext(ctx context.Context, what, where, fstype, options string, mode string, properties []Property, ch chan<- string) (int, error) {
+	props := append(mountProperties(what, where, fstype, options), properties...)
+	return c.StartTransientUnitContext(ctx, MountUnitName(where, ".mount"), mode, props, ch)
+}
+
+// StartTransientAutomountContext creates and starts a transient automount
+// unit for where, along with the transient mount unit mounting what there
+// on first access like systemd-mount --automount does. The automount
+// properties may include PropTimeoutIdleSec.
+func (c *Conn) StartTransientAutomountContext(ctx context.Context, what, where, fstype, options string, mode string, automount []Property, ch chan<- string) (int, error) {
+	props := append([]Property{PropWhere(where)}, automount...)
+	aux := []PropertyCollection{{
+		Name:       MountUnitName(where, ".mount"),
+		Properties: mountProperties(what, where, fstype, options),
+	}}
+	return c.StartTransientUnitAuxContext(ctx, MountUnitName(where, ".automount"), mode, props, aux, ch)
+}
+
+func mountProperties(what, where, fstype, options string) []Property {
+	props := []Property{PropWhat(what), PropWhere(where)}
+	if fstype != "" {
+		props = append(props, PropMountType(fstype))
+	}
+	if options != "" {
+		props = append(props, PropMountOptions(options))
+	}
+	return props
+}
+
+func usec(d time.Duration) uint64 {
+	return uint64(d / time.Microsecond)
+}
+
+type timerMonotonic struct {
+	Base string
+	USec uint64
+}
+
+func propTimerMonotonic(base string, d time.Duration) Property {
+	return Property{
+		Name:  "TimersMonotonic",
+		Value: dbus.MakeVariant([]timerMonotonic{{base, usec(d)}}),
+	}
+}
+
+// PropOnActiveSec makes a timer elapse d after it was started. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnActiveSec=
+func PropOnActiveSec(d time.Duration) Property {
+	return propTimerMonotonic("OnActiveSec", d)
+}
+
+// PropOnBootSec makes a timer elapse d after the system booted. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnBootSec=
+func PropOnBootSec(d time.Duration) Property {
+	return propTimerMonotonic("OnBootSec", d)
+}
+
+// PropOnStartupSec makes a timer elapse d after the service manager was
+// started. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnStartupSec=
+func PropOnStartupSec(d time.Duration) Property {
+	return propTimerMonotonic("OnStartupSec", d)
+}
+
+// PropOnUnitActiveSec makes a timer elapse d after the unit it activates
+// was last activated. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnUnitActiveSec=
+func PropOnUnitActiveSec(d time.Duration) Property {
+	return propTimerMonotonic("OnUnitActiveSec", d)
+}
+
+// PropOnUnitInactiveSec makes a timer elapse d after the unit it activates
+// was last deactivated. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnUnitInactiveSec=
+func PropOnUnitInactiveSec(d time.Duration) Property {
+	return propTimerMonotonic("OnUnitInactiveSec", d)
+}
+
+// PropOnCalendar makes a timer elapse at the times of a calendar event
+// such as "Mon *-*-* 08:00", see the unit package for parsing them. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#OnCalendar=
+func PropOnCalendar(spec string) Property {
+	return Property{
+		Name: "TimersCalendar",
+		Value: dbus.MakeVariant([]struct {
+			Base string
+			Spec string
+		}{{"OnCalendar", spec}}),
+	}
+}
+
+// PropPersistent sets the Persistent timer property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#Persistent=
+func PropPersistent(b bool) Property {
+	return Property{
+		Name:  "Persistent",
+		Value: dbus.MakeVariant(b),
+	}
+}
+
+// PropAccuracySec sets the AccuracySec timer property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#AccuracySec=
+func PropAccuracySec(d time.Duration) Property {
+	return Property{
+		Name:  "AccuracyUSec",
+		Value: dbus.MakeVariant(usec(d)),
+	}
+}
+
+// PropRandomizedDelaySec sets the RandomizedDelaySec timer property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#RandomizedDelaySec=
+func PropRandomizedDelaySec(d time.Duration) Property {
+	return Property{
+		Name:  "RandomizedDelayUSec",
+		Value: dbus.MakeVariant(usec(d)),
+	}
+}
+
+// PropUnit sets the Unit property of timers and paths, the unit they
+// activate instead of the service of the same name. See
+// https://www.freedesktop.org/software/systemd/man/systemd.timer.html#Unit=
+func PropUnit(name string) Property {
+	return Property{
+		Name:  "Unit",
+		Value: dbus.MakeVariant(name),
+	}
+}
+
+// Conditions of path units.
+const (
+	PathExists        = "PathExists"
+	PathExistsGlob    = "PathExistsGlob"
+	PathChanged       = "PathChanged"
+	PathModified      = "PathModified"
+	DirectoryNotEmpty = "DirectoryNotEmpty"
+)
+
+// PropPath makes a path unit watch path for the condition, such as
+// PathChanged. See
+// https://www.freedesktop.org/software/systemd/man/systemd.path.html#PathExists=
+func PropPath(condition, path string) Property {
+	return Property{
+		Name: "Paths",
+		Value: dbus.MakeVariant([]struct {
+			Condition string
+			Path      string
+		}{{condition, path}}),
+	}
+}
+
+// PropMakeDirectory sets the MakeDirectory path property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.path.html#MakeDirectory=
+func PropMakeDirectory(b bool) Property {
+	return Property{
+		Name:  "MakeDirectory",
+		Value: dbus.MakeVariant(b),
+	}
+}
+
+// Kinds of socket unit listeners, matching the ListenStream=, ListenDatagram=
+// and so on settings of unit files.
+const (
+	ListenStream           = "Stream"
+	ListenDatagram         = "Datagram"
+	ListenSequentialPacket = "SequentialPacket"
+	ListenFIFO             = "FIFO"
+	ListenSpecial          = "Special"
+	ListenNetlink          = "Netlink"
+	ListenMessageQueue     = "MessageQueue"
+	ListenUSBFunction      = "USBFunction"
+)
+
+// PropListen makes a socket unit listen on address, with kind being one of
+// the kinds such as ListenStream, which is "Stream" rather than the name of
+// the unit file setting. See
+// https://www.freedesktop.org/software/systemd/man/systemd.socket.html#ListenStream=
+func PropListen(kind, address string) Property {
+	return Property{
+		Name: "Listen",
+		Value: dbus.MakeVariant([]struct {
+			Kind    string
+			Address string
+		}{{kind, address}}),
+	}
+}
+
+// PropAccept sets the Accept socket property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.socket.html#Accept=
+func PropAccept(b bool) Property {
+	return Property{
+		Name:  "Accept",
+		Value: dbus.MakeVariant(b),
+	}
+}
+
+// PropWhat sets the What mount property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.mount.html#What=
+func PropWhat(what string) Property {
+	return Property{
+		Name:  "What",
+		Value: dbus.MakeVariant(what),
+	}
+}
+
+// PropWhere sets the Where mount and automount property, which has to match
+// the unit name, see MountUnitName. See
+// https://www.freedesktop.org/software/systemd/man/systemd.mount.html#Where=
+func PropWhere(where string) Property {
+	return Property{
+		Name:  "Where",
+		Value: dbus.MakeVariant(where),
+	}
+}
+
+// PropMountType sets the Type mount property, the file system type. See
+// https://www.freedesktop.org/software/systemd/man/systemd.mount.html#Type=
+func PropMountType(fstype string) Property {
+	return Property{
+		Name:  "Type",
+		Value: dbus.MakeVariant(fstype),
+	}
+}
+
+// PropMountOptions sets the Options mount property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.mount.html#Options=
+func PropMountOptions(options string) Property {
+	return Property{
+		Name:  "Options",
+		Value: dbus.MakeVariant(options),
+	}
+}
+
+// PropTimeoutIdleSec sets the TimeoutIdleSec automount property. See
+// https://www.freedesktop.org/software/systemd/man/systemd.automount.html#TimeoutIdleSec=
+func PropTimeoutIdleSec(d time.Duration) Property {
+	return Property{
+		Name:  "TimeoutIdleUSec",
+		Value: dbus.MakeVariant(usec(d)),
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTransientProperties(t *testing.T) {
+	for i, tt := range []struct {
+		prop      Property
+		name      string
+		signature string
+	}{
+		{PropOnActiveSec(time.Minute), "TimersMonotonic", "a(st)"},
+		{PropOnUnitActiveSec(time.Hour), "TimersMonotonic", "a(st)"},
+		{PropOnCalendar("daily"), "TimersCalendar", "a(ss)"},
+		{PropPersistent(true), "Persistent", "b"},
+		{PropAccuracySec(time.Second), "AccuracyUSec", "t"},
+		{PropRandomizedDelaySec(time.Second), "RandomizedDelayUSec", "t"},
+		{PropUnit("foo.service"), "Unit", "s"},
+		{PropPath(PathChanged, "/etc/foo"), "Paths", "a(ss)"},
+		{PropMakeDirectory(true), "MakeDirectory", "b"},
+		{PropListen(ListenStream, "127.0.0.1:8080"), "Listen", "a(ss)"},
+		{PropAccept(false), "Accept", "b"},
+		{PropWhat("/dev/sda1"), "What", "s"},
+		{PropWhere("/mnt"), "Where", "s"},
+		{PropMountType("ext4"), "Type", "s"},
+		{PropMountOptions("ro"), "Options", "s"},
+		{PropTimeoutIdleSec(time.Minute), "TimeoutIdleUSec", "t"},
+	} {
+		if tt.prop.Name != tt.name {
+			t.Errorf("case %d: got name %s, want %s", i, tt.prop.Name, tt.name)
+		}
+		if sig := tt.prop.Value.Signature().String(); sig != tt.signature {
+			t.Errorf("case %d: %s has signature %s, want %s", i, tt.prop.Name, sig, tt.signature)
+		}
+	}
+
+	if v := PropOnUnitActiveSec(90 * time.Second).Value.Value().([]timerMonotonic); len(v) != 1 || v[0] != (timerMonotonic{"OnUnitActiveSec", 90000000}) {
+		t.Errorf("unexpected timer %v", v)
+	}
+	if v := PropListen(ListenStream, "127.0.0.1:8080").Value.Value().([]struct {
+		Kind    string
+		Address string
+	}); len(v) != 1 || v[0].Kind != "Stream" || v[0].Address != "127.0.0.1:8080" {
+		t.Errorf("unexpected listener %v", v)
+	}
+	if name := MountUnitName("/var/lib/my-data", ".mount"); name != "var-lib-my\\x2ddata.mount" {
+		t.Errorf("unexpected mount unit name %s", name)
+	}
+	if name := withSuffix("backup.timer", ".service"); name != "backup.service" {
+		t.Errorf("unexpected service name %s", name)
+	}
+}
+
+func TestStartTransientTimer(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	ctx := context.Background()
+	const target = "testing-transient-timer.timer"
+	reschan := make(chan string)
+	_, err := conn.StartTransientTimerContext(ctx, target, "replace",
+		[]Property{PropOnActiveSec(time.Hour), PropAccuracySec(time.Second)},
+		[]Property{PropExecStart([]string{"/bin/true"}, false), PropType("oneshot")},
+		reschan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.StopUnitContext(ctx, target, "replace", nil)
+	if job := <-reschan; job != "done" {
+		t.Fatalf("job is not done: %s", job)
+	}
+
+	p, err := conn.GetUnitTypePropertyContext(ctx, target, "Timer", "Unit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := p.Value.Value().(string); v != "testing-transient-timer.service" {
+		t.Errorf("timer activates %v", p.Value)
+	}
+
+	if _, err := conn.StartTransientTimerContext(ctx, "testing-transient-timer.service", "replace", nil, nil, nil); err == nil {
+		t.Error("expected error for timer without .timer suffix")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// UnitState is the state of a unit observed by WaitUnitState.
+type UnitState struct {
+	LoadState   string
+	ActiveState string
+	SubState    string
+	JobId       uint32 // The job queued for the unit, 0 if there is none
+}
+
+// UnitStateError is returned by WaitUnitState when a unit cannot reach the
+// desired state anymore.
+type UnitStateError struct {
+	Unit  string
+	State UnitState
+}
+
+func (e *UnitStateError) Error() string {
+	if e.State.LoadState != "loaded" {
+		return fmt.Sprintf("unit %s is %s", e.Unit, e.State.LoadState)
+	}
+	return fmt.Sprintf("unit %s is %s (%s)", e.Unit, e.State.ActiveState, e.State.SubState)
+}
+
+// WaitUnitState waits until unit reaches targetActiveState and, unless it is
+// empty, targetSubState, like "active" and "running" for a started service.
+// It returns the states the unit went through, ending with the one it
+// reached. If the unit fails to load, or is inactive or failed without a job
+// queued that could change this, a *UnitStateError is returned, so the
+// wait should be started after the job to reach the state was queued.
+//
+// The manager is subscribed to for signals as with Subscribe, which stays in
+// effect after WaitUnitState returns.
+func (c *Conn) WaitUnitState(ctx context.Context, unit, targetActiveState, targetSubState string) ([]UnitState, error) {
+	var states []UnitState
+	err := c.WaitUnitStateFunc(ctx, unit, targetActiveState, targetSubState, func(s UnitState) {
+		states = append(states, s)
+	})
+	return states, err
+}
+
+// WaitUnitStateFunc is like WaitUnitState but calls fn with every state the
+// unit goes through as it is observed, starting with the current one.
+func (c *Conn) WaitUnitStateFunc(ctx context.Context, unit, targetActiveState, targetSubState string, fn func(UnitState)) error {
+	path := unitPath(unit)
+	match := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(path),
+		dbus.WithMatchInterface("org.freedesktop.DBus.Properties"),
+		dbus.WithMatchMember("PropertiesChanged"),
+	}
+	if err := c.sigconn.AddMatchSignalContext(ctx, match...); err != nil {
+		return err
+	}
+	defer c.sigconn.RemoveMatchSignal(match...)
+
+	ch := make(chan *dbus.Signal, signalBuffer)
+	c.sigconn.Signal(ch)
+	defer c.sigconn.RemoveSignal(ch)
+
+	// systemd refuses to subscribe a connection twice, which happens for
+	// each wait after the first or after Subscribe, but it stays subscribed.
+	err := c.sigobj.CallWithContext(ctx, "org.freedesktop.systemd1.Manager.Subscribe", 0).Store()
+	if err := ignoreAlreadySubscribed(err); err != nil {
+		return err
+	}
+
+	// The state is read after subscribing to changes, so none are missed.
+	var last UnitState
+	first := true
+	for {
+		state, err := c.unitState(ctx, path)
+		if err != nil {
+			return err
+		}
+		if first || state != last {
+			first = false
+			last = state
+			if fn != nil {
+				fn(state)
+			}
+		}
+
+		if state.ActiveState == targetActiveState && (targetSubState == "" || state.SubState == targetSubState) {
+			return nil
+		}
+		if state.LoadState != "loaded" && state.LoadState != "stub" {
+			return &UnitStateError{Unit: unit, State: state}
+		}
+		if state.JobId == 0 && (state.ActiveState == "inactive" || state.ActiveState == "failed") {
+			return &UnitStateError{Unit: unit, State: state}
+		}
+
+		if err := waitPropertiesChanged(ctx, ch, path); err != nil {
+			return err
+		}
+	}
+}
+
+// ignoreAlreadySubscribed returns err unless it is the error systemd replies
+// with to a connection which is already subscribed.
+func ignoreAlreadySubscribed(err error) error {
+	if e, ok := err.(dbus.Error); ok && e.Name == "org.freedesktop.systemd1.AlreadySubscribed" {
+		return nil
+	}
+	return err
+}
+
+// waitPropertiesChanged waits for a change of the unit properties of path.
+func waitPropertiesChanged(ctx context.Context, ch <-chan *dbus.Signal, path dbus.ObjectPath) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case signal, ok := <-ch:
+			if !ok {
+				return errors.New("connection closed")
+			}
+			if signal.Path != path || signal.Name != "org.freedesktop.DBus.Properties.PropertiesChanged" || len(signal.Body) == 0 {
+				continue
+			}
+			if iface, _ := signal.Body[0].(string); iface == "org.freedesktop.systemd1.Unit" {
+				return nil
+			}
+		}
+	}
+}
+
+func (c *Conn) unitState(ctx context.Context, path dbus.ObjectPath) (UnitState, error) {
+	props, err := c.getProperties(ctx, path, "org.freedesktop.systemd1.Unit")
+	if err != nil {
+		return UnitState{}, err
+	}
+	var s UnitState
+	s.LoadState, _ = props["LoadState"].(string)
+	s.ActiveState, _ = props["ActiveState"].(string)
+	s.SubState, _ = props["SubState"].(string)
+	if job, ok := props["Job"].([]interface{}); ok && len(job) == 2 {
+		s.JobId, _ = job[0].(uint32)
+	}
+	return s, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestWaitUnitState(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	target := "testing-wait.service"
+	props := []Property{PropExecStart([]string{"/bin/sleep", "400"}, false)}
+	if _, err := conn.StartTransientUnitContext(ctx, target, "replace", props, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.StopUnitContext(ctx, target, "replace", nil)
+
+	states, err := conn.WaitUnitState(ctx, target, "active", "running")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := states[len(states)-1]; last.ActiveState != "active" || last.SubState != "running" {
+		t.Fatalf("unexpected final state %+v", last)
+	}
+
+	if _, err := conn.StopUnitContext(ctx, target, "replace", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.WaitUnitState(ctx, target, "inactive", ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWaitUnitStateFailed(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	target := "testing-wait-failed.service"
+	props := []Property{PropExecStart([]string{"/bin/false"}, false)}
+	if _, err := conn.StartTransientUnitContext(ctx, target, "replace", props, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.ResetFailedUnitContext(ctx, target)
+
+	_, err := conn.WaitUnitState(ctx, target, "active", "running")
+	e, ok := err.(*UnitStateError)
+	if !ok {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if e.State.ActiveState != "failed" && e.State.ActiveState != "inactive" {
+		t.Errorf("unexpected state %+v", e.State)
+	}
+}
+
+func TestWaitUnitStateSubscribed(t *testing.T) {
+	conn := setupConn(t)
+	defer conn.Close()
+
+	if err := conn.Subscribe(); err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Unsubscribe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Waiting repeatedly on a subscribed connection must not fail.
+	for i := 0; i < 2; i++ {
+		if _, err := conn.WaitUnitState(ctx, "-.slice", "active", ""); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+}
+
+func TestIgnoreAlreadySubscribed(t *testing.T) {
+	if err := ignoreAlreadySubscribed(dbus.Error{Name: "org.freedesktop.systemd1.AlreadySubscribed"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	other := dbus.Error{Name: "org.freedesktop.DBus.Error.AccessDenied"}
+	if err := ignoreAlreadySubscribed(other); err == nil {
+		t.Error("expected other errors to be kept")
+	}
+	if err := ignoreAlreadySubscribed(nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestUnitStateError(t *testing.T) {
+	for i, tt := range []struct {
+		state UnitState
+		want  string
+	}{
+		{UnitState{LoadState: "not-found", ActiveState: "inactive"}, "unit a.service is not-found"},
+		{UnitState{LoadState: "loaded", ActiveState: "failed", SubState: "failed"}, "unit a.service is failed (failed)"},
+	} {
+		err := &UnitStateError{Unit: "a.service", State: tt.state}
+		if err.Error() != tt.want {
+			t.Errorf("case %d: got %q", i, err.Error())
+		}
+	}
+}
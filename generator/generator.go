@@ -0,0 +1,202 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package generator helps writing systemd generators, small programs systemd
+// runs early at boot and on every reload to create units dynamically. See
+// https://www.freedesktop.org/software/systemd/man/systemd.generator.html
+//
+// Generators run before most of the system is up: D-Bus is not available,
+// nothing should be printed on standard output and no units may be started.
+// Messages logged with Generator.Logf go to the kernel log buffer, where
+// they end up in the journal later.
+package generator
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/cmdline"
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// Generator holds the output directories passed to a generator, in order
+// of precedence relative to the units installed on the system.
+type Generator struct {
+	// NormalDir overrides /usr but not /etc.
+	NormalDir string
+	// EarlyDir overrides everything, including /etc.
+	EarlyDir string
+	// LateDir is overridden by everything else.
+	LateDir string
+
+	// UserScope is set if the generator runs for a user manager.
+	UserScope bool
+	// InInitrd is set if the generator runs in the initrd.
+	InInitrd bool
+	// FirstBoot is set if the system boots for the first time.
+	FirstBoot bool
+
+	log io.Writer
+}
+
+// FromArgs returns the Generator for the arguments a generator was invoked
+// with, excluding the program name. systemd passes the normal, early and late
+// directories; without arguments, all output goes to /tmp, which is useful for
+// testing a generator by hand.
+func FromArgs(args []string) (*Generator, error) {
+	var g *Generator
+	switch len(args) {
+	case 0:
+		g = &Generator{NormalDir: os.TempDir(), EarlyDir: os.TempDir(), LateDir: os.TempDir()}
+	case 3:
+		g = &Generator{NormalDir: args[0], EarlyDir: args[1], LateDir: args[2]}
+	default:
+		return nil, fmt.Errorf("expected zero or three arguments, got %d", len(args))
+	}
+
+	g.UserScope = os.Getenv("SYSTEMD_SCOPE") == "user"
+	g.InInitrd = cmdline.InInitrd()
+	g.FirstBoot = os.Getenv("SYSTEMD_FIRST_BOOT") == "1"
+	return g, nil
+}
+
+// Run parses the command line of the generator and runs fn. If fn fails, the
+// error is logged and the program exits with status 1.
+func Run(fn func(*Generator) error) {
+	g, err := FromArgs(os.Args[1:])
+	if err == nil {
+		err = fn(g)
+	}
+	if err != nil {
+		if g == nil {
+			g = &Generator{}
+		}
+		g.Logf("%v", err)
+		os.Exit(1)
+	}
+}
+
+// Logf writes a message to the kernel log buffer, or to standard error if
+// /dev/kmsg cannot be opened.
+func (g *Generator) Logf(format string, args ...interface{}) {
+	if g.log == nil {
+		if kmsg, err := os.OpenFile("/dev/kmsg", os.O_WRONLY, 0); err == nil {
+			g.log = kmsg
+		} else {
+			g.log = os.Stderr
+		}
+	}
+
+	msg := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+	if g.log == os.Stderr {
+		fmt.Fprintln(g.log, msg)
+		return
+	}
+	fmt.Fprintf(g.log, "<3>%s[%d]: %s\n", filepath.Base(os.Args[0]), os.Getpid(), msg)
+}
+
+// WriteUnit writes the unit name with the given options below dir, which is
+// one of the generator's directories. Like systemd's generators, it fails
+// rather than overwrite an existing unit, as that would mean two sources
+// configured the same unit.
+func (g *Generator) WriteUnit(dir, name string, opts []*unit.UnitOption) error {
+	if !unit.UnitNameIsValid(name) {
+		return fmt.Errorf("invalid unit name %q", name)
+	}
+	p := filepath.Join(dir, name)
+	if _, err := os.Lstat(p); err == nil {
+		return &os.PathError{Op: "create", Path: p, Err: os.ErrExist}
+	}
+	return writeFileAtomic(p, unit.Serialize(opts))
+}
+
+// WriteDropIn writes a drop-in called dropIn for the unit name below dir,
+// e.g. dir/foo.service.d/50-generated.conf. Existing drop-ins are replaced.
+func (g *Generator) WriteDropIn(dir, name, dropIn string, opts []*unit.UnitOption) error {
+	p := unit.DropInPath(dir, name, dropIn)
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(p, unit.Serialize(opts))
+}
+
+// AddDependency links the unit name into the .wants, .requires or .upholds
+// directory of target below dir, as installing a unit with WantedBy= would.
+// dep is the dependency type, e.g. "wants". name refers to a unit written to
+// dir, or to an absolute path of a unit file.
+func (g *Generator) AddDependency(dir, target, dep, name string) error {
+	switch dep {
+	case "wants", "requires", "upholds":
+	default:
+		return fmt.Errorf("invalid dependency type %q", dep)
+	}
+
+	src := name
+	if !filepath.IsAbs(src) {
+		src = filepath.Join("..", name)
+	}
+	linkDir := filepath.Join(dir, target+"."+dep)
+	if err := os.MkdirAll(linkDir, 0755); err != nil {
+		return err
+	}
+	link := filepath.Join(linkDir, filepath.Base(name))
+	if existing, err := os.Readlink(link); err == nil && existing == src {
+		return nil
+	}
+
+	// Create the link next to its final place and rename it, so systemd
+	// never sees a partial directory entry.
+	tmp := filepath.Join(linkDir, "."+filepath.Base(name)+".tmp")
+	os.Remove(tmp)
+	if err := os.Symlink(src, tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// writeFileAtomic writes the contents of r to a temporary file in the
+// directory of p and renames it to p.
+func writeFileAtomic(p string, r io.Reader) error {
+	f, err := ioutil.TempFile(filepath.Dir(p), "."+filepath.Base(p))
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	err = func() error {
+		defer f.Close()
+		if _, err := io.Copy(f, r); err != nil {
+			return err
+		}
+		if err := f.Chmod(0644); err != nil {
+			return err
+		}
+		return f.Sync()
+	}()
+	if err == nil {
+		err = os.Rename(tmp, p)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
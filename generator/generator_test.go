@@ -0,0 +1,115 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+func TestFromArgs(t *testing.T) {
+	g, err := FromArgs([]string{"/run/systemd/generator", "/run/systemd/generator.early", "/run/systemd/generator.late"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NormalDir != "/run/systemd/generator" || g.EarlyDir != "/run/systemd/generator.early" || g.LateDir != "/run/systemd/generator.late" {
+		t.Errorf("unexpected directories %+v", g)
+	}
+
+	g, err = FromArgs(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.NormalDir != os.TempDir() {
+		t.Errorf("expected %s without arguments, got %s", os.TempDir(), g.NormalDir)
+	}
+
+	if _, err := FromArgs([]string{"/run/systemd/generator"}); err == nil {
+		t.Error("expected error for a single argument")
+	}
+}
+
+func TestWriteUnit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-generator")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g, err := FromArgs([]string{dir, dir, dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := []*unit.UnitOption{
+		unit.NewUnitOption("Unit", "Description", "Generated"),
+		unit.NewUnitOption("Service", "ExecStart", "/bin/true"),
+	}
+	if err := g.WriteUnit(dir, "foo.service", opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "foo.service"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "[Unit]\nDescription=Generated\n\n[Service]\nExecStart=/bin/true\n"
+	if string(b) != expected {
+		t.Errorf("expected %q, got %q", expected, string(b))
+	}
+
+	if err := g.WriteUnit(dir, "foo.service", opts); !os.IsExist(err) {
+		t.Errorf("expected exists error writing a unit twice, got %v", err)
+	}
+	if err := g.WriteUnit(dir, "foo", opts); err == nil {
+		t.Error("expected error for invalid unit name")
+	}
+
+	if err := g.WriteDropIn(dir, "bar.service", "50-generated", opts[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bar.service.d", "50-generated.conf")); err != nil {
+		t.Error(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := g.AddDependency(dir, "multi-user.target", "wants", "foo.service"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	target, err := os.Readlink(filepath.Join(dir, "multi-user.target.wants", "foo.service"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target != "../foo.service" {
+		t.Errorf("expected link to ../foo.service, got %s", target)
+	}
+	if err := g.AddDependency(dir, "multi-user.target", "conflicts", "foo.service"); err == nil {
+		t.Error("expected error for invalid dependency type")
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name()[0] == '.' {
+			t.Errorf("temporary file %s left behind", e.Name())
+		}
+	}
+}
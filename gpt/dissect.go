@@ -0,0 +1,107 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpt
+
+import (
+	"fmt"
+
+	"github.com/coreos/go-systemd/v22/boot"
+)
+
+// versioned are the designators of which an image may have several
+// partitions, e.g. for A/B updates, of which the newest is used.
+var versioned = map[Designator]bool{
+	Root:          true,
+	RootVerity:    true,
+	RootVeritySig: true,
+	Usr:           true,
+	UsrVerity:     true,
+	UsrVeritySig:  true,
+}
+
+// Dissected are the partitions of an image identified by Dissect.
+type Dissected struct {
+	// Arch is the architecture of the root and usr partitions, which is
+	// the native or the secondary one for the requested architecture.
+	Arch       Arch
+	Partitions map[Designator]*Partition
+}
+
+// Dissect identifies the partitions of t that systemd would use when booting
+// or dissecting the image on arch, which defaults to the native
+// architecture:
+//
+//   - Partitions marked with FlagNoAuto are ignored, as are ESPs marked with
+//     FlagNoBlockIOProtocol.
+//   - Root and usr partitions of arch are preferred over those of its
+//     secondary architecture, e.g. x86 for x86-64.
+//   - If there are several root or usr partitions, the one with the newest
+//     version in its label is used, compared like systemd-boot compares
+//     versions.
+//   - Several partitions of any other designator are an error.
+func Dissect(t *Table, arch Arch) (*Dissected, error) {
+	if arch == "" {
+		arch = NativeArch()
+	}
+	d := &Dissected{Partitions: make(map[Designator]*Partition)}
+	secondary := make(map[Designator]*Partition)
+
+	for _, p := range t.Partitions {
+		typ, ok := p.TypeInfo()
+		if !ok || typ.Designator == LinuxGeneric {
+			continue
+		}
+		if typ.Designator == ESP {
+			if p.Flags&FlagNoBlockIOProtocol != 0 {
+				continue
+			}
+		} else if p.Flags&FlagNoAuto != 0 {
+			continue
+		}
+
+		found := d.Partitions
+		if typ.Arch != "" && typ.Arch != arch {
+			if typ.Arch != secondaryArches[arch] {
+				continue
+			}
+			found = secondary
+		}
+		if old := found[typ.Designator]; old != nil {
+			if !versioned[typ.Designator] {
+				return nil, fmt.Errorf("partitions %d and %d are both %s partitions", old.Number, p.Number, typ.Designator)
+			}
+			if boot.CompareVersions(p.Label, old.Label) <= 0 {
+				continue
+			}
+		}
+		found[typ.Designator] = p
+	}
+
+	d.Arch = arch
+	_, haveRoot := d.Partitions[Root]
+	_, haveUsr := d.Partitions[Usr]
+	if !haveRoot && !haveUsr && (secondary[Root] != nil || secondary[Usr] != nil) {
+		// Use the secondary architecture as a whole rather than mixing
+		// partitions of both.
+		for des := range versioned {
+			delete(d.Partitions, des)
+			if p := secondary[des]; p != nil {
+				d.Partitions[des] = p
+			}
+		}
+		d.Arch = secondaryArches[arch]
+	}
+	return d, nil
+}
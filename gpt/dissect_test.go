@@ -0,0 +1,119 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpt
+
+import (
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/id128"
+)
+
+func typeUUID(d Designator, arch Arch) id128.ID {
+	id, ok := TypeUUID(d, arch)
+	if !ok {
+		panic("no type " + string(d))
+	}
+	return id
+}
+
+func TestDissect(t *testing.T) {
+	tab := &Table{Partitions: []*Partition{
+		{Number: 1, Type: typeUUID(ESP, "")},
+		{Number: 2, Type: typeUUID(Root, ArchX86_64), Label: "root_1.9"},
+		{Number: 3, Type: typeUUID(Root, ArchX86_64), Label: "root_1.10"},
+		{Number: 4, Type: typeUUID(Root, ArchX86), Label: "root_2"},
+		{Number: 5, Type: typeUUID(Root, ArchARM64)},
+		{Number: 6, Type: typeUUID(Swap, ""), Flags: FlagNoAuto},
+		{Number: 7, Type: typeUUID(Home, "")},
+		{Number: 8, Type: typeUUID(LinuxGeneric, "")},
+		{Number: 9, Type: typeUUID(XBOOTLDR, ""), Flags: FlagNoBlockIOProtocol},
+	}}
+	d, err := Dissect(tab, ArchX86_64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Arch != ArchX86_64 {
+		t.Errorf("unexpected architecture %q", d.Arch)
+	}
+	want := map[Designator]int{ESP: 1, Root: 3, Home: 7, XBOOTLDR: 9}
+	if len(d.Partitions) != len(want) {
+		t.Errorf("unexpected partitions %v", d.Partitions)
+	}
+	for des, n := range want {
+		if p := d.Partitions[des]; p == nil || p.Number != n {
+			t.Errorf("unexpected %s partition %+v", des, p)
+		}
+	}
+
+	// Without native partitions, the secondary architecture is used.
+	tab.Partitions = append(tab.Partitions[:1], tab.Partitions[3:]...)
+	tab.Partitions[1].Label = ""
+	if d, err = Dissect(tab, ArchX86_64); err != nil {
+		t.Fatal(err)
+	}
+	if d.Arch != ArchX86 || d.Partitions[Root] == nil || d.Partitions[Root].Number != 4 {
+		t.Errorf("unexpected secondary dissection %q %+v", d.Arch, d.Partitions[Root])
+	}
+	if d, err = Dissect(tab, ArchARM64); err != nil {
+		t.Fatal(err)
+	}
+	if d.Arch != ArchARM64 || d.Partitions[Root].Number != 5 {
+		t.Errorf("unexpected arm64 dissection %q %+v", d.Arch, d.Partitions[Root])
+	}
+
+	tab.Partitions = append(tab.Partitions, &Partition{Number: 10, Type: typeUUID(Home, "")})
+	if _, err := Dissect(tab, ArchX86_64); err == nil {
+		t.Error("expected error for duplicate home partitions")
+	}
+}
+
+func TestTypes(t *testing.T) {
+	seen := map[id128.ID]bool{}
+	for _, typ := range Types {
+		if seen[typ.UUID] {
+			t.Errorf("duplicate type UUID %s", typ.UUID.UUIDString())
+		}
+		seen[typ.UUID] = true
+	}
+
+	for i, tt := range []struct {
+		in   string
+		want string
+	}{
+		{"esp", "c12a7328-f81f-11d2-ba4b-00a0c93ec93b"},
+		{"root-arm64", "b921b045-1df0-41c3-af44-4c6f280d3fae"},
+		{"usr-verity-sig-x86-64", "e7bb33fb-06cf-4e81-8273-e543b413e2e2"},
+		{"0FC63DAF-8483-4772-8E79-3D69D8477DE4", "0fc63daf-8483-4772-8e79-3d69d8477de4"},
+		{"root-sparc", ""},
+	} {
+		id, err := ParseType(tt.in)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("case %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil || id.UUIDString() != tt.want {
+			t.Errorf("case %d: got %s, %v", i, id.UUIDString(), err)
+		}
+	}
+
+	if NativeArch() != "" {
+		id, err := ParseType("root")
+		if err != nil || id != typeUUID(Root, NativeArch()) {
+			t.Errorf("unexpected native root type %s, %v", id.UUIDString(), err)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gpt reads GUID partition tables and identifies their partitions by
+// the type UUIDs of the Discoverable Partitions Specification, as
+// systemd-gpt-auto-generator and systemd-dissect do.  See
+// https://uapi-group.org/specifications/specs/discoverable_partitions_specification/
+package gpt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"unicode/utf16"
+
+	"github.com/coreos/go-systemd/v22/id128"
+)
+
+// Partition attribute flags.
+const (
+	FlagRequiredPartition  uint64 = 1 << 0
+	FlagNoBlockIOProtocol  uint64 = 1 << 1
+	FlagLegacyBIOSBootable uint64 = 1 << 2
+
+	// The following flags are only defined for the types of the
+	// Discoverable Partitions Specification.
+	FlagGrowFileSystem uint64 = 1 << 59
+	FlagReadOnly       uint64 = 1 << 60
+	FlagNoAuto         uint64 = 1 << 63
+)
+
+// ErrNoTable is returned for disks without a valid GUID partition table.
+var ErrNoTable = errors.New("no GUID partition table found")
+
+// Partition is an entry of a GUID partition table.
+type Partition struct {
+	Number   int // Starting at 1
+	Type     id128.ID
+	UUID     id128.ID
+	FirstLBA uint64
+	LastLBA  uint64
+	Flags    uint64
+	Label    string
+
+	Offset int64 // in bytes
+	Size   int64
+}
+
+// Table is a GUID partition table.
+type Table struct {
+	SectorSize     int
+	DiskGUID       id128.ID
+	FirstUsableLBA uint64
+	LastUsableLBA  uint64
+	// Partitions are the used entries of the table, in their order.
+	Partitions []*Partition
+}
+
+// Open reads the partition table of the disk image or block device at path.
+func Open(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read reads the primary partition table of a disk with 512 or 4096 byte
+// sectors.
+func Read(r io.ReaderAt) (*Table, error) {
+	for _, size := range []int{512, 4096} {
+		t, err := ReadSectorSize(r, size)
+		if err != ErrNoTable {
+			return t, err
+		}
+	}
+	return nil, ErrNoTable
+}
+
+// mixedEndian converts between the on-disk form of a GUID, whose first three
+// fields are little-endian, and the UUID byte order of id128.ID.
+func mixedEndian(b []byte) id128.ID {
+	var id id128.ID
+	copy(id[:], b)
+	id[0], id[1], id[2], id[3] = id[3], id[2], id[1], id[0]
+	id[4], id[5] = id[5], id[4]
+	id[6], id[7] = id[7], id[6]
+	return id
+}
+
+// ReadSectorSize reads the primary partition table of a disk with the given
+// sector size, which must be a power of two of at least 512 bytes.
+func ReadSectorSize(r io.ReaderAt, sectorSize int) (*Table, error) {
+	if sectorSize < 512 || sectorSize&(sectorSize-1) != 0 {
+		return nil, fmt.Errorf("invalid sector size %d", sectorSize)
+	}
+	hdr := make([]byte, sectorSize)
+	if _, err := r.ReadAt(hdr, int64(sectorSize)); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrNoTable
+		}
+		return nil, err
+	}
+	if string(hdr[:8]) != "EFI PART" {
+		return nil, ErrNoTable
+	}
+	hdrSize := binary.LittleEndian.Uint32(hdr[12:])
+	if hdrSize < 92 || int(hdrSize) > sectorSize {
+		return nil, fmt.Errorf("invalid GPT header size %d", hdrSize)
+	}
+	sum := binary.LittleEndian.Uint32(hdr[16:])
+	binary.LittleEndian.PutUint32(hdr[16:], 0)
+	if crc32.ChecksumIEEE(hdr[:hdrSize]) != sum {
+		return nil, errors.New("GPT header checksum mismatch")
+	}
+
+	t := &Table{
+		SectorSize:     sectorSize,
+		FirstUsableLBA: binary.LittleEndian.Uint64(hdr[40:]),
+		LastUsableLBA:  binary.LittleEndian.Uint64(hdr[48:]),
+		DiskGUID:       mixedEndian(hdr[56:72]),
+	}
+	entriesLBA := binary.LittleEndian.Uint64(hdr[72:])
+	count := binary.LittleEndian.Uint32(hdr[80:])
+	entrySize := binary.LittleEndian.Uint32(hdr[84:])
+	// Tables usually have 128 entries, refuse absurdly large ones.
+	if entrySize < 128 || entrySize%8 != 0 || count > 4096 || uint64(count)*uint64(entrySize) > 1<<20 {
+		return nil, fmt.Errorf("invalid GPT entry array of %d entries of %d bytes", count, entrySize)
+	}
+
+	entries := make([]byte, count*entrySize)
+	if _, err := r.ReadAt(entries, int64(entriesLBA)*int64(sectorSize)); err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(entries) != binary.LittleEndian.Uint32(hdr[88:]) {
+		return nil, errors.New("GPT partition entries checksum mismatch")
+	}
+
+	for i := uint32(0); i < count; i++ {
+		e := entries[i*entrySize : (i+1)*entrySize]
+		p := &Partition{
+			Number:   int(i) + 1,
+			Type:     mixedEndian(e[0:16]),
+			UUID:     mixedEndian(e[16:32]),
+			FirstLBA: binary.LittleEndian.Uint64(e[32:]),
+			LastLBA:  binary.LittleEndian.Uint64(e[40:]),
+			Flags:    binary.LittleEndian.Uint64(e[48:]),
+		}
+		if p.Type.IsNull() {
+			continue
+		}
+		if p.LastLBA < p.FirstLBA {
+			return nil, fmt.Errorf("partition %d ends before it starts", p.Number)
+		}
+		name := make([]uint16, 0, 36)
+		for j := 56; j+1 < 128; j += 2 {
+			c := binary.LittleEndian.Uint16(e[j:])
+			if c == 0 {
+				break
+			}
+			name = append(name, c)
+		}
+		p.Label = string(utf16.Decode(name))
+		p.Offset = int64(p.FirstLBA) * int64(sectorSize)
+		p.Size = int64(p.LastLBA-p.FirstLBA+1) * int64(sectorSize)
+		t.Partitions = append(t.Partitions, p)
+	}
+	return t, nil
+}
+
+// TypeInfo returns the Discoverable Partitions Specification type of the
+// partition, if it has one.
+func (p *Partition) TypeInfo() (Type, bool) {
+	return LookupType(p.Type)
+}
+
+// Section returns a reader for the contents of the partition.
+func (p *Partition) Section(r io.ReaderAt) *io.SectionReader {
+	return io.NewSectionReader(r, p.Offset, p.Size)
+}
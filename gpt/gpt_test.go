@@ -0,0 +1,151 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/coreos/go-systemd/v22/id128"
+)
+
+// image returns a disk image with a primary GUID partition table holding
+// the given partitions at their LBAs, in 128 entries.
+func image(sectorSize int, parts []Partition) []byte {
+	const count, entrySize = 128, 128
+	entriesLBA := 2
+	firstUsable := entriesLBA + count*entrySize/sectorSize
+	disk := make([]byte, (firstUsable+64)*sectorSize)
+
+	entries := disk[entriesLBA*sectorSize : entriesLBA*sectorSize+count*entrySize]
+	for i, p := range parts {
+		e := entries[i*entrySize:]
+		copy(e[0:], toDisk(p.Type))
+		copy(e[16:], toDisk(p.UUID))
+		binary.LittleEndian.PutUint64(e[32:], p.FirstLBA)
+		binary.LittleEndian.PutUint64(e[40:], p.LastLBA)
+		binary.LittleEndian.PutUint64(e[48:], p.Flags)
+		for j, c := range utf16.Encode([]rune(p.Label)) {
+			binary.LittleEndian.PutUint16(e[56+2*j:], c)
+		}
+	}
+
+	hdr := disk[sectorSize:]
+	copy(hdr, "EFI PART")
+	binary.LittleEndian.PutUint32(hdr[8:], 0x10000)
+	binary.LittleEndian.PutUint32(hdr[12:], 92)
+	binary.LittleEndian.PutUint64(hdr[24:], 1)
+	binary.LittleEndian.PutUint64(hdr[40:], uint64(firstUsable))
+	binary.LittleEndian.PutUint64(hdr[48:], uint64(len(disk)/sectorSize-1))
+	copy(hdr[56:], toDisk(id128.MustParse("11111111-2222-3333-4444-555555555555")))
+	binary.LittleEndian.PutUint64(hdr[72:], uint64(entriesLBA))
+	binary.LittleEndian.PutUint32(hdr[80:], count)
+	binary.LittleEndian.PutUint32(hdr[84:], entrySize)
+	binary.LittleEndian.PutUint32(hdr[88:], crc32.ChecksumIEEE(entries))
+	binary.LittleEndian.PutUint32(hdr[16:], crc32.ChecksumIEEE(hdr[:92]))
+	return disk
+}
+
+func toDisk(id id128.ID) []byte {
+	b := mixedEndian(id[:])
+	return b[:]
+}
+
+func TestRead(t *testing.T) {
+	root := id128.MustParse("4f68bce3-e8cd-4db1-96e7-fbcaf984b709")
+	uuid := id128.MustParse("01234567-89ab-cdef-0123-456789abcdef")
+	for _, sectorSize := range []int{512, 4096} {
+		disk := image(sectorSize, []Partition{
+			{},
+			{Type: root, UUID: uuid, FirstLBA: 40, LastLBA: 49, Flags: FlagReadOnly, Label: "root-ü"},
+		})
+		tab, err := Read(bytes.NewReader(disk))
+		if err != nil {
+			t.Fatalf("sector size %d: %v", sectorSize, err)
+		}
+		if tab.SectorSize != sectorSize || tab.DiskGUID.UUIDString() != "11111111-2222-3333-4444-555555555555" {
+			t.Errorf("sector size %d: unexpected table %+v", sectorSize, tab)
+		}
+		if len(tab.Partitions) != 1 {
+			t.Fatalf("sector size %d: got %d partitions", sectorSize, len(tab.Partitions))
+		}
+		p := tab.Partitions[0]
+		want := Partition{
+			Number: 2, Type: root, UUID: uuid, FirstLBA: 40, LastLBA: 49, Flags: FlagReadOnly, Label: "root-ü",
+			Offset: 40 * int64(sectorSize), Size: 10 * int64(sectorSize),
+		}
+		if *p != want {
+			t.Errorf("sector size %d: got %+v", sectorSize, p)
+		}
+		if typ, ok := p.TypeInfo(); !ok || typ.String() != "root-x86-64" {
+			t.Errorf("sector size %d: unexpected type %v", sectorSize, typ)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	if _, err := Read(bytes.NewReader(make([]byte, 8192))); err != ErrNoTable {
+		t.Errorf("unexpected error for empty disk: %v", err)
+	}
+	if _, err := Read(bytes.NewReader(nil)); err != ErrNoTable {
+		t.Errorf("unexpected error for short disk: %v", err)
+	}
+
+	parts := []Partition{{Type: id128.MustParse("0657fd6d-a4ab-43c4-84e5-0933c84b4f4f"), FirstLBA: 40, LastLBA: 41}}
+	disk := image(512, parts)
+	disk[512+24]++
+	if _, err := Read(bytes.NewReader(disk)); err == nil || err == ErrNoTable {
+		t.Errorf("unexpected error for corrupted header: %v", err)
+	}
+	disk = image(512, parts)
+	disk[1024]++
+	if _, err := Read(bytes.NewReader(disk)); err == nil || err == ErrNoTable {
+		t.Errorf("unexpected error for corrupted entries: %v", err)
+	}
+	for _, sectorSize := range []int{0, -512, 256, 768} {
+		if _, err := ReadSectorSize(bytes.NewReader(disk), sectorSize); err == nil || err == ErrNoTable {
+			t.Errorf("unexpected error for sector size %d: %v", sectorSize, err)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gpt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "disk.img")
+	disk := image(512, []Partition{{Type: id128.MustParse("c12a7328-f81f-11d2-ba4b-00a0c93ec93b"), FirstLBA: 34, LastLBA: 35}})
+	copy(disk[34*512:], "hello")
+	if err := ioutil.WriteFile(path, disk, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tab, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, 5)
+	if _, err := tab.Partitions[0].Section(bytes.NewReader(disk)).Read(b); err != nil || string(b) != "hello" {
+		t.Errorf("unexpected partition contents %q, %v", b, err)
+	}
+}
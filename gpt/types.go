@@ -0,0 +1,273 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gpt
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/coreos/go-systemd/v22/id128"
+)
+
+// Designator is the purpose of a partition in the Discoverable Partitions
+// Specification, named like the Type= values of repart.d.
+type Designator string
+
+const (
+	ESP           Designator = "esp"
+	XBOOTLDR      Designator = "xbootldr"
+	Swap          Designator = "swap"
+	Home          Designator = "home"
+	Srv           Designator = "srv"
+	Var           Designator = "var"
+	Tmp           Designator = "tmp"
+	UserHome      Designator = "user-home"
+	LinuxGeneric  Designator = "linux-generic"
+	Root          Designator = "root"
+	RootVerity    Designator = "root-verity"
+	RootVeritySig Designator = "root-verity-sig"
+	Usr           Designator = "usr"
+	UsrVerity     Designator = "usr-verity"
+	UsrVeritySig  Designator = "usr-verity-sig"
+)
+
+// Arch is a CPU architecture as named by systemd, e.g. in
+// ConditionArchitecture=.
+type Arch string
+
+const (
+	ArchAlpha       Arch = "alpha"
+	ArchARC         Arch = "arc"
+	ArchARM         Arch = "arm"
+	ArchARM64       Arch = "arm64"
+	ArchIA64        Arch = "ia64"
+	ArchLoongArch64 Arch = "loongarch64"
+	ArchMIPSLE      Arch = "mips-le"
+	ArchMIPS64LE    Arch = "mips64-le"
+	ArchPPC         Arch = "ppc"
+	ArchPPC64       Arch = "ppc64"
+	ArchPPC64LE     Arch = "ppc64-le"
+	ArchRISCV32     Arch = "riscv32"
+	ArchRISCV64     Arch = "riscv64"
+	ArchS390        Arch = "s390"
+	ArchS390X       Arch = "s390x"
+	ArchTILEGX      Arch = "tilegx"
+	ArchX86         Arch = "x86"
+	ArchX86_64      Arch = "x86-64"
+)
+
+var goArches = map[string]Arch{
+	"386":      ArchX86,
+	"amd64":    ArchX86_64,
+	"arm":      ArchARM,
+	"arm64":    ArchARM64,
+	"loong64":  ArchLoongArch64,
+	"mipsle":   ArchMIPSLE,
+	"mips64le": ArchMIPS64LE,
+	"ppc64":    ArchPPC64,
+	"ppc64le":  ArchPPC64LE,
+	"riscv64":  ArchRISCV64,
+	"s390x":    ArchS390X,
+}
+
+// NativeArch returns the architecture the program was built for, or "" if
+// the specification defines no partition types for it.
+func NativeArch() Arch {
+	return goArches[runtime.GOARCH]
+}
+
+// secondaryArches are the architectures whose root and usr partitions are
+// used when there are none for the native one, as systemd does.
+var secondaryArches = map[Arch]Arch{
+	ArchX86_64: ArchX86,
+	ArchARM64:  ArchARM,
+}
+
+// Type is a partition type of the Discoverable Partitions Specification.
+type Type struct {
+	Designator Designator
+	Arch       Arch // Only set for the root and usr types
+	UUID       id128.ID
+}
+
+// String returns the name of the type, e.g. "root-x86-64".
+func (t Type) String() string {
+	if t.Arch == "" {
+		return string(t.Designator)
+	}
+	return string(t.Designator) + "-" + string(t.Arch)
+}
+
+// Types are the partition types of the Discoverable Partitions
+// Specification.  See
+// https://uapi-group.org/specifications/specs/discoverable_partitions_specification/
+var Types = []Type{
+	{ESP, "", id128.MustParse("c12a7328-f81f-11d2-ba4b-00a0c93ec93b")},
+	{XBOOTLDR, "", id128.MustParse("bc13c2ff-59e6-4262-a352-b275fd6f7172")},
+	{Swap, "", id128.MustParse("0657fd6d-a4ab-43c4-84e5-0933c84b4f4f")},
+	{Home, "", id128.MustParse("933ac7e1-2eb4-4f13-b844-0e14e2aef915")},
+	{Srv, "", id128.MustParse("3b8f8425-20e0-4f3b-907f-1a25a76f98e8")},
+	{Var, "", id128.MustParse("4d21b016-b534-45c2-a9fb-5c16e091fd2d")},
+	{Tmp, "", id128.MustParse("7ec6f557-3bc5-4aca-b293-16ef5df639d1")},
+	{UserHome, "", id128.MustParse("773f91ef-66d4-49b5-bd83-d683bf40ad16")},
+	{LinuxGeneric, "", id128.MustParse("0fc63daf-8483-4772-8e79-3d69d8477de4")},
+	{Root, ArchARC, id128.MustParse("d27f46ed-2919-4cb8-bd25-9531f3c16534")},
+	{Root, ArchARM, id128.MustParse("69dad710-2ce4-4e3c-b16c-21a1d49abed3")},
+	{Root, ArchARM64, id128.MustParse("b921b045-1df0-41c3-af44-4c6f280d3fae")},
+	{Root, ArchAlpha, id128.MustParse("6523f8ae-3eb1-4e2a-a05a-18b695ae656f")},
+	{Root, ArchIA64, id128.MustParse("993d8d3d-f80e-4225-855a-9daf8ed7ea97")},
+	{Root, ArchLoongArch64, id128.MustParse("77055800-792c-4f94-b39a-98c91b762bb6")},
+	{Root, ArchMIPS64LE, id128.MustParse("700bda43-7a34-4507-b179-eeb93d7a7ca3")},
+	{Root, ArchMIPSLE, id128.MustParse("37c58c8a-d913-4156-a25f-48b1b64e07f0")},
+	{Root, ArchPPC, id128.MustParse("1de3f1ef-fa98-47b5-8dcd-4a860a654d78")},
+	{Root, ArchPPC64, id128.MustParse("912ade1d-a839-4913-8964-a10eee08fbd2")},
+	{Root, ArchPPC64LE, id128.MustParse("c31c45e6-3f39-412e-80fb-4809c4980599")},
+	{Root, ArchRISCV32, id128.MustParse("60d5a7fe-8e7d-435c-b714-3dd8162144e1")},
+	{Root, ArchRISCV64, id128.MustParse("72ec70a6-cf74-40e6-bd49-4bda08e8f224")},
+	{Root, ArchS390, id128.MustParse("08a7acea-624c-4a20-91e8-6e0fa67d23f9")},
+	{Root, ArchS390X, id128.MustParse("5eead9a9-fe09-4a1e-a1d7-520d00531306")},
+	{Root, ArchTILEGX, id128.MustParse("c50cdd70-3862-4cc3-90e1-809a8c93ee2c")},
+	{Root, ArchX86, id128.MustParse("44479540-f297-41b2-9af7-d131d5f0458a")},
+	{Root, ArchX86_64, id128.MustParse("4f68bce3-e8cd-4db1-96e7-fbcaf984b709")},
+	{RootVerity, ArchARC, id128.MustParse("24b2d975-0f97-4521-afa1-cd531e421b8d")},
+	{RootVerity, ArchARM, id128.MustParse("7386cdf2-203c-47a9-a498-f2ecce45a2d6")},
+	{RootVerity, ArchARM64, id128.MustParse("df3300ce-d69f-4c92-978c-9bfb0f38d820")},
+	{RootVerity, ArchAlpha, id128.MustParse("fc56d9e9-e6e5-4c06-be32-e74407ce09a5")},
+	{RootVerity, ArchIA64, id128.MustParse("86ed10d5-b607-45bb-8957-d350f23d0571")},
+	{RootVerity, ArchLoongArch64, id128.MustParse("f3393b22-e9af-4613-a948-9d3bfbd0c535")},
+	{RootVerity, ArchMIPS64LE, id128.MustParse("16b417f8-3e06-4f57-8dd2-9b5232f41aa6")},
+	{RootVerity, ArchMIPSLE, id128.MustParse("d7d150d2-2a04-4a33-8f12-16651205ff7b")},
+	{RootVerity, ArchPPC, id128.MustParse("98cfe649-1588-46dc-b2f0-add147424925")},
+	{RootVerity, ArchPPC64, id128.MustParse("9225a9a3-3c19-4d89-b4f6-eeff88f17631")},
+	{RootVerity, ArchPPC64LE, id128.MustParse("906bd944-4589-4aae-a4e4-dd983917446a")},
+	{RootVerity, ArchRISCV32, id128.MustParse("ae0253be-1167-4007-ac68-43926c14c5de")},
+	{RootVerity, ArchRISCV64, id128.MustParse("b6ed5582-440b-4209-b8da-5ff7c419ea3d")},
+	{RootVerity, ArchS390, id128.MustParse("7ac63b47-b25c-463b-8df8-b4a94e6c90e1")},
+	{RootVerity, ArchS390X, id128.MustParse("b325bfbe-c7be-4ab8-8357-139e652d2f6b")},
+	{RootVerity, ArchTILEGX, id128.MustParse("966061ec-28e4-4b2e-b4a5-1f0a825a1d84")},
+	{RootVerity, ArchX86, id128.MustParse("d13c5d3b-b5d1-422a-b29f-9454fdc89d76")},
+	{RootVerity, ArchX86_64, id128.MustParse("2c7357ed-ebd2-46d9-aec1-23d437ec2bf5")},
+	{RootVeritySig, ArchARC, id128.MustParse("143a70ba-cbd3-4f06-919f-6c05683a78bc")},
+	{RootVeritySig, ArchARM, id128.MustParse("42b0455f-eb11-491d-98d3-56145ba9d037")},
+	{RootVeritySig, ArchARM64, id128.MustParse("6db69de6-29f4-4758-a7a5-962190f00ce3")},
+	{RootVeritySig, ArchAlpha, id128.MustParse("d46495b7-a053-414f-80f7-700c99921ef8")},
+	{RootVeritySig, ArchIA64, id128.MustParse("e98b36ee-32ba-4882-9b12-0ce14655f46a")},
+	{RootVeritySig, ArchLoongArch64, id128.MustParse("5afb67eb-ecc8-4f85-ae8e-ac1e7c50e7d0")},
+	{RootVeritySig, ArchMIPS64LE, id128.MustParse("904e58ef-5c65-4a31-9c57-6af5fc7c5de7")},
+	{RootVeritySig, ArchMIPSLE, id128.MustParse("c919cc1f-4456-4eff-918c-f75e94525ca5")},
+	{RootVeritySig, ArchPPC, id128.MustParse("1b31b5aa-add9-463a-b2ed-bd467fc857e7")},
+	{RootVeritySig, ArchPPC64, id128.MustParse("f5e2c20c-45b2-4ffa-bce9-2a60737e1aaf")},
+	{RootVeritySig, ArchPPC64LE, id128.MustParse("d4a236e7-e873-4c07-bf1d-bf6cf7f1c3c6")},
+	{RootVeritySig, ArchRISCV32, id128.MustParse("3a112a75-8729-4380-b4cf-764d79934448")},
+	{RootVeritySig, ArchRISCV64, id128.MustParse("efe0f087-ea8d-4469-821a-4c2a96a8386a")},
+	{RootVeritySig, ArchS390, id128.MustParse("3482388e-4254-435a-a241-766a065f9960")},
+	{RootVeritySig, ArchS390X, id128.MustParse("c80187a5-73a3-491a-901a-017c3fa953e9")},
+	{RootVeritySig, ArchTILEGX, id128.MustParse("b3671439-97b0-4a53-90f7-2d5a8f3ad47b")},
+	{RootVeritySig, ArchX86, id128.MustParse("5996fc05-109c-48de-808b-23fa0830b676")},
+	{RootVeritySig, ArchX86_64, id128.MustParse("41092b05-9fc8-4523-994f-2def0408b176")},
+	{Usr, ArchARC, id128.MustParse("7978a683-6316-4922-bbee-38bff5a2fecc")},
+	{Usr, ArchARM, id128.MustParse("7d0359a3-02b3-4f0a-865c-654403e70625")},
+	{Usr, ArchARM64, id128.MustParse("b0e01050-ee5f-4390-949a-9101b17104e9")},
+	{Usr, ArchAlpha, id128.MustParse("e18cf08c-33ec-4c0d-8246-c6c6fb3da024")},
+	{Usr, ArchIA64, id128.MustParse("4301d2a6-4e3b-4b2a-bb94-9e0b2c4225ea")},
+	{Usr, ArchLoongArch64, id128.MustParse("e611c702-575c-4cbe-9a46-434fa0bf7e3f")},
+	{Usr, ArchMIPS64LE, id128.MustParse("c97c1f32-ba06-40b4-9f22-236061b08aa8")},
+	{Usr, ArchMIPSLE, id128.MustParse("0f4868e9-9952-4706-979f-3ed3a473e947")},
+	{Usr, ArchPPC, id128.MustParse("7d14fec5-cc71-415d-9d6c-06bf0b3c3eaf")},
+	{Usr, ArchPPC64, id128.MustParse("2c9739e2-f068-46b3-9fd0-01c5a9afbcca")},
+	{Usr, ArchPPC64LE, id128.MustParse("15bb03af-77e7-4d4a-b12b-c0d084f7491c")},
+	{Usr, ArchRISCV32, id128.MustParse("b933fb22-5c3f-4f91-af90-e2bb0fa50702")},
+	{Usr, ArchRISCV64, id128.MustParse("beaec34b-8442-439b-a40b-984381ed097d")},
+	{Usr, ArchS390, id128.MustParse("cd0f869b-d0fb-4ca0-b141-9ea87cc78d66")},
+	{Usr, ArchS390X, id128.MustParse("8a4f5770-50aa-4ed3-874a-99b710db6fea")},
+	{Usr, ArchTILEGX, id128.MustParse("55497029-c7c1-44cc-aa39-815ed1558630")},
+	{Usr, ArchX86, id128.MustParse("75250d76-8cc6-458e-bd66-bd47cc81a812")},
+	{Usr, ArchX86_64, id128.MustParse("8484680c-9521-48c6-9c11-b0720656f69e")},
+	{UsrVerity, ArchARC, id128.MustParse("fca0598c-d880-4591-8c16-4eda05c7347c")},
+	{UsrVerity, ArchARM, id128.MustParse("c215d751-7bcd-4649-be90-6627490a4c05")},
+	{UsrVerity, ArchARM64, id128.MustParse("6e11a4e7-fbca-4ded-b9e9-e1a512bb664e")},
+	{UsrVerity, ArchAlpha, id128.MustParse("8cce0d25-c0d0-4a44-bd87-46331bf1df67")},
+	{UsrVerity, ArchIA64, id128.MustParse("6a491e03-3be7-4545-8e38-83320e0ea880")},
+	{UsrVerity, ArchLoongArch64, id128.MustParse("f46b2c26-59ae-48f0-9106-c50ed47f673d")},
+	{UsrVerity, ArchMIPS64LE, id128.MustParse("3c3d61fe-b5f3-414d-bb71-8739a694a4ef")},
+	{UsrVerity, ArchMIPSLE, id128.MustParse("46b98d8d-b55c-4e8f-aab3-37fca7f80752")},
+	{UsrVerity, ArchPPC, id128.MustParse("df765d00-270e-49e5-bc75-f47bb2118b09")},
+	{UsrVerity, ArchPPC64, id128.MustParse("bdb528a5-a259-475f-a87d-da53fa736a07")},
+	{UsrVerity, ArchPPC64LE, id128.MustParse("ee2b9983-21e8-4153-86d9-b6901a54d1ce")},
+	{UsrVerity, ArchRISCV32, id128.MustParse("cb1ee4e3-8cd0-4136-a0a4-aa61a32e8730")},
+	{UsrVerity, ArchRISCV64, id128.MustParse("8f1056be-9b05-47c4-81d6-be53128e5b54")},
+	{UsrVerity, ArchS390, id128.MustParse("b663c618-e7bc-4d6d-90aa-11b756bb1797")},
+	{UsrVerity, ArchS390X, id128.MustParse("31741cc4-1a2a-4111-a581-e00b447d2d06")},
+	{UsrVerity, ArchTILEGX, id128.MustParse("2fb4bf56-07fa-42da-8132-6b139f2026ae")},
+	{UsrVerity, ArchX86, id128.MustParse("8f461b0d-14ee-4e81-9aa9-049b6fb97abd")},
+	{UsrVerity, ArchX86_64, id128.MustParse("77ff5f63-e7b6-4633-acf4-1565b864c0e6")},
+	{UsrVeritySig, ArchARC, id128.MustParse("94f9a9a1-9971-427a-a400-50cb297f0f35")},
+	{UsrVeritySig, ArchARM, id128.MustParse("d7ff812f-37d1-4902-a810-d76ba57b975a")},
+	{UsrVeritySig, ArchARM64, id128.MustParse("c23ce4ff-44bd-4b00-b2d4-b41b3419e02a")},
+	{UsrVeritySig, ArchAlpha, id128.MustParse("5c6e1c76-076a-457a-a0fe-f3b4cd21ce6e")},
+	{UsrVeritySig, ArchIA64, id128.MustParse("8de58bc2-2a43-460d-b14e-a76e4a17b47f")},
+	{UsrVeritySig, ArchLoongArch64, id128.MustParse("b024f315-d330-444c-8461-44bbde524e99")},
+	{UsrVeritySig, ArchMIPS64LE, id128.MustParse("f2c2c7ee-adcc-4351-b5c6-ee9816b66e16")},
+	{UsrVeritySig, ArchMIPSLE, id128.MustParse("3e23ca0b-a4bc-4b4e-8087-5ab6a26aa8a9")},
+	{UsrVeritySig, ArchPPC, id128.MustParse("7007891d-d371-4a80-86a4-5cb875b9302e")},
+	{UsrVeritySig, ArchPPC64, id128.MustParse("0b888863-d7f8-4d9e-9766-239fce4d58af")},
+	{UsrVeritySig, ArchPPC64LE, id128.MustParse("c8bfbd1e-268e-4521-8bba-bf314c399557")},
+	{UsrVeritySig, ArchRISCV32, id128.MustParse("c3836a13-3137-45ba-b583-b16c50fe5eb4")},
+	{UsrVeritySig, ArchRISCV64, id128.MustParse("d2f9000a-7a18-453f-b5cd-4d32f77a7b32")},
+	{UsrVeritySig, ArchS390, id128.MustParse("17440e4f-a8d0-467f-a46e-3912ae6ef2c5")},
+	{UsrVeritySig, ArchS390X, id128.MustParse("3f324816-667b-46ae-86ee-9b0c0c6c11b4")},
+	{UsrVeritySig, ArchTILEGX, id128.MustParse("4ede75e2-6ccc-4cc8-b9c7-70334b087510")},
+	{UsrVeritySig, ArchX86, id128.MustParse("974a71c0-de41-43c3-be5d-5c5ccd1ad2c0")},
+	{UsrVeritySig, ArchX86_64, id128.MustParse("e7bb33fb-06cf-4e81-8273-e543b413e2e2")},
+}
+
+// LookupType returns the type with the given GPT partition type UUID.
+func LookupType(uuid id128.ID) (Type, bool) {
+	for _, t := range Types {
+		if t.UUID == uuid {
+			return t, true
+		}
+	}
+	return Type{}, false
+}
+
+// TypeUUID returns the partition type UUID of d on arch, which is ignored
+// for types that are not specific to an architecture.
+func TypeUUID(d Designator, arch Arch) (id128.ID, bool) {
+	for _, t := range Types {
+		if t.Designator == d && (t.Arch == "" || t.Arch == arch) {
+			return t.UUID, true
+		}
+	}
+	return id128.Null, false
+}
+
+// ParseType parses a partition type name like "esp" or "root-arm64", or a
+// UUID. Root and usr types without an architecture suffix refer to the
+// native architecture.
+func ParseType(s string) (id128.ID, error) {
+	if id, err := id128.Parse(s); err == nil {
+		return id, nil
+	}
+	for _, t := range Types {
+		if s == t.String() {
+			return t.UUID, nil
+		}
+	}
+	if id, ok := TypeUUID(Designator(s), NativeArch()); ok {
+		return id, nil
+	}
+	return id128.Null, fmt.Errorf("unknown partition type %q", s)
+}
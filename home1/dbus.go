@@ -0,0 +1,354 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package home1 provides integration with the systemd-homed API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.home1.html
+package home1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/coreos/go-systemd/v22/userdb"
+)
+
+const (
+	dbusDest      = "org.freedesktop.home1"
+	dbusInterface = "org.freedesktop.home1.Manager"
+	dbusPath      = "/org/freedesktop/home1"
+)
+
+// Names of some of the D-Bus errors returned by systemd-homed. Callers
+// typically react to them by asking the user for a (different) secret and
+// retrying the request.
+const (
+	ErrorNoSuchHome            = "org.freedesktop.home1.NoSuchHome"
+	ErrorBadPassword           = "org.freedesktop.home1.BadPassword"
+	ErrorBadRecoveryKey        = "org.freedesktop.home1.BadRecoveryKey"
+	ErrorTokenPINNeeded        = "org.freedesktop.home1.TokenPinNeeded"
+	ErrorBadPasswordAndNoToken = "org.freedesktop.home1.BadPasswordAndNoToken"
+	ErrorHomeActive            = "org.freedesktop.home1.HomeActive"
+	ErrorHomeNotActive         = "org.freedesktop.home1.HomeNotActive"
+	ErrorHomeLocked            = "org.freedesktop.home1.HomeLocked"
+	ErrorHomeBusy              = "org.freedesktop.home1.HomeBusy"
+	ErrorBadSignature          = "org.freedesktop.home1.BadSignature"
+	ErrorHomeRecordSigned      = "org.freedesktop.home1.HomeRecordSigned"
+	ErrorHomeRecordDowngrade   = "org.freedesktop.home1.HomeRecordDowngrade"
+)
+
+// Home is a home directory managed by systemd-homed.
+type Home struct {
+	UserName string
+	UID      uint32
+	// State is one of unfixated, absent, inactive, dirty, activating,
+	// active, deactivating, locked, linger and a few transitional states.
+	State         string
+	GID           uint32
+	RealName      string
+	HomeDirectory string
+	Shell         string
+	Path          dbus.ObjectPath
+}
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+// ListHomes returns all home directories known to systemd-homed.
+func (c *Conn) ListHomes(ctx context.Context) ([]Home, error) {
+	var result [][]interface{}
+	if err := c.object.CallWithContext(ctx, dbusInterface+".ListHomes", 0).Store(&result); err != nil {
+		return nil, err
+	}
+
+	homes := []Home{}
+	for _, i := range result {
+		home, err := homeFromInterfaces(i)
+		if err != nil {
+			return nil, err
+		}
+		homes = append(homes, *home)
+	}
+	return homes, nil
+}
+
+// GetHomeByName returns the home directory of the user called name.
+func (c *Conn) GetHomeByName(ctx context.Context, name string) (*Home, error) {
+	home := &Home{UserName: name}
+	err := c.object.CallWithContext(ctx, dbusInterface+".GetHomeByName", 0, name).
+		Store(&home.UID, &home.State, &home.GID, &home.RealName, &home.HomeDirectory, &home.Shell, &home.Path)
+	if err != nil {
+		return nil, err
+	}
+	return home, nil
+}
+
+// GetHomeByUID returns the home directory of the user with the given UID.
+func (c *Conn) GetHomeByUID(ctx context.Context, uid uint32) (*Home, error) {
+	home := &Home{UID: uid}
+	err := c.object.CallWithContext(ctx, dbusInterface+".GetHomeByUID", 0, uid).
+		Store(&home.UserName, &home.State, &home.GID, &home.RealName, &home.HomeDirectory, &home.Shell, &home.Path)
+	if err != nil {
+		return nil, err
+	}
+	return home, nil
+}
+
+// GetUserRecordByName returns the user record of the user called name. The
+// privileged section is only included if the caller may see it, otherwise
+// the record is marked as incomplete.
+func (c *Conn) GetUserRecordByName(ctx context.Context, name string) (*userdb.UserRecord, error) {
+	return c.getUserRecord(ctx, "GetUserRecordByName", name)
+}
+
+// GetUserRecordByUID returns the user record of the user with the given UID,
+// like GetUserRecordByName.
+func (c *Conn) GetUserRecordByUID(ctx context.Context, uid uint32) (*userdb.UserRecord, error) {
+	return c.getUserRecord(ctx, "GetUserRecordByUID", uid)
+}
+
+func (c *Conn) getUserRecord(ctx context.Context, method string, arg interface{}) (*userdb.UserRecord, error) {
+	var (
+		record     string
+		incomplete bool
+		path       dbus.ObjectPath
+	)
+	if err := c.object.CallWithContext(ctx, dbusInterface+"."+method, 0, arg).Store(&record, &incomplete, &path); err != nil {
+		return nil, err
+	}
+
+	u := new(userdb.UserRecord)
+	if err := json.Unmarshal([]byte(record), u); err != nil {
+		return nil, err
+	}
+	u.Incomplete = incomplete
+	return u, nil
+}
+
+// ActivateHome unlocks and mounts the home directory of the user called
+// name, using secret to unlock it.
+func (c *Conn) ActivateHome(ctx context.Context, name string, secret *userdb.UserSecret) error {
+	s, err := secretJSON(secret)
+	if err != nil {
+		return err
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".ActivateHome", 0, name, s).Store()
+}
+
+// DeactivateHome unmounts the home directory of the user called name.
+func (c *Conn) DeactivateHome(ctx context.Context, name string) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".DeactivateHome", 0, name).Store()
+}
+
+// CreateHome creates a home directory for the user described by record. The
+// initial password or other secrets are passed in the record's secret
+// section.
+func (c *Conn) CreateHome(ctx context.Context, record *userdb.UserRecord) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".CreateHome", 0, string(b)).Store()
+}
+
+// RemoveHome removes the home directory and user record of the user called
+// name.
+func (c *Conn) RemoveHome(ctx context.Context, name string) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".RemoveHome", 0, name).Store()
+}
+
+// AuthenticateHome checks secret against the home directory of the user
+// called name, without activating it.
+func (c *Conn) AuthenticateHome(ctx context.Context, name string, secret *userdb.UserSecret) error {
+	s, err := secretJSON(secret)
+	if err != nil {
+		return err
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".AuthenticateHome", 0, name, s).Store()
+}
+
+// UpdateHome replaces the user record of a home directory with record. The
+// record must be signed by a key systemd-homed trusts, or be unsigned to
+// have homed sign it, and must not be older than the current record.
+// Secrets needed to update an inactive home directory are passed in the
+// record's secret section.
+func (c *Conn) UpdateHome(ctx context.Context, record *userdb.UserRecord) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".UpdateHome", 0, string(b)).Store()
+}
+
+// ResizeHome grows or shrinks the home directory of the user called name to
+// size bytes.
+func (c *Conn) ResizeHome(ctx context.Context, name string, size uint64, secret *userdb.UserSecret) error {
+	s, err := secretJSON(secret)
+	if err != nil {
+		return err
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".ResizeHome", 0, name, size, s).Store()
+}
+
+// ChangePasswordHome replaces the passwords of the user called name with
+// those in newSecret, authenticating with oldSecret.
+func (c *Conn) ChangePasswordHome(ctx context.Context, name string, newSecret, oldSecret *userdb.UserSecret) error {
+	n, err := secretJSON(newSecret)
+	if err != nil {
+		return err
+	}
+	o, err := secretJSON(oldSecret)
+	if err != nil {
+		return err
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".ChangePasswordHome", 0, name, n, o).Store()
+}
+
+// LockHome suspends access to the active home directory of the user called
+// name and removes its key from memory.
+func (c *Conn) LockHome(ctx context.Context, name string) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".LockHome", 0, name).Store()
+}
+
+// UnlockHome resumes access to a home directory locked with LockHome.
+func (c *Conn) UnlockHome(ctx context.Context, name string, secret *userdb.UserSecret) error {
+	s, err := secretJSON(secret)
+	if err != nil {
+		return err
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".UnlockHome", 0, name, s).Store()
+}
+
+// secretJSON encodes secret as the JSON object homed expects for secret
+// parameters, which is empty if no secret is given.
+func secretJSON(secret *userdb.UserSecret) (string, error) {
+	if secret == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(secret)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func homeFromInterfaces(home []interface{}) (*Home, error) {
+	if len(home) < 8 {
+		return nil, fmt.Errorf("invalid number of home fields: %d", len(home))
+	}
+	name, ok := home[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast home field 0 to string")
+	}
+	uid, ok := home[1].(uint32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast home field 1 to uint32")
+	}
+	state, ok := home[2].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast home field 2 to string")
+	}
+	gid, ok := home[3].(uint32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast home field 3 to uint32")
+	}
+	realName, ok := home[4].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast home field 4 to string")
+	}
+	homeDirectory, ok := home[5].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast home field 5 to string")
+	}
+	shell, ok := home[6].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast home field 6 to string")
+	}
+	path, ok := home[7].(dbus.ObjectPath)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast home field 7 to ObjectPath")
+	}
+	return &Home{
+		UserName:      name,
+		UID:           uid,
+		State:         state,
+		GID:           gid,
+		RealName:      realName,
+		HomeDirectory: homeDirectory,
+		Shell:         shell,
+		Path:          path,
+	}, nil
+}
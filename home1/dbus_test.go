@@ -0,0 +1,84 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package home1
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/coreos/go-systemd/v22/userdb"
+)
+
+// TestNew ensures that New() works without errors.
+func TestNew(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestSecretJSON(t *testing.T) {
+	tests := []struct {
+		secret *userdb.UserSecret
+		out    string
+	}{
+		{nil, `{}`},
+		{&userdb.UserSecret{}, `{}`},
+		{&userdb.UserSecret{Password: []string{"hunter2"}}, `{"password":["hunter2"]}`},
+		{&userdb.UserSecret{Password: []string{"a"}, TokenPIN: []string{"1234"}}, `{"password":["a"],"tokenPin":["1234"]}`},
+	}
+
+	for i, tt := range tests {
+		out, err := secretJSON(tt.secret)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("case %d: expected %s, got %s", i, tt.out, out)
+		}
+	}
+}
+
+func TestHomeFromInterfaces(t *testing.T) {
+	home, err := homeFromInterfaces([]interface{}{
+		"alice", uint32(60100), "active", uint32(60100), "Alice", "/home/alice", "/bin/bash", dbus.ObjectPath("/org/freedesktop/home1/home/alice"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Home{
+		UserName:      "alice",
+		UID:           60100,
+		State:         "active",
+		GID:           60100,
+		RealName:      "Alice",
+		HomeDirectory: "/home/alice",
+		Shell:         "/bin/bash",
+		Path:          "/org/freedesktop/home1/home/alice",
+	}
+	if *home != expected {
+		t.Errorf("expected %+v, got %+v", expected, *home)
+	}
+
+	if _, err := homeFromInterfaces([]interface{}{"alice", "60100"}); err == nil {
+		t.Error("expected error for short home")
+	}
+	if _, err := homeFromInterfaces([]interface{}{"alice", "60100", "", uint32(0), "", "", "", dbus.ObjectPath("/")}); err == nil {
+		t.Error("expected error for mistyped uid")
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hostname1 provides integration with the systemd hostnamed API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.hostname1.html
+package hostname1
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.hostname1"
+	dbusInterface = "org.freedesktop.hostname1"
+	dbusPath      = "/org/freedesktop/hostname1"
+)
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+// Note: systemd-hostnamed will be activated via D-Bus, we don't need to check service status.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+func (c *Conn) getStringProperty(property string) (string, error) {
+	v, err := c.object.GetProperty(dbusInterface + "." + property)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("failed to typecast %s property %s to string", property, v)
+	}
+	return s, nil
+}
+
+// SetHostname sets the transient hostname of the system, which is lost on
+// reboot. An empty hostname reverts it to the static hostname.
+func (c *Conn) SetHostname(hostname string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetHostname", 0, hostname, interactive).Err
+}
+
+// SetStaticHostname sets the static hostname stored in /etc/hostname.
+func (c *Conn) SetStaticHostname(hostname string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetStaticHostname", 0, hostname, interactive).Err
+}
+
+// SetPrettyHostname sets the free-form, human readable hostname stored in /etc/machine-info.
+func (c *Conn) SetPrettyHostname(hostname string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetPrettyHostname", 0, hostname, interactive).Err
+}
+
+// SetIconName sets the XDG icon name used to represent the machine.
+func (c *Conn) SetIconName(iconName string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetIconName", 0, iconName, interactive).Err
+}
+
+// SetChassis sets the chassis type, e.g. desktop, laptop, server, vm or container.
+// An empty chassis reverts to the detected one.
+func (c *Conn) SetChassis(chassis string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetChassis", 0, chassis, interactive).Err
+}
+
+// SetDeployment sets the deployment environment, e.g. development,
+// integration, staging or production.
+func (c *Conn) SetDeployment(deployment string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetDeployment", 0, deployment, interactive).Err
+}
+
+// SetLocation sets the free-form physical location of the system, e.g. a
+// rack or room number.
+func (c *Conn) SetLocation(location string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetLocation", 0, location, interactive).Err
+}
+
+// Hostname returns the current hostname of the system.
+func (c *Conn) Hostname() (string, error) {
+	return c.getStringProperty("Hostname")
+}
+
+// StaticHostname returns the static hostname stored in /etc/hostname.
+func (c *Conn) StaticHostname() (string, error) {
+	return c.getStringProperty("StaticHostname")
+}
+
+// PrettyHostname returns the human readable hostname stored in /etc/machine-info.
+func (c *Conn) PrettyHostname() (string, error) {
+	return c.getStringProperty("PrettyHostname")
+}
+
+// DefaultHostname returns the hostname used if no static hostname is set.
+// Note: Requires systemd v249 or higher
+func (c *Conn) DefaultHostname() (string, error) {
+	return c.getStringProperty("DefaultHostname")
+}
+
+// HostnameSource returns where the current hostname came from: static,
+// transient or default.
+// Note: Requires systemd v249 or higher
+func (c *Conn) HostnameSource() (string, error) {
+	return c.getStringProperty("HostnameSource")
+}
+
+// IconName returns the XDG icon name of the machine.
+func (c *Conn) IconName() (string, error) {
+	return c.getStringProperty("IconName")
+}
+
+// Chassis returns the chassis type of the machine.
+func (c *Conn) Chassis() (string, error) {
+	return c.getStringProperty("Chassis")
+}
+
+// Deployment returns the deployment environment of the machine.
+func (c *Conn) Deployment() (string, error) {
+	return c.getStringProperty("Deployment")
+}
+
+// Location returns the physical location of the machine.
+func (c *Conn) Location() (string, error) {
+	return c.getStringProperty("Location")
+}
+
+// HardwareVendor returns the hardware vendor as reported by the firmware.
+// Note: Requires systemd v249 or higher
+func (c *Conn) HardwareVendor() (string, error) {
+	return c.getStringProperty("HardwareVendor")
+}
+
+// HardwareModel returns the hardware model as reported by the firmware.
+// Note: Requires systemd v249 or higher
+func (c *Conn) HardwareModel() (string, error) {
+	return c.getStringProperty("HardwareModel")
+}
+
+// GetProductUUID returns the product UUID of the hardware as reported by the
+// firmware. Reading it requires privileges, which are asked for
+// interactively if requested.
+func (c *Conn) GetProductUUID(interactive bool) ([]byte, error) {
+	var uuid []byte
+	if err := c.object.Call(dbusInterface+".GetProductUUID", 0, interactive).Store(&uuid); err != nil {
+		return nil, err
+	}
+	return uuid, nil
+}
+
+// Describe returns a JSON object with all of the hostnamed properties.
+// Note: Requires systemd v249 or higher
+func (c *Conn) Describe() (string, error) {
+	var description string
+	if err := c.object.Call(dbusInterface+".Describe", 0).Store(&description); err != nil {
+		return "", err
+	}
+	return description, nil
+}
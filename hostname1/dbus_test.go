@@ -0,0 +1,78 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostname1
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// TestNew ensures that New() works without errors.
+func TestNew(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestHostname(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	hostname, err := conn.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := os.Hostname()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostname != expected {
+		t.Fatalf("expected hostname '%s' but got '%s'", expected, hostname)
+	}
+
+	if _, err := conn.StaticHostname(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.Chassis(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	description, err := conn.Describe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var props map[string]interface{}
+	if err := json.Unmarshal([]byte(description), &props); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := props["Hostname"]; !ok {
+		t.Fatalf("Hostname missing from description: %s", description)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostname1
+
+import (
+	"encoding/json"
+
+	"github.com/coreos/go-systemd/v22/internal/jsonfields"
+)
+
+// Description holds the hostnamed properties returned by Describe. Fields
+// only reported by newer versions of hostnamed are left empty on older ones.
+type Description struct {
+	Hostname        string `json:"Hostname"`
+	StaticHostname  string `json:"StaticHostname,omitempty"`
+	PrettyHostname  string `json:"PrettyHostname,omitempty"`
+	DefaultHostname string `json:"DefaultHostname,omitempty"`
+	// HostnameSource is one of static, transient or default.
+	HostnameSource string `json:"HostnameSource,omitempty"`
+	IconName       string `json:"IconName,omitempty"`
+	Chassis        string `json:"Chassis,omitempty"`
+	Deployment     string `json:"Deployment,omitempty"`
+	Location       string `json:"Location,omitempty"`
+
+	KernelName    string `json:"KernelName,omitempty"`
+	KernelRelease string `json:"KernelRelease,omitempty"`
+	KernelVersion string `json:"KernelVersion,omitempty"`
+
+	OperatingSystemPrettyName string `json:"OperatingSystemPrettyName,omitempty"`
+	OperatingSystemCPEName    string `json:"OperatingSystemCPEName,omitempty"`
+	OperatingSystemHomeURL    string `json:"OperatingSystemHomeURL,omitempty"`
+	// OperatingSystemSupportEnd is in microseconds since the epoch.
+	OperatingSystemSupportEnd uint64 `json:"OperatingSystemSupportEnd,omitempty"`
+
+	HardwareVendor  string `json:"HardwareVendor,omitempty"`
+	HardwareModel   string `json:"HardwareModel,omitempty"`
+	HardwareSerial  string `json:"HardwareSerial,omitempty"`
+	FirmwareVersion string `json:"FirmwareVersion,omitempty"`
+	FirmwareVendor  string `json:"FirmwareVendor,omitempty"`
+	// FirmwareDate is in microseconds since the epoch.
+	FirmwareDate uint64 `json:"FirmwareDate,omitempty"`
+
+	MachineID   string  `json:"MachineID,omitempty"`
+	BootID      string  `json:"BootID,omitempty"`
+	ProductUUID string  `json:"ProductUUID,omitempty"`
+	VSockCID    *uint32 `json:"VSockCID,omitempty"`
+
+	// Unknown holds the fields not covered above, such as those added by
+	// newer versions of hostnamed.
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Description) UnmarshalJSON(b []byte) error {
+	type plain Description
+	if err := json.Unmarshal(b, (*plain)(d)); err != nil {
+		return err
+	}
+	var err error
+	d.Unknown, err = jsonfields.Unknown(b, d)
+	return err
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Description) MarshalJSON() ([]byte, error) {
+	type plain Description
+	b, err := json.Marshal(plain(d))
+	if err != nil {
+		return nil, err
+	}
+	return jsonfields.Merge(b, d.Unknown)
+}
+
+// ParseDescription decodes the JSON object returned by Describe.
+func ParseDescription(description string) (*Description, error) {
+	d := &Description{}
+	if err := json.Unmarshal([]byte(description), d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Description returns the hostnamed properties, decoded from Describe.
+// Note: Requires systemd v249 or higher
+func (c *Conn) Description() (*Description, error) {
+	description, err := c.Describe()
+	if err != nil {
+		return nil, err
+	}
+	return ParseDescription(description)
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostname1
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const descriptionJSON = `{
+	"Hostname": "pc",
+	"StaticHostname": "pc",
+	"PrettyHostname": null,
+	"DefaultHostname": "localhost",
+	"HostnameSource": "static",
+	"IconName": "computer-laptop",
+	"Chassis": "laptop",
+	"KernelName": "Linux",
+	"KernelRelease": "6.8.0",
+	"OperatingSystemPrettyName": "Fedora Linux 40",
+	"OperatingSystemSupportEnd": 1747785600000000,
+	"HardwareVendor": "LENOVO",
+	"HardwareModel": "ThinkPad X1",
+	"FirmwareDate": 1700000000000000,
+	"MachineID": "0123456789abcdef0123456789abcdef",
+	"ProductUUID": null,
+	"VSockCID": 3,
+	"OperatingSystemReleaseData": ["ID=fedora", "VERSION_ID=40"]
+}`
+
+func TestParseDescription(t *testing.T) {
+	d, err := ParseDescription(descriptionJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Hostname != "pc" || d.HostnameSource != "static" || d.Chassis != "laptop" || d.PrettyHostname != "" {
+		t.Errorf("unexpected hostname fields %+v", d)
+	}
+	if d.HardwareVendor != "LENOVO" || d.FirmwareDate != 1700000000000000 || d.OperatingSystemSupportEnd != 1747785600000000 {
+		t.Errorf("unexpected hardware fields %+v", d)
+	}
+	if d.VSockCID == nil || *d.VSockCID != 3 || d.ProductUUID != "" {
+		t.Errorf("unexpected VSockCID %v or ProductUUID %q", d.VSockCID, d.ProductUUID)
+	}
+	if len(d.Unknown) != 1 || string(d.Unknown["OperatingSystemReleaseData"]) != `["ID=fedora", "VERSION_ID=40"]` {
+		t.Errorf("unexpected unknown fields %v", d.Unknown)
+	}
+
+	out, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := ParseDescription(string(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.MachineID != d.MachineID || len(again.Unknown) != 1 {
+		t.Errorf("unexpected description after round trip %+v", again)
+	}
+
+	if _, err := ParseDescription("[]"); err == nil {
+		t.Error("expected error for non-object")
+	}
+}
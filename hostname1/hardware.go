@@ -0,0 +1,105 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostname1
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/id128"
+	"github.com/coreos/go-systemd/v22/unit"
+	"github.com/godbus/dbus/v5"
+)
+
+var (
+	// ErrAuthorizationRequired is returned by ProductUUID and
+	// HardwareSerial if reading them requires privileges the caller lacks,
+	// and interactive authorization was not requested or did not succeed.
+	ErrAuthorizationRequired = errors.New("authorization required")
+	// ErrNotAvailable is returned by ProductUUID and HardwareSerial if the
+	// firmware does not report the value.
+	ErrNotAvailable = errors.New("not reported by the firmware")
+)
+
+// hardwareError maps the dbus errors of the privileged hostnamed methods to
+// ErrAuthorizationRequired and ErrNotAvailable.
+func hardwareError(err error) error {
+	e, ok := err.(dbus.Error)
+	if !ok {
+		return err
+	}
+	switch e.Name {
+	case "org.freedesktop.DBus.Error.InteractiveAuthorizationRequired", "org.freedesktop.DBus.Error.AccessDenied":
+		return ErrAuthorizationRequired
+	case "org.freedesktop.hostname1.NoProductUUID", "org.freedesktop.hostname1.NoHardwareSerial":
+		return ErrNotAvailable
+	}
+	return err
+}
+
+// ProductUUID is like GetProductUUID, but returns the UUID as an ID, and
+// ErrAuthorizationRequired or ErrNotAvailable if it cannot be read.
+func (c *Conn) ProductUUID(interactive bool) (id128.ID, error) {
+	b, err := c.GetProductUUID(interactive)
+	if err != nil {
+		return id128.ID{}, hardwareError(err)
+	}
+	var id id128.ID
+	if len(b) != len(id) {
+		return id128.ID{}, fmt.Errorf("invalid product UUID length %d", len(b))
+	}
+	copy(id[:], b)
+	return id, nil
+}
+
+// HardwareSerial returns the serial number of the hardware as reported by
+// the firmware, with ErrAuthorizationRequired or ErrNotAvailable if it
+// cannot be read like ProductUUID.
+// Note: Requires systemd v254 or higher
+func (c *Conn) HardwareSerial(interactive bool) (string, error) {
+	var serial string
+	if err := c.object.Call(dbusInterface+".GetHardwareSerial", 0, interactive).Store(&serial); err != nil {
+		return "", hardwareError(err)
+	}
+	return serial, nil
+}
+
+// FirmwareVersion returns the version of the firmware.
+// Note: Requires systemd v253 or higher
+func (c *Conn) FirmwareVersion() (string, error) {
+	return c.getStringProperty("FirmwareVersion")
+}
+
+// FirmwareVendor returns the vendor of the firmware.
+// Note: Requires systemd v254 or higher
+func (c *Conn) FirmwareVendor() (string, error) {
+	return c.getStringProperty("FirmwareVendor")
+}
+
+// FirmwareDate returns the release date of the firmware, the zero time if
+// it is not known.
+// Note: Requires systemd v254 or higher
+func (c *Conn) FirmwareDate() (time.Time, error) {
+	v, err := c.object.GetProperty(dbusInterface + ".FirmwareDate")
+	if err != nil {
+		return time.Time{}, err
+	}
+	usec, ok := v.Value().(uint64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("failed to typecast FirmwareDate property %s to uint64", v)
+	}
+	return unit.TimestampFromUsec(usec), nil
+}
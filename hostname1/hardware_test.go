@@ -0,0 +1,54 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostname1
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestHardwareError(t *testing.T) {
+	for i, tt := range []struct {
+		name string
+		want error
+	}{
+		{"org.freedesktop.DBus.Error.InteractiveAuthorizationRequired", ErrAuthorizationRequired},
+		{"org.freedesktop.DBus.Error.AccessDenied", ErrAuthorizationRequired},
+		{"org.freedesktop.hostname1.NoProductUUID", ErrNotAvailable},
+		{"org.freedesktop.hostname1.NoHardwareSerial", ErrNotAvailable},
+	} {
+		if err := hardwareError(dbus.Error{Name: tt.name}); err != tt.want {
+			t.Errorf("case %d: got %v, want %v", i, err, tt.want)
+		}
+	}
+	other := dbus.Error{Name: "org.freedesktop.DBus.Error.UnknownMethod"}
+	if err := hardwareError(other); err == ErrAuthorizationRequired || err == ErrNotAvailable {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestProductUUID(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	_, err = conn.ProductUUID(false)
+	if err != nil && err != ErrAuthorizationRequired && err != ErrNotAvailable {
+		t.Fatal(err)
+	}
+}
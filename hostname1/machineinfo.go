@@ -0,0 +1,77 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostname1
+
+import (
+	"io"
+	"os"
+
+	"github.com/coreos/go-systemd/v22/sysext"
+)
+
+// MachineInfoPath is the machine-info(5) file hostnamed stores the pretty
+// hostname and the other local machine metadata in.
+const MachineInfoPath = "/etc/machine-info"
+
+// MachineInfo holds the fields of a machine-info(5) file, which override
+// the values hostnamed detects.
+type MachineInfo struct {
+	PrettyHostname string // PRETTY_HOSTNAME
+	IconName       string // ICON_NAME
+	Chassis        string // CHASSIS
+	Deployment     string // DEPLOYMENT
+	Location       string // LOCATION
+	HardwareVendor string // HARDWARE_VENDOR
+	HardwareModel  string // HARDWARE_MODEL
+
+	// Fields holds all fields of the file, including the ones above.
+	Fields map[string]string
+}
+
+// ParseMachineInfo parses a machine-info(5) file.
+func ParseMachineInfo(r io.Reader) (*MachineInfo, error) {
+	fields, err := sysext.ParseRelease(r)
+	if err != nil {
+		return nil, err
+	}
+	return &MachineInfo{
+		PrettyHostname: fields["PRETTY_HOSTNAME"],
+		IconName:       fields["ICON_NAME"],
+		Chassis:        fields["CHASSIS"],
+		Deployment:     fields["DEPLOYMENT"],
+		Location:       fields["LOCATION"],
+		HardwareVendor: fields["HARDWARE_VENDOR"],
+		HardwareModel:  fields["HARDWARE_MODEL"],
+		Fields:         fields,
+	}, nil
+}
+
+// ReadMachineInfo reads the machine-info(5) file at path, MachineInfoPath
+// if empty, without going through hostnamed. A missing file yields an empty
+// MachineInfo, as it does for hostnamed.
+func ReadMachineInfo(path string) (*MachineInfo, error) {
+	if path == "" {
+		path = MachineInfoPath
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &MachineInfo{Fields: map[string]string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseMachineInfo(f)
+}
@@ -0,0 +1,84 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostname1
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMachineInfo(t *testing.T) {
+	info, err := ParseMachineInfo(strings.NewReader(`# set by hostnamectl
+PRETTY_HOSTNAME="Lennart's Laptop"
+ICON_NAME=computer-laptop
+CHASSIS=laptop
+DEPLOYMENT=production
+LOCATION="Berlin, rack 3"
+HARDWARE_VENDOR=ACME
+HARDWARE_MODEL='Model 1'
+KERNEL_NAME=Linux
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := MachineInfo{
+		PrettyHostname: "Lennart's Laptop",
+		IconName:       "computer-laptop",
+		Chassis:        "laptop",
+		Deployment:     "production",
+		Location:       "Berlin, rack 3",
+		HardwareVendor: "ACME",
+		HardwareModel:  "Model 1",
+	}
+	want.Fields = info.Fields
+	if !reflect.DeepEqual(*info, want) {
+		t.Errorf("got %+v, want %+v", *info, want)
+	}
+	if len(info.Fields) != 8 || info.Fields["KERNEL_NAME"] != "Linux" {
+		t.Errorf("unexpected fields %v", info.Fields)
+	}
+}
+
+func TestReadMachineInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "machine-info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "machine-info")
+	info, err := ReadMachineInfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.PrettyHostname != "" || len(info.Fields) != 0 {
+		t.Errorf("unexpected info for missing file %+v", info)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("CHASSIS=server\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err = ReadMachineInfo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Chassis != "server" {
+		t.Errorf("unexpected chassis %q", info.Chassis)
+	}
+}
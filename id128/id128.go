@@ -0,0 +1,190 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package id128 provides the 128-bit IDs systemd uses to identify machines,
+// boots, unit invocations and journal messages, like sd-id128 does. See
+// https://www.freedesktop.org/software/systemd/man/sd-id128.html
+package id128
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// The files the IDs are read from, variables so they can be changed by tests.
+var (
+	machineIDFile = "/etc/machine-id"
+	bootIDFile    = "/proc/sys/kernel/random/boot_id"
+)
+
+// ErrNoInvocationID is returned by GetInvocationID outside of a unit.
+var ErrNoInvocationID = errors.New("no invocation ID set")
+
+// ID is a 128-bit ID.
+type ID [16]byte
+
+// Null is the ID with all bits unset, which is not a valid ID for most uses.
+var Null ID
+
+// Parse parses an ID in its plain form of 32 hexadecimal digits, or in UUID
+// form with dashes.
+func Parse(s string) (ID, error) {
+	var id ID
+	hexa := s
+	if len(s) == 36 {
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return Null, fmt.Errorf("invalid ID %q", s)
+		}
+		hexa = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	}
+	if len(hexa) != 32 {
+		return Null, fmt.Errorf("invalid ID %q", s)
+	}
+	if _, err := hex.Decode(id[:], []byte(hexa)); err != nil {
+		return Null, fmt.Errorf("invalid ID %q", s)
+	}
+	return id, nil
+}
+
+// MustParse is like Parse but panics if s is not a valid ID. It is meant for
+// IDs known at compile time, like message IDs.
+func MustParse(s string) ID {
+	id, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// String returns the plain form of the ID, as used in journal fields.
+func (id ID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// UUIDString returns the ID in UUID form, e.g. as used for the boot ID in
+// /proc.
+func (id ID) UUIDString() string {
+	s := id.String()
+	return s[:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:]
+}
+
+// IsNull returns whether all bits of the ID are unset.
+func (id ID) IsNull() bool {
+	return id == Null
+}
+
+// MarshalText implements encoding.TextMarshaler using the plain form.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepting both forms.
+func (id *ID) UnmarshalText(b []byte) error {
+	parsed, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// makeV4 marks the ID as a random (version 4) UUID, as systemd does for all
+// the IDs it generates.
+func makeV4(id ID) ID {
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return id
+}
+
+// Randomize returns a new random ID.
+func Randomize() (ID, error) {
+	var id ID
+	if _, err := rand.Read(id[:]); err != nil {
+		return Null, err
+	}
+	return makeV4(id), nil
+}
+
+func readID(path string) (ID, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Null, err
+	}
+	id, err := Parse(strings.TrimSpace(string(b)))
+	if err != nil {
+		return Null, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if id.IsNull() {
+		return Null, fmt.Errorf("%s is null", path)
+	}
+	return id, nil
+}
+
+// GetMachineID returns the machine ID from /etc/machine-id. On the first
+// boot, before systemd initialized it, ErrMachineIDUninitialized is
+// returned.
+func GetMachineID() (ID, error) {
+	return ReadMachineIDFile(machineIDFile)
+}
+
+// GetBootID returns the ID of the current boot.
+func GetBootID() (ID, error) {
+	return readID(bootIDFile)
+}
+
+// GetInvocationID returns the invocation ID of the unit the process runs in,
+// which systemd passes in $INVOCATION_ID.
+func GetInvocationID() (ID, error) {
+	e := os.Getenv("INVOCATION_ID")
+	if e == "" {
+		return Null, ErrNoInvocationID
+	}
+	return Parse(e)
+}
+
+// AppSpecific derives an ID specific to app from base, so that base itself,
+// e.g. the machine ID, does not need to be exposed to the network. It is
+// computed like sd_id128_get_machine_app_specific does.
+func AppSpecific(base, app ID) ID {
+	mac := hmac.New(sha256.New, base[:])
+	mac.Write(app[:])
+	var id ID
+	copy(id[:], mac.Sum(nil))
+	return makeV4(id)
+}
+
+// GetMachineAppSpecific returns the machine ID specific to app.
+func GetMachineAppSpecific(app ID) (ID, error) {
+	id, err := GetMachineID()
+	if err != nil {
+		return Null, err
+	}
+	return AppSpecific(id, app), nil
+}
+
+// GetBootAppSpecific returns the boot ID specific to app.
+func GetBootAppSpecific(app ID) (ID, error) {
+	id, err := GetBootID()
+	if err != nil {
+		return Null, err
+	}
+	return AppSpecific(id, app), nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package id128
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		out  string
+		uuid string
+	}{
+		{"8f6c2ae3bc2e4c5da06d9c2bd3cfe8f1", "8f6c2ae3bc2e4c5da06d9c2bd3cfe8f1", "8f6c2ae3-bc2e-4c5d-a06d-9c2bd3cfe8f1"},
+		{"8F6C2AE3-BC2E-4C5D-A06D-9C2BD3CFE8F1", "8f6c2ae3bc2e4c5da06d9c2bd3cfe8f1", "8f6c2ae3-bc2e-4c5d-a06d-9c2bd3cfe8f1"},
+		{"00000000000000000000000000000000", "00000000000000000000000000000000", "00000000-0000-0000-0000-000000000000"},
+	}
+
+	for i, tt := range tests {
+		id, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if id.String() != tt.out || id.UUIDString() != tt.uuid {
+			t.Errorf("case %d: expected %s and %s, got %s and %s", i, tt.out, tt.uuid, id.String(), id.UUIDString())
+		}
+	}
+
+	for i, tt := range []string{
+		"",
+		"8f6c2ae3bc2e4c5da06d9c2bd3cfe8f",
+		"8f6c2ae3bc2e4c5da06d9c2bd3cfe8fg",
+		"8f6c2ae3-bc2e-4c5d-a06d9c2bd3cfe8f1",
+		"8f6c2ae3_bc2e_4c5d_a06d_9c2bd3cfe8f1",
+		"uninitialized",
+	} {
+		if _, err := Parse(tt); err == nil {
+			t.Errorf("case %d: expected error for %q", i, tt)
+		}
+	}
+}
+
+func TestRandomize(t *testing.T) {
+	a, err := Randomize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Randomize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b || a.IsNull() {
+		t.Errorf("expected distinct random IDs, got %s and %s", a, b)
+	}
+	if a[6]>>4 != 4 || a[8]>>6 != 2 {
+		t.Errorf("expected a v4 UUID, got %s", a.UUIDString())
+	}
+}
+
+func TestAppSpecific(t *testing.T) {
+	base := MustParse("7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d")
+	app := MustParse("0e73f1d8ac0d4f07a9c5c3e3bb1c2d7e")
+	id := AppSpecific(base, app)
+	if id == AppSpecific(base, MustParse("0e73f1d8ac0d4f07a9c5c3e3bb1c2d7f")) {
+		t.Error("expected different IDs for different apps")
+	}
+	if id != AppSpecific(base, app) {
+		t.Error("expected app specific ID to be stable")
+	}
+	if id[6]>>4 != 4 || id[8]>>6 != 2 {
+		t.Errorf("expected a v4 UUID, got %s", id.UUIDString())
+	}
+}
+
+func TestGetIDs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-id128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(m, b string) { machineIDFile, bootIDFile = m, b }(machineIDFile, bootIDFile)
+	machineIDFile = filepath.Join(dir, "machine-id")
+	bootIDFile = filepath.Join(dir, "boot_id")
+
+	if err := ioutil.WriteFile(machineIDFile, []byte("7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(bootIDFile, []byte("8f6c2ae3-bc2e-4c5d-a06d-9c2bd3cfe8f1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if id, err := GetMachineID(); err != nil || id.String() != "7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d" {
+		t.Errorf("unexpected machine ID %s, %v", id, err)
+	}
+	if id, err := GetBootID(); err != nil || id.String() != "8f6c2ae3bc2e4c5da06d9c2bd3cfe8f1" {
+		t.Errorf("unexpected boot ID %s, %v", id, err)
+	}
+	app := MustParse("0e73f1d8ac0d4f07a9c5c3e3bb1c2d7e")
+	if id, err := GetMachineAppSpecific(app); err != nil || id != AppSpecific(MustParse("7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d"), app) {
+		t.Errorf("unexpected app specific machine ID %s, %v", id, err)
+	}
+
+	if err := ioutil.WriteFile(machineIDFile, []byte("uninitialized\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetMachineID(); err == nil {
+		t.Error("expected error for uninitialized machine ID")
+	}
+
+	os.Setenv("INVOCATION_ID", "0e73f1d8ac0d4f07a9c5c3e3bb1c2d7e")
+	defer os.Unsetenv("INVOCATION_ID")
+	if id, err := GetInvocationID(); err != nil || id != app {
+		t.Errorf("unexpected invocation ID %s, %v", id, err)
+	}
+	os.Unsetenv("INVOCATION_ID")
+	if _, err := GetInvocationID(); err != ErrNoInvocationID {
+		t.Errorf("expected ErrNoInvocationID, got %v", err)
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	var v struct{ ID ID }
+	if err := json.Unmarshal([]byte(`{"ID":"8f6c2ae3-bc2e-4c5d-a06d-9c2bd3cfe8f1"}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"ID":"8f6c2ae3bc2e4c5da06d9c2bd3cfe8f1"}` {
+		t.Errorf("unexpected JSON %s", b)
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package id128
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/cmdline"
+)
+
+// ErrMachineIDUninitialized is returned for a machine ID file that is empty
+// or contains "uninitialized", which systemd treats as the first boot of
+// the system.
+var ErrMachineIDUninitialized = errors.New("machine ID is uninitialized")
+
+// The sources consulted by AcquireMachineID, variables so they can be changed
+// by tests.
+var (
+	dmiDir             = "/sys/class/dmi/id"
+	deviceTreeUUIDFile = "/proc/device-tree/vm,uuid"
+	pid1EnvironFile    = "/proc/1/environ"
+	containerFile      = "/run/systemd/container"
+)
+
+// MachineIDSource describes where a machine ID returned by AcquireMachineID
+// came from.
+type MachineIDSource string
+
+const (
+	// MachineIDSourceCommandLine is the systemd.machine_id= kernel command
+	// line parameter.
+	MachineIDSourceCommandLine MachineIDSource = "command line"
+	// MachineIDSourceContainer is the container_uuid environment variable
+	// passed by the container manager.
+	MachineIDSourceContainer MachineIDSource = "container"
+	// MachineIDSourceFirmware is the product UUID of the virtual machine.
+	MachineIDSourceFirmware MachineIDSource = "firmware"
+	// MachineIDSourceRandom is a newly generated random ID.
+	MachineIDSourceRandom MachineIDSource = "random"
+)
+
+// ReadMachineIDFile reads and validates a machine ID file, which must hold
+// the ID in plain form followed by an optional newline. An empty file or
+// one containing "uninitialized" yields ErrMachineIDUninitialized.
+func ReadMachineIDFile(path string) (ID, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Null, err
+	}
+	b = bytes.TrimSuffix(b, []byte("\n"))
+	if len(b) == 0 || string(b) == "uninitialized" {
+		return Null, ErrMachineIDUninitialized
+	}
+	if len(b) != 32 {
+		return Null, fmt.Errorf("invalid machine ID in %s", path)
+	}
+	id, err := Parse(string(b))
+	if err != nil || id.IsNull() {
+		return Null, fmt.Errorf("invalid machine ID in %s", path)
+	}
+	return id, nil
+}
+
+// WriteMachineIDFile atomically writes id to path in the format systemd
+// expects, readable by everyone and writable by no one.
+func WriteMachineIDFile(path string, id ID) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(id.String() + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0444); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// IsFirstBoot returns whether the system below root boots for the first
+// time, as ConditionFirstBoot= does: /etc/machine-id is missing, empty or
+// uninitialized.
+func IsFirstBoot(root string) (bool, error) {
+	_, err := ReadMachineIDFile(filepath.Join(root, "etc/machine-id"))
+	switch {
+	case err == nil:
+		return false, nil
+	case err == ErrMachineIDUninitialized || os.IsNotExist(err):
+		return true, nil
+	}
+	return false, err
+}
+
+// AcquireMachineID returns a machine ID for the running system from the same
+// sources systemd consults when /etc/machine-id is not initialized: the
+// systemd.machine_id= kernel command line parameter, the container UUID
+// passed by the container manager, the product UUID of a virtual machine
+// and finally a random ID.
+func AcquireMachineID() (ID, MachineIDSource, error) {
+	if c, err := cmdline.Read(); err == nil {
+		if v, ok := c.Value("systemd.machine_id"); ok {
+			if v == "firmware" {
+				if id, err := productUUID(); err == nil {
+					return id, MachineIDSourceFirmware, nil
+				}
+			} else if id, err := Parse(v); err == nil && !id.IsNull() {
+				return id, MachineIDSourceCommandLine, nil
+			}
+		}
+	}
+
+	if inContainer() {
+		if id, err := containerUUID(); err == nil {
+			return id, MachineIDSourceContainer, nil
+		}
+	} else if inVM() {
+		if id, err := productUUID(); err == nil {
+			return id, MachineIDSourceFirmware, nil
+		}
+	}
+
+	id, err := Randomize()
+	if err != nil {
+		return Null, "", err
+	}
+	return id, MachineIDSourceRandom, nil
+}
+
+// SetupMachineID makes sure /etc/machine-id below root holds a valid machine
+// ID and returns it, like systemd-machine-id-setup. A missing or
+// uninitialized ID is replaced by one from AcquireMachineID if root is the
+// running system, or a random one otherwise.
+func SetupMachineID(root string) (ID, error) {
+	path := filepath.Join(root, "etc/machine-id")
+	id, err := ReadMachineIDFile(path)
+	if err == nil {
+		return id, nil
+	}
+	if err != ErrMachineIDUninitialized && !os.IsNotExist(err) {
+		return Null, err
+	}
+
+	if root == "" || filepath.Clean(root) == "/" {
+		id, _, err = AcquireMachineID()
+	} else {
+		id, err = Randomize()
+	}
+	if err != nil {
+		return Null, err
+	}
+	if err := WriteMachineIDFile(path, id); err != nil {
+		return Null, err
+	}
+	return id, nil
+}
+
+func inContainer() bool {
+	if os.Getenv("container") != "" {
+		return true
+	}
+	_, err := os.Stat(containerFile)
+	return err == nil
+}
+
+// inVM returns whether the system is a virtual machine whose firmware
+// provides a system UUID, using the DMI vendor strings as systemd-detect-virt
+// does when CPUID is inconclusive.
+func inVM() bool {
+	for _, name := range []string{"product_name", "sys_vendor", "board_vendor", "bios_vendor"} {
+		b, err := ioutil.ReadFile(filepath.Join(dmiDir, name))
+		if err != nil {
+			continue
+		}
+		v := string(b)
+		for _, vendor := range []string{"KVM", "QEMU", "Amazon EC2", "Xen", "BHYVE"} {
+			if strings.HasPrefix(v, vendor) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// productUUID returns the system UUID of the virtual machine.
+func productUUID() (ID, error) {
+	for _, path := range []string{filepath.Join(dmiDir, "product_uuid"), deviceTreeUUIDFile} {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		id, err := Parse(strings.TrimRight(string(b), "\x00\n"))
+		if err != nil {
+			return Null, err
+		}
+		if id.IsNull() || id == (ID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}) {
+			return Null, errors.New("product UUID is not set")
+		}
+		return id, nil
+	}
+	return Null, errors.New("no product UUID available")
+}
+
+// containerUUID returns the container_uuid the container manager passed to
+// PID 1.
+func containerUUID() (ID, error) {
+	v := ""
+	if os.Getpid() == 1 {
+		v = os.Getenv("container_uuid")
+	} else if b, err := ioutil.ReadFile(pid1EnvironFile); err == nil {
+		for _, e := range strings.Split(string(b), "\x00") {
+			if strings.HasPrefix(e, "container_uuid=") {
+				v = strings.TrimPrefix(e, "container_uuid=")
+			}
+		}
+	}
+	if v == "" {
+		return Null, errors.New("no container UUID available")
+	}
+	id, err := Parse(v)
+	if err != nil {
+		return Null, err
+	}
+	if id.IsNull() {
+		return Null, errors.New("container UUID is not set")
+	}
+	return id, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package id128
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadMachineIDFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-id128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "machine-id")
+
+	tests := []struct {
+		content string
+		id      string
+		err     error
+	}{
+		{"7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d\n", "7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d", nil},
+		{"7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d", "7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d", nil},
+		{"", "", ErrMachineIDUninitialized},
+		{"uninitialized\n", "", ErrMachineIDUninitialized},
+	}
+
+	for i, tt := range tests {
+		if err := ioutil.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		id, err := ReadMachineIDFile(path)
+		if err != tt.err {
+			t.Errorf("case %d: expected error %v, got %v", i, tt.err, err)
+			continue
+		}
+		if err == nil && id.String() != tt.id {
+			t.Errorf("case %d: expected %s, got %s", i, tt.id, id)
+		}
+	}
+
+	for i, tt := range []string{
+		"00000000000000000000000000000000\n",
+		"7c9a1d2e-5d9a-4e1f-8b6f-0c3d2a1b4c5d\n",
+		"7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d\n\n",
+		"7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5\n",
+	} {
+		if err := ioutil.WriteFile(path, []byte(tt), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadMachineIDFile(path); err == nil || err == ErrMachineIDUninitialized {
+			t.Errorf("case %d: expected invalid machine ID error, got %v", i, err)
+		}
+	}
+}
+
+func TestSetupMachineID(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-systemd-id128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	if err := os.Mkdir(filepath.Join(root, "etc"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if first, err := IsFirstBoot(root); err != nil || !first {
+		t.Errorf("expected first boot without machine ID, got %v, %v", first, err)
+	}
+
+	id, err := SetupMachineID(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(root, "etc/machine-id")
+	read, err := ReadMachineIDFile(path)
+	if err != nil || read != id {
+		t.Errorf("expected %s to be written, got %s, %v", id, read, err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0444 {
+		t.Errorf("unexpected machine ID file mode: %v, %v", fi.Mode(), err)
+	}
+	if first, err := IsFirstBoot(root); err != nil || first {
+		t.Errorf("expected no first boot with machine ID, got %v, %v", first, err)
+	}
+
+	again, err := SetupMachineID(root)
+	if err != nil || again != id {
+		t.Errorf("expected existing machine ID %s to be kept, got %s, %v", id, again, err)
+	}
+}
+
+func TestAcquireMachineID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-id128")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d, e, c string) { dmiDir, pid1EnvironFile, containerFile = d, e, c }(dmiDir, pid1EnvironFile, containerFile)
+	dmiDir = dir
+	pid1EnvironFile = filepath.Join(dir, "environ")
+	containerFile = filepath.Join(dir, "container")
+	defer os.Unsetenv("SYSTEMD_PROC_CMDLINE")
+	defer os.Setenv("container", os.Getenv("container"))
+	os.Unsetenv("container")
+
+	write := func(name, content string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("sys_vendor", "QEMU\n")
+	write("product_uuid", "8f6c2ae3-bc2e-4c5d-a06d-9c2bd3cfe8f1\n")
+	write("environ", "PATH=/bin\x00container_uuid=0e73f1d8-ac0d-4f07-a9c5-c3e3bb1c2d7e\x00")
+
+	tests := []struct {
+		cmdline   string
+		container bool
+		id        string
+		source    MachineIDSource
+	}{
+		{"quiet systemd.machine_id=7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d", false, "7c9a1d2e5d9a4e1f8b6f0c3d2a1b4c5d", MachineIDSourceCommandLine},
+		{"systemd.machine_id=firmware", true, "8f6c2ae3bc2e4c5da06d9c2bd3cfe8f1", MachineIDSourceFirmware},
+		{"quiet", false, "8f6c2ae3bc2e4c5da06d9c2bd3cfe8f1", MachineIDSourceFirmware},
+		{"quiet", true, "0e73f1d8ac0d4f07a9c5c3e3bb1c2d7e", MachineIDSourceContainer},
+	}
+
+	for i, tt := range tests {
+		os.Setenv("SYSTEMD_PROC_CMDLINE", tt.cmdline)
+		os.Remove(containerFile)
+		if tt.container {
+			write("container", "docker\n")
+		}
+		id, source, err := AcquireMachineID()
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if id.String() != tt.id || source != tt.source {
+			t.Errorf("case %d: expected %s from %s, got %s from %s", i, tt.id, tt.source, id, source)
+		}
+	}
+
+	os.Setenv("SYSTEMD_PROC_CMDLINE", "")
+	write("sys_vendor", "Dell Inc.\n")
+	os.Remove(containerFile)
+	if id, source, err := AcquireMachineID(); err != nil || source != MachineIDSourceRandom || id.IsNull() {
+		t.Errorf("expected random machine ID, got %s from %s, %v", id, source, err)
+	}
+}
@@ -17,6 +17,7 @@
 package import1
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -26,8 +27,23 @@ import (
 )
 
 const (
-	dbusInterface = "org.freedesktop.import1.Manager"
-	dbusPath      = "/org/freedesktop/import1"
+	dbusInterface         = "org.freedesktop.import1.Manager"
+	dbusTransferInterface = "org.freedesktop.import1.Transfer"
+	dbusPath              = "/org/freedesktop/import1"
+)
+
+// Verification modes for PullTar and PullRaw.
+const (
+	VerifyNo        = "no"        // Do not verify the download
+	VerifyChecksum  = "checksum"  // Verify the SHA256SUMS file published alongside the image
+	VerifySignature = "signature" // Also verify the GPG signature of the SHA256SUMS file
+)
+
+// Transfer results, as reported by TransferEvent.Result.
+const (
+	TransferDone     = "done"
+	TransferFailed   = "failed"
+	TransferCanceled = "canceled"
 )
 
 // Conn is a connection to systemds dbus endpoint.
@@ -140,6 +156,14 @@ func (c *Conn) ImportRaw(
 	return c.getResult("ImportRaw", dbus.UnixFD(f.Fd()), local_name, force, read_only)
 }
 
+// ImportFileSystem imports a directory tree into systemd-importd.
+// Note: Requires systemd v239 or higher
+func (c *Conn) ImportFileSystem(
+	f *os.File, local_name string, force, read_only bool,
+) (*Transfer, error) {
+	return c.getResult("ImportFileSystem", dbus.UnixFD(f.Fd()), local_name, force, read_only)
+}
+
 // ExportTar exports a tar from systemd-importd.
 func (c *Conn) ExportTar(
 	local_name string, f *os.File, format string,
@@ -192,6 +216,112 @@ func (c *Conn) CancelTransfer(transfer_id uint32) error {
 	return c.object.Call(dbusInterface+".CancelTransfer", 0, transfer_id).Err
 }
 
+// GetTransferProgress returns the current progress of a transfer, as a value between 0.0 and 1.0.
+func (c *Conn) GetTransferProgress(path dbus.ObjectPath) (float64, error) {
+	var progress dbus.Variant
+	obj := c.conn.Object("org.freedesktop.import1", path)
+	if err := obj.Call("org.freedesktop.DBus.Properties.Get", 0, dbusTransferInterface, "Progress").Store(&progress); err != nil {
+		return 0, err
+	}
+	p, ok := progress.Value().(float64)
+	if !ok {
+		return 0, fmt.Errorf("unable to convert Progress property '%v' to float64", progress)
+	}
+	return p, nil
+}
+
+// TransferEvent is emitted when a transfer is started or removed.
+type TransferEvent struct {
+	Transfer
+	New    bool   // Whether the transfer was started, as opposed to removed
+	Result string // The result of a removed transfer, one of the Transfer* result constants
+}
+
+// SubscribeTransfers returns a channel receiving an event whenever a
+// transfer is started or removed. The channel is closed when ctx is done or
+// the connection is closed.
+func (c *Conn) SubscribeTransfers(ctx context.Context) (<-chan TransferEvent, error) {
+	opts := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(dbusPath),
+		dbus.WithMatchInterface(dbusInterface),
+	}
+	if err := c.conn.AddMatchSignalContext(ctx, opts...); err != nil {
+		return nil, err
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	c.conn.Signal(signals)
+
+	events := make(chan TransferEvent)
+	go func() {
+		defer close(events)
+		defer c.conn.RemoveMatchSignal(opts...)
+		defer c.conn.RemoveSignal(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig, ok := <-signals:
+				if !ok {
+					return
+				}
+				event, err := transferEventFromSignal(sig)
+				if err != nil || event == nil {
+					continue
+				}
+				select {
+				case events <- *event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// transferEventFromSignal returns nil for signals other than TransferNew and TransferRemoved.
+func transferEventFromSignal(sig *dbus.Signal) (*TransferEvent, error) {
+	if sig.Path != dbusPath {
+		return nil, nil
+	}
+
+	event := &TransferEvent{}
+	switch sig.Name {
+	case dbusInterface + ".TransferNew":
+		event.New = true
+	case dbusInterface + ".TransferRemoved":
+		if len(sig.Body) < 3 {
+			return nil, fmt.Errorf("invalid number of TransferRemoved fields: %d", len(sig.Body))
+		}
+		result, ok := sig.Body[2].(string)
+		if !ok {
+			return nil, fmt.Errorf("failed to typecast TransferRemoved field 2 to string")
+		}
+		event.Result = result
+	default:
+		return nil, nil
+	}
+
+	if len(sig.Body) < 2 {
+		return nil, fmt.Errorf("invalid number of transfer signal fields: %d", len(sig.Body))
+	}
+	id, ok := sig.Body[0].(uint32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast transfer signal field 0 to uint32")
+	}
+	path, ok := sig.Body[1].(dbus.ObjectPath)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast transfer signal field 1 to ObjectPath")
+	}
+	event.Id = id
+	event.Path = path
+
+	return event, nil
+}
+
 func transferFromInterfaces(transfer []interface{}) (*TransferStatus, error) {
 	// Verify may be not defined in response.
 	if len(transfer) < 5 {
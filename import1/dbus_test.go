@@ -15,12 +15,17 @@
 package import1
 
 import (
+	"context"
+	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
 )
 
 const (
@@ -61,6 +66,33 @@ func TestImportRaw(t *testing.T) {
 	}
 }
 
+func TestImportFileSystem(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", importPrefix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello"), []byte("world"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	_, err = conn.ImportFileSystem(f, importPrefix+"ImportFileSystem", true, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestExportTar(t *testing.T) {
 	conn, err := New()
 	if err != nil {
@@ -157,6 +189,70 @@ func TestListAndCancelTransfers(t *testing.T) {
 	}
 }
 
+func TestSubscribeTransfers(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	events, err := conn.SubscribeTransfers(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfer, err := conn.PullTar("http://127.0.0.1:8080/image.tar.xz", importPrefix+"SubscribeTransfers", VerifyNo, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for event := range events {
+		if event.Id != transfer.Id || event.New {
+			continue
+		}
+		if event.Result != TransferDone {
+			t.Fatalf("expected transfer result %q but got %q", TransferDone, event.Result)
+		}
+		return
+	}
+	t.Fatal("transfer was not removed")
+}
+
+func TestTransferEventFromSignal(t *testing.T) {
+	event, err := transferEventFromSignal(&dbus.Signal{
+		Path: dbusPath,
+		Name: dbusInterface + ".TransferRemoved",
+		Body: []interface{}{uint32(7), dbus.ObjectPath("/org/freedesktop/import1/transfer/_7"), "failed"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event == nil || event.New || event.Id != 7 || event.Path != "/org/freedesktop/import1/transfer/_7" || event.Result != TransferFailed {
+		t.Fatalf("unexpected transfer event %+v", event)
+	}
+
+	event, err = transferEventFromSignal(&dbus.Signal{
+		Path: dbusPath,
+		Name: dbusInterface + ".TransferNew",
+		Body: []interface{}{uint32(8), dbus.ObjectPath("/org/freedesktop/import1/transfer/_8")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event == nil || !event.New || event.Id != 8 {
+		t.Fatalf("unexpected transfer event %+v", event)
+	}
+
+	event, err = transferEventFromSignal(&dbus.Signal{
+		Path: dbusPath,
+		Name: "org.freedesktop.DBus.Properties.PropertiesChanged",
+	})
+	if err != nil || event != nil {
+		t.Fatalf("unrelated signal reported as transfer event: %+v %v", event, err)
+	}
+}
+
 func findFixture(target string, t *testing.T) string {
 	abs, err := filepath.Abs("../fixtures/" + target)
 	if err != nil {
@@ -0,0 +1,225 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package import1
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// progressInterval is how often the progress of a tracked transfer is
+// polled, since importd does not signal changes of it.
+const progressInterval = 500 * time.Millisecond
+
+var (
+	// ErrTransferFailed is returned by TransferProgress.Wait if importd
+	// reported the transfer as failed.
+	ErrTransferFailed = errors.New("transfer failed")
+	// ErrTransferCanceled is returned by TransferProgress.Wait if the
+	// transfer was canceled by someone else than the tracker.
+	ErrTransferCanceled = errors.New("transfer canceled")
+)
+
+// Progress is a progress report of a tracked transfer.
+type Progress struct {
+	// Fraction is the progress reported by importd, between 0.0 and 1.0.
+	Fraction float64
+	// Bytes is how much of the file passed to the transfer was read by an
+	// import or written by an export, -1 without a file or if it is not a
+	// regular file.
+	Bytes int64
+	// Size is the size of the imported file, -1 if it is not known.
+	Size int64
+	// Speed is the average number of bytes transferred per second, 0 if
+	// Bytes is not known.
+	Speed float64
+	// Elapsed is the time since the transfer was started.
+	Elapsed time.Duration
+	// Remaining is the estimated time until the transfer is done, 0 while
+	// no progress was made.
+	Remaining time.Duration
+}
+
+// Percent returns the progress as a percentage.
+func (p Progress) Percent() float64 {
+	return p.Fraction * 100
+}
+
+// TransferProgress tracks a transfer started with Track until it ends.
+type TransferProgress struct {
+	Transfer
+
+	conn    *Conn
+	file    *os.File
+	export  bool
+	started time.Time
+	updates chan Progress
+	done    chan struct{}
+	err     error
+}
+
+// Track starts a transfer with start, such as a call of PullRaw or
+// ImportTar, and tracks its progress until it ends. f is the file passed to
+// an import or export, whose offset or size tells how many bytes were
+// transferred, and may be nil. Signals are subscribed to before start is
+// called, so that the end of short transfers is not missed.
+//
+// Cancelling ctx cancels the transfer with CancelTransfer.
+func (c *Conn) Track(ctx context.Context, f *os.File, start func() (*Transfer, error)) (*TransferProgress, error) {
+	subCtx, stop := context.WithCancel(context.Background())
+	events, err := c.SubscribeTransfers(subCtx)
+	if err != nil {
+		stop()
+		return nil, err
+	}
+	t, err := start()
+	if err != nil {
+		stop()
+		return nil, err
+	}
+
+	p := &TransferProgress{
+		Transfer: *t,
+		conn:     c,
+		file:     f,
+		started:  time.Now(),
+		updates:  make(chan Progress, 1),
+		done:     make(chan struct{}),
+	}
+	var typ dbus.Variant
+	obj := c.conn.Object("org.freedesktop.import1", t.Path)
+	if err := obj.Call("org.freedesktop.DBus.Properties.Get", 0, dbusTransferInterface, "Type").Store(&typ); err == nil {
+		s, _ := typ.Value().(string)
+		p.export = strings.HasPrefix(s, "export-")
+	}
+
+	go p.run(ctx, stop, events)
+	return p, nil
+}
+
+// Progress returns a channel receiving the progress of the transfer. A
+// receiver that falls behind only gets the latest report. The channel is
+// closed when the transfer ended, after a last report if it succeeded.
+func (p *TransferProgress) Progress() <-chan Progress {
+	return p.updates
+}
+
+// Done returns a channel that is closed when the transfer ended.
+func (p *TransferProgress) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits for the transfer to end. It returns nil if it succeeded,
+// ErrTransferFailed or ErrTransferCanceled if importd reported otherwise,
+// and the error of the context passed to Track if it was canceled through
+// it.
+func (p *TransferProgress) Wait() error {
+	<-p.done
+	return p.err
+}
+
+func (p *TransferProgress) run(ctx context.Context, stop func(), events <-chan TransferEvent) {
+	defer stop()
+	defer close(p.done)
+	defer close(p.updates)
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+
+	ctxDone := ctx.Done()
+	var ctxErr error
+	for {
+		select {
+		case <-ctxDone:
+			ctxDone = nil
+			ctxErr = ctx.Err()
+			// The transfer may have ended meanwhile, which is reported
+			// by its removal like a successful cancellation.
+			p.conn.CancelTransfer(p.Id)
+		case event, ok := <-events:
+			if !ok {
+				p.err = errors.New("connection closed")
+				return
+			}
+			if event.New || event.Id != p.Id {
+				continue
+			}
+			switch {
+			case ctxErr != nil:
+				p.err = ctxErr
+			case event.Result == TransferDone:
+				p.publish(p.sample(1))
+			case event.Result == TransferCanceled:
+				p.err = ErrTransferCanceled
+			default:
+				p.err = ErrTransferFailed
+			}
+			return
+		case <-ticker.C:
+			fraction, err := p.conn.GetTransferProgress(p.Path)
+			if err != nil {
+				// The transfer ended, its removal follows.
+				continue
+			}
+			p.publish(p.sample(fraction))
+		}
+	}
+}
+
+// publish sends a progress report, replacing the one not received yet.
+func (p *TransferProgress) publish(progress Progress) {
+	for {
+		select {
+		case p.updates <- progress:
+			return
+		default:
+		}
+		select {
+		case <-p.updates:
+		default:
+		}
+	}
+}
+
+func (p *TransferProgress) sample(fraction float64) Progress {
+	bytes, size := int64(-1), int64(-1)
+	if p.file != nil {
+		if fi, err := p.file.Stat(); err == nil && fi.Mode().IsRegular() {
+			if p.export {
+				bytes = fi.Size()
+			} else if off, err := p.file.Seek(0, io.SeekCurrent); err == nil {
+				bytes, size = off, fi.Size()
+			}
+		}
+	}
+	return makeProgress(fraction, bytes, size, time.Since(p.started))
+}
+
+func makeProgress(fraction float64, bytes, size int64, elapsed time.Duration) Progress {
+	p := Progress{Fraction: fraction, Bytes: bytes, Size: size, Elapsed: elapsed}
+	if bytes > 0 && elapsed > 0 {
+		p.Speed = float64(bytes) / elapsed.Seconds()
+	}
+	if fraction > 0 && fraction < 1 {
+		p.Remaining = time.Duration(float64(elapsed) * (1 - fraction) / fraction)
+	}
+	return p
+}
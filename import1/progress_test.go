@@ -0,0 +1,92 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package import1
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMakeProgress(t *testing.T) {
+	for i, tt := range []struct {
+		fraction  float64
+		bytes     int64
+		elapsed   time.Duration
+		speed     float64
+		remaining time.Duration
+	}{
+		{0, -1, time.Second, 0, 0},
+		{0.25, 1000, 2 * time.Second, 500, 6 * time.Second},
+		{0.5, -1, 10 * time.Second, 0, 10 * time.Second},
+		{1, 4000, 4 * time.Second, 1000, 0},
+	} {
+		p := makeProgress(tt.fraction, tt.bytes, 4000, tt.elapsed)
+		if p.Speed != tt.speed || p.Remaining != tt.remaining || p.Bytes != tt.bytes || p.Size != 4000 {
+			t.Errorf("case %d: unexpected progress %+v", i, p)
+		}
+	}
+	if pct := (Progress{Fraction: 0.125}).Percent(); pct != 12.5 {
+		t.Errorf("unexpected percentage %v", pct)
+	}
+}
+
+func TestPublish(t *testing.T) {
+	p := &TransferProgress{updates: make(chan Progress, 1)}
+	p.publish(Progress{Fraction: 0.1})
+	p.publish(Progress{Fraction: 0.2})
+	if got := <-p.updates; got.Fraction != 0.2 {
+		t.Errorf("got fraction %v, want the latest one", got.Fraction)
+	}
+	select {
+	case got := <-p.updates:
+		t.Errorf("unexpected report %+v", got)
+	default:
+	}
+}
+
+func TestTrackImport(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(findFixture("image.tar.xz", t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	p, err := conn.Track(ctx, f, func() (*Transfer, error) {
+		return conn.ImportTar(f, importPrefix+"TrackImport", true, true)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last Progress
+	for progress := range p.Progress() {
+		last = progress
+	}
+	if err := p.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if last.Fraction != 1 || last.Size <= 0 {
+		t.Errorf("unexpected final progress %+v", last)
+	}
+}
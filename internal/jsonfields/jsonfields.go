@@ -0,0 +1,97 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonfields helps decoding JSON objects into structs while keeping
+// the fields the struct does not know about, so that data from newer systemd
+// versions is not lost.
+package jsonfields
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Unknown returns the fields of the JSON object b that do not correspond to
+// a field of the struct v points to, using the same case-insensitive
+// matching as encoding/json. It returns nil if there are none.
+func Unknown(b []byte, v interface{}) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	known := fieldNames(reflect.TypeOf(v).Elem(), nil)
+	for k := range fields {
+		for _, name := range known {
+			if strings.EqualFold(k, name) {
+				delete(fields, k)
+				break
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// Merge adds the fields in unknown to the JSON object b, unless b already
+// has a field of the same name.
+func Merge(b []byte, unknown map[string]json.RawMessage) ([]byte, error) {
+	if len(unknown) == 0 {
+		return b, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range unknown {
+		if _, ok := fields[k]; !ok {
+			fields[k] = v
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// fieldNames returns the JSON names of the fields of the struct type t,
+// including those of embedded structs.
+func fieldNames(t reflect.Type, names []string) []string {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				names = fieldNames(ft, names)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
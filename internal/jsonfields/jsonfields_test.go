@@ -0,0 +1,83 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonfields
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type embedded struct {
+	Inner string
+}
+
+type object struct {
+	embedded
+	Name    string `json:"Name"`
+	Renamed int    `json:"other,omitempty"`
+	Skipped string `json:"-"`
+	Plain   bool
+	private string
+}
+
+func TestUnknown(t *testing.T) {
+	tests := []struct {
+		in      string
+		unknown map[string]json.RawMessage
+	}{
+		{`{"Name":"a","other":1,"Plain":true,"Inner":"x"}`, nil},
+		{`{"name":"a","OTHER":1}`, nil},
+		{`{"Name":"a","New":[1, 2],"Skipped":"s","private":"p","Renamed":2}`, map[string]json.RawMessage{
+			"New":     json.RawMessage(`[1, 2]`),
+			"Skipped": json.RawMessage(`"s"`),
+			"private": json.RawMessage(`"p"`),
+			"Renamed": json.RawMessage(`2`),
+		}},
+	}
+
+	for i, tt := range tests {
+		unknown, err := Unknown([]byte(tt.in), &object{})
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(unknown, tt.unknown) {
+			t.Errorf("case %d: expected %v, got %v", i, tt.unknown, unknown)
+		}
+	}
+
+	if _, err := Unknown([]byte(`[]`), &object{}); err == nil {
+		t.Error("expected error for non-object")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	out, err := Merge([]byte(`{"Name":"a"}`), map[string]json.RawMessage{
+		"Name": json.RawMessage(`"b"`),
+		"New":  json.RawMessage(`true`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"Name":"a","New":true}` {
+		t.Errorf("unexpected merged object %s", out)
+	}
+
+	out, err = Merge([]byte(`{"Name":"a"}`), nil)
+	if err != nil || string(out) != `{"Name":"a"}` {
+		t.Errorf("unexpected merged object %s, %v", out, err)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrDropEntry is returned by a Hook to drop an entry without the send
+// failing, for example to sample noisy messages.
+var ErrDropEntry = errors.New("journal entry dropped")
+
+// Entry is a journal entry about to be sent by a Client.
+type Entry struct {
+	Message  string
+	Priority Priority
+	// Fields are the fields of the entry apart from MESSAGE and PRIORITY.
+	Fields map[string]string
+}
+
+// Hook is called by a Client for every entry before it is encoded. It may
+// change the entry, for example to add the trace and span IDs found in ctx,
+// or veto it by returning an error. Send fails with the error, unless it is
+// ErrDropEntry.
+type Hook interface {
+	BeforeSend(ctx context.Context, e *Entry) error
+}
+
+// HookFunc is a function used as a Hook.
+type HookFunc func(ctx context.Context, e *Entry) error
+
+// BeforeSend calls f(ctx, e).
+func (f HookFunc) BeforeSend(ctx context.Context, e *Entry) error {
+	return f(ctx, e)
+}
+
+// RequireFields returns a hook failing entries that lack any of fields,
+// which are checked after the hooks before it ran.
+func RequireFields(fields ...string) Hook {
+	return HookFunc(func(ctx context.Context, e *Entry) error {
+		for _, f := range fields {
+			if _, ok := e.Fields[f]; !ok {
+				return fmt.Errorf("journal entry %q lacks required field %s", e.Message, f)
+			}
+		}
+		return nil
+	})
+}
+
+// Client sends entries to the journal with default fields and hooks. The
+// zero value sends entries like Send.
+type Client struct {
+	// Fields are added to every entry, the fields passed to Send take
+	// precedence.
+	Fields map[string]string
+	// Hooks are called in order for every entry.
+	Hooks []Hook
+}
+
+// Send sends a message to the journal like SendContext with a background
+// context.
+func (c *Client) Send(message string, priority Priority, vars map[string]string) error {
+	return c.SendContext(context.Background(), message, priority, vars)
+}
+
+// SendContext sends a message to the journal like Send, after adding the
+// default fields of c and calling its hooks with ctx.
+func (c *Client) SendContext(ctx context.Context, message string, priority Priority, vars map[string]string) error {
+	e := &Entry{
+		Message:  message,
+		Priority: priority,
+		Fields:   make(map[string]string, len(c.Fields)+len(vars)),
+	}
+	for k, v := range c.Fields {
+		e.Fields[k] = v
+	}
+	for k, v := range vars {
+		e.Fields[k] = v
+	}
+	for _, h := range c.Hooks {
+		if err := h.BeforeSend(ctx, e); err == ErrDropEntry {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return Send(e.Message, e.Priority, e.Fields)
+}
+
+// Print prints a message to the journal like Print, through c.
+func (c *Client) Print(priority Priority, format string, a ...interface{}) error {
+	return c.Send(fmt.Sprintf(format, a...), priority, nil)
+}
+
+// With returns a copy of c with fields added to its default fields, which
+// take precedence over the existing ones. Hooks appended to either client
+// later are not seen by the other.
+func (c *Client) With(fields map[string]string) *Client {
+	merged := make(map[string]string, len(c.Fields)+len(fields))
+	for k, v := range c.Fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Client{Fields: merged, Hooks: append([]Hook(nil), c.Hooks...)}
+}
+
+type clientKey struct{}
+
+// NewContext returns a copy of ctx carrying c, to be retrieved with
+// FromContext by the functions it is passed to.
+func NewContext(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// FromContext returns the client stored in ctx by NewContext, or a zero
+// Client if there is none.
+func FromContext(ctx context.Context) *Client {
+	if c, ok := ctx.Value(clientKey{}).(*Client); ok && c != nil {
+		return c
+	}
+	return &Client{}
+}
+
+// WithFields returns a copy of ctx carrying the client of ctx with fields
+// added, such as the REQUEST_ID of the request ctx belongs to.
+func WithFields(ctx context.Context, fields map[string]string) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(fields))
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestClientContext(t *testing.T) {
+	ctx := context.Background()
+	if c := FromContext(ctx); c == nil || len(c.Fields) != 0 {
+		t.Fatalf("unexpected client %+v without NewContext", c)
+	}
+
+	hook := HookFunc(func(context.Context, *Entry) error { return nil })
+	base := &Client{Fields: map[string]string{"SYSLOG_IDENTIFIER": "api", "USER_ID": "0"}, Hooks: []Hook{hook}}
+	ctx = NewContext(ctx, base)
+	if FromContext(ctx) != base {
+		t.Fatal("client not retrieved from context")
+	}
+
+	reqCtx := WithFields(ctx, map[string]string{"REQUEST_ID": "42", "USER_ID": "1000"})
+	got := FromContext(reqCtx)
+	want := map[string]string{"SYSLOG_IDENTIFIER": "api", "REQUEST_ID": "42", "USER_ID": "1000"}
+	if !reflect.DeepEqual(got.Fields, want) || len(got.Hooks) != 1 {
+		t.Errorf("unexpected request client %+v", got)
+	}
+	if len(base.Fields) != 2 || base.Fields["USER_ID"] != "0" {
+		t.Errorf("base client changed to %v", base.Fields)
+	}
+	if FromContext(ctx) != base {
+		t.Error("parent context changed")
+	}
+}
+
+func TestClientWithHooks(t *testing.T) {
+	var calls []string
+	hook := func(name string) Hook {
+		return HookFunc(func(context.Context, *Entry) error {
+			calls = append(calls, name)
+			return nil
+		})
+	}
+
+	base := &Client{Hooks: make([]Hook, 1, 4)}
+	base.Hooks[0] = hook("base")
+	derived := base.With(nil)
+	base.Hooks = append(base.Hooks, hook("base2"))
+	derived.Hooks = append(derived.Hooks, hook("derived"))
+
+	for _, h := range base.Hooks {
+		h.BeforeSend(context.Background(), &Entry{})
+	}
+	if want := []string{"base", "base2"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("expected base hooks %v, got %v", want, calls)
+	}
+}
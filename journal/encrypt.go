@@ -0,0 +1,169 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// EncryptedPrefix starts the values of fields encrypted by a FieldEncryptor,
+// followed by the base64 encoded nonce and AES-GCM ciphertext.
+const EncryptedPrefix = "encrypted:v1:"
+
+// ErrNotEncrypted is returned when decrypting a value that was not encrypted
+// by a FieldEncryptor.
+var ErrNotEncrypted = errors.New("field value is not encrypted")
+
+// FieldEncryptor encrypts the values of selected fields before they are sent
+// to the journal, so they can only be read by holders of the key instead of
+// everyone allowed to read the journal. Field names, the entry metadata added
+// by journald and the fields not selected stay readable, which keeps matches
+// on them working.
+//
+// Values are encrypted with AES-GCM using the field name as additional data,
+// so an encrypted value cannot be moved to another field unnoticed.
+type FieldEncryptor struct {
+	aead   cipher.AEAD
+	fields map[string]bool
+}
+
+// NewFieldEncryptor returns a FieldEncryptor encrypting the given fields with
+// key, which must be 16, 24 or 32 bytes long to select AES-128, AES-192 or
+// AES-256. The field MESSAGE may be given to encrypt the message itself.
+func NewFieldEncryptor(key []byte, fields ...string) (*FieldEncryptor, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	e := &FieldEncryptor{aead: aead, fields: make(map[string]bool)}
+	for _, f := range fields {
+		if err := validVarName(f); err != nil {
+			return nil, fmt.Errorf("invalid field name %q: %v", f, err)
+		}
+		e.fields[f] = true
+	}
+	return e, nil
+}
+
+// Encrypts returns whether field is encrypted.
+func (e *FieldEncryptor) Encrypts(field string) bool {
+	return e.fields[field]
+}
+
+// EncryptValue encrypts value as the value of field, regardless of whether
+// the field was selected.
+func (e *FieldEncryptor) EncryptValue(field, value string) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize(), e.aead.NonceSize()+len(value)+e.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := e.aead.Seal(nonce, nonce, []byte(value), []byte(field))
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Encrypt returns a copy of vars with the values of the selected fields
+// encrypted.
+func (e *FieldEncryptor) Encrypt(vars map[string]string) (map[string]string, error) {
+	out := make(map[string]string, len(vars))
+	for k, v := range vars {
+		if e.fields[k] {
+			var err error
+			if v, err = e.EncryptValue(k, v); err != nil {
+				return nil, err
+			}
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// DecryptValue decrypts the value of field. It returns ErrNotEncrypted for
+// values that are not encrypted and an error if the value was encrypted with
+// another key, for another field or was tampered with.
+func (e *FieldEncryptor) DecryptValue(field, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", ErrNotEncrypted
+	}
+	sealed, err := base64.StdEncoding.DecodeString(value[len(EncryptedPrefix):])
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value of %s: %v", field, err)
+	}
+	if len(sealed) < e.aead.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted value of %s: too short", field)
+	}
+	nonce := sealed[:e.aead.NonceSize()]
+	plain, err := e.aead.Open(nil, nonce, sealed[len(nonce):], []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt value of %s: %v", field, err)
+	}
+	return string(plain), nil
+}
+
+// Decrypt decrypts the encrypted values of fields in place, leaving the
+// others as they are. Fields that fail to decrypt are left encrypted and the
+// first error is returned.
+func (e *FieldEncryptor) Decrypt(fields map[string]string) error {
+	var first error
+	for k, v := range fields {
+		if !IsEncrypted(v) {
+			continue
+		}
+		plain, err := e.DecryptValue(k, v)
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			continue
+		}
+		fields[k] = plain
+	}
+	return first
+}
+
+// Send sends a message to the journal like Send, with the selected fields
+// encrypted.
+func (e *FieldEncryptor) Send(message string, priority Priority, vars map[string]string) error {
+	vars, err := e.Encrypt(vars)
+	if err != nil {
+		return err
+	}
+	if e.fields["MESSAGE"] {
+		if message, err = e.EncryptValue("MESSAGE", message); err != nil {
+			return err
+		}
+	}
+	return Send(message, priority, vars)
+}
+
+// Print prints a message to the journal like Print, encrypting it if
+// MESSAGE is selected.
+func (e *FieldEncryptor) Print(priority Priority, format string, a ...interface{}) error {
+	return e.Send(fmt.Sprintf(format, a...), priority, nil)
+}
+
+// IsEncrypted returns whether value was encrypted by a FieldEncryptor.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptedPrefix)
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFieldEncryptor(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	e, err := NewFieldEncryptor(key, "SECRET", "MESSAGE")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars, err := e.Encrypt(map[string]string{"SECRET": "hunter2", "PLAIN": "visible"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vars["PLAIN"] != "visible" {
+		t.Errorf("unselected field changed to %q", vars["PLAIN"])
+	}
+	if !IsEncrypted(vars["SECRET"]) || strings.Contains(vars["SECRET"], "hunter2") {
+		t.Errorf("field not encrypted: %q", vars["SECRET"])
+	}
+	again, _ := e.EncryptValue("SECRET", "hunter2")
+	if again == vars["SECRET"] {
+		t.Error("nonce reused")
+	}
+
+	fields := map[string]string{"SECRET": vars["SECRET"], "PLAIN": "visible"}
+	if err := e.Decrypt(fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["SECRET"] != "hunter2" || fields["PLAIN"] != "visible" {
+		t.Errorf("unexpected decrypted fields %v", fields)
+	}
+
+	if _, err := e.DecryptValue("OTHER", vars["SECRET"]); err == nil {
+		t.Error("value moved to another field was decrypted")
+	}
+	if _, err := e.DecryptValue("SECRET", "hunter2"); err != ErrNotEncrypted {
+		t.Errorf("unexpected error %v", err)
+	}
+	tampered := vars["SECRET"][:len(vars["SECRET"])-4] + "AAAA"
+	if _, err := e.DecryptValue("SECRET", tampered); err == nil {
+		t.Error("tampered value was decrypted")
+	}
+	if _, err := e.DecryptValue("SECRET", EncryptedPrefix+"!"); err == nil {
+		t.Error("invalid base64 was decrypted")
+	}
+
+	other, _ := NewFieldEncryptor(bytes.Repeat([]byte{2}, 16), "SECRET")
+	fields = map[string]string{"SECRET": vars["SECRET"]}
+	if err := other.Decrypt(fields); err == nil || fields["SECRET"] != vars["SECRET"] {
+		t.Errorf("decrypted with the wrong key: %v, %q", err, fields["SECRET"])
+	}
+}
+
+func TestNewFieldEncryptor(t *testing.T) {
+	if _, err := NewFieldEncryptor(make([]byte, 10), "SECRET"); err == nil {
+		t.Error("invalid key accepted")
+	}
+	if _, err := NewFieldEncryptor(make([]byte, 16), "secret"); err == nil {
+		t.Error("invalid field name accepted")
+	}
+	e, _ := NewFieldEncryptor(make([]byte, 16), "SECRET")
+	if !e.Encrypts("SECRET") || e.Encrypts("MESSAGE") {
+		t.Error("unexpected selected fields")
+	}
+}
@@ -26,20 +26,18 @@
 package journal
 
 import (
-	"bytes"
-	"encoding/binary"
 	"errors"
 	"fmt"
-	"io"
 	"io/ioutil"
 	"net"
 	"os"
 	"strconv"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"syscall"
 	"unsafe"
+
+	"github.com/coreos/go-systemd/v22/journal/wire"
 )
 
 var (
@@ -69,6 +67,16 @@ func Enabled() bool {
 	return true
 }
 
+// SetSocket makes Send and Enabled use the journal socket at path instead of
+// the default one and returns the previous path. It is meant for tests, see
+// the journal/testserver package, and must not be called while entries are
+// being sent.
+func SetSocket(path string) string {
+	prev := journalSocket
+	journalSocket = path
+	return prev
+}
+
 // StderrIsJournalStream returns whether the process stderr is connected
 // to the Journal's stream transport.
 //
@@ -139,14 +147,14 @@ func Send(message string, priority Priority, vars map[string]string) error {
 		Net:  "unixgram",
 	}
 
-	data := new(bytes.Buffer)
-	appendVariable(data, "PRIORITY", strconv.Itoa(int(priority)))
-	appendVariable(data, "MESSAGE", message)
+	var data []byte
+	data = appendVariable(data, "PRIORITY", strconv.Itoa(int(priority)))
+	data = appendVariable(data, "MESSAGE", message)
 	for k, v := range vars {
-		appendVariable(data, k, v)
+		data = appendVariable(data, k, v)
 	}
 
-	_, _, err := conn.WriteMsgUnix(data.Bytes(), nil, socketAddr)
+	_, _, err := conn.WriteMsgUnix(data, nil, socketAddr)
 	if err == nil {
 		return nil
 	}
@@ -160,7 +168,7 @@ func Send(message string, priority Priority, vars map[string]string) error {
 		return err
 	}
 	defer file.Close()
-	_, err = io.Copy(file, data)
+	_, err = file.Write(data)
 	if err != nil {
 		return err
 	}
@@ -183,42 +191,11 @@ func getOrInitConn() *net.UnixConn {
 	return (*net.UnixConn)(atomic.LoadPointer(&unixConnPtr))
 }
 
-func appendVariable(w io.Writer, name, value string) {
+func appendVariable(b []byte, name, value string) []byte {
 	if err := validVarName(name); err != nil {
 		fmt.Fprintf(os.Stderr, "variable name %s contains invalid character, ignoring\n", name)
 	}
-	if strings.ContainsRune(value, '\n') {
-		/* When the value contains a newline, we write:
-		 * - the variable name, followed by a newline
-		 * - the size (in 64bit little endian format)
-		 * - the data, followed by a newline
-		 */
-		fmt.Fprintln(w, name)
-		binary.Write(w, binary.LittleEndian, uint64(len(value)))
-		fmt.Fprintln(w, value)
-	} else {
-		/* just write the variable and value all on one line */
-		fmt.Fprintf(w, "%s=%s\n", name, value)
-	}
-}
-
-// validVarName validates a variable name to make sure journald will accept it.
-// The variable name must be in uppercase and consist only of characters,
-// numbers and underscores, and may not begin with an underscore:
-// https://www.freedesktop.org/software/systemd/man/sd_journal_print.html
-func validVarName(name string) error {
-	if name == "" {
-		return errors.New("Empty variable name")
-	} else if name[0] == '_' {
-		return errors.New("Variable name begins with an underscore")
-	}
-
-	for _, c := range name {
-		if !(('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '_') {
-			return errors.New("Variable name contains invalid characters")
-		}
-	}
-	return nil
+	return wire.AppendField(b, name, value)
 }
 
 // isSocketSpaceError checks whether the error is signaling
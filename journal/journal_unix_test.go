@@ -18,15 +18,103 @@
 package journal_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/coreos/go-systemd/v22/journal/testserver"
 )
 
+func TestFieldEncryptorSend(t *testing.T) {
+	s, err := testserver.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	e, err := journal.NewFieldEncryptor(make([]byte, 32), "MESSAGE", "TOKEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Send("secret message", journal.PriInfo, map[string]string{"TOKEN": "abc", "USER": "core"}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := s.Wait(1, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := map[string]string{}
+	for _, name := range []string{"MESSAGE", "TOKEN", "USER"} {
+		fields[name] = entries[0].Get(name)
+	}
+	if !journal.IsEncrypted(fields["MESSAGE"]) || !journal.IsEncrypted(fields["TOKEN"]) || fields["USER"] != "core" {
+		t.Fatalf("unexpected entry %q", entries[0])
+	}
+	if err := e.Decrypt(fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["MESSAGE"] != "secret message" || fields["TOKEN"] != "abc" {
+		t.Errorf("unexpected decrypted fields %v", fields)
+	}
+}
+
+type traceKey struct{}
+
+func TestClientHooks(t *testing.T) {
+	s, err := testserver.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	c := &journal.Client{
+		Fields: map[string]string{"SYSLOG_IDENTIFIER": "test", "USER": "default"},
+		Hooks: []journal.Hook{
+			journal.HookFunc(func(ctx context.Context, e *journal.Entry) error {
+				if e.Priority == journal.PriDebug {
+					return journal.ErrDropEntry
+				}
+				if id, ok := ctx.Value(traceKey{}).(string); ok {
+					e.Fields["TRACE_ID"] = id
+				}
+				return nil
+			}),
+			journal.RequireFields("TRACE_ID"),
+		},
+	}
+	ctx := context.WithValue(context.Background(), traceKey{}, "4bf92f35")
+	if err := c.SendContext(ctx, "hello", journal.PriInfo, map[string]string{"USER": "core"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SendContext(ctx, "dropped", journal.PriDebug, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Send("untraced", journal.PriInfo, nil); err == nil {
+		t.Fatal("expected error for entry without required field")
+	}
+
+	entries, err := s.Wait(1, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := len(s.Entries()); n != 1 {
+		t.Fatalf("received %d entries", n)
+	}
+	e := entries[0]
+	if e.Message() != "hello" || e.Get("TRACE_ID") != "4bf92f35" || e.Get("USER") != "core" || e.Get("SYSLOG_IDENTIFIER") != "test" {
+		t.Errorf("unexpected entry %q", e)
+	}
+	if c.Fields["TRACE_ID"] != "" {
+		t.Error("hook changed the default fields")
+	}
+}
+
 func TestJournalStreamParsing(t *testing.T) {
 	if _, ok := os.LookupEnv("JOURNAL_STREAM"); ok {
 		t.Fatal("unset JOURNAL_STREAM before running this test")
@@ -34,6 +34,12 @@ func Send(message string, priority Priority, vars map[string]string) error {
 	return errors.New("could not initialize socket to journald")
 }
 
+// SetSocket has no effect on Windows, where there is no journal socket, and
+// always returns an empty path.
+func SetSocket(path string) string {
+	return ""
+}
+
 func StderrIsJournalStream() (bool, error) {
 	return false, nil
 }
@@ -0,0 +1,174 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package journaltest is a harness for integration tests against a real
+// journald. Entries are sent with the journal package, tagged with a field
+// unique to the test, and read back with the sdjournal package, so tests can
+// assert what journald actually stored.
+//
+// The tests using it need a running journald and a journal readable by the
+// test, so they are opt-in: unless the GO_SYSTEMD_JOURNAL_INTEGRATION
+// environment variable is set, New skips the test. For unit tests without
+// journald, see the journal/testserver package.
+package journaltest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/coreos/go-systemd/v22/sdjournal"
+)
+
+// EnvVar is the environment variable enabling the integration tests.
+const EnvVar = "GO_SYSTEMD_JOURNAL_INTEGRATION"
+
+// IDField is the field tagging the entries of a harness with its ID.
+const IDField = "GO_SYSTEMD_TEST_ID"
+
+// DefaultTimeout is how long a harness waits for entries to show up in the
+// journal unless its Timeout is set.
+const DefaultTimeout = 10 * time.Second
+
+// Harness sends entries tagged with its ID and reads them back.
+type Harness struct {
+	t       testing.TB
+	journal *sdjournal.Journal
+
+	// ID is the value of the IDField of the entries sent by the harness.
+	ID string
+	// Timeout is how long Next waits for an entry.
+	Timeout time.Duration
+}
+
+// New returns a harness for the test t, skipping the test unless EnvVar is
+// set. The harness must be closed after use.
+func New(t testing.TB) *Harness {
+	t.Helper()
+	if os.Getenv(EnvVar) == "" {
+		t.Skipf("journald integration tests are disabled, set %s to run them", EnvVar)
+	}
+	if !journal.Enabled() {
+		t.Fatal("journald is not available")
+	}
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		t.Fatal(err)
+	}
+	h := &Harness{t: t, ID: hex.EncodeToString(id[:]), Timeout: DefaultTimeout}
+
+	j, err := sdjournal.NewJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.AddMatch(IDField + "=" + h.ID); err != nil {
+		j.Close()
+		t.Fatal(err)
+	}
+	// Large fields are read back completely, not only their first 64KiB.
+	if err := j.SetDataThreshold(0); err != nil {
+		j.Close()
+		t.Fatal(err)
+	}
+	if err := j.SeekHead(); err != nil {
+		j.Close()
+		t.Fatal(err)
+	}
+	h.journal = j
+	return h
+}
+
+// Close closes the journal of the harness.
+func (h *Harness) Close() error {
+	return h.journal.Close()
+}
+
+// Fields returns a copy of vars with the IDField of the harness added, for
+// sending entries with other clients than Send, such as journal.Client.
+func (h *Harness) Fields(vars map[string]string) map[string]string {
+	fields := make(map[string]string, len(vars)+1)
+	for k, v := range vars {
+		fields[k] = v
+	}
+	fields[IDField] = h.ID
+	return fields
+}
+
+// Send sends an entry tagged with the ID of the harness with journal.Send,
+// failing the test if it cannot be sent.
+func (h *Harness) Send(message string, priority journal.Priority, vars map[string]string) {
+	h.t.Helper()
+	if err := journal.Send(message, priority, h.Fields(vars)); err != nil {
+		h.t.Fatalf("sending entry: %v", err)
+	}
+}
+
+// Next returns the next entry sent by the harness, waiting up to Timeout for
+// it to be written to the journal. It fails the test if there is none.
+func (h *Harness) Next() *sdjournal.JournalEntry {
+	h.t.Helper()
+	deadline := time.Now().Add(h.Timeout)
+	for {
+		n, err := h.journal.Next()
+		if err != nil {
+			h.t.Fatalf("reading journal: %v", err)
+		}
+		if n > 0 {
+			entry, err := h.journal.GetEntry()
+			if err != nil {
+				h.t.Fatalf("reading journal entry: %v", err)
+			}
+			return entry
+		}
+
+		left := time.Until(deadline)
+		if left <= 0 {
+			h.t.Fatalf("no entry with %s=%s after %v", IDField, h.ID, h.Timeout)
+		}
+		if r := h.journal.Wait(left); r < 0 {
+			h.t.Fatalf("waiting for journal: %d", r)
+		}
+	}
+}
+
+// Expect reads the next entry like Next and reports an error for each of
+// the fields whose value differs, returning the entry.
+func (h *Harness) Expect(fields map[string]string) *sdjournal.JournalEntry {
+	h.t.Helper()
+	entry := h.Next()
+	for k, want := range fields {
+		got, ok := entry.Fields[k]
+		switch {
+		case !ok:
+			h.t.Errorf("entry lacks field %s", k)
+		case got != want:
+			h.t.Errorf("field %s is %s, want %s", k, abbrev(got), abbrev(want))
+		}
+	}
+	return entry
+}
+
+// abbrev quotes a value for error messages, shortening large values.
+func abbrev(v string) string {
+	const max = 64
+	if len(v) <= max {
+		return fmt.Sprintf("%q", v)
+	}
+	return fmt.Sprintf("%q... (%d bytes)", v[:max], len(v))
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journaltest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+func TestDelivery(t *testing.T) {
+	h := New(t)
+	defer h.Close()
+
+	h.Send("first", journal.PriInfo, nil)
+	h.Send("second", journal.PriWarning, map[string]string{"GO_SYSTEMD_TEST": "1"})
+
+	h.Expect(map[string]string{"MESSAGE": "first", "PRIORITY": "6"})
+	h.Expect(map[string]string{"MESSAGE": "second", "PRIORITY": "4", "GO_SYSTEMD_TEST": "1"})
+}
+
+func TestEncoding(t *testing.T) {
+	h := New(t)
+	defer h.Close()
+
+	fields := map[string]string{
+		"MULTILINE": "line 1\nline 2\n",
+		"BINARY":    "\x00\x01\xff=\n",
+		"UNICODE":   "grüße, 世界",
+		"EMPTY":     "",
+		"EQUALS":    "a=b=c",
+	}
+	h.Send("encoding", journal.PriInfo, fields)
+	h.Expect(fields)
+}
+
+func TestLargeMessage(t *testing.T) {
+	h := New(t)
+	defer h.Close()
+
+	// Too large for a datagram, so the entry is passed in a file instead.
+	message := strings.Repeat("0123456789abcdef", 1<<16)
+	h.Send(message, journal.PriInfo, nil)
+	h.Expect(map[string]string{"MESSAGE": message})
+}
+
+func TestClient(t *testing.T) {
+	h := New(t)
+	defer h.Close()
+
+	c := &journal.Client{Fields: h.Fields(map[string]string{"COMPONENT": "client"})}
+	if err := c.Send("from client", journal.PriNotice, map[string]string{"EXTRA": "x"}); err != nil {
+		t.Fatal(err)
+	}
+	h.Expect(map[string]string{"MESSAGE": "from client", "PRIORITY": "5", "COMPONENT": "client", "EXTRA": "x"})
+}
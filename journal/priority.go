@@ -0,0 +1,192 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var priorityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// String returns the name journalctl uses for the priority, such as "err".
+func (p Priority) String() string {
+	if p >= 0 && int(p) < len(priorityNames) {
+		return priorityNames[p]
+	}
+	return strconv.Itoa(int(p))
+}
+
+// ParsePriority parses a priority given by its name, as accepted by
+// journalctl -p, or its number.
+func ParsePriority(s string) (Priority, error) {
+	for i, name := range priorityNames {
+		if strings.EqualFold(s, name) {
+			return Priority(i), nil
+		}
+	}
+	switch strings.ToLower(s) {
+	case "emergency":
+		return PriEmerg, nil
+	case "critical":
+		return PriCrit, nil
+	case "error":
+		return PriErr, nil
+	case "warn":
+		return PriWarning, nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= int(PriEmerg) && n <= int(PriDebug) {
+		return Priority(n), nil
+	}
+	return 0, fmt.Errorf("invalid priority %q", s)
+}
+
+// Threshold maps all levels from Level up to the next threshold to Priority.
+type Threshold struct {
+	Level    int
+	Priority Priority
+}
+
+// PriorityMap maps the numeric levels of a logging library to journal
+// priorities, so logging adapters share one table that can be adjusted to
+// local conventions. Levels holds the priorities of individual levels and
+// takes precedence. Other levels get the priority of the highest threshold
+// not above them, for libraries where higher levels are more severe, and
+// Default if there is none. Of several thresholds at the same level, the
+// last one counts.
+type PriorityMap struct {
+	Levels     map[int]Priority
+	Thresholds []Threshold
+	Default    Priority
+}
+
+// Priority returns the priority of level.
+func (m *PriorityMap) Priority(level int) Priority {
+	if p, ok := m.Levels[level]; ok {
+		return p
+	}
+	var best *Threshold
+	for i := range m.Thresholds {
+		t := &m.Thresholds[i]
+		if t.Level <= level && (best == nil || t.Level >= best.Level) {
+			best = t
+		}
+	}
+	if best == nil {
+		return m.Default
+	}
+	return best.Priority
+}
+
+// Override returns a copy of m with the priorities of the given levels
+// replaced, for adapters that deviate from a shared map.
+func (m *PriorityMap) Override(levels map[int]Priority) *PriorityMap {
+	c := &PriorityMap{
+		Levels:     make(map[int]Priority, len(m.Levels)+len(levels)),
+		Thresholds: append([]Threshold(nil), m.Thresholds...),
+		Default:    m.Default,
+	}
+	for l, p := range m.Levels {
+		c.Levels[l] = p
+	}
+	for l, p := range levels {
+		c.Levels[l] = p
+	}
+	return c
+}
+
+// SlogPriorities returns the default map for the levels of log/slog, where
+// every level from slog.LevelError on is an error, from slog.LevelWarn on a
+// warning and so on. Levels below slog.LevelDebug are debug messages too.
+func SlogPriorities() *PriorityMap {
+	return &PriorityMap{
+		Thresholds: []Threshold{
+			{Level: -4, Priority: PriDebug},
+			{Level: 0, Priority: PriInfo},
+			{Level: 4, Priority: PriWarning},
+			{Level: 8, Priority: PriErr},
+		},
+		Default: PriDebug,
+	}
+}
+
+// ZapPriorities returns the default map for the levels of go.uber.org/zap,
+// from DebugLevel (-1) to FatalLevel (5).
+func ZapPriorities() *PriorityMap {
+	return &PriorityMap{
+		Thresholds: []Threshold{
+			{Level: -1, Priority: PriDebug},
+			{Level: 0, Priority: PriInfo},
+			{Level: 1, Priority: PriWarning},
+			{Level: 2, Priority: PriErr},
+			{Level: 3, Priority: PriCrit},
+			{Level: 5, Priority: PriAlert},
+		},
+		Default: PriDebug,
+	}
+}
+
+// LogrusPriorities returns the default map for the levels of
+// github.com/sirupsen/logrus, from PanicLevel (0) to TraceLevel (6). As
+// logrus levels get more severe towards zero, unknown levels are debug
+// messages.
+func LogrusPriorities() *PriorityMap {
+	return &PriorityMap{
+		Levels: map[int]Priority{
+			0: PriCrit,
+			1: PriAlert,
+			2: PriErr,
+			3: PriWarning,
+			4: PriInfo,
+			5: PriDebug,
+			6: PriDebug,
+		},
+		Default: PriDebug,
+	}
+}
+
+// ParsePriorityMap parses mappings of the form "LEVEL=PRIORITY" separated
+// by commas or white space, such as "8=crit,2=notice", as found in
+// configuration files and environment variables, and returns a copy of m
+// with them applied. Mappings of the form ">=LEVEL=PRIORITY" add a threshold
+// instead of mapping a single level.
+func ParsePriorityMap(m *PriorityMap, s string) (*PriorityMap, error) {
+	levels := make(map[int]Priority)
+	var thresholds []Threshold
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		mapping := strings.TrimPrefix(f, ">=")
+		i := strings.IndexByte(mapping, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid priority mapping %q", f)
+		}
+		level, err := strconv.Atoi(mapping[:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid level in priority mapping %q", f)
+		}
+		p, err := ParsePriority(mapping[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		if len(mapping) < len(f) {
+			thresholds = append(thresholds, Threshold{Level: level, Priority: p})
+		} else {
+			levels[level] = p
+		}
+	}
+	c := m.Override(levels)
+	c.Thresholds = append(c.Thresholds, thresholds...)
+	return c, nil
+}
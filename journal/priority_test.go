@@ -0,0 +1,105 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import "testing"
+
+func TestPriorityMap(t *testing.T) {
+	slog := SlogPriorities()
+	zap := ZapPriorities()
+	logrus := LogrusPriorities()
+	for i, tt := range []struct {
+		m     *PriorityMap
+		level int
+		want  Priority
+	}{
+		{slog, -8, PriDebug},
+		{slog, -4, PriDebug},
+		{slog, 0, PriInfo},
+		{slog, 2, PriInfo},
+		{slog, 4, PriWarning},
+		{slog, 12, PriErr},
+		{zap, -1, PriDebug},
+		{zap, 2, PriErr},
+		{zap, 4, PriCrit},
+		{zap, 5, PriAlert},
+		{logrus, 0, PriCrit},
+		{logrus, 3, PriWarning},
+		{logrus, 6, PriDebug},
+		{logrus, 42, PriDebug},
+	} {
+		if got := tt.m.Priority(tt.level); got != tt.want {
+			t.Errorf("case %d: got %v, want %v", i, got, tt.want)
+		}
+	}
+
+	o := slog.Override(map[int]Priority{2: PriNotice})
+	if o.Priority(2) != PriNotice || o.Priority(3) != PriInfo {
+		t.Error("override not applied")
+	}
+	if slog.Priority(2) != PriInfo {
+		t.Error("override changed the original map")
+	}
+}
+
+func TestParsePriorityMap(t *testing.T) {
+	m, err := ParsePriorityMap(SlogPriorities(), "2=notice, >=12=crit >=4=5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range []struct {
+		level int
+		want  Priority
+	}{
+		{2, PriNotice},
+		{4, PriNotice},
+		{8, PriErr},
+		{12, PriCrit},
+		{16, PriCrit},
+	} {
+		if got := m.Priority(tt.level); got != tt.want {
+			t.Errorf("case %d: got %v, want %v", i, got, tt.want)
+		}
+	}
+
+	for _, s := range []string{"2", "x=err", "2=loud", ">=2"} {
+		if _, err := ParsePriorityMap(SlogPriorities(), s); err == nil {
+			t.Errorf("%q parsed", s)
+		}
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	for i, tt := range []struct {
+		in   string
+		want Priority
+		ok   bool
+	}{
+		{"err", PriErr, true},
+		{"WARNING", PriWarning, true},
+		{"warn", PriWarning, true},
+		{"7", PriDebug, true},
+		{"8", 0, false},
+		{"loud", 0, false},
+	} {
+		p, err := ParsePriority(tt.in)
+		if (err == nil) != tt.ok || p != tt.want {
+			t.Errorf("case %d: got %v, %v", i, p, err)
+		}
+	}
+	if PriNotice.String() != "notice" || Priority(9).String() != "9" {
+		t.Error("unexpected priority names")
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testserver stands in for journald in unit tests: it receives the
+// entries the journal package sends, decodes them from the native protocol
+// and keeps them for assertions, so applications can test their logging
+// without a running journald.  See https://systemd.io/JOURNAL_NATIVE_PROTOCOL/
+package testserver
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/coreos/go-systemd/v22/journal/wire"
+)
+
+// Entry is a decoded journal entry, holding the values of each field in the
+// order they were sent. Fields usually appear once, but the protocol allows
+// several values per field.
+type Entry map[string][]string
+
+// Get returns the first value of the field, or "" if it is not set.
+func (e Entry) Get(name string) string {
+	if v := e[name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// Message returns the MESSAGE field.
+func (e Entry) Message() string {
+	return e.Get("MESSAGE")
+}
+
+// Priority returns the PRIORITY field.
+func (e Entry) Priority() (journal.Priority, error) {
+	p, err := strconv.Atoi(e.Get("PRIORITY"))
+	if err != nil || p < 0 || p > int(journal.PriDebug) {
+		return 0, fmt.Errorf("invalid priority %q", e.Get("PRIORITY"))
+	}
+	return journal.Priority(p), nil
+}
+
+// Decode decodes a datagram of the native journal protocol. Unlike journald,
+// which drops invalid fields silently, it returns an error for them so tests
+// catch them.
+func Decode(b []byte) (Entry, error) {
+	entries, err := wire.Decoder{Strict: true}.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 1 {
+		return nil, fmt.Errorf("datagram holds %d entries", len(entries))
+	}
+	e := make(Entry)
+	for _, fields := range entries {
+		for _, f := range fields {
+			e[f.Name] = append(e[f.Name], f.Value)
+		}
+	}
+	return e, nil
+}
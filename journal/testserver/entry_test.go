@@ -0,0 +1,67 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testserver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+func TestDecode(t *testing.T) {
+	for i, tt := range []struct {
+		in   string
+		want Entry
+	}{
+		{"", Entry{}},
+		{"PRIORITY=6\nMESSAGE=hello\n", Entry{"PRIORITY": {"6"}, "MESSAGE": {"hello"}}},
+		{"MESSAGE\n\x0b\x00\x00\x00\x00\x00\x00\x00hello\nworld\nFOO=a=b\n", Entry{"MESSAGE": {"hello\nworld"}, "FOO": {"a=b"}}},
+		{"TAG=a\nTAG=b\nEMPTY=\n", Entry{"TAG": {"a", "b"}, "EMPTY": {""}}},
+		{"MESSAGE=no newline", nil},
+		{"lower=case\n", nil},
+		{"_PID=1\n", nil},
+		{"MESSAGE\n\x05\x00\x00", nil},
+		{"MESSAGE\n\x0b\x00\x00\x00\x00\x00\x00\x00hello\n", nil},
+		{"MESSAGE\n\xff\xff\xff\xff\xff\xff\xff\xff\n", nil},
+		{"A=1\n\nB=2\n", nil},
+	} {
+		e, err := Decode([]byte(tt.in))
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("case %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+		} else if !reflect.DeepEqual(e, tt.want) {
+			t.Errorf("case %d: got %q", i, e)
+		}
+	}
+}
+
+func TestEntry(t *testing.T) {
+	e := Entry{"MESSAGE": {"hi"}, "PRIORITY": {"3"}}
+	if e.Message() != "hi" || e.Get("MISSING") != "" {
+		t.Errorf("unexpected fields %q %q", e.Message(), e.Get("MISSING"))
+	}
+	if p, err := e.Priority(); err != nil || p != journal.PriErr {
+		t.Errorf("unexpected priority %v, %v", p, err)
+	}
+	if _, err := (Entry{"PRIORITY": {"8"}}).Priority(); err == nil {
+		t.Error("expected error for invalid priority")
+	}
+}
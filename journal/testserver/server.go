@@ -0,0 +1,206 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package testserver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+// maxDatagram is the size of the largest datagram received, larger entries
+// are sent in a file descriptor by the journal package.
+const maxDatagram = 8 << 20
+
+// Server receives the entries sent by the journal package in place of
+// journald.
+type Server struct {
+	// Path is the socket the server listens on.
+	Path string
+
+	dir  string
+	prev string
+	conn *net.UnixConn
+	done chan struct{}
+
+	mu      sync.Mutex
+	entries []Entry
+	err     error
+	changed chan struct{}
+}
+
+// Start binds a socket in a temporary directory and makes the journal
+// package send to it until the server is closed. Only one server may run at
+// a time, so tests using it should not run in parallel.
+func Start() (*Server, error) {
+	dir, err := ioutil.TempDir("", "journal")
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	s := &Server{
+		Path:    path,
+		dir:     dir,
+		conn:    conn,
+		done:    make(chan struct{}),
+		changed: make(chan struct{}),
+	}
+	s.prev = journal.SetSocket(path)
+	go s.serve()
+	return s, nil
+}
+
+func (s *Server) serve() {
+	defer close(s.done)
+	buf := make([]byte, maxDatagram)
+	oob := make([]byte, syscall.CmsgSpace(4*4))
+	for {
+		n, oobn, flags, _, err := s.conn.ReadMsgUnix(buf, oob)
+		if err != nil {
+			return
+		}
+		if flags&(syscall.MSG_TRUNC|syscall.MSG_CTRUNC) != 0 {
+			s.add(nil, errors.New("received truncated datagram"))
+			continue
+		}
+		data := buf[:n]
+		if oobn > 0 {
+			data, err = readFds(oob[:oobn])
+			if err != nil {
+				s.add(nil, err)
+				continue
+			}
+		}
+		s.add(Decode(data))
+	}
+}
+
+// readFds returns the contents of the file passed with a datagram, which
+// replaces the datagram itself, and closes all passed file descriptors.
+func readFds(oob []byte) ([]byte, error) {
+	msgs, err := syscall.ParseSocketControlMessage(oob)
+	if err != nil {
+		return nil, err
+	}
+	var files []*os.File
+	for _, m := range msgs {
+		fds, err := syscall.ParseUnixRights(&m)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			f := os.NewFile(uintptr(fd), "journal entry")
+			defer f.Close()
+			files = append(files, f)
+		}
+	}
+	if len(files) != 1 {
+		return nil, fmt.Errorf("received %d file descriptors with entry", len(files))
+	}
+	if _, err := files[0].Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(files[0])
+}
+
+func (s *Server) add(e Entry, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		if s.err == nil {
+			s.err = err
+		}
+	} else {
+		s.entries = append(s.entries, e)
+	}
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Entries returns the entries received so far.
+func (s *Server) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Entry(nil), s.entries...)
+}
+
+// Err returns the first error decoding a received datagram.
+func (s *Server) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Reset forgets the entries and errors received so far.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+	s.err = nil
+}
+
+// Wait waits until at least n entries have been received and returns them.
+// It fails if that takes longer than timeout or a datagram could not be
+// decoded.
+func (s *Server) Wait(n int, timeout time.Duration) ([]Entry, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		s.mu.Lock()
+		entries := append([]Entry(nil), s.entries...)
+		err, changed := s.err, s.changed
+		s.mu.Unlock()
+
+		if err != nil {
+			return entries, err
+		}
+		if len(entries) >= n {
+			return entries, nil
+		}
+		select {
+		case <-changed:
+		case <-deadline.C:
+			return entries, fmt.Errorf("received %d of %d journal entries", len(entries), n)
+		}
+	}
+}
+
+// Close stops the server, makes the journal package use its previous socket
+// again and returns the first decoding error, if any.
+func (s *Server) Close() error {
+	journal.SetSocket(s.prev)
+	s.conn.Close()
+	<-s.done
+	os.RemoveAll(s.dir)
+	return s.Err()
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package testserver
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/journal"
+)
+
+func TestServer(t *testing.T) {
+	s, err := Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !journal.Enabled() {
+		t.Fatal("journal is not enabled with test server")
+	}
+	if err := journal.Send("hello", journal.PriWarning, map[string]string{"CODE": "line1\nline2"}); err != nil {
+		t.Fatal(err)
+	}
+	// Larger than the socket buffer, so passed as a file descriptor.
+	large := strings.Repeat("x", 1<<20)
+	if err := journal.Send(large, journal.PriInfo, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := s.Wait(2, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].Message() != "hello" || entries[0].Get("CODE") != "line1\nline2" {
+		t.Errorf("unexpected first entry %q", entries[0])
+	}
+	if p, _ := entries[0].Priority(); p != journal.PriWarning {
+		t.Errorf("unexpected priority %v", p)
+	}
+	if entries[1].Message() != large {
+		t.Errorf("large entry has message of %d bytes", len(entries[1].Message()))
+	}
+
+	s.Reset()
+	if len(s.Entries()) != 0 {
+		t.Error("entries left after reset")
+	}
+	if _, err := s.Wait(1, 10*time.Millisecond); err == nil {
+		t.Error("expected timeout")
+	}
+
+	conn, err := net.Dial("unixgram", s.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("invalid")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Wait(1, 5*time.Second); err == nil {
+		t.Error("expected decoding error")
+	}
+
+	path := s.Path
+	if err := s.Close(); err == nil {
+		t.Error("expected decoding error from Close")
+	}
+	journal.Send("after close", journal.PriInfo, nil)
+	if prev := journal.SetSocket(path); prev == path {
+		t.Error("journal socket was not restored")
+	} else {
+		journal.SetSocket(prev)
+	}
+}
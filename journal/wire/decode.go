@@ -0,0 +1,107 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// SizeError is returned for values larger than MaxValueSize.
+type SizeError struct {
+	Name string
+	Size uint64
+}
+
+func (e *SizeError) Error() string {
+	return fmt.Sprintf("value of field %s has %d bytes, more than %d", e.Name, e.Size, MaxValueSize)
+}
+
+// Decoder decodes journal entries.
+type Decoder struct {
+	// Strict makes decoding fail where journald silently ignores data:
+	// fields with invalid names, trailing data without a newline and
+	// truncated binary values. Otherwise they are skipped like journald
+	// does.
+	Strict bool
+}
+
+// Decode decodes the entries of a datagram. Entries without any fields,
+// like those left by consecutive empty lines, are omitted. Oversized
+// binary values always fail with a *SizeError, as they make journald drop
+// the datagram.
+func (d Decoder) Decode(b []byte) ([][]Field, error) {
+	var entries [][]Field
+	var fields []Field
+	for len(b) > 0 {
+		nl := bytes.IndexByte(b, '\n')
+		if nl < 0 {
+			if d.Strict {
+				return nil, fmt.Errorf("missing newline after %.32q", b)
+			}
+			break
+		}
+		line := b[:nl]
+		b = b[nl+1:]
+
+		if len(line) == 0 {
+			if len(fields) > 0 {
+				entries = append(entries, fields)
+				fields = nil
+			}
+			continue
+		}
+
+		var name, value string
+		if eq := bytes.IndexByte(line, '='); eq >= 0 {
+			name, value = string(line[:eq]), string(line[eq+1:])
+		} else {
+			name = string(line)
+			if len(b) < 8 {
+				if d.Strict {
+					return nil, fmt.Errorf("missing size of field %s", name)
+				}
+				break
+			}
+			size := binary.LittleEndian.Uint64(b)
+			if size > MaxValueSize {
+				return nil, &SizeError{Name: name, Size: size}
+			}
+			b = b[8:]
+			// size is small enough for size+1 not to overflow.
+			if uint64(len(b)) < size+1 || b[size] != '\n' {
+				if d.Strict {
+					return nil, fmt.Errorf("truncated value of field %s", name)
+				}
+				break
+			}
+			value = string(b[:size])
+			b = b[size+1:]
+		}
+
+		if err := ValidFieldName(name); err != nil {
+			if d.Strict {
+				return nil, err
+			}
+			continue
+		}
+		fields = append(fields, Field{Name: name, Value: value})
+	}
+	if len(fields) > 0 {
+		entries = append(entries, fields)
+	}
+	return entries, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	type entries = [][]Field
+	for i, tt := range []struct {
+		in      string
+		lenient entries
+		strict  entries // nil if strict decoding fails
+	}{
+		{"", nil, entries{}},
+		{"\n\n", nil, entries{}},
+		{"MESSAGE=hello\nEMPTY=\nEQ==a=\n", entries{{{"MESSAGE", "hello"}, {"EMPTY", ""}, {"EQ", "=a="}}}, entries{{{"MESSAGE", "hello"}, {"EMPTY", ""}, {"EQ", "=a="}}}},
+		{"A=1\n\nB=2\n\n", entries{{{"A", "1"}}, {{"B", "2"}}}, entries{{{"A", "1"}}, {{"B", "2"}}}},
+		{"MESSAGE\n\x06\x00\x00\x00\x00\x00\x00\x00a\nb=c\n\nA=1\n", entries{{{"MESSAGE", "a\nb=c\n"}, {"A", "1"}}}, entries{{{"MESSAGE", "a\nb=c\n"}, {"A", "1"}}}},
+		{"EMPTY\n\x00\x00\x00\x00\x00\x00\x00\x00\n", entries{{{"EMPTY", ""}}}, entries{{{"EMPTY", ""}}}},
+		// journald drops invalid fields and ignores trailing noise.
+		{"_PID=1\nlower=a\nA=1\n", entries{{{"A", "1"}}}, nil},
+		{"_BIN\n\x01\x00\x00\x00\x00\x00\x00\x00\n\nA=1\n", entries{{{"A", "1"}}}, nil},
+		{"A=1\nB=no newline", entries{{{"A", "1"}}}, nil},
+		{"A=1\nMESSAGE\n\x05\x00\x00", entries{{{"A", "1"}}}, nil},
+		{"A=1\nMESSAGE\n\x0b\x00\x00\x00\x00\x00\x00\x00hello\n", entries{{{"A", "1"}}}, nil},
+		{"A=1\nMESSAGE\n\x01\x00\x00\x00\x00\x00\x00\x00xy", entries{{{"A", "1"}}}, nil},
+	} {
+		for _, strict := range []bool{false, true} {
+			want := tt.lenient
+			if strict {
+				want = tt.strict
+			}
+			got, err := Decoder{Strict: strict}.Decode([]byte(tt.in))
+			if strict && want == nil {
+				if err == nil {
+					t.Errorf("case %d: expected strict error, got %q", i, got)
+				}
+				continue
+			}
+			if len(want) == 0 {
+				want = nil
+			}
+			if err != nil {
+				t.Errorf("case %d (strict %v): %v", i, strict, err)
+			} else if !reflect.DeepEqual(got, want) {
+				t.Errorf("case %d (strict %v): got %q", i, strict, got)
+			}
+		}
+	}
+}
+
+func TestDecodeSizeLimit(t *testing.T) {
+	for i, in := range []string{
+		"MESSAGE\n\xff\xff\xff\xff\xff\xff\xff\xff\n",
+		"MESSAGE\n\x00\x00\x00\x00\x00\x00\x00\x80x\n",
+		"MESSAGE\n\x01\x00\x00\x30\x00\x00\x00\x00x\n",
+	} {
+		for _, strict := range []bool{false, true} {
+			if _, err := (Decoder{Strict: strict}).Decode([]byte(in)); err == nil {
+				t.Errorf("case %d: expected error", i)
+			} else if _, ok := err.(*SizeError); !ok {
+				t.Errorf("case %d: unexpected error %v", i, err)
+			}
+		}
+	}
+}
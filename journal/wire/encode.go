@@ -0,0 +1,75 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// AppendField appends the encoding of a field to b and returns the extended
+// buffer. Values containing a newline are encoded in the binary safe form,
+// others as a NAME=value line, like sd_journal_send does. The name is not
+// checked; journald drops fields with invalid names.
+func AppendField(b []byte, name, value string) []byte {
+	if !strings.ContainsRune(value, '\n') {
+		b = append(b, name...)
+		b = append(b, '=')
+		b = append(b, value...)
+		return append(b, '\n')
+	}
+	b = append(b, name...)
+	b = append(b, '\n')
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	b = append(b, size[:]...)
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// Encoder encodes journal entries.
+type Encoder struct {
+	// Strict makes encoding fail for fields journald would drop or reject,
+	// instead of encoding them anyway.
+	Strict bool
+}
+
+// Append appends the encoding of a field to b like AppendField and returns
+// the extended buffer. In strict mode, it fails with the unchanged buffer
+// if the name is invalid or the value larger than MaxValueSize.
+func (e Encoder) Append(b []byte, name, value string) ([]byte, error) {
+	if e.Strict {
+		if err := ValidFieldName(name); err != nil {
+			return b, err
+		}
+		if len(value) > MaxValueSize {
+			return b, &SizeError{Name: name, Size: uint64(len(value))}
+		}
+	}
+	return AppendField(b, name, value), nil
+}
+
+// Encode returns the encoding of an entry holding fields, suitable to be
+// sent as a datagram on its own.
+func (e Encoder) Encode(fields []Field) ([]byte, error) {
+	var b []byte
+	for _, f := range fields {
+		var err error
+		if b, err = e.Append(b, f.Name, f.Value); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"testing"
+)
+
+func TestAppendField(t *testing.T) {
+	for i, tt := range []struct {
+		name, value string
+		want        string
+	}{
+		{"MESSAGE", "hello", "MESSAGE=hello\n"},
+		{"EMPTY", "", "EMPTY=\n"},
+		{"EQ", "a=b=", "EQ=a=b=\n"},
+		{"NUL", "a\x00b", "NUL=a\x00b\n"},
+		{"MESSAGE", "two\nlines", "MESSAGE\n\x09\x00\x00\x00\x00\x00\x00\x00two\nlines\n"},
+		{"NL", "\n", "NL\n\x01\x00\x00\x00\x00\x00\x00\x00\n\n"},
+	} {
+		if got := string(AppendField(nil, tt.name, tt.value)); got != tt.want {
+			t.Errorf("case %d: got %q", i, got)
+		}
+	}
+}
+
+func TestEncoderStrict(t *testing.T) {
+	fields := []Field{{"MESSAGE", "hi"}, {"_PID", "1"}}
+	b, err := Encoder{}.Encode(fields)
+	if err != nil || string(b) != "MESSAGE=hi\n_PID=1\n" {
+		t.Errorf("unexpected lenient encoding %q, %v", b, err)
+	}
+	if _, err := (Encoder{Strict: true}).Encode(fields); err == nil {
+		t.Error("expected error for reserved field name")
+	}
+
+	buf := []byte("PRIORITY=6\n")
+	buf, err = Encoder{Strict: true}.Append(buf, "message", "hi")
+	if err == nil || string(buf) != "PRIORITY=6\n" {
+		t.Errorf("unexpected strict encoding %q, %v", buf, err)
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package wire
+
+import (
+	"reflect"
+	"testing"
+)
+
+func FuzzDecode(f *testing.F) {
+	for _, seed := range []string{
+		"MESSAGE=hello\nPRIORITY=6\n",
+		"A=\nB==\n\nC=1\n",
+		"MESSAGE\n\x05\x00\x00\x00\x00\x00\x00\x00a\nb\n\n",
+		"MESSAGE\n\xff\xff\xff\xff\xff\xff\xff\xff\n",
+		"_PID=1\nA=1",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		lenient, lerr := Decoder{}.Decode(b)
+		strict, err := Decoder{Strict: true}.Decode(b)
+		if err != nil {
+			return
+		}
+		if lerr != nil || !reflect.DeepEqual(strict, lenient) {
+			t.Fatalf("strict decoding %q, lenient %q, %v", strict, lenient, lerr)
+		}
+		// Whatever decodes strictly must survive a round trip.
+		for _, fields := range strict {
+			enc, err := Encoder{Strict: true}.Encode(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again, err := Decoder{Strict: true}.Decode(enc)
+			if err != nil || len(again) != 1 || !reflect.DeepEqual(again[0], fields) {
+				t.Fatalf("round trip of %q gave %q, %v", fields, again, err)
+			}
+		}
+	})
+}
+
+func FuzzEncode(f *testing.F) {
+	f.Add("MESSAGE", "hello")
+	f.Add("MESSAGE", "a\nb")
+	f.Add("EMPTY", "")
+	f.Add("EQ", "=\n=")
+	f.Fuzz(func(t *testing.T, name, value string) {
+		enc, err := Encoder{Strict: true}.Encode([]Field{{name, value}})
+		if err != nil {
+			if ValidFieldName(name) == nil {
+				t.Fatalf("valid field %s rejected: %v", name, err)
+			}
+			return
+		}
+		got, err := Decoder{Strict: true}.Decode(enc)
+		if err != nil || len(got) != 1 || !reflect.DeepEqual(got[0], []Field{{name, value}}) {
+			t.Fatalf("round trip of %s=%q gave %q, %v", name, value, got, err)
+		}
+	})
+}
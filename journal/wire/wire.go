@@ -0,0 +1,67 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wire encodes and decodes journal entries in the native protocol
+// journald receives on /run/systemd/journal/socket, following the rules of
+// journald's parser. See https://systemd.io/JOURNAL_NATIVE_PROTOCOL/
+//
+// An entry is a sequence of fields, each either a NAME=value line or, for
+// values that contain newlines or binary data, the name on a line of its own
+// followed by the length of the value as 64-bit little endian integer, the
+// value and a newline. An empty line ends an entry, so a datagram may carry
+// several of them.
+package wire
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxFieldNameLen is the longest field name journald accepts.
+	MaxFieldNameLen = 64
+	// MaxValueSize is the largest binary value journald accepts, larger
+	// ones make it drop the entry.
+	MaxValueSize = 768 << 20
+)
+
+// Field is a field of a journal entry. Fields may be repeated in an entry.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// ValidFieldName returns an error if name is not a field name journald
+// accepts from clients: upper case letters, digits and underscores, not
+// starting with a digit or underscore, at most MaxFieldNameLen long. Names
+// starting with an underscore are reserved for trusted fields added by
+// journald itself.
+func ValidFieldName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("empty field name")
+	case len(name) > MaxFieldNameLen:
+		return fmt.Errorf("field name %.16q... longer than %d bytes", name, MaxFieldNameLen)
+	case name[0] == '_':
+		return fmt.Errorf("field name %q is reserved for trusted fields", name)
+	case '0' <= name[0] && name[0] <= '9':
+		return fmt.Errorf("field name %q begins with a digit", name)
+	}
+	for _, c := range name {
+		if !(('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') || c == '_') {
+			return fmt.Errorf("field name %q contains invalid characters", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestValidFieldName(t *testing.T) {
+	for i, tt := range []struct {
+		name  string
+		valid bool
+	}{
+		{"MESSAGE", true},
+		{"CODE_LINE2", true},
+		{"A", true},
+		{strings.Repeat("A", 64), true},
+		{strings.Repeat("A", 65), false},
+		{"", false},
+		{"_PID", false},
+		{"2FA", false},
+		{"lower", false},
+		{"WITH-DASH", false},
+		{"WITH=EQ", false},
+		{"ÄNDERUNG", false},
+	} {
+		if err := ValidFieldName(tt.name); (err == nil) != tt.valid {
+			t.Errorf("case %d: got %v", i, err)
+		}
+	}
+}
+
+// entry is a random journal entry with valid field names, for testing/quick.
+type entry []Field
+
+func (entry) Generate(r *rand.Rand, size int) reflect.Value {
+	const nameChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
+	e := make(entry, 1+r.Intn(size+1))
+	for i := range e {
+		name := []byte{nameChars[r.Intn(26)]}
+		for n := r.Intn(MaxFieldNameLen); n > 0; n-- {
+			name = append(name, nameChars[r.Intn(len(nameChars))])
+		}
+		value := make([]byte, r.Intn(4*size+1))
+		for j := range value {
+			// Favour the bytes with a meaning in the protocol.
+			switch r.Intn(4) {
+			case 0:
+				value[j] = "\n=\x00"[r.Intn(3)]
+			default:
+				value[j] = byte(r.Intn(256))
+			}
+		}
+		e[i] = Field{Name: string(name), Value: string(value)}
+	}
+	return reflect.ValueOf(e)
+}
+
+func TestRoundTrip(t *testing.T) {
+	roundTrip := func(e entry) bool {
+		b, err := Encoder{Strict: true}.Encode(e)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		entries, err := Decoder{Strict: true}.Decode(b)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+		return len(entries) == 1 && reflect.DeepEqual(entries[0], []Field(e))
+	}
+	if err := quick.Check(roundTrip, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
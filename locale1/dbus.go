@@ -0,0 +1,168 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package locale1 provides integration with the systemd localed API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.locale1.html
+package locale1
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.locale1"
+	dbusInterface = "org.freedesktop.locale1"
+	dbusPath      = "/org/freedesktop/locale1"
+)
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+// Note: systemd-localed will be activated via D-Bus, we don't need to check service status.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+func (c *Conn) getStringProperty(property string) (string, error) {
+	v, err := c.object.GetProperty(dbusInterface + "." + property)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("failed to typecast %s property %s to string", property, v)
+	}
+	return s, nil
+}
+
+// SetLocale sets the system locale from a list of environment variable
+// assignments, e.g. "LANG=de_DE.UTF-8". Variables not listed are unset.
+func (c *Conn) SetLocale(locale []string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetLocale", 0, locale, interactive).Err
+}
+
+// SetVConsoleKeyboard sets the keymap of the virtual console, and optionally
+// a toggle keymap. If convert is set, the X11 keyboard layout is set to the
+// closest match as well.
+func (c *Conn) SetVConsoleKeyboard(keymap, keymapToggle string, convert, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetVConsoleKeyboard", 0, keymap, keymapToggle, convert, interactive).Err
+}
+
+// SetX11Keyboard sets the default keyboard layout of X11 and Wayland
+// sessions. If convert is set, the virtual console keymap is set to the
+// closest match as well.
+func (c *Conn) SetX11Keyboard(layout, model, variant, options string, convert, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetX11Keyboard", 0, layout, model, variant, options, convert, interactive).Err
+}
+
+// Locale returns the system locale as a list of environment variable assignments.
+func (c *Conn) Locale() ([]string, error) {
+	v, err := c.object.GetProperty(dbusInterface + ".Locale")
+	if err != nil {
+		return nil, err
+	}
+	locale, ok := v.Value().([]string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast Locale property %s to []string", v)
+	}
+	return locale, nil
+}
+
+// VConsoleKeymap returns the keymap of the virtual console.
+func (c *Conn) VConsoleKeymap() (string, error) {
+	return c.getStringProperty("VConsoleKeymap")
+}
+
+// VConsoleKeymapToggle returns the toggle keymap of the virtual console.
+func (c *Conn) VConsoleKeymapToggle() (string, error) {
+	return c.getStringProperty("VConsoleKeymapToggle")
+}
+
+// X11Layout returns the default X11 keyboard layout.
+func (c *Conn) X11Layout() (string, error) {
+	return c.getStringProperty("X11Layout")
+}
+
+// X11Model returns the default X11 keyboard model.
+func (c *Conn) X11Model() (string, error) {
+	return c.getStringProperty("X11Model")
+}
+
+// X11Variant returns the default X11 keyboard variant.
+func (c *Conn) X11Variant() (string, error) {
+	return c.getStringProperty("X11Variant")
+}
+
+// X11Options returns the default X11 keyboard options.
+func (c *Conn) X11Options() (string, error) {
+	return c.getStringProperty("X11Options")
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locale1
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNew ensures that New() works without errors.
+func TestNew(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestLocale(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	locale, err := conn.Locale()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range locale {
+		if !strings.Contains(l, "=") {
+			t.Fatalf("invalid locale assignment %q", l)
+		}
+	}
+
+	if _, err := conn.VConsoleKeymap(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.X11Layout(); err != nil {
+		t.Fatal(err)
+	}
+}
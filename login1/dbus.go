@@ -18,9 +18,11 @@ package login1
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/godbus/dbus/v5"
 )
@@ -30,7 +32,80 @@ const (
 	dbusManagerInterface = "org.freedesktop.login1.Manager"
 	dbusSessionInterface = "org.freedesktop.login1.Session"
 	dbusUserInterface    = "org.freedesktop.login1.User"
+	dbusSeatInterface    = "org.freedesktop.login1.Seat"
 	dbusPath             = "/org/freedesktop/login1"
+
+	dbusNoSuchUserError = "org.freedesktop.login1.NoSuchUser"
+)
+
+// SessionSelfPath refers to the session of the calling process.
+const SessionSelfPath = dbus.ObjectPath(dbusPath + "/session/auto")
+
+// Inhibition lock types, for use with InhibitContext. Multiple types may be
+// combined by joining them with a colon.
+const (
+	InhibitWhatShutdown           = "shutdown"
+	InhibitWhatSleep              = "sleep"
+	InhibitWhatIdle               = "idle"
+	InhibitWhatHandlePowerKey     = "handle-power-key"
+	InhibitWhatHandleSuspendKey   = "handle-suspend-key"
+	InhibitWhatHandleHibernateKey = "handle-hibernate-key"
+	InhibitWhatHandleLidSwitch    = "handle-lid-switch"
+)
+
+// Inhibition lock modes, for use with InhibitContext.
+const (
+	// InhibitModeBlock prevents the operation from happening at all while
+	// the lock is held, unless it is explicitly overridden.
+	InhibitModeBlock = "block"
+	// InhibitModeDelay delays the operation until the lock is released or
+	// InhibitDelayMaxUSec has passed.
+	InhibitModeDelay = "delay"
+)
+
+// Results of the Can* capability queries, e.g. CanRebootContext.
+const (
+	// CanYes means the operation is supported and the caller may execute it.
+	CanYes = "yes"
+	// CanNo means the operation is supported but the caller may not execute it.
+	CanNo = "no"
+	// CanChallenge means the caller may execute the operation after authenticating.
+	CanChallenge = "challenge"
+	// CanNA means the operation is not supported by the hardware or configuration.
+	CanNA = "na"
+)
+
+// Flags for the *WithFlagsContext power operations.
+const (
+	// PowerFlagCheckInhibitors makes the operation fail for root too if it is
+	// blocked by an inhibitor, rather than ignoring inhibitors.
+	PowerFlagCheckInhibitors uint64 = 1 << 0
+	// PowerFlagRebootViaKexec reboots into a previously loaded kexec kernel.
+	PowerFlagRebootViaKexec uint64 = 1 << 1
+	// PowerFlagSoftReboot restarts userspace only. Requires systemd v254 or higher.
+	PowerFlagSoftReboot uint64 = 1 << 2
+	// PowerFlagSoftRebootIfNextRootSetUp performs a soft reboot if a new root
+	// file system has been set up in /run/nextroot/. Requires systemd v254 or higher.
+	PowerFlagSoftRebootIfNextRootSetUp uint64 = 1 << 3
+)
+
+// Shutdown kinds, for use with ScheduleShutdownContext.
+const (
+	ShutdownKindPowerOff = "poweroff"
+	ShutdownKindReboot   = "reboot"
+	ShutdownKindHalt     = "halt"
+	// The dry-run kinds only broadcast the warnings, without shutting down.
+	ShutdownKindDryPowerOff = "dry-poweroff"
+	ShutdownKindDryReboot   = "dry-reboot"
+	ShutdownKindDryHalt     = "dry-halt"
+)
+
+// Process selections for KillSessionContext.
+const (
+	// KillWhoLeader signals only the session leader.
+	KillWhoLeader = "leader"
+	// KillWhoAll signals all processes of the session.
+	KillWhoAll = "all"
 )
 
 // Conn is a connection to systemds dbus endpoint.
@@ -111,6 +186,22 @@ type User struct {
 	Path dbus.ObjectPath
 }
 
+// Seat object definition.
+type Seat struct {
+	ID   string
+	Path dbus.ObjectPath
+}
+
+// Inhibitor object definition.
+type Inhibitor struct {
+	What string // Colon-separated list of the inhibited operations
+	Who  string // Human readable description of the locking application
+	Why  string // Human readable description of the reason for the lock
+	Mode string // Either block or delay
+	UID  uint32
+	PID  uint32
+}
+
 func (s Session) toInterface() []interface{} {
 	return []interface{}{s.ID, s.UID, s.User, s.Seat, s.Path}
 }
@@ -165,6 +256,56 @@ func userFromInterfaces(user []interface{}) (*User, error) {
 	return &ret, nil
 }
 
+func seatFromInterfaces(seat []interface{}) (*Seat, error) {
+	if len(seat) < 2 {
+		return nil, fmt.Errorf("invalid number of seat fields: %d", len(seat))
+	}
+	id, ok := seat[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast seat field 0 to string")
+	}
+	path, ok := seat[1].(dbus.ObjectPath)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast seat field 1 to ObjectPath")
+	}
+
+	ret := Seat{ID: id, Path: path}
+	return &ret, nil
+}
+
+func inhibitorFromInterfaces(inhibitor []interface{}) (*Inhibitor, error) {
+	if len(inhibitor) < 6 {
+		return nil, fmt.Errorf("invalid number of inhibitor fields: %d", len(inhibitor))
+	}
+	what, ok := inhibitor[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast inhibitor field 0 to string")
+	}
+	who, ok := inhibitor[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast inhibitor field 1 to string")
+	}
+	why, ok := inhibitor[2].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast inhibitor field 2 to string")
+	}
+	mode, ok := inhibitor[3].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast inhibitor field 3 to string")
+	}
+	uid, ok := inhibitor[4].(uint32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast inhibitor field 4 to uint32")
+	}
+	pid, ok := inhibitor[5].(uint32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast inhibitor field 5 to uint32")
+	}
+
+	ret := Inhibitor{What: what, Who: who, Why: why, Mode: mode, UID: uid, PID: pid}
+	return &ret, nil
+}
+
 // GetActiveSession may be used to get the session object path for the current active session
 func (c *Conn) GetActiveSession() (dbus.ObjectPath, error) {
 	var seat0Path dbus.ObjectPath
@@ -256,6 +397,36 @@ func (c *Conn) GetSession(id string) (dbus.ObjectPath, error) {
 	return ret, nil
 }
 
+// GetSessionByPIDContext may be used to get the session object path for the session a process belongs to.
+func (c *Conn) GetSessionByPIDContext(ctx context.Context, pid uint32) (dbus.ObjectPath, error) {
+	var out dbus.ObjectPath
+	if err := c.object.CallWithContext(ctx, dbusManagerInterface+".GetSessionByPID", 0, pid).Store(&out); err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
+
+// GetUserContext may be used to get the user object path for the user with the specified UID.
+func (c *Conn) GetUserContext(ctx context.Context, uid uint32) (dbus.ObjectPath, error) {
+	var out dbus.ObjectPath
+	if err := c.object.CallWithContext(ctx, dbusManagerInterface+".GetUser", 0, uid).Store(&out); err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
+
+// GetSeatContext may be used to get the seat object path for the seat with the specified ID.
+func (c *Conn) GetSeatContext(ctx context.Context, id string) (dbus.ObjectPath, error) {
+	var out dbus.ObjectPath
+	if err := c.object.CallWithContext(ctx, dbusManagerInterface+".GetSeat", 0, id).Store(&out); err != nil {
+		return "", err
+	}
+
+	return out, nil
+}
+
 // Deprecated: use ListSessionsContext instead.
 func (c *Conn) ListSessions() ([]Session, error) {
 	return c.ListSessionsContext(context.Background())
@@ -302,6 +473,24 @@ func (c *Conn) ListUsersContext(ctx context.Context) ([]User, error) {
 	return ret, nil
 }
 
+// ListSeatsContext returns an array with all currently available seats.
+func (c *Conn) ListSeatsContext(ctx context.Context) ([]Seat, error) {
+	out := [][]interface{}{}
+	if err := c.object.CallWithContext(ctx, dbusManagerInterface+".ListSeats", 0).Store(&out); err != nil {
+		return nil, err
+	}
+
+	ret := []Seat{}
+	for _, el := range out {
+		seat, err := seatFromInterfaces(el)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *seat)
+	}
+	return ret, nil
+}
+
 // GetSessionPropertiesContext takes a session path and returns all of its dbus object properties.
 func (c *Conn) GetSessionPropertiesContext(ctx context.Context, sessionPath dbus.ObjectPath) (map[string]dbus.Variant, error) {
 	return c.getProperties(ctx, sessionPath, dbusSessionInterface)
@@ -322,36 +511,184 @@ func (c *Conn) GetUserPropertyContext(ctx context.Context, userPath dbus.ObjectP
 	return c.getProperty(ctx, userPath, dbusUserInterface, property)
 }
 
-// LockSession asks the session with the specified ID to activate the screen lock.
+// GetSeatPropertiesContext takes a seat path and returns all of its dbus object properties.
+func (c *Conn) GetSeatPropertiesContext(ctx context.Context, seatPath dbus.ObjectPath) (map[string]dbus.Variant, error) {
+	return c.getProperties(ctx, seatPath, dbusSeatInterface)
+}
+
+// GetSeatPropertyContext takes a seat path and a property name and returns the property value.
+func (c *Conn) GetSeatPropertyContext(ctx context.Context, seatPath dbus.ObjectPath, property string) (*dbus.Variant, error) {
+	return c.getProperty(ctx, seatPath, dbusSeatInterface, property)
+}
+
+// Deprecated: use LockSessionContext instead.
 func (c *Conn) LockSession(id string) {
-	c.object.Call(dbusManagerInterface+".LockSession", 0, id)
+	c.LockSessionContext(context.Background(), id)
+}
+
+// LockSessionContext asks the session with the specified ID to activate the screen lock.
+func (c *Conn) LockSessionContext(ctx context.Context, id string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".LockSession", 0, id).Store()
 }
 
-// LockSessions asks all sessions to activate the screen locks. This may be used to lock any access to the machine in one action.
+// UnlockSessionContext asks the session with the specified ID to remove an active screen lock.
+func (c *Conn) UnlockSessionContext(ctx context.Context, id string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".UnlockSession", 0, id).Store()
+}
+
+// Deprecated: use LockSessionsContext instead.
 func (c *Conn) LockSessions() {
-	c.object.Call(dbusManagerInterface+".LockSessions", 0)
+	c.LockSessionsContext(context.Background())
+}
+
+// LockSessionsContext asks all sessions to activate the screen locks. This may be used to lock any access to the machine in one action.
+func (c *Conn) LockSessionsContext(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".LockSessions", 0).Store()
 }
 
-// TerminateSession forcibly terminate one specific session.
+// UnlockSessionsContext asks all sessions to remove their screen locks.
+func (c *Conn) UnlockSessionsContext(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".UnlockSessions", 0).Store()
+}
+
+// ActivateSessionContext brings the session with the specified ID into the foreground of its seat.
+func (c *Conn) ActivateSessionContext(ctx context.Context, id string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".ActivateSession", 0, id).Store()
+}
+
+// ActivateSessionOnSeatContext brings the session with the specified ID into
+// the foreground, checking that it is assigned to the given seat.
+func (c *Conn) ActivateSessionOnSeatContext(ctx context.Context, id, seat string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".ActivateSessionOnSeat", 0, id, seat).Store()
+}
+
+// Deprecated: use TerminateSessionContext instead.
 func (c *Conn) TerminateSession(id string) {
-	c.object.Call(dbusManagerInterface+".TerminateSession", 0, id)
+	c.TerminateSessionContext(context.Background(), id)
+}
+
+// TerminateSessionContext forcibly terminate one specific session.
+func (c *Conn) TerminateSessionContext(ctx context.Context, id string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".TerminateSession", 0, id).Store()
 }
 
-// TerminateUser forcibly terminates all processes of a user.
+// Deprecated: use TerminateUserContext instead.
 func (c *Conn) TerminateUser(uid uint32) {
-	c.object.Call(dbusManagerInterface+".TerminateUser", 0, uid)
+	c.TerminateUserContext(context.Background(), uid)
 }
 
-// Reboot asks logind for a reboot optionally asking for auth.
+// TerminateUserContext forcibly terminates all processes of a user.
+func (c *Conn) TerminateUserContext(ctx context.Context, uid uint32) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".TerminateUser", 0, uid).Store()
+}
+
+// TerminateSeatContext forcibly terminates all sessions on a seat.
+func (c *Conn) TerminateSeatContext(ctx context.Context, id string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".TerminateSeat", 0, id).Store()
+}
+
+// KillSessionContext sends a Unix signal to the processes of a session. who
+// selects whether only the session leader (KillWhoLeader) or all processes
+// of the session (KillWhoAll) are signalled.
+func (c *Conn) KillSessionContext(ctx context.Context, id, who string, signal int32) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".KillSession", 0, id, who, signal).Store()
+}
+
+// KillUserContext sends a Unix signal to all processes of a user.
+func (c *Conn) KillUserContext(ctx context.Context, uid uint32, signal int32) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".KillUser", 0, uid, signal).Store()
+}
+
+// AttachDeviceContext permanently assigns the device at the given sysfs path,
+// e.g. a USB hub or graphics card, and all devices below it to a seat. The
+// seat is created if it does not exist yet, once a graphics device is
+// attached to it.
+func (c *Conn) AttachDeviceContext(ctx context.Context, seat, sysfsPath string, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".AttachDevice", 0, seat, sysfsPath, askForAuth).Store()
+}
+
+// FlushDevicesContext removes all device assignments made with
+// AttachDeviceContext, moving the devices back to seat0.
+func (c *Conn) FlushDevicesContext(ctx context.Context, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".FlushDevices", 0, askForAuth).Store()
+}
+
+// SetIdleHintContext sets the idle hint of a session. Only processes of the
+// session itself may do this, usually via SessionSelfPath.
+func (c *Conn) SetIdleHintContext(ctx context.Context, sessionPath dbus.ObjectPath, idle bool) error {
+	return c.conn.Object(dbusDest, sessionPath).CallWithContext(ctx, dbusSessionInterface+".SetIdleHint", 0, idle).Store()
+}
+
+// SetLockedHintContext sets the locked hint of a session, e.g. from a screen
+// locker. Only processes of the session itself may do this, usually via
+// SessionSelfPath.
+func (c *Conn) SetLockedHintContext(ctx context.Context, sessionPath dbus.ObjectPath, locked bool) error {
+	return c.conn.Object(dbusDest, sessionPath).CallWithContext(ctx, dbusSessionInterface+".SetLockedHint", 0, locked).Store()
+}
+
+// SetUserLingerContext enables or disables lingering for a user: if enabled,
+// the user's service manager is started at boot and kept running after the
+// user logs out.
+func (c *Conn) SetUserLingerContext(ctx context.Context, uid uint32, enable, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SetUserLinger", 0, uid, enable, askForAuth).Store()
+}
+
+// EnableUserLingerContext enables lingering for a user.
+func (c *Conn) EnableUserLingerContext(ctx context.Context, uid uint32, askForAuth bool) error {
+	return c.SetUserLingerContext(ctx, uid, true, askForAuth)
+}
+
+// DisableUserLingerContext disables lingering for a user.
+func (c *Conn) DisableUserLingerContext(ctx context.Context, uid uint32, askForAuth bool) error {
+	return c.SetUserLingerContext(ctx, uid, false, askForAuth)
+}
+
+// GetUserLingerContext returns whether lingering is enabled for a user.
+// logind only tracks users which are logged in or lingering, so a user
+// unknown to logind is reported as not lingering.
+func (c *Conn) GetUserLingerContext(ctx context.Context, uid uint32) (bool, error) {
+	userPath, err := c.GetUserContext(ctx, uid)
+	if err != nil {
+		if dbusErr, ok := err.(dbus.Error); ok && dbusErr.Name == dbusNoSuchUserError {
+			return false, nil
+		}
+		return false, err
+	}
+
+	linger, err := c.GetUserPropertyContext(ctx, userPath, "Linger")
+	if err != nil {
+		return false, err
+	}
+	enabled, ok := linger.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("failed to typecast Linger property %s to bool", linger)
+	}
+	return enabled, nil
+}
+
+// Deprecated: use RebootContext instead.
 func (c *Conn) Reboot(askForAuth bool) {
-	c.object.Call(dbusManagerInterface+".Reboot", 0, askForAuth)
+	c.RebootContext(context.Background(), askForAuth)
 }
 
-// Inhibit takes inhibition lock in logind.
+// Deprecated: use InhibitContext instead.
 func (c *Conn) Inhibit(what, who, why, mode string) (*os.File, error) {
+	return c.InhibitContext(context.Background(), what, who, why, mode)
+}
+
+// InhibitContext takes inhibition lock in logind. what is a colon-separated
+// list of lock types (see the InhibitWhat* constants), who and why are human
+// readable descriptions of the locking application and the reason, and mode
+// is either InhibitModeBlock or InhibitModeDelay.
+//
+// The lock is held for as long as the returned file is open; closing it
+// releases the lock. Delay locks should be released as soon as the pending
+// operation has been prepared for, logind will proceed anyway once
+// InhibitDelayMaxUSec has passed (see GetInhibitDelayMaxContext).
+func (c *Conn) InhibitContext(ctx context.Context, what, who, why, mode string) (*os.File, error) {
 	var fd dbus.UnixFD
 
-	err := c.object.Call(dbusManagerInterface+".Inhibit", 0, what, who, why, mode).Store(&fd)
+	err := c.object.CallWithContext(ctx, dbusManagerInterface+".Inhibit", 0, what, who, why, mode).Store(&fd)
 	if err != nil {
 		return nil, err
 	}
@@ -359,6 +696,39 @@ func (c *Conn) Inhibit(what, who, why, mode string) (*os.File, error) {
 	return os.NewFile(uintptr(fd), "inhibit"), nil
 }
 
+// ListInhibitorsContext returns an array with all currently active inhibition locks.
+func (c *Conn) ListInhibitorsContext(ctx context.Context) ([]Inhibitor, error) {
+	out := [][]interface{}{}
+	if err := c.object.CallWithContext(ctx, dbusManagerInterface+".ListInhibitors", 0).Store(&out); err != nil {
+		return nil, err
+	}
+
+	ret := []Inhibitor{}
+	for _, el := range out {
+		inhibitor, err := inhibitorFromInterfaces(el)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *inhibitor)
+	}
+	return ret, nil
+}
+
+// GetInhibitDelayMaxContext returns the maximum time logind waits for delay
+// inhibitors to be released before proceeding with shutdown or sleep.
+func (c *Conn) GetInhibitDelayMaxContext(ctx context.Context) (time.Duration, error) {
+	prop, err := c.getProperty(ctx, dbusPath, dbusManagerInterface, "InhibitDelayMaxUSec")
+	if err != nil {
+		return 0, err
+	}
+
+	usec, ok := prop.Value().(uint64)
+	if !ok {
+		return 0, fmt.Errorf("failed to typecast InhibitDelayMaxUSec to uint64")
+	}
+	return time.Duration(usec) * time.Microsecond, nil
+}
+
 // Subscribe to signals on the logind dbus
 func (c *Conn) Subscribe(members ...string) chan *dbus.Signal {
 	for _, member := range members {
@@ -370,9 +740,255 @@ func (c *Conn) Subscribe(members ...string) chan *dbus.Signal {
 	return ch
 }
 
-// PowerOff asks logind for a power off optionally asking for auth.
+// Deprecated: use PowerOffContext instead.
 func (c *Conn) PowerOff(askForAuth bool) {
-	c.object.Call(dbusManagerInterface+".PowerOff", 0, askForAuth)
+	c.PowerOffContext(context.Background(), askForAuth)
+}
+
+// PowerOffContext asks logind for a power off optionally asking for auth.
+func (c *Conn) PowerOffContext(ctx context.Context, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".PowerOff", 0, askForAuth).Store()
+}
+
+// RebootContext asks logind for a reboot optionally asking for auth.
+func (c *Conn) RebootContext(ctx context.Context, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".Reboot", 0, askForAuth).Store()
+}
+
+// HaltContext asks logind for a halt optionally asking for auth.
+func (c *Conn) HaltContext(ctx context.Context, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".Halt", 0, askForAuth).Store()
+}
+
+// SuspendContext asks logind to suspend the system optionally asking for auth.
+func (c *Conn) SuspendContext(ctx context.Context, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".Suspend", 0, askForAuth).Store()
+}
+
+// HibernateContext asks logind to hibernate the system optionally asking for auth.
+func (c *Conn) HibernateContext(ctx context.Context, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".Hibernate", 0, askForAuth).Store()
+}
+
+// HybridSleepContext asks logind to hibernate and suspend the system optionally asking for auth.
+func (c *Conn) HybridSleepContext(ctx context.Context, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".HybridSleep", 0, askForAuth).Store()
+}
+
+// SuspendThenHibernateContext asks logind to suspend the system and hibernate
+// it after a delay, optionally asking for auth.
+func (c *Conn) SuspendThenHibernateContext(ctx context.Context, askForAuth bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SuspendThenHibernate", 0, askForAuth).Store()
+}
+
+// PowerOffWithFlagsContext is like PowerOffContext, but takes a set of
+// PowerFlag* values instead. Requires systemd v246 or higher.
+func (c *Conn) PowerOffWithFlagsContext(ctx context.Context, flags uint64) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".PowerOffWithFlags", 0, flags).Store()
+}
+
+// RebootWithFlagsContext is like RebootContext, but takes a set of PowerFlag*
+// values instead, e.g. PowerFlagRebootViaKexec. Requires systemd v246 or higher.
+func (c *Conn) RebootWithFlagsContext(ctx context.Context, flags uint64) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".RebootWithFlags", 0, flags).Store()
+}
+
+// HaltWithFlagsContext is like HaltContext, but takes a set of PowerFlag*
+// values instead. Requires systemd v246 or higher.
+func (c *Conn) HaltWithFlagsContext(ctx context.Context, flags uint64) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".HaltWithFlags", 0, flags).Store()
+}
+
+// SuspendWithFlagsContext is like SuspendContext, but takes a set of
+// PowerFlag* values instead. Requires systemd v246 or higher.
+func (c *Conn) SuspendWithFlagsContext(ctx context.Context, flags uint64) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SuspendWithFlags", 0, flags).Store()
+}
+
+// HibernateWithFlagsContext is like HibernateContext, but takes a set of
+// PowerFlag* values instead. Requires systemd v246 or higher.
+func (c *Conn) HibernateWithFlagsContext(ctx context.Context, flags uint64) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".HibernateWithFlags", 0, flags).Store()
+}
+
+// HybridSleepWithFlagsContext is like HybridSleepContext, but takes a set of
+// PowerFlag* values instead. Requires systemd v246 or higher.
+func (c *Conn) HybridSleepWithFlagsContext(ctx context.Context, flags uint64) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".HybridSleepWithFlags", 0, flags).Store()
+}
+
+// SuspendThenHibernateWithFlagsContext is like SuspendThenHibernateContext,
+// but takes a set of PowerFlag* values instead. Requires systemd v246 or higher.
+func (c *Conn) SuspendThenHibernateWithFlagsContext(ctx context.Context, flags uint64) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SuspendThenHibernateWithFlags", 0, flags).Store()
+}
+
+func (c *Conn) canQuery(ctx context.Context, method string) (string, error) {
+	var out string
+	if err := c.object.CallWithContext(ctx, dbusManagerInterface+"."+method, 0).Store(&out); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// CanPowerOffContext returns whether the caller may power off the system: one
+// of the Can* constants.
+func (c *Conn) CanPowerOffContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanPowerOff")
+}
+
+// CanRebootContext returns whether the caller may reboot the system: one of
+// the Can* constants.
+func (c *Conn) CanRebootContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanReboot")
+}
+
+// CanHaltContext returns whether the caller may halt the system: one of the
+// Can* constants.
+func (c *Conn) CanHaltContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanHalt")
+}
+
+// CanSuspendContext returns whether the caller may suspend the system: one of
+// the Can* constants.
+func (c *Conn) CanSuspendContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanSuspend")
+}
+
+// CanHibernateContext returns whether the caller may hibernate the system:
+// one of the Can* constants.
+func (c *Conn) CanHibernateContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanHibernate")
+}
+
+// CanHybridSleepContext returns whether the caller may hybrid-sleep the
+// system: one of the Can* constants.
+func (c *Conn) CanHybridSleepContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanHybridSleep")
+}
+
+// CanSuspendThenHibernateContext returns whether the caller may
+// suspend-then-hibernate the system: one of the Can* constants.
+func (c *Conn) CanSuspendThenHibernateContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanSuspendThenHibernate")
+}
+
+// CanRebootParameterContext returns whether the caller may set a reboot
+// parameter: one of the Can* constants.
+func (c *Conn) CanRebootParameterContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanRebootParameter")
+}
+
+// CanRebootToFirmwareSetupContext returns whether the caller may request a
+// reboot into the firmware setup: one of the Can* constants.
+func (c *Conn) CanRebootToFirmwareSetupContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanRebootToFirmwareSetup")
+}
+
+// CanRebootToBootLoaderMenuContext returns whether the caller may request a
+// reboot into the boot loader menu: one of the Can* constants.
+func (c *Conn) CanRebootToBootLoaderMenuContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanRebootToBootLoaderMenu")
+}
+
+// CanRebootToBootLoaderEntryContext returns whether the caller may request a
+// reboot into a specific boot loader entry: one of the Can* constants.
+func (c *Conn) CanRebootToBootLoaderEntryContext(ctx context.Context) (string, error) {
+	return c.canQuery(ctx, "CanRebootToBootLoaderEntry")
+}
+
+// SetRebootParameterContext sets the parameter passed to the reboot(2)
+// system call on the next reboot, e.g. to select a firmware boot mode on
+// some ARM devices. An empty parameter clears it.
+func (c *Conn) SetRebootParameterContext(ctx context.Context, parameter string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SetRebootParameter", 0, parameter).Store()
+}
+
+// SetRebootToFirmwareSetupContext requests that the next boot enters the
+// firmware setup instead of booting the operating system.
+func (c *Conn) SetRebootToFirmwareSetupContext(ctx context.Context, enable bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SetRebootToFirmwareSetup", 0, enable).Store()
+}
+
+// SetRebootToBootLoaderMenuContext requests that the boot loader shows its
+// menu on the next boot, for at most the given timeout. A zero timeout shows
+// the menu until a selection is made, a negative one clears the request.
+func (c *Conn) SetRebootToBootLoaderMenuContext(ctx context.Context, timeout time.Duration) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SetRebootToBootLoaderMenu", 0, bootLoaderMenuTimeout(timeout)).Store()
+}
+
+// bootLoaderMenuTimeout converts timeout to microseconds, mapping negative
+// timeouts to the maximum value, which logind takes to clear the request.
+func bootLoaderMenuTimeout(timeout time.Duration) uint64 {
+	if timeout < 0 {
+		return math.MaxUint64
+	}
+	return uint64(timeout / time.Microsecond)
+}
+
+// SetRebootToBootLoaderEntryContext requests that the boot loader boots the
+// given entry once on the next boot. An empty entry clears the request.
+func (c *Conn) SetRebootToBootLoaderEntryContext(ctx context.Context, entry string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SetRebootToBootLoaderEntry", 0, entry).Store()
+}
+
+// SetWallMessageContext sets the message broadcast to all terminals when a
+// shutdown is scheduled or started, and whether it is broadcast at all.
+func (c *Conn) SetWallMessageContext(ctx context.Context, message string, enable bool) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".SetWallMessage", 0, message, enable).Store()
+}
+
+// ScheduleShutdownContext schedules a shutdown of the given ShutdownKind* at
+// the given time. Users are warned via wall messages as the time approaches.
+// A zero time schedules the shutdown immediately.
+func (c *Conn) ScheduleShutdownContext(ctx context.Context, kind string, at time.Time) error {
+	var usec uint64
+	if !at.IsZero() {
+		usec = uint64(at.UnixNano() / int64(time.Microsecond))
+	}
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".ScheduleShutdown", 0, kind, usec).Store()
+}
+
+// CancelScheduledShutdownContext cancels a scheduled shutdown, returning
+// whether one was pending.
+func (c *Conn) CancelScheduledShutdownContext(ctx context.Context) (bool, error) {
+	var cancelled bool
+	if err := c.object.CallWithContext(ctx, dbusManagerInterface+".CancelScheduledShutdown", 0).Store(&cancelled); err != nil {
+		return false, err
+	}
+	return cancelled, nil
+}
+
+// GetScheduledShutdownContext returns the kind and time of the currently
+// scheduled shutdown. The kind is empty if no shutdown is scheduled. logind
+// does not report dry runs, so a shutdown scheduled as e.g.
+// ShutdownKindDryReboot is returned as ShutdownKindReboot.
+func (c *Conn) GetScheduledShutdownContext(ctx context.Context) (string, time.Time, error) {
+	v, err := c.getProperty(ctx, dbusPath, dbusManagerInterface, "ScheduledShutdown")
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	kind, usec, err := scheduledShutdownFromVariant(*v)
+	if err != nil || kind == "" {
+		return "", time.Time{}, err
+	}
+	return kind, time.Unix(0, int64(usec)*int64(time.Microsecond)), nil
+}
+
+func scheduledShutdownFromVariant(v dbus.Variant) (string, uint64, error) {
+	shutdown, ok := v.Value().([]interface{})
+	if !ok || len(shutdown) < 2 {
+		return "", 0, fmt.Errorf("failed to typecast ScheduledShutdown property %s", v)
+	}
+	kind, ok := shutdown[0].(string)
+	if !ok {
+		return "", 0, fmt.Errorf("failed to typecast ScheduledShutdown field 0 to string")
+	}
+	usec, ok := shutdown[1].(uint64)
+	if !ok {
+		return "", 0, fmt.Errorf("failed to typecast ScheduledShutdown field 1 to uint64")
+	}
+	return kind, usec, nil
 }
 
 func (c *Conn) getProperties(ctx context.Context, path dbus.ObjectPath, dbusInterface string) (map[string]dbus.Variant, error) {
@@ -17,6 +17,8 @@ package login1
 import (
 	"context"
 	"fmt"
+	"math"
+	"os"
 	"os/user"
 	"regexp"
 	"testing"
@@ -191,3 +193,257 @@ func TestConn_GetUserPropertyContext(t *testing.T) {
 		}()
 	}
 }
+
+func TestListSeats(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seats, err := c.ListSeatsContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range seats {
+		props, err := c.DescribeSeatContext(context.Background(), s.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if props.ID != s.ID {
+			t.Fatalf("expected seat ID '%s' but got '%s'", s.ID, props.ID)
+		}
+	}
+}
+
+func TestGetSessionByPID(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessions, err := c.ListSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range sessions {
+		props, err := c.DescribeSessionContext(context.Background(), s.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if props.Leader == 0 {
+			continue
+		}
+
+		path, err := c.GetSessionByPIDContext(context.Background(), props.Leader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != s.Path {
+			t.Fatalf("expected session path '%s' but got '%s'", s.Path, path)
+		}
+	}
+}
+
+func TestInhibit(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	why := "go-systemd inhibitor test " + fmt.Sprint(time.Now().UnixNano())
+	lock, err := c.InhibitContext(context.Background(), InhibitWhatSleep, "go-systemd", why, InhibitModeDelay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inhibitors, err := c.ListInhibitorsContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found *Inhibitor
+	for i := range inhibitors {
+		if inhibitors[i].Why == why {
+			found = &inhibitors[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("inhibitor %q not listed", why)
+	}
+	if found.What != InhibitWhatSleep || found.Mode != InhibitModeDelay {
+		t.Fatalf("unexpected inhibitor %+v", found)
+	}
+
+	if err := lock.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCanPowerOperations(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, query := range map[string]func(context.Context) (string, error){
+		"CanPowerOff":  c.CanPowerOffContext,
+		"CanReboot":    c.CanRebootContext,
+		"CanSuspend":   c.CanSuspendContext,
+		"CanHibernate": c.CanHibernateContext,
+	} {
+		res, err := query(context.Background())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		switch res {
+		case CanYes, CanNo, CanChallenge, CanNA:
+		default:
+			t.Fatalf("%s: unexpected result %q", name, res)
+		}
+	}
+}
+
+func TestSessionControlUnknownSession(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const id = "go-systemd-nonexistent"
+	ctx := context.Background()
+	if err := c.LockSessionContext(ctx, id); err == nil {
+		t.Fatal("expected error locking unknown session")
+	}
+	if err := c.ActivateSessionContext(ctx, id); err == nil {
+		t.Fatal("expected error activating unknown session")
+	}
+	if err := c.KillSessionContext(ctx, id, KillWhoAll, 0); err == nil {
+		t.Fatal("expected error killing unknown session")
+	}
+	if err := c.ReleaseSessionContext(ctx, id); err == nil {
+		t.Fatal("expected error releasing unknown session")
+	}
+}
+
+func TestCreateSessionUnprivileged(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("creating sessions is allowed for root")
+	}
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := c.CreateSessionContext(context.Background(), SessionRequest{
+		UID:     uint32(os.Getuid()),
+		Service: "go-systemd",
+		Type:    SessionTypeUnspecified,
+		Class:   SessionClassBackground,
+	})
+	if err == nil {
+		s.Close()
+		t.Fatal("expected error creating session without privileges")
+	}
+}
+
+func TestGetUserLinger(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := c.ListUsers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, u := range users {
+		props, err := c.DescribeUserContext(context.Background(), u.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		linger, err := c.GetUserLingerContext(context.Background(), u.UID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if linger != props.Linger {
+			t.Fatalf("expected linger %v for uid %d but got %v", props.Linger, u.UID, linger)
+		}
+	}
+}
+
+func TestSeatActiveSession(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seats, err := c.ListSeatsContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range seats {
+		props, err := c.DescribeSeatContext(context.Background(), s.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if props.ActiveSession == "" {
+			continue
+		}
+		path, err := c.GetSession(props.ActiveSession)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != props.ActiveSessionPath {
+			t.Fatalf("expected active session path '%s' but got '%s'", path, props.ActiveSessionPath)
+		}
+	}
+}
+
+func TestScheduleShutdown(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	at := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	if err := c.ScheduleShutdownContext(ctx, ShutdownKindDryReboot, at); err != nil {
+		t.Fatal(err)
+	}
+
+	kind, scheduled, err := c.GetScheduledShutdownContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// logind reports dry runs without the "dry-" prefix.
+	if kind != ShutdownKindReboot || !scheduled.Equal(at) {
+		t.Fatalf("expected %s at %v but got %s at %v", ShutdownKindReboot, at, kind, scheduled)
+	}
+
+	cancelled, err := c.CancelScheduledShutdownContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cancelled {
+		t.Fatal("scheduled shutdown was not cancelled")
+	}
+}
+
+func TestBootLoaderMenuTimeout(t *testing.T) {
+	for i, tt := range []struct {
+		in  time.Duration
+		out uint64
+	}{
+		{0, 0},
+		{30 * time.Second, 30000000},
+		{-1, math.MaxUint64},
+	} {
+		if out := bootLoaderMenuTimeout(tt.in); out != tt.out {
+			t.Errorf("case %d: expected %d, got %d", i, tt.out, out)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login1
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Pause types of PauseDevice signals.
+const (
+	// DevicePauseForce means the device was paused already.
+	DevicePauseForce = "force"
+	// DevicePausePause means the session controller should stop using the
+	// device and acknowledge it with PauseDeviceCompleteContext, after
+	// which logind pauses it.
+	DevicePausePause = "pause"
+	// DevicePauseGone means the device was removed.
+	DevicePauseGone = "gone"
+)
+
+// TakeControlContext makes this connection the controller of a session, as
+// display servers and compositors are, which lets it take the session's
+// devices with TakeDeviceContext. Control is released when the connection
+// is closed, so it must stay open for as long as the session is controlled.
+// Unless force is set, taking control fails if the session has a
+// controller already; only root may force it.
+func (c *Conn) TakeControlContext(ctx context.Context, sessionPath dbus.ObjectPath, force bool) error {
+	return c.conn.Object(dbusDest, sessionPath).CallWithContext(ctx, dbusSessionInterface+".TakeControl", 0, force).Store()
+}
+
+// ReleaseControlContext gives up control of a session taken with
+// TakeControlContext, releasing all its devices.
+func (c *Conn) ReleaseControlContext(ctx context.Context, sessionPath dbus.ObjectPath) error {
+	return c.conn.Object(dbusDest, sessionPath).CallWithContext(ctx, dbusSessionInterface+".ReleaseControl", 0).Store()
+}
+
+// Device is a device node opened by logind for a session controller.
+type Device struct {
+	Major, Minor uint32
+	// File is the opened device node. DRM devices are revoked while the
+	// session is inactive, input devices are muted, and a new file is
+	// passed on every ResumeDevice signal.
+	File *os.File
+	// Inactive is set if the session is not active, in which case the
+	// device starts paused.
+	Inactive bool
+}
+
+// TakeDeviceContext opens the device node with the given device number for
+// the controller of a session, see TakeControlContext.
+func (c *Conn) TakeDeviceContext(ctx context.Context, sessionPath dbus.ObjectPath, major, minor uint32) (*Device, error) {
+	var fd dbus.UnixFD
+	d := &Device{Major: major, Minor: minor}
+	err := c.conn.Object(dbusDest, sessionPath).CallWithContext(ctx, dbusSessionInterface+".TakeDevice", 0, major, minor).Store(&fd, &d.Inactive)
+	if err != nil {
+		return nil, err
+	}
+	d.File = os.NewFile(uintptr(fd), fmt.Sprintf("device-%d:%d", major, minor))
+	return d, nil
+}
+
+// ReleaseDeviceContext releases a device taken with TakeDeviceContext.
+func (c *Conn) ReleaseDeviceContext(ctx context.Context, sessionPath dbus.ObjectPath, major, minor uint32) error {
+	return c.conn.Object(dbusDest, sessionPath).CallWithContext(ctx, dbusSessionInterface+".ReleaseDevice", 0, major, minor).Store()
+}
+
+// PauseDeviceCompleteContext acknowledges a PauseDevice signal of the
+// DevicePausePause type, once the controller stopped using the device.
+func (c *Conn) PauseDeviceCompleteContext(ctx context.Context, sessionPath dbus.ObjectPath, major, minor uint32) error {
+	return c.conn.Object(dbusDest, sessionPath).CallWithContext(ctx, dbusSessionInterface+".PauseDeviceComplete", 0, major, minor).Store()
+}
+
+// DeviceEvent is a PauseDevice or ResumeDevice signal of a session.
+type DeviceEvent struct {
+	Major, Minor uint32
+	// Pause is the pause type, one of the DevicePause* constants, and empty
+	// if the device was resumed.
+	Pause string
+	// File is the device node reopened on resume, which replaces the
+	// previous one, nil for pause events.
+	File *os.File
+}
+
+// WatchDevicesContext returns a channel receiving the PauseDevice and
+// ResumeDevice signals of a session, which logind sends to its controller
+// when the session is switched away from and back to. The channel is closed
+// when ctx is done.
+func (c *Conn) WatchDevicesContext(ctx context.Context, sessionPath dbus.ObjectPath) (<-chan DeviceEvent, error) {
+	// Cancelling ctx removes the PauseDevice match if subscribing to
+	// ResumeDevice fails.
+	ctx, cancel := context.WithCancel(ctx)
+	pause, err := c.subscribeSignal(ctx, sessionPath, dbusSessionInterface, "PauseDevice")
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resume, err := c.subscribeSignal(ctx, sessionPath, dbusSessionInterface, "ResumeDevice")
+	if err != nil {
+		cancel()
+		for range pause {
+		}
+		return nil, err
+	}
+
+	out := make(chan DeviceEvent)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for pause != nil || resume != nil {
+			var sig *dbus.Signal
+			var ok bool
+			select {
+			case sig, ok = <-pause:
+				if !ok {
+					pause = nil
+					continue
+				}
+			case sig, ok = <-resume:
+				if !ok {
+					resume = nil
+					continue
+				}
+			}
+			event, err := deviceEventFromSignal(sig)
+			if err != nil {
+				continue
+			}
+			select {
+			case out <- *event:
+			case <-ctx.Done():
+				if event.File != nil {
+					event.File.Close()
+				}
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func deviceEventFromSignal(sig *dbus.Signal) (*DeviceEvent, error) {
+	if len(sig.Body) != 3 {
+		return nil, fmt.Errorf("invalid number of %s fields: %d", sig.Name, len(sig.Body))
+	}
+	var event DeviceEvent
+	var ok bool
+	if event.Major, ok = sig.Body[0].(uint32); !ok {
+		return nil, fmt.Errorf("failed to typecast %s field 0 to uint32", sig.Name)
+	}
+	if event.Minor, ok = sig.Body[1].(uint32); !ok {
+		return nil, fmt.Errorf("failed to typecast %s field 1 to uint32", sig.Name)
+	}
+	switch sig.Name {
+	case dbusSessionInterface + ".PauseDevice":
+		if event.Pause, ok = sig.Body[2].(string); !ok {
+			return nil, fmt.Errorf("failed to typecast %s field 2 to string", sig.Name)
+		}
+	case dbusSessionInterface + ".ResumeDevice":
+		fd, ok := sig.Body[2].(dbus.UnixFD)
+		if !ok {
+			return nil, fmt.Errorf("failed to typecast %s field 2 to UnixFD", sig.Name)
+		}
+		event.File = os.NewFile(uintptr(fd), fmt.Sprintf("device-%d:%d", event.Major, event.Minor))
+	default:
+		return nil, fmt.Errorf("unexpected signal %s", sig.Name)
+	}
+	return &event, nil
+}
+
+// WatchSessionContext returns a channel receiving the properties of a
+// session whenever logind reports a change of them, such as the session
+// becoming active or going idle. The channel is closed when ctx is done.
+func (c *Conn) WatchSessionContext(ctx context.Context, sessionPath dbus.ObjectPath) (<-chan *SessionProperties, error) {
+	signals, err := c.subscribeSignal(ctx, sessionPath, "org.freedesktop.DBus.Properties", "PropertiesChanged")
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan *SessionProperties)
+	go func() {
+		defer close(out)
+		for sig := range signals {
+			iface, _, _, err := propertiesChangedFromSignal(sig)
+			if err != nil || iface != dbusSessionInterface {
+				continue
+			}
+			props, err := c.DescribeSessionContext(ctx, sessionPath)
+			if err != nil {
+				continue
+			}
+			select {
+			case out <- props:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
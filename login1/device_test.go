@@ -0,0 +1,84 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestDeviceEventFromSignal(t *testing.T) {
+	// An fd that is not open, so closing the file of the event is harmless.
+	const fd = 1 << 20
+
+	for i, tt := range []struct {
+		sig   *dbus.Signal
+		event *DeviceEvent
+	}{
+		{
+			&dbus.Signal{Name: dbusSessionInterface + ".PauseDevice", Body: []interface{}{uint32(226), uint32(0), DevicePausePause}},
+			&DeviceEvent{Major: 226, Minor: 0, Pause: DevicePausePause},
+		},
+		{
+			&dbus.Signal{Name: dbusSessionInterface + ".ResumeDevice", Body: []interface{}{uint32(13), uint32(64), dbus.UnixFD(fd)}},
+			&DeviceEvent{Major: 13, Minor: 64},
+		},
+		{&dbus.Signal{Name: dbusSessionInterface + ".PauseDevice", Body: []interface{}{uint32(1), uint32(2)}}, nil},
+		{&dbus.Signal{Name: dbusSessionInterface + ".ResumeDevice", Body: []interface{}{uint32(1), uint32(2), "gone"}}, nil},
+		{&dbus.Signal{Name: dbusSessionInterface + ".Lock", Body: []interface{}{uint32(1), uint32(2), "x"}}, nil},
+	} {
+		event, err := deviceEventFromSignal(tt.sig)
+		if tt.event == nil {
+			if err == nil {
+				t.Errorf("case %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+			continue
+		}
+		if event.Major != tt.event.Major || event.Minor != tt.event.Minor || event.Pause != tt.event.Pause {
+			t.Errorf("case %d: got %+v, want %+v", i, event, tt.event)
+		}
+		if (event.File != nil) != (tt.event.Pause == "") {
+			t.Errorf("case %d: unexpected file %v", i, event.File)
+		}
+		if event.File != nil {
+			if event.File.Fd() != fd {
+				t.Errorf("case %d: file has fd %d, want %d", i, event.File.Fd(), fd)
+			}
+			event.File.Close()
+		}
+	}
+}
+
+func TestTakeControlUnknownSession(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	path := dbus.ObjectPath(dbusPath + "/session/go_2dsystemd_2dnonexistent")
+	if err := c.TakeControlContext(ctx, path, false); err == nil {
+		t.Fatal("expected error taking control of unknown session")
+	}
+	if _, err := c.TakeDeviceContext(ctx, path, 1, 3); err == nil {
+		t.Fatal("expected error taking device of unknown session")
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// SessionProperties holds the commonly used properties of a session object.
+type SessionProperties struct {
+	ID         string
+	Name       string // The user name of the session owner
+	UID        uint32
+	UserPath   dbus.ObjectPath
+	Seat       string // The seat ID, empty for sessions without a seat
+	SeatPath   dbus.ObjectPath
+	TTY        string
+	Display    string
+	Remote     bool
+	RemoteHost string
+	RemoteUser string
+	Service    string // The PAM service that registered the session
+	Desktop    string
+	Scope      string // The scope unit the session's processes belong to
+	Leader     uint32 // The PID of the session leader
+	Type       string // One of unspecified, tty, x11, wayland, mir or web
+	Class      string // One of user, greeter, lock-screen or background
+	State      string // One of online, active or closing
+	Active     bool
+	IdleHint   bool
+	// IdleSinceHint is the wallclock time in microseconds since the session went idle
+	IdleSinceHint uint64
+	// Timestamp is the wallclock time in microseconds when the session was created
+	Timestamp uint64
+}
+
+// UserProperties holds the commonly used properties of a user object.
+type UserProperties struct {
+	UID         uint32
+	GID         uint32
+	Name        string
+	RuntimePath string // The user's $XDG_RUNTIME_DIR
+	Service     string // The user's systemd --user service unit
+	Slice       string
+	Display     string // The ID of the session owning the user's graphical display, if any
+	State       string // One of offline, lingering, online, active or closing
+	Sessions    []string
+	Linger      bool
+	IdleHint    bool
+	// IdleSinceHint is the wallclock time in microseconds since the user went idle
+	IdleSinceHint uint64
+	// Timestamp is the wallclock time in microseconds when the user logged in
+	Timestamp uint64
+}
+
+// SeatProperties holds the commonly used properties of a seat object.
+type SeatProperties struct {
+	ID            string
+	ActiveSession string // The ID of the currently active session, if any
+	// ActiveSessionPath is the object path of the currently active session, if any
+	ActiveSessionPath dbus.ObjectPath
+	CanGraphical      bool
+	CanTTY            bool
+	Sessions          []string
+	IdleHint          bool
+	// IdleSinceHint is the wallclock time in microseconds since the seat went idle
+	IdleSinceHint uint64
+}
+
+// DescribeSessionContext returns the typed properties of the session at the given path.
+func (c *Conn) DescribeSessionContext(ctx context.Context, sessionPath dbus.ObjectPath) (*SessionProperties, error) {
+	props, err := c.GetSessionPropertiesContext(ctx, sessionPath)
+	if err != nil {
+		return nil, err
+	}
+	return sessionPropertiesFromMap(props)
+}
+
+// DescribeUserContext returns the typed properties of the user at the given path.
+func (c *Conn) DescribeUserContext(ctx context.Context, userPath dbus.ObjectPath) (*UserProperties, error) {
+	props, err := c.GetUserPropertiesContext(ctx, userPath)
+	if err != nil {
+		return nil, err
+	}
+	return userPropertiesFromMap(props)
+}
+
+// DescribeSeatContext returns the typed properties of the seat at the given path.
+func (c *Conn) DescribeSeatContext(ctx context.Context, seatPath dbus.ObjectPath) (*SeatProperties, error) {
+	props, err := c.GetSeatPropertiesContext(ctx, seatPath)
+	if err != nil {
+		return nil, err
+	}
+	return seatPropertiesFromMap(props)
+}
+
+func sessionPropertiesFromMap(props map[string]dbus.Variant) (*SessionProperties, error) {
+	var err error
+	p := &SessionProperties{}
+
+	p.UID, p.UserPath, err = userRefFromVariant(props["User"])
+	if err != nil {
+		return nil, err
+	}
+	p.Seat, p.SeatPath, err = objectRefFromVariant(props["Seat"])
+	if err != nil {
+		return nil, err
+	}
+
+	p.ID, _ = props["Id"].Value().(string)
+	p.Name, _ = props["Name"].Value().(string)
+	p.TTY, _ = props["TTY"].Value().(string)
+	p.Display, _ = props["Display"].Value().(string)
+	p.Remote, _ = props["Remote"].Value().(bool)
+	p.RemoteHost, _ = props["RemoteHost"].Value().(string)
+	p.RemoteUser, _ = props["RemoteUser"].Value().(string)
+	p.Service, _ = props["Service"].Value().(string)
+	p.Desktop, _ = props["Desktop"].Value().(string)
+	p.Scope, _ = props["Scope"].Value().(string)
+	p.Leader, _ = props["Leader"].Value().(uint32)
+	p.Type, _ = props["Type"].Value().(string)
+	p.Class, _ = props["Class"].Value().(string)
+	p.State, _ = props["State"].Value().(string)
+	p.Active, _ = props["Active"].Value().(bool)
+	p.IdleHint, _ = props["IdleHint"].Value().(bool)
+	p.IdleSinceHint, _ = props["IdleSinceHint"].Value().(uint64)
+	p.Timestamp, _ = props["Timestamp"].Value().(uint64)
+
+	return p, nil
+}
+
+func userPropertiesFromMap(props map[string]dbus.Variant) (*UserProperties, error) {
+	var err error
+	p := &UserProperties{}
+
+	p.Display, _, err = objectRefFromVariant(props["Display"])
+	if err != nil {
+		return nil, err
+	}
+	p.Sessions, err = objectRefsFromVariant(props["Sessions"])
+	if err != nil {
+		return nil, err
+	}
+
+	p.UID, _ = props["UID"].Value().(uint32)
+	p.GID, _ = props["GID"].Value().(uint32)
+	p.Name, _ = props["Name"].Value().(string)
+	p.RuntimePath, _ = props["RuntimePath"].Value().(string)
+	p.Service, _ = props["Service"].Value().(string)
+	p.Slice, _ = props["Slice"].Value().(string)
+	p.State, _ = props["State"].Value().(string)
+	p.Linger, _ = props["Linger"].Value().(bool)
+	p.IdleHint, _ = props["IdleHint"].Value().(bool)
+	p.IdleSinceHint, _ = props["IdleSinceHint"].Value().(uint64)
+	p.Timestamp, _ = props["Timestamp"].Value().(uint64)
+
+	return p, nil
+}
+
+func seatPropertiesFromMap(props map[string]dbus.Variant) (*SeatProperties, error) {
+	var err error
+	p := &SeatProperties{}
+
+	p.ActiveSession, p.ActiveSessionPath, err = objectRefFromVariant(props["ActiveSession"])
+	if err != nil {
+		return nil, err
+	}
+	p.Sessions, err = objectRefsFromVariant(props["Sessions"])
+	if err != nil {
+		return nil, err
+	}
+
+	p.ID, _ = props["Id"].Value().(string)
+	p.CanGraphical, _ = props["CanGraphical"].Value().(bool)
+	p.CanTTY, _ = props["CanTTY"].Value().(bool)
+	p.IdleHint, _ = props["IdleHint"].Value().(bool)
+	p.IdleSinceHint, _ = props["IdleSinceHint"].Value().(uint64)
+
+	return p, nil
+}
+
+// objectRefFromVariant decodes a (so) reference to another logind object,
+// e.g. the Seat property of a session. Missing properties decode to empty values.
+func objectRefFromVariant(v dbus.Variant) (string, dbus.ObjectPath, error) {
+	if v.Value() == nil {
+		return "", "", nil
+	}
+	ref, ok := v.Value().([]interface{})
+	if !ok {
+		return "", "", fmt.Errorf("failed to typecast object reference %s", v)
+	}
+	return objectRefFromInterfaces(ref)
+}
+
+func objectRefFromInterfaces(ref []interface{}) (string, dbus.ObjectPath, error) {
+	if len(ref) < 2 {
+		return "", "", fmt.Errorf("invalid number of object reference fields: %d", len(ref))
+	}
+	id, ok := ref[0].(string)
+	if !ok {
+		return "", "", fmt.Errorf("failed to typecast object reference field 0 to string")
+	}
+	path, ok := ref[1].(dbus.ObjectPath)
+	if !ok {
+		return "", "", fmt.Errorf("failed to typecast object reference field 1 to ObjectPath")
+	}
+	return id, path, nil
+}
+
+// objectRefsFromVariant decodes an a(so) list of references, returning the IDs.
+func objectRefsFromVariant(v dbus.Variant) ([]string, error) {
+	if v.Value() == nil {
+		return nil, nil
+	}
+	refs, ok := v.Value().([][]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast object reference list %s", v)
+	}
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		id, _, err := objectRefFromInterfaces(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// userRefFromVariant decodes the (uo) User property of a session.
+func userRefFromVariant(v dbus.Variant) (uint32, dbus.ObjectPath, error) {
+	if v.Value() == nil {
+		return 0, "", nil
+	}
+	ref, ok := v.Value().([]interface{})
+	if !ok || len(ref) < 2 {
+		return 0, "", fmt.Errorf("failed to typecast user reference %s", v)
+	}
+	uid, ok := ref[0].(uint32)
+	if !ok {
+		return 0, "", fmt.Errorf("failed to typecast user reference field 0 to uint32")
+	}
+	path, ok := ref[1].(dbus.ObjectPath)
+	if !ok {
+		return 0, "", fmt.Errorf("failed to typecast user reference field 1 to ObjectPath")
+	}
+	return uid, path, nil
+}
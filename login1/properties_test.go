@@ -0,0 +1,108 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestSessionPropertiesFromMap(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"Id":         dbus.MakeVariant("3"),
+		"Name":       dbus.MakeVariant("core"),
+		"User":       dbus.MakeVariant([]interface{}{uint32(1000), dbus.ObjectPath("/org/freedesktop/login1/user/_1000")}),
+		"Seat":       dbus.MakeVariant([]interface{}{"seat0", dbus.ObjectPath("/org/freedesktop/login1/seat/seat0")}),
+		"TTY":        dbus.MakeVariant("tty2"),
+		"Remote":     dbus.MakeVariant(true),
+		"RemoteHost": dbus.MakeVariant("10.0.0.1"),
+		"Class":      dbus.MakeVariant("user"),
+		"State":      dbus.MakeVariant("active"),
+		"Leader":     dbus.MakeVariant(uint32(4242)),
+	}
+
+	got, err := sessionPropertiesFromMap(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &SessionProperties{
+		ID:         "3",
+		Name:       "core",
+		UID:        1000,
+		UserPath:   "/org/freedesktop/login1/user/_1000",
+		Seat:       "seat0",
+		SeatPath:   "/org/freedesktop/login1/seat/seat0",
+		TTY:        "tty2",
+		Remote:     true,
+		RemoteHost: "10.0.0.1",
+		Class:      "user",
+		State:      "active",
+		Leader:     4242,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	props["Seat"] = dbus.MakeVariant("seat0")
+	if _, err := sessionPropertiesFromMap(props); err == nil {
+		t.Fatal("expected error for malformed Seat property")
+	}
+}
+
+func TestSeatPropertiesFromMap(t *testing.T) {
+	props := map[string]dbus.Variant{
+		"Id":            dbus.MakeVariant("seat0"),
+		"ActiveSession": dbus.MakeVariant([]interface{}{"3", dbus.ObjectPath("/org/freedesktop/login1/session/_33")}),
+		"CanGraphical":  dbus.MakeVariant(true),
+		"Sessions": dbus.MakeVariant([][]interface{}{
+			{"3", dbus.ObjectPath("/org/freedesktop/login1/session/_33")},
+			{"c1", dbus.ObjectPath("/org/freedesktop/login1/session/c1")},
+		}),
+	}
+
+	got, err := seatPropertiesFromMap(props)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &SeatProperties{
+		ID:                "seat0",
+		ActiveSession:     "3",
+		ActiveSessionPath: "/org/freedesktop/login1/session/_33",
+		CanGraphical:      true,
+		Sessions:          []string{"3", "c1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestUserPropertiesFromMapMissing(t *testing.T) {
+	got, err := userPropertiesFromMap(map[string]dbus.Variant{
+		"UID":  dbus.MakeVariant(uint32(1000)),
+		"Name": dbus.MakeVariant("core"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &UserProperties{UID: 1000, Name: "core"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login1
+
+import (
+	"context"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// Session types, for use with CreateSessionContext.
+const (
+	SessionTypeUnspecified = "unspecified"
+	SessionTypeTTY         = "tty"
+	SessionTypeX11         = "x11"
+	SessionTypeWayland     = "wayland"
+	SessionTypeMir         = "mir"
+	SessionTypeWeb         = "web"
+)
+
+// Session classes, for use with CreateSessionContext.
+const (
+	SessionClassUser       = "user"
+	SessionClassGreeter    = "greeter"
+	SessionClassLockScreen = "lock-screen"
+	SessionClassBackground = "background"
+)
+
+// SessionProperty is an additional property of a session to create, such
+// as "TimeoutStopUSec".
+type SessionProperty struct {
+	Name  string
+	Value dbus.Variant
+}
+
+// SessionRequest describes a session to register with CreateSessionContext,
+// like pam_systemd does when a user logs in.
+type SessionRequest struct {
+	UID     uint32
+	PID     uint32 // The session leader, the caller if 0
+	Service string // The PAM service name, such as "sshd" or "gdm-password"
+	Type    string // One of the SessionType* constants
+	Class   string // One of the SessionClass* constants
+	Desktop string // The desktop environment, such as "GNOME"
+	Seat    string // The seat of local sessions, such as "seat0"
+	VTNr    uint32 // The virtual terminal of a seat, 0 if none
+	TTY     string // The terminal, such as "tty2" or "pts/3"
+	Display string // The X11 display, such as ":0"
+	Remote  bool
+	// RemoteUser and RemoteHost identify the origin of remote sessions.
+	RemoteUser string
+	RemoteHost string
+	Properties []SessionProperty
+}
+
+// CreatedSession is a session registered by CreateSessionContext.
+type CreatedSession struct {
+	ID          string
+	Path        dbus.ObjectPath
+	RuntimePath string // The user's $XDG_RUNTIME_DIR
+	UID         uint32
+	Seat        string
+	VTNr        uint32
+	// Existing is set if the leader already belonged to a session, which
+	// is returned instead of a new one.
+	Existing bool
+	// FIFO keeps the session alive: logind closes the session once it is
+	// closed, nil if logind did not pass one.
+	FIFO *os.File
+}
+
+// Close closes the FIFO of the session, which tells logind that the session
+// ended, like the session leader exiting.
+func (s *CreatedSession) Close() error {
+	if s.FIFO == nil {
+		return nil
+	}
+	return s.FIFO.Close()
+}
+
+// CreateSessionContext registers a new session with logind, as display
+// managers, greeters and remote login daemons do through pam_systemd. Only
+// root may create sessions. The session is closed when the FIFO of the
+// returned session is closed or ReleaseSessionContext is called, so
+// callers must keep the FIFO open for the lifetime of the session.
+func (c *Conn) CreateSessionContext(ctx context.Context, r SessionRequest) (*CreatedSession, error) {
+	properties := r.Properties
+	if properties == nil {
+		properties = []SessionProperty{}
+	}
+
+	var s CreatedSession
+	var fd dbus.UnixFD
+	err := c.object.CallWithContext(ctx, dbusManagerInterface+".CreateSession", 0,
+		r.UID, r.PID, r.Service, r.Type, r.Class, r.Desktop, r.Seat, r.VTNr, r.TTY, r.Display,
+		r.Remote, r.RemoteUser, r.RemoteHost, properties,
+	).Store(&s.ID, &s.Path, &s.RuntimePath, &fd, &s.UID, &s.Seat, &s.VTNr, &s.Existing)
+	if err != nil {
+		return nil, err
+	}
+	if fd >= 0 {
+		s.FIFO = os.NewFile(uintptr(fd), "session-"+s.ID)
+	}
+
+	return &s, nil
+}
+
+// ReleaseSessionContext tells logind that a session created with
+// CreateSessionContext ended, like closing its FIFO does. The session is
+// closed once its processes exited.
+func (c *Conn) ReleaseSessionContext(ctx context.Context, id string) error {
+	return c.object.CallWithContext(ctx, dbusManagerInterface+".ReleaseSession", 0, id).Store()
+}
@@ -0,0 +1,363 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login1
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// subscribeSignal delivers the signals matching path, interface and member
+// until ctx is done or the connection is closed, after which the returned
+// channel is closed. An empty path matches signals from any object.
+func (c *Conn) subscribeSignal(ctx context.Context, path dbus.ObjectPath, iface, member string) (<-chan *dbus.Signal, error) {
+	opts := []dbus.MatchOption{
+		dbus.WithMatchInterface(iface),
+		dbus.WithMatchMember(member),
+	}
+	if path != "" {
+		opts = append(opts, dbus.WithMatchObjectPath(path))
+	}
+	if err := c.conn.AddMatchSignalContext(ctx, opts...); err != nil {
+		return nil, err
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	c.conn.Signal(signals)
+
+	out := make(chan *dbus.Signal)
+	go func() {
+		defer close(out)
+		defer c.conn.RemoveMatchSignal(opts...)
+		defer c.conn.RemoveSignal(signals)
+
+		name := iface + "." + member
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig, ok := <-signals:
+				if !ok {
+					return
+				}
+				if sig.Name != name || (path != "" && sig.Path != path) {
+					continue
+				}
+				select {
+				case out <- sig:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+func (c *Conn) subscribePrepare(ctx context.Context, member string) (<-chan bool, error) {
+	signals, err := c.subscribeSignal(ctx, dbusPath, dbusManagerInterface, member)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan bool)
+	go func() {
+		defer close(out)
+		for sig := range signals {
+			if len(sig.Body) < 1 {
+				continue
+			}
+			start, ok := sig.Body[0].(bool)
+			if !ok {
+				continue
+			}
+			select {
+			case out <- start:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+// SubscribePrepareForSleepContext returns a channel receiving the argument
+// of each PrepareForSleep signal: true right before the system goes to
+// sleep, false once it resumed. The channel is closed when ctx is done.
+func (c *Conn) SubscribePrepareForSleepContext(ctx context.Context) (<-chan bool, error) {
+	return c.subscribePrepare(ctx, "PrepareForSleep")
+}
+
+// SubscribePrepareForShutdownContext returns a channel receiving the
+// argument of each PrepareForShutdown signal: true right before the system
+// shuts down, false if a shutdown was cancelled. The channel is closed when
+// ctx is done.
+func (c *Conn) SubscribePrepareForShutdownContext(ctx context.Context) (<-chan bool, error) {
+	return c.subscribePrepare(ctx, "PrepareForShutdown")
+}
+
+// DelayLock manages a delay inhibitor lock which is released once the
+// inhibited operation is about to happen and taken again afterwards.
+type DelayLock struct {
+	conn *Conn
+	what string
+	who  string
+	why  string
+
+	mu   sync.Mutex
+	lock *os.File
+}
+
+// NewDelayLock returns a DelayLock for the given InhibitWhat* operations.
+// The lock is not taken until Acquire is called.
+func (c *Conn) NewDelayLock(what, who, why string) *DelayLock {
+	return &DelayLock{conn: c, what: what, who: who, why: why}
+}
+
+// Acquire takes the delay inhibitor lock. It is a no-op if the lock is held.
+func (l *DelayLock) Acquire(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.lock != nil {
+		return nil
+	}
+	lock, err := l.conn.InhibitContext(ctx, l.what, l.who, l.why, InhibitModeDelay)
+	if err != nil {
+		return err
+	}
+	l.lock = lock
+	return nil
+}
+
+// Release gives up the delay inhibitor lock, letting the inhibited operation
+// proceed. It is a no-op if the lock is not held.
+func (l *DelayLock) Release() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.lock == nil {
+		return nil
+	}
+	err := l.lock.Close()
+	l.lock = nil
+	return err
+}
+
+// Held returns whether the delay inhibitor lock is currently held.
+func (l *DelayLock) Held() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lock != nil
+}
+
+// HandleSleepContext holds a sleep delay inhibitor on behalf of who and runs
+// suspend right before the system goes to sleep, then releases the lock so
+// the system can proceed. After resume the lock is taken again and resume is
+// run. Either callback may be nil. It blocks until ctx is done or the
+// connection is closed, and releases the lock before returning.
+func (c *Conn) HandleSleepContext(ctx context.Context, who, why string, suspend, resume func()) error {
+	return c.handlePrepare(ctx, "PrepareForSleep", InhibitWhatSleep, who, why, suspend, resume)
+}
+
+// HandleShutdownContext holds a shutdown delay inhibitor on behalf of who and
+// runs shutdown right before the system shuts down, then releases the lock
+// so the shutdown can proceed. If the shutdown is cancelled the lock is taken
+// again. It blocks until ctx is done or the connection is closed, and
+// releases the lock before returning.
+func (c *Conn) HandleShutdownContext(ctx context.Context, who, why string, shutdown func()) error {
+	return c.handlePrepare(ctx, "PrepareForShutdown", InhibitWhatShutdown, who, why, shutdown, nil)
+}
+
+func (c *Conn) handlePrepare(ctx context.Context, member, what, who, why string, before, after func()) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribe before taking the lock, so no signal can be missed in between.
+	events, err := c.subscribePrepare(ctx, member)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// The match is removed by the time the forwarding goroutines
+		// closed the channel.
+		cancel()
+		for range events {
+		}
+	}()
+
+	lock := c.NewDelayLock(what, who, why)
+	defer lock.Release()
+	if err := lock.Acquire(ctx); err != nil {
+		return err
+	}
+
+	for start := range events {
+		if start {
+			if before != nil {
+				before()
+			}
+			if err := lock.Release(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := lock.Acquire(ctx); err != nil {
+			return err
+		}
+		if after != nil {
+			after()
+		}
+	}
+
+	return ctx.Err()
+}
+
+// IdleHint is the idle state of the manager, a seat or a session.
+type IdleHint struct {
+	Path dbus.ObjectPath // The object whose idle state changed
+	Idle bool
+	// IdleSince is the wallclock time in microseconds since the object went
+	// idle, or since it was last active if not idle.
+	IdleSince uint64
+}
+
+// WatchIdleHintContext returns a channel receiving the idle state whenever
+// the IdleHint or IdleSinceHint property of one of the given objects changes.
+// Without any paths, the global idle state of the manager is watched. The
+// channel is closed when ctx is done.
+func (c *Conn) WatchIdleHintContext(ctx context.Context, paths ...dbus.ObjectPath) (<-chan IdleHint, error) {
+	if len(paths) == 0 {
+		paths = []dbus.ObjectPath{dbusPath}
+	}
+
+	// Cancelling ctx removes the matches of the paths already subscribed
+	// to if a later one fails.
+	ctx, cancel := context.WithCancel(ctx)
+	out := make(chan IdleHint)
+	var wg sync.WaitGroup
+	for _, path := range paths {
+		signals, err := c.subscribeSignal(ctx, path, "org.freedesktop.DBus.Properties", "PropertiesChanged")
+		if err != nil {
+			cancel()
+			wg.Wait()
+			return nil, err
+		}
+
+		wg.Add(1)
+		go func(signals <-chan *dbus.Signal) {
+			defer wg.Done()
+			for sig := range signals {
+				hint, ok, err := c.idleHintFromSignal(ctx, sig)
+				if err != nil || !ok {
+					continue
+				}
+				select {
+				case out <- *hint:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(signals)
+	}
+
+	go func() {
+		wg.Wait()
+		cancel()
+		close(out)
+	}()
+
+	return out, nil
+}
+
+// idleHintFromSignal decodes a PropertiesChanged signal, reporting false if
+// neither idle property was changed. Properties which were only invalidated
+// are fetched from logind.
+func (c *Conn) idleHintFromSignal(ctx context.Context, sig *dbus.Signal) (*IdleHint, bool, error) {
+	return decodeIdleHint(sig, func(iface, name string) (*dbus.Variant, error) {
+		return c.getProperty(ctx, sig.Path, iface, name)
+	})
+}
+
+// decodeIdleHint implements idleHintFromSignal, calling get for each idle
+// property missing from the signal. The signal is shared with every other
+// subscriber and is never modified.
+func decodeIdleHint(sig *dbus.Signal, get func(iface, name string) (*dbus.Variant, error)) (*IdleHint, bool, error) {
+	iface, changed, invalidated, err := propertiesChangedFromSignal(sig)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch iface {
+	case dbusManagerInterface, dbusSeatInterface, dbusSessionInterface, dbusUserInterface:
+	default:
+		return nil, false, nil
+	}
+
+	_, hintChanged := changed["IdleHint"]
+	_, sinceChanged := changed["IdleSinceHint"]
+	for _, name := range invalidated {
+		if name == "IdleHint" || name == "IdleSinceHint" {
+			hintChanged = true
+		}
+	}
+	if !hintChanged && !sinceChanged {
+		return nil, false, nil
+	}
+
+	values := make(map[string]dbus.Variant, 2)
+	for _, name := range []string{"IdleHint", "IdleSinceHint"} {
+		if v, ok := changed[name]; ok {
+			values[name] = v
+			continue
+		}
+		v, err := get(iface, name)
+		if err != nil {
+			return nil, false, err
+		}
+		values[name] = *v
+	}
+
+	hint := &IdleHint{Path: sig.Path}
+	hint.Idle, _ = values["IdleHint"].Value().(bool)
+	hint.IdleSince, _ = values["IdleSinceHint"].Value().(uint64)
+	return hint, true, nil
+}
+
+func propertiesChangedFromSignal(sig *dbus.Signal) (string, map[string]dbus.Variant, []string, error) {
+	if len(sig.Body) < 3 {
+		return "", nil, nil, fmt.Errorf("invalid number of PropertiesChanged fields: %d", len(sig.Body))
+	}
+	iface, ok := sig.Body[0].(string)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("failed to typecast PropertiesChanged field 0 to string")
+	}
+	changed, ok := sig.Body[1].(map[string]dbus.Variant)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("failed to typecast PropertiesChanged field 1 to map[string]dbus.Variant")
+	}
+	invalidated, ok := sig.Body[2].([]string)
+	if !ok {
+		return "", nil, nil, fmt.Errorf("failed to typecast PropertiesChanged field 2 to []string")
+	}
+	return iface, changed, invalidated, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package login1
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestSubscribePrepareForSleep(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := c.SubscribePrepareForSleepContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Fatal("unexpected PrepareForSleep signal")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestDelayLock(t *testing.T) {
+	c, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock := c.NewDelayLock(InhibitWhatSleep, "go-systemd", "delay lock test")
+	if lock.Held() {
+		t.Fatal("lock held before Acquire")
+	}
+	if err := lock.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !lock.Held() {
+		t.Fatal("lock not held after Acquire")
+	}
+	if err := lock.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if lock.Held() {
+		t.Fatal("lock held after Release")
+	}
+}
+
+func TestIdleHintFromSignal(t *testing.T) {
+	c := &Conn{}
+
+	sig := &dbus.Signal{
+		Path: dbusPath,
+		Name: "org.freedesktop.DBus.Properties.PropertiesChanged",
+		Body: []interface{}{
+			dbusManagerInterface,
+			map[string]dbus.Variant{
+				"IdleHint":      dbus.MakeVariant(true),
+				"IdleSinceHint": dbus.MakeVariant(uint64(1234)),
+			},
+			[]string{},
+		},
+	}
+	hint, ok, err := c.idleHintFromSignal(context.Background(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("idle hint change not detected")
+	}
+	if !hint.Idle || hint.IdleSince != 1234 || hint.Path != dbusPath {
+		t.Fatalf("unexpected idle hint %+v", hint)
+	}
+
+	sig.Body[1] = map[string]dbus.Variant{"BlockInhibited": dbus.MakeVariant("sleep")}
+	if _, ok, err := c.idleHintFromSignal(context.Background(), sig); err != nil || ok {
+		t.Fatalf("unrelated change reported as idle hint: %v %v", ok, err)
+	}
+
+	sig.Body[0] = "org.example.Other"
+	sig.Body[1] = map[string]dbus.Variant{"IdleHint": dbus.MakeVariant(true)}
+	if _, ok, err := c.idleHintFromSignal(context.Background(), sig); err != nil || ok {
+		t.Fatalf("foreign interface reported as idle hint: %v %v", ok, err)
+	}
+}
+
+func TestIdleHintFromSharedSignal(t *testing.T) {
+	// godbus hands the same signal to every subscriber, so decoding it must
+	// not write to the changed properties.
+	changed := map[string]dbus.Variant{"IdleHint": dbus.MakeVariant(true)}
+	sig := &dbus.Signal{
+		Path: dbusPath,
+		Name: "org.freedesktop.DBus.Properties.PropertiesChanged",
+		Body: []interface{}{dbusManagerInterface, changed, []string{"IdleSinceHint"}},
+	}
+	get := func(iface, name string) (*dbus.Variant, error) {
+		v := dbus.MakeVariant(uint64(1234))
+		return &v, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				hint, ok, err := decodeIdleHint(sig, get)
+				if err != nil || !ok {
+					t.Errorf("idle hint not decoded: %v %v", ok, err)
+					return
+				}
+				if !hint.Idle || hint.IdleSince != 1234 {
+					t.Errorf("unexpected idle hint %+v", hint)
+					return
+				}
+				for range sig.Body[1].(map[string]dbus.Variant) {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(changed) != 1 {
+		t.Fatalf("signal modified while decoding: %v", changed)
+	}
+}
@@ -19,6 +19,7 @@ package machine1
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"syscall"
@@ -29,10 +30,15 @@ import (
 )
 
 const (
-	dbusInterface = "org.freedesktop.machine1.Manager"
-	dbusPath      = "/org/freedesktop/machine1"
+	dbusInterface        = "org.freedesktop.machine1.Manager"
+	dbusMachineInterface = "org.freedesktop.machine1.Machine"
+	dbusPath             = "/org/freedesktop/machine1"
 )
 
+// CopyFlagReplace makes CopyToMachineWithFlags and CopyFromMachineWithFlags
+// replace an existing destination instead of failing.
+const CopyFlagReplace uint64 = 1 << 0
+
 // Conn is a connection to systemds dbus endpoint.
 type Conn struct {
 	conn   *dbus.Conn
@@ -45,6 +51,7 @@ type MachineStatus struct {
 	Class   string          // The machine class as string
 	Service string          // The machine service as string
 	JobPath dbus.ObjectPath // The job object path
+	State   string          // The machine state, one of opening, running or closing
 }
 
 // ImageStatus is a set of necessary info for each machine image
@@ -112,6 +119,17 @@ func (c *Conn) getPath(method string, args ...interface{}) (dbus.ObjectPath, err
 	return path, nil
 }
 
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
 // Connected returns whether conn is connected
 func (c *Conn) Connected() bool {
 	return c.conn.Connected()
@@ -137,16 +155,70 @@ func (c *Conn) GetImage(name string) (dbus.ObjectPath, error) {
 	return c.getPath("GetImage", name)
 }
 
-// GetMachineByPID gets a machine specified by a PID from systemd-machined
+// GetMachineByPID gets a machine specified by a PID from systemd-machined.
+// Any process running inside the machine may be passed, not only its leader.
 func (c *Conn) GetMachineByPID(pid uint) (dbus.ObjectPath, error) {
 	return c.getPath("GetMachineByPID", pid)
 }
 
 // GetMachineAddresses gets a list of IP addresses
+//
+// Deprecated: machined returns the addresses themselves rather than an
+// object path, so this always fails. Use GetMachineIPAddresses instead.
 func (c *Conn) GetMachineAddresses(name string) (dbus.ObjectPath, error) {
 	return c.getPath("GetMachineAddresses", name)
 }
 
+// GetMachineIPAddresses gets the IP addresses configured inside a container
+func (c *Conn) GetMachineIPAddresses(name string) ([]net.IP, error) {
+	result := make([][]interface{}, 0)
+	if err := c.object.Call(dbusInterface+".GetMachineAddresses", 0, name).Store(&result); err != nil {
+		return nil, err
+	}
+
+	addrs := []net.IP{}
+	for _, i := range result {
+		addr, err := addressFromInterfaces(i)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, addr)
+	}
+
+	return addrs, nil
+}
+
+// GetMachineOSRelease gets the os-release(5) fields of the operating system running in a container
+func (c *Conn) GetMachineOSRelease(name string) (map[string]string, error) {
+	var osRelease map[string]string
+	if err := c.object.Call(dbusInterface+".GetMachineOSRelease", 0, name).Store(&osRelease); err != nil {
+		return nil, err
+	}
+	return osRelease, nil
+}
+
+// GetMachineState gets the state of a machine, one of opening, running or closing
+func (c *Conn) GetMachineState(name string) (string, error) {
+	path, err := c.GetMachine(name)
+	if err != nil {
+		return "", err
+	}
+	return c.machineState(path)
+}
+
+func (c *Conn) machineState(path dbus.ObjectPath) (string, error) {
+	var state dbus.Variant
+	obj := c.conn.Object("org.freedesktop.machine1", path)
+	if err := obj.Call("org.freedesktop.DBus.Properties.Get", 0, dbusMachineInterface, "State").Store(&state); err != nil {
+		return "", err
+	}
+	s, ok := state.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("failed to typecast State property %s to string", state)
+	}
+	return s, nil
+}
+
 // DescribeMachine gets the properties of a machine
 func (c *Conn) DescribeMachine(name string) (machineProps map[string]interface{}, err error) {
 	var dbusProps map[string]dbus.Variant
@@ -166,6 +238,26 @@ func (c *Conn) DescribeMachine(name string) (machineProps map[string]interface{}
 	return
 }
 
+// DescribeImage gets the properties of an image, including its disk usage
+// and size limits
+func (c *Conn) DescribeImage(name string) (imageProps map[string]interface{}, err error) {
+	var dbusProps map[string]dbus.Variant
+	path, pathErr := c.GetImage(name)
+	if pathErr != nil {
+		return nil, pathErr
+	}
+	obj := c.conn.Object("org.freedesktop.machine1", path)
+	err = obj.Call("org.freedesktop.DBus.Properties.GetAll", 0, "").Store(&dbusProps)
+	if err != nil {
+		return nil, err
+	}
+	imageProps = make(map[string]interface{}, len(dbusProps))
+	for key, val := range dbusProps {
+		imageProps[key] = val.Value()
+	}
+	return
+}
+
 // KillMachine sends a signal to a machine
 func (c *Conn) KillMachine(name, who string, sig syscall.Signal) error {
 	return c.object.Call(dbusInterface+".KillMachine", 0, name, who, sig).Err
@@ -176,16 +268,57 @@ func (c *Conn) TerminateMachine(name string) error {
 	return c.object.Call(dbusInterface+".TerminateMachine", 0, name).Err
 }
 
-// RegisterMachine registers the container with the systemd-machined
+// RegisterMachine registers the container with the systemd-machined. Unlike
+// CreateMachine, no scope unit is created: the leader must already run in a
+// unit of its own, which is then associated with the machine. id is the
+// 128-bit machine ID of the container, or nil if unknown.
 func (c *Conn) RegisterMachine(name string, id []byte, service string, class string, pid int, root_directory string) error {
 	return c.object.Call(dbusInterface+".RegisterMachine", 0, name, id, service, class, uint32(pid), root_directory).Err
 }
 
-// RegisterMachineWithNetwork registers the container with its network with systemd-machined
+// RegisterMachineWithNetwork registers the container with its network with systemd-machined.
+// ifindices are the host-side network interfaces of the container, e.g. the
+// host end of its veth pair, which machinectl shows for the machine.
 func (c *Conn) RegisterMachineWithNetwork(name string, id []byte, service string, class string, pid int, root_directory string, ifindices []int) error {
 	return c.object.Call(dbusInterface+".RegisterMachineWithNetwork", 0, name, id, service, class, uint32(pid), root_directory, ifindices).Err
 }
 
+// UnregisterMachine makes systemd-machined forget a machine registered with
+// RegisterMachine, without killing its processes.
+func (c *Conn) UnregisterMachine(name string) error {
+	return c.object.Call(dbusInterface+".UnregisterMachine", 0, name).Err
+}
+
+// BindMountMachine bind mounts a file or directory from the host into a
+// running container. If mkdir is set, the destination is created if missing.
+func (c *Conn) BindMountMachine(name, source, destination string, readOnly, mkdir bool) error {
+	return c.object.Call(dbusInterface+".BindMountMachine", 0, name, source, destination, readOnly, mkdir).Err
+}
+
+// CopyToMachine copies a file or directory from the host into a running
+// container. An empty destination uses the same path as source.
+func (c *Conn) CopyToMachine(name, source, destination string) error {
+	return c.object.Call(dbusInterface+".CopyToMachine", 0, name, source, destination).Err
+}
+
+// CopyFromMachine copies a file or directory from a running container to the
+// host. An empty destination uses the same path as source.
+func (c *Conn) CopyFromMachine(name, source, destination string) error {
+	return c.object.Call(dbusInterface+".CopyFromMachine", 0, name, source, destination).Err
+}
+
+// CopyToMachineWithFlags is like CopyToMachine, but accepts CopyFlag* values.
+// Requires systemd v248 or higher.
+func (c *Conn) CopyToMachineWithFlags(name, source, destination string, flags uint64) error {
+	return c.object.Call(dbusInterface+".CopyToMachineWithFlags", 0, name, source, destination, flags).Err
+}
+
+// CopyFromMachineWithFlags is like CopyFromMachine, but accepts CopyFlag*
+// values. Requires systemd v248 or higher.
+func (c *Conn) CopyFromMachineWithFlags(name, source, destination string, flags uint64) error {
+	return c.object.Call(dbusInterface+".CopyFromMachineWithFlags", 0, name, source, destination, flags).Err
+}
+
 func machineFromInterfaces(machine []interface{}) (*MachineStatus, error) {
 	if len(machine) < 4 {
 		return nil, fmt.Errorf("invalid number of machine fields: %d", len(machine))
@@ -211,7 +344,27 @@ func machineFromInterfaces(machine []interface{}) (*MachineStatus, error) {
 	return &ret, nil
 }
 
-// ListMachines returns an array of all currently running machines.
+func addressFromInterfaces(address []interface{}) (net.IP, error) {
+	if len(address) < 2 {
+		return nil, fmt.Errorf("invalid number of address fields: %d", len(address))
+	}
+	family, ok := address[0].(int32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast family field 0 to int32")
+	}
+	addr, ok := address[1].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast address field 1 to []byte")
+	}
+	if (family == syscall.AF_INET && len(addr) != net.IPv4len) || (family == syscall.AF_INET6 && len(addr) != net.IPv6len) {
+		return nil, fmt.Errorf("invalid address length %d for family %d", len(addr), family)
+	}
+
+	return net.IP(addr), nil
+}
+
+// ListMachines returns an array of all currently running machines along with
+// their state. Machines which terminate while they are listed are left out.
 func (c *Conn) ListMachines() ([]MachineStatus, error) {
 	result := make([][]interface{}, 0)
 	if err := c.object.Call(dbusInterface+".ListMachines", 0).Store(&result); err != nil {
@@ -224,6 +377,13 @@ func (c *Conn) ListMachines() ([]MachineStatus, error) {
 		if err != nil {
 			return nil, err
 		}
+		machine.State, err = c.machineState(machine.JobPath)
+		if err != nil {
+			if e, ok := err.(dbus.Error); ok && e.Name == "org.freedesktop.DBus.Error.UnknownObject" {
+				continue
+			}
+			return nil, err
+		}
 		machs = append(machs, *machine)
 	}
 
@@ -267,6 +427,46 @@ func imageFromInterfaces(image []interface{}) (*ImageStatus, error) {
 	return &ret, nil
 }
 
+// CloneImage clones an image under a new name, optionally making the clone read-only
+func (c *Conn) CloneImage(name, newName string, readOnly bool) error {
+	return c.object.Call(dbusInterface+".CloneImage", 0, name, newName, readOnly).Err
+}
+
+// RenameImage renames an image
+func (c *Conn) RenameImage(name, newName string) error {
+	return c.object.Call(dbusInterface+".RenameImage", 0, name, newName).Err
+}
+
+// RemoveImage removes an image
+func (c *Conn) RemoveImage(name string) error {
+	return c.object.Call(dbusInterface+".RemoveImage", 0, name).Err
+}
+
+// MarkImageReadOnly toggles the read-only flag of an image
+func (c *Conn) MarkImageReadOnly(name string, readOnly bool) error {
+	return c.object.Call(dbusInterface+".MarkImageReadOnly", 0, name, readOnly).Err
+}
+
+// SetImageLimit sets the disk space limit of an image in bytes. This is only
+// supported for images on btrfs subvolumes with quota enabled.
+func (c *Conn) SetImageLimit(name string, limit uint64) error {
+	return c.object.Call(dbusInterface+".SetImageLimit", 0, name, limit).Err
+}
+
+// SetPoolLimit sets the disk space limit of the whole /var/lib/machines image pool in bytes
+func (c *Conn) SetPoolLimit(limit uint64) error {
+	return c.object.Call(dbusInterface+".SetPoolLimit", 0, limit).Err
+}
+
+// GetImageOSRelease gets the os-release(5) fields of the operating system contained in an image
+func (c *Conn) GetImageOSRelease(name string) (map[string]string, error) {
+	var osRelease map[string]string
+	if err := c.object.Call(dbusInterface+".GetImageOSRelease", 0, name).Store(&osRelease); err != nil {
+		return nil, err
+	}
+	return osRelease, nil
+}
+
 // ListImages returns an array of all currently available images.
 func (c *Conn) ListImages() ([]ImageStatus, error) {
 	result := make([][]interface{}, 0)
@@ -19,9 +19,12 @@ package machine1
 import (
 	"fmt"
 	"math/rand"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -123,6 +126,11 @@ func TestMachine(t *testing.T) {
 	if len(listMachines) <= 4 {
 		t.Fatalf("did not find any machine")
 	}
+	for _, m := range listMachines {
+		if m.State == "" {
+			t.Errorf("no state for machine %s", m.Name)
+		}
+	}
 
 	for _, v := range machineNames {
 		tErr := conn.TerminateMachine(v)
@@ -145,6 +153,139 @@ func TestMachine(t *testing.T) {
 	}
 }
 
+func TestUnregisterMachine(t *testing.T) {
+	machineName := machinePrefix + "unregister-" + generateRandomLabel(8)
+	leader := mustCreateTestProcess(machineName)
+
+	conn, newErr := New()
+	if newErr != nil {
+		t.Fatal(newErr)
+	}
+	defer conn.Close()
+
+	if err := conn.RegisterMachine(machineName, nil, "go-systemd", "container", leader, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.UnregisterMachine(machineName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.GetMachine(machineName); err == nil {
+		t.Fatalf("unexpectedly found machine named %s", machineName)
+	}
+
+	// Unregistering must leave the leader process running.
+	proc, err := os.FindProcess(leader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proc.Signal(syscall.Signal(0)); err != nil {
+		t.Fatalf("leader of unregistered machine is gone: %v", err)
+	}
+	proc.Signal(syscall.SIGTERM)
+}
+
+func TestHostMachine(t *testing.T) {
+	conn, newErr := New()
+	if newErr != nil {
+		t.Fatal(newErr)
+	}
+	defer conn.Close()
+
+	machine, err := conn.GetMachineByPID(uint(os.Getpid()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostMachine, err := conn.GetMachine(".host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if machine != hostMachine {
+		t.Fatalf("expected machine %s but got %s", hostMachine, machine)
+	}
+
+	state, err := conn.GetMachineState(".host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state != "running" {
+		t.Fatalf("unexpected host machine state %q", state)
+	}
+
+	osRelease, err := conn.GetMachineOSRelease(".host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(osRelease) == 0 {
+		t.Fatal("no os-release fields returned")
+	}
+
+	if _, err := conn.GetMachineIPAddresses(".host"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpenMachinePTY(t *testing.T) {
+	conn, newErr := New()
+	if newErr != nil {
+		t.Fatal(newErr)
+	}
+	defer conn.Close()
+
+	pty, err := conn.OpenMachinePTY(".host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pty.Close()
+
+	if !strings.HasPrefix(pty.Path, "/dev/pts/") {
+		t.Fatalf("unexpected pty path %q", pty.Path)
+	}
+	if err := pty.Resize(24, 80); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyUnknownMachine(t *testing.T) {
+	conn, newErr := New()
+	if newErr != nil {
+		t.Fatal(newErr)
+	}
+	defer conn.Close()
+
+	name := machinePrefix + "unknown-" + generateRandomLabel(8)
+	if err := conn.CopyToMachine(name, "/etc/os-release", "/tmp/os-release"); err == nil {
+		t.Fatal("expected error copying to unknown machine")
+	}
+	if err := conn.CopyFromMachine(name, "/etc/os-release", "/tmp/os-release"); err == nil {
+		t.Fatal("expected error copying from unknown machine")
+	}
+	if err := conn.BindMountMachine(name, "/etc", "/mnt", true, true); err == nil {
+		t.Fatal("expected error bind mounting into unknown machine")
+	}
+}
+
+func TestAddressFromInterfaces(t *testing.T) {
+	addr, err := addressFromInterfaces([]interface{}{int32(syscall.AF_INET), []byte{192, 168, 0, 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.Equal(net.IPv4(192, 168, 0, 1)) {
+		t.Fatalf("unexpected address %s", addr)
+	}
+
+	addr, err = addressFromInterfaces([]interface{}{int32(syscall.AF_INET6), []byte(net.IPv6loopback)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !addr.Equal(net.IPv6loopback) {
+		t.Fatalf("unexpected address %s", addr)
+	}
+
+	if _, err := addressFromInterfaces([]interface{}{int32(syscall.AF_INET), []byte{127, 0, 0}}); err == nil {
+		t.Fatal("expected error for truncated address")
+	}
+}
+
 func generateRandomLabel(n int) string {
 	letters := []rune("abcdefghijklmnopqrstuvwxyz")
 	s := make([]rune, n)
@@ -181,4 +322,33 @@ func TestImages(t *testing.T) {
 	if len(listImages) < 1 {
 		t.Fatalf("did not find any image")
 	}
+
+	props, err := conn.DescribeImage(imageName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if props["Name"] != imageName {
+		t.Fatalf("expected image name %s but got %v", imageName, props["Name"])
+	}
+
+	cloneName := imageName + "-clone"
+	if err := conn.CloneImage(imageName, cloneName, false); err != nil {
+		t.Fatal(err)
+	}
+	renamedName := imageName + "-renamed"
+	if err := conn.RenameImage(cloneName, renamedName); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.MarkImageReadOnly(renamedName, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.MarkImageReadOnly(renamedName, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.RemoveImage(renamedName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.GetImage(renamedName); err == nil {
+		t.Fatalf("unexpectedly found image named %s", renamedName)
+	}
 }
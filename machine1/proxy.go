@@ -0,0 +1,155 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine1
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// DefaultEscape is the escape sequence of Proxy, three times Ctrl-], as in
+// machinectl.
+var DefaultEscape = []byte{0x1d, 0x1d, 0x1d}
+
+// Proxy connects a local terminal to a pseudo terminal of a machine, like
+// machinectl login and shell do: the local terminal is put into raw mode,
+// its window size is propagated to the machine whenever it changes, and
+// typing the escape sequence disconnects.
+type Proxy struct {
+	PTY *PTY
+	In  *os.File // The terminal to read from, os.Stdin if nil
+	Out *os.File // The terminal to write to, os.Stdout if nil
+
+	// Escape is the sequence typed to disconnect, DefaultEscape if nil.
+	// It is passed on to the machine like all other input.
+	Escape []byte
+	// EscapeTimeout is the time within which the whole escape sequence
+	// must be typed, one second if zero.
+	EscapeTimeout time.Duration
+	// DisableEscape only disconnects when the pseudo terminal is hung up or
+	// the proxy is stopped.
+	DisableEscape bool
+}
+
+// Run proxies between the terminal and the pseudo terminal until the escape
+// sequence is typed, the pseudo terminal is hung up, the process receives
+// SIGTERM or SIGHUP, or ctx is done, restoring the terminal before it
+// returns. Only ctx being done is reported as error.
+//
+// As reading from the terminal cannot be interrupted, a read may still be
+// pending when Run returns, consuming the next input.
+func (p *Proxy) Run(ctx context.Context) error {
+	in, out := p.In, p.Out
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stdout
+	}
+
+	if restore, err := makeRaw(in); err == nil {
+		defer restore()
+	}
+
+	stop := watchTerminal(out, p.PTY)
+	defer stop.close()
+
+	done := make(chan error, 2)
+	go func() {
+		_, err := io.Copy(out, p.PTY.File)
+		done <- hangupErr(err)
+	}()
+	go func() {
+		done <- p.copyInput(in)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-stop.signaled:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// copyInput copies the terminal input to the pseudo terminal until the
+// escape sequence was typed.
+func (p *Proxy) copyInput(in *os.File) error {
+	e := escapeMatcher{seq: p.Escape, timeout: p.EscapeTimeout}
+	if e.seq == nil {
+		e.seq = DefaultEscape
+	}
+	if e.timeout == 0 {
+		e.timeout = time.Second
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if _, werr := p.PTY.Write(buf[:n]); werr != nil {
+				return hangupErr(werr)
+			}
+			if !p.DisableEscape && e.match(buf[:n], time.Now()) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// escapeMatcher detects an escape sequence typed within a timeout.
+type escapeMatcher struct {
+	seq     []byte
+	timeout time.Duration
+	matched int
+	start   time.Time
+}
+
+// match feeds input typed at now and returns whether it completed the
+// escape sequence.
+func (e *escapeMatcher) match(input []byte, now time.Time) bool {
+	if len(e.seq) == 0 {
+		return false
+	}
+	for _, b := range input {
+		if e.matched > 0 && now.Sub(e.start) > e.timeout {
+			e.matched = 0
+		}
+		if b != e.seq[e.matched] {
+			e.matched = 0
+			if b != e.seq[0] {
+				continue
+			}
+		}
+		if e.matched == 0 {
+			e.start = now
+		}
+		e.matched++
+		if e.matched == len(e.seq) {
+			e.matched = 0
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine1
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+func ioctl(f *os.File, req uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), req, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// makeRaw puts the terminal f into raw mode like cfmakeraw and returns a
+// function restoring its previous mode.
+func makeRaw(f *os.File) (func(), error) {
+	var old syscall.Termios
+	if err := ioctl(f, syscall.TCGETS, unsafe.Pointer(&old)); err != nil {
+		return nil, err
+	}
+	t := old
+	t.Iflag &^= syscall.IGNBRK | syscall.BRKINT | syscall.PARMRK | syscall.ISTRIP | syscall.INLCR | syscall.IGNCR | syscall.ICRNL | syscall.IXON
+	t.Oflag &^= syscall.OPOST
+	t.Lflag &^= syscall.ECHO | syscall.ECHONL | syscall.ICANON | syscall.ISIG | syscall.IEXTEN
+	t.Cflag &^= syscall.CSIZE | syscall.PARENB
+	t.Cflag |= syscall.CS8
+	t.Cc[syscall.VMIN] = 1
+	t.Cc[syscall.VTIME] = 0
+	if err := ioctl(f, syscall.TCSETS, unsafe.Pointer(&t)); err != nil {
+		return nil, err
+	}
+	return func() {
+		ioctl(f, syscall.TCSETS, unsafe.Pointer(&old))
+	}, nil
+}
+
+func getWindowSize(f *os.File) (winsize, error) {
+	var ws winsize
+	err := ioctl(f, syscall.TIOCGWINSZ, unsafe.Pointer(&ws))
+	return ws, err
+}
+
+type terminalWatch struct {
+	signals  chan os.Signal
+	signaled chan struct{}
+	done     chan struct{}
+}
+
+// watchTerminal copies the window size of the terminal out to pty now and
+// whenever it changes, and closes signaled on SIGTERM or SIGHUP.
+func watchTerminal(out *os.File, pty *PTY) *terminalWatch {
+	w := &terminalWatch{
+		signals:  make(chan os.Signal, 1),
+		signaled: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	resize := func() {
+		if ws, err := getWindowSize(out); err == nil {
+			pty.Resize(ws.rows, ws.cols)
+		}
+	}
+	resize()
+
+	signal.Notify(w.signals, syscall.SIGWINCH, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		for {
+			select {
+			case s := <-w.signals:
+				if s == syscall.SIGWINCH {
+					resize()
+					continue
+				}
+				close(w.signaled)
+				return
+			case <-w.done:
+				return
+			}
+		}
+	}()
+	return w
+}
+
+func (w *terminalWatch) close() {
+	signal.Stop(w.signals)
+	close(w.done)
+}
+
+// hangupErr maps the EIO returned by the master side of a pseudo terminal
+// once the machine side is closed to a regular end.
+func hangupErr(err error) error {
+	if pe, ok := err.(*os.PathError); ok && pe.Err == syscall.EIO {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// openPTY opens a pseudo terminal pair, standing in for the one machined
+// allocates inside a machine.
+func openPTY(t *testing.T) (*PTY, *os.File) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skip(err)
+	}
+	var unlock int32
+	if err := ioctl(master, syscall.TIOCSPTLCK, unsafe.Pointer(&unlock)); err != nil {
+		t.Fatal(err)
+	}
+	var n uint32
+	if err := ioctl(master, syscall.TIOCGPTN, unsafe.Pointer(&n)); err != nil {
+		t.Fatal(err)
+	}
+	path := fmt.Sprintf("/dev/pts/%d", n)
+	slave, err := os.OpenFile(path, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &PTY{File: master, Path: path}, slave
+}
+
+func TestProxy(t *testing.T) {
+	pty, machine := openPTY(t)
+	defer pty.Close()
+	defer machine.Close()
+	if _, err := makeRaw(machine); err != nil {
+		t.Fatal(err)
+	}
+
+	inR, inW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inR.Close()
+	defer inW.Close()
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer outR.Close()
+	defer outW.Close()
+
+	p := &Proxy{PTY: pty, In: inR, Out: outW}
+	done := make(chan error)
+	go func() {
+		done <- p.Run(context.Background())
+	}()
+
+	inW.Write([]byte("input"))
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(machine, buf); err != nil || string(buf) != "input" {
+		t.Fatalf("machine read %q, %v", buf, err)
+	}
+	machine.Write([]byte("output"))
+	buf = make([]byte, 6)
+	if _, err := io.ReadFull(outR, buf); err != nil || string(buf) != "output" {
+		t.Fatalf("terminal read %q, %v", buf, err)
+	}
+
+	inW.Write(DefaultEscape)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("escape sequence did not end the proxy")
+	}
+}
+
+func TestProxyHangup(t *testing.T) {
+	pty, machine := openPTY(t)
+	defer pty.Close()
+
+	inR, inW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inR.Close()
+	defer inW.Close()
+	null, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer null.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- (&Proxy{PTY: pty, In: inR, Out: null}).Run(ctx)
+	}()
+	machine.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("hangup ended proxy with %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package machine1
+
+import (
+	"errors"
+	"os"
+)
+
+func makeRaw(f *os.File) (func(), error) {
+	return nil, errors.New("raw terminal mode is only supported on Linux")
+}
+
+type terminalWatch struct {
+	signaled chan struct{}
+}
+
+func watchTerminal(out *os.File, pty *PTY) *terminalWatch {
+	return &terminalWatch{signaled: make(chan struct{})}
+}
+
+func (w *terminalWatch) close() {}
+
+func hangupErr(err error) error {
+	return err
+}
@@ -0,0 +1,52 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEscapeMatcher(t *testing.T) {
+	start := time.Unix(1000, 0)
+	for i, tt := range []struct {
+		inputs []string
+		gaps   time.Duration
+		want   bool
+	}{
+		{[]string{"\x1d\x1d\x1d"}, 0, true},
+		{[]string{"ls\x1d", "\x1d", "\x1d"}, 100 * time.Millisecond, true},
+		{[]string{"\x1d\x1d", "\x1d"}, 2 * time.Second, false},
+		{[]string{"\x1d\x1dx\x1d"}, 0, false},
+		{[]string{"\x1dx\x1d\x1d\x1d"}, 0, true},
+		{[]string{"hello"}, 0, false},
+	} {
+		e := escapeMatcher{seq: DefaultEscape, timeout: time.Second}
+		got := false
+		for j, in := range tt.inputs {
+			if e.match([]byte(in), start.Add(time.Duration(j)*tt.gaps)) {
+				got = true
+			}
+		}
+		if got != tt.want {
+			t.Errorf("case %d: got %v", i, got)
+		}
+	}
+
+	e := escapeMatcher{seq: []byte("~."), timeout: time.Second}
+	if !e.match([]byte("\r~."), start) {
+		t.Error("custom escape sequence not matched")
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine1
+
+import (
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// PTY is the master side of a pseudo terminal allocated inside a machine.
+type PTY struct {
+	*os.File
+	Path string // The path of the pseudo terminal inside the machine, e.g. /dev/pts/0
+}
+
+// Resize sets the window size of the pseudo terminal, e.g. after the size
+// of a web console changed.
+func (p *PTY) Resize(rows, cols uint16) error {
+	return setWindowSize(p.File, rows, cols)
+}
+
+func (c *Conn) openPTY(method string, args ...interface{}) (*PTY, error) {
+	var fd dbus.UnixFD
+	var path string
+	if err := c.object.Call(dbusInterface+"."+method, 0, args...).Store(&fd, &path); err != nil {
+		return nil, err
+	}
+	return &PTY{File: os.NewFile(uintptr(fd), path), Path: path}, nil
+}
+
+// OpenMachinePTY allocates a pseudo terminal inside a container, without
+// starting anything on it
+func (c *Conn) OpenMachinePTY(name string) (*PTY, error) {
+	return c.openPTY("OpenMachinePTY", name)
+}
+
+// OpenMachineLogin allocates a pseudo terminal inside a container and starts
+// a getty login prompt on it
+func (c *Conn) OpenMachineLogin(name string) (*PTY, error) {
+	return c.openPTY("OpenMachineLogin", name)
+}
+
+// OpenMachineShell allocates a pseudo terminal inside a container and runs
+// path with args (including argv[0]) and the additional environment
+// variables on it, as the given user. An empty user means root, an empty
+// path the user's login shell.
+func (c *Conn) OpenMachineShell(name, user, path string, args, environment []string) (*PTY, error) {
+	if args == nil {
+		args = []string{}
+	}
+	if environment == nil {
+		environment = []string{}
+	}
+	return c.openPTY("OpenMachineShell", name, user, path, args, environment)
+}
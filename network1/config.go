@@ -0,0 +1,344 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// ConfigKind is the type of a networkd configuration file.
+type ConfigKind string
+
+const (
+	KindNetwork ConfigKind = "network" // A .network file configuring links
+	KindNetDev  ConfigKind = "netdev"  // A .netdev file creating virtual devices
+	KindLink    ConfigKind = "link"    // A .link file applied by udev
+)
+
+// configSchema describes a section of a configuration file. A nil key set
+// accepts any key, which is used for the device specific sections of .netdev
+// files.
+type configSchema struct {
+	keys       map[string]bool
+	repeatable bool
+}
+
+func keySet(keys ...string) map[string]bool {
+	m := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		m[k] = true
+	}
+	return m
+}
+
+var matchConditionKeys = []string{"Host", "Virtualization", "KernelCommandLine", "KernelVersion", "Credential", "Architecture", "Firmware"}
+
+var dhcpv4Keys = keySet("SendHostname", "Hostname", "MUDURL", "ClientIdentifier", "VendorClassIdentifier",
+	"UserClass", "DUIDType", "DUIDRawData", "IAID", "Anonymize", "RequestOptions", "SendOption",
+	"SendVendorOption", "IPServiceType", "Label", "UseDNS", "RoutesToDNS", "UseNTP", "RoutesToNTP",
+	"UseSIP", "UseCaptivePortal", "UseMTU", "UseHostname", "UseDomains", "UseRoutes", "RouteMetric",
+	"RouteTable", "RouteMTUBytes", "UseGateway", "UseTimezone", "Use6RD", "RequestBroadcast",
+	"MaxAttempts", "ListenPort", "DenyList", "AllowList", "SendRelease", "SendDecline",
+	"FallbackLeaseLifetimeSec", "NetLabel", "NFTSet", "CriticalConnection")
+
+var configSchemas = map[ConfigKind]map[string]*configSchema{
+	KindNetwork: {
+		"Match": {keys: keySet(append([]string{"MACAddress", "PermanentMACAddress", "Path", "Driver", "Type",
+			"Kind", "Property", "Name", "WLANInterfaceType", "SSID", "BSSID"}, matchConditionKeys...)...)},
+		"Link": {keys: keySet("MACAddress", "MTUBytes", "ARP", "Multicast", "AllMulticast", "Promiscuous",
+			"Unmanaged", "Group", "RequiredForOnline", "RequiredFamilyForOnline", "ActivationPolicy")},
+		"Network": {keys: keySet("Description", "DHCP", "DHCPServer", "LinkLocalAddressing",
+			"IPv6LinkLocalAddressGenerationMode", "IPv4LLRoute", "DefaultRouteOnDevice", "LLMNR",
+			"MulticastDNS", "DNSOverTLS", "DNSSEC", "DNSSECNegativeTrustAnchors", "LLDP", "EmitLLDP",
+			"BindCarrier", "Address", "Gateway", "DNS", "Domains", "DNSDefaultRoute", "NTP", "IPForward",
+			"IPMasquerade", "IPv6PrivacyExtensions", "IPv6AcceptRA", "IPv6DuplicateAddressDetection",
+			"IPv6HopLimit", "IPv4ProxyARP", "IPv6ProxyNDP", "IPv6ProxyNDPAddress", "IPv6SendRA",
+			"DHCPPrefixDelegation", "IPv6MTUBytes", "KeepConfiguration", "ConfigureWithoutCarrier",
+			"IgnoreCarrierLoss", "Bridge", "Bond", "VRF", "VLAN", "IPVLAN", "MACVLAN", "VXLAN", "Tunnel",
+			"MACsec", "Xfrm", "ActiveSlave", "PrimarySlave")},
+		"Address": {repeatable: true, keys: keySet("Address", "Peer", "Broadcast", "Label",
+			"PreferredLifetime", "Scope", "RouteMetric", "HomeAddress", "DuplicateAddressDetection",
+			"ManageTemporaryAddress", "AddPrefixRoute", "AutoJoin", "NetLabel", "NFTSet")},
+		"Route": {repeatable: true, keys: keySet("Gateway", "GatewayOnLink", "Destination", "Source",
+			"Metric", "IPv6Preference", "Scope", "PreferredSource", "Table", "Protocol", "Type",
+			"InitialCongestionWindow", "InitialAdvertisedReceiveWindow", "QuickAck", "FastOpenNoCookie",
+			"TTLPropagate", "MTUBytes", "TCPAdvertisedMaximumSegmentSize", "TCPCongestionControlAlgorithm",
+			"MultiPathRoute", "NextHop")},
+		// [DHCP] is the deprecated name of the [DHCPv4] section.
+		"DHCP":   {keys: dhcpv4Keys},
+		"DHCPv4": {keys: dhcpv4Keys},
+		"DHCPv6": {keys: keySet("MUDURL", "RequestOptions", "SendVendorOption", "UserClass", "VendorClass",
+			"SendOption", "PrefixDelegationHint", "RapidCommit", "SendHostname", "Hostname", "UseAddress",
+			"UseCaptivePortal", "UseDelegatedPrefix", "UseDNS", "UseNTP", "UseHostname", "UseDomains",
+			"WithoutRA", "DUIDType", "DUIDRawData", "IAID")},
+	},
+	KindNetDev: {
+		"Match":         {keys: keySet(matchConditionKeys...)},
+		"NetDev":        {keys: keySet("Description", "Name", "Kind", "MTUBytes", "MACAddress")},
+		"Bridge":        {},
+		"VLAN":          {},
+		"MACVLAN":       {},
+		"MACVTAP":       {},
+		"IPVLAN":        {},
+		"VXLAN":         {},
+		"Tunnel":        {},
+		"Tun":           {},
+		"Tap":           {},
+		"Bond":          {},
+		"VRF":           {},
+		"Peer":          {},
+		"WireGuard":     {},
+		"WireGuardPeer": {repeatable: true},
+	},
+	KindLink: {
+		"Match": {keys: keySet(append([]string{"MACAddress", "PermanentMACAddress", "OriginalName", "Path",
+			"Driver", "Type", "Kind", "Property"}, matchConditionKeys...)...)},
+		"Link": {keys: keySet("Description", "Alias", "MACAddressPolicy", "MACAddress", "NamePolicy",
+			"Name", "AlternativeNamesPolicy", "AlternativeName", "TransmitQueues", "ReceiveQueues",
+			"TransmitQueueLength", "MTUBytes", "BitsPerSecond", "Duplex", "AutoNegotiation", "WakeOnLan",
+			"Port", "Advertise", "ReceiveChecksumOffload", "TransmitChecksumOffload",
+			"TCPSegmentationOffload", "GenericSegmentationOffload", "GenericReceiveOffload",
+			"LargeReceiveOffload", "RxChannels", "TxChannels", "OtherChannels", "CombinedChannels",
+			"RxBufferSize", "TxBufferSize")},
+	},
+}
+
+// ConfigFile builds a networkd configuration file. Errors made while
+// building, e.g. unknown sections or keys, are recorded and returned by Err
+// and Serialize.
+type ConfigFile struct {
+	kind     ConfigKind
+	sections []*unit.UnitSection
+	err      error
+}
+
+// ConfigSection is a section of a ConfigFile.
+type ConfigSection struct {
+	file    *ConfigFile
+	schema  *configSchema
+	section *unit.UnitSection
+}
+
+// NewNetworkConfig returns an empty .network file.
+func NewNetworkConfig() *ConfigFile {
+	return &ConfigFile{kind: KindNetwork}
+}
+
+// NewNetDevConfig returns an empty .netdev file.
+func NewNetDevConfig() *ConfigFile {
+	return &ConfigFile{kind: KindNetDev}
+}
+
+// NewLinkConfig returns an empty .link file.
+func NewLinkConfig() *ConfigFile {
+	return &ConfigFile{kind: KindLink}
+}
+
+// Kind returns the type of the file.
+func (f *ConfigFile) Kind() ConfigKind {
+	return f.kind
+}
+
+// Err returns the first error made while building the file.
+func (f *ConfigFile) Err() error {
+	return f.err
+}
+
+func (f *ConfigFile) fail(format string, args ...interface{}) {
+	if f.err == nil {
+		f.err = fmt.Errorf(format, args...)
+	}
+}
+
+// Section returns the named section, adding it if the file has none yet. For
+// sections which may appear multiple times, such as [Address] or [Route], the
+// last one is returned; use AddSection to start a new one.
+func (f *ConfigFile) Section(name string) *ConfigSection {
+	for i := len(f.sections) - 1; i >= 0; i-- {
+		if f.sections[i].Section == name {
+			return &ConfigSection{file: f, schema: f.schema(name), section: f.sections[i]}
+		}
+	}
+	return f.AddSection(name)
+}
+
+// AddSection appends a new instance of the named section.
+func (f *ConfigFile) AddSection(name string) *ConfigSection {
+	schema := f.schema(name)
+	if _, ok := configSchemas[f.kind][name]; !ok {
+		f.fail("unknown section [%s] in .%s file", name, f.kind)
+	} else if !schema.repeatable {
+		for _, s := range f.sections {
+			if s.Section == name {
+				f.fail("section [%s] may only appear once in .%s file", name, f.kind)
+			}
+		}
+	}
+
+	s := &unit.UnitSection{Section: name}
+	f.sections = append(f.sections, s)
+	return &ConfigSection{file: f, schema: schema, section: s}
+}
+
+// schema returns the schema of the named section. Unknown sections, already
+// reported by AddSection, accept any key.
+func (f *ConfigFile) schema(name string) *configSchema {
+	if schema, ok := configSchemas[f.kind][name]; ok {
+		return schema
+	}
+	return &configSchema{repeatable: true}
+}
+
+// Match returns the [Match] section.
+func (f *ConfigFile) Match() *ConfigSection {
+	return f.Section("Match")
+}
+
+// Network returns the [Network] section of a .network file.
+func (f *ConfigFile) Network() *ConfigSection {
+	return f.Section("Network")
+}
+
+// Link returns the [Link] section of a .network or .link file.
+func (f *ConfigFile) Link() *ConfigSection {
+	return f.Section("Link")
+}
+
+// NetDev returns the [NetDev] section of a .netdev file.
+func (f *ConfigFile) NetDev() *ConfigSection {
+	return f.Section("NetDev")
+}
+
+// DHCPv4 returns the [DHCPv4] section of a .network file.
+func (f *ConfigFile) DHCPv4() *ConfigSection {
+	return f.Section("DHCPv4")
+}
+
+// DHCPv6 returns the [DHCPv6] section of a .network file.
+func (f *ConfigFile) DHCPv6() *ConfigSection {
+	return f.Section("DHCPv6")
+}
+
+// AddAddress appends an [Address] section for the given address in CIDR
+// notation.
+func (f *ConfigFile) AddAddress(address string) *ConfigSection {
+	if _, _, err := net.ParseCIDR(address); err != nil {
+		f.fail("invalid address %q: %v", address, err)
+	}
+	return f.AddSection("Address").Set("Address", address)
+}
+
+// AddRoute appends a [Route] section for the given destination in CIDR
+// notation and gateway. Either may be empty.
+func (f *ConfigFile) AddRoute(destination, gateway string) *ConfigSection {
+	s := f.AddSection("Route")
+	if destination != "" {
+		if _, _, err := net.ParseCIDR(destination); err != nil {
+			f.fail("invalid route destination %q: %v", destination, err)
+		}
+		s.Set("Destination", destination)
+	}
+	if gateway != "" {
+		// networkd also accepts the special _dhcp4 and _ipv6ra gateways.
+		if net.ParseIP(gateway) == nil && !strings.HasPrefix(gateway, "_") {
+			f.fail("invalid route gateway %q", gateway)
+		}
+		s.Set("Gateway", gateway)
+	}
+	return s
+}
+
+// Name returns the name of the section.
+func (s *ConfigSection) Name() string {
+	return s.section.Section
+}
+
+// Set adds the key with each of the given values to the section. Keys taking
+// a list, such as DNS= or Address=, may be set several times.
+func (s *ConfigSection) Set(key string, values ...string) *ConfigSection {
+	if s.schema.keys != nil && !s.schema.keys[key] {
+		s.file.fail("unknown key %s in section [%s] of .%s file", key, s.section.Section, s.file.kind)
+	}
+	return s.SetUnchecked(key, values...)
+}
+
+// SetUnchecked is like Set, but accepts keys unknown to this package, e.g.
+// those added by newer versions of networkd.
+func (s *ConfigSection) SetUnchecked(key string, values ...string) *ConfigSection {
+	if key == "" || strings.ContainsAny(key, "=[]\n") {
+		s.file.fail("invalid key %q in section [%s]", key, s.section.Section)
+	}
+	for _, v := range values {
+		if strings.ContainsAny(v, "\n") {
+			s.file.fail("invalid value %q for key %s: contains newline", v, key)
+		}
+		s.section.Entries = append(s.section.Entries, &unit.UnitEntry{Name: key, Value: v})
+	}
+	return s
+}
+
+// SetBool sets the key to yes or no.
+func (s *ConfigSection) SetBool(key string, value bool) *ConfigSection {
+	if value {
+		return s.Set(key, "yes")
+	}
+	return s.Set(key, "no")
+}
+
+// Sections returns the sections of the file, with the [Match] section first.
+func (f *ConfigFile) Sections() []*unit.UnitSection {
+	sections := make([]*unit.UnitSection, 0, len(f.sections))
+	for _, s := range f.sections {
+		if s.Section == "Match" {
+			sections = append(sections, s)
+		}
+	}
+	for _, s := range f.sections {
+		if s.Section != "Match" {
+			sections = append(sections, s)
+		}
+	}
+	return sections
+}
+
+// Serialize encodes the file, returning the first error made while building it.
+func (f *ConfigFile) Serialize() (io.Reader, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.kind == KindNetDev && !f.hasKey("NetDev", "Kind") {
+		return nil, fmt.Errorf("missing Kind= in section [NetDev] of .netdev file")
+	}
+	return unit.SerializeSections(f.Sections()), nil
+}
+
+func (f *ConfigFile) hasKey(section, key string) bool {
+	for _, s := range f.sections {
+		if s.Section != section {
+			continue
+		}
+		for _, e := range s.Entries {
+			if e.Name == key {
+				return true
+			}
+		}
+	}
+	return false
+}
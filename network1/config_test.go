@@ -0,0 +1,133 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"io/ioutil"
+	"testing"
+)
+
+func TestNetworkConfig(t *testing.T) {
+	f := NewNetworkConfig()
+	f.Network().Set("DNS", "10.0.0.1", "10.0.0.2").SetBool("IPv6AcceptRA", false)
+	f.Match().Set("Name", "eth0")
+	f.AddAddress("10.0.0.10/24")
+	f.AddAddress("fd00::10/64").Set("PreferredLifetime", "0")
+	f.AddRoute("192.168.0.0/16", "10.0.0.1").Set("Metric", "100")
+	f.DHCPv4().Set("UseDNS", "no")
+
+	r, err := f.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[Match]
+Name=eth0
+
+[Network]
+DNS=10.0.0.1
+DNS=10.0.0.2
+IPv6AcceptRA=no
+
+[Address]
+Address=10.0.0.10/24
+
+[Address]
+Address=fd00::10/64
+PreferredLifetime=0
+
+[Route]
+Destination=192.168.0.0/16
+Gateway=10.0.0.1
+Metric=100
+
+[DHCPv4]
+UseDNS=no
+`
+	if string(got) != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestNetDevConfig(t *testing.T) {
+	f := NewNetDevConfig()
+	f.NetDev().Set("Name", "wg0")
+	if _, err := f.Serialize(); err == nil {
+		t.Fatal("expected error for missing Kind=")
+	}
+
+	f.NetDev().Set("Kind", "wireguard")
+	f.Section("WireGuard").Set("PrivateKeyFile", "/etc/systemd/network/wg0.key")
+	f.AddSection("WireGuardPeer").Set("PublicKey", "a")
+	f.AddSection("WireGuardPeer").Set("PublicKey", "b")
+	if _, err := f.Serialize(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	for name, build := range map[string]func() *ConfigFile{
+		"unknown section": func() *ConfigFile {
+			f := NewLinkConfig()
+			f.Network()
+			return f
+		},
+		"unknown section reused": func() *ConfigFile {
+			f := NewNetworkConfig()
+			f.Section("Unknown")
+			f.Section("Unknown").Set("Key", "value")
+			return f
+		},
+		"unknown key": func() *ConfigFile {
+			f := NewNetworkConfig()
+			f.Network().Set("Adress", "10.0.0.1/24")
+			return f
+		},
+		"duplicate section": func() *ConfigFile {
+			f := NewNetworkConfig()
+			f.AddSection("Network")
+			f.AddSection("Network")
+			return f
+		},
+		"invalid address": func() *ConfigFile {
+			f := NewNetworkConfig()
+			f.AddAddress("10.0.0.1")
+			return f
+		},
+		"newline in value": func() *ConfigFile {
+			f := NewLinkConfig()
+			f.Link().Set("Description", "a\nb")
+			return f
+		},
+	} {
+		f := build()
+		if f.Err() == nil {
+			t.Errorf("%s: expected error", name)
+		}
+		if _, err := f.Serialize(); err == nil {
+			t.Errorf("%s: expected serialize error", name)
+		}
+	}
+
+	f := NewNetworkConfig()
+	f.Network().SetUnchecked("FutureKey", "yes")
+	if err := f.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
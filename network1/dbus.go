@@ -0,0 +1,240 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package network1 provides integration with the systemd-networkd API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.network1.html
+package network1
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest          = "org.freedesktop.network1"
+	dbusInterface     = "org.freedesktop.network1.Manager"
+	dbusLinkInterface = "org.freedesktop.network1.Link"
+	dbusPath          = "/org/freedesktop/network1"
+)
+
+// Link is a network interface managed by networkd.
+type Link struct {
+	IfIndex int32
+	Name    string
+	Path    dbus.ObjectPath
+}
+
+// LinkState holds the state of a link, or the aggregated state of all links.
+type LinkState struct {
+	// OperationalState is one of missing, off, no-carrier, dormant,
+	// degraded-carrier, carrier, degraded, enslaved or routable.
+	OperationalState string
+	// CarrierState is one of off, no-carrier, dormant, degraded-carrier,
+	// carrier or enslaved.
+	CarrierState string
+	// AddressState, IPv4AddressState and IPv6AddressState are one of off,
+	// degraded or routable.
+	AddressState     string
+	IPv4AddressState string
+	IPv6AddressState string
+	// OnlineState is one of offline, partial or online, or empty if unknown.
+	OnlineState string
+	// AdministrativeState is one of pending, initialized, configuring,
+	// configured, unmanaged, failed or linger. It is only set for links.
+	AdministrativeState string
+}
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+func linkFromInterfaces(link []interface{}) (*Link, error) {
+	if len(link) < 3 {
+		return nil, fmt.Errorf("invalid number of link fields: %d", len(link))
+	}
+	ifindex, ok := link[0].(int32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast link field 0 to int32")
+	}
+	name, ok := link[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast link field 1 to string")
+	}
+	path, ok := link[2].(dbus.ObjectPath)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast link field 2 to ObjectPath")
+	}
+	return &Link{IfIndex: ifindex, Name: name, Path: path}, nil
+}
+
+// ListLinks returns all network interfaces known to networkd.
+func (c *Conn) ListLinks(ctx context.Context) ([]Link, error) {
+	result := make([][]interface{}, 0)
+	if err := c.object.CallWithContext(ctx, dbusInterface+".ListLinks", 0).Store(&result); err != nil {
+		return nil, err
+	}
+
+	links := []Link{}
+	for _, i := range result {
+		link, err := linkFromInterfaces(i)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+
+	return links, nil
+}
+
+// GetLinkByName returns the link with the given interface name.
+func (c *Conn) GetLinkByName(ctx context.Context, name string) (*Link, error) {
+	link := &Link{Name: name}
+	if err := c.object.CallWithContext(ctx, dbusInterface+".GetLinkByName", 0, name).Store(&link.IfIndex, &link.Path); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// GetLinkByIndex returns the link with the given interface index.
+func (c *Conn) GetLinkByIndex(ctx context.Context, ifindex int) (*Link, error) {
+	link := &Link{IfIndex: int32(ifindex)}
+	if err := c.object.CallWithContext(ctx, dbusInterface+".GetLinkByIndex", 0, int32(ifindex)).Store(&link.Name, &link.Path); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (c *Conn) getState(ctx context.Context, path dbus.ObjectPath, iface string) (*LinkState, error) {
+	var props map[string]dbus.Variant
+	obj := c.conn.Object(dbusDest, path)
+	if err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.GetAll", 0, iface).Store(&props); err != nil {
+		return nil, err
+	}
+	return linkStateFromMap(props), nil
+}
+
+func linkStateFromMap(props map[string]dbus.Variant) *LinkState {
+	s := &LinkState{}
+	s.OperationalState, _ = props["OperationalState"].Value().(string)
+	s.CarrierState, _ = props["CarrierState"].Value().(string)
+	s.AddressState, _ = props["AddressState"].Value().(string)
+	s.IPv4AddressState, _ = props["IPv4AddressState"].Value().(string)
+	s.IPv6AddressState, _ = props["IPv6AddressState"].Value().(string)
+	s.OnlineState, _ = props["OnlineState"].Value().(string)
+	s.AdministrativeState, _ = props["AdministrativeState"].Value().(string)
+	return s
+}
+
+// GetState returns the aggregated state of all links.
+func (c *Conn) GetState(ctx context.Context) (*LinkState, error) {
+	return c.getState(ctx, dbusPath, dbusInterface)
+}
+
+// GetLinkState returns the state of a link.
+func (c *Conn) GetLinkState(ctx context.Context, link *Link) (*LinkState, error) {
+	return c.getState(ctx, link.Path, dbusLinkInterface)
+}
+
+// Reload reloads the .netdev and .network files from disk and applies them
+// to the links whose configuration changed.
+func (c *Conn) Reload(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".Reload", 0).Err
+}
+
+// ReconfigureLink reapplies the configuration of a link, e.g. after its
+// .network file was changed and Reload was called.
+func (c *Conn) ReconfigureLink(ctx context.Context, ifindex int) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".ReconfigureLink", 0, int32(ifindex)).Err
+}
+
+// RenewLink asks the DHCP client of a link to renew its lease.
+func (c *Conn) RenewLink(ctx context.Context, ifindex int) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".RenewLink", 0, int32(ifindex)).Err
+}
+
+// ForceRenewLink asks the DHCP server of a link to make all its clients
+// renew their leases, by sending a FORCERENEW message.
+func (c *Conn) ForceRenewLink(ctx context.Context, ifindex int) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".ForceRenewLink", 0, int32(ifindex)).Err
+}
+
+// ReconfigureLinkByName is like ReconfigureLink, but looks up the link by its interface name.
+func (c *Conn) ReconfigureLinkByName(ctx context.Context, name string) error {
+	link, err := c.GetLinkByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	return c.ReconfigureLink(ctx, int(link.IfIndex))
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+// TestNew ensures that New() works without errors.
+func TestNew(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestListLinks(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	links, err := conn.ListLinks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range links {
+		iface, err := net.InterfaceByIndex(int(l.IfIndex))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if iface.Name != l.Name {
+			t.Fatalf("expected link name '%s' but got '%s'", iface.Name, l.Name)
+		}
+
+		byName, err := conn.GetLinkByName(ctx, l.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if byName.Path != l.Path || byName.IfIndex != l.IfIndex {
+			t.Fatalf("expected link %+v but got %+v", l, byName)
+		}
+
+		if _, err := conn.GetLinkState(ctx, &l); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := conn.GetState(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReconfigureUnknownLink(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	if err := conn.ReconfigureLink(ctx, 1<<30); err == nil {
+		t.Fatal("expected error reconfiguring unknown link")
+	}
+	if err := conn.ReconfigureLinkByName(ctx, "go-systemd-none"); err == nil {
+		t.Fatal("expected error reconfiguring unknown link")
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+
+	"github.com/coreos/go-systemd/v22/internal/jsonfields"
+)
+
+// IP is an IP address, which networkd encodes in JSON as an array of bytes.
+type IP net.IP
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (ip *IP) UnmarshalJSON(b []byte) error {
+	addr, err := bytesFromJSON(b)
+	if err != nil {
+		return err
+	}
+	if len(addr) != 0 && len(addr) != net.IPv4len && len(addr) != net.IPv6len {
+		return fmt.Errorf("invalid IP address length %d", len(addr))
+	}
+	*ip = IP(addr)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (ip IP) MarshalJSON() ([]byte, error) {
+	return bytesToJSON(ip)
+}
+
+func (ip IP) String() string {
+	return net.IP(ip).String()
+}
+
+// HardwareAddr is a hardware address, which networkd encodes in JSON as an
+// array of bytes.
+type HardwareAddr net.HardwareAddr
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *HardwareAddr) UnmarshalJSON(b []byte) error {
+	addr, err := bytesFromJSON(b)
+	if err != nil {
+		return err
+	}
+	*a = HardwareAddr(addr)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a HardwareAddr) MarshalJSON() ([]byte, error) {
+	return bytesToJSON(a)
+}
+
+func (a HardwareAddr) String() string {
+	return net.HardwareAddr(a).String()
+}
+
+func bytesFromJSON(b []byte) ([]byte, error) {
+	// Decode into []int, as encoding/json treats []byte as base64.
+	var ints []int
+	if err := json.Unmarshal(b, &ints); err != nil {
+		return nil, err
+	}
+	values := make([]byte, len(ints))
+	for i, v := range ints {
+		if v < 0 || v > 255 {
+			return nil, fmt.Errorf("invalid byte value %d", v)
+		}
+		values[i] = byte(v)
+	}
+	return values, nil
+}
+
+func bytesToJSON(b []byte) ([]byte, error) {
+	ints := make([]int, len(b))
+	for i, v := range b {
+		ints[i] = int(v)
+	}
+	return json.Marshal(ints)
+}
+
+// AddressDescription is an address configured on a link.
+type AddressDescription struct {
+	Family                int    `json:"Family"`
+	Address               IP     `json:"Address"`
+	Peer                  IP     `json:"Peer,omitempty"`
+	PrefixLength          int    `json:"PrefixLength"`
+	Scope                 int    `json:"Scope"`
+	ScopeString           string `json:"ScopeString,omitempty"`
+	Flags                 uint32 `json:"Flags"`
+	FlagsString           string `json:"FlagsString,omitempty"`
+	Label                 string `json:"Label,omitempty"`
+	PreferredLifetimeUSec uint64 `json:"PreferredLifetimeUSec,omitempty"`
+	ValidLifetimeUSec     uint64 `json:"ValidLifetimeUSec,omitempty"`
+	// ConfigSource is where the address came from, e.g. static, dhcp4,
+	// dhcp6, ndisc or foreign (not configured by networkd).
+	ConfigSource   string `json:"ConfigSource"`
+	ConfigProvider IP     `json:"ConfigProvider,omitempty"` // The server that provided the address, e.g. via DHCP
+	ConfigState    string `json:"ConfigState,omitempty"`
+}
+
+// RouteDescription is a route configured on a link.
+type RouteDescription struct {
+	Family                  int    `json:"Family"`
+	Destination             IP     `json:"Destination"`
+	DestinationPrefixLength int    `json:"DestinationPrefixLength"`
+	Gateway                 IP     `json:"Gateway,omitempty"`
+	PreferredSource         IP     `json:"PreferredSource,omitempty"`
+	Scope                   int    `json:"Scope"`
+	ScopeString             string `json:"ScopeString,omitempty"`
+	Protocol                int    `json:"Protocol"`
+	ProtocolString          string `json:"ProtocolString,omitempty"`
+	Type                    int    `json:"Type"`
+	TypeString              string `json:"TypeString,omitempty"`
+	Priority                uint32 `json:"Priority"`
+	Table                   uint32 `json:"Table"`
+	TableString             string `json:"TableString,omitempty"`
+	LifetimeUSec            uint64 `json:"LifetimeUSec,omitempty"`
+	ConfigSource            string `json:"ConfigSource"`
+	ConfigProvider          IP     `json:"ConfigProvider,omitempty"`
+	ConfigState             string `json:"ConfigState,omitempty"`
+}
+
+// DNSDescription is a DNS server configured for a link.
+type DNSDescription struct {
+	Family         int    `json:"Family"`
+	Address        IP     `json:"Address"`
+	Port           uint16 `json:"Port,omitempty"`
+	InterfaceIndex int    `json:"InterfaceIndex,omitempty"`
+	ServerName     string `json:"ServerName,omitempty"`
+	ConfigSource   string `json:"ConfigSource"`
+	ConfigProvider IP     `json:"ConfigProvider,omitempty"`
+}
+
+// DomainDescription is a search or routing domain configured for a link.
+type DomainDescription struct {
+	Domain         string `json:"Domain"`
+	ConfigSource   string `json:"ConfigSource"`
+	ConfigProvider IP     `json:"ConfigProvider,omitempty"`
+}
+
+// NTPDescription is an NTP server configured for a link.
+type NTPDescription struct {
+	Family         int    `json:"Family,omitempty"`
+	Address        IP     `json:"Address,omitempty"`
+	Server         string `json:"Server,omitempty"`
+	ConfigSource   string `json:"ConfigSource"`
+	ConfigProvider IP     `json:"ConfigProvider,omitempty"`
+}
+
+// DHCPLease describes the timers of a DHCP lease.
+type DHCPLease struct {
+	LeaseTimestampUSec uint64 `json:"LeaseTimestampUSec,omitempty"` // When the lease was acquired
+	Timeout1USec       uint64 `json:"Timeout1USec,omitempty"`       // When the lease is renewed (T1)
+	Timeout2USec       uint64 `json:"Timeout2USec,omitempty"`       // When the lease is rebound (T2)
+}
+
+// DHCPClientDescription is the state of the DHCPv4 or DHCPv6 client of a link.
+type DHCPClientDescription struct {
+	Lease *DHCPLease `json:"Lease,omitempty"`
+}
+
+// LinkDescription is the description of a link, as returned by DescribeLink.
+type LinkDescription struct {
+	Index                    int          `json:"Index"`
+	Name                     string       `json:"Name"`
+	AlternativeNames         []string     `json:"AlternativeNames,omitempty"`
+	Type                     string       `json:"Type"`
+	Kind                     string       `json:"Kind,omitempty"`
+	Driver                   string       `json:"Driver,omitempty"`
+	HardwareAddress          HardwareAddr `json:"HardwareAddress,omitempty"`
+	PermanentHardwareAddress HardwareAddr `json:"PermanentHardwareAddress,omitempty"`
+	MTU                      uint32       `json:"MTU,omitempty"`
+
+	OperationalState    string `json:"OperationalState"`
+	CarrierState        string `json:"CarrierState"`
+	AddressState        string `json:"AddressState"`
+	IPv4AddressState    string `json:"IPv4AddressState"`
+	IPv6AddressState    string `json:"IPv6AddressState"`
+	OnlineState         string `json:"OnlineState,omitempty"`
+	AdministrativeState string `json:"SetupState"` // Called SetupState in the JSON data
+
+	NetworkFile string `json:"NetworkFile,omitempty"`
+	// RequiredForOnline is whether the link is considered by
+	// systemd-networkd-wait-online, RequiredOperationalStateForOnline the
+	// minimum and maximum operational state it must be in then.
+	RequiredForOnline                 bool     `json:"RequiredForOnline"`
+	RequiredOperationalStateForOnline []string `json:"RequiredOperationalStateForOnline,omitempty"`
+	RequiredFamilyForOnline           string   `json:"RequiredFamilyForOnline,omitempty"`
+	ActivationPolicy                  string   `json:"ActivationPolicy,omitempty"`
+
+	Addresses     []AddressDescription `json:"Addresses,omitempty"`
+	Routes        []RouteDescription   `json:"Routes,omitempty"`
+	DNS           []DNSDescription     `json:"DNS,omitempty"`
+	SearchDomains []DomainDescription  `json:"SearchDomains,omitempty"`
+	RouteDomains  []DomainDescription  `json:"RouteDomains,omitempty"`
+	NTP           []NTPDescription     `json:"NTP,omitempty"`
+
+	DHCPv4Client *DHCPClientDescription `json:"DHCPv4Client,omitempty"`
+	DHCPv6Client *DHCPClientDescription `json:"DHCPv6Client,omitempty"`
+
+	// Unknown holds the fields not covered above, such as those added by
+	// newer versions of networkd.
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *LinkDescription) UnmarshalJSON(b []byte) error {
+	type plain LinkDescription
+	if err := json.Unmarshal(b, (*plain)(d)); err != nil {
+		return err
+	}
+	var err error
+	d.Unknown, err = jsonfields.Unknown(b, d)
+	return err
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d LinkDescription) MarshalJSON() ([]byte, error) {
+	type plain LinkDescription
+	b, err := json.Marshal(plain(d))
+	if err != nil {
+		return nil, err
+	}
+	return jsonfields.Merge(b, d.Unknown)
+}
+
+// Description is the description of all links, as returned by Describe.
+type Description struct {
+	Interfaces []LinkDescription `json:"Interfaces"`
+
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Description) UnmarshalJSON(b []byte) error {
+	type plain Description
+	if err := json.Unmarshal(b, (*plain)(d)); err != nil {
+		return err
+	}
+	var err error
+	d.Unknown, err = jsonfields.Unknown(b, d)
+	return err
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Description) MarshalJSON() ([]byte, error) {
+	type plain Description
+	b, err := json.Marshal(plain(d))
+	if err != nil {
+		return nil, err
+	}
+	return jsonfields.Merge(b, d.Unknown)
+}
+
+// Describe returns the description of all links managed by networkd.
+// Note: Requires systemd v250 or higher
+func (c *Conn) Describe(ctx context.Context) (*Description, error) {
+	var out string
+	if err := c.object.CallWithContext(ctx, dbusInterface+".Describe", 0).Store(&out); err != nil {
+		return nil, err
+	}
+	d := &Description{}
+	if err := json.Unmarshal([]byte(out), d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DescribeLink returns the description of a link.
+// Note: Requires systemd v250 or higher
+func (c *Conn) DescribeLink(ctx context.Context, ifindex int) (*LinkDescription, error) {
+	var out string
+	if err := c.object.CallWithContext(ctx, dbusInterface+".DescribeLink", 0, int32(ifindex)).Store(&out); err != nil {
+		return nil, err
+	}
+	d := &LinkDescription{}
+	if err := json.Unmarshal([]byte(out), d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const linkDescriptionJSON = `{
+	"Index": 2,
+	"Name": "eth0",
+	"Type": "ether",
+	"Driver": "virtio_net",
+	"HardwareAddress": [82, 84, 0, 18, 52, 86],
+	"MTU": 1500,
+	"OperationalState": "routable",
+	"CarrierState": "carrier",
+	"AddressState": "routable",
+	"IPv4AddressState": "routable",
+	"IPv6AddressState": "degraded",
+	"OnlineState": "online",
+	"SetupState": "configured",
+	"NetworkFile": "/etc/systemd/network/20-wired.network",
+	"RequiredForOnline": true,
+	"RequiredOperationalStateForOnline": ["degraded", "routable"],
+	"Addresses": [
+		{"Family": 2, "Address": [10, 0, 2, 15], "PrefixLength": 24, "Scope": 0, "Flags": 0, "ConfigSource": "dhcp4", "ConfigProvider": [10, 0, 2, 2]}
+	],
+	"Routes": [
+		{"Family": 2, "Destination": [0, 0, 0, 0], "DestinationPrefixLength": 0, "Gateway": [10, 0, 2, 2], "Scope": 0, "Protocol": 16, "Type": 1, "Priority": 1024, "Table": 254, "ConfigSource": "dhcp4"}
+	],
+	"DNS": [
+		{"Family": 2, "Address": [10, 0, 2, 3], "ConfigSource": "dhcp4"}
+	],
+	"SearchDomains": [
+		{"Domain": "example.com", "ConfigSource": "dhcp4"}
+	],
+	"DHCPv4Client": {"Lease": {"LeaseTimestampUSec": 1700000000000000, "Timeout1USec": 1700000043200000}},
+	"SomeFutureField": true
+}`
+
+func TestLinkDescription(t *testing.T) {
+	var d LinkDescription
+	if err := json.Unmarshal([]byte(linkDescriptionJSON), &d); err != nil {
+		t.Fatal(err)
+	}
+
+	if d.Index != 2 || d.Name != "eth0" || d.HardwareAddress.String() != "52:54:00:12:34:56" {
+		t.Fatalf("unexpected link %+v", d)
+	}
+	if len(d.Addresses) != 1 || d.Addresses[0].Address.String() != "10.0.2.15" || d.Addresses[0].ConfigProvider.String() != "10.0.2.2" {
+		t.Fatalf("unexpected addresses %+v", d.Addresses)
+	}
+	if len(d.Routes) != 1 || d.Routes[0].Gateway.String() != "10.0.2.2" || d.Routes[0].Table != 254 {
+		t.Fatalf("unexpected routes %+v", d.Routes)
+	}
+	if len(d.DNS) != 1 || d.DNS[0].Address.String() != "10.0.2.3" {
+		t.Fatalf("unexpected DNS servers %+v", d.DNS)
+	}
+	if d.DHCPv4Client == nil || d.DHCPv4Client.Lease == nil || d.DHCPv4Client.Lease.Timeout1USec != 1700000043200000 {
+		t.Fatalf("unexpected DHCPv4 client %+v", d.DHCPv4Client)
+	}
+	if len(d.RequiredOperationalStateForOnline) != 2 {
+		t.Fatalf("unexpected required operational state %v", d.RequiredOperationalStateForOnline)
+	}
+
+	out, err := json.Marshal(d.Addresses[0].Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "[10,0,2,15]" {
+		t.Fatalf("unexpected address encoding %s", out)
+	}
+
+	if len(d.Unknown) != 1 || string(d.Unknown["SomeFutureField"]) != "true" {
+		t.Fatalf("unexpected unknown fields %v", d.Unknown)
+	}
+	out, err = json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again LinkDescription
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatal(err)
+	}
+	if string(again.Unknown["SomeFutureField"]) != "true" || again.HardwareAddress.String() != "52:54:00:12:34:56" || again.AdministrativeState != "configured" {
+		t.Fatalf("unexpected link after round trip %+v", again)
+	}
+}
+
+func TestIPUnmarshalJSON(t *testing.T) {
+	var ip IP
+	if err := json.Unmarshal([]byte("[1, 2, 3]"), &ip); err == nil {
+		t.Fatal("expected error for 3-byte address")
+	}
+	if err := json.Unmarshal([]byte("[1, 2, 3, 256]"), &ip); err == nil {
+		t.Fatal("expected error for out of range byte")
+	}
+	if err := json.Unmarshal([]byte("[0,0,0,0,0,0,0,0,0,0,0,0,0,0,0,1]"), &ip); err != nil {
+		t.Fatal(err)
+	}
+	if ip.String() != "::1" {
+		t.Fatalf("unexpected address %s", ip)
+	}
+}
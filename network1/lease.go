@@ -0,0 +1,260 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// leaseDir is where networkd saves the DHCPv4 leases of its links.
+var leaseDir = "/run/systemd/netif/leases"
+
+// DHCPRoute is a classless static route handed out via DHCP.
+type DHCPRoute struct {
+	Destination *net.IPNet
+	Gateway     net.IP
+}
+
+// DHCPLeaseFile is a DHCPv4 lease as saved by networkd.
+type DHCPLeaseFile struct {
+	Address       net.IP
+	Netmask       net.IP
+	Router        []net.IP
+	ServerAddress net.IP // The DHCP server that handed out the lease
+	NextServer    net.IP
+	Broadcast     net.IP
+	MTU           int
+	T1            time.Duration // The time after which the lease is renewed
+	T2            time.Duration // The time after which the lease is rebound
+	Lifetime      time.Duration
+	DNS           []net.IP
+	NTP           []net.IP
+	DomainName    string
+	SearchDomains []string
+	Hostname      string
+	RootPath      string
+	Routes        []DHCPRoute
+	ClientID      []byte
+	Timezone      string
+	// VendorSpecific is the raw content of option 43.
+	VendorSpecific []byte
+	// Options holds the raw content of the private options 224 to 254 by option number.
+	Options map[int][]byte
+	// Fields holds all fields of the lease file, including unparsed ones.
+	Fields map[string]string
+}
+
+// LinkDHCPLease is the DHCPv4 lease of a link, combining the lease saved by
+// networkd with the timers reported by its Describe data.
+type LinkDHCPLease struct {
+	*DHCPLeaseFile
+	Timers *DHCPLease // nil if networkd reports no lease timers
+}
+
+// GetDHCPv4Lease returns the current DHCPv4 lease of a link.
+func (c *Conn) GetDHCPv4Lease(ctx context.Context, ifindex int) (*LinkDHCPLease, error) {
+	lease, err := ReadDHCPLease(ifindex)
+	if err != nil {
+		return nil, err
+	}
+	link, err := c.DescribeLink(ctx, ifindex)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &LinkDHCPLease{DHCPLeaseFile: lease}
+	if link.DHCPv4Client != nil {
+		l.Timers = link.DHCPv4Client.Lease
+	}
+	return l, nil
+}
+
+// GetDHCPv6Lease returns the timers of the current DHCPv6 lease of a link, or
+// nil if the link has none.
+func (c *Conn) GetDHCPv6Lease(ctx context.Context, ifindex int) (*DHCPLease, error) {
+	link, err := c.DescribeLink(ctx, ifindex)
+	if err != nil {
+		return nil, err
+	}
+	if link.DHCPv6Client == nil {
+		return nil, nil
+	}
+	return link.DHCPv6Client.Lease, nil
+}
+
+// ReadDHCPLease reads the DHCPv4 lease networkd saved for a link. networkd
+// does not save DHCPv6 leases; the timers of those are part of the
+// DHCPv6Client field returned by DescribeLink.
+func ReadDHCPLease(ifindex int) (*DHCPLeaseFile, error) {
+	f, err := os.Open(filepath.Join(leaseDir, strconv.Itoa(ifindex)))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseDHCPLease(f)
+}
+
+// ParseDHCPLease parses a DHCPv4 lease in the format saved by networkd
+// below /run/systemd/netif/leases/.
+func ParseDHCPLease(r io.Reader) (*DHCPLeaseFile, error) {
+	lease := &DHCPLeaseFile{
+		Options: map[int][]byte{},
+		Fields:  map[string]string{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid lease line %q", line)
+		}
+		key, value := line[:i], line[i+1:]
+		lease.Fields[key] = value
+
+		if err := lease.setField(key, value); err != nil {
+			return nil, fmt.Errorf("invalid lease field %s: %v", key, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lease, nil
+}
+
+func (l *DHCPLeaseFile) setField(key, value string) error {
+	var err error
+	switch key {
+	case "ADDRESS":
+		l.Address, err = parseIP(value)
+	case "NETMASK":
+		l.Netmask, err = parseIP(value)
+	case "ROUTER":
+		l.Router, err = parseIPs(value)
+	case "SERVER_ADDRESS":
+		l.ServerAddress, err = parseIP(value)
+	case "NEXT_SERVER":
+		l.NextServer, err = parseIP(value)
+	case "BROADCAST":
+		l.Broadcast, err = parseIP(value)
+	case "MTU":
+		l.MTU, err = strconv.Atoi(value)
+	case "T1":
+		l.T1, err = parseSeconds(value)
+	case "T2":
+		l.T2, err = parseSeconds(value)
+	case "LIFETIME":
+		l.Lifetime, err = parseSeconds(value)
+	case "DNS":
+		l.DNS, err = parseIPs(value)
+	case "NTP":
+		l.NTP, err = parseIPs(value)
+	case "DOMAINNAME":
+		l.DomainName = value
+	case "DOMAIN_SEARCH_LIST":
+		l.SearchDomains = strings.Fields(value)
+	case "HOSTNAME":
+		l.Hostname = value
+	case "ROOT_PATH":
+		l.RootPath = value
+	case "ROUTES", "STATIC_ROUTES", "CLASSLESS_ROUTES":
+		var routes []DHCPRoute
+		routes, err = parseRoutes(value)
+		l.Routes = append(l.Routes, routes...)
+	case "CLIENTID":
+		l.ClientID, err = hex.DecodeString(value)
+	case "TIMEZONE":
+		l.Timezone = value
+	case "VENDOR_SPECIFIC":
+		l.VendorSpecific, err = hex.DecodeString(value)
+	default:
+		if !strings.HasPrefix(key, "OPTION_") {
+			return nil
+		}
+		var option int
+		option, err = strconv.Atoi(strings.TrimPrefix(key, "OPTION_"))
+		if err != nil {
+			return err
+		}
+		l.Options[option], err = hex.DecodeString(value)
+	}
+	return err
+}
+
+func parseIP(s string) (net.IP, error) {
+	if s == "" {
+		return nil, nil
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", s)
+	}
+	return ip, nil
+}
+
+func parseIPs(s string) ([]net.IP, error) {
+	var ips []net.IP
+	for _, f := range strings.Fields(s) {
+		ip, err := parseIP(f)
+		if err != nil {
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+func parseSeconds(s string) (time.Duration, error) {
+	sec, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(sec) * time.Second, nil
+}
+
+// parseRoutes parses space separated routes of the form destination/prefix,gateway.
+func parseRoutes(s string) ([]DHCPRoute, error) {
+	var routes []DHCPRoute
+	for _, f := range strings.Fields(s) {
+		i := strings.Index(f, ",")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid route %q", f)
+		}
+		_, dst, err := net.ParseCIDR(f[:i])
+		if err != nil {
+			return nil, err
+		}
+		gw, err := parseIP(f[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, DHCPRoute{Destination: dst, Gateway: gw})
+	}
+	return routes, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const leaseFile = `# This is private data. Do not parse.
+ADDRESS=10.0.2.15
+NETMASK=255.255.255.0
+ROUTER=10.0.2.2
+SERVER_ADDRESS=10.0.2.2
+LIFETIME=86400
+T1=43200
+T2=75600
+DNS=10.0.2.3 10.0.2.4
+DOMAINNAME=example.com
+HOSTNAME=node1
+ROUTES=192.168.100.0/24,10.0.2.1
+CLIENTID=ff5254001234560002
+VENDOR_SPECIFIC=0104c0a80001
+OPTION_224=deadbeef
+UNKNOWN_FIELD=kept
+`
+
+func TestParseDHCPLease(t *testing.T) {
+	lease, err := ParseDHCPLease(strings.NewReader(leaseFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lease.Address.String() != "10.0.2.15" || lease.Netmask.String() != "255.255.255.0" {
+		t.Fatalf("unexpected address %s/%s", lease.Address, lease.Netmask)
+	}
+	if len(lease.Router) != 1 || lease.Router[0].String() != "10.0.2.2" {
+		t.Fatalf("unexpected router %v", lease.Router)
+	}
+	if lease.Lifetime != 24*time.Hour || lease.T1 != 12*time.Hour || lease.T2 != 21*time.Hour {
+		t.Fatalf("unexpected timers %v %v %v", lease.Lifetime, lease.T1, lease.T2)
+	}
+	if len(lease.DNS) != 2 || lease.DNS[1].String() != "10.0.2.4" {
+		t.Fatalf("unexpected DNS servers %v", lease.DNS)
+	}
+	if lease.DomainName != "example.com" || lease.Hostname != "node1" {
+		t.Fatalf("unexpected names %q %q", lease.DomainName, lease.Hostname)
+	}
+	if len(lease.Routes) != 1 || lease.Routes[0].Destination.String() != "192.168.100.0/24" || lease.Routes[0].Gateway.String() != "10.0.2.1" {
+		t.Fatalf("unexpected routes %+v", lease.Routes)
+	}
+	if !bytes.Equal(lease.VendorSpecific, []byte{1, 4, 192, 168, 0, 1}) {
+		t.Fatalf("unexpected vendor specific data %x", lease.VendorSpecific)
+	}
+	if !bytes.Equal(lease.Options[224], []byte{0xde, 0xad, 0xbe, 0xef}) {
+		t.Fatalf("unexpected option 224 %x", lease.Options[224])
+	}
+	if lease.Fields["UNKNOWN_FIELD"] != "kept" {
+		t.Fatalf("unknown field not retained: %v", lease.Fields)
+	}
+}
+
+func TestParseDHCPLeaseInvalid(t *testing.T) {
+	for _, s := range []string{
+		"ADDRESS=10.0.2\n",
+		"LIFETIME=forever\n",
+		"OPTION_224=xyz\n",
+		"garbage\n",
+	} {
+		if _, err := ParseDHCPLease(strings.NewReader(s)); err == nil {
+			t.Errorf("expected error parsing %q", s)
+		}
+	}
+}
+
+func TestReadDHCPLease(t *testing.T) {
+	dir, err := ioutil.TempDir("", "network1-lease")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "2"), []byte(leaseFile), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(orig string) { leaseDir = orig }(leaseDir)
+	leaseDir = dir
+
+	lease, err := ReadDHCPLease(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lease.Address.String() != "10.0.2.15" {
+		t.Fatalf("unexpected address %s", lease.Address)
+	}
+	if _, err := ReadDHCPLease(3); !os.IsNotExist(err) {
+		t.Fatalf("expected not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// operationalStates lists the operational states in ascending order, as
+// compared by systemd-networkd-wait-online.
+var operationalStates = []string{"missing", "off", "no-carrier", "dormant", "degraded-carrier", "carrier", "degraded", "enslaved", "routable"}
+
+// addressStates lists the address states in ascending order.
+var addressStates = []string{"off", "degraded", "routable"}
+
+func stateIndex(states []string, state string) int {
+	for i, s := range states {
+		if s == state {
+			return i
+		}
+	}
+	return -1
+}
+
+// LinkStatus is the status of a link, along with whether it is online
+// according to the rules of systemd-networkd-wait-online.
+type LinkStatus struct {
+	LinkDescription
+	// Ignored is set for links not considered when determining whether the
+	// system is online, because they are unmanaged or not RequiredForOnline.
+	Ignored bool
+	// Online is whether the link satisfies its RequiredForOnline conditions.
+	Online bool
+	// Reason explains why a considered link is not online.
+	Reason string
+}
+
+// Status is the state of the network as seen by networkd.
+type Status struct {
+	LinkState
+	Links []LinkStatus
+	// Online is whether systemd-networkd-wait-online would consider the
+	// network online: at least one link is online and all other links
+	// required for online are online too.
+	Online bool
+	// AnyOnline is whether at least one link required for online is online,
+	// as checked by systemd-networkd-wait-online --any.
+	AnyOnline bool
+
+	// DNS, SearchDomains and NTP are the servers and domains of all links.
+	DNS           []net.IP
+	SearchDomains []string
+	NTP           []string
+}
+
+// Status returns the state of all links, the addresses, routes, DNS and NTP
+// servers configured on them and whether the network is online.
+// Note: Requires systemd v250 or higher
+func (c *Conn) Status(ctx context.Context) (*Status, error) {
+	state, err := c.GetState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.Describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return statusFromDescription(state, d), nil
+}
+
+func statusFromDescription(state *LinkState, d *Description) *Status {
+	s := &Status{LinkState: *state}
+
+	oneOnline, allOnline := false, true
+	seenDNS := map[string]bool{}
+	seenDomains := map[string]bool{}
+	seenNTP := map[string]bool{}
+	for _, l := range d.Interfaces {
+		ls := LinkStatus{LinkDescription: l}
+		ls.Ignored, ls.Online, ls.Reason = linkOnline(&l)
+		if !ls.Ignored {
+			if ls.Online {
+				oneOnline = true
+			} else {
+				allOnline = false
+			}
+		}
+		s.Links = append(s.Links, ls)
+
+		for _, dns := range l.DNS {
+			if ip := net.IP(dns.Address); !seenDNS[ip.String()] {
+				seenDNS[ip.String()] = true
+				s.DNS = append(s.DNS, ip)
+			}
+		}
+		for _, domain := range l.SearchDomains {
+			if !seenDomains[domain.Domain] {
+				seenDomains[domain.Domain] = true
+				s.SearchDomains = append(s.SearchDomains, domain.Domain)
+			}
+		}
+		for _, ntp := range l.NTP {
+			server := ntp.Server
+			if server == "" {
+				server = net.IP(ntp.Address).String()
+			}
+			if !seenNTP[server] {
+				seenNTP[server] = true
+				s.NTP = append(s.NTP, server)
+			}
+		}
+	}
+	s.Online = oneOnline && allOnline
+	s.AnyOnline = oneOnline
+
+	return s
+}
+
+// linkOnline implements the checks systemd-networkd-wait-online does for a
+// link, returning whether the link is ignored, whether it is online and if
+// not, why.
+func linkOnline(l *LinkDescription) (ignored, online bool, reason string) {
+	if !l.RequiredForOnline {
+		return true, false, ""
+	}
+
+	switch l.AdministrativeState {
+	case "", "pending", "initialized":
+		return false, false, "not yet processed by udev or networkd"
+	case "unmanaged":
+		return true, false, ""
+	case "configuring":
+		return false, false, "being configured by networkd"
+	}
+
+	min, max := "degraded", "routable"
+	if len(l.RequiredOperationalStateForOnline) > 0 {
+		min = l.RequiredOperationalStateForOnline[0]
+		max = min
+		if len(l.RequiredOperationalStateForOnline) > 1 {
+			max = l.RequiredOperationalStateForOnline[1]
+		}
+	}
+	current := stateIndex(operationalStates, l.OperationalState)
+	if current < stateIndex(operationalStates, min) || current > stateIndex(operationalStates, max) {
+		return false, false, fmt.Sprintf("operational state %s is not in range [%s, %s]", l.OperationalState, min, max)
+	}
+
+	// Links required to be routable need routable addresses of the required
+	// families, otherwise degraded ones suffice.
+	required := "degraded"
+	if stateIndex(operationalStates, min) >= stateIndex(operationalStates, "routable") {
+		required = "routable"
+	}
+	family := l.RequiredFamilyForOnline
+	if (family == "ipv4" || family == "both") && stateIndex(addressStates, l.IPv4AddressState) < stateIndex(addressStates, required) {
+		return false, false, fmt.Sprintf("IPv4 address state %s is not %s", l.IPv4AddressState, required)
+	}
+	if (family == "ipv6" || family == "both") && stateIndex(addressStates, l.IPv6AddressState) < stateIndex(addressStates, required) {
+		return false, false, fmt.Sprintf("IPv6 address state %s is not %s", l.IPv6AddressState, required)
+	}
+
+	return false, true, ""
+}
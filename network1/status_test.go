@@ -0,0 +1,110 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network1
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestLinkOnline(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		link    LinkDescription
+		ignored bool
+		online  bool
+	}{
+		{"not required", LinkDescription{OperationalState: "off"}, true, false},
+		{"unmanaged", LinkDescription{RequiredForOnline: true, AdministrativeState: "unmanaged"}, true, false},
+		{"configuring", LinkDescription{RequiredForOnline: true, AdministrativeState: "configuring", OperationalState: "routable"}, false, false},
+		{"default range", LinkDescription{RequiredForOnline: true, AdministrativeState: "configured", OperationalState: "degraded"}, false, true},
+		{"below default range", LinkDescription{RequiredForOnline: true, AdministrativeState: "configured", OperationalState: "carrier"}, false, false},
+		{"custom range", LinkDescription{
+			RequiredForOnline:                 true,
+			AdministrativeState:               "configured",
+			OperationalState:                  "carrier",
+			RequiredOperationalStateForOnline: []string{"carrier"},
+		}, false, true},
+		{"ipv4 degraded", LinkDescription{
+			RequiredForOnline:       true,
+			AdministrativeState:     "configured",
+			OperationalState:        "routable",
+			IPv4AddressState:        "off",
+			IPv6AddressState:        "routable",
+			RequiredFamilyForOnline: "ipv4",
+		}, false, false},
+		{"both routable", LinkDescription{
+			RequiredForOnline:                 true,
+			AdministrativeState:               "configured",
+			OperationalState:                  "routable",
+			IPv4AddressState:                  "routable",
+			IPv6AddressState:                  "degraded",
+			RequiredFamilyForOnline:           "both",
+			RequiredOperationalStateForOnline: []string{"routable", "routable"},
+		}, false, false},
+	} {
+		ignored, online, reason := linkOnline(&tt.link)
+		if ignored != tt.ignored || online != tt.online {
+			t.Errorf("%s: expected ignored=%v online=%v, got ignored=%v online=%v (%s)", tt.name, tt.ignored, tt.online, ignored, online, reason)
+		}
+		if !ignored && !online && reason == "" {
+			t.Errorf("%s: missing reason", tt.name)
+		}
+	}
+}
+
+func TestStatusFromDescription(t *testing.T) {
+	online := LinkDescription{
+		Name:                "eth0",
+		RequiredForOnline:   true,
+		AdministrativeState: "configured",
+		OperationalState:    "routable",
+		DNS:                 []DNSDescription{{Family: 2, Address: IP{10, 0, 0, 1}}},
+		SearchDomains:       []DomainDescription{{Domain: "example.com"}},
+		NTP:                 []NTPDescription{{Server: "ntp.example.com"}},
+	}
+	offline := LinkDescription{
+		Name:                "eth1",
+		RequiredForOnline:   true,
+		AdministrativeState: "configured",
+		OperationalState:    "no-carrier",
+		SearchDomains:       []DomainDescription{{Domain: "example.com"}},
+	}
+	lo := LinkDescription{Name: "lo", AdministrativeState: "unmanaged", OperationalState: "carrier"}
+
+	s := statusFromDescription(&LinkState{OperationalState: "routable"}, &Description{Interfaces: []LinkDescription{lo, online, offline}})
+	if s.Online || !s.AnyOnline {
+		t.Fatalf("expected partially online status, got online=%v any=%v", s.Online, s.AnyOnline)
+	}
+	if len(s.Links) != 3 || !s.Links[0].Ignored || !s.Links[1].Online || s.Links[2].Online {
+		t.Fatalf("unexpected link status %+v", s.Links)
+	}
+	if !reflect.DeepEqual(s.DNS, []net.IP{net.IP{10, 0, 0, 1}}) {
+		t.Fatalf("unexpected DNS servers %v", s.DNS)
+	}
+	if !reflect.DeepEqual(s.SearchDomains, []string{"example.com"}) || !reflect.DeepEqual(s.NTP, []string{"ntp.example.com"}) {
+		t.Fatalf("unexpected domains %v or NTP servers %v", s.SearchDomains, s.NTP)
+	}
+
+	s = statusFromDescription(&LinkState{}, &Description{Interfaces: []LinkDescription{lo, online}})
+	if !s.Online {
+		t.Fatal("expected online status")
+	}
+	s = statusFromDescription(&LinkState{}, &Description{Interfaces: []LinkDescription{lo}})
+	if s.Online || s.AnyOnline {
+		t.Fatal("expected offline status without any required links")
+	}
+}
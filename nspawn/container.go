@@ -0,0 +1,132 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nspawn
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/machine1"
+)
+
+// Container is a running systemd-nspawn container.
+type Container struct {
+	// Name is the machine name of the container.
+	Name string
+	Cmd  *exec.Cmd
+	// Stdin and Stdout are connected to the console of the container for
+	// ConsolePipe and ConsoleAutopipe, if the Stdin and Stdout of Cmd were
+	// not set by the caller.
+	Stdin  io.WriteCloser
+	Stdout io.ReadCloser
+}
+
+// Command returns the command launching a container with the given
+// configuration, which is killed if ctx is done before it exits.
+func Command(ctx context.Context, c *Config) (*exec.Cmd, error) {
+	args, err := c.Args()
+	if err != nil {
+		return nil, err
+	}
+	return exec.CommandContext(ctx, Binary, args...), nil
+}
+
+// Start launches a container with the given configuration. It is killed if
+// ctx is done before it exits, Poweroff shuts it down orderly instead.
+func Start(ctx context.Context, c *Config) (*Container, error) {
+	cmd, err := Command(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	ct := &Container{Name: c.MachineName(), Cmd: cmd}
+	if c.Console == ConsolePipe || c.Console == ConsoleAutopipe {
+		if ct.Stdin, err = cmd.StdinPipe(); err != nil {
+			return nil, err
+		}
+		if ct.Stdout, err = cmd.StdoutPipe(); err != nil {
+			return nil, err
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+// Wait waits for the container to exit, see exec.Cmd.Wait.
+func (c *Container) Wait() error {
+	return c.Cmd.Wait()
+}
+
+// WaitRunning waits until systemd-machined reports the container as
+// running, which with NotifyReady means its payload finished starting up.
+func (c *Container) WaitRunning(ctx context.Context, m *machine1.Conn) error {
+	t := time.NewTicker(100 * time.Millisecond)
+	defer t.Stop()
+	for {
+		if state, err := m.GetMachineState(c.Name); err == nil && state == "running" {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Poweroff asks the container to shut down orderly, which systemd-nspawn
+// forwards to the init system of booted containers, and returns without
+// waiting for it.
+func (c *Container) Poweroff() error {
+	if c.Cmd.Process == nil {
+		return errors.New("container is not running")
+	}
+	return c.Cmd.Process.Signal(syscall.SIGTERM)
+}
+
+// Terminate terminates the container through systemd-machined, killing all
+// its processes.
+func (c *Container) Terminate(m *machine1.Conn) error {
+	return m.TerminateMachine(c.Name)
+}
+
+// Kill sends sig to the processes of the container, who being "leader" for
+// its init process or "all".
+func (c *Container) Kill(m *machine1.Conn, who string, sig syscall.Signal) error {
+	return m.KillMachine(c.Name, who, sig)
+}
+
+// OpenConsole allocates a pseudo terminal inside the container without
+// starting anything on it.
+func (c *Container) OpenConsole(m *machine1.Conn) (*machine1.PTY, error) {
+	return m.OpenMachinePTY(c.Name)
+}
+
+// Login allocates a pseudo terminal inside a booted container and starts a
+// login prompt on it.
+func (c *Container) Login(m *machine1.Conn) (*machine1.PTY, error) {
+	return m.OpenMachineLogin(c.Name)
+}
+
+// Shell runs path with args as user on a pseudo terminal inside the
+// container, see machine1.Conn.OpenMachineShell.
+func (c *Container) Shell(m *machine1.Conn, user, path string, args, environment []string) (*machine1.PTY, error) {
+	return m.OpenMachineShell(c.Name, user, path, args, environment)
+}
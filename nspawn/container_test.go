@@ -0,0 +1,50 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nspawn
+
+import (
+	"context"
+	"io/ioutil"
+	"os/exec"
+	"testing"
+)
+
+func TestStart(t *testing.T) {
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer func(b string) { Binary = b }(Binary)
+	Binary = echo
+
+	c, err := Start(context.Background(), &Config{Directory: "/var/lib/machines/web", Console: ConsolePipe, Command: []string{"/bin/true"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "web" {
+		t.Errorf("unexpected name %q", c.Name)
+	}
+	c.Stdin.Close()
+	b, err := ioutil.ReadAll(c.Stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if s := string(b); s != "--directory=/var/lib/machines/web --console=pipe -- /bin/true\n" {
+		t.Errorf("unexpected arguments %q", s)
+	}
+}
@@ -0,0 +1,368 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nspawn configures and launches systemd-nspawn containers and
+// manages their lifecycle through systemd-machined.  See
+// https://www.freedesktop.org/software/systemd/man/systemd-nspawn.html
+package nspawn
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Binary is the systemd-nspawn binary Start runs, a variable so it can be
+// changed by tests.
+var Binary = "systemd-nspawn"
+
+// ConsoleMode selects how the console of the container is connected, see
+// --console= in systemd-nspawn(1).
+type ConsoleMode string
+
+const (
+	ConsoleDefault     ConsoleMode = ""
+	ConsoleInteractive ConsoleMode = "interactive"
+	ConsoleReadOnly    ConsoleMode = "read-only"
+	ConsolePassive     ConsoleMode = "passive"
+	ConsolePipe        ConsoleMode = "pipe"
+	ConsoleAutopipe    ConsoleMode = "autopipe"
+)
+
+// Bind is a bind mount of a host path into the container.
+type Bind struct {
+	Source      string
+	Destination string // Defaults to Source
+	ReadOnly    bool
+	Options     string // Comma separated, e.g. "norbind" or "idmap"
+}
+
+func (b Bind) arg() string {
+	s := escapeColon(b.Source)
+	if b.Destination != "" || b.Options != "" {
+		s += ":" + escapeColon(b.Destination)
+	}
+	if b.Options != "" {
+		s += ":" + b.Options
+	}
+	return s
+}
+
+// escapeColon escapes the backslashes and colons in s with a backslash.
+func escapeColon(s string) string {
+	return strings.Replace(strings.Replace(s, `\`, `\\`, -1), ":", `\:`, -1)
+}
+
+// Port is a port forwarded from the host to the container, which requires a
+// private network.
+type Port struct {
+	Protocol  string // "tcp" or "udp", defaults to "tcp"
+	Host      uint16
+	Container uint16 // Defaults to Host
+}
+
+func (p Port) arg() string {
+	proto := p.Protocol
+	if proto == "" {
+		proto = "tcp"
+	}
+	c := p.Container
+	if c == 0 {
+		c = p.Host
+	}
+	return fmt.Sprintf("%s:%d:%d", proto, p.Host, c)
+}
+
+// Network configures the networking of a container. The zero value shares
+// the host's network.
+type Network struct {
+	Private         bool     // A private network with only a loopback device
+	VirtualEthernet bool     // A veth pair between host and container, implies Private
+	Bridge          string   // Adds the host side of the veth pair to this bridge
+	Zone            string   // Adds the host side of the veth pair to the bridge of this zone
+	Interfaces      []string // Host interfaces moved into the container
+	MACVLAN         []string // Host interfaces a MACVLAN interface is created for
+	IPVLAN          []string // Host interfaces an IPVLAN interface is created for
+	Ports           []Port
+}
+
+// Config describes a container to launch.
+type Config struct {
+	// Machine is the machine name the container registers with, by
+	// default the base name of Directory or Image.
+	Machine string
+	// Directory or Image is the root of the container, a directory
+	// tree or a disk image.
+	Directory string
+	Image     string
+	// Template is a directory the root is created from as snapshot if
+	// Directory does not exist yet.
+	Template string
+	// Ephemeral runs the container on a snapshot removed when it exits.
+	Ephemeral bool
+	ReadOnly  bool
+	Volatile  string // "yes", "state" or "overlay"
+
+	// Boot runs the init system of the container, with Command as its
+	// arguments. Otherwise Command is run, or a shell if it is empty.
+	Boot             bool
+	Command          []string
+	AsPID2           bool // Runs Command under a minimal init process
+	User             string
+	WorkingDirectory string
+	Environment      []string // KEY=VALUE pairs
+	Hostname         string
+	UUID             string
+
+	Bind        []Bind
+	TemporaryFS []string // Paths to mount a tmpfs on, optionally followed by ":" and mount options
+
+	Network Network
+
+	Capabilities     []string // Added to the default set, "all" for all
+	DropCapabilities []string
+	NoNewPrivileges  bool
+	SystemCallFilter []string          // Allowed system calls and groups, "~"-prefixed to deny
+	Limits           map[string]string // Resource limits by name, e.g. "NOFILE": "1024:4096"
+	OOMScoreAdjust   *int
+	CPUAffinity      string
+	PrivateUsers     string // "yes", "no", "pick", "identity" or UID ranges
+
+	ResolvConf  string // --resolv-conf= mode, e.g. "bind-host"
+	Timezone    string // --timezone= mode, e.g. "bind"
+	LinkJournal string // --link-journal= mode, e.g. "try-guest"
+
+	// NotifyReady waits for the payload to send READY=1 instead of
+	// reporting readiness once it started.
+	NotifyReady bool
+	KillSignal  string // e.g. "SIGRTMIN+3"
+	// NoRegister does not register the container with systemd-machined,
+	// which the lifecycle methods of Container need.
+	NoRegister bool
+	Slice      string
+	Properties []string // Unit properties of the scope, e.g. "MemoryMax=1G"
+
+	Console ConsoleMode
+	// Settings is the --settings= mode for .nspawn files, e.g. "no" to
+	// ignore them.
+	Settings string
+	Quiet    bool
+	// ExtraArgs are passed to systemd-nspawn before the command.
+	ExtraArgs []string
+}
+
+// MachineName returns the name the container registers with.
+func (c *Config) MachineName() string {
+	if c.Machine != "" {
+		return c.Machine
+	}
+	root := c.Directory
+	if root == "" {
+		root = strings.TrimSuffix(c.Image, ".raw")
+	}
+	return filepath.Base(filepath.Clean(root))
+}
+
+// ValidMachineName returns whether name is a valid machine name, which
+// follows the rules for host names.
+func ValidMachineName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (c *Config) validate() error {
+	if (c.Directory == "") == (c.Image == "") {
+		return errors.New("exactly one of Directory and Image must be set")
+	}
+	if c.Template != "" && c.Directory == "" {
+		return errors.New("Template requires Directory")
+	}
+	if c.Ephemeral && c.Template != "" {
+		return errors.New("Ephemeral and Template cannot be combined")
+	}
+	if c.Boot && c.AsPID2 {
+		return errors.New("Boot and AsPID2 cannot be combined")
+	}
+	if !ValidMachineName(c.MachineName()) {
+		return fmt.Errorf("invalid machine name %q", c.MachineName())
+	}
+	for _, b := range c.Bind {
+		if !filepath.IsAbs(b.Source) && !strings.HasPrefix(b.Source, "+") {
+			return fmt.Errorf("bind mount source %q is not absolute", b.Source)
+		}
+		if b.Destination != "" && !filepath.IsAbs(b.Destination) {
+			return fmt.Errorf("bind mount destination %q is not absolute", b.Destination)
+		}
+	}
+	for _, e := range c.Environment {
+		if i := strings.IndexByte(e, '='); i <= 0 {
+			return fmt.Errorf("invalid environment variable %q", e)
+		}
+	}
+	n := c.Network
+	private := n.Private || n.VirtualEthernet || n.Bridge != "" || n.Zone != "" ||
+		len(n.Interfaces) > 0 || len(n.MACVLAN) > 0 || len(n.IPVLAN) > 0
+	for _, p := range n.Ports {
+		if p.Protocol != "" && p.Protocol != "tcp" && p.Protocol != "udp" {
+			return fmt.Errorf("invalid port protocol %q", p.Protocol)
+		}
+		if p.Host == 0 {
+			return errors.New("port forward without host port")
+		}
+		if !private {
+			return errors.New("port forwards require a private network")
+		}
+	}
+	switch c.Console {
+	case ConsoleDefault, ConsoleInteractive, ConsoleReadOnly, ConsolePassive, ConsolePipe, ConsoleAutopipe:
+	default:
+		return fmt.Errorf("invalid console mode %q", c.Console)
+	}
+	return nil
+}
+
+// Args returns the systemd-nspawn arguments for the configuration.
+func (c *Config) Args() ([]string, error) {
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+
+	var args []string
+	add := func(flag, value string) {
+		if value != "" {
+			args = append(args, flag+"="+value)
+		}
+	}
+	flag := func(flag string, set bool) {
+		if set {
+			args = append(args, flag)
+		}
+	}
+
+	add("--directory", c.Directory)
+	add("--image", c.Image)
+	add("--template", c.Template)
+	flag("--ephemeral", c.Ephemeral)
+	flag("--read-only", c.ReadOnly)
+	add("--volatile", c.Volatile)
+	add("--machine", c.Machine)
+	add("--uuid", c.UUID)
+	add("--hostname", c.Hostname)
+	flag("--boot", c.Boot)
+	flag("--as-pid2", c.AsPID2)
+	add("--user", c.User)
+	add("--chdir", c.WorkingDirectory)
+	for _, e := range c.Environment {
+		add("--setenv", e)
+	}
+
+	for _, b := range c.Bind {
+		if b.ReadOnly {
+			add("--bind-ro", b.arg())
+		} else {
+			add("--bind", b.arg())
+		}
+	}
+	for _, t := range c.TemporaryFS {
+		add("--tmpfs", t)
+	}
+
+	n := c.Network
+	flag("--private-network", n.Private)
+	flag("--network-veth", n.VirtualEthernet)
+	add("--network-bridge", n.Bridge)
+	add("--network-zone", n.Zone)
+	for _, i := range n.Interfaces {
+		add("--network-interface", i)
+	}
+	for _, i := range n.MACVLAN {
+		add("--network-macvlan", i)
+	}
+	for _, i := range n.IPVLAN {
+		add("--network-ipvlan", i)
+	}
+	for _, p := range n.Ports {
+		add("--port", p.arg())
+	}
+
+	if len(c.Capabilities) > 0 {
+		add("--capability", strings.Join(c.Capabilities, ","))
+	}
+	if len(c.DropCapabilities) > 0 {
+		add("--drop-capability", strings.Join(c.DropCapabilities, ","))
+	}
+	if c.NoNewPrivileges {
+		add("--no-new-privileges", "yes")
+	}
+	if len(c.SystemCallFilter) > 0 {
+		add("--system-call-filter", strings.Join(c.SystemCallFilter, " "))
+	}
+	for _, name := range sortedKeys(c.Limits) {
+		add("--rlimit", "RLIMIT_"+strings.TrimPrefix(strings.ToUpper(name), "RLIMIT_")+"="+c.Limits[name])
+	}
+	if c.OOMScoreAdjust != nil {
+		add("--oom-score-adjust", strconv.Itoa(*c.OOMScoreAdjust))
+	}
+	add("--cpu-affinity", c.CPUAffinity)
+	add("--private-users", c.PrivateUsers)
+	add("--resolv-conf", c.ResolvConf)
+	add("--timezone", c.Timezone)
+	add("--link-journal", c.LinkJournal)
+
+	if c.NotifyReady {
+		add("--notify-ready", "yes")
+	}
+	add("--kill-signal", c.KillSignal)
+	if c.NoRegister {
+		add("--register", "no")
+	}
+	add("--slice", c.Slice)
+	for _, p := range c.Properties {
+		add("--property", p)
+	}
+	add("--console", string(c.Console))
+	add("--settings", c.Settings)
+	flag("--quiet", c.Quiet)
+	args = append(args, c.ExtraArgs...)
+
+	if len(c.Command) > 0 {
+		args = append(args, "--")
+		args = append(args, c.Command...)
+	}
+	return args, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
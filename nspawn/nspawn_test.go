@@ -0,0 +1,144 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nspawn
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	adj := -500
+	for i, tt := range []struct {
+		config Config
+		want   []string
+	}{
+		{
+			Config{Directory: "/var/lib/machines/web", Boot: true},
+			[]string{"--directory=/var/lib/machines/web", "--boot"},
+		},
+		{
+			Config{
+				Image:       "/var/lib/machines/db.raw",
+				Machine:     "db1",
+				Ephemeral:   true,
+				Command:     []string{"/usr/bin/postgres", "-D", "/data"},
+				User:        "postgres",
+				Environment: []string{"LANG=C.UTF-8"},
+				Bind: []Bind{
+					{Source: "/srv/db", Destination: "/data"},
+					{Source: "/etc/ssl:certs", ReadOnly: true},
+					{Source: "/srv/log", Destination: "/var/log", Options: "idmap"},
+					{Source: `/srv/a\b:c`, Destination: "/d"},
+				},
+				TemporaryFS: []string{"/tmp:mode=1777"},
+				Network: Network{
+					VirtualEthernet: true,
+					Bridge:          "br0",
+					Ports:           []Port{{Host: 5432}, {Protocol: "udp", Host: 8053, Container: 53}},
+				},
+				Capabilities:     []string{"CAP_NET_ADMIN"},
+				DropCapabilities: []string{"CAP_SYS_ADMIN", "CAP_MKNOD"},
+				NoNewPrivileges:  true,
+				SystemCallFilter: []string{"@system-service", "~@mount"},
+				Limits:           map[string]string{"nofile": "1024:4096", "RLIMIT_CORE": "0"},
+				OOMScoreAdjust:   &adj,
+				NotifyReady:      true,
+				Properties:       []string{"MemoryMax=1G"},
+				Console:          ConsolePipe,
+				Quiet:            true,
+			},
+			[]string{
+				"--image=/var/lib/machines/db.raw",
+				"--ephemeral",
+				"--machine=db1",
+				"--user=postgres",
+				"--setenv=LANG=C.UTF-8",
+				"--bind=/srv/db:/data",
+				`--bind-ro=/etc/ssl\:certs`,
+				"--bind=/srv/log:/var/log:idmap",
+				`--bind=/srv/a\\b\:c:/d`,
+				"--tmpfs=/tmp:mode=1777",
+				"--network-veth",
+				"--network-bridge=br0",
+				"--port=tcp:5432:5432",
+				"--port=udp:8053:53",
+				"--capability=CAP_NET_ADMIN",
+				"--drop-capability=CAP_SYS_ADMIN,CAP_MKNOD",
+				"--no-new-privileges=yes",
+				"--system-call-filter=@system-service ~@mount",
+				"--rlimit=RLIMIT_CORE=0",
+				"--rlimit=RLIMIT_NOFILE=1024:4096",
+				"--oom-score-adjust=-500",
+				"--notify-ready=yes",
+				"--property=MemoryMax=1G",
+				"--console=pipe",
+				"--quiet",
+				"--",
+				"/usr/bin/postgres", "-D", "/data",
+			},
+		},
+	} {
+		args, err := tt.config.Args()
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(args, tt.want) {
+			t.Errorf("case %d: expected %q, got %q", i, tt.want, args)
+		}
+	}
+}
+
+func TestArgsErrors(t *testing.T) {
+	for i, c := range []Config{
+		{},
+		{Directory: "/a", Image: "/b.raw"},
+		{Image: "/b.raw", Template: "/t"},
+		{Directory: "/a", Template: "/t", Ephemeral: true},
+		{Directory: "/a", Boot: true, AsPID2: true},
+		{Directory: "/a", Machine: "bad name"},
+		{Directory: "/a", Bind: []Bind{{Source: "relative"}}},
+		{Directory: "/a", Environment: []string{"NOVALUE"}},
+		{Directory: "/a", Network: Network{Ports: []Port{{Host: 80}}}},
+		{Directory: "/a", Network: Network{Private: true, Ports: []Port{{Protocol: "sctp", Host: 80}}}},
+		{Directory: "/a", Console: "serial"},
+	} {
+		if _, err := c.Args(); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestMachineName(t *testing.T) {
+	for i, tt := range []struct {
+		config Config
+		want   string
+	}{
+		{Config{Directory: "/var/lib/machines/web/"}, "web"},
+		{Config{Image: "/var/lib/machines/db.raw"}, "db"},
+		{Config{Image: "/var/lib/machines/db.raw", Machine: "db1"}, "db1"},
+	} {
+		if n := tt.config.MachineName(); n != tt.want {
+			t.Errorf("case %d: expected %q, got %q", i, tt.want, n)
+		}
+	}
+
+	for _, name := range []string{"", ".a", "a..b", "a b", "a/b"} {
+		if ValidMachineName(name) {
+			t.Errorf("%q is not a valid machine name", name)
+		}
+	}
+}
@@ -0,0 +1,680 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nspawn
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// SettingsDirs are the directories systemd-nspawn looks for the .nspawn
+// file of a container in, before the directory holding its image. It is a
+// variable so it can be changed by tests.
+var SettingsDirs = []string{"/etc/systemd/nspawn", "/run/systemd/nspawn"}
+
+// ExecSection is the [Exec] section of a .nspawn file. Unset pointers and
+// empty values leave the setting to the command line.
+type ExecSection struct {
+	Boot              *bool
+	Ephemeral         *bool
+	ProcessTwo        *bool
+	Parameters        []string
+	Environment       []string // KEY=VALUE pairs
+	User              string
+	WorkingDirectory  string
+	PivotRoot         string
+	Capability        []string
+	DropCapability    []string
+	AmbientCapability []string
+	NoNewPrivileges   *bool
+	KillSignal        string
+	Personality       string
+	MachineID         string
+	PrivateUsers      string
+	NotifyReady       *bool
+	SystemCallFilter  []string
+	Limits            map[string]string // By name without the Limit prefix, e.g. "NOFILE"
+	OOMScoreAdjust    *int
+	CPUAffinity       string
+	Hostname          string
+	ResolvConf        string
+	Timezone          string
+	LinkJournal       string
+	SuppressSync      *bool
+}
+
+// FilesSection is the [Files] section of a .nspawn file.
+type FilesSection struct {
+	ReadOnly              *bool
+	Volatile              string
+	Bind                  []Bind // Bind= and BindReadOnly=
+	BindUser              []string
+	TemporaryFileSystem   []string
+	Inaccessible          []string
+	Overlay               []string
+	OverlayReadOnly       []string
+	PrivateUsersOwnership string
+}
+
+// NetworkSection is the [Network] section of a .nspawn file.
+type NetworkSection struct {
+	Private              *bool
+	VirtualEthernet      *bool
+	VirtualEthernetExtra []string
+	Interface            []string
+	MACVLAN              []string
+	IPVLAN               []string
+	Bridge               string
+	Zone                 string
+	Port                 []Port
+}
+
+// Settings are the per-container settings of a .nspawn file.
+type Settings struct {
+	Exec    ExecSection
+	Files   FilesSection
+	Network NetworkSection
+	// Unknown holds the options this package does not know, e.g. those
+	// added by newer versions of systemd-nspawn, which are written back
+	// unchanged.
+	Unknown []*unit.UnitOption
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "1", "yes", "y", "true", "t", "on":
+		return true, nil
+	case "0", "no", "n", "false", "f", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// parseBind parses SOURCE[:DESTINATION[:OPTIONS]], where colons and
+// backslashes in the paths are escaped with a backslash.
+func parseBind(s string, readOnly bool) (Bind, error) {
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			cur.WriteByte(s[i+1])
+			i++
+		case s[i] == ':' && len(parts) < 2:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(s[i])
+		}
+	}
+	parts = append(parts, cur.String())
+
+	b := Bind{Source: parts[0], ReadOnly: readOnly}
+	if len(parts) > 1 {
+		b.Destination = parts[1]
+	}
+	if len(parts) > 2 {
+		b.Options = parts[2]
+	}
+	if b.Source == "" {
+		return Bind{}, fmt.Errorf("invalid bind mount %q", s)
+	}
+	return b, nil
+}
+
+// parsePort parses [PROTOCOL:]HOSTPORT[:CONTAINERPORT].
+func parsePort(s string) (Port, error) {
+	var p Port
+	parts := strings.Split(s, ":")
+	if len(parts) > 1 && (parts[0] == "tcp" || parts[0] == "udp") {
+		p.Protocol = parts[0]
+		parts = parts[1:]
+	}
+	if len(parts) > 2 {
+		return Port{}, fmt.Errorf("invalid port %q", s)
+	}
+	host, err := strconv.ParseUint(parts[0], 10, 16)
+	if err != nil || host == 0 {
+		return Port{}, fmt.Errorf("invalid port %q", s)
+	}
+	p.Host = uint16(host)
+	if len(parts) == 2 {
+		c, err := strconv.ParseUint(parts[1], 10, 16)
+		if err != nil || c == 0 {
+			return Port{}, fmt.Errorf("invalid port %q", s)
+		}
+		p.Container = uint16(c)
+	}
+	return p, nil
+}
+
+// quoteWords joins words into a value SplitWords splits into them again.
+func quoteWords(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		if w != "" && !strings.ContainsAny(w, " \t\n\r\"'\\") {
+			quoted[i] = w
+			continue
+		}
+		r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+		quoted[i] = `"` + r.Replace(w) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// ParseSettings parses a .nspawn file. Settings taking a list of words may
+// be given several times to extend the list, an empty assignment resets it.
+func ParseSettings(r io.Reader) (*Settings, error) {
+	opts, err := unit.DeserializeOptions(r)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Settings{}
+	for _, o := range opts {
+		if err := s.set(o.Section, o.Name, o.Value); err != nil {
+			return nil, fmt.Errorf("[%s] %s=%s: %v", o.Section, o.Name, o.Value, err)
+		}
+	}
+	return s, nil
+}
+
+func (s *Settings) set(section, name, value string) error {
+	e, f, n := &s.Exec, &s.Files, &s.Network
+
+	setBool := func(p **bool) error {
+		if value == "" {
+			*p = nil
+			return nil
+		}
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		*p = &b
+		return nil
+	}
+	words := func(p *[]string) error {
+		if value == "" {
+			*p = nil
+			return nil
+		}
+		w, err := unit.SplitWords(value)
+		if err != nil {
+			return err
+		}
+		*p = append(*p, w...)
+		return nil
+	}
+	each := func(p *[]string) error {
+		if value == "" {
+			*p = nil
+		} else {
+			*p = append(*p, value)
+		}
+		return nil
+	}
+	str := func(p *string) error {
+		*p = value
+		return nil
+	}
+
+	switch section + "." + name {
+	case "Exec.Boot":
+		return setBool(&e.Boot)
+	case "Exec.Ephemeral":
+		return setBool(&e.Ephemeral)
+	case "Exec.ProcessTwo":
+		return setBool(&e.ProcessTwo)
+	case "Exec.Parameters":
+		return words(&e.Parameters)
+	case "Exec.Environment":
+		if value != "" && strings.IndexByte(value, '=') <= 0 {
+			return fmt.Errorf("invalid environment variable assignment")
+		}
+		return each(&e.Environment)
+	case "Exec.User":
+		return str(&e.User)
+	case "Exec.WorkingDirectory":
+		return str(&e.WorkingDirectory)
+	case "Exec.PivotRoot":
+		return str(&e.PivotRoot)
+	case "Exec.Capability":
+		return words(&e.Capability)
+	case "Exec.DropCapability":
+		return words(&e.DropCapability)
+	case "Exec.AmbientCapability":
+		return words(&e.AmbientCapability)
+	case "Exec.NoNewPrivileges":
+		return setBool(&e.NoNewPrivileges)
+	case "Exec.KillSignal":
+		return str(&e.KillSignal)
+	case "Exec.Personality":
+		return str(&e.Personality)
+	case "Exec.MachineID":
+		return str(&e.MachineID)
+	case "Exec.PrivateUsers":
+		return str(&e.PrivateUsers)
+	case "Exec.NotifyReady":
+		return setBool(&e.NotifyReady)
+	case "Exec.SystemCallFilter":
+		return words(&e.SystemCallFilter)
+	case "Exec.OOMScoreAdjust":
+		if value == "" {
+			e.OOMScoreAdjust = nil
+			return nil
+		}
+		v, err := strconv.Atoi(value)
+		if err != nil || v < -1000 || v > 1000 {
+			return fmt.Errorf("invalid OOM score adjustment")
+		}
+		e.OOMScoreAdjust = &v
+		return nil
+	case "Exec.CPUAffinity":
+		return str(&e.CPUAffinity)
+	case "Exec.Hostname":
+		return str(&e.Hostname)
+	case "Exec.ResolvConf":
+		return str(&e.ResolvConf)
+	case "Exec.Timezone":
+		return str(&e.Timezone)
+	case "Exec.LinkJournal":
+		return str(&e.LinkJournal)
+	case "Exec.SuppressSync":
+		return setBool(&e.SuppressSync)
+
+	case "Files.ReadOnly":
+		return setBool(&f.ReadOnly)
+	case "Files.Volatile":
+		return str(&f.Volatile)
+	case "Files.Bind", "Files.BindReadOnly":
+		if value == "" {
+			f.Bind = nil
+			return nil
+		}
+		b, err := parseBind(value, name == "BindReadOnly")
+		if err != nil {
+			return err
+		}
+		f.Bind = append(f.Bind, b)
+		return nil
+	case "Files.BindUser":
+		return words(&f.BindUser)
+	case "Files.TemporaryFileSystem":
+		return each(&f.TemporaryFileSystem)
+	case "Files.Inaccessible":
+		return each(&f.Inaccessible)
+	case "Files.Overlay":
+		return each(&f.Overlay)
+	case "Files.OverlayReadOnly":
+		return each(&f.OverlayReadOnly)
+	case "Files.PrivateUsersOwnership":
+		return str(&f.PrivateUsersOwnership)
+
+	case "Network.Private":
+		return setBool(&n.Private)
+	case "Network.VirtualEthernet":
+		return setBool(&n.VirtualEthernet)
+	case "Network.VirtualEthernetExtra":
+		return each(&n.VirtualEthernetExtra)
+	case "Network.Interface":
+		return words(&n.Interface)
+	case "Network.MACVLAN":
+		return words(&n.MACVLAN)
+	case "Network.IPVLAN":
+		return words(&n.IPVLAN)
+	case "Network.Bridge":
+		return str(&n.Bridge)
+	case "Network.Zone":
+		return str(&n.Zone)
+	case "Network.Port":
+		if value == "" {
+			n.Port = nil
+			return nil
+		}
+		p, err := parsePort(value)
+		if err != nil {
+			return err
+		}
+		n.Port = append(n.Port, p)
+		return nil
+	}
+
+	if section == "Exec" && strings.HasPrefix(name, "Limit") && len(name) > len("Limit") {
+		if e.Limits == nil {
+			e.Limits = map[string]string{}
+		}
+		if value == "" {
+			delete(e.Limits, name[len("Limit"):])
+		} else {
+			e.Limits[name[len("Limit"):]] = value
+		}
+		return nil
+	}
+	s.Unknown = append(s.Unknown, unit.NewUnitOption(section, name, value))
+	return nil
+}
+
+// Validate checks the settings for values systemd-nspawn rejects.
+func (s *Settings) Validate() error {
+	if s.Exec.Boot != nil && *s.Exec.Boot && s.Exec.ProcessTwo != nil && *s.Exec.ProcessTwo {
+		return fmt.Errorf("Boot= and ProcessTwo= cannot both be enabled")
+	}
+	switch s.Files.Volatile {
+	case "", "yes", "no", "state", "overlay":
+	default:
+		return fmt.Errorf("invalid Volatile= mode %q", s.Files.Volatile)
+	}
+	for _, b := range s.Files.Bind {
+		if !filepath.IsAbs(b.Source) && !strings.HasPrefix(b.Source, "+") {
+			return fmt.Errorf("bind mount source %q is not absolute", b.Source)
+		}
+		if b.Destination != "" && !filepath.IsAbs(b.Destination) {
+			return fmt.Errorf("bind mount destination %q is not absolute", b.Destination)
+		}
+	}
+	for _, p := range s.Network.Port {
+		if p.Protocol != "" && p.Protocol != "tcp" && p.Protocol != "udp" {
+			return fmt.Errorf("invalid port protocol %q", p.Protocol)
+		}
+		if p.Host == 0 {
+			return fmt.Errorf("port forward without host port")
+		}
+	}
+	for _, e := range s.Exec.Environment {
+		if strings.IndexByte(e, '=') <= 0 {
+			return fmt.Errorf("invalid environment variable %q", e)
+		}
+	}
+	return nil
+}
+
+// Sections returns the settings as sections of a .nspawn file.
+func (s *Settings) Sections() []*unit.UnitSection {
+	var sections []*unit.UnitSection
+	var cur *unit.UnitSection
+	begin := func(name string) {
+		cur = &unit.UnitSection{Section: name}
+		sections = append(sections, cur)
+	}
+	add := func(name, value string) {
+		if value != "" {
+			cur.Entries = append(cur.Entries, &unit.UnitEntry{Name: name, Value: value})
+		}
+	}
+	addBool := func(name string, b *bool) {
+		if b != nil {
+			add(name, formatBool(*b))
+		}
+	}
+	addWords := func(name string, w []string) {
+		if len(w) > 0 {
+			add(name, quoteWords(w))
+		}
+	}
+	addEach := func(name string, values []string) {
+		for _, v := range values {
+			add(name, v)
+		}
+	}
+
+	e := &s.Exec
+	begin("Exec")
+	addBool("Boot", e.Boot)
+	addBool("Ephemeral", e.Ephemeral)
+	addBool("ProcessTwo", e.ProcessTwo)
+	addWords("Parameters", e.Parameters)
+	addEach("Environment", e.Environment)
+	add("User", e.User)
+	add("WorkingDirectory", e.WorkingDirectory)
+	add("PivotRoot", e.PivotRoot)
+	addWords("Capability", e.Capability)
+	addWords("DropCapability", e.DropCapability)
+	addWords("AmbientCapability", e.AmbientCapability)
+	addBool("NoNewPrivileges", e.NoNewPrivileges)
+	add("KillSignal", e.KillSignal)
+	add("Personality", e.Personality)
+	add("MachineID", e.MachineID)
+	add("PrivateUsers", e.PrivateUsers)
+	addBool("NotifyReady", e.NotifyReady)
+	addWords("SystemCallFilter", e.SystemCallFilter)
+	for _, name := range sortedKeys(e.Limits) {
+		add("Limit"+name, e.Limits[name])
+	}
+	if e.OOMScoreAdjust != nil {
+		add("OOMScoreAdjust", strconv.Itoa(*e.OOMScoreAdjust))
+	}
+	add("CPUAffinity", e.CPUAffinity)
+	add("Hostname", e.Hostname)
+	add("ResolvConf", e.ResolvConf)
+	add("Timezone", e.Timezone)
+	add("LinkJournal", e.LinkJournal)
+	addBool("SuppressSync", e.SuppressSync)
+
+	f := &s.Files
+	begin("Files")
+	addBool("ReadOnly", f.ReadOnly)
+	add("Volatile", f.Volatile)
+	for _, b := range f.Bind {
+		if b.ReadOnly {
+			add("BindReadOnly", b.arg())
+		} else {
+			add("Bind", b.arg())
+		}
+	}
+	addWords("BindUser", f.BindUser)
+	addEach("TemporaryFileSystem", f.TemporaryFileSystem)
+	addEach("Inaccessible", f.Inaccessible)
+	addEach("Overlay", f.Overlay)
+	addEach("OverlayReadOnly", f.OverlayReadOnly)
+	add("PrivateUsersOwnership", f.PrivateUsersOwnership)
+
+	n := &s.Network
+	begin("Network")
+	addBool("Private", n.Private)
+	addBool("VirtualEthernet", n.VirtualEthernet)
+	addEach("VirtualEthernetExtra", n.VirtualEthernetExtra)
+	addWords("Interface", n.Interface)
+	addWords("MACVLAN", n.MACVLAN)
+	addWords("IPVLAN", n.IPVLAN)
+	add("Bridge", n.Bridge)
+	add("Zone", n.Zone)
+	for _, p := range n.Port {
+		add("Port", p.arg())
+	}
+
+	for _, o := range s.Unknown {
+		var sec *unit.UnitSection
+		for _, existing := range sections {
+			if existing.Section == o.Section {
+				sec = existing
+			}
+		}
+		if sec == nil {
+			sec = &unit.UnitSection{Section: o.Section}
+			sections = append(sections, sec)
+		}
+		sec.Entries = append(sec.Entries, &unit.UnitEntry{Name: o.Name, Value: o.Value})
+	}
+
+	// Leave out empty sections.
+	kept := sections[:0]
+	for _, sec := range sections {
+		if len(sec.Entries) > 0 {
+			kept = append(kept, sec)
+		}
+	}
+	return kept
+}
+
+// Serialize validates and encodes the settings as .nspawn file.
+func (s *Settings) Serialize() (io.Reader, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return unit.SerializeSections(s.Sections()), nil
+}
+
+// ReadSettings reads the .nspawn file at path.
+func ReadSettings(path string) (*Settings, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSettings(bytes.NewReader(b))
+}
+
+// WriteSettings validates the settings and writes them to path.
+func WriteSettings(path string, s *Settings) error {
+	r, err := s.Serialize()
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// FindSettings returns the path of the .nspawn file systemd-nspawn uses
+// for the machine called name, whose image is below imageDir, usually
+// /var/lib/machines. Files in SettingsDirs are trusted and fully applied, a
+// file next to the image only partially unless --settings=trusted is
+// given. It returns os.ErrNotExist if there is none.
+func FindSettings(name, imageDir string) (path string, trusted bool, err error) {
+	for _, dir := range SettingsDirs {
+		p := filepath.Join(dir, name+".nspawn")
+		if _, err := os.Stat(p); err == nil {
+			return p, true, nil
+		}
+	}
+	if imageDir != "" {
+		p := filepath.Join(imageDir, name+".nspawn")
+		if _, err := os.Stat(p); err == nil {
+			return p, false, nil
+		}
+	}
+	return "", false, os.ErrNotExist
+}
+
+// Apply applies the settings to c as systemd-nspawn does for a trusted
+// .nspawn file, overriding the values of c with those that are set and
+// appending to lists. Settings without a field in Config are added to
+// ExtraArgs.
+func (s *Settings) Apply(c *Config) {
+	e, f, n := &s.Exec, &s.Files, &s.Network
+	setBool := func(dst *bool, src *bool) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+	setStr := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	extra := func(flag, value string) {
+		if value != "" {
+			c.ExtraArgs = append(c.ExtraArgs, flag+"="+value)
+		}
+	}
+
+	setBool(&c.Boot, e.Boot)
+	setBool(&c.Ephemeral, e.Ephemeral)
+	setBool(&c.AsPID2, e.ProcessTwo)
+	if len(e.Parameters) > 0 {
+		c.Command = e.Parameters
+	}
+	c.Environment = append(c.Environment, e.Environment...)
+	setStr(&c.User, e.User)
+	setStr(&c.WorkingDirectory, e.WorkingDirectory)
+	extra("--pivot-root", e.PivotRoot)
+	c.Capabilities = append(c.Capabilities, e.Capability...)
+	c.DropCapabilities = append(c.DropCapabilities, e.DropCapability...)
+	if len(e.AmbientCapability) > 0 {
+		extra("--ambient-capability", strings.Join(e.AmbientCapability, ","))
+	}
+	setBool(&c.NoNewPrivileges, e.NoNewPrivileges)
+	setStr(&c.KillSignal, e.KillSignal)
+	extra("--personality", e.Personality)
+	setStr(&c.UUID, e.MachineID)
+	setStr(&c.PrivateUsers, e.PrivateUsers)
+	setBool(&c.NotifyReady, e.NotifyReady)
+	c.SystemCallFilter = append(c.SystemCallFilter, e.SystemCallFilter...)
+	for k, v := range e.Limits {
+		if c.Limits == nil {
+			c.Limits = map[string]string{}
+		}
+		c.Limits[k] = v
+	}
+	if e.OOMScoreAdjust != nil {
+		v := *e.OOMScoreAdjust
+		c.OOMScoreAdjust = &v
+	}
+	setStr(&c.CPUAffinity, e.CPUAffinity)
+	setStr(&c.Hostname, e.Hostname)
+	setStr(&c.ResolvConf, e.ResolvConf)
+	setStr(&c.Timezone, e.Timezone)
+	setStr(&c.LinkJournal, e.LinkJournal)
+	if e.SuppressSync != nil {
+		extra("--suppress-sync", formatBool(*e.SuppressSync))
+	}
+
+	setBool(&c.ReadOnly, f.ReadOnly)
+	setStr(&c.Volatile, f.Volatile)
+	c.Bind = append(c.Bind, f.Bind...)
+	for _, u := range f.BindUser {
+		extra("--bind-user", u)
+	}
+	c.TemporaryFS = append(c.TemporaryFS, f.TemporaryFileSystem...)
+	for _, p := range f.Inaccessible {
+		extra("--inaccessible", p)
+	}
+	for _, o := range f.Overlay {
+		extra("--overlay", o)
+	}
+	for _, o := range f.OverlayReadOnly {
+		extra("--overlay-ro", o)
+	}
+	extra("--private-users-ownership", f.PrivateUsersOwnership)
+
+	setBool(&c.Network.Private, n.Private)
+	setBool(&c.Network.VirtualEthernet, n.VirtualEthernet)
+	for _, v := range n.VirtualEthernetExtra {
+		extra("--network-veth-extra", v)
+	}
+	c.Network.Interfaces = append(c.Network.Interfaces, n.Interface...)
+	c.Network.MACVLAN = append(c.Network.MACVLAN, n.MACVLAN...)
+	c.Network.IPVLAN = append(c.Network.IPVLAN, n.IPVLAN...)
+	setStr(&c.Network.Bridge, n.Bridge)
+	setStr(&c.Network.Zone, n.Zone)
+	c.Network.Ports = append(c.Network.Ports, n.Port...)
+}
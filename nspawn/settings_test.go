@@ -0,0 +1,226 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nspawn
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSettings = `[Exec]
+Boot=yes
+Parameters=--log-level=debug "quoted arg"
+Environment=LANG=C.UTF-8
+Environment=TERM=xterm
+Capability=CAP_NET_ADMIN CAP_SYS_TIME
+NoNewPrivileges=true
+LimitNOFILE=1024:4096
+OOMScoreAdjust=-100
+Hostname=web
+FutureSetting=1
+
+[Files]
+ReadOnly=no
+Bind=/srv/www:/var/www
+BindReadOnly=/etc/ssl\:certs:/etc/ssl:norbind
+Bind=/srv/a\\b\:c:/d
+TemporaryFileSystem=/tmp
+Inaccessible=/proc/kcore
+
+[Network]
+VirtualEthernet=yes
+Zone=web
+Port=tcp:80:8080
+Port=443
+
+[Future]
+Key=value
+`
+
+func TestParseSettings(t *testing.T) {
+	s, err := ParseSettings(strings.NewReader(testSettings))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := s.Exec
+	if e.Boot == nil || !*e.Boot || e.Ephemeral != nil {
+		t.Errorf("unexpected booleans %v %v", e.Boot, e.Ephemeral)
+	}
+	if !reflect.DeepEqual(e.Parameters, []string{"--log-level=debug", "quoted arg"}) {
+		t.Errorf("unexpected parameters %q", e.Parameters)
+	}
+	if !reflect.DeepEqual(e.Environment, []string{"LANG=C.UTF-8", "TERM=xterm"}) {
+		t.Errorf("unexpected environment %q", e.Environment)
+	}
+	if !reflect.DeepEqual(e.Capability, []string{"CAP_NET_ADMIN", "CAP_SYS_TIME"}) {
+		t.Errorf("unexpected capabilities %q", e.Capability)
+	}
+	if e.Limits["NOFILE"] != "1024:4096" || e.OOMScoreAdjust == nil || *e.OOMScoreAdjust != -100 {
+		t.Errorf("unexpected limits %v %v", e.Limits, e.OOMScoreAdjust)
+	}
+	wantBind := []Bind{
+		{Source: "/srv/www", Destination: "/var/www"},
+		{Source: "/etc/ssl:certs", Destination: "/etc/ssl", Options: "norbind", ReadOnly: true},
+		{Source: `/srv/a\b:c`, Destination: "/d"},
+	}
+	if !reflect.DeepEqual(s.Files.Bind, wantBind) {
+		t.Errorf("unexpected bind mounts %+v", s.Files.Bind)
+	}
+	wantPort := []Port{{Protocol: "tcp", Host: 80, Container: 8080}, {Host: 443}}
+	if !reflect.DeepEqual(s.Network.Port, wantPort) {
+		t.Errorf("unexpected ports %+v", s.Network.Port)
+	}
+	if len(s.Unknown) != 2 || s.Unknown[0].Name != "FutureSetting" || s.Unknown[1].Section != "Future" {
+		t.Errorf("unexpected unknown options %v", s.Unknown)
+	}
+
+	// Writing and reading the settings again yields the same settings.
+	r, err := s.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := ParseSettings(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Exec, s2.Exec) || !reflect.DeepEqual(s.Files, s2.Files) || len(s2.Unknown) != 2 {
+		t.Errorf("settings do not round trip:\n%+v\n%+v", s, s2)
+	}
+	for i := range s.Network.Port {
+		if s.Network.Port[i].arg() != s2.Network.Port[i].arg() {
+			t.Errorf("port %d does not round trip", i)
+		}
+	}
+}
+
+func TestParseSettingsReset(t *testing.T) {
+	s, err := ParseSettings(strings.NewReader("[Exec]\nCapability=CAP_A\nCapability=\nCapability=CAP_B\nBoot=yes\nBoot=\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Exec.Capability, []string{"CAP_B"}) || s.Exec.Boot != nil {
+		t.Errorf("unexpected settings %+v", s.Exec)
+	}
+}
+
+func TestParseSettingsErrors(t *testing.T) {
+	for i, in := range []string{
+		"[Exec]\nBoot=maybe\n",
+		"[Exec]\nEnvironment=NOVALUE\n",
+		"[Exec]\nOOMScoreAdjust=2000\n",
+		"[Exec]\nParameters=\"unterminated\n",
+		"[Network]\nPort=sctp:80\n",
+		"[Network]\nPort=0\n",
+	} {
+		if _, err := ParseSettings(strings.NewReader(in)); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+
+	yes := true
+	for i, s := range []Settings{
+		{Exec: ExecSection{Boot: &yes, ProcessTwo: &yes}},
+		{Files: FilesSection{Volatile: "sometimes"}},
+		{Files: FilesSection{Bind: []Bind{{Source: "relative"}}}},
+	} {
+		if err := s.Validate(); err == nil {
+			t.Errorf("case %d: expected validation error", i)
+		}
+	}
+}
+
+func TestApplySettings(t *testing.T) {
+	s, err := ParseSettings(strings.NewReader(testSettings))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Config{Directory: "/var/lib/machines/web", Environment: []string{"A=B"}}
+	s.Apply(c)
+	args, err := c.Args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"--directory=/var/lib/machines/web",
+		"--hostname=web",
+		"--boot",
+		"--setenv=A=B",
+		"--setenv=LANG=C.UTF-8",
+		"--setenv=TERM=xterm",
+		"--bind=/srv/www:/var/www",
+		`--bind-ro=/etc/ssl\:certs:/etc/ssl:norbind`,
+		`--bind=/srv/a\\b\:c:/d`,
+		"--tmpfs=/tmp",
+		"--network-veth",
+		"--network-zone=web",
+		"--port=tcp:80:8080",
+		"--port=tcp:443:443",
+		"--capability=CAP_NET_ADMIN,CAP_SYS_TIME",
+		"--no-new-privileges=yes",
+		"--rlimit=RLIMIT_NOFILE=1024:4096",
+		"--oom-score-adjust=-100",
+		"--inaccessible=/proc/kcore",
+		"--",
+		"--log-level=debug", "quoted arg",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("expected\n%q\ngot\n%q", want, args)
+	}
+}
+
+func TestFindSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nspawn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d []string) { SettingsDirs = d }(SettingsDirs)
+	etc := filepath.Join(dir, "etc")
+	images := filepath.Join(dir, "machines")
+	SettingsDirs = []string{etc}
+	for _, d := range []string{etc, images} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, _, err := FindSettings("web", images); !os.IsNotExist(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+
+	s := &Settings{Exec: ExecSection{Hostname: "web"}}
+	if err := WriteSettings(filepath.Join(images, "web.nspawn"), s); err != nil {
+		t.Fatal(err)
+	}
+	if p, trusted, err := FindSettings("web", images); err != nil || trusted || p != filepath.Join(images, "web.nspawn") {
+		t.Errorf("unexpected result %q %v %v", p, trusted, err)
+	}
+	if err := WriteSettings(filepath.Join(etc, "web.nspawn"), s); err != nil {
+		t.Fatal(err)
+	}
+	p, trusted, err := FindSettings("web", images)
+	if err != nil || !trusted || p != filepath.Join(etc, "web.nspawn") {
+		t.Errorf("unexpected result %q %v %v", p, trusted, err)
+	}
+	s2, err := ReadSettings(p)
+	if err != nil || s2.Exec.Hostname != "web" {
+		t.Errorf("unexpected settings %+v, %v", s2, err)
+	}
+}
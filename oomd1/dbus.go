@@ -0,0 +1,181 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oomd1 provides integration with the systemd-oomd API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.oom1.html
+package oomd1
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.oom1"
+	dbusInterface = "org.freedesktop.oom1.Manager"
+	dbusPath      = "/org/freedesktop/oom1"
+)
+
+// Kill reasons reported by the Killed signal.
+const (
+	KillReasonMemoryUsed     = "memory-used"     // Swap usage exceeded SwapUsedLimit=
+	KillReasonMemoryPressure = "memory-pressure" // Memory pressure exceeded ManagedOOMMemoryPressureLimit=
+)
+
+// KilledEvent is emitted when systemd-oomd killed the processes of a cgroup.
+type KilledEvent struct {
+	Cgroup string
+	Reason string
+}
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+// DumpString returns the state of systemd-oomd as printed by `oomctl dump`.
+func (c *Conn) DumpString(ctx context.Context) (string, error) {
+	var fd dbus.UnixFD
+	if err := c.object.CallWithContext(ctx, dbusInterface+".DumpByFileDescriptor", 0).Store(&fd); err != nil {
+		return "", err
+	}
+	f := os.NewFile(uintptr(fd), "oomd-dump")
+	defer f.Close()
+
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Dump returns the state of systemd-oomd, including the cgroups it monitors
+// and their current swap usage and memory pressure.
+func (c *Conn) Dump(ctx context.Context) (*Dump, error) {
+	s, err := c.DumpString(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDump(s)
+}
+
+// SubscribeKilled returns a channel receiving an event each time
+// systemd-oomd kills a cgroup. The channel is closed when ctx is done or the
+// connection is closed.
+// Note: Requires systemd v252 or higher
+func (c *Conn) SubscribeKilled(ctx context.Context) (<-chan KilledEvent, error) {
+	opts := []dbus.MatchOption{
+		dbus.WithMatchObjectPath(dbusPath),
+		dbus.WithMatchInterface(dbusInterface),
+		dbus.WithMatchMember("Killed"),
+	}
+	if err := c.conn.AddMatchSignalContext(ctx, opts...); err != nil {
+		return nil, err
+	}
+
+	signals := make(chan *dbus.Signal, 10)
+	c.conn.Signal(signals)
+
+	out := make(chan KilledEvent)
+	go func() {
+		defer close(out)
+		defer c.conn.RemoveMatchSignal(opts...)
+		defer c.conn.RemoveSignal(signals)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig, ok := <-signals:
+				if !ok {
+					return
+				}
+				if sig.Name != dbusInterface+".Killed" || sig.Path != dbusPath {
+					continue
+				}
+				var ev KilledEvent
+				if err := dbus.Store(sig.Body, &ev.Cgroup, &ev.Reason); err != nil {
+					continue
+				}
+				select {
+				case out <- ev:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oomd1
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/cgroup"
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// Dump is the state of systemd-oomd. Sizes are in bytes, but only as
+// precise as the human readable dump they are parsed from.
+type Dump struct {
+	DryRun                        bool
+	SwapUsedLimit                 float64 // In percent
+	DefaultMemoryPressureLimit    float64 // In percent
+	DefaultMemoryPressureDuration time.Duration
+
+	MemoryUsed  uint64
+	MemoryTotal uint64
+	SwapUsed    uint64
+	SwapTotal   uint64
+
+	// SwapMonitored are the cgroups monitored for ManagedOOMSwap=kill,
+	// MemoryPressureMonitored the ones for ManagedOOMMemoryPressure=kill.
+	SwapMonitored           []*CgroupContext
+	MemoryPressureMonitored []*CgroupContext
+}
+
+// CgroupContext is the state of a cgroup monitored by systemd-oomd. Only
+// the fields relevant to the kind of monitoring are set.
+type CgroupContext struct {
+	Path string
+
+	SwapUsage uint64
+
+	MemoryPressureLimit         float64 // In percent
+	MemoryPressureDurationLimit time.Duration
+	Pressure                    cgroup.PressureStats
+	CurrentMemoryUsage          uint64
+	MemoryMin                   uint64
+	MemoryLow                   uint64
+	Pgscan                      uint64
+	LastPgscan                  uint64
+}
+
+// ParseDump parses the output of `oomctl dump`.
+func ParseDump(s string) (*Dump, error) {
+	d := &Dump{}
+	var list *[]*CgroupContext
+	var cur *CgroupContext
+
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		switch line {
+		case "System Context:":
+			list = nil
+			continue
+		case "Swap Monitored CGroups:":
+			list = &d.SwapMonitored
+			continue
+		case "Memory Pressure Monitored CGroups:":
+			list = &d.MemoryPressureMonitored
+			continue
+		}
+
+		i := strings.Index(line, ": ")
+		if i < 0 {
+			continue
+		}
+		key, value := line[:i], line[i+2:]
+		var err error
+		switch key {
+		case "Dry Run":
+			d.DryRun = value == "yes"
+		case "Swap Used Limit":
+			d.SwapUsedLimit, err = parsePercent(value)
+		case "Default Memory Pressure Limit":
+			d.DefaultMemoryPressureLimit, err = parsePercent(value)
+		case "Default Memory Pressure Duration":
+			d.DefaultMemoryPressureDuration, err = unit.ParseTimeSpan(value)
+		case "Memory":
+			d.MemoryUsed, d.MemoryTotal, err = parseUsedTotal(value)
+		case "Swap":
+			d.SwapUsed, d.SwapTotal, err = parseUsedTotal(value)
+		case "Path":
+			if list == nil {
+				return nil, fmt.Errorf("cgroup %s outside of a monitored list", value)
+			}
+			cur = &CgroupContext{Path: value}
+			*list = append(*list, cur)
+		default:
+			if cur == nil {
+				continue
+			}
+			err = cur.set(key, value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (c *CgroupContext) set(key, value string) error {
+	var err error
+	switch key {
+	case "Swap Usage":
+		// The root cgroup refers to the system context.
+		if !strings.HasPrefix(value, "(") {
+			c.SwapUsage, err = parseSize(value)
+		}
+	case "Memory Pressure Limit":
+		c.MemoryPressureLimit, err = parsePercent(value)
+	case "Memory Pressure Duration Limit":
+		c.MemoryPressureDurationLimit, err = unit.ParseTimeSpan(value)
+	case "Pressure":
+		err = parsePressure(value, &c.Pressure)
+	case "Current Memory Usage":
+		c.CurrentMemoryUsage, err = parseSize(value)
+	case "Memory Min":
+		c.MemoryMin, err = parseSize(value)
+	case "Memory Low":
+		c.MemoryLow, err = parseSize(value)
+	case "Pgscan":
+		c.Pgscan, err = strconv.ParseUint(value, 10, 64)
+	case "Last Pgscan":
+		c.LastPgscan, err = strconv.ParseUint(value, 10, 64)
+	}
+	return err
+}
+
+func parsePercent(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+}
+
+// parseSize parses a size as formatted by systemd, e.g. "1.5G" or "512B",
+// using binary prefixes.
+func parseSize(s string) (uint64, error) {
+	const suffixes = "BKMGTPE"
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+	n := strings.IndexByte(suffixes, s[len(s)-1])
+	if n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	v, err := strconv.ParseFloat(s[:len(s)-1], 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return uint64(v * float64(uint64(1)<<(10*uint(n)))), nil
+}
+
+// parseUsedTotal parses "Used: 3.4G Total: 7.6G".
+func parseUsedTotal(s string) (uint64, uint64, error) {
+	f := strings.Fields(s)
+	if len(f) != 4 || f[0] != "Used:" || f[2] != "Total:" {
+		return 0, 0, fmt.Errorf("invalid usage %q", s)
+	}
+	used, err := parseSize(f[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	total, err := parseSize(f[3])
+	if err != nil {
+		return 0, 0, err
+	}
+	return used, total, nil
+}
+
+// parsePressure parses "Avg10: 0.00 Avg60: 0.00 Avg300: 0.00 Total: 2s".
+func parsePressure(s string, p *cgroup.PressureStats) error {
+	i := strings.Index(s, "Total: ")
+	if i < 0 {
+		return fmt.Errorf("invalid pressure %q", s)
+	}
+	total, err := unit.ParseTimeSpan(s[i+len("Total: "):])
+	if err != nil {
+		return err
+	}
+	p.Total = total
+
+	f := strings.Fields(s[:i])
+	if len(f) != 6 {
+		return fmt.Errorf("invalid pressure %q", s)
+	}
+	for j, dst := range []*float64{&p.Avg10, &p.Avg60, &p.Avg300} {
+		if *dst, err = strconv.ParseFloat(f[2*j+1], 64); err != nil {
+			return err
+		}
+	}
+	return nil
+}
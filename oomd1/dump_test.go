@@ -0,0 +1,90 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oomd1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/cgroup"
+)
+
+const testDump = `Dry Run: no
+Swap Used Limit: 90.00%
+Default Memory Pressure Limit: 60.00%
+Default Memory Pressure Duration: 30s
+System Context:
+	Memory: Used: 3.5G Total: 7.6G
+	Swap: Used: 512.0M Total: 2.0G
+Swap Monitored CGroups:
+	Path: /
+		Swap Usage: (see System Context)
+Memory Pressure Monitored CGroups:
+	Path: /user.slice/user-1000.slice/user@1000.service
+		Memory Pressure Limit: 50.00%
+		Memory Pressure Duration Limit: 20s
+		Pressure: Avg10: 1.25 Avg60: 0.50 Avg300: 0.00 Total: 1min 2s
+		Current Memory Usage: 1.5G
+		Memory Min: 0B
+		Memory Low: 256.0K
+		Pgscan: 42
+		Last Pgscan: 40
+`
+
+func TestParseDump(t *testing.T) {
+	d, err := ParseDump(testDump)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.DryRun || d.SwapUsedLimit != 90 || d.DefaultMemoryPressureLimit != 60 || d.DefaultMemoryPressureDuration != 30*time.Second {
+		t.Errorf("unexpected settings %+v", d)
+	}
+	if d.MemoryUsed != 3758096384 || d.MemoryTotal != 8160437862 || d.SwapUsed != 512<<20 || d.SwapTotal != 2<<30 {
+		t.Errorf("unexpected system context %+v", d)
+	}
+
+	if len(d.SwapMonitored) != 1 || d.SwapMonitored[0].Path != "/" || d.SwapMonitored[0].SwapUsage != 0 {
+		t.Errorf("unexpected swap monitored cgroups %+v", d.SwapMonitored)
+	}
+
+	if len(d.MemoryPressureMonitored) != 1 {
+		t.Fatalf("unexpected memory pressure monitored cgroups %+v", d.MemoryPressureMonitored)
+	}
+	expected := CgroupContext{
+		Path:                        "/user.slice/user-1000.slice/user@1000.service",
+		MemoryPressureLimit:         50,
+		MemoryPressureDurationLimit: 20 * time.Second,
+		Pressure:                    cgroup.PressureStats{Avg10: 1.25, Avg60: 0.5, Total: 62 * time.Second},
+		CurrentMemoryUsage:          3 << 29,
+		MemoryLow:                   256 << 10,
+		Pgscan:                      42,
+		LastPgscan:                  40,
+	}
+	if *d.MemoryPressureMonitored[0] != expected {
+		t.Errorf("expected %+v, got %+v", expected, *d.MemoryPressureMonitored[0])
+	}
+
+	for i, tt := range []string{
+		"Swap Used Limit: many\n",
+		"System Context:\n\tMemory: Used: 3.5X Total: 7.6G\n",
+		"Path: /\n",
+		"Memory Pressure Monitored CGroups:\n\tPath: /\n\t\tPressure: Avg10: 1.25 Total: 1s\n",
+	} {
+		if _, err := ParseDump(tt); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
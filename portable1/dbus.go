@@ -0,0 +1,439 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package portable1 provides integration with the systemd-portabled API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.portable1.html
+package portable1
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+const (
+	dbusDest      = "org.freedesktop.portable1"
+	dbusInterface = "org.freedesktop.portable1.Manager"
+	dbusPath      = "/org/freedesktop/portable1"
+)
+
+// Flags modify how images are attached, detached and inspected.
+type Flags uint64
+
+const (
+	// FlagRuntime attaches the image below /run rather than /etc, so that
+	// it is detached again on reboot.
+	FlagRuntime Flags = 1 << 0
+	// FlagForceAttach attaches the image even if units of the same name
+	// are already running.
+	FlagForceAttach Flags = 1 << 1
+	// FlagForceExtension allows extension images without an
+	// extension-release file matching the image.
+	FlagForceExtension Flags = 1 << 2
+)
+
+// CopyMode selects how the unit files and profile drop-ins of an image are
+// installed on the host.
+type CopyMode string
+
+const (
+	// CopyModeDefault lets portabled choose, which is symlink for images
+	// in /var/lib/portables and copy otherwise.
+	CopyModeDefault CopyMode = ""
+	CopyModeCopy    CopyMode = "copy"
+	CopyModeSymlink CopyMode = "symlink"
+	// CopyModeMixed copies the profile drop-ins and links the unit files.
+	CopyModeMixed CopyMode = "mixed"
+)
+
+// Image is a portable service image known to portabled.
+type Image struct {
+	Name     string
+	Type     string // directory, subvolume, raw or block
+	ReadOnly bool
+	// CreationTime and ModificationTime are in microseconds since the
+	// epoch, or 0 if unknown.
+	CreationTime     uint64
+	ModificationTime uint64
+	Usage            uint64
+	// State is one of detached, attached, attached-runtime, enabled,
+	// enabled-runtime, running or running-runtime.
+	State string
+	Path  dbus.ObjectPath
+}
+
+// Change is a change made to the host file system while attaching or
+// detaching an image.
+type Change struct {
+	// Type is one of symlink, copy, write, mkdir or unlink.
+	Type   string
+	Path   string
+	Source string
+}
+
+// Metadata describes the contents of an image.
+type Metadata struct {
+	// Image is the path of the image.
+	Image     string
+	OSRelease map[string]string
+	// ExtensionReleases maps the path of each extension image to its
+	// extension-release fields.
+	ExtensionReleases map[string]map[string]string
+	// Units maps the names of the matching unit files to their contents.
+	Units map[string][]byte
+}
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+// Profiles returns the names of the profiles images can be attached with.
+func (c *Conn) Profiles(ctx context.Context) ([]string, error) {
+	var v dbus.Variant
+	if err := c.object.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, dbusInterface, "Profiles").Store(&v); err != nil {
+		return nil, err
+	}
+	profiles, ok := v.Value().([]string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast Profiles to []string")
+	}
+	return profiles, nil
+}
+
+// GetImage returns the object path of the image called name, which may also
+// be the path of an image outside of the search path.
+func (c *Conn) GetImage(ctx context.Context, name string) (dbus.ObjectPath, error) {
+	var path dbus.ObjectPath
+	if err := c.object.CallWithContext(ctx, dbusInterface+".GetImage", 0, name).Store(&path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ListImages returns the images in the portable service search path.
+func (c *Conn) ListImages(ctx context.Context) ([]Image, error) {
+	var result [][]interface{}
+	if err := c.object.CallWithContext(ctx, dbusInterface+".ListImages", 0).Store(&result); err != nil {
+		return nil, err
+	}
+
+	images := []Image{}
+	for _, i := range result {
+		image, err := imageFromInterfaces(i)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, *image)
+	}
+	return images, nil
+}
+
+// GetImageOSRelease returns the os-release(5) fields of image.
+func (c *Conn) GetImageOSRelease(ctx context.Context, image string) (map[string]string, error) {
+	var osRelease map[string]string
+	if err := c.object.CallWithContext(ctx, dbusInterface+".GetImageOSRelease", 0, image).Store(&osRelease); err != nil {
+		return nil, err
+	}
+	return osRelease, nil
+}
+
+// GetImageMetadata returns the os-release fields and the unit files of image
+// when combined with the given extension images. Only unit files whose names
+// start with one of matches are returned, or all portable units if matches
+// is empty.
+func (c *Conn) GetImageMetadata(ctx context.Context, image string, extensions, matches []string, flags Flags) (*Metadata, error) {
+	var (
+		path        string
+		osRelease   []byte
+		extReleases map[string][]byte
+		units       map[string][]byte
+		err         error
+	)
+	if len(extensions) == 0 && flags == 0 {
+		err = c.object.CallWithContext(ctx, dbusInterface+".GetImageMetadata", 0, image, nonNil(matches)).
+			Store(&path, &osRelease, &units)
+	} else {
+		err = c.object.CallWithContext(ctx, dbusInterface+".GetImageMetadataWithExtensions", 0, image, extensions, nonNil(matches), uint64(flags)).
+			Store(&path, &osRelease, &extReleases, &units)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Metadata{
+		Image:             path,
+		OSRelease:         ParseOSRelease(osRelease),
+		ExtensionReleases: map[string]map[string]string{},
+		Units:             units,
+	}
+	for ext, release := range extReleases {
+		m.ExtensionReleases[ext] = ParseOSRelease(release)
+	}
+	return m, nil
+}
+
+// GetImageState returns whether image, combined with the given extension
+// images, is attached, enabled or running. See Image.State for the values.
+func (c *Conn) GetImageState(ctx context.Context, image string, extensions []string, flags Flags) (string, error) {
+	var (
+		state string
+		err   error
+	)
+	if len(extensions) == 0 && flags == 0 {
+		err = c.object.CallWithContext(ctx, dbusInterface+".GetImageState", 0, image).Store(&state)
+	} else {
+		err = c.object.CallWithContext(ctx, dbusInterface+".GetImageStateWithExtensions", 0, image, extensions, uint64(flags)).Store(&state)
+	}
+	if err != nil {
+		return "", err
+	}
+	return state, nil
+}
+
+// AttachImage attaches image with the given extension images to the host,
+// installing the unit files matching matches along with the drop-ins of
+// profile, such as "default" or "strict". The changes made are returned.
+func (c *Conn) AttachImage(ctx context.Context, image string, extensions, matches []string, profile string, mode CopyMode, flags Flags) ([]Change, error) {
+	var (
+		result [][]interface{}
+		err    error
+	)
+	if len(extensions) == 0 && flags&^FlagRuntime == 0 {
+		err = c.object.CallWithContext(ctx, dbusInterface+".AttachImage", 0, image, nonNil(matches), profile, flags&FlagRuntime != 0, string(mode)).
+			Store(&result)
+	} else {
+		err = c.object.CallWithContext(ctx, dbusInterface+".AttachImageWithExtensions", 0, image, extensions, nonNil(matches), profile, string(mode), uint64(flags)).
+			Store(&result)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return changesFromInterfaces(result)
+}
+
+// DetachImage removes the unit files and drop-ins installed by AttachImage
+// for image and the given extension images. The changes made are returned.
+func (c *Conn) DetachImage(ctx context.Context, image string, extensions []string, flags Flags) ([]Change, error) {
+	var (
+		result [][]interface{}
+		err    error
+	)
+	if len(extensions) == 0 && flags&^FlagRuntime == 0 {
+		err = c.object.CallWithContext(ctx, dbusInterface+".DetachImage", 0, image, flags&FlagRuntime != 0).Store(&result)
+	} else {
+		err = c.object.CallWithContext(ctx, dbusInterface+".DetachImageWithExtensions", 0, image, extensions, uint64(flags)).Store(&result)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return changesFromInterfaces(result)
+}
+
+// ReattachImage detaches and attaches image again in one step, as done when
+// upgrading it to a new version. The arguments are those of AttachImage. The
+// files removed and the files installed are returned.
+func (c *Conn) ReattachImage(ctx context.Context, image string, extensions, matches []string, profile string, mode CopyMode, flags Flags) (removed []Change, updated []Change, err error) {
+	var removedResult, updatedResult [][]interface{}
+	if len(extensions) == 0 && flags&^FlagRuntime == 0 {
+		err = c.object.CallWithContext(ctx, dbusInterface+".ReattachImage", 0, image, nonNil(matches), profile, flags&FlagRuntime != 0, string(mode)).
+			Store(&removedResult, &updatedResult)
+	} else {
+		err = c.object.CallWithContext(ctx, dbusInterface+".ReattachImageWithExtensions", 0, image, extensions, nonNil(matches), profile, string(mode), uint64(flags)).
+			Store(&removedResult, &updatedResult)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if removed, err = changesFromInterfaces(removedResult); err != nil {
+		return nil, nil, err
+	}
+	if updated, err = changesFromInterfaces(updatedResult); err != nil {
+		return nil, nil, err
+	}
+	return removed, updated, nil
+}
+
+// RemoveImage removes image from the search path. It must be detached.
+func (c *Conn) RemoveImage(ctx context.Context, image string) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".RemoveImage", 0, image).Store()
+}
+
+// MarkImageReadOnly toggles the read-only flag of image.
+func (c *Conn) MarkImageReadOnly(ctx context.Context, image string, readOnly bool) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".MarkImageReadOnly", 0, image, readOnly).Store()
+}
+
+// ParseOSRelease parses the contents of an os-release(5) or
+// extension-release file as returned by GetImageMetadata.
+func ParseOSRelease(b []byte) map[string]string {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			continue
+		}
+		value := line[i+1:]
+		if words, err := unit.SplitWords(value); err == nil && len(words) == 1 {
+			value = words[0]
+		}
+		fields[line[:i]] = value
+	}
+	return fields
+}
+
+// nonNil returns s, or an empty slice if s is nil, as godbus cannot send a
+// nil slice.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func imageFromInterfaces(image []interface{}) (*Image, error) {
+	if len(image) < 8 {
+		return nil, fmt.Errorf("invalid number of image fields: %d", len(image))
+	}
+	name, ok := image[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast image field 0 to string")
+	}
+	imageType, ok := image[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast image field 1 to string")
+	}
+	readOnly, ok := image[2].(bool)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast image field 2 to bool")
+	}
+	crtime, ok := image[3].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast image field 3 to uint64")
+	}
+	mtime, ok := image[4].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast image field 4 to uint64")
+	}
+	usage, ok := image[5].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast image field 5 to uint64")
+	}
+	state, ok := image[6].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast image field 6 to string")
+	}
+	path, ok := image[7].(dbus.ObjectPath)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast image field 7 to ObjectPath")
+	}
+	return &Image{
+		Name:             name,
+		Type:             imageType,
+		ReadOnly:         readOnly,
+		CreationTime:     crtime,
+		ModificationTime: mtime,
+		Usage:            usage,
+		State:            state,
+		Path:             path,
+	}, nil
+}
+
+func changesFromInterfaces(result [][]interface{}) ([]Change, error) {
+	changes := []Change{}
+	for _, change := range result {
+		if len(change) < 3 {
+			return nil, fmt.Errorf("invalid number of change fields: %d", len(change))
+		}
+		var fields [3]string
+		for i := range fields {
+			s, ok := change[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("failed to typecast change field %d to string", i)
+			}
+			fields[i] = s
+		}
+		changes = append(changes, Change{Type: fields[0], Path: fields[1], Source: fields[2]})
+	}
+	return changes, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package portable1
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// TestNew ensures that New() works without errors.
+func TestNew(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestParseOSRelease(t *testing.T) {
+	in := `# comment
+ID=debian
+PRETTY_NAME="Debian GNU/Linux 12 (bookworm)"
+PORTABLE_PREFIXES='foo bar'
+
+garbage
+`
+	expected := map[string]string{
+		"ID":                "debian",
+		"PRETTY_NAME":       "Debian GNU/Linux 12 (bookworm)",
+		"PORTABLE_PREFIXES": "foo bar",
+	}
+	if fields := ParseOSRelease([]byte(in)); !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestImageFromInterfaces(t *testing.T) {
+	image, err := imageFromInterfaces([]interface{}{
+		"foo", "raw", true, uint64(1), uint64(2), uint64(4096), "attached", dbus.ObjectPath("/org/freedesktop/portable1/image/foo"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Image{
+		Name:             "foo",
+		Type:             "raw",
+		ReadOnly:         true,
+		CreationTime:     1,
+		ModificationTime: 2,
+		Usage:            4096,
+		State:            "attached",
+		Path:             "/org/freedesktop/portable1/image/foo",
+	}
+	if *image != expected {
+		t.Errorf("expected %+v, got %+v", expected, *image)
+	}
+
+	if _, err := imageFromInterfaces([]interface{}{"foo"}); err == nil {
+		t.Error("expected error for short image")
+	}
+}
+
+func TestChangesFromInterfaces(t *testing.T) {
+	changes, err := changesFromInterfaces([][]interface{}{
+		{"symlink", "/etc/systemd/system.attached/foo.service", "/var/lib/portables/foo.raw/usr/lib/systemd/system/foo.service"},
+		{"mkdir", "/etc/systemd/system.attached/foo.service.d", ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{
+		{Type: "symlink", Path: "/etc/systemd/system.attached/foo.service", Source: "/var/lib/portables/foo.raw/usr/lib/systemd/system/foo.service"},
+		{Type: "mkdir", Path: "/etc/systemd/system.attached/foo.service.d"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("expected %+v, got %+v", expected, changes)
+	}
+
+	if _, err := changesFromInterfaces([][]interface{}{{"copy", "/a", 1}}); err == nil {
+		t.Error("expected error for mistyped change")
+	}
+}
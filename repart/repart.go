@@ -0,0 +1,541 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package repart reads and writes the repart.d partition definitions
+// systemd-repart creates, grows and populates partitions from, so image
+// build pipelines can generate them with validation.  See
+// https://www.freedesktop.org/software/systemd/man/repart.d.html
+package repart
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// Verity is the role of a partition in a dm-verity setup.
+type Verity string
+
+const (
+	VerityOff       Verity = ""
+	VerityData      Verity = "data"
+	VerityHash      Verity = "hash"
+	VeritySignature Verity = "signature"
+)
+
+// Designators are the partition type names of the Discoverable Partitions
+// Specification Type= accepts, optionally followed by an architecture
+// suffix such as "-x86-64" for the root and usr types.
+var Designators = []string{
+	"esp", "xbootldr", "swap", "home", "srv", "var", "tmp", "linux-generic",
+	"root", "root-verity", "root-verity-sig", "root-secondary", "root-secondary-verity", "root-secondary-verity-sig",
+	"usr", "usr-verity", "usr-verity-sig", "usr-secondary", "usr-secondary-verity", "usr-secondary-verity-sig",
+}
+
+// Architectures are the architecture suffixes of partition type names.
+var Architectures = []string{
+	"alpha", "arc", "arm", "arm64", "ia64", "loongarch64", "mips-le", "mips64-le", "parisc",
+	"ppc", "ppc64", "ppc64-le", "riscv32", "riscv64", "s390", "s390x", "tilegx", "x86", "x86-64",
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+
+// ValidType returns whether t is a partition type Type= accepts, a
+// designator or a GPT type UUID.
+func ValidType(t string) bool {
+	if uuidRegexp.MatchString(t) {
+		return true
+	}
+	for _, d := range Designators {
+		if t == d {
+			return true
+		}
+		if !strings.HasPrefix(d, "root") && !strings.HasPrefix(d, "usr") {
+			continue
+		}
+		for _, a := range Architectures {
+			if t == d+"-"+a {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CopyFiles copies a file or directory tree into a new file system.
+type CopyFiles struct {
+	Source string
+	Target string // Defaults to Source
+}
+
+// Partition is the [Partition] section of a repart.d definition.
+type Partition struct {
+	Type     string // Required, see ValidType
+	Label    string
+	UUID     string
+	Priority int
+	// Weight and PaddingWeight distribute free space, defaulting to 1000
+	// and 0.
+	Weight          *uint32
+	PaddingWeight   *uint32
+	SizeMinBytes    uint64
+	SizeMaxBytes    uint64
+	PaddingMinBytes uint64
+	PaddingMaxBytes uint64
+
+	// CopyBlocks initializes the partition from a file or device, "auto"
+	// from the matching partition of the running system.
+	CopyBlocks string
+	// Format creates a file system of this type, e.g. "ext4", "vfat" or
+	// "squashfs", or "swap".
+	Format             string
+	CopyFiles          []CopyFiles
+	ExcludeFiles       []string
+	ExcludeFilesTarget []string
+	MakeDirectories    []string
+	Subvolumes         []string
+	Minimize           string // "off", "best" or "guess"
+	Compression        string
+	CompressionLevel   string
+
+	Encrypt                  string // "off", "key-file", "tpm2" or "key-file+tpm2"
+	Verity                   Verity
+	VerityMatchKey           string
+	VerityDataBlockSizeBytes uint64
+	VerityHashBlockSizeBytes uint64
+
+	FactoryReset    *bool
+	Flags           *uint64
+	NoAuto          *bool
+	ReadOnly        *bool
+	GrowFileSystem  *bool
+	SplitName       string
+	MountPoint      []string
+	EncryptedVolume string
+
+	// Unknown holds the options this package does not know, which are
+	// written back unchanged.
+	Unknown []*unit.UnitOption
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "1", "yes", "y", "true", "t", "on":
+		return true, nil
+	case "0", "no", "n", "false", "f", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+var sizeSuffixes = []struct {
+	suffix string
+	shift  uint
+}{{"E", 60}, {"P", 50}, {"T", 40}, {"G", 30}, {"M", 20}, {"K", 10}, {"B", 0}}
+
+// ParseSize parses a size in bytes with an optional base 1024 suffix, e.g.
+// "512M".
+func ParseSize(s string) (uint64, error) {
+	num := s
+	var shift uint
+	for _, suf := range sizeSuffixes {
+		if strings.HasSuffix(s, suf.suffix) {
+			num, shift = strings.TrimSuffix(s, suf.suffix), suf.shift
+			break
+		}
+	}
+	n, err := strconv.ParseUint(num, 10, 64)
+	if err != nil || (shift > 0 && n > (^uint64(0))>>shift) {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n << shift, nil
+}
+
+// FormatSize formats a size with the largest suffix it is a multiple of.
+func FormatSize(n uint64) string {
+	for _, suf := range sizeSuffixes {
+		if suf.shift > 0 && n != 0 && n%(1<<suf.shift) == 0 {
+			return strconv.FormatUint(n>>suf.shift, 10) + suf.suffix
+		}
+	}
+	return strconv.FormatUint(n, 10)
+}
+
+// ParsePartition parses a repart.d definition.
+func ParsePartition(r io.Reader) (*Partition, error) {
+	opts, err := unit.DeserializeOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &Partition{}
+	for _, o := range opts {
+		if err := p.set(o.Section, o.Name, o.Value); err != nil {
+			return nil, fmt.Errorf("[%s] %s=%s: %v", o.Section, o.Name, o.Value, err)
+		}
+	}
+	return p, nil
+}
+
+func (p *Partition) set(section, name, value string) error {
+	if section != "Partition" {
+		p.Unknown = append(p.Unknown, unit.NewUnitOption(section, name, value))
+		return nil
+	}
+
+	setBool := func(dst **bool) error {
+		if value == "" {
+			*dst = nil
+			return nil
+		}
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		*dst = &b
+		return nil
+	}
+	setSize := func(dst *uint64) error {
+		if value == "" {
+			*dst = 0
+			return nil
+		}
+		n, err := ParseSize(value)
+		*dst = n
+		return err
+	}
+	setWeight := func(dst **uint32) error {
+		if value == "" {
+			*dst = nil
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || n > 1000*1000 {
+			return fmt.Errorf("invalid weight")
+		}
+		w := uint32(n)
+		*dst = &w
+		return nil
+	}
+	words := func(dst *[]string) error {
+		if value == "" {
+			*dst = nil
+			return nil
+		}
+		w, err := unit.SplitWords(value)
+		if err != nil {
+			return err
+		}
+		*dst = append(*dst, w...)
+		return nil
+	}
+
+	switch name {
+	case "Type":
+		p.Type = value
+	case "Label":
+		p.Label = value
+	case "UUID":
+		p.UUID = value
+	case "Priority":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		p.Priority = n
+	case "Weight":
+		return setWeight(&p.Weight)
+	case "PaddingWeight":
+		return setWeight(&p.PaddingWeight)
+	case "SizeMinBytes":
+		return setSize(&p.SizeMinBytes)
+	case "SizeMaxBytes":
+		return setSize(&p.SizeMaxBytes)
+	case "PaddingMinBytes":
+		return setSize(&p.PaddingMinBytes)
+	case "PaddingMaxBytes":
+		return setSize(&p.PaddingMaxBytes)
+	case "CopyBlocks":
+		p.CopyBlocks = value
+	case "Format":
+		p.Format = value
+	case "CopyFiles":
+		if value == "" {
+			p.CopyFiles = nil
+			return nil
+		}
+		// A colon separates source and target, as in "/usr/share:/usr".
+		c := CopyFiles{Source: value}
+		if i := strings.IndexByte(value, ':'); i >= 0 {
+			c = CopyFiles{Source: value[:i], Target: value[i+1:]}
+		}
+		p.CopyFiles = append(p.CopyFiles, c)
+	case "ExcludeFiles":
+		return words(&p.ExcludeFiles)
+	case "ExcludeFilesTarget":
+		return words(&p.ExcludeFilesTarget)
+	case "MakeDirectories":
+		return words(&p.MakeDirectories)
+	case "Subvolumes":
+		return words(&p.Subvolumes)
+	case "Minimize":
+		p.Minimize = value
+	case "Compression":
+		p.Compression = value
+	case "CompressionLevel":
+		p.CompressionLevel = value
+	case "Encrypt":
+		p.Encrypt = value
+	case "Verity":
+		if value == "off" {
+			value = ""
+		}
+		p.Verity = Verity(value)
+	case "VerityMatchKey":
+		p.VerityMatchKey = value
+	case "VerityDataBlockSizeBytes":
+		return setSize(&p.VerityDataBlockSizeBytes)
+	case "VerityHashBlockSizeBytes":
+		return setSize(&p.VerityHashBlockSizeBytes)
+	case "FactoryReset":
+		return setBool(&p.FactoryReset)
+	case "Flags":
+		if value == "" {
+			p.Flags = nil
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 0, 64)
+		if err != nil {
+			return err
+		}
+		p.Flags = &n
+	case "NoAuto":
+		return setBool(&p.NoAuto)
+	case "ReadOnly":
+		return setBool(&p.ReadOnly)
+	case "GrowFileSystem":
+		return setBool(&p.GrowFileSystem)
+	case "SplitName":
+		p.SplitName = value
+	case "MountPoint":
+		if value == "" {
+			p.MountPoint = nil
+		} else {
+			p.MountPoint = append(p.MountPoint, value)
+		}
+	case "EncryptedVolume":
+		p.EncryptedVolume = value
+	default:
+		p.Unknown = append(p.Unknown, unit.NewUnitOption(section, name, value))
+	}
+	return nil
+}
+
+// Validate checks the definition for settings systemd-repart rejects.
+func (p *Partition) Validate() error {
+	if p.Type == "" {
+		return fmt.Errorf("Type= is required")
+	}
+	if !ValidType(p.Type) {
+		return fmt.Errorf("unknown partition type %q", p.Type)
+	}
+	if p.UUID != "" && p.UUID != "null" && !uuidRegexp.MatchString(p.UUID) {
+		return fmt.Errorf("invalid partition UUID %q", p.UUID)
+	}
+	if p.SizeMaxBytes != 0 && p.SizeMinBytes > p.SizeMaxBytes {
+		return fmt.Errorf("SizeMinBytes= is larger than SizeMaxBytes=")
+	}
+	if p.PaddingMaxBytes != 0 && p.PaddingMinBytes > p.PaddingMaxBytes {
+		return fmt.Errorf("PaddingMinBytes= is larger than PaddingMaxBytes=")
+	}
+	if p.CopyBlocks != "" && p.Format != "" {
+		return fmt.Errorf("CopyBlocks= and Format= cannot be combined")
+	}
+	if (len(p.CopyFiles) > 0 || len(p.MakeDirectories) > 0) && p.Format == "" {
+		return fmt.Errorf("CopyFiles= and MakeDirectories= require Format=")
+	}
+	for _, c := range p.CopyFiles {
+		if !strings.HasPrefix(c.Source, "/") || (c.Target != "" && !strings.HasPrefix(c.Target, "/")) {
+			return fmt.Errorf("CopyFiles= paths must be absolute")
+		}
+	}
+	switch p.Minimize {
+	case "", "off", "best", "guess":
+	default:
+		return fmt.Errorf("invalid Minimize= mode %q", p.Minimize)
+	}
+	if p.Minimize != "" && p.Minimize != "off" && p.Format == "" {
+		return fmt.Errorf("Minimize= requires Format=")
+	}
+	switch p.Encrypt {
+	case "", "off", "key-file", "tpm2", "key-file+tpm2":
+	default:
+		return fmt.Errorf("invalid Encrypt= mode %q", p.Encrypt)
+	}
+	switch p.Verity {
+	case VerityOff:
+	case VerityData, VerityHash, VeritySignature:
+		if p.VerityMatchKey == "" {
+			return fmt.Errorf("Verity=%s requires VerityMatchKey=", p.Verity)
+		}
+		if p.Verity == VerityHash && !strings.Contains(p.Type, "-verity") {
+			return fmt.Errorf("Verity=hash requires a verity partition type")
+		}
+		if p.Verity == VeritySignature && !strings.Contains(p.Type, "-verity-sig") {
+			return fmt.Errorf("Verity=signature requires a verity signature partition type")
+		}
+	default:
+		return fmt.Errorf("invalid Verity= mode %q", p.Verity)
+	}
+	for _, n := range []uint64{p.VerityDataBlockSizeBytes, p.VerityHashBlockSizeBytes} {
+		if n != 0 && (n < 512 || n > 4096 || n&(n-1) != 0) {
+			return fmt.Errorf("verity block sizes must be a power of two between 512 and 4096")
+		}
+	}
+	return nil
+}
+
+// Sections returns the definition as sections of a repart.d file.
+func (p *Partition) Sections() []*unit.UnitSection {
+	s := &unit.UnitSection{Section: "Partition"}
+	add := func(name, value string) {
+		if value != "" {
+			s.Entries = append(s.Entries, &unit.UnitEntry{Name: name, Value: value})
+		}
+	}
+	addBool := func(name string, b *bool) {
+		if b != nil {
+			add(name, formatBool(*b))
+		}
+	}
+	addSize := func(name string, n uint64) {
+		if n != 0 {
+			add(name, FormatSize(n))
+		}
+	}
+	addWeight := func(name string, w *uint32) {
+		if w != nil {
+			add(name, strconv.FormatUint(uint64(*w), 10))
+		}
+	}
+	addEach := func(name string, values []string) {
+		for _, v := range values {
+			add(name, v)
+		}
+	}
+
+	add("Type", p.Type)
+	add("Label", p.Label)
+	add("UUID", p.UUID)
+	if p.Priority != 0 {
+		add("Priority", strconv.Itoa(p.Priority))
+	}
+	addWeight("Weight", p.Weight)
+	addWeight("PaddingWeight", p.PaddingWeight)
+	addSize("SizeMinBytes", p.SizeMinBytes)
+	addSize("SizeMaxBytes", p.SizeMaxBytes)
+	addSize("PaddingMinBytes", p.PaddingMinBytes)
+	addSize("PaddingMaxBytes", p.PaddingMaxBytes)
+	add("CopyBlocks", p.CopyBlocks)
+	add("Format", p.Format)
+	for _, c := range p.CopyFiles {
+		if c.Target != "" {
+			add("CopyFiles", c.Source+":"+c.Target)
+		} else {
+			add("CopyFiles", c.Source)
+		}
+	}
+	addEach("ExcludeFiles", p.ExcludeFiles)
+	addEach("ExcludeFilesTarget", p.ExcludeFilesTarget)
+	addEach("MakeDirectories", p.MakeDirectories)
+	addEach("Subvolumes", p.Subvolumes)
+	add("Minimize", p.Minimize)
+	add("Compression", p.Compression)
+	add("CompressionLevel", p.CompressionLevel)
+	add("Encrypt", p.Encrypt)
+	add("Verity", string(p.Verity))
+	add("VerityMatchKey", p.VerityMatchKey)
+	addSize("VerityDataBlockSizeBytes", p.VerityDataBlockSizeBytes)
+	addSize("VerityHashBlockSizeBytes", p.VerityHashBlockSizeBytes)
+	addBool("FactoryReset", p.FactoryReset)
+	if p.Flags != nil {
+		add("Flags", fmt.Sprintf("0x%x", *p.Flags))
+	}
+	addBool("NoAuto", p.NoAuto)
+	addBool("ReadOnly", p.ReadOnly)
+	addBool("GrowFileSystem", p.GrowFileSystem)
+	add("SplitName", p.SplitName)
+	addEach("MountPoint", p.MountPoint)
+	add("EncryptedVolume", p.EncryptedVolume)
+
+	sections := []*unit.UnitSection{s}
+	for _, o := range p.Unknown {
+		var sec *unit.UnitSection
+		for _, existing := range sections {
+			if existing.Section == o.Section {
+				sec = existing
+			}
+		}
+		if sec == nil {
+			sec = &unit.UnitSection{Section: o.Section}
+			sections = append(sections, sec)
+		}
+		sec.Entries = append(sec.Entries, &unit.UnitEntry{Name: o.Name, Value: o.Value})
+	}
+	return sections
+}
+
+// Serialize validates and encodes the definition.
+func (p *Partition) Serialize() (io.Reader, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return unit.SerializeSections(p.Sections()), nil
+}
+
+// ReadPartition reads the repart.d definition at path.
+func ReadPartition(path string) (*Partition, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePartition(bytes.NewReader(b))
+}
+
+// WritePartition validates the definition and writes it to path, which
+// should be named like "10-root.conf" as definitions are applied in the
+// order of their file names.
+func WritePartition(path string, p *Partition) error {
+	r, err := p.Serialize()
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repart
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testPartition = `[Partition]
+Type=root-x86-64
+Label=root-%A
+SizeMinBytes=512M
+SizeMaxBytes=2G
+Format=ext4
+CopyFiles=/usr/share/factory/etc:/etc
+CopyFiles=/srv
+MakeDirectories=/home /var
+Verity=data
+VerityMatchKey=root
+ReadOnly=yes
+Flags=0x1000000000000000
+Future=1
+`
+
+func TestParsePartition(t *testing.T) {
+	p, err := ParsePartition(strings.NewReader(testPartition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Type != "root-x86-64" || p.Label != "root-%A" || p.Format != "ext4" {
+		t.Errorf("unexpected strings %q %q %q", p.Type, p.Label, p.Format)
+	}
+	if p.SizeMinBytes != 512<<20 || p.SizeMaxBytes != 2<<30 {
+		t.Errorf("unexpected sizes %d %d", p.SizeMinBytes, p.SizeMaxBytes)
+	}
+	wantCopy := []CopyFiles{{Source: "/usr/share/factory/etc", Target: "/etc"}, {Source: "/srv"}}
+	if !reflect.DeepEqual(p.CopyFiles, wantCopy) {
+		t.Errorf("unexpected CopyFiles %v", p.CopyFiles)
+	}
+	if !reflect.DeepEqual(p.MakeDirectories, []string{"/home", "/var"}) {
+		t.Errorf("unexpected MakeDirectories %q", p.MakeDirectories)
+	}
+	if p.Verity != VerityData || p.VerityMatchKey != "root" {
+		t.Errorf("unexpected verity %q %q", p.Verity, p.VerityMatchKey)
+	}
+	if p.ReadOnly == nil || !*p.ReadOnly || p.NoAuto != nil {
+		t.Errorf("unexpected booleans %v %v", p.ReadOnly, p.NoAuto)
+	}
+	if p.Flags == nil || *p.Flags != 1<<60 {
+		t.Errorf("unexpected flags %v", p.Flags)
+	}
+	if len(p.Unknown) != 1 || p.Unknown[0].Name != "Future" {
+		t.Errorf("unexpected unknown options %v", p.Unknown)
+	}
+	if err := p.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	r, err := p.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := ParsePartition(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, p2) {
+		t.Errorf("definition changed in round trip:\n%s", b)
+	}
+}
+
+func TestParsePartitionErrors(t *testing.T) {
+	for i, s := range []string{
+		"[Partition]\nSizeMinBytes=1X\n",
+		"[Partition]\nSizeMinBytes=99999999999999999999\n",
+		"[Partition]\nWeight=-1\n",
+		"[Partition]\nReadOnly=maybe\n",
+		"[Partition]\nPriority=high\n",
+	} {
+		if _, err := ParsePartition(strings.NewReader(s)); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestSize(t *testing.T) {
+	for i, tt := range []struct {
+		in   string
+		n    uint64
+		want string
+	}{
+		{"4096", 4096, "4K"},
+		{"1000", 1000, "1000"},
+		{"1K", 1024, "1K"},
+		{"1536M", 1536 << 20, "1536M"},
+		{"3G", 3 << 30, "3G"},
+		{"16E", 0, ""},
+	} {
+		n, err := ParseSize(tt.in)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("case %d: expected error", i)
+			}
+			continue
+		}
+		if err != nil || n != tt.n {
+			t.Errorf("case %d: got %d, %v", i, n, err)
+		}
+		if s := FormatSize(n); s != tt.want {
+			t.Errorf("case %d: formatted as %q", i, s)
+		}
+	}
+}
+
+func TestValidType(t *testing.T) {
+	for i, tt := range []struct {
+		in   string
+		want bool
+	}{
+		{"esp", true},
+		{"root", true},
+		{"usr-verity-sig-arm64", true},
+		{"4f68bce3-e8cd-4db1-96e7-fbcaf984b709", true},
+		{"esp-x86-64", false},
+		{"root-sparc", false},
+		{"rootfs", false},
+	} {
+		if got := ValidType(tt.in); got != tt.want {
+			t.Errorf("case %d: got %v", i, got)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for i, tt := range []struct {
+		p  Partition
+		ok bool
+	}{
+		{Partition{Type: "esp", Format: "vfat", SizeMinBytes: 512 << 20}, true},
+		{Partition{Type: "root-verity", Verity: VerityHash, VerityMatchKey: "root"}, true},
+		{Partition{}, false},
+		{Partition{Type: "swap", SizeMinBytes: 2 << 30, SizeMaxBytes: 1 << 30}, false},
+		{Partition{Type: "root", CopyBlocks: "auto", Format: "ext4"}, false},
+		{Partition{Type: "root", CopyFiles: []CopyFiles{{Source: "/etc"}}}, false},
+		{Partition{Type: "root", Format: "ext4", CopyFiles: []CopyFiles{{Source: "etc"}}}, false},
+		{Partition{Type: "root", Verity: VerityData}, false},
+		{Partition{Type: "root", Verity: VerityHash, VerityMatchKey: "root"}, false},
+		{Partition{Type: "root-verity", Verity: VeritySignature, VerityMatchKey: "root"}, false},
+		{Partition{Type: "root", Encrypt: "password"}, false},
+		{Partition{Type: "root", Minimize: "best"}, false},
+		{Partition{Type: "root", UUID: "nope"}, false},
+		{Partition{Type: "root", VerityDataBlockSizeBytes: 1000}, false},
+	} {
+		err := tt.p.Validate()
+		if (err == nil) != tt.ok {
+			t.Errorf("case %d: unexpected result %v", i, err)
+		}
+	}
+}
+
+func TestWritePartition(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repart")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "10-esp.conf")
+	w := uint32(0)
+	p := &Partition{Type: "esp", Format: "vfat", SizeMinBytes: 512 << 20, Weight: &w}
+	if err := WritePartition(path, p); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(path)
+	if want := "[Partition]\nType=esp\nWeight=0\nSizeMinBytes=512M\nFormat=vfat\n"; string(b) != want {
+		t.Errorf("unexpected file %q", b)
+	}
+	got, err := ReadPartition(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("got %+v", got)
+	}
+
+	if err := WritePartition(filepath.Join(dir, "20-bad.conf"), &Partition{}); err == nil {
+		t.Error("expected error for invalid definition")
+	}
+}
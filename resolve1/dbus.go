@@ -0,0 +1,449 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resolve1 provides integration with the systemd resolved API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.resolve1.html
+package resolve1
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.resolve1"
+	dbusInterface = "org.freedesktop.resolve1.Manager"
+	dbusPath      = "/org/freedesktop/resolve1"
+)
+
+// Flags for the resolve calls. The first group restricts or alters how a
+// lookup is done, the second one is only set on results and describes how
+// the data was obtained.
+const (
+	FlagDNS           uint64 = 1 << 0  // Look up via unicast DNS
+	FlagLLMNRIPv4     uint64 = 1 << 1  // Look up via LLMNR over IPv4
+	FlagLLMNRIPv6     uint64 = 1 << 2  // Look up via LLMNR over IPv6
+	FlagMulticastIPv4 uint64 = 1 << 3  // Look up via multicast DNS over IPv4
+	FlagMulticastIPv6 uint64 = 1 << 4  // Look up via multicast DNS over IPv6
+	FlagNoCNAME       uint64 = 1 << 5  // Do not follow CNAME redirects
+	FlagNoTXT         uint64 = 1 << 6  // Do not resolve the TXT records of a service
+	FlagNoAddress     uint64 = 1 << 7  // Do not resolve the addresses of a service
+	FlagNoSearch      uint64 = 1 << 8  // Do not apply search domains to single-label names
+	FlagNoValidate    uint64 = 1 << 10 // Do not validate the result with DNSSEC
+	FlagNoSynthesize  uint64 = 1 << 11 // Do not synthesize local records, e.g. for the own hostname
+	FlagNoCache       uint64 = 1 << 12 // Do not answer from the cache
+	FlagNoZone        uint64 = 1 << 13 // Do not answer from locally registered records
+	FlagNoTrustAnchor uint64 = 1 << 14 // Do not answer from the DNSSEC trust anchor
+	FlagNoNetwork     uint64 = 1 << 15 // Do not send queries to the network
+
+	FlagAuthenticated   uint64 = 1 << 9  // The result was validated with DNSSEC
+	FlagConfidential    uint64 = 1 << 18 // The result was only transmitted over encrypted channels
+	FlagSynthetic       uint64 = 1 << 19 // The result was synthesized locally
+	FlagFromCache       uint64 = 1 << 20 // The result was answered from the cache
+	FlagFromZone        uint64 = 1 << 21 // The result was answered from locally registered records
+	FlagFromTrustAnchor uint64 = 1 << 22 // The result was answered from the DNSSEC trust anchor
+	FlagFromNetwork     uint64 = 1 << 23 // The result was received from the network
+)
+
+// Address is an IP address of a resolved name.
+type Address struct {
+	IfIndex int32 // The network interface the address was found on, or 0
+	IP      net.IP
+}
+
+// HostnameResult is the result of ResolveHostname.
+type HostnameResult struct {
+	Addresses     []Address
+	CanonicalName string // The name after following CNAME redirects
+	Flags         uint64
+}
+
+// Authenticated returns whether the result was validated with DNSSEC.
+func (r *HostnameResult) Authenticated() bool {
+	return r.Flags&FlagAuthenticated != 0
+}
+
+// Name is a hostname an address resolved to.
+type Name struct {
+	IfIndex int32 // The network interface the name was found on, or 0
+	Name    string
+}
+
+// AddressResult is the result of ResolveAddress.
+type AddressResult struct {
+	Names []Name
+	Flags uint64
+}
+
+// Authenticated returns whether the result was validated with DNSSEC.
+func (r *AddressResult) Authenticated() bool {
+	return r.Flags&FlagAuthenticated != 0
+}
+
+// Record is a raw DNS resource record.
+type Record struct {
+	IfIndex int32 // The network interface the record was found on, or 0
+	Class   uint16
+	Type    uint16
+	Data    []byte // The full record in DNS wire format, including its header
+}
+
+// RecordResult is the result of ResolveRecord.
+type RecordResult struct {
+	Records []Record
+	Flags   uint64
+}
+
+// Authenticated returns whether the result was validated with DNSSEC.
+func (r *RecordResult) Authenticated() bool {
+	return r.Flags&FlagAuthenticated != 0
+}
+
+// SRV is a service location found by ResolveService.
+type SRV struct {
+	Priority      uint16
+	Weight        uint16
+	Port          uint16
+	Hostname      string
+	Addresses     []Address // The addresses of Hostname, unless FlagNoAddress was passed
+	CanonicalName string    // The canonical name of Hostname
+}
+
+// ServiceResult is the result of ResolveService.
+type ServiceResult struct {
+	Services        []SRV
+	TXT             [][]byte // The TXT record strings, unless FlagNoTXT was passed
+	CanonicalName   string   // The instance name of a DNS-SD service, if any
+	CanonicalType   string   // The service type, e.g. _http._tcp
+	CanonicalDomain string
+	Flags           uint64
+}
+
+// Authenticated returns whether the result was validated with DNSSEC.
+func (r *ServiceResult) Authenticated() bool {
+	return r.Flags&FlagAuthenticated != 0
+}
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+func (c *Conn) getStringProperty(ctx context.Context, property string) (string, error) {
+	var v dbus.Variant
+	if err := c.object.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, dbusInterface, property).Store(&v); err != nil {
+		return "", err
+	}
+	s, ok := v.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("failed to typecast %s property %s to string", property, v)
+	}
+	return s, nil
+}
+
+// LLMNR returns the global LLMNR mode: SettingYes, SettingNo or SettingResolve.
+func (c *Conn) LLMNR(ctx context.Context) (string, error) {
+	return c.getStringProperty(ctx, "LLMNR")
+}
+
+// MulticastDNS returns the global multicast DNS mode: SettingYes, SettingNo
+// or SettingResolve.
+func (c *Conn) MulticastDNS(ctx context.Context) (string, error) {
+	return c.getStringProperty(ctx, "MulticastDNS")
+}
+
+// LLMNRHostname returns the hostname announced via LLMNR and multicast DNS.
+func (c *Conn) LLMNRHostname(ctx context.Context) (string, error) {
+	return c.getStringProperty(ctx, "LLMNRHostname")
+}
+
+// DNSSEC returns the global DNSSEC mode.
+func (c *Conn) DNSSEC(ctx context.Context) (string, error) {
+	return c.getStringProperty(ctx, "DNSSEC")
+}
+
+// DNSOverTLS returns the global DNS-over-TLS mode.
+func (c *Conn) DNSOverTLS(ctx context.Context) (string, error) {
+	return c.getStringProperty(ctx, "DNSOverTLS")
+}
+
+// ResolveHostname resolves a hostname to its IP addresses. ifindex limits the
+// lookup to a network interface, family to syscall.AF_INET or
+// syscall.AF_INET6; pass 0 for either to not restrict the lookup.
+func (c *Conn) ResolveHostname(ctx context.Context, ifindex int, name string, family int, flags uint64) (*HostnameResult, error) {
+	var addresses [][]interface{}
+	result := &HostnameResult{}
+	err := c.object.CallWithContext(ctx, dbusInterface+".ResolveHostname", 0, int32(ifindex), name, int32(family), flags).Store(&addresses, &result.CanonicalName, &result.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Addresses, err = addressesFromInterfaces(addresses)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ResolveAddress resolves an IP address to its hostnames. ifindex limits the
+// lookup to a network interface, pass 0 to not restrict it.
+func (c *Conn) ResolveAddress(ctx context.Context, ifindex int, ip net.IP, flags uint64) (*AddressResult, error) {
+	family, addr := familyOf(ip)
+	if family == 0 {
+		return nil, fmt.Errorf("invalid IP address %v", ip)
+	}
+
+	var names [][]interface{}
+	result := &AddressResult{}
+	err := c.object.CallWithContext(ctx, dbusInterface+".ResolveAddress", 0, int32(ifindex), family, []byte(addr), flags).Store(&names, &result.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range names {
+		name, err := nameFromInterfaces(n)
+		if err != nil {
+			return nil, err
+		}
+		result.Names = append(result.Names, *name)
+	}
+	return result, nil
+}
+
+// ResolveRecord looks up the resource records of a name with the given DNS
+// class and type, e.g. 1 (IN) and 16 (TXT). ifindex limits the lookup to a
+// network interface, pass 0 to not restrict it.
+func (c *Conn) ResolveRecord(ctx context.Context, ifindex int, name string, class, rrType uint16, flags uint64) (*RecordResult, error) {
+	var records [][]interface{}
+	result := &RecordResult{}
+	err := c.object.CallWithContext(ctx, dbusInterface+".ResolveRecord", 0, int32(ifindex), name, class, rrType, flags).Store(&records, &result.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range records {
+		record, err := recordFromInterfaces(r)
+		if err != nil {
+			return nil, err
+		}
+		result.Records = append(result.Records, *record)
+	}
+	return result, nil
+}
+
+// ResolveService resolves a DNS-SD or plain SRV service. For DNS-SD, pass the
+// instance name, the service type (e.g. "_ipp._tcp") and the domain; for
+// plain SRV lookups leave the name empty. family restricts the addresses
+// resolved for the services, pass 0 to not restrict them.
+func (c *Conn) ResolveService(ctx context.Context, ifindex int, name, serviceType, domain string, family int, flags uint64) (*ServiceResult, error) {
+	var services [][]interface{}
+	result := &ServiceResult{}
+	err := c.object.CallWithContext(ctx, dbusInterface+".ResolveService", 0, int32(ifindex), name, serviceType, domain, int32(family), flags).
+		Store(&services, &result.TXT, &result.CanonicalName, &result.CanonicalType, &result.CanonicalDomain, &result.Flags)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range services {
+		srv, err := srvFromInterfaces(s)
+		if err != nil {
+			return nil, err
+		}
+		result.Services = append(result.Services, *srv)
+	}
+	return result, nil
+}
+
+func familyOf(ip net.IP) (int32, net.IP) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return syscall.AF_INET, ip4
+	}
+	if ip16 := ip.To16(); ip16 != nil {
+		return syscall.AF_INET6, ip16
+	}
+	return 0, nil
+}
+
+func addressesFromInterfaces(addresses [][]interface{}) ([]Address, error) {
+	var ret []Address
+	for _, a := range addresses {
+		address, err := addressFromInterfaces(a)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, *address)
+	}
+	return ret, nil
+}
+
+func addressFromInterfaces(address []interface{}) (*Address, error) {
+	if len(address) < 3 {
+		return nil, fmt.Errorf("invalid number of address fields: %d", len(address))
+	}
+	ifindex, ok := address[0].(int32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast address field 0 to int32")
+	}
+	family, ok := address[1].(int32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast address field 1 to int32")
+	}
+	addr, ok := address[2].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast address field 2 to []byte")
+	}
+	if (family == syscall.AF_INET && len(addr) != net.IPv4len) || (family == syscall.AF_INET6 && len(addr) != net.IPv6len) {
+		return nil, fmt.Errorf("invalid address length %d for family %d", len(addr), family)
+	}
+	return &Address{IfIndex: ifindex, IP: net.IP(addr)}, nil
+}
+
+func nameFromInterfaces(name []interface{}) (*Name, error) {
+	if len(name) < 2 {
+		return nil, fmt.Errorf("invalid number of name fields: %d", len(name))
+	}
+	ifindex, ok := name[0].(int32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast name field 0 to int32")
+	}
+	n, ok := name[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast name field 1 to string")
+	}
+	return &Name{IfIndex: ifindex, Name: n}, nil
+}
+
+func recordFromInterfaces(record []interface{}) (*Record, error) {
+	if len(record) < 4 {
+		return nil, fmt.Errorf("invalid number of record fields: %d", len(record))
+	}
+	ifindex, ok := record[0].(int32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast record field 0 to int32")
+	}
+	class, ok := record[1].(uint16)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast record field 1 to uint16")
+	}
+	rrType, ok := record[2].(uint16)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast record field 2 to uint16")
+	}
+	data, ok := record[3].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast record field 3 to []byte")
+	}
+	return &Record{IfIndex: ifindex, Class: class, Type: rrType, Data: data}, nil
+}
+
+func srvFromInterfaces(srv []interface{}) (*SRV, error) {
+	if len(srv) < 6 {
+		return nil, fmt.Errorf("invalid number of service fields: %d", len(srv))
+	}
+	ret := &SRV{}
+	var ok bool
+	ret.Priority, ok = srv[0].(uint16)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast service field 0 to uint16")
+	}
+	ret.Weight, ok = srv[1].(uint16)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast service field 1 to uint16")
+	}
+	ret.Port, ok = srv[2].(uint16)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast service field 2 to uint16")
+	}
+	ret.Hostname, ok = srv[3].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast service field 3 to string")
+	}
+	addresses, ok := srv[4].([][]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast service field 4 to [][]interface{}")
+	}
+	ret.CanonicalName, ok = srv[5].(string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast service field 5 to string")
+	}
+
+	var err error
+	ret.Addresses, err = addressesFromInterfaces(addresses)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// TestNew ensures that New() works without errors.
+func TestNew(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestResolveHostname(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := conn.ResolveHostname(ctx, 0, "localhost", syscall.AF_INET, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Addresses) == 0 || !result.Addresses[0].IP.IsLoopback() {
+		t.Fatalf("unexpected addresses for localhost: %+v", result.Addresses)
+	}
+	if result.Flags&FlagSynthetic == 0 {
+		t.Fatal("localhost result not marked as synthetic")
+	}
+}
+
+func TestResolveAddress(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := conn.ResolveAddress(ctx, 0, net.IPv4(127, 0, 0, 1), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Names) == 0 {
+		t.Fatal("no names returned for 127.0.0.1")
+	}
+}
+
+func TestSRVFromInterfaces(t *testing.T) {
+	srv, err := srvFromInterfaces([]interface{}{
+		uint16(10), uint16(5), uint16(631), "printer.local",
+		[][]interface{}{{int32(2), int32(syscall.AF_INET), []byte{192, 0, 2, 7}}},
+		"printer.local",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.Priority != 10 || srv.Weight != 5 || srv.Port != 631 || srv.Hostname != "printer.local" {
+		t.Fatalf("unexpected service %+v", srv)
+	}
+	if len(srv.Addresses) != 1 || srv.Addresses[0].IfIndex != 2 || !srv.Addresses[0].IP.Equal(net.IPv4(192, 0, 2, 7)) {
+		t.Fatalf("unexpected service addresses %+v", srv.Addresses)
+	}
+
+	if _, err := addressFromInterfaces([]interface{}{int32(0), int32(syscall.AF_INET6), []byte{1, 2, 3, 4}}); err == nil {
+		t.Fatal("expected error for IPv4-sized IPv6 address")
+	}
+}
+
+func TestGlobalSettings(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	for name, get := range map[string]func(context.Context) (string, error){
+		"LLMNR":        conn.LLMNR,
+		"MulticastDNS": conn.MulticastDNS,
+	} {
+		mode, err := get(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		switch mode {
+		case SettingYes, SettingNo, SettingResolve:
+		default:
+			t.Fatalf("%s: unexpected mode %q", name, mode)
+		}
+	}
+}
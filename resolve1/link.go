@@ -0,0 +1,149 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// DNSServer is a DNS server configured for a link.
+type DNSServer struct {
+	IP         net.IP
+	Port       uint16 // The server port, or 0 for the default port
+	ServerName string // The name used for DNS-over-TLS certificate validation (SNI), if any
+}
+
+// Domain is a search or routing domain configured for a link.
+type Domain struct {
+	Name string
+	// RoutingOnly marks the domain as only used to route lookups for names
+	// below it to the link, not as a search domain for single-label names.
+	// Use "." with RoutingOnly to route all lookups to the link.
+	RoutingOnly bool
+}
+
+// The D-Bus argument structs of the SetLink* calls.
+type (
+	linkDNS struct {
+		Family  int32
+		Address []byte
+	}
+	linkDNSEx struct {
+		Family     int32
+		Address    []byte
+		Port       uint16
+		ServerName string
+	}
+	linkDomain struct {
+		Domain      string
+		RoutingOnly bool
+	}
+)
+
+// Settings for SetLinkDNSOverTLS, SetLinkDNSSEC, SetLinkLLMNR and SetLinkMulticastDNS.
+// An empty string reverts to the global setting.
+const (
+	SettingYes            = "yes"
+	SettingNo             = "no"
+	SettingOpportunistic  = "opportunistic"   // DNSOverTLS only
+	SettingAllowDowngrade = "allow-downgrade" // DNSSEC only
+	SettingResolve        = "resolve"         // LLMNR and MulticastDNS only: resolve, but do not respond
+)
+
+// SetLinkDNS sets the DNS servers of a link. Use SetLinkDNSEx to also set
+// ports and DNS-over-TLS server names.
+func (c *Conn) SetLinkDNS(ctx context.Context, ifindex int, servers []net.IP) error {
+	addresses := make([]linkDNS, 0, len(servers))
+	for _, ip := range servers {
+		family, addr := familyOf(ip)
+		if family == 0 {
+			return fmt.Errorf("invalid DNS server address %v", ip)
+		}
+		addresses = append(addresses, linkDNS{family, addr})
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkDNS", 0, int32(ifindex), addresses).Err
+}
+
+// SetLinkDNSEx sets the DNS servers of a link, including their ports and
+// DNS-over-TLS server names.
+// Note: Requires systemd v246 or higher
+func (c *Conn) SetLinkDNSEx(ctx context.Context, ifindex int, servers []DNSServer) error {
+	addresses := make([]linkDNSEx, 0, len(servers))
+	for _, s := range servers {
+		family, addr := familyOf(s.IP)
+		if family == 0 {
+			return fmt.Errorf("invalid DNS server address %v", s.IP)
+		}
+		addresses = append(addresses, linkDNSEx{family, addr, s.Port, s.ServerName})
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkDNSEx", 0, int32(ifindex), addresses).Err
+}
+
+// SetLinkDomains sets the search and routing domains of a link.
+func (c *Conn) SetLinkDomains(ctx context.Context, ifindex int, domains []Domain) error {
+	args := make([]linkDomain, 0, len(domains))
+	for _, d := range domains {
+		args = append(args, linkDomain{d.Name, d.RoutingOnly})
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkDomains", 0, int32(ifindex), args).Err
+}
+
+// SetLinkDefaultRoute sets whether lookups not matching any routing domain
+// are sent to the DNS servers of a link.
+// Note: Requires systemd v240 or higher
+func (c *Conn) SetLinkDefaultRoute(ctx context.Context, ifindex int, enable bool) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkDefaultRoute", 0, int32(ifindex), enable).Err
+}
+
+// SetLinkDNSOverTLS sets the DNS-over-TLS mode of a link: SettingYes,
+// SettingNo, SettingOpportunistic or empty for the global setting.
+func (c *Conn) SetLinkDNSOverTLS(ctx context.Context, ifindex int, mode string) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkDNSOverTLS", 0, int32(ifindex), mode).Err
+}
+
+// SetLinkDNSSEC sets the DNSSEC mode of a link: SettingYes, SettingNo,
+// SettingAllowDowngrade or empty for the global setting.
+func (c *Conn) SetLinkDNSSEC(ctx context.Context, ifindex int, mode string) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkDNSSEC", 0, int32(ifindex), mode).Err
+}
+
+// SetLinkDNSSECNegativeTrustAnchors sets the domains for which DNSSEC
+// validation is turned off on a link.
+func (c *Conn) SetLinkDNSSECNegativeTrustAnchors(ctx context.Context, ifindex int, domains []string) error {
+	if domains == nil {
+		domains = []string{}
+	}
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkDNSSECNegativeTrustAnchors", 0, int32(ifindex), domains).Err
+}
+
+// SetLinkLLMNR sets the LLMNR mode of a link: SettingYes, SettingNo,
+// SettingResolve or empty for the global setting.
+func (c *Conn) SetLinkLLMNR(ctx context.Context, ifindex int, mode string) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkLLMNR", 0, int32(ifindex), mode).Err
+}
+
+// SetLinkMulticastDNS sets the multicast DNS mode of a link: SettingYes,
+// SettingNo, SettingResolve or empty for the global setting. Responding
+// on a link also requires multicast DNS to be enabled globally.
+func (c *Conn) SetLinkMulticastDNS(ctx context.Context, ifindex int, mode string) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".SetLinkMulticastDNS", 0, int32(ifindex), mode).Err
+}
+
+// RevertLink resets all DNS settings of a link made through this API.
+func (c *Conn) RevertLink(ctx context.Context, ifindex int) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".RevertLink", 0, int32(ifindex)).Err
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestLinkArgumentSignatures(t *testing.T) {
+	for _, tt := range []struct {
+		arg  interface{}
+		want string
+	}{
+		{[]linkDNS{}, "a(iay)"},
+		{[]linkDNSEx{}, "a(iayqs)"},
+		{[]linkDomain{}, "a(sb)"},
+	} {
+		if got := dbus.SignatureOf(tt.arg).String(); got != tt.want {
+			t.Errorf("expected signature %s but got %s", tt.want, got)
+		}
+	}
+}
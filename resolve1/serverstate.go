@@ -0,0 +1,92 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/coreos/go-systemd/v22/internal/jsonfields"
+)
+
+// ServerState is the state resolved keeps about a DNS server, as returned by
+// ServerStates.
+type ServerState struct {
+	// Server is the address of the server, with port and server name if
+	// configured.
+	Server string `json:"Server"`
+	// Type is one of system, fallback or link.
+	Type           string `json:"Type"`
+	Interface      string `json:"Interface,omitempty"`
+	InterfaceIndex int    `json:"InterfaceIndex,omitempty"`
+
+	// VerifiedFeatureLevel is the highest feature level, such as UDP+EDNS0
+	// or TLS+EDNS0+DO, the server has been verified to support, or empty
+	// if none has been verified yet. PossibleFeatureLevel is the level
+	// currently tried.
+	VerifiedFeatureLevel string `json:"VerifiedFeatureLevel,omitempty"`
+	PossibleFeatureLevel string `json:"PossibleFeatureLevel,omitempty"`
+	DNSSECMode           string `json:"DNSSECMode,omitempty"`
+	DNSSECSupported      bool   `json:"DNSSECSupported"`
+
+	ReceivedUDPFragmentMax uint64 `json:"ReceivedUDPFragmentMax,omitempty"`
+	FailedUDPAttempts      uint64 `json:"FailedUDPAttempts,omitempty"`
+	FailedTCPAttempts      uint64 `json:"FailedTCPAttempts,omitempty"`
+	PacketTruncated        bool   `json:"PacketTruncated,omitempty"`
+	PacketBadOpt           bool   `json:"PacketBadOpt,omitempty"`
+	PacketRRSIGMissing     bool   `json:"PacketRRSIGMissing,omitempty"`
+	PacketInvalid          bool   `json:"PacketInvalid,omitempty"`
+	PacketDoOff            bool   `json:"PacketDoOff,omitempty"`
+
+	// Unknown holds the fields not covered above, such as those added by
+	// newer versions of resolved.
+	Unknown map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *ServerState) UnmarshalJSON(b []byte) error {
+	type plain ServerState
+	if err := json.Unmarshal(b, (*plain)(s)); err != nil {
+		return err
+	}
+	var err error
+	s.Unknown, err = jsonfields.Unknown(b, s)
+	return err
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s ServerState) MarshalJSON() ([]byte, error) {
+	type plain ServerState
+	b, err := json.Marshal(plain(s))
+	if err != nil {
+		return nil, err
+	}
+	return jsonfields.Merge(b, s.Unknown)
+}
+
+// ServerStates returns the state of all known DNS servers, decoded from
+// DumpServerState.
+// Note: Requires systemd v254 or higher
+func ServerStates(ctx context.Context) ([]ServerState, error) {
+	dump, err := DumpServerState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var states []ServerState
+	if err := json.Unmarshal(dump, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"bufio"
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServerStates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resolve1-monitor-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "io.systemd.Resolve.Monitor")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := bufio.NewReader(conn).ReadBytes(0); err != nil {
+			return
+		}
+		conn.Write([]byte(`{"parameters":{"dump":[` +
+			`{"Server":"1.1.1.1#cloudflare-dns.com","Type":"system","Interface":null,"InterfaceIndex":null,"VerifiedFeatureLevel":"TLS+EDNS0+DO","PossibleFeatureLevel":"TLS+EDNS0+DO","DNSSECMode":"allow-downgrade","DNSSECSupported":true,"ReceivedUDPFragmentMax":512,"FailedUDPAttempts":0,"FailedTCPAttempts":0,"PacketTruncated":false,"PacketBadOpt":false,"PacketRRSIGMissing":false,"PacketInvalid":false,"PacketDoOff":false},` +
+			`{"Server":"10.0.2.3","Type":"link","Interface":"eth0","InterfaceIndex":2,"VerifiedFeatureLevel":null,"PossibleFeatureLevel":"UDP+EDNS0","DNSSECMode":"no","DNSSECSupported":false,"FailedUDPAttempts":3,"PacketTruncated":true,"Resets":1}` +
+			`]}}` + "\x00"))
+	}()
+
+	saved := monitorSocket
+	monitorSocket = path
+	defer func() { monitorSocket = saved }()
+
+	states, err := ServerStates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states) != 2 {
+		t.Fatalf("expected 2 servers, got %d", len(states))
+	}
+
+	if s := states[0]; s.Server != "1.1.1.1#cloudflare-dns.com" || s.Type != "system" || s.Interface != "" || !s.DNSSECSupported || s.ReceivedUDPFragmentMax != 512 || s.Unknown != nil {
+		t.Errorf("unexpected first server %+v", s)
+	}
+	if s := states[1]; s.Interface != "eth0" || s.InterfaceIndex != 2 || s.VerifiedFeatureLevel != "" || s.FailedUDPAttempts != 3 || !s.PacketTruncated {
+		t.Errorf("unexpected second server %+v", s)
+	}
+	if string(states[1].Unknown["Resets"]) != "1" {
+		t.Errorf("unexpected unknown fields %v", states[1].Unknown)
+	}
+}
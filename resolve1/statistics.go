@@ -0,0 +1,109 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// TransactionStatistics counts the lookup transactions of resolved.
+type TransactionStatistics struct {
+	Current uint64 // The number of transactions currently in progress
+	Total   uint64 // The number of transactions since the last reset
+}
+
+// CacheStatistics describes the resolved cache.
+type CacheStatistics struct {
+	Size   uint64 // The number of cached resource records
+	Hits   uint64
+	Misses uint64
+}
+
+// DNSSECStatistics counts the DNSSEC validation results of resolved.
+type DNSSECStatistics struct {
+	Secure        uint64
+	Insecure      uint64
+	Bogus         uint64
+	Indeterminate uint64
+}
+
+// FlushCaches flushes all resolved caches.
+func (c *Conn) FlushCaches(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".FlushCaches", 0).Err
+}
+
+// ResetStatistics resets the counters returned by the *Statistics calls.
+func (c *Conn) ResetStatistics(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".ResetStatistics", 0).Err
+}
+
+// ResetServerFeatures forgets the features learned about the configured DNS
+// servers, e.g. after they were found to not support EDNS0.
+func (c *Conn) ResetServerFeatures(ctx context.Context) error {
+	return c.object.CallWithContext(ctx, dbusInterface+".ResetServerFeatures", 0).Err
+}
+
+func (c *Conn) getUint64sProperty(ctx context.Context, property string, n int) ([]uint64, error) {
+	var v dbus.Variant
+	if err := c.object.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, dbusInterface, property).Store(&v); err != nil {
+		return nil, err
+	}
+	return uint64sFromVariant(v, property, n)
+}
+
+func uint64sFromVariant(v dbus.Variant, property string, n int) ([]uint64, error) {
+	fields, ok := v.Value().([]interface{})
+	if !ok || len(fields) < n {
+		return nil, fmt.Errorf("failed to typecast %s property %s", property, v)
+	}
+	ret := make([]uint64, n)
+	for i := range ret {
+		ret[i], ok = fields[i].(uint64)
+		if !ok {
+			return nil, fmt.Errorf("failed to typecast %s field %d to uint64", property, i)
+		}
+	}
+	return ret, nil
+}
+
+// TransactionStatistics returns the lookup transaction counters.
+func (c *Conn) TransactionStatistics(ctx context.Context) (*TransactionStatistics, error) {
+	v, err := c.getUint64sProperty(ctx, "TransactionStatistics", 2)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionStatistics{Current: v[0], Total: v[1]}, nil
+}
+
+// CacheStatistics returns the cache size and hit counters.
+func (c *Conn) CacheStatistics(ctx context.Context) (*CacheStatistics, error) {
+	v, err := c.getUint64sProperty(ctx, "CacheStatistics", 3)
+	if err != nil {
+		return nil, err
+	}
+	return &CacheStatistics{Size: v[0], Hits: v[1], Misses: v[2]}, nil
+}
+
+// DNSSECStatistics returns the DNSSEC validation counters.
+func (c *Conn) DNSSECStatistics(ctx context.Context) (*DNSSECStatistics, error) {
+	v, err := c.getUint64sProperty(ctx, "DNSSECStatistics", 4)
+	if err != nil {
+		return nil, err
+	}
+	return &DNSSECStatistics{Secure: v[0], Insecure: v[1], Bogus: v[2], Indeterminate: v[3]}, nil
+}
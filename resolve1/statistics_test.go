@@ -0,0 +1,55 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestStatistics(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	if _, err := conn.TransactionStatistics(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.CacheStatistics(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.DNSSECStatistics(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUint64sFromVariant(t *testing.T) {
+	v, err := uint64sFromVariant(dbus.MakeVariant([]interface{}{uint64(3), uint64(42), uint64(7)}), "CacheStatistics", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v[0] != 3 || v[1] != 42 || v[2] != 7 {
+		t.Fatalf("unexpected values %v", v)
+	}
+
+	if _, err := uint64sFromVariant(dbus.MakeVariant([]interface{}{uint64(3)}), "CacheStatistics", 3); err == nil {
+		t.Fatal("expected error for truncated statistics")
+	}
+}
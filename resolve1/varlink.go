@@ -0,0 +1,191 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"syscall"
+
+	"github.com/coreos/go-systemd/v22/varlink"
+)
+
+var (
+	// resolveSocket is the varlink socket of the resolved lookup interface.
+	resolveSocket = "/run/systemd/resolve/io.systemd.Resolve"
+	// monitorSocket is the varlink socket of the resolved monitor interface.
+	monitorSocket = "/run/systemd/resolve/io.systemd.Resolve.Monitor"
+)
+
+// varlinkAddress is an address as encoded by io.systemd.Resolve, with the
+// address as an array of byte values.
+type varlinkAddress struct {
+	IfIndex int32 `json:"ifindex,omitempty"`
+	Family  int32 `json:"family"`
+	Address []int `json:"address"`
+}
+
+// ResolveHostname is like Conn.ResolveHostname, but talks to resolved's
+// varlink socket and needs no bus connection. Errors returned by resolved,
+// e.g. io.systemd.Resolve.NoSuchResourceRecord, are *varlink.Error.
+// Note: Requires systemd v246 or higher
+func ResolveHostname(ctx context.Context, ifindex int, name string, family int, flags uint64) (*HostnameResult, error) {
+	parameters := struct {
+		IfIndex int    `json:"ifindex,omitempty"`
+		Name    string `json:"name"`
+		Family  int    `json:"family,omitempty"`
+		Flags   uint64 `json:"flags,omitempty"`
+	}{ifindex, name, family, flags}
+	var reply struct {
+		Addresses []varlinkAddress `json:"addresses"`
+		Name      string           `json:"name"`
+		Flags     uint64           `json:"flags"`
+	}
+	if err := varlink.Call(ctx, resolveSocket, "io.systemd.Resolve.ResolveHostname", parameters, &reply); err != nil {
+		return nil, err
+	}
+
+	result := &HostnameResult{CanonicalName: reply.Name, Flags: reply.Flags}
+	for _, a := range reply.Addresses {
+		ip := make(net.IP, len(a.Address))
+		for i, b := range a.Address {
+			ip[i] = byte(b)
+		}
+		if (a.Family == syscall.AF_INET && len(ip) != net.IPv4len) || (a.Family == syscall.AF_INET6 && len(ip) != net.IPv6len) {
+			return nil, fmt.Errorf("invalid address length %d for family %d", len(ip), a.Family)
+		}
+		result.Addresses = append(result.Addresses, Address{IfIndex: a.IfIndex, IP: ip})
+	}
+	return result, nil
+}
+
+// ResolveAddress is like Conn.ResolveAddress, but talks to resolved's
+// varlink socket and needs no bus connection.
+// Note: Requires systemd v246 or higher
+func ResolveAddress(ctx context.Context, ifindex int, ip net.IP, flags uint64) (*AddressResult, error) {
+	family, addr := familyOf(ip)
+	if family == 0 {
+		return nil, fmt.Errorf("invalid IP address %v", ip)
+	}
+
+	parameters := struct {
+		varlinkAddress
+		Flags uint64 `json:"flags,omitempty"`
+	}{varlinkAddress{IfIndex: int32(ifindex), Family: family, Address: make([]int, len(addr))}, flags}
+	for i, b := range addr {
+		parameters.Address[i] = int(b)
+	}
+	var reply struct {
+		Names []struct {
+			IfIndex int32  `json:"ifindex"`
+			Name    string `json:"name"`
+		} `json:"names"`
+		Flags uint64 `json:"flags"`
+	}
+	if err := varlink.Call(ctx, resolveSocket, "io.systemd.Resolve.ResolveAddress", parameters, &reply); err != nil {
+		return nil, err
+	}
+
+	result := &AddressResult{Flags: reply.Flags}
+	for _, n := range reply.Names {
+		result.Names = append(result.Names, Name{IfIndex: n.IfIndex, Name: n.Name})
+	}
+	return result, nil
+}
+
+// ResourceKey is the question part of a DNS lookup.
+type ResourceKey struct {
+	Class uint16 `json:"class"`
+	Type  uint16 `json:"type"`
+	Name  string `json:"name"`
+}
+
+// MonitorAnswer is a resource record of a lookup answer.
+type MonitorAnswer struct {
+	RR      json.RawMessage `json:"rr,omitempty"` // The record as JSON, if resolved could convert it
+	Raw     []byte          `json:"raw"`          // The record in DNS wire format
+	IfIndex int             `json:"ifindex,omitempty"`
+}
+
+// QueryResult is a lookup completed by resolved, as streamed by MonitorQueries.
+type QueryResult struct {
+	// State is the final state of the lookup, e.g. success, no-servers,
+	// timeout, rcode-failure or dnssec-failed.
+	State  string `json:"state"`
+	Result string `json:"result,omitempty"` // The DNSSEC validation result, if any
+	Rcode  int    `json:"rcode,omitempty"`
+	Errno  int    `json:"errno,omitempty"`
+
+	Question []ResourceKey `json:"question,omitempty"`
+	// CollectedQuestions also contains the questions asked while following CNAME redirects.
+	CollectedQuestions []ResourceKey   `json:"collectedQuestions,omitempty"`
+	Answer             []MonitorAnswer `json:"answer,omitempty"`
+}
+
+// MonitorQueries subscribes to all lookups processed by resolved, as
+// "resolvectl monitor" does, and calls fn with the result of each. It talks
+// to resolved's varlink monitor socket, which requires root, and blocks
+// until ctx is done or fn returns an error.
+// Note: Requires systemd v252 or higher
+func MonitorQueries(ctx context.Context, fn func(*QueryResult) error) error {
+	conn, err := varlink.Dial(ctx, monitorSocket)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.CallMore(ctx, "io.systemd.Resolve.Monitor.SubscribeQueryResults", nil, func(parameters json.RawMessage) error {
+		var msg struct {
+			QueryResult
+			// The first reply only signals that the subscription is active.
+			Ready bool `json:"ready"`
+		}
+		if err := json.Unmarshal(parameters, &msg); err != nil {
+			return err
+		}
+		if msg.Ready {
+			return nil
+		}
+		return fn(&msg.QueryResult)
+	})
+}
+
+// DumpCache returns the contents of the resolved cache as JSON, as shown by
+// "resolvectl show-cache". It talks to resolved's varlink monitor socket,
+// which requires root.
+// Note: Requires systemd v254 or higher
+func DumpCache(ctx context.Context) (json.RawMessage, error) {
+	return dumpMonitor(ctx, "DumpCache")
+}
+
+// DumpServerState returns the state of all known DNS servers as JSON, as
+// shown by "resolvectl show-server-state". It talks to resolved's varlink
+// monitor socket, which requires root.
+// Note: Requires systemd v254 or higher
+func DumpServerState(ctx context.Context) (json.RawMessage, error) {
+	return dumpMonitor(ctx, "DumpServerState")
+}
+
+func dumpMonitor(ctx context.Context, method string) (json.RawMessage, error) {
+	var reply struct {
+		Dump json.RawMessage `json:"dump"`
+	}
+	if err := varlink.Call(ctx, monitorSocket, "io.systemd.Resolve.Monitor."+method, nil, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Dump, nil
+}
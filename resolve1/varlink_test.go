@@ -0,0 +1,169 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resolve1
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMonitorQueries(t *testing.T) {
+	dir, err := ioutil.TempDir("", "resolve1-monitor-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "io.systemd.Resolve.Monitor")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if _, err := bufio.NewReader(conn).ReadBytes(0); err != nil {
+			return
+		}
+		for _, reply := range []string{
+			`{"parameters":{"ready":true},"continues":true}`,
+			`{"parameters":{"state":"success","question":[{"class":1,"type":1,"name":"example.com"}],"answer":[{"raw":"AAEC","ifindex":2}]},"continues":true}`,
+			`{"parameters":{"state":"timeout","question":[{"class":1,"type":28,"name":"example.org"}]},"continues":true}`,
+		} {
+			conn.Write([]byte(reply + "\x00"))
+		}
+	}()
+
+	saved := monitorSocket
+	monitorSocket = path
+	defer func() { monitorSocket = saved }()
+
+	var results []*QueryResult
+	errDone := errors.New("done")
+	err = MonitorQueries(context.Background(), func(r *QueryResult) error {
+		results = append(results, r)
+		if len(results) == 2 {
+			return errDone
+		}
+		return nil
+	})
+	if err != errDone {
+		t.Fatalf("expected %v but got %v", errDone, err)
+	}
+
+	if results[0].State != "success" || results[0].Question[0].Name != "example.com" {
+		t.Fatalf("unexpected first result %+v", results[0])
+	}
+	if len(results[0].Answer) != 1 || string(results[0].Answer[0].Raw) != "\x00\x01\x02" || results[0].Answer[0].IfIndex != 2 {
+		t.Fatalf("unexpected answer %+v", results[0].Answer)
+	}
+	if results[1].State != "timeout" || results[1].Question[0].Type != 28 {
+		t.Fatalf("unexpected second result %+v", results[1])
+	}
+}
+
+// serveResolve answers a single varlink call on a socket at the returned
+// path with reply, sending the request to the returned channel.
+func serveResolve(t *testing.T, reply string) (string, <-chan []byte, func()) {
+	dir, err := ioutil.TempDir("", "resolve1-varlink-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "io.systemd.Resolve")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+
+	requests := make(chan []byte, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		msg, err := bufio.NewReader(conn).ReadBytes(0)
+		if err != nil {
+			return
+		}
+		requests <- msg[:len(msg)-1]
+		conn.Write([]byte(reply + "\x00"))
+	}()
+
+	return path, requests, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestVarlinkResolveHostname(t *testing.T) {
+	path, requests, cleanup := serveResolve(t, `{"parameters":{"addresses":[{"ifindex":2,"family":2,"address":[192,0,2,1]},{"family":10,"address":[32,1,13,184,0,0,0,0,0,0,0,0,0,0,0,1]}],"name":"example.com","flags":1048577}}`)
+	defer cleanup()
+	saved := resolveSocket
+	resolveSocket = path
+	defer func() { resolveSocket = saved }()
+
+	r, err := ResolveHostname(context.Background(), 0, "example.com", 0, FlagNoCache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := string(<-requests); req != `{"method":"io.systemd.Resolve.ResolveHostname","parameters":{"name":"example.com","flags":4096}}` {
+		t.Errorf("unexpected request %s", req)
+	}
+	if r.CanonicalName != "example.com" || r.Flags != FlagDNS|FlagFromCache || len(r.Addresses) != 2 {
+		t.Fatalf("unexpected result %+v", r)
+	}
+	if a := r.Addresses[0]; a.IfIndex != 2 || !a.IP.Equal(net.ParseIP("192.0.2.1")) {
+		t.Errorf("unexpected first address %+v", a)
+	}
+	if a := r.Addresses[1]; a.IfIndex != 0 || !a.IP.Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("unexpected second address %+v", a)
+	}
+}
+
+func TestVarlinkResolveAddress(t *testing.T) {
+	path, requests, cleanup := serveResolve(t, `{"parameters":{"names":[{"ifindex":2,"name":"host.example.com"}],"flags":1}}`)
+	defer cleanup()
+	saved := resolveSocket
+	resolveSocket = path
+	defer func() { resolveSocket = saved }()
+
+	r, err := ResolveAddress(context.Background(), 2, net.ParseIP("192.0.2.1"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := string(<-requests); req != `{"method":"io.systemd.Resolve.ResolveAddress","parameters":{"ifindex":2,"family":2,"address":[192,0,2,1]}}` {
+		t.Errorf("unexpected request %s", req)
+	}
+	if len(r.Names) != 1 || r.Names[0].IfIndex != 2 || r.Names[0].Name != "host.example.com" || r.Flags != FlagDNS {
+		t.Fatalf("unexpected result %+v", r)
+	}
+
+	if _, err := ResolveAddress(context.Background(), 0, nil, 0); err == nil {
+		t.Error("expected error for an invalid address")
+	}
+}
@@ -6,13 +6,45 @@ PROJ="go-systemd"
 ORG_PATH="github.com/coreos"
 REPO_PATH="${ORG_PATH}/${PROJ}"
 
-PACKAGES="activation daemon dbus internal/dlopen journal login1 machine1 sdjournal unit util import1"
+PACKAGES="activation daemon dbus internal/dlopen internal/jsonfields journal login1 machine1 sdjournal unit util import1 hostname1 timedate1 locale1 timesync1 resolve1 varlink network1 cmdline generator sysusers tmpfiles id128 cgroup oomd1 userdb home1 portable1 sysext boot uki coredump nspawn repart sysupdate gpt journal/testserver askpassword dbus/metrics journal/wire timers journal/journaltest"
 EXAMPLES="activation listen udpconn"
+# Nested modules with dependencies the main module does not have. They need
+# a newer toolchain than the main module, so they are handled separately.
+MODULES="dbus/metrics/prometheus"
+# Systems other than linux the pure Go packages must keep building for.
+CROSS_GOOS="darwin freebsd netbsd openbsd windows"
 
 function build_source {
     go build ./...
 }
 
+function build_modules {
+    for mod in ${MODULES}; do
+        echo "  - ${mod}"
+        (cd "./${mod}" && go build ./...)
+        vetRes=$(cd "./${mod}" && go vet ./...)
+        if [ -n "${vetRes}" ]; then
+            echo -e "govet checking failed:\n${vetRes}"
+            exit 254
+        fi
+    done
+}
+
+function build_cross {
+    for goos in ${CROSS_GOOS}; do
+        echo "  - ${goos}"
+        # cgo is not available when cross-compiling, so leave out the
+        # packages needing it: those loading libsystemd, and on freebsd
+        # those using godbus.
+        pkgs=$(GOOS=${goos} CGO_ENABLED=0 go list -e \
+            -f '{{if not (or .Error .DepsErrors)}}{{.ImportPath}} {{join .Deps " "}}{{end}}' ./... |
+            grep -v /internal/dlopen |
+            { if [ "${goos}" = freebsd ]; then grep -v github.com/godbus/dbus; else cat; fi; } |
+            cut -d' ' -f1)
+        GOOS=${goos} CGO_ENABLED=0 go build ${pkgs}
+    done
+}
+
 function build_tests {
     rm -rf ./test_bins ; mkdir -p ./test_bins
     for pkg in ${PACKAGES}; do
@@ -36,6 +68,10 @@ function run_tests {
     done
     popd
     sudo rm -rf ./test_bins
+    for mod in ${MODULES}; do
+        echo "  - ${mod}"
+        (cd "./${mod}" && go test ./...)
+    done
 }
 
 function go_fmt {
@@ -79,6 +115,16 @@ case "$subcommand" in
         build_source
         ;;
 
+    "build_cross" )
+        echo "Cross-building source..."
+        build_cross
+        ;;
+
+    "build_modules" )
+        echo "Building nested modules..."
+        build_modules
+        ;;
+
     "build_tests" )
         echo "Building tests..."
         build_tests
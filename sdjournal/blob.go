@@ -0,0 +1,90 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdjournal
+
+import (
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// maxFieldNameLen is the longest field name journald accepts.
+const maxFieldNameLen = 64
+
+// ErrDataInvalidated is returned when reading from a DataReader after the
+// journal it came from moved on.
+var ErrDataInvalidated = errors.New("journal data was invalidated")
+
+// DataReader reads the value of a field, such as the COREDUMP of a crash,
+// straight from the memory of sd-journal instead of copying it as a whole.
+// It is only valid until another method of the Journal is called.
+type DataReader struct {
+	j    *Journal
+	gen  uint64
+	data unsafe.Pointer
+	size int
+	off  int
+}
+
+// GetDataReader returns a reader for the value of field in the journal entry
+// referenced by the last completed Next/Previous function call.
+//
+// Values larger than the data threshold may be truncated when they are
+// stored compressed, see SetDataThreshold; uncompressed values are mapped
+// from the journal files and do not take up any memory until they are read.
+func (j *Journal) GetDataReader(field string) (*DataReader, error) {
+	d, l, gen, err := j.getData(field)
+	if err != nil {
+		return nil, err
+	}
+	skip := len(field) + 1
+	if int(l) < skip {
+		return nil, errors.New("failed to parse field")
+	}
+	return &DataReader{
+		j:    j,
+		gen:  gen,
+		data: unsafe.Pointer(uintptr(d) + uintptr(skip)),
+		size: int(l) - skip,
+	}, nil
+}
+
+// Size returns the length of the value in bytes.
+func (r *DataReader) Size() int {
+	return r.size
+}
+
+// Read reads from the value, failing with ErrDataInvalidated if it is not
+// valid anymore.
+func (r *DataReader) Read(p []byte) (int, error) {
+	r.j.mu.Lock()
+	defer r.j.mu.Unlock()
+	if r.gen != r.j.gen {
+		return 0, ErrDataInvalidated
+	}
+	if r.off >= r.size {
+		return 0, io.EOF
+	}
+	n := r.size - r.off
+	if n > len(p) {
+		n = len(p)
+	}
+	if n > 1<<30 {
+		n = 1 << 30
+	}
+	copy(p, (*[1 << 30]byte)(unsafe.Pointer(uintptr(r.data) + uintptr(r.off)))[:n:n])
+	r.off += n
+	return n, nil
+}
@@ -0,0 +1,385 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdjournal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// OutputMode selects one of the output formats of journalctl -o.
+type OutputMode string
+
+const (
+	OutputShort           OutputMode = "short"
+	OutputShortISOPrecise OutputMode = "short-iso-precise"
+	OutputVerbose         OutputMode = "verbose"
+	OutputJSON            OutputMode = "json"
+	OutputJSONPretty      OutputMode = "json-pretty"
+	OutputCat             OutputMode = "cat"
+)
+
+// jsonThreshold is the size above which journalctl prints fields as null in
+// JSON output unless --all is given.
+const jsonThreshold = 4096
+
+// Output renders entries like journalctl does in one of its output modes.
+// Its Format method can be used as JournalReaderConfig.Formatter.
+type Output struct {
+	Mode OutputMode
+
+	// Location is the time zone of timestamps, the local one if nil.
+	Location *time.Location
+
+	// ShowAll prints unprintable messages and large fields in JSON output
+	// instead of placeholders, like journalctl --all.
+	ShowAll bool
+
+	// MultiLine selects how the continuation lines of multi-line values
+	// are printed in the short and verbose modes.
+	MultiLine MultiLineMode
+
+	// ANSI selects how ANSI escape sequences in values are printed in the
+	// short, verbose and cat modes. JSON output always keeps them.
+	ANSI ANSIMode
+}
+
+// MultiLineMode selects how multi-line values are printed.
+type MultiLineMode int
+
+const (
+	// MultiLineIndent indents continuation lines to align with the first
+	// line, like journalctl.
+	MultiLineIndent MultiLineMode = iota
+	// MultiLineVerbatim prints values as they are.
+	MultiLineVerbatim
+	// MultiLineEscape prints newlines as \n, keeping every entry on a
+	// single line.
+	MultiLineEscape
+)
+
+// ANSIMode selects how ANSI escape sequences, such as the color codes
+// written by many programs, are printed.
+type ANSIMode int
+
+const (
+	// ANSIBlob treats values with escape sequences as unprintable, like
+	// journalctl, printing a placeholder unless ShowAll is set.
+	ANSIBlob ANSIMode = iota
+	// ANSIStrip removes escape sequences.
+	ANSIStrip
+	// ANSIPreserve prints escape sequences as they are, for output to a
+	// terminal.
+	ANSIPreserve
+)
+
+// Format renders entry followed by a newline. Entries without a message
+// yield an empty string in the short and cat modes, as journalctl skips
+// them.
+func (o *Output) Format(entry *JournalEntry) (string, error) {
+	switch o.Mode {
+	case OutputShort, "":
+		return o.formatShort(entry, "Jan 02 15:04:05")
+	case OutputShortISOPrecise:
+		return o.formatShort(entry, "2006-01-02T15:04:05.000000-07:00")
+	case OutputVerbose:
+		return o.formatVerbose(entry), nil
+	case OutputJSON:
+		return o.formatJSON(entry, false), nil
+	case OutputJSONPretty:
+		return o.formatJSON(entry, true), nil
+	case OutputCat:
+		return o.formatCat(entry), nil
+	}
+	return "", fmt.Errorf("unsupported output mode %q", o.Mode)
+}
+
+func (o *Output) location() *time.Location {
+	if o.Location == nil {
+		return time.Local
+	}
+	return o.Location
+}
+
+func usecTime(usec uint64) time.Time {
+	return time.Unix(int64(usec/1000000), int64(usec%1000000)*1000)
+}
+
+// displayTime returns the time the entry was logged at, preferring the
+// timestamp passed by the client as journalctl does.
+func displayTime(entry *JournalEntry) time.Time {
+	if v, ok := entry.Fields[SD_JOURNAL_FIELD_SOURCE_REALTIME_TIMESTAMP]; ok {
+		if usec, err := strconv.ParseUint(v, 10, 64); err == nil && usec > 0 {
+			return usecTime(usec)
+		}
+	}
+	return usecTime(entry.RealtimeTimestamp)
+}
+
+func (o *Output) formatShort(entry *JournalEntry, layout string) (string, error) {
+	msg, ok := entry.Fields[SD_JOURNAL_FIELD_MESSAGE]
+	if !ok {
+		return "", nil
+	}
+
+	var b strings.Builder
+	b.WriteString(displayTime(entry).In(o.location()).Format(layout))
+	if host := entry.Fields[SD_JOURNAL_FIELD_HOSTNAME]; host != "" {
+		b.WriteString(" " + host)
+	}
+	if ident := entry.Fields[SD_JOURNAL_FIELD_SYSLOG_IDENTIFIER]; ident != "" {
+		b.WriteString(" " + ident)
+	} else if comm := entry.Fields[SD_JOURNAL_FIELD_COMM]; comm != "" {
+		b.WriteString(" " + comm)
+	} else {
+		b.WriteString(" unknown")
+	}
+	if pid := entry.Fields[SD_JOURNAL_FIELD_SYSLOG_PID]; pid != "" {
+		b.WriteString("[" + pid + "]")
+	} else if pid := entry.Fields[SD_JOURNAL_FIELD_PID]; pid != "" {
+		b.WriteString("[" + pid + "]")
+	}
+	b.WriteString(": ")
+	b.WriteString(o.value(msg, utf8.RuneCountInString(b.String())))
+	b.WriteByte('\n')
+	return b.String(), nil
+}
+
+func (o *Output) formatVerbose(entry *JournalEntry) string {
+	var b strings.Builder
+	b.WriteString(usecTime(entry.RealtimeTimestamp).In(o.location()).Format("Mon 2006-01-02 15:04:05.000000 MST"))
+	b.WriteString(" [" + entry.Cursor + "]\n")
+	for _, k := range sortedFields(entry) {
+		b.WriteString("    " + k + "=")
+		b.WriteString(o.value(entry.Fields[k], 4+len(k)+1))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func (o *Output) formatCat(entry *JournalEntry) string {
+	msg, ok := entry.Fields[SD_JOURNAL_FIELD_MESSAGE]
+	if !ok {
+		return ""
+	}
+	if o.ANSI == ANSIStrip {
+		msg = StripANSI(msg)
+	}
+	return msg + "\n"
+}
+
+// value renders v for the short and verbose modes, where it follows a
+// prefix of indent characters.
+func (o *Output) value(v string, indent int) string {
+	if o.ANSI == ANSIStrip {
+		v = StripANSI(v)
+	}
+	if !o.ShowAll && !printable(v, o.ANSI == ANSIPreserve) {
+		return "[" + formatBytes(len(v)) + " blob data]"
+	}
+	switch o.MultiLine {
+	case MultiLineIndent:
+		return IndentMultiLine(v, indent)
+	case MultiLineEscape:
+		return strings.Replace(strings.TrimRight(v, "\n"), "\n", `\n`, -1)
+	}
+	return v
+}
+
+// IndentMultiLine indents all but the first line of s by indent spaces and
+// drops trailing newlines, as journalctl prints multi-line messages.
+func IndentMultiLine(s string, indent int) string {
+	s = strings.TrimRight(s, "\n")
+	if !strings.Contains(s, "\n") {
+		return s
+	}
+	return strings.Replace(s, "\n", "\n"+strings.Repeat(" ", indent), -1)
+}
+
+// StripANSI removes ANSI escape sequences, such as color codes, from s.
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != 0x1b {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i >= len(s) {
+			break
+		}
+		switch s[i] {
+		case '[':
+			// CSI: parameter and intermediate bytes up to a final byte.
+			for i++; i < len(s) && (s[i] < 0x40 || s[i] > 0x7e); i++ {
+			}
+		case ']':
+			// OSC: terminated by BEL or ST.
+			for i++; i < len(s); i++ {
+				if s[i] == 0x07 {
+					break
+				}
+				if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+					i++
+					break
+				}
+			}
+		default:
+			// Other sequences consist of intermediate bytes and a
+			// final byte.
+			for ; i < len(s) && s[i] >= 0x20 && s[i] <= 0x2f; i++ {
+			}
+		}
+	}
+	return b.String()
+}
+
+// formatJSON renders entry like journalctl -o json and json-pretty, with
+// the address fields first.
+func (o *Output) formatJSON(entry *JournalEntry, pretty bool) string {
+	type field struct{ name, value string }
+	fields := []field{
+		{SD_JOURNAL_FIELD_CURSOR, entry.Cursor},
+		{SD_JOURNAL_FIELD_REALTIME_TIMESTAMP, strconv.FormatUint(entry.RealtimeTimestamp, 10)},
+		{SD_JOURNAL_FIELD_MONOTONIC_TIMESTAMP, strconv.FormatUint(entry.MonotonicTimestamp, 10)},
+	}
+	if v, ok := entry.Fields[SD_JOURNAL_FIELD_BOOT_ID]; ok {
+		fields = append(fields, field{SD_JOURNAL_FIELD_BOOT_ID, v})
+	}
+	for _, k := range sortedFields(entry) {
+		if k != SD_JOURNAL_FIELD_BOOT_ID {
+			fields = append(fields, field{k, entry.Fields[k]})
+		}
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if pretty {
+			b.WriteString("\n\t")
+		}
+		writeJSONString(&b, f.name)
+		if pretty {
+			b.WriteString(" : ")
+		} else {
+			b.WriteByte(':')
+		}
+		switch {
+		case !o.ShowAll && len(f.value) > jsonThreshold:
+			b.WriteString("null")
+		case utf8.ValidString(f.value):
+			writeJSONString(&b, f.value)
+		default:
+			// Binary data is printed as an array of its bytes.
+			b.WriteByte('[')
+			for j := 0; j < len(f.value); j++ {
+				if j > 0 {
+					b.WriteByte(',')
+				}
+				b.WriteString(strconv.Itoa(int(f.value[j])))
+			}
+			b.WriteByte(']')
+		}
+	}
+	if pretty {
+		b.WriteByte('\n')
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// writeJSONString writes s quoted like systemd's JSON encoder, which only
+// escapes quotes, backslashes and control characters.
+func writeJSONString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(b, `\u%04x`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
+
+// sortedFields returns the names of the fields of entry in order, as the
+// order they were logged in is not known.
+func sortedFields(entry *JournalEntry) []string {
+	names := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printable returns whether s is valid UTF-8 without control characters
+// other than newlines and tabs, which journalctl prints as they are, and
+// escape characters if escapes is set.
+func printable(s string, escapes bool) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if escapes && r == 0x1b {
+			continue
+		}
+		if (r < 0x20 && r != '\n' && r != '\t') || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// formatBytes formats a size like systemd, e.g. "512B" or "1.5K".
+func formatBytes(n int) string {
+	const units = "KMGTPE"
+	if n < 1024 {
+		return strconv.Itoa(n) + "B"
+	}
+	f, i := uint64(1024), 0
+	for i < len(units)-1 && uint64(n) >= f*1024 {
+		f *= 1024
+		i++
+	}
+	return fmt.Sprintf("%d.%d%c", uint64(n)/f, uint64(n)*10/f%10, units[i])
+}
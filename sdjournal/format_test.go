@@ -0,0 +1,175 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdjournal
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func testEntry() *JournalEntry {
+	return &JournalEntry{
+		Fields: map[string]string{
+			"MESSAGE":           "Started \"test\".",
+			"PRIORITY":          "6",
+			"SYSLOG_IDENTIFIER": "systemd",
+			"_PID":              "1",
+			"_HOSTNAME":         "host",
+			"_BOOT_ID":          "0e9fd4ad4ef0439e8adbb7d3e2c1d2f4",
+		},
+		Cursor:             "s=1;i=2",
+		RealtimeTimestamp:  1700000000123456,
+		MonotonicTimestamp: 42,
+	}
+}
+
+func TestOutput(t *testing.T) {
+	blob := testEntry()
+	blob.Fields["MESSAGE"] = "\x00\x01"
+	blob.Fields["_SOURCE_REALTIME_TIMESTAMP"] = "1700000001000000"
+	delete(blob.Fields, "SYSLOG_IDENTIFIER")
+	bare := testEntry()
+	delete(bare.Fields, "MESSAGE")
+
+	for i, tt := range []struct {
+		mode  OutputMode
+		entry *JournalEntry
+		all   bool
+		want  string
+	}{
+		{OutputShort, testEntry(), false, "Nov 14 22:13:20 host systemd[1]: Started \"test\".\n"},
+		{OutputShort, blob, false, "Nov 14 22:13:21 host unknown[1]: [2B blob data]\n"},
+		{OutputShort, blob, true, "Nov 14 22:13:21 host unknown[1]: \x00\x01\n"},
+		{OutputShort, bare, false, ""},
+		{OutputShortISOPrecise, testEntry(), false, "2023-11-14T22:13:20.123456+00:00 host systemd[1]: Started \"test\".\n"},
+		{OutputCat, testEntry(), false, "Started \"test\".\n"},
+		{OutputCat, bare, false, ""},
+		{OutputVerbose, bare, false, "Tue 2023-11-14 22:13:20.123456 UTC [s=1;i=2]\n" +
+			"    PRIORITY=6\n" +
+			"    SYSLOG_IDENTIFIER=systemd\n" +
+			"    _BOOT_ID=0e9fd4ad4ef0439e8adbb7d3e2c1d2f4\n" +
+			"    _HOSTNAME=host\n" +
+			"    _PID=1\n"},
+		{OutputJSON, bare, false, `{"__CURSOR":"s=1;i=2","__REALTIME_TIMESTAMP":"1700000000123456","__MONOTONIC_TIMESTAMP":"42",` +
+			`"_BOOT_ID":"0e9fd4ad4ef0439e8adbb7d3e2c1d2f4","PRIORITY":"6","SYSLOG_IDENTIFIER":"systemd","_HOSTNAME":"host","_PID":"1"}` + "\n"},
+		{OutputJSON, &JournalEntry{Fields: map[string]string{"A": "<\"\n\x01>", "B": "\xff\x00"}}, false,
+			`{"__CURSOR":"","__REALTIME_TIMESTAMP":"0","__MONOTONIC_TIMESTAMP":"0","A":"<\"\n\u0001>","B":[255,0]}` + "\n"},
+		{OutputJSON, &JournalEntry{Fields: map[string]string{"A": strings.Repeat("x", 4097)}}, false,
+			`{"__CURSOR":"","__REALTIME_TIMESTAMP":"0","__MONOTONIC_TIMESTAMP":"0","A":null}` + "\n"},
+		{OutputJSONPretty, &JournalEntry{Fields: map[string]string{"A": "b"}}, false,
+			"{\n\t\"__CURSOR\" : \"\",\n\t\"__REALTIME_TIMESTAMP\" : \"0\",\n\t\"__MONOTONIC_TIMESTAMP\" : \"0\",\n\t\"A\" : \"b\"\n}\n"},
+	} {
+		o := &Output{Mode: tt.mode, Location: time.UTC, ShowAll: tt.all}
+		got, err := o.Format(tt.entry)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("case %d: got %q, want %q", i, got, tt.want)
+		}
+	}
+
+	if _, err := (&Output{Mode: "export"}).Format(testEntry()); err == nil {
+		t.Error("unsupported mode accepted")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for i, tt := range []struct {
+		n    int
+		want string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1024, "1.0K"},
+		{1536, "1.5K"},
+		{5 << 20, "5.0M"},
+	} {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("case %d: got %q, want %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestOutputMultiLine(t *testing.T) {
+	e := testEntry()
+	e.Fields = map[string]string{"MESSAGE": "first\nsecond\n", "_HOSTNAME": "h", "_PID": "7"}
+	e.Cursor = "c"
+	for i, tt := range []struct {
+		o    Output
+		want string
+	}{
+		{Output{}, "Nov 14 22:13:20 h unknown[7]: first\n                              second\n"},
+		{Output{MultiLine: MultiLineVerbatim}, "Nov 14 22:13:20 h unknown[7]: first\nsecond\n\n"},
+		{Output{MultiLine: MultiLineEscape}, "Nov 14 22:13:20 h unknown[7]: first\\nsecond\n"},
+		{Output{Mode: OutputVerbose}, "Tue 2023-11-14 22:13:20.123456 UTC [c]\n" +
+			"    MESSAGE=first\n" +
+			"            second\n" +
+			"    _HOSTNAME=h\n" +
+			"    _PID=7\n"},
+		{Output{Mode: OutputCat}, "first\nsecond\n\n"},
+	} {
+		o := tt.o
+		o.Location = time.UTC
+		got, err := o.Format(e)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("case %d: got %q, want %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestOutputANSI(t *testing.T) {
+	e := &JournalEntry{Fields: map[string]string{"MESSAGE": "\x1b[1;31mfailed\x1b[0m", "_COMM": "app"}}
+	for i, tt := range []struct {
+		o    Output
+		want string
+	}{
+		{Output{}, "Jan 01 00:00:00 app: [17B blob data]\n"},
+		{Output{ANSI: ANSIStrip}, "Jan 01 00:00:00 app: failed\n"},
+		{Output{ANSI: ANSIPreserve}, "Jan 01 00:00:00 app: \x1b[1;31mfailed\x1b[0m\n"},
+		{Output{Mode: OutputCat, ANSI: ANSIStrip}, "failed\n"},
+		{Output{Mode: OutputCat}, "\x1b[1;31mfailed\x1b[0m\n"},
+	} {
+		o := tt.o
+		o.Location = time.UTC
+		got, _ := o.Format(e)
+		if got != tt.want {
+			t.Errorf("case %d: got %q, want %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	for i, tt := range []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"\x1b[0;1;39mbold\x1b[0m", "bold"},
+		{"\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x07", "link"},
+		{"a\x1b(Bb", "ab"},
+		{"cut\x1b", "cut"},
+		{"cut\x1b[12", "cut"},
+	} {
+		if got := StripANSI(tt.in); got != tt.want {
+			t.Errorf("case %d: got %q, want %q", i, got, tt.want)
+		}
+	}
+}
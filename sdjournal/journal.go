@@ -399,6 +399,9 @@ var (
 type Journal struct {
 	cjournal *C.sd_journal
 	mu       sync.Mutex
+	// gen changes with every call into sd-journal, which may invalidate
+	// the data it returned before, for DataReader to detect it.
+	gen uint64
 }
 
 // JournalEntry represents all fields of a journal entry plus address fields.
@@ -493,6 +496,7 @@ func (j *Journal) Close() error {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	C.my_sd_journal_close(sd_journal_close, j.cjournal)
 	j.mu.Unlock()
 
@@ -510,6 +514,7 @@ func (j *Journal) AddMatch(match string) error {
 	defer C.free(unsafe.Pointer(m))
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_add_match(sd_journal_add_match, j.cjournal, unsafe.Pointer(m), C.size_t(len(match)))
 	j.mu.Unlock()
 
@@ -528,6 +533,7 @@ func (j *Journal) AddDisjunction() error {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_add_disjunction(sd_journal_add_disjunction, j.cjournal)
 	j.mu.Unlock()
 
@@ -546,6 +552,7 @@ func (j *Journal) AddConjunction() error {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_add_conjunction(sd_journal_add_conjunction, j.cjournal)
 	j.mu.Unlock()
 
@@ -564,6 +571,7 @@ func (j *Journal) FlushMatches() {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	C.my_sd_journal_flush_matches(sd_journal_flush_matches, j.cjournal)
 	j.mu.Unlock()
 }
@@ -576,6 +584,7 @@ func (j *Journal) Next() (uint64, error) {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_next(sd_journal_next, j.cjournal)
 	j.mu.Unlock()
 
@@ -595,6 +604,7 @@ func (j *Journal) NextSkip(skip uint64) (uint64, error) {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_next_skip(sd_journal_next_skip, j.cjournal, C.uint64_t(skip))
 	j.mu.Unlock()
 
@@ -613,6 +623,7 @@ func (j *Journal) Previous() (uint64, error) {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_previous(sd_journal_previous, j.cjournal)
 	j.mu.Unlock()
 
@@ -632,6 +643,7 @@ func (j *Journal) PreviousSkip(skip uint64) (uint64, error) {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_previous_skip(sd_journal_previous_skip, j.cjournal, C.uint64_t(skip))
 	j.mu.Unlock()
 
@@ -642,10 +654,11 @@ func (j *Journal) PreviousSkip(skip uint64) (uint64, error) {
 	return uint64(r), nil
 }
 
-func (j *Journal) getData(field string) (unsafe.Pointer, C.int, error) {
+// getData returns the data of field and the generation it is valid for.
+func (j *Journal) getData(field string) (unsafe.Pointer, C.int, uint64, error) {
 	sd_journal_get_data, err := getFunction("sd_journal_get_data")
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
 
 	f := C.CString(field)
@@ -655,21 +668,23 @@ func (j *Journal) getData(field string) (unsafe.Pointer, C.int, error) {
 	var l C.size_t
 
 	j.mu.Lock()
+	j.gen++
+	gen := j.gen
 	r := C.my_sd_journal_get_data(sd_journal_get_data, j.cjournal, f, &d, &l)
 	j.mu.Unlock()
 
 	if r < 0 {
-		return nil, 0, fmt.Errorf("failed to read message: %s", syscall.Errno(-r).Error())
+		return nil, 0, 0, fmt.Errorf("failed to read message: %s", syscall.Errno(-r).Error())
 	}
 
-	return d, C.int(l), nil
+	return d, C.int(l), gen, nil
 }
 
 // GetData gets the data object associated with a specific field from the
 // the journal entry referenced by the last completed Next/Previous function
 // call. To call GetData, you must have first called one of these functions.
 func (j *Journal) GetData(field string) (string, error) {
-	d, l, err := j.getData(field)
+	d, l, _, err := j.getData(field)
 	if err != nil {
 		return "", err
 	}
@@ -694,7 +709,7 @@ func (j *Journal) GetDataValue(field string) (string, error) {
 // journal entry referenced by the last completed Next/Previous function call.
 // To call GetDataBytes, you must first have called one of these functions.
 func (j *Journal) GetDataBytes(field string) ([]byte, error) {
-	d, l, err := j.getData(field)
+	d, l, _, err := j.getData(field)
 	if err != nil {
 		return nil, err
 	}
@@ -720,41 +735,55 @@ func (j *Journal) GetDataValueBytes(field string) ([]byte, error) {
 // as well as address fields (cursor, realtime timestamp and monotonic timestamp).
 // To call GetEntry, you must first have called one of the Next/Previous functions.
 func (j *Journal) GetEntry() (*JournalEntry, error) {
+	entry, _, err := j.getEntry(-1)
+	return entry, err
+}
+
+// GetEntryLimit is like GetEntry, but leaves out the fields with values
+// longer than limit bytes and returns their names instead, so they can be
+// streamed with GetDataReader rather than copied.
+func (j *Journal) GetEntryLimit(limit int) (*JournalEntry, []string, error) {
+	return j.getEntry(limit)
+}
+
+func (j *Journal) getEntry(limit int) (*JournalEntry, []string, error) {
 	sd_journal_get_realtime_usec, err := getFunction("sd_journal_get_realtime_usec")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	sd_journal_get_monotonic_usec, err := getFunction("sd_journal_get_monotonic_usec")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	sd_journal_get_cursor, err := getFunction("sd_journal_get_cursor")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	sd_journal_restart_data, err := getFunction("sd_journal_restart_data")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	sd_journal_enumerate_data, err := getFunction("sd_journal_enumerate_data")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	j.mu.Lock()
+	j.gen++
 	defer j.mu.Unlock()
 
 	var r C.int
 	entry := &JournalEntry{Fields: make(map[string]string)}
+	var large []string
 
 	var realtimeUsec C.uint64_t
 	r = C.my_sd_journal_get_realtime_usec(sd_journal_get_realtime_usec, j.cjournal, &realtimeUsec)
 	if r < 0 {
-		return nil, fmt.Errorf("failed to get realtime timestamp: %s", syscall.Errno(-r).Error())
+		return nil, nil, fmt.Errorf("failed to get realtime timestamp: %s", syscall.Errno(-r).Error())
 	}
 
 	entry.RealtimeTimestamp = uint64(realtimeUsec)
@@ -764,7 +793,7 @@ func (j *Journal) GetEntry() (*JournalEntry, error) {
 
 	r = C.my_sd_journal_get_monotonic_usec(sd_journal_get_monotonic_usec, j.cjournal, &monotonicUsec, &boot_id)
 	if r < 0 {
-		return nil, fmt.Errorf("failed to get monotonic timestamp: %s", syscall.Errno(-r).Error())
+		return nil, nil, fmt.Errorf("failed to get monotonic timestamp: %s", syscall.Errno(-r).Error())
 	}
 
 	entry.MonotonicTimestamp = uint64(monotonicUsec)
@@ -775,7 +804,7 @@ func (j *Journal) GetEntry() (*JournalEntry, error) {
 	r = C.my_sd_journal_get_cursor(sd_journal_get_cursor, j.cjournal, &c)
 	defer C.free(unsafe.Pointer(c))
 	if r < 0 {
-		return nil, fmt.Errorf("failed to get cursor: %s", syscall.Errno(-r).Error())
+		return nil, nil, fmt.Errorf("failed to get cursor: %s", syscall.Errno(-r).Error())
 	}
 
 	entry.Cursor = C.GoString(c)
@@ -791,19 +820,37 @@ func (j *Journal) GetEntry() (*JournalEntry, error) {
 		}
 
 		if r < 0 {
-			return nil, fmt.Errorf("failed to read message field: %s", syscall.Errno(-r).Error())
+			return nil, nil, fmt.Errorf("failed to read message field: %s", syscall.Errno(-r).Error())
+		}
+
+		if limit >= 0 && int(l) > limit {
+			// Only copy enough to find the field name.
+			n := l
+			if n > maxFieldNameLen+1 {
+				n = maxFieldNameLen + 1
+			}
+			data := C.GoStringN((*C.char)(d), C.int(n))
+			eq := strings.IndexByte(data, '=')
+			if eq < 0 {
+				return nil, nil, fmt.Errorf("failed to parse field")
+			}
+			// Unless the value fits within limit after all.
+			if int(l)-eq-1 > limit {
+				large = append(large, data[:eq])
+				continue
+			}
 		}
 
 		msg := C.GoStringN((*C.char)(d), C.int(l))
 		kv := strings.SplitN(msg, "=", 2)
 		if len(kv) < 2 {
-			return nil, fmt.Errorf("failed to parse field")
+			return nil, nil, fmt.Errorf("failed to parse field")
 		}
 
 		entry.Fields[kv[0]] = kv[1]
 	}
 
-	return entry, nil
+	return entry, large, nil
 }
 
 // SetDataThreshold sets the data field size threshold for data returned by
@@ -817,6 +864,7 @@ func (j *Journal) SetDataThreshold(threshold uint64) error {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_set_data_threshold(sd_journal_set_data_threshold, j.cjournal, C.size_t(threshold))
 	j.mu.Unlock()
 
@@ -840,6 +888,7 @@ func (j *Journal) GetRealtimeUsec() (uint64, error) {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_get_realtime_usec(sd_journal_get_realtime_usec, j.cjournal, &usec)
 	j.mu.Unlock()
 
@@ -864,6 +913,7 @@ func (j *Journal) GetMonotonicUsec() (uint64, error) {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_get_monotonic_usec(sd_journal_get_monotonic_usec, j.cjournal, &usec, &boot_id)
 	j.mu.Unlock()
 
@@ -888,6 +938,7 @@ func (j *Journal) GetCursor() (string, error) {
 	// until after the call to free the memory
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_get_cursor(sd_journal_get_cursor, j.cjournal, &d)
 	j.mu.Unlock()
 	defer C.free(unsafe.Pointer(d))
@@ -913,6 +964,7 @@ func (j *Journal) TestCursor(cursor string) error {
 	defer C.free(unsafe.Pointer(c))
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_test_cursor(sd_journal_test_cursor, j.cjournal, c)
 	j.mu.Unlock()
 
@@ -935,6 +987,7 @@ func (j *Journal) SeekHead() error {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_seek_head(sd_journal_seek_head, j.cjournal)
 	j.mu.Unlock()
 
@@ -955,6 +1008,7 @@ func (j *Journal) SeekTail() error {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_seek_tail(sd_journal_seek_tail, j.cjournal)
 	j.mu.Unlock()
 
@@ -975,6 +1029,7 @@ func (j *Journal) SeekRealtimeUsec(usec uint64) error {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_seek_realtime_usec(sd_journal_seek_realtime_usec, j.cjournal, C.uint64_t(usec))
 	j.mu.Unlock()
 
@@ -998,6 +1053,7 @@ func (j *Journal) SeekCursor(cursor string) error {
 	defer C.free(unsafe.Pointer(c))
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_seek_cursor(sd_journal_seek_cursor, j.cjournal, c)
 	j.mu.Unlock()
 
@@ -1029,6 +1085,7 @@ func (j *Journal) Wait(timeout time.Duration) int {
 		to = uint64(timeout / time.Microsecond)
 	}
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_wait(sd_journal_wait, j.cjournal, C.uint64_t(to))
 	j.mu.Unlock()
 
@@ -1045,6 +1102,7 @@ func (j *Journal) GetUsage() (uint64, error) {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_get_usage(sd_journal_get_usage, j.cjournal, &out)
 	j.mu.Unlock()
 
@@ -1075,6 +1133,7 @@ func (j *Journal) GetUniqueValues(field string) ([]string, error) {
 	}
 
 	j.mu.Lock()
+	j.gen++
 	defer j.mu.Unlock()
 
 	f := C.CString(field)
@@ -1124,6 +1183,7 @@ func (j *Journal) GetCatalog() (string, error) {
 	var c *C.char
 
 	j.mu.Lock()
+	j.gen++
 	r := C.my_sd_journal_get_catalog(sd_journal_get_catalog, j.cjournal, &c)
 	j.mu.Unlock()
 	defer C.free(unsafe.Pointer(c))
@@ -341,6 +341,59 @@ func TestJournalGetEntry(t *testing.T) {
 
 // Check for incorrect read into small buffers,
 // see https://github.com/coreos/go-systemd/issues/172
+func TestJournalGetDataReader(t *testing.T) {
+	j, wantEntry, err := setupJournalRoundtrip()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defer j.Close()
+
+	if err := j.SetDataThreshold(0); err != nil {
+		t.Fatal(err)
+	}
+	entry, large, err := j.GetEntryLimit(len(wantEntry["TESTJOURNALENTRY"]))
+	if err != nil {
+		t.Fatalf("Error getting the entry to journal: %s", err)
+	}
+	if entry.Fields["TESTJOURNALENTRY"] != wantEntry["TESTJOURNALENTRY"] {
+		t.Fatalf("Bad result for entry.Fields: %v", entry.Fields)
+	}
+	if _, ok := entry.Fields["MESSAGE"]; ok || !strings.Contains(strings.Join(large, " "), "MESSAGE") {
+		t.Fatalf("MESSAGE not left out of entry: %v", large)
+	}
+
+	r, err := j.GetDataReader("MESSAGE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Size() != len(wantEntry["MESSAGE"]) {
+		t.Fatalf("got size %d, want %d", r.Size(), len(wantEntry["MESSAGE"]))
+	}
+	first := make([]byte, 4)
+	if _, err := io.ReadFull(r, first); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(first) + string(rest); got != wantEntry["MESSAGE"] {
+		t.Fatalf("got %q, want %q", got, wantEntry["MESSAGE"])
+	}
+
+	r, err = j.GetDataReader("MESSAGE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := j.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Read(first); err != ErrDataInvalidated {
+		t.Fatalf("expected invalidated data, got %v", err)
+	}
+}
+
 func TestJournalReaderSmallReadBuffer(t *testing.T) {
 	// Write a long entry ...
 	delim := "%%%%%%"
@@ -0,0 +1,171 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdjournal
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MergeJournal is the part of a Journal read by a MergeReader.
+type MergeJournal interface {
+	Next() (uint64, error)
+	GetEntry() (*JournalEntry, error)
+	SeekCursor(cursor string) error
+}
+
+// MergeSource is a journal merged by a MergeReader, such as one opened with
+// NewJournalFromDir for the logs of another machine. Its position is owned
+// by the MergeReader.
+type MergeSource struct {
+	// Name identifies the source in merged entries and cursors.
+	Name    string
+	Journal MergeJournal
+}
+
+// MergedEntry is an entry read by a MergeReader with the source it is from.
+type MergedEntry struct {
+	*JournalEntry
+	Source string
+}
+
+type mergeSource struct {
+	MergeSource
+	pending *JournalEntry // next entry of the source, nil if not read yet
+	cursor  string        // cursor of the last entry returned
+}
+
+// MergeReader merges the entries of several journals in the order of their
+// realtime timestamps, keeping a cursor per journal so reading can continue
+// later. Entries with the same timestamp are returned in the order the
+// sources were given.
+type MergeReader struct {
+	sources []*mergeSource
+}
+
+// NewMergeReader returns a reader for the entries of sources from their
+// current positions on. Source names must be unique.
+func NewMergeReader(sources ...MergeSource) (*MergeReader, error) {
+	r := &MergeReader{}
+	names := make(map[string]bool, len(sources))
+	for _, s := range sources {
+		if names[s.Name] {
+			return nil, fmt.Errorf("duplicate journal source %q", s.Name)
+		}
+		names[s.Name] = true
+		r.sources = append(r.sources, &mergeSource{MergeSource: s})
+	}
+	return r, nil
+}
+
+// Resume makes the sources with an entry in cursors continue after it, as
+// returned by Cursors.
+func (r *MergeReader) Resume(cursors map[string]string) error {
+	for _, s := range r.sources {
+		c, ok := cursors[s.Name]
+		if !ok {
+			continue
+		}
+		if err := s.Journal.SeekCursor(c); err != nil {
+			return fmt.Errorf("journal source %s: %v", s.Name, err)
+		}
+		s.pending, s.cursor = nil, c
+		e, err := s.read()
+		if err != nil {
+			return err
+		}
+		// The seek lands on the entry of the cursor, unless it has been
+		// rotated away since and the closest entry is unread.
+		if e != nil && e.Cursor != c {
+			s.pending = e
+		}
+	}
+	return nil
+}
+
+// read returns the next entry of s, or nil at its end.
+func (s *mergeSource) read() (*JournalEntry, error) {
+	n, err := s.Journal.Next()
+	if err != nil {
+		return nil, fmt.Errorf("journal source %s: %v", s.Name, err)
+	}
+	if n == 0 {
+		return nil, nil
+	}
+	e, err := s.Journal.GetEntry()
+	if err != nil {
+		return nil, fmt.Errorf("journal source %s: %v", s.Name, err)
+	}
+	return e, nil
+}
+
+// Next returns the oldest entry not returned yet over all sources, or io.EOF
+// if they are all at their end. As journals grow, Next can be called again
+// after the journals were waited for.
+func (r *MergeReader) Next() (*MergedEntry, error) {
+	if err := r.fill(); err != nil {
+		return nil, err
+	}
+	var next *mergeSource
+	for _, s := range r.sources {
+		if s.pending != nil && (next == nil || s.pending.RealtimeTimestamp < next.pending.RealtimeTimestamp) {
+			next = s
+		}
+	}
+	if next == nil {
+		return nil, io.EOF
+	}
+	e := next.pending
+	next.pending, next.cursor = nil, e.Cursor
+	return &MergedEntry{JournalEntry: e, Source: next.Name}, nil
+}
+
+// fill reads the next entry of every source that has none pending, reading
+// the sources concurrently.
+func (r *MergeReader) fill() error {
+	errs := make([]error, len(r.sources))
+	var wg sync.WaitGroup
+	for i, s := range r.sources {
+		if s.pending != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, s *mergeSource) {
+			defer wg.Done()
+			s.pending, errs[i] = s.read()
+		}(i, s)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cursors returns the cursor of the last entry returned from each source,
+// for passing to Resume. Sources no entry was returned from yet are left
+// out.
+func (r *MergeReader) Cursors() map[string]string {
+	cursors := make(map[string]string, len(r.sources))
+	for _, s := range r.sources {
+		if s.cursor != "" {
+			cursors[s.Name] = s.cursor
+		}
+	}
+	return cursors
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sdjournal
+
+import (
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// fakeJournal is an in-memory MergeJournal.
+type fakeJournal struct {
+	entries []*JournalEntry
+	pos     int // index of the current entry, -1 before the first
+}
+
+func newFakeJournal(name string, timestamps ...uint64) *fakeJournal {
+	j := &fakeJournal{pos: -1}
+	for i, ts := range timestamps {
+		j.add(name, i, ts)
+	}
+	return j
+}
+
+func (j *fakeJournal) add(name string, i int, ts uint64) {
+	j.entries = append(j.entries, &JournalEntry{
+		Fields:            map[string]string{SD_JOURNAL_FIELD_MESSAGE: name + strconv.Itoa(i)},
+		Cursor:            name + ";" + strconv.Itoa(i),
+		RealtimeTimestamp: ts,
+	})
+}
+
+func (j *fakeJournal) Next() (uint64, error) {
+	if j.pos+1 >= len(j.entries) {
+		return 0, nil
+	}
+	j.pos++
+	return 1, nil
+}
+
+func (j *fakeJournal) GetEntry() (*JournalEntry, error) {
+	return j.entries[j.pos], nil
+}
+
+func (j *fakeJournal) SeekCursor(cursor string) error {
+	for i, e := range j.entries {
+		if e.Cursor == cursor {
+			j.pos = i - 1
+			return nil
+		}
+	}
+	j.pos = -1
+	return nil
+}
+
+func readAll(t *testing.T, r *MergeReader) []string {
+	var msgs []string
+	for {
+		e, err := r.Next()
+		if err == io.EOF {
+			return msgs
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Source != e.Fields[SD_JOURNAL_FIELD_MESSAGE][:1] {
+			t.Errorf("entry %s from source %s", e.Fields[SD_JOURNAL_FIELD_MESSAGE], e.Source)
+		}
+		msgs = append(msgs, e.Fields[SD_JOURNAL_FIELD_MESSAGE])
+	}
+}
+
+func TestMergeReader(t *testing.T) {
+	a := newFakeJournal("a", 10, 20, 30, 50)
+	b := newFakeJournal("b", 5, 20, 40)
+	c := newFakeJournal("c")
+	r, err := NewMergeReader(MergeSource{"a", a}, MergeSource{"b", b}, MergeSource{"c", c})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := readAll(t, r)
+	want := []string{"b0", "a0", "a1", "b1", "a2", "b2", "a3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v", got)
+	}
+	cursors := r.Cursors()
+	if !reflect.DeepEqual(cursors, map[string]string{"a": "a;3", "b": "b;2"}) {
+		t.Errorf("unexpected cursors %v", cursors)
+	}
+
+	// Entries appended later are read on the next call.
+	c.add("c", 0, 60)
+	b.add("b", 3, 45)
+	if got := readAll(t, r); !reflect.DeepEqual(got, []string{"b3", "c0"}) {
+		t.Errorf("got %v after growing", got)
+	}
+
+	r, err = NewMergeReader(MergeSource{"a", newFakeJournal("a", 10, 20, 30, 50)}, MergeSource{"b", newFakeJournal("b", 5, 20, 40)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Resume(map[string]string{"a": "a;1", "b": "b;0"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readAll(t, r); !reflect.DeepEqual(got, []string{"b1", "a2", "b2", "a3"}) {
+		t.Errorf("got %v after resuming", got)
+	}
+
+	// A cursor that was rotated away resumes at the oldest entry.
+	r, _ = NewMergeReader(MergeSource{"a", newFakeJournal("a", 10, 20)})
+	if err := r.Resume(map[string]string{"a": "a;gone"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := readAll(t, r); !reflect.DeepEqual(got, []string{"a0", "a1"}) {
+		t.Errorf("got %v after resuming at lost cursor", got)
+	}
+
+	if _, err := NewMergeReader(MergeSource{"a", a}, MergeSource{"a", b}); err == nil {
+		t.Error("expected error for duplicate source name")
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/coreos/go-systemd/v22/journal"
 )
 
 var (
@@ -52,6 +54,12 @@ type JournalReaderConfig struct {
 	// into strings. If not set, the default format (timestamp and message field)
 	// will be used. If Formatter returns an error, Read will stop and return the error.
 	Formatter func(entry *JournalEntry) (string, error)
+
+	// If not nil, the fields of entries encrypted with a
+	// journal.FieldEncryptor using the same key are decrypted before the
+	// entries are formatted. If a field cannot be decrypted, Read will stop
+	// and return the error.
+	Encryptor *journal.FieldEncryptor
 }
 
 // JournalReader is an io.ReadCloser which provides a simple interface for iterating through the
@@ -60,6 +68,7 @@ type JournalReader struct {
 	journal   *Journal
 	msgReader *strings.Reader
 	formatter func(entry *JournalEntry) (string, error)
+	encryptor *journal.FieldEncryptor
 }
 
 // NewJournalReader creates a new JournalReader with configuration options that are similar to the
@@ -72,6 +81,7 @@ func NewJournalReader(config JournalReaderConfig) (*JournalReader, error) {
 
 	r := &JournalReader{
 		formatter: config.Formatter,
+		encryptor: config.Encryptor,
 	}
 
 	// Open the journal
@@ -155,6 +165,11 @@ func (r *JournalReader) Read(b []byte) (int, error) {
 		if err != nil {
 			return 0, err
 		}
+		if r.encryptor != nil {
+			if err := r.encryptor.Decrypt(entry.Fields); err != nil {
+				return 0, err
+			}
+		}
 
 		// Build a message
 		msg, err := r.formatter(entry)
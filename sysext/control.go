@@ -0,0 +1,88 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysext
+
+import (
+	"context"
+
+	"github.com/coreos/go-systemd/v22/varlink"
+)
+
+// Socket is the varlink socket of systemd-sysext, which also serves
+// configuration extensions. It is a variable so it can be changed by tests.
+var Socket = "/run/systemd/io.systemd.sysext"
+
+// Units that merge the extensions of each class at boot. On systems whose
+// systemd-sysext does not offer the varlink interface yet, restarting the
+// unit with the dbus package refreshes the merged extensions instead.
+const (
+	SysextUnit  = "systemd-sysext.service"
+	ConfextUnit = "systemd-confext.service"
+)
+
+// Options modify how extensions are merged.
+type Options struct {
+	// Force merges extensions even if their release files do not match
+	// the host.
+	Force bool
+	// NoReload skips reloading the service manager after merging, which
+	// is otherwise done if any extension ships unit files.
+	NoReload bool
+	// NoExec mounts the merged extensions noexec.
+	NoExec bool
+}
+
+type mergeParameters struct {
+	Class    Class `json:"class,omitempty"`
+	Force    bool  `json:"force,omitempty"`
+	NoReload bool  `json:"noReload,omitempty"`
+	NoExec   bool  `json:"noexec,omitempty"`
+}
+
+type unmergeParameters struct {
+	Class    Class `json:"class,omitempty"`
+	NoReload bool  `json:"noReload,omitempty"`
+}
+
+func newMergeParameters(c Class, opts *Options) mergeParameters {
+	p := mergeParameters{Class: c}
+	if opts != nil {
+		p.Force = opts.Force
+		p.NoReload = opts.NoReload
+		p.NoExec = opts.NoExec
+	}
+	return p
+}
+
+// Merge merges the extension images of class c in the search path into the
+// host. It fails if extensions of the class are merged already.
+func Merge(ctx context.Context, c Class, opts *Options) error {
+	return varlink.Call(ctx, Socket, "io.systemd.sysext.Merge", newMergeParameters(c, opts), nil)
+}
+
+// Unmerge removes the merged extension images of class c from the host.
+func Unmerge(ctx context.Context, c Class, opts *Options) error {
+	p := unmergeParameters{Class: c}
+	if opts != nil {
+		p.NoReload = opts.NoReload
+	}
+	return varlink.Call(ctx, Socket, "io.systemd.sysext.Unmerge", p, nil)
+}
+
+// Refresh merges the extension images of class c again, picking up images
+// added, removed or updated since they were last merged.
+func Refresh(ctx context.Context, c Class, opts *Options) error {
+	return varlink.Call(ctx, Socket, "io.systemd.sysext.Refresh", newMergeParameters(c, opts), nil)
+}
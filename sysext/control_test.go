@@ -0,0 +1,95 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysext
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/varlink"
+)
+
+func TestControl(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sysext-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "io.systemd.sysext")
+	l, err := varlink.Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type call struct {
+		method     string
+		parameters map[string]interface{}
+	}
+	calls := make(chan call, 1)
+	s := &varlink.Server{Handler: func(ctx context.Context, c *varlink.MethodCall) error {
+		var p map[string]interface{}
+		if err := json.Unmarshal(c.Parameters, &p); err != nil {
+			return err
+		}
+		calls <- call{c.Method, p}
+		return nil
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Serve(ctx, l)
+
+	saved := Socket
+	Socket = path
+	defer func() { Socket = saved }()
+
+	tests := []struct {
+		fn         func() error
+		method     string
+		parameters map[string]interface{}
+	}{
+		{
+			func() error { return Merge(ctx, ClassSysext, nil) },
+			"io.systemd.sysext.Merge",
+			map[string]interface{}{"class": "sysext"},
+		},
+		{
+			func() error { return Refresh(ctx, ClassConfext, &Options{Force: true, NoExec: true}) },
+			"io.systemd.sysext.Refresh",
+			map[string]interface{}{"class": "confext", "force": true, "noexec": true},
+		},
+		{
+			func() error { return Unmerge(ctx, ClassSysext, &Options{Force: true, NoReload: true}) },
+			"io.systemd.sysext.Unmerge",
+			map[string]interface{}{"class": "sysext", "noReload": true},
+		},
+	}
+
+	for i, tt := range tests {
+		if err := tt.fn(); err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		c := <-calls
+		if c.method != tt.method || !reflect.DeepEqual(c.parameters, tt.parameters) {
+			t.Errorf("case %d: expected %s %v, got %s %v", i, tt.method, tt.parameters, c.method, c.parameters)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysext
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SearchPaths are the directories searched for extension images of each
+// class, in order of precedence. It is a variable so it can be changed by
+// tests.
+var SearchPaths = map[Class][]string{
+	ClassSysext:  {"/etc/extensions", "/run/extensions", "/var/lib/extensions", "/usr/local/lib/extensions", "/usr/lib/extensions"},
+	ClassConfext: {"/run/confexts", "/var/lib/confexts", "/usr/local/lib/confexts", "/usr/lib/confexts"},
+}
+
+// Image is an extension image found in the search path.
+type Image struct {
+	Name  string
+	Class Class
+	Path  string
+	// Type is directory or raw. Raw disk images need to be mounted, for
+	// example with systemd-dissect, before their release file can be read.
+	Type string
+}
+
+// ListImages returns the extension images of class c in the search path,
+// sorted by name. Images of the same name in directories of lower precedence
+// are shadowed, as are hidden files.
+func ListImages(c Class) ([]Image, error) {
+	seen := map[string]bool{}
+	images := []Image{}
+	for _, dir := range SearchPaths[c] {
+		entries, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			fi, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+
+			var image Image
+			switch {
+			case fi.IsDir():
+				image = Image{Name: e.Name(), Type: "directory"}
+			case fi.Mode().IsRegular() && strings.HasSuffix(e.Name(), ".raw"):
+				image = Image{Name: strings.TrimSuffix(e.Name(), ".raw"), Type: "raw"}
+			default:
+				continue
+			}
+			if seen[image.Name] {
+				continue
+			}
+			seen[image.Name] = true
+			image.Class = c
+			image.Path = path
+			images = append(images, image)
+		}
+	}
+
+	sort.Slice(images, func(i, j int) bool { return images[i].Name < images[j].Name })
+	return images, nil
+}
+
+// Release reads the extension-release file of a directory image. For raw
+// images, mount the image and use ReadExtensionRelease.
+func (i *Image) Release() (map[string]string, error) {
+	if i.Type != "directory" {
+		return nil, fmt.Errorf("%s is a %s image, which must be mounted to read its extension-release file", i.Path, i.Type)
+	}
+	return ReadExtensionRelease(i.Path, i.Name, i.Class)
+}
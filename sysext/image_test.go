@@ -0,0 +1,83 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysext
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListImages(t *testing.T) {
+	root, err := ioutil.TempDir("", "sysext-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	etc := filepath.Join(root, "etc/extensions")
+	lib := filepath.Join(root, "usr/lib/extensions")
+	for _, dir := range []string{
+		filepath.Join(etc, "foo"),
+		filepath.Join(etc, ".hidden"),
+		filepath.Join(lib, "foo"),
+		filepath.Join(lib, "baz/usr/lib/extension-release.d"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for path, content := range map[string]string{
+		filepath.Join(lib, "bar.raw"):   "",
+		filepath.Join(lib, "notes.txt"): "",
+		filepath.Join(lib, "baz/usr/lib/extension-release.d/extension-release.baz"): "ID=_any\n",
+	} {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saved := SearchPaths
+	SearchPaths = map[Class][]string{ClassSysext: {etc, filepath.Join(root, "run/extensions"), lib}}
+	defer func() { SearchPaths = saved }()
+
+	images, err := ListImages(ClassSysext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Image{
+		{Name: "bar", Class: ClassSysext, Path: filepath.Join(lib, "bar.raw"), Type: "raw"},
+		{Name: "baz", Class: ClassSysext, Path: filepath.Join(lib, "baz"), Type: "directory"},
+		{Name: "foo", Class: ClassSysext, Path: filepath.Join(etc, "foo"), Type: "directory"},
+	}
+	if !reflect.DeepEqual(images, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, images)
+	}
+
+	release, err := images[1].Release()
+	if err != nil || release["ID"] != "_any" {
+		t.Errorf("unexpected release %v, %v", release, err)
+	}
+	if _, err := images[0].Release(); err == nil {
+		t.Error("expected error reading release of raw image")
+	}
+
+	images, err = ListImages(ClassConfext)
+	if err != nil || len(images) != 0 {
+		t.Errorf("expected no confext images, got %v, %v", images, err)
+	}
+}
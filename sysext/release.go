@@ -0,0 +1,222 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sysext inspects system and configuration extension images and
+// controls systemd-sysext and systemd-confext, which merge them into /usr
+// and /etc.  See https://www.freedesktop.org/software/systemd/man/systemd-sysext.html
+package sysext
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// Class is the kind of extension image, either a system extension for /usr
+// and /opt or a configuration extension for /etc.
+type Class string
+
+const (
+	ClassSysext  Class = "sysext"
+	ClassConfext Class = "confext"
+)
+
+// Scopes an extension may declare in SYSEXT_SCOPE= or CONFEXT_SCOPE=.
+const (
+	ScopeSystem   = "system"
+	ScopeInitrd   = "initrd"
+	ScopePortable = "portable"
+)
+
+// systemdArchitectures maps GOARCH values to systemd's architecture names.
+var systemdArchitectures = map[string]string{
+	"386":      "x86",
+	"amd64":    "x86-64",
+	"arm":      "arm",
+	"arm64":    "arm64",
+	"loong64":  "loongarch64",
+	"mips64le": "mips64-le",
+	"mipsle":   "mips-le",
+	"ppc64":    "ppc64",
+	"ppc64le":  "ppc64-le",
+	"riscv64":  "riscv64",
+	"s390x":    "s390x",
+}
+
+// releaseDir returns the directory of the extension-release files of an
+// image of class c, relative to the image root.
+func (c Class) releaseDir() string {
+	if c == ClassConfext {
+		return "etc/extension-release.d"
+	}
+	return "usr/lib/extension-release.d"
+}
+
+// levelField returns the release field holding the extension API level.
+func (c Class) levelField() string {
+	if c == ClassConfext {
+		return "CONFEXT_LEVEL"
+	}
+	return "SYSEXT_LEVEL"
+}
+
+// scopeField returns the release field holding the scopes of an extension.
+func (c Class) scopeField() string {
+	if c == ClassConfext {
+		return "CONFEXT_SCOPE"
+	}
+	return "SYSEXT_SCOPE"
+}
+
+// ParseRelease parses an os-release(5) or extension-release file.
+func ParseRelease(r io.Reader) (map[string]string, error) {
+	fields := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			continue
+		}
+		value := line[i+1:]
+		if words, err := unit.SplitWords(value); err == nil && len(words) == 1 {
+			value = words[0]
+		}
+		fields[line[:i]] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func readRelease(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseRelease(f)
+}
+
+// ReadHostRelease reads the os-release file of the operating system below
+// root, preferring /etc/os-release over /usr/lib/os-release.
+func ReadHostRelease(root string) (map[string]string, error) {
+	fields, err := readRelease(filepath.Join(root, "etc/os-release"))
+	if os.IsNotExist(err) {
+		fields, err = readRelease(filepath.Join(root, "usr/lib/os-release"))
+	}
+	return fields, err
+}
+
+// ReadExtensionRelease reads the extension-release file of the extension
+// image called name of class c, unpacked or mounted at root. If the image
+// has been renamed and carries no file for name, the only extension-release
+// file in the image is used.
+func ReadExtensionRelease(root, name string, c Class) (map[string]string, error) {
+	dir := filepath.Join(root, c.releaseDir())
+	fields, err := readRelease(filepath.Join(dir, "extension-release."+name))
+	if !os.IsNotExist(err) {
+		return fields, err
+	}
+
+	entries, dirErr := ioutil.ReadDir(dir)
+	if dirErr != nil && !os.IsNotExist(dirErr) {
+		return nil, dirErr
+	}
+	var found []string
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "extension-release.") && e.Mode().IsRegular() {
+			found = append(found, e.Name())
+		}
+	}
+	if len(found) != 1 {
+		return nil, err
+	}
+	return readRelease(filepath.Join(dir, found[0]))
+}
+
+// Validate checks whether an extension with the given extension-release
+// fields may be merged into the host with the given os-release fields, like
+// systemd-sysext does. scope is the scope of the host, ScopeSystem or
+// ScopeInitrd, or empty to skip the check. A nil error means the extension
+// is compatible.
+func Validate(name string, c Class, host, ext map[string]string, scope string) error {
+	if scope != "" {
+		if scopes, ok := ext[c.scopeField()]; ok {
+			if !containsWord(scopes, scope) {
+				return fmt.Errorf("extension %q is not meant for scope %s", name, scope)
+			}
+		}
+	}
+
+	if arch := ext["ARCHITECTURE"]; arch != "" && arch != "_any" && arch != systemdArchitectures[runtime.GOARCH] {
+		return fmt.Errorf("extension %q is for architecture %s", name, arch)
+	}
+
+	id := ext["ID"]
+	if id == "" {
+		return fmt.Errorf("extension %q does not declare an ID", name)
+	}
+	if id == "_any" {
+		return nil
+	}
+	if id != host["ID"] {
+		return fmt.Errorf("extension %q is for %s, not %s", name, id, host["ID"])
+	}
+
+	// Rolling releases typically set neither, so any version matches.
+	hostLevel, hostVersion := host[c.levelField()], host["VERSION_ID"]
+	if hostLevel == "" && hostVersion == "" {
+		return nil
+	}
+
+	// An extension API level, if both declare one, takes precedence over
+	// the version of the operating system.
+	if extLevel := ext[c.levelField()]; hostLevel != "" && extLevel != "" {
+		if extLevel != hostLevel {
+			return fmt.Errorf("extension %q is for %s %s, not %s", name, c.levelField(), extLevel, hostLevel)
+		}
+		return nil
+	}
+	if hostVersion != "" {
+		extVersion := ext["VERSION_ID"]
+		if extVersion == "" {
+			return fmt.Errorf("extension %q does not declare a VERSION_ID", name)
+		}
+		if extVersion != hostVersion {
+			return fmt.Errorf("extension %q is for version %s, not %s", name, extVersion, hostVersion)
+		}
+	}
+	return nil
+}
+
+func containsWord(s, word string) bool {
+	for _, w := range strings.Fields(s) {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysext
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseRelease(t *testing.T) {
+	fields, err := ParseRelease(strings.NewReader("# comment\nID=fedora\nVERSION_ID=\"40\"\n\nSYSEXT_SCOPE='system initrd'\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields["ID"] != "fedora" || fields["VERSION_ID"] != "40" || fields["SYSEXT_SCOPE"] != "system initrd" || len(fields) != 3 {
+		t.Errorf("unexpected fields %v", fields)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	arch := systemdArchitectures[runtime.GOARCH]
+	fedora := map[string]string{"ID": "fedora", "VERSION_ID": "40"}
+	flatcar := map[string]string{"ID": "flatcar", "VERSION_ID": "3815.2.0", "SYSEXT_LEVEL": "1.0"}
+	rolling := map[string]string{"ID": "arch"}
+
+	tests := []struct {
+		class Class
+		host  map[string]string
+		ext   map[string]string
+		scope string
+		ok    bool
+	}{
+		{ClassSysext, fedora, map[string]string{"ID": "fedora", "VERSION_ID": "40"}, "", true},
+		{ClassSysext, fedora, map[string]string{"ID": "fedora", "VERSION_ID": "39"}, "", false},
+		{ClassSysext, fedora, map[string]string{"ID": "fedora"}, "", false},
+		{ClassSysext, fedora, map[string]string{"ID": "debian", "VERSION_ID": "40"}, "", false},
+		{ClassSysext, fedora, map[string]string{"VERSION_ID": "40"}, "", false},
+		{ClassSysext, fedora, map[string]string{"ID": "_any"}, "", true},
+		{ClassSysext, rolling, map[string]string{"ID": "arch"}, "", true},
+		{ClassSysext, flatcar, map[string]string{"ID": "flatcar", "SYSEXT_LEVEL": "1.0"}, "", true},
+		{ClassSysext, flatcar, map[string]string{"ID": "flatcar", "SYSEXT_LEVEL": "2.0"}, "", false},
+		{ClassSysext, flatcar, map[string]string{"ID": "flatcar", "VERSION_ID": "3815.2.0"}, "", true},
+		{ClassConfext, flatcar, map[string]string{"ID": "flatcar", "SYSEXT_LEVEL": "1.0"}, "", false},
+		{ClassConfext, flatcar, map[string]string{"ID": "flatcar", "CONFEXT_LEVEL": "1.0", "VERSION_ID": "3815.2.0"}, "", true},
+		{ClassSysext, fedora, map[string]string{"ID": "_any", "SYSEXT_SCOPE": "initrd"}, ScopeSystem, false},
+		{ClassSysext, fedora, map[string]string{"ID": "_any", "SYSEXT_SCOPE": "system initrd"}, ScopeSystem, true},
+		{ClassSysext, fedora, map[string]string{"ID": "_any", "SYSEXT_SCOPE": "initrd"}, "", true},
+		{ClassSysext, fedora, map[string]string{"ID": "_any", "ARCHITECTURE": arch}, "", true},
+		{ClassSysext, fedora, map[string]string{"ID": "_any", "ARCHITECTURE": "_any"}, "", true},
+		{ClassSysext, fedora, map[string]string{"ID": "_any", "ARCHITECTURE": "alpha"}, "", false},
+	}
+
+	for i, tt := range tests {
+		err := Validate("foo", tt.class, tt.host, tt.ext, tt.scope)
+		if tt.ok && err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+		} else if !tt.ok && err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestReadRelease(t *testing.T) {
+	root, err := ioutil.TempDir("", "sysext-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	for path, content := range map[string]string{
+		"usr/lib/os-release": "ID=fedora\n",
+		"usr/lib/extension-release.d/extension-release.foo": "ID=_any\n",
+		"etc/extension-release.d/extension-release.bar":     "ID=fedora\nCONFEXT_LEVEL=1\n",
+	} {
+		path = filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	host, err := ReadHostRelease(root)
+	if err != nil || host["ID"] != "fedora" {
+		t.Errorf("unexpected host release %v, %v", host, err)
+	}
+
+	ext, err := ReadExtensionRelease(root, "foo", ClassSysext)
+	if err != nil || ext["ID"] != "_any" {
+		t.Errorf("unexpected extension release %v, %v", ext, err)
+	}
+
+	// A renamed image falls back to its only release file.
+	ext, err = ReadExtensionRelease(root, "renamed", ClassConfext)
+	if err != nil || ext["CONFEXT_LEVEL"] != "1" {
+		t.Errorf("unexpected renamed extension release %v, %v", ext, err)
+	}
+
+	if _, err := ReadExtensionRelease(filepath.Join(root, "missing"), "foo", ClassSysext); !os.IsNotExist(err) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,497 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sysupdate reads and writes the sysupdate.d transfer definitions
+// systemd-sysupdate downloads and installs new versions of images and
+// partitions from.  See
+// https://www.freedesktop.org/software/systemd/man/sysupdate.d.html
+package sysupdate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// ResourceType is the kind of resource a transfer reads from or writes to.
+type ResourceType string
+
+const (
+	ResourceURLFile     ResourceType = "url-file"
+	ResourceURLTar      ResourceType = "url-tar"
+	ResourceTar         ResourceType = "tar"
+	ResourceRegularFile ResourceType = "regular-file"
+	ResourceDirectory   ResourceType = "directory"
+	ResourceSubvolume   ResourceType = "subvolume"
+	ResourcePartition   ResourceType = "partition"
+)
+
+// TransferSection is the [Transfer] section of a transfer definition.
+type TransferSection struct {
+	MinVersion     string
+	ProtectVersion string // May contain specifiers, e.g. "%A"
+	// Verify checks the SHA256SUMS manifest of url-* sources against its
+	// signature, which systemd-sysupdate does unless set to false.
+	Verify            *bool
+	Features          []string
+	RequisiteFeatures []string
+}
+
+// SourceSection is the [Source] section of a transfer definition.
+type SourceSection struct {
+	Type ResourceType
+	Path string // A URL for url-* types
+	// MatchPattern are the patterns of the names of source versions, each
+	// containing "@v" where the version goes.
+	MatchPattern []string
+}
+
+// TargetSection is the [Target] section of a transfer definition.
+type TargetSection struct {
+	Type           ResourceType
+	Path           string // "auto" selects the block device of the root file system for partitions
+	PathRelativeTo string
+	MatchPattern   []string
+	// MatchPartitionType is the GPT partition type, a designator like
+	// "root" or a UUID, of partitions targets are installed into.
+	MatchPartitionType      string
+	PartitionUUID           string
+	PartitionFlags          *uint64
+	PartitionNoAuto         *bool
+	PartitionGrowFileSystem *bool
+	Mode                    *uint32
+	TriesLeft               *uint
+	TriesDone               *uint
+	InstancesMax            uint
+	RemoveTemporary         *bool
+	CurrentSymlink          string
+	ReadOnly                *bool
+}
+
+// Transfer is a sysupdate.d transfer definition.
+type Transfer struct {
+	Transfer TransferSection
+	Source   SourceSection
+	Target   TargetSection
+
+	// Unknown holds the options this package does not know, which are
+	// written back unchanged.
+	Unknown []*unit.UnitOption
+}
+
+// Wildcards are the fields a MatchPattern may contain besides the
+// mandatory "@v" for the version.
+var Wildcards = map[byte]string{
+	'v': "version",
+	'u': "partition UUID",
+	'f': "partition flags",
+	'a': "GPT NoAuto flag",
+	'g': "GPT GrowFileSystem flag",
+	'r': "read-only flag",
+	't': "modification time",
+	'm': "file mode",
+	's': "file size",
+	'd': "tries done",
+	'l': "tries left",
+	'h': "SHA256 hash",
+}
+
+// ValidatePattern checks that pattern contains "@v" exactly once and only
+// known wildcards.
+func ValidatePattern(pattern string) error {
+	versions := 0
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '@' {
+			continue
+		}
+		if i+1 == len(pattern) {
+			return fmt.Errorf("pattern %q ends in @", pattern)
+		}
+		i++
+		if pattern[i] == '@' {
+			continue
+		}
+		if _, ok := Wildcards[pattern[i]]; !ok {
+			return fmt.Errorf("unknown wildcard @%c in pattern %q", pattern[i], pattern)
+		}
+		if pattern[i] == 'v' {
+			versions++
+		}
+	}
+	if versions != 1 {
+		return fmt.Errorf("pattern %q must contain @v exactly once", pattern)
+	}
+	return nil
+}
+
+// ExpandPattern replaces the @v wildcard in pattern with version, giving the
+// name of that version of a resource. Patterns containing other wildcards
+// cannot be expanded.
+func ExpandPattern(pattern, version string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '@' || i+1 == len(pattern) {
+			b.WriteByte(pattern[i])
+			continue
+		}
+		i++
+		switch pattern[i] {
+		case '@':
+			b.WriteByte('@')
+		case 'v':
+			b.WriteString(version)
+		default:
+			return "", fmt.Errorf("cannot expand wildcard @%c in pattern %q", pattern[i], pattern)
+		}
+	}
+	return b.String(), nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "1", "yes", "y", "true", "t", "on":
+		return true, nil
+	case "0", "no", "n", "false", "f", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q", s)
+}
+
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// ParseTransfer parses a sysupdate.d transfer definition.
+func ParseTransfer(r io.Reader) (*Transfer, error) {
+	opts, err := unit.DeserializeOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	t := &Transfer{}
+	for _, o := range opts {
+		if err := t.set(o.Section, o.Name, o.Value); err != nil {
+			return nil, fmt.Errorf("[%s] %s=%s: %v", o.Section, o.Name, o.Value, err)
+		}
+	}
+	return t, nil
+}
+
+func (t *Transfer) set(section, name, value string) error {
+	setBool := func(dst **bool) error {
+		if value == "" {
+			*dst = nil
+			return nil
+		}
+		b, err := parseBool(value)
+		if err != nil {
+			return err
+		}
+		*dst = &b
+		return nil
+	}
+	setUint := func(dst **uint) error {
+		if value == "" {
+			*dst = nil
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return err
+		}
+		u := uint(n)
+		*dst = &u
+		return nil
+	}
+	words := func(dst *[]string) error {
+		if value == "" {
+			*dst = nil
+			return nil
+		}
+		w, err := unit.SplitWords(value)
+		if err != nil {
+			return err
+		}
+		*dst = append(*dst, w...)
+		return nil
+	}
+
+	tr, src, tgt := &t.Transfer, &t.Source, &t.Target
+	switch section + "." + name {
+	case "Transfer.MinVersion":
+		tr.MinVersion = value
+	case "Transfer.ProtectVersion":
+		tr.ProtectVersion = value
+	case "Transfer.Verify":
+		return setBool(&tr.Verify)
+	case "Transfer.Features":
+		return words(&tr.Features)
+	case "Transfer.RequisiteFeatures":
+		return words(&tr.RequisiteFeatures)
+	case "Target.InstancesMax":
+		if value == "" {
+			tgt.InstancesMax = 0
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || n < 2 {
+			return fmt.Errorf("InstancesMax= must be at least 2")
+		}
+		tgt.InstancesMax = uint(n)
+	case "Target.RemoveTemporary":
+		return setBool(&tgt.RemoveTemporary)
+	case "Source.Type":
+		src.Type = ResourceType(value)
+	case "Source.Path":
+		src.Path = value
+	case "Source.MatchPattern":
+		return words(&src.MatchPattern)
+	case "Target.Type":
+		tgt.Type = ResourceType(value)
+	case "Target.Path":
+		tgt.Path = value
+	case "Target.PathRelativeTo":
+		tgt.PathRelativeTo = value
+	case "Target.MatchPattern":
+		return words(&tgt.MatchPattern)
+	case "Target.MatchPartitionType":
+		tgt.MatchPartitionType = value
+	case "Target.PartitionUUID":
+		tgt.PartitionUUID = value
+	case "Target.PartitionFlags":
+		if value == "" {
+			tgt.PartitionFlags = nil
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 0, 64)
+		if err != nil {
+			return err
+		}
+		tgt.PartitionFlags = &n
+	case "Target.PartitionNoAuto":
+		return setBool(&tgt.PartitionNoAuto)
+	case "Target.PartitionGrowFileSystem":
+		return setBool(&tgt.PartitionGrowFileSystem)
+	case "Target.Mode":
+		if value == "" {
+			tgt.Mode = nil
+			return nil
+		}
+		n, err := strconv.ParseUint(value, 8, 32)
+		if err != nil || n > 07777 {
+			return fmt.Errorf("invalid mode")
+		}
+		m := uint32(n)
+		tgt.Mode = &m
+	case "Target.TriesLeft":
+		return setUint(&tgt.TriesLeft)
+	case "Target.TriesDone":
+		return setUint(&tgt.TriesDone)
+	case "Target.CurrentSymlink":
+		tgt.CurrentSymlink = value
+	case "Target.ReadOnly":
+		return setBool(&tgt.ReadOnly)
+	default:
+		t.Unknown = append(t.Unknown, unit.NewUnitOption(section, name, value))
+	}
+	return nil
+}
+
+// Validate checks the definition for settings systemd-sysupdate rejects.
+func (t *Transfer) Validate() error {
+	switch t.Source.Type {
+	case ResourceURLFile, ResourceURLTar:
+		if !strings.Contains(t.Source.Path, "://") {
+			return fmt.Errorf("source of type %s needs a URL as Path=", t.Source.Type)
+		}
+	case ResourceTar, ResourceRegularFile, ResourceDirectory, ResourceSubvolume, ResourcePartition:
+		if t.Source.Path == "" {
+			return fmt.Errorf("source needs a Path=")
+		}
+	case "":
+		return fmt.Errorf("source needs a Type=")
+	default:
+		return fmt.Errorf("unknown source type %q", t.Source.Type)
+	}
+
+	switch t.Target.Type {
+	case ResourcePartition:
+		if t.Target.MatchPartitionType == "" {
+			return fmt.Errorf("partition targets need MatchPartitionType=")
+		}
+		if t.Source.Type != ResourceURLFile && t.Source.Type != ResourceRegularFile && t.Source.Type != ResourcePartition {
+			return fmt.Errorf("partition targets need a file or partition source")
+		}
+	case ResourceRegularFile:
+		if t.Source.Type != ResourceURLFile && t.Source.Type != ResourceRegularFile {
+			return fmt.Errorf("regular-file targets need a file source")
+		}
+	case ResourceDirectory, ResourceSubvolume:
+		if t.Source.Type != ResourceURLTar && t.Source.Type != ResourceTar && t.Source.Type != ResourceDirectory && t.Source.Type != ResourceSubvolume {
+			return fmt.Errorf("%s targets need a tar or directory source", t.Target.Type)
+		}
+	case "":
+		return fmt.Errorf("target needs a Type=")
+	default:
+		return fmt.Errorf("unknown target type %q", t.Target.Type)
+	}
+	if t.Target.Path == "" && t.Target.Type != ResourcePartition {
+		return fmt.Errorf("target needs a Path=")
+	}
+	switch t.Target.PathRelativeTo {
+	case "", "root", "esp", "xbootldr", "boot":
+	default:
+		return fmt.Errorf("invalid PathRelativeTo= %q", t.Target.PathRelativeTo)
+	}
+
+	if len(t.Source.MatchPattern) == 0 || len(t.Target.MatchPattern) == 0 {
+		return fmt.Errorf("source and target need MatchPattern=")
+	}
+	for _, p := range append(append([]string{}, t.Source.MatchPattern...), t.Target.MatchPattern...) {
+		if err := ValidatePattern(p); err != nil {
+			return err
+		}
+	}
+	if t.Target.TriesLeft != nil || t.Target.TriesDone != nil {
+		// Boot counters are part of the file name, so the pattern needs to
+		// say where.
+		found := false
+		for _, p := range t.Target.MatchPattern {
+			found = found || strings.Contains(p, "@l")
+		}
+		if !found {
+			return fmt.Errorf("TriesLeft= requires a target MatchPattern= with @l")
+		}
+	}
+	return nil
+}
+
+// Sections returns the definition as sections of a sysupdate.d file.
+func (t *Transfer) Sections() []*unit.UnitSection {
+	var sections []*unit.UnitSection
+	var cur *unit.UnitSection
+	section := func(name string) {
+		cur = &unit.UnitSection{Section: name}
+		sections = append(sections, cur)
+	}
+	add := func(name, value string) {
+		if value != "" {
+			cur.Entries = append(cur.Entries, &unit.UnitEntry{Name: name, Value: value})
+		}
+	}
+	addBool := func(name string, b *bool) {
+		if b != nil {
+			add(name, formatBool(*b))
+		}
+	}
+	addUint := func(name string, n *uint) {
+		if n != nil {
+			add(name, strconv.FormatUint(uint64(*n), 10))
+		}
+	}
+	addWords := func(name string, values []string) {
+		if len(values) > 0 {
+			add(name, strings.Join(values, " "))
+		}
+	}
+
+	section("Transfer")
+	add("MinVersion", t.Transfer.MinVersion)
+	add("ProtectVersion", t.Transfer.ProtectVersion)
+	addBool("Verify", t.Transfer.Verify)
+	addWords("Features", t.Transfer.Features)
+	addWords("RequisiteFeatures", t.Transfer.RequisiteFeatures)
+
+	section("Source")
+	add("Type", string(t.Source.Type))
+	add("Path", t.Source.Path)
+	addWords("MatchPattern", t.Source.MatchPattern)
+
+	tgt := &t.Target
+	section("Target")
+	add("Type", string(tgt.Type))
+	add("Path", tgt.Path)
+	add("PathRelativeTo", tgt.PathRelativeTo)
+	addWords("MatchPattern", tgt.MatchPattern)
+	add("MatchPartitionType", tgt.MatchPartitionType)
+	add("PartitionUUID", tgt.PartitionUUID)
+	if tgt.PartitionFlags != nil {
+		add("PartitionFlags", fmt.Sprintf("0x%x", *tgt.PartitionFlags))
+	}
+	addBool("PartitionNoAuto", tgt.PartitionNoAuto)
+	addBool("PartitionGrowFileSystem", tgt.PartitionGrowFileSystem)
+	if tgt.Mode != nil {
+		add("Mode", fmt.Sprintf("%04o", *tgt.Mode))
+	}
+	addUint("TriesLeft", tgt.TriesLeft)
+	addUint("TriesDone", tgt.TriesDone)
+	if tgt.InstancesMax != 0 {
+		add("InstancesMax", strconv.FormatUint(uint64(tgt.InstancesMax), 10))
+	}
+	addBool("RemoveTemporary", tgt.RemoveTemporary)
+	add("CurrentSymlink", tgt.CurrentSymlink)
+	addBool("ReadOnly", tgt.ReadOnly)
+
+	for _, o := range t.Unknown {
+		cur = nil
+		for _, s := range sections {
+			if s.Section == o.Section {
+				cur = s
+			}
+		}
+		if cur == nil {
+			section(o.Section)
+		}
+		cur.Entries = append(cur.Entries, &unit.UnitEntry{Name: o.Name, Value: o.Value})
+	}
+	return sections
+}
+
+// Serialize validates and encodes the definition.
+func (t *Transfer) Serialize() (io.Reader, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return unit.SerializeSections(t.Sections()), nil
+}
+
+// ReadTransfer reads the transfer definition at path.
+func ReadTransfer(path string) (*Transfer, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTransfer(bytes.NewReader(b))
+}
+
+// WriteTransfer validates the definition and writes it to path, which must
+// end in ".transfer" to be picked up.
+func WriteTransfer(path string, t *Transfer) error {
+	r, err := t.Serialize()
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
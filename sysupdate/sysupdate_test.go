@@ -0,0 +1,192 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysupdate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testTransfer = `[Transfer]
+ProtectVersion=%A
+Verify=no
+
+[Source]
+Type=url-file
+Path=https://download.example.com/
+MatchPattern=foobarOS_@v.root.xz
+
+[Target]
+Type=partition
+Path=auto
+MatchPattern=foobarOS_@v
+MatchPartitionType=root
+PartitionFlags=0
+ReadOnly=1
+InstancesMax=2
+`
+
+func TestParseTransfer(t *testing.T) {
+	tr, err := ParseTransfer(strings.NewReader(testTransfer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Transfer.ProtectVersion != "%A" || tr.Transfer.Verify == nil || *tr.Transfer.Verify {
+		t.Errorf("unexpected transfer section %+v", tr.Transfer)
+	}
+	want := SourceSection{Type: ResourceURLFile, Path: "https://download.example.com/", MatchPattern: []string{"foobarOS_@v.root.xz"}}
+	if !reflect.DeepEqual(tr.Source, want) {
+		t.Errorf("unexpected source section %+v", tr.Source)
+	}
+	tgt := tr.Target
+	if tgt.Type != ResourcePartition || tgt.MatchPartitionType != "root" || tgt.InstancesMax != 2 {
+		t.Errorf("unexpected target section %+v", tgt)
+	}
+	if tgt.PartitionFlags == nil || *tgt.PartitionFlags != 0 || tgt.ReadOnly == nil || !*tgt.ReadOnly {
+		t.Errorf("unexpected flags %v %v", tgt.PartitionFlags, tgt.ReadOnly)
+	}
+	if err := tr.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	r, err := tr.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr2, err := ParseTransfer(strings.NewReader(string(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tr, tr2) {
+		t.Errorf("definition changed in round trip:\n%s", b)
+	}
+}
+
+func TestParseTransferErrors(t *testing.T) {
+	for i, s := range []string{
+		"[Transfer]\nVerify=maybe\n",
+		"[Target]\nInstancesMax=1\n",
+		"[Target]\nMode=0999\n",
+		"[Target]\nTriesLeft=-1\n",
+	} {
+		if _, err := ParseTransfer(strings.NewReader(s)); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	for i, tt := range []struct {
+		pattern string
+		valid   bool
+		expand  string
+	}{
+		{"foo_@v.raw", true, "foo_1.2.raw"},
+		{"foo_@v+@l-@d.efi", true, ""},
+		{"mail@@example_@v", true, "mail@example_1.2"},
+		{"foo.raw", false, ""},
+		{"foo_@v_@v", false, ""},
+		{"foo_@x_@v", false, ""},
+		{"foo_@v@", false, ""},
+	} {
+		err := ValidatePattern(tt.pattern)
+		if (err == nil) != tt.valid {
+			t.Errorf("case %d: unexpected validation result %v", i, err)
+		}
+		if !tt.valid {
+			continue
+		}
+		s, err := ExpandPattern(tt.pattern, "1.2")
+		if tt.expand == "" {
+			if err == nil {
+				t.Errorf("case %d: expected expansion error", i)
+			}
+		} else if err != nil || s != tt.expand {
+			t.Errorf("case %d: expanded to %q, %v", i, s, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	base := func() Transfer {
+		return Transfer{
+			Source: SourceSection{Type: ResourceURLTar, Path: "https://example.com/", MatchPattern: []string{"x_@v.tar.xz"}},
+			Target: TargetSection{Type: ResourceDirectory, Path: "/var/lib/machines", MatchPattern: []string{"x_@v"}},
+		}
+	}
+	two := uint(2)
+	for i, tt := range []struct {
+		fn func(*Transfer)
+		ok bool
+	}{
+		{func(*Transfer) {}, true},
+		{func(t *Transfer) { t.Target.PathRelativeTo = "esp" }, true},
+		{func(t *Transfer) { t.Source.Path = "/srv" }, false},
+		{func(t *Transfer) { t.Source.Type = "" }, false},
+		{func(t *Transfer) { t.Source.Type = "ftp" }, false},
+		{func(t *Transfer) { t.Target.Type = ResourceRegularFile }, false},
+		{func(t *Transfer) { t.Target.Type = ResourcePartition }, false},
+		{func(t *Transfer) { t.Target.Path = "" }, false},
+		{func(t *Transfer) { t.Target.PathRelativeTo = "home" }, false},
+		{func(t *Transfer) { t.Target.MatchPattern = nil }, false},
+		{func(t *Transfer) { t.Source.MatchPattern = []string{"x.tar.xz"} }, false},
+		{func(t *Transfer) { t.Target.TriesLeft = &two }, false},
+		{func(t *Transfer) { t.Target.TriesLeft, t.Target.MatchPattern = &two, []string{"x_@v+@l"} }, true},
+	} {
+		tr := base()
+		tt.fn(&tr)
+		err := tr.Validate()
+		if (err == nil) != tt.ok {
+			t.Errorf("case %d: unexpected result %v", i, err)
+		}
+	}
+}
+
+func TestWriteTransfer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sysupdate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	mode := uint32(0644)
+	tr := &Transfer{
+		Source: SourceSection{Type: ResourceURLFile, Path: "https://example.com/", MatchPattern: []string{"x_@v.efi"}},
+		Target: TargetSection{Type: ResourceRegularFile, Path: "/EFI/Linux", PathRelativeTo: "boot", MatchPattern: []string{"x_@v.efi"}, Mode: &mode},
+	}
+	path := filepath.Join(dir, "50-uki.transfer")
+	if err := WriteTransfer(path, tr); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadFile(path)
+	if !strings.Contains(string(b), "\n[Target]\nType=regular-file\n") || !strings.Contains(string(b), "Mode=0644\n") {
+		t.Errorf("unexpected file %q", b)
+	}
+	got, err := ReadTransfer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, tr) {
+		t.Errorf("got %+v", got)
+	}
+}
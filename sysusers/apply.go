@@ -0,0 +1,559 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysusers
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The system ID range used when no range lines are given and login.defs
+// does not say otherwise, as compiled into systemd by default.
+const (
+	defaultSystemUIDMax = 999
+	defaultSystemGIDMax = 999
+)
+
+const passwordLocked = "!*"
+
+// User is an entry of /etc/passwd.
+type User struct {
+	Name  string
+	UID   uint32
+	GID   uint32
+	GECOS string
+	Home  string
+	Shell string
+}
+
+// Group is an entry of /etc/group.
+type Group struct {
+	Name    string
+	GID     uint32
+	Members []string
+}
+
+// ChangeKind is the kind of a Change.
+type ChangeKind int
+
+const (
+	ChangeCreateGroup ChangeKind = iota
+	ChangeCreateUser
+	ChangeAddMember
+)
+
+// Change is a modification of the user database made by Database.Add.
+type Change struct {
+	Kind  ChangeKind
+	User  *User  // the created user or the user added to Group
+	Group *Group // the created group or the group User was added to
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeCreateGroup:
+		return fmt.Sprintf("Creating group '%s' with GID %d.", c.Group.Name, c.Group.GID)
+	case ChangeCreateUser:
+		return fmt.Sprintf("Creating user '%s' (%s) with UID %d and GID %d.", c.User.Name, c.User.GECOS, c.User.UID, c.User.GID)
+	}
+	return fmt.Sprintf("Adding user '%s' to group '%s'.", c.User.Name, c.Group.Name)
+}
+
+// dbFile is one of the files of the user database. Lines are kept as they
+// are, so that entries which are not modified are written back unchanged.
+type dbFile struct {
+	path    string
+	mode    os.FileMode
+	lines   []string
+	changed bool
+}
+
+func readDBFile(path string, mode os.FileMode) (*dbFile, error) {
+	f := &dbFile{path: path, mode: mode}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if fi, err := os.Stat(path); err == nil {
+		f.mode = fi.Mode().Perm()
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(b)))
+	for scanner.Scan() {
+		f.lines = append(f.lines, scanner.Text())
+	}
+	return f, nil
+}
+
+// find returns the index of the line for name, or -1.
+func (f *dbFile) find(name string) int {
+	for i, l := range f.lines {
+		if strings.HasPrefix(l, name+":") {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *dbFile) add(line string) {
+	f.lines = append(f.lines, line)
+	f.changed = true
+}
+
+func (f *dbFile) write() error {
+	if !f.changed {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), "."+filepath.Base(f.path))
+	if err != nil {
+		return err
+	}
+	err = func() error {
+		defer tmp.Close()
+		w := bufio.NewWriter(tmp)
+		for _, l := range f.lines {
+			w.WriteString(l)
+			w.WriteByte('\n')
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if err := tmp.Chmod(f.mode); err != nil {
+			return err
+		}
+		return tmp.Sync()
+	}()
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	f.changed = false
+	return nil
+}
+
+// Database is the user and group database below a root directory, i.e.
+// its /etc/passwd, /etc/group, /etc/shadow and /etc/gshadow.
+type Database struct {
+	root                           string
+	passwd, group, shadow, gshadow *dbFile
+
+	users  map[string]*User
+	groups map[string]*Group
+	uids   map[uint32]bool
+	gids   map[uint32]bool
+
+	uidMax, gidMax uint32
+	ranges         [][2]uint32
+}
+
+// Open reads the user database below root, e.g. "/" for the running system
+// or the root of an image. Missing files are treated as empty.
+func Open(root string) (*Database, error) {
+	db := &Database{
+		root:   root,
+		users:  map[string]*User{},
+		groups: map[string]*Group{},
+		uids:   map[uint32]bool{},
+		gids:   map[uint32]bool{},
+		uidMax: defaultSystemUIDMax,
+		gidMax: defaultSystemGIDMax,
+	}
+
+	var err error
+	etc := filepath.Join(root, "etc")
+	if db.passwd, err = readDBFile(filepath.Join(etc, "passwd"), 0644); err != nil {
+		return nil, err
+	}
+	if db.group, err = readDBFile(filepath.Join(etc, "group"), 0644); err != nil {
+		return nil, err
+	}
+	if db.shadow, err = readDBFile(filepath.Join(etc, "shadow"), 0); err != nil {
+		return nil, err
+	}
+	if db.gshadow, err = readDBFile(filepath.Join(etc, "gshadow"), 0); err != nil {
+		return nil, err
+	}
+
+	for _, l := range db.passwd.lines {
+		f := strings.Split(l, ":")
+		if len(f) < 7 {
+			continue
+		}
+		uid, err1 := strconv.ParseUint(f[2], 10, 32)
+		gid, err2 := strconv.ParseUint(f[3], 10, 32)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		db.users[f[0]] = &User{Name: f[0], UID: uint32(uid), GID: uint32(gid), GECOS: f[4], Home: f[5], Shell: f[6]}
+		db.uids[uint32(uid)] = true
+	}
+	for _, l := range db.group.lines {
+		f := strings.Split(l, ":")
+		if len(f) < 4 {
+			continue
+		}
+		gid, err := strconv.ParseUint(f[2], 10, 32)
+		if err != nil {
+			continue
+		}
+		g := &Group{Name: f[0], GID: uint32(gid)}
+		if f[3] != "" {
+			g.Members = strings.Split(f[3], ",")
+		}
+		db.groups[f[0]] = g
+		db.gids[uint32(gid)] = true
+	}
+
+	if err := db.readLoginDefs(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// readLoginDefs honors SYS_UID_MAX and SYS_GID_MAX from login.defs.
+func (db *Database) readLoginDefs() error {
+	b, err := ioutil.ReadFile(filepath.Join(db.root, "etc", "login.defs"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, l := range strings.Split(string(b), "\n") {
+		f := strings.Fields(l)
+		if len(f) != 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(f[1], 10, 32)
+		if err != nil {
+			continue
+		}
+		switch f[0] {
+		case "SYS_UID_MAX":
+			db.uidMax = uint32(v)
+		case "SYS_GID_MAX":
+			db.gidMax = uint32(v)
+		}
+	}
+	return nil
+}
+
+// User returns the user called name, or nil.
+func (db *Database) User(name string) *User {
+	return db.users[name]
+}
+
+// Group returns the group called name, or nil.
+func (db *Database) Group(name string) *Group {
+	return db.groups[name]
+}
+
+// Add creates the users and groups declared by entries which do not exist
+// yet, and returns the changes made. Existing users and groups are never
+// modified, other than adding members to groups, so applying the same
+// entries again makes no changes. The database is only changed in memory;
+// call Write to save it, or inspect the changes for a dry run.
+//
+// As in systemd-sysusers, groups are created before users and memberships
+// last, regardless of the order of entries.
+func (db *Database) Add(entries []*Entry) ([]Change, error) {
+	changes := []Change{}
+	for _, e := range entries {
+		if e.Type == TypeRange {
+			from, to, err := parseRange(e.ID)
+			if err != nil {
+				return nil, err
+			}
+			db.ranges = append(db.ranges, [2]uint32{from, to})
+		}
+	}
+
+	for _, e := range entries {
+		if e.Type != TypeGroup {
+			continue
+		}
+		c, err := db.addGroup(e)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c...)
+	}
+	for _, e := range entries {
+		if e.Type != TypeUser {
+			continue
+		}
+		c, err := db.addUser(e)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c...)
+	}
+	for _, e := range entries {
+		if e.Type != TypeMember {
+			continue
+		}
+		c, err := db.addMember(e.Name, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c...)
+	}
+	return changes, nil
+}
+
+func (db *Database) addGroup(e *Entry) ([]Change, error) {
+	if db.groups[e.Name] != nil {
+		return nil, nil
+	}
+
+	var gid uint32
+	found := false
+	switch {
+	case strings.HasPrefix(e.ID, "/"):
+		_, id, err := fileOwner(filepath.Join(db.root, e.ID))
+		if err != nil {
+			return nil, err
+		}
+		gid, found = id, !db.gids[id]
+	case e.ID != "":
+		id, err := parseID(e.ID)
+		if err != nil {
+			return nil, err
+		}
+		gid, found = id, !db.gids[id]
+	}
+	if u := db.users[e.Name]; !found && u != nil && !db.gids[u.UID] {
+		gid, found = u.UID, true
+	}
+	if !found {
+		var err error
+		if gid, err = db.allocate(db.gidMax, false, true); err != nil {
+			return nil, err
+		}
+	}
+	return []Change{db.createGroup(e.Name, gid)}, nil
+}
+
+func (db *Database) addUser(e *Entry) ([]Change, error) {
+	if db.users[e.Name] != nil {
+		return nil, nil
+	}
+
+	var changes []Change
+	uidField, groupField := e.ID, ""
+	if i := strings.IndexByte(e.ID, ':'); i >= 0 {
+		uidField, groupField = e.ID[:i], e.ID[i+1:]
+	}
+
+	// The primary group is either given or named after the user.
+	var group *Group
+	if groupField != "" {
+		if gid, err := parseID(groupField); err == nil {
+			for _, g := range db.groups {
+				if g.GID == gid {
+					group = g
+				}
+			}
+		} else {
+			group = db.groups[groupField]
+		}
+		if group == nil {
+			return nil, fmt.Errorf("primary group %s of user %s does not exist", groupField, e.Name)
+		}
+	} else {
+		group = db.groups[e.Name]
+	}
+
+	var uid, fileGID uint32
+	found, hasFileGID := false, false
+	switch {
+	case strings.HasPrefix(uidField, "/"):
+		id, gid, err := fileOwner(filepath.Join(db.root, uidField))
+		if err != nil {
+			return nil, err
+		}
+		uid, found = id, !db.uids[id]
+		fileGID, hasFileGID = gid, true
+	case uidField != "":
+		id, err := parseID(uidField)
+		if err != nil {
+			return nil, err
+		}
+		uid, found = id, !db.uids[id]
+	}
+	if !found && group != nil && !db.uids[group.GID] {
+		uid, found = group.GID, true
+	}
+	if !found {
+		var err error
+		if uid, err = db.allocate(db.uidMax, true, group == nil); err != nil {
+			return nil, err
+		}
+	}
+
+	if group == nil {
+		gid := uid
+		if hasFileGID {
+			gid = fileGID
+		}
+		if db.gids[gid] {
+			var err error
+			if gid, err = db.allocate(db.gidMax, false, true); err != nil {
+				return nil, err
+			}
+		}
+		c := db.createGroup(e.Name, gid)
+		group = c.Group
+		changes = append(changes, c)
+	}
+
+	u := &User{Name: e.Name, UID: uid, GID: group.GID, GECOS: e.GECOS, Home: e.Home, Shell: e.Shell}
+	if u.Home == "" {
+		u.Home = "/"
+	}
+	if u.Shell == "" {
+		u.Shell = "/usr/sbin/nologin"
+		if uid == 0 {
+			u.Shell = "/bin/sh"
+		}
+	}
+	db.users[u.Name] = u
+	db.uids[uid] = true
+	db.passwd.add(fmt.Sprintf("%s:x:%d:%d:%s:%s:%s", u.Name, u.UID, u.GID, u.GECOS, u.Home, u.Shell))
+	if db.shadow.find(u.Name) < 0 {
+		expire := ""
+		if e.Locked {
+			expire = "1"
+		}
+		db.shadow.add(fmt.Sprintf("%s:%s:%d:::::%s:", u.Name, passwordLocked, lastChange(), expire))
+	}
+
+	return append(changes, Change{Kind: ChangeCreateUser, User: u}), nil
+}
+
+func (db *Database) addMember(user, group string) ([]Change, error) {
+	// Like systemd-sysusers, create missing users and groups implicitly.
+	changes, err := db.addGroup(&Entry{Type: TypeGroup, Name: group})
+	if err != nil {
+		return nil, err
+	}
+	c, err := db.addUser(&Entry{Type: TypeUser, Name: user})
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, c...)
+
+	g, u := db.groups[group], db.users[user]
+	for _, m := range g.Members {
+		if m == user {
+			return changes, nil
+		}
+	}
+	g.Members = append(g.Members, user)
+
+	members := strings.Join(g.Members, ",")
+	if i := db.group.find(group); i >= 0 {
+		f := strings.Split(db.group.lines[i], ":")
+		f[3] = members
+		db.group.lines[i] = strings.Join(f, ":")
+		db.group.changed = true
+	}
+	if i := db.gshadow.find(group); i >= 0 {
+		f := strings.Split(db.gshadow.lines[i], ":")
+		if len(f) >= 4 {
+			f[3] = members
+			db.gshadow.lines[i] = strings.Join(f, ":")
+			db.gshadow.changed = true
+		}
+	}
+	return append(changes, Change{Kind: ChangeAddMember, User: u, Group: g}), nil
+}
+
+func (db *Database) createGroup(name string, gid uint32) Change {
+	g := &Group{Name: name, GID: gid}
+	db.groups[name] = g
+	db.gids[gid] = true
+	db.group.add(fmt.Sprintf("%s:x:%d:", name, gid))
+	if db.gshadow.find(name) < 0 {
+		db.gshadow.add(fmt.Sprintf("%s:%s::", name, passwordLocked))
+	}
+	return Change{Kind: ChangeCreateGroup, Group: g}
+}
+
+// allocate returns the highest free ID, from the range lines if any, or
+// below max. The ID must be free as a UID if uid is set and as a GID if gid
+// is set.
+func (db *Database) allocate(max uint32, uid, gid bool) (uint32, error) {
+	ranges := db.ranges
+	if len(ranges) == 0 {
+		ranges = [][2]uint32{{1, max}}
+	}
+	for i := len(ranges) - 1; i >= 0; i-- {
+		for id := ranges[i][1]; id >= ranges[i][0] && id > 0; id-- {
+			if (!uid || !db.uids[id]) && (!gid || !db.gids[id]) {
+				return id, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("no free ID left")
+}
+
+// lastChange returns the shadow password change date for new users, in days
+// since the epoch, honoring $SOURCE_DATE_EPOCH for reproducible builds.
+func lastChange() int64 {
+	now := time.Now().Unix()
+	if e := os.Getenv("SOURCE_DATE_EPOCH"); e != "" {
+		if v, err := strconv.ParseInt(e, 10, 64); err == nil {
+			now = v
+		}
+	}
+	return now / (24 * 60 * 60)
+}
+
+// Write saves the modified files of the database atomically.
+func (db *Database) Write() error {
+	for _, f := range []*dbFile{db.group, db.gshadow, db.passwd, db.shadow} {
+		if err := f.write(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply creates the users and groups declared by entries below root and
+// returns the changes made. See Database.Add.
+func Apply(root string, entries []*Entry) ([]Change, error) {
+	db, err := Open(root)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := db.Add(entries)
+	if err != nil {
+		return nil, err
+	}
+	return changes, db.Write()
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysusers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-systemd-sysusers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	etc := filepath.Join(root, "etc")
+	if err := os.MkdirAll(etc, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"passwd":  "root:x:0:0:root:/root:/bin/bash\n+::::::\n",
+		"group":   "root:x:0:\nwheel:x:10:root\nssh:x:999:\n",
+		"gshadow": "root:::\nwheel:::root\nssh:!*::\n",
+		"shadow":  "",
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(etc, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Setenv("SOURCE_DATE_EPOCH", "86400")
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+	entries, err := Parse(strings.NewReader(`
+u httpd 404 "HTTP User" /var/www
+u sshd 900:ssh
+u tty-user 5:tty-group
+u! locked -
+g input -
+m httpd wheel
+m httpd input
+m root wheel
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Apply(root, entries); err == nil {
+		t.Fatal("expected error for a missing primary group")
+	}
+	entries = append(entries[:2], entries[3:]...)
+
+	changes, err := Apply(root, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var output []string
+	for _, c := range changes {
+		output = append(output, c.String())
+	}
+	expected := []string{
+		"Creating group 'input' with GID 998.",
+		"Creating group 'httpd' with GID 404.",
+		"Creating user 'httpd' (HTTP User) with UID 404 and GID 404.",
+		"Creating user 'sshd' () with UID 900 and GID 999.",
+		"Creating group 'locked' with GID 997.",
+		"Creating user 'locked' () with UID 997 and GID 997.",
+		"Adding user 'httpd' to group 'wheel'.",
+		"Adding user 'httpd' to group 'input'.",
+	}
+	if !reflect.DeepEqual(expected, output) {
+		t.Errorf("expected changes %q, got %q", expected, output)
+	}
+
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(etc, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	for name, contents := range map[string]string{
+		"passwd": "root:x:0:0:root:/root:/bin/bash\n+::::::\n" +
+			"httpd:x:404:404:HTTP User:/var/www:/usr/sbin/nologin\n" +
+			"sshd:x:900:999::/:/usr/sbin/nologin\n" +
+			"locked:x:997:997::/:/usr/sbin/nologin\n",
+		"group":   "root:x:0:\nwheel:x:10:root,httpd\nssh:x:999:\ninput:x:998:httpd\nhttpd:x:404:\nlocked:x:997:\n",
+		"gshadow": "root:::\nwheel:::root,httpd\nssh:!*::\ninput:!*::httpd\nhttpd:!*::\nlocked:!*::\n",
+		"shadow":  "httpd:!*:1::::::\nsshd:!*:1::::::\nlocked:!*:1:::::1:\n",
+	} {
+		if got := read(name); got != contents {
+			t.Errorf("expected %s:\n%s\ngot:\n%s", name, contents, got)
+		}
+	}
+	if fi, err := os.Stat(filepath.Join(etc, "shadow")); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("expected the mode of shadow to be kept, got %v", fi.Mode())
+	}
+
+	// Applying the same entries again changes nothing.
+	changes, err = Apply(root, entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("expected no changes, got %v", changes)
+	}
+}
+
+func TestAddRange(t *testing.T) {
+	db, err := Open("/nonexistent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Parse(strings.NewReader("r - 500-501\nu a -\nu b -\nu c -\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Add(entries[:3]); err != nil {
+		t.Fatal(err)
+	}
+	if a, b := db.User("a"), db.User("b"); a.UID != 501 || b.UID != 500 || db.Group("b").GID != 500 {
+		t.Errorf("expected IDs allocated from the top of the range, got %+v and %+v", a, b)
+	}
+	if _, err := db.Add(entries); err == nil {
+		t.Error("expected error once the range is exhausted")
+	}
+}
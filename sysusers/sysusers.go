@@ -0,0 +1,256 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sysusers parses sysusers.d configuration and creates the system
+// users and groups it declares, like systemd-sysusers does. See
+// https://www.freedesktop.org/software/systemd/man/sysusers.d.html
+package sysusers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// Type is the type of a sysusers.d line.
+type Type byte
+
+const (
+	TypeUser   Type = 'u' // create a user and, unless given, its group
+	TypeGroup  Type = 'g' // create a group
+	TypeMember Type = 'm' // add a user to a group
+	TypeRange  Type = 'r' // restrict the range automatic IDs are allocated from
+)
+
+// Entry is a line of a sysusers.d file. Fields set to "-" in the file are
+// empty.
+type Entry struct {
+	Type Type
+	// Locked is set for "u!" lines, creating a user whose account is locked.
+	Locked bool
+	// Name is the user or group name, or the user to add for TypeMember.
+	Name string
+	// ID is empty for automatic allocation, a numeric ID or the path of a
+	// file whose owner to use. For TypeUser it may also be "UID:GID" or
+	// "UID:group", setting the primary group. For TypeMember it is the group
+	// name, and for TypeRange an ID range like "500-900".
+	ID    string
+	GECOS string
+	Home  string
+	Shell string
+}
+
+func (e *Entry) String() string {
+	t := string(e.Type)
+	if e.Locked {
+		t += "!"
+	}
+	fields := []string{t, orDash(e.Name), orDash(e.ID), orDash(e.GECOS), orDash(e.Home), orDash(e.Shell)}
+	for len(fields) > 2 && fields[len(fields)-1] == "-" {
+		fields = fields[:len(fields)-1]
+	}
+	for i, f := range fields {
+		fields[i] = unit.QuoteWord(f)
+	}
+	return strings.Join(fields, " ")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// Parse reads sysusers.d configuration from r. If c is not nil, specifiers
+// in the fields are expanded with it, otherwise they are left as they are.
+func Parse(r io.Reader, c *unit.SpecifierContext) ([]*Entry, error) {
+	entries := []*Entry{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		e, err := ParseLine(line, c)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParseLine parses a single sysusers.d line. See Parse.
+func ParseLine(line string, c *unit.SpecifierContext) (*Entry, error) {
+	fields, err := unit.SplitWords(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("missing name in %q", line)
+	}
+	if len(fields) > 6 {
+		return nil, fmt.Errorf("trailing garbage in %q", line)
+	}
+
+	e := &Entry{}
+	switch fields[0] {
+	case "u", "g", "m", "r":
+		e.Type = Type(fields[0][0])
+	case "u!":
+		e.Type, e.Locked = TypeUser, true
+	default:
+		return nil, fmt.Errorf("unknown type %q", fields[0])
+	}
+
+	values := make([]string, 5)
+	for i, f := range fields[1:] {
+		if f == "-" {
+			continue
+		}
+		if c != nil {
+			if f, err = unit.ExpandSpecifiers(f, c); err != nil {
+				return nil, err
+			}
+		}
+		values[i] = f
+	}
+	e.Name, e.ID, e.GECOS, e.Home, e.Shell = values[0], values[1], values[2], values[3], values[4]
+
+	if err := e.validate(c == nil); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// validate checks the fields of e. With unexpanded set, fields containing a
+// specifier are not checked, as they would not pass validation before being
+// expanded.
+func (e *Entry) validate(unexpanded bool) error {
+	skip := func(s string) bool {
+		return unexpanded && strings.Contains(s, "%")
+	}
+
+	if e.Type == TypeRange {
+		if e.Name != "" {
+			return fmt.Errorf("name must be - for range lines, got %q", e.Name)
+		}
+		if !skip(e.ID) {
+			if _, _, err := parseRange(e.ID); err != nil {
+				return err
+			}
+		}
+		return e.noExtraFields()
+	}
+
+	if !skip(e.Name) && !validName(e.Name) {
+		return fmt.Errorf("invalid user or group name %q", e.Name)
+	}
+
+	switch e.Type {
+	case TypeMember:
+		if !skip(e.ID) && !validName(e.ID) {
+			return fmt.Errorf("invalid group name %q", e.ID)
+		}
+		return e.noExtraFields()
+	case TypeGroup:
+		if e.ID != "" && !strings.HasPrefix(e.ID, "/") && !skip(e.ID) {
+			if _, err := parseID(e.ID); err != nil {
+				return err
+			}
+		}
+		if e.Home != "" || e.Shell != "" {
+			return fmt.Errorf("home and shell are not allowed for group %s", e.Name)
+		}
+	case TypeUser:
+		if e.ID != "" && !strings.HasPrefix(e.ID, "/") && !skip(e.ID) {
+			uid, group := e.ID, ""
+			if i := strings.IndexByte(e.ID, ':'); i >= 0 {
+				uid, group = e.ID[:i], e.ID[i+1:]
+				if _, err := parseID(group); err != nil && !validName(group) {
+					return fmt.Errorf("invalid group %q", group)
+				}
+			}
+			if _, err := parseID(uid); err != nil {
+				return err
+			}
+		}
+		if e.Home != "" && !strings.HasPrefix(e.Home, "/") && !skip(e.Home) {
+			return fmt.Errorf("home directory %q is not absolute", e.Home)
+		}
+		if e.Shell != "" && !strings.HasPrefix(e.Shell, "/") && !skip(e.Shell) {
+			return fmt.Errorf("shell %q is not absolute", e.Shell)
+		}
+	}
+	if strings.ContainsAny(e.GECOS, ":\n") && !skip(e.GECOS) {
+		return fmt.Errorf("invalid GECOS field %q", e.GECOS)
+	}
+	return nil
+}
+
+func (e *Entry) noExtraFields() error {
+	if e.GECOS != "" || e.Home != "" || e.Shell != "" {
+		return fmt.Errorf("trailing fields not allowed for %c lines", e.Type)
+	}
+	return nil
+}
+
+// validName reports whether s is a valid user or group name, following the
+// strict rules systemd-sysusers applies.
+func validName(s string) bool {
+	if s == "" || len(s) > 31 {
+		return false
+	}
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case i > 0 && (c >= '0' && c <= '9' || c == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func parseID(s string) (uint32, error) {
+	id, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || id == 65535 || id == 4294967295 {
+		return 0, fmt.Errorf("invalid ID %q", s)
+	}
+	return uint32(id), nil
+}
+
+func parseRange(s string) (uint32, uint32, error) {
+	from, to := s, s
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		from, to = s[:i], s[i+1:]
+	}
+	a, err := parseID(from)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid range %q", s)
+	}
+	b, err := parseID(to)
+	if err != nil || b < a {
+		return 0, 0, fmt.Errorf("invalid range %q", s)
+	}
+	return a, b, nil
+}
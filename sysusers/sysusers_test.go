@@ -0,0 +1,129 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysusers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+func TestParse(t *testing.T) {
+	in := `# comment
+u     httpd   404           "HTTP User"    /var/www
+u!    locked  -             -              -         /bin/bash
+u     sshd    42:ssh
+u     mail    8:12          "Mail"
+g     input   -             -
+g     owned   /var/lib/foo
+m     sshd    input
+r     -       500-900
+u     host-%H -
+`
+	c := &unit.SpecifierContext{Hostname: "example"}
+	entries, err := Parse(strings.NewReader(in), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*Entry{
+		{Type: TypeUser, Name: "httpd", ID: "404", GECOS: "HTTP User", Home: "/var/www"},
+		{Type: TypeUser, Locked: true, Name: "locked", Shell: "/bin/bash"},
+		{Type: TypeUser, Name: "sshd", ID: "42:ssh"},
+		{Type: TypeUser, Name: "mail", ID: "8:12", GECOS: "Mail"},
+		{Type: TypeGroup, Name: "input"},
+		{Type: TypeGroup, Name: "owned", ID: "/var/lib/foo"},
+		{Type: TypeMember, Name: "sshd", ID: "input"},
+		{Type: TypeRange, ID: "500-900"},
+		{Type: TypeUser, Name: "host-example"},
+	}
+	if !reflect.DeepEqual(expected, entries) {
+		t.Errorf("unexpected entries")
+		for i := range entries {
+			t.Logf("%d: %+v", i, entries[i])
+		}
+	}
+
+	if s := entries[0].String(); s != `u httpd 404 "HTTP User" /var/www` {
+		t.Errorf("unexpected string %q", s)
+	}
+	if s := entries[1].String(); s != "u! locked - - - /bin/bash" {
+		t.Errorf("unexpected string %q", s)
+	}
+	if s := entries[7].String(); s != "r - 500-900" {
+		t.Errorf("unexpected string %q", s)
+	}
+
+	// Values are quoted with C-style escapes, which ParseLine decodes.
+	e := &Entry{Type: 'u', Name: "quoted", ID: "-", GECOS: `Say "hi" D\ ä`}
+	if s := e.String(); s != `u quoted - "Say \"hi\" D\\ ä"` {
+		t.Errorf("unexpected string %q", s)
+	}
+	if p, err := ParseLine(e.String(), nil); err != nil || p.GECOS != e.GECOS {
+		t.Errorf("expected GECOS %q to survive, got %+v, %v", e.GECOS, p, err)
+	}
+
+	// Without a context, specifiers are kept.
+	e, err = ParseLine("u host-%H -", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Name != "host-%H" {
+		t.Errorf("expected specifier to be kept, got %q", e.Name)
+	}
+
+	// Only the fields containing specifiers skip validation.
+	for _, line := range []string{
+		`u host-%H - "a:b"`,
+		"u 1foo - %H",
+		"u foo - - relative %s",
+		"g foo - - /home/%u",
+	} {
+		if _, err := ParseLine(line, nil); err == nil {
+			t.Errorf("expected error parsing %q", line)
+		}
+	}
+	if _, err := ParseLine("u foo %U:%g %H %h %s", nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestParseFail(t *testing.T) {
+	tests := []string{
+		"x foo",
+		"u",
+		"u foo - - - - extra",
+		"u 1foo",
+		"u foo bar",
+		"u foo 65535",
+		"u foo - - relative/home",
+		"u foo - - / sh",
+		`u foo - "a:b"`,
+		"g foo - - /home",
+		"m foo",
+		"m foo - extra",
+		"r foo 1-2",
+		"r - 900-500",
+		`u foo "unterminated`,
+	}
+
+	for i, tt := range tests {
+		if e, err := ParseLine(tt, nil); err == nil {
+			t.Errorf("case %d: expected error for %q, got %+v", i, tt, e)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timedate1 provides integration with the systemd timedated API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.timedate1.html
+package timedate1
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.timedate1"
+	dbusInterface = "org.freedesktop.timedate1"
+	dbusPath      = "/org/freedesktop/timedate1"
+)
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+// Note: systemd-timedated will be activated via D-Bus, we don't need to check service status.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+func (c *Conn) getProperty(property string) (dbus.Variant, error) {
+	return c.object.GetProperty(dbusInterface + "." + property)
+}
+
+func (c *Conn) getBoolProperty(property string) (bool, error) {
+	v, err := c.getProperty(property)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("failed to typecast %s property %s to bool", property, v)
+	}
+	return b, nil
+}
+
+func (c *Conn) getTimeProperty(property string) (time.Time, error) {
+	v, err := c.getProperty(property)
+	if err != nil {
+		return time.Time{}, err
+	}
+	usec, ok := v.Value().(uint64)
+	if !ok {
+		return time.Time{}, fmt.Errorf("failed to typecast %s property %s to uint64", property, v)
+	}
+	return time.Unix(0, int64(usec)*int64(time.Microsecond)), nil
+}
+
+// SetTime sets the system clock to the given time. This fails if NTP is enabled.
+func (c *Conn) SetTime(t time.Time, interactive bool) error {
+	usec := t.UnixNano() / int64(time.Microsecond)
+	return c.object.Call(dbusInterface+".SetTime", 0, usec, false, interactive).Err
+}
+
+// AdjustTime moves the system clock by the given offset. This fails if NTP is enabled.
+func (c *Conn) AdjustTime(offset time.Duration, interactive bool) error {
+	usec := int64(offset / time.Microsecond)
+	return c.object.Call(dbusInterface+".SetTime", 0, usec, true, interactive).Err
+}
+
+// SetTimezone sets the system timezone, e.g. "Europe/Berlin". Valid names
+// are returned by ListTimezones.
+func (c *Conn) SetTimezone(timezone string, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetTimezone", 0, timezone, interactive).Err
+}
+
+// ListTimezones returns the names of all timezones known to the system.
+// Note: Requires systemd v239 or higher
+func (c *Conn) ListTimezones() ([]string, error) {
+	var timezones []string
+	if err := c.object.Call(dbusInterface+".ListTimezones", 0).Store(&timezones); err != nil {
+		return nil, err
+	}
+	return timezones, nil
+}
+
+// SetLocalRTC sets whether the hardware clock is kept in local time rather
+// than UTC. If fixSystem is set, the system clock is set from the hardware
+// clock reinterpreted accordingly, otherwise the hardware clock is written
+// from the system clock.
+func (c *Conn) SetLocalRTC(localRTC, fixSystem, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetLocalRTC", 0, localRTC, fixSystem, interactive).Err
+}
+
+// SetNTP enables or disables network time synchronization.
+func (c *Conn) SetNTP(useNTP, interactive bool) error {
+	return c.object.Call(dbusInterface+".SetNTP", 0, useNTP, interactive).Err
+}
+
+// Timezone returns the system timezone.
+func (c *Conn) Timezone() (string, error) {
+	v, err := c.getProperty("Timezone")
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("failed to typecast Timezone property %s to string", v)
+	}
+	return s, nil
+}
+
+// LocalRTC returns whether the hardware clock is kept in local time.
+func (c *Conn) LocalRTC() (bool, error) {
+	return c.getBoolProperty("LocalRTC")
+}
+
+// CanNTP returns whether a network time synchronization service is available.
+func (c *Conn) CanNTP() (bool, error) {
+	return c.getBoolProperty("CanNTP")
+}
+
+// NTP returns whether network time synchronization is enabled.
+func (c *Conn) NTP() (bool, error) {
+	return c.getBoolProperty("NTP")
+}
+
+// NTPSynchronized returns whether the kernel considers the system clock synchronized.
+func (c *Conn) NTPSynchronized() (bool, error) {
+	return c.getBoolProperty("NTPSynchronized")
+}
+
+// Time returns the current time of the system clock.
+func (c *Conn) Time() (time.Time, error) {
+	return c.getTimeProperty("TimeUSec")
+}
+
+// RTCTime returns the current time of the hardware clock.
+func (c *Conn) RTCTime() (time.Time, error) {
+	return c.getTimeProperty("RTCTimeUSec")
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timedate1
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNew ensures that New() works without errors.
+func TestNew(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestTime(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	now, err := conn.Time()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(now); d > time.Minute || d < -time.Minute {
+		t.Fatalf("system time %v differs from local time by %v", now, d)
+	}
+}
+
+func TestTimezones(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	timezone, err := conn.Timezone()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timezones, err := conn.ListTimezones()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timezones) == 0 {
+		t.Fatal("no timezones returned")
+	}
+
+	if timezone == "" {
+		return
+	}
+	for _, tz := range timezones {
+		if tz == timezone {
+			return
+		}
+	}
+	t.Fatalf("current timezone %q not listed", timezone)
+}
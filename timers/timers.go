@@ -0,0 +1,379 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timers schedules commands with systemd timers, as a replacement
+// for cron. Each job is a timer and the service of the same name it
+// activates, created either as transient units, which are gone after a
+// reboot, or as unit files installed in /etc/systemd/system.
+//
+// See https://www.freedesktop.org/software/systemd/man/systemd.timer.html
+package timers
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sd_dbus "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/coreos/go-systemd/v22/unit"
+	"github.com/godbus/dbus/v5"
+)
+
+// DefaultPrefix is prepended to job names to form their unit names unless a
+// Scheduler sets another one.
+const DefaultPrefix = "go-systemd-job-"
+
+// DefaultDir is where installed jobs are written unless a Scheduler sets
+// another directory.
+const DefaultDir = "/etc/systemd/system"
+
+// Options are the settings of a job besides its schedule and command.
+type Options struct {
+	// Description of the timer and service, the job name if empty.
+	Description string
+	// Transient creates the units over dbus instead of installing unit
+	// files, so that the job is gone after a reboot.
+	Transient bool
+	// Persistent makes the timer run the job on boot if it missed a run
+	// while the system was down, see Persistent= in systemd.timer(5).
+	Persistent bool
+	// RandomizedDelay delays each run by a random time up to it.
+	RandomizedDelay time.Duration
+	// Accuracy is how much later than scheduled a run may start, so that
+	// systemd can coalesce wakeups; one minute if zero.
+	Accuracy time.Duration
+	// User runs the command as that user instead of root.
+	User string
+}
+
+// Job is a job managed by a Scheduler.
+type Job struct {
+	Name    string // The job name, without the prefix
+	Timer   string // The timer unit
+	Service string // The service unit running the command
+
+	Calendar  []string // The OnCalendar= schedules of the timer
+	Transient bool     // Whether the units are transient
+	Active    bool     // Whether the timer is started
+	// NextRun and LastRun are the next time the timer elapses and the last
+	// time it did, zero if unknown.
+	NextRun time.Time
+	LastRun time.Time
+}
+
+// Scheduler manages the jobs of a dbus connection to systemd, which are the
+// timers whose names start with Prefix.
+type Scheduler struct {
+	conn *sd_dbus.Conn
+
+	// Prefix is prepended to job names to form their unit names.
+	Prefix string
+	// Dir is where unit files of jobs that are not transient are written.
+	// Since systemd only looks for them in its unit search path, only
+	// changing it for a user manager, or for tests, makes sense.
+	Dir string
+}
+
+// New returns a scheduler for the jobs of conn, with DefaultPrefix and
+// DefaultDir.
+func New(conn *sd_dbus.Conn) *Scheduler {
+	return &Scheduler{conn: conn, Prefix: DefaultPrefix, Dir: DefaultDir}
+}
+
+// units returns the timer and service units of the job name.
+func (s *Scheduler) units(name string) (string, string, error) {
+	if name == "" || strings.ContainsAny(name, "@/") {
+		return "", "", fmt.Errorf("invalid job name %q", name)
+	}
+	timer := s.Prefix + name + ".timer"
+	if !unit.UnitNameIsValid(timer) {
+		return "", "", fmt.Errorf("invalid job name %q", name)
+	}
+	return timer, s.Prefix + name + ".service", nil
+}
+
+// ScheduleJob creates the job name running execStart, whose first element
+// is the executable, at the times of calendarSpec such as "daily" or
+// "Mon..Fri 08:00", the syntax of OnCalendar= in systemd.timer(5). A job of
+// the same name is replaced, whether it was transient or not. Environment
+// variable references in execStart are substituted like in ExecStart=,
+// specifiers are not.
+func (s *Scheduler) ScheduleJob(ctx context.Context, name, calendarSpec string, execStart []string, opts Options) error {
+	timer, service, err := s.units(name)
+	if err != nil {
+		return err
+	}
+	if _, err := unit.ParseCalendar(calendarSpec); err != nil {
+		return err
+	}
+	if len(execStart) == 0 {
+		return fmt.Errorf("job %s has no command", name)
+	}
+	if opts.Description == "" {
+		opts.Description = name
+	}
+
+	if _, err := s.remove(ctx, timer, service); err != nil {
+		return err
+	}
+	if opts.Transient {
+		return waitJob(ctx, func(ch chan<- string) (int, error) {
+			return s.conn.StartTransientTimerContext(ctx, timer, "replace",
+				timerProperties(calendarSpec, opts), serviceProperties(execStart, opts), ch)
+		})
+	}
+
+	timerFile, serviceFile := unitFiles(calendarSpec, execStart, opts)
+	if err := writeUnit(filepath.Join(s.Dir, service), serviceFile); err != nil {
+		return err
+	}
+	if err := writeUnit(filepath.Join(s.Dir, timer), timerFile); err != nil {
+		return err
+	}
+	if err := s.conn.ReloadContext(ctx); err != nil {
+		return err
+	}
+	if _, _, err := s.conn.EnableUnitFilesContext(ctx, []string{timer}, false, true); err != nil {
+		return err
+	}
+	return waitJob(ctx, func(ch chan<- string) (int, error) {
+		return s.conn.StartUnitContext(ctx, timer, "replace", ch)
+	})
+}
+
+// RemoveJob stops the job name and removes its units. It returns an error if
+// there is no such job.
+func (s *Scheduler) RemoveJob(ctx context.Context, name string) error {
+	timer, service, err := s.units(name)
+	if err != nil {
+		return err
+	}
+	found, err := s.remove(ctx, timer, service)
+	if err == nil && !found {
+		err = fmt.Errorf("no job %s", name)
+	}
+	return err
+}
+
+// remove stops the units of a job, disables and removes their unit files
+// and returns whether there were any.
+func (s *Scheduler) remove(ctx context.Context, timer, service string) (bool, error) {
+	found := false
+	loaded, err := s.conn.ListUnitsByNamesContext(ctx, []string{timer, service})
+	if err != nil {
+		return false, err
+	}
+	for _, u := range loaded {
+		if u.LoadState == "not-found" {
+			continue
+		}
+		found = true
+		if u.ActiveState != "inactive" && u.ActiveState != "failed" {
+			name := u.Name
+			if err := waitJob(ctx, func(ch chan<- string) (int, error) {
+				return s.conn.StopUnitContext(ctx, name, "replace", ch)
+			}); err != nil {
+				return true, err
+			}
+		}
+		if u.ActiveState == "failed" {
+			s.conn.ResetFailedUnitContext(ctx, u.Name)
+		}
+	}
+
+	timerPath := filepath.Join(s.Dir, timer)
+	if _, err := os.Stat(timerPath); err == nil {
+		found = true
+		if _, err := s.conn.DisableUnitFilesContext(ctx, []string{timer}, false); err != nil {
+			return true, err
+		}
+	}
+	removed := false
+	for _, path := range []string{timerPath, filepath.Join(s.Dir, service)} {
+		err := os.Remove(path)
+		if err == nil {
+			removed = true
+		} else if !os.IsNotExist(err) {
+			return true, err
+		}
+	}
+	if removed {
+		found = true
+		if err := s.conn.ReloadContext(ctx); err != nil {
+			return true, err
+		}
+	}
+	return found, nil
+}
+
+// ListJobs returns the jobs of the scheduler, whether their units are
+// loaded, or only installed, sorted by name.
+func (s *Scheduler) ListJobs(ctx context.Context) ([]Job, error) {
+	pattern := []string{s.Prefix + "*.timer"}
+	timers := map[string]bool{}
+	loaded, err := s.conn.ListUnitsByPatternsContext(ctx, nil, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range loaded {
+		timers[u.Name] = true
+	}
+	files, err := s.conn.ListUnitFilesByPatternsContext(ctx, nil, pattern)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		timers[filepath.Base(f.Path)] = true
+	}
+
+	var jobs []Job
+	for timer := range timers {
+		name := strings.TrimSuffix(strings.TrimPrefix(timer, s.Prefix), ".timer")
+		job := Job{Name: name, Timer: timer}
+		props, err := s.conn.GetUnitTypePropertiesContext(ctx, timer, "Timer")
+		if err != nil {
+			return nil, err
+		}
+		job.Service, _ = props["Unit"].(string)
+		job.Calendar = calendarSpecs(props["TimersCalendar"])
+		next, _ := props["NextElapseUSecRealtime"].(uint64)
+		job.NextRun = unit.TimestampFromUsec(next)
+		last, _ := props["LastTriggerUSec"].(uint64)
+		job.LastRun = unit.TimestampFromUsec(last)
+
+		props, err = s.conn.GetUnitPropertiesContext(ctx, timer)
+		if err != nil {
+			return nil, err
+		}
+		job.Transient, _ = props["Transient"].(bool)
+		state, _ := props["ActiveState"].(string)
+		job.Active = state == "active"
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs, nil
+}
+
+// calendarSpecs returns the specs of the TimersCalendar property, an array
+// of the timer base, spec and next elapse time.
+func calendarSpecs(v interface{}) []string {
+	timers, _ := v.([][]interface{})
+	var specs []string
+	for _, t := range timers {
+		if len(t) != 3 {
+			continue
+		}
+		if spec, ok := t[1].(string); ok {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// waitJob queues a job with start and waits for its result.
+func waitJob(ctx context.Context, start func(ch chan<- string) (int, error)) error {
+	ch := make(chan string, 1)
+	if _, err := start(ch); err != nil {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case result := <-ch:
+		if result != "done" {
+			return fmt.Errorf("job %s", result)
+		}
+		return nil
+	}
+}
+
+func timerProperties(calendarSpec string, opts Options) []sd_dbus.Property {
+	props := []sd_dbus.Property{
+		sd_dbus.PropDescription(opts.Description),
+		sd_dbus.PropOnCalendar(calendarSpec),
+		sd_dbus.PropPersistent(opts.Persistent),
+	}
+	if opts.RandomizedDelay != 0 {
+		props = append(props, sd_dbus.PropRandomizedDelaySec(opts.RandomizedDelay))
+	}
+	if opts.Accuracy != 0 {
+		props = append(props, sd_dbus.PropAccuracySec(opts.Accuracy))
+	}
+	return props
+}
+
+func serviceProperties(execStart []string, opts Options) []sd_dbus.Property {
+	props := []sd_dbus.Property{
+		sd_dbus.PropDescription(opts.Description),
+		sd_dbus.PropType("oneshot"),
+		sd_dbus.PropExecStart(execStart, true),
+	}
+	if opts.User != "" {
+		props = append(props, sd_dbus.Property{Name: "User", Value: dbus.MakeVariant(opts.User)})
+	}
+	return props
+}
+
+// unitFiles returns the timer and service unit files of an installed job.
+func unitFiles(calendarSpec string, execStart []string, opts Options) ([]*unit.UnitOption, []*unit.UnitOption) {
+	description := unit.EscapeSpecifiers(opts.Description)
+	timer := []*unit.UnitOption{
+		unit.NewUnitOption("Unit", "Description", description),
+		unit.NewUnitOption("Timer", "OnCalendar", calendarSpec),
+		unit.NewUnitOption("Timer", "Persistent", fmt.Sprint(opts.Persistent)),
+	}
+	if opts.RandomizedDelay != 0 {
+		timer = append(timer, unit.NewUnitOption("Timer", "RandomizedDelaySec", unit.FormatTimeSpan(opts.RandomizedDelay, time.Microsecond)))
+	}
+	if opts.Accuracy != 0 {
+		timer = append(timer, unit.NewUnitOption("Timer", "AccuracySec", unit.FormatTimeSpan(opts.Accuracy, time.Microsecond)))
+	}
+	timer = append(timer, unit.NewUnitOption("Install", "WantedBy", "timers.target"))
+
+	argv := make([]string, len(execStart))
+	for i, arg := range execStart {
+		argv[i] = unit.EscapeSpecifiers(arg)
+	}
+	service := []*unit.UnitOption{
+		unit.NewUnitOption("Unit", "Description", description),
+		unit.NewUnitOption("Service", "Type", "oneshot"),
+		unit.NewUnitOption("Service", "ExecStart", unit.NewExecCommand(argv...).String()),
+	}
+	if opts.User != "" {
+		service = append(service, unit.NewUnitOption("Service", "User", opts.User))
+	}
+	return timer, service
+}
+
+// writeUnit atomically replaces the unit file at path.
+func writeUnit(path string, opts []*unit.UnitOption) error {
+	b, err := ioutil.ReadAll(unit.Serialize(opts))
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
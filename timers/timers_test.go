@@ -0,0 +1,149 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timers
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	sd_dbus "github.com/coreos/go-systemd/v22/dbus"
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+func TestUnits(t *testing.T) {
+	s := &Scheduler{Prefix: DefaultPrefix}
+	timer, service, err := s.units("backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timer != "go-systemd-job-backup.timer" || service != "go-systemd-job-backup.service" {
+		t.Errorf("unexpected units %s %s", timer, service)
+	}
+	for i, name := range []string{"", "a/b", "tmpl@x", "sp ace"} {
+		if _, _, err := s.units(name); err == nil {
+			t.Errorf("case %d: expected error for job name %q", i, name)
+		}
+	}
+}
+
+func TestUnitFiles(t *testing.T) {
+	timer, service := unitFiles("*-*-* 03:00", []string{"/usr/bin/backup", "--to", "100%"}, Options{
+		Description:     "Nightly backup",
+		Persistent:      true,
+		RandomizedDelay: 5 * time.Minute,
+		User:            "backup",
+	})
+	b, err := ioutil.ReadAll(unit.Serialize(timer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[Unit]
+Description=Nightly backup
+
+[Timer]
+OnCalendar=*-*-* 03:00
+Persistent=true
+RandomizedDelaySec=5min
+
+[Install]
+WantedBy=timers.target
+`
+	if string(b) != want {
+		t.Errorf("unexpected timer:\n%s", b)
+	}
+
+	b, err = ioutil.ReadAll(unit.Serialize(service))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `[Unit]
+Description=Nightly backup
+
+[Service]
+Type=oneshot
+ExecStart=/usr/bin/backup --to 100%%
+User=backup
+`
+	if string(b) != want {
+		t.Errorf("unexpected service:\n%s", b)
+	}
+}
+
+func TestCalendarSpecs(t *testing.T) {
+	v := [][]interface{}{
+		{"OnCalendar", "*-*-* 03:00:00", uint64(0)},
+		{"OnCalendar", "Mon *-*-* 00:00:00", uint64(0)},
+		{"bogus"},
+	}
+	if specs := calendarSpecs(v); !reflect.DeepEqual(specs, []string{"*-*-* 03:00:00", "Mon *-*-* 00:00:00"}) {
+		t.Errorf("unexpected specs %v", specs)
+	}
+	if specs := calendarSpecs(nil); specs != nil {
+		t.Errorf("unexpected specs %v", specs)
+	}
+}
+
+func TestScheduleJob(t *testing.T) {
+	conn, err := sd_dbus.NewWithContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	s := New(conn)
+	s.Prefix = "go-systemd-test-" + time.Now().Format("150405") + "-"
+	ctx := context.Background()
+
+	if err := s.ScheduleJob(ctx, "sleep", "daily", nil, Options{Transient: true}); err == nil {
+		t.Error("expected error for job without command")
+	}
+	if err := s.ScheduleJob(ctx, "sleep", "yesterday-ish", []string{"/bin/true"}, Options{Transient: true}); err == nil {
+		t.Error("expected error for invalid calendar spec")
+	}
+
+	opts := Options{Transient: os.Geteuid() != 0}
+	if err := s.ScheduleJob(ctx, "true", "*-*-* 03:00", []string{"/bin/true"}, opts); err != nil {
+		t.Fatal(err)
+	}
+	// Scheduling it again replaces the job.
+	if err := s.ScheduleJob(ctx, "true", "Mon *-*-* 03:00", []string{"/bin/true"}, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := s.ListJobs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Name != "true" || !jobs[0].Active || jobs[0].Transient != opts.Transient {
+		t.Fatalf("unexpected jobs %+v", jobs)
+	}
+	if !reflect.DeepEqual(jobs[0].Calendar, []string{"Mon *-*-* 03:00:00"}) || jobs[0].NextRun.Weekday() != time.Monday {
+		t.Errorf("unexpected schedule %v, next run %v", jobs[0].Calendar, jobs[0].NextRun)
+	}
+
+	if err := s.RemoveJob(ctx, "true"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveJob(ctx, "true"); err == nil {
+		t.Error("expected error removing job twice")
+	}
+	if jobs, err := s.ListJobs(ctx); err != nil || len(jobs) != 0 {
+		t.Errorf("unexpected jobs after removal %+v, %v", jobs, err)
+	}
+}
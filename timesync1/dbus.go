@@ -0,0 +1,331 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timesync1 provides integration with the systemd timesyncd API.  See https://www.freedesktop.org/software/systemd/man/org.freedesktop.timesync1.html
+package timesync1
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusDest      = "org.freedesktop.timesync1"
+	dbusInterface = "org.freedesktop.timesync1.Manager"
+	dbusPath      = "/org/freedesktop/timesync1"
+)
+
+// NTPMessage is the last NTP response received from the time server.
+type NTPMessage struct {
+	Leap                 uint32
+	Version              uint32
+	Mode                 uint32
+	Stratum              uint32
+	Precision            int32  // The clock precision as an exponent of two, in seconds
+	RootDelay            uint64 // The round-trip delay to the reference clock in microseconds
+	RootDispersion       uint64 // The dispersion to the reference clock in microseconds
+	Reference            []byte // The reference clock identifier, e.g. "GPS\x00" for stratum 1 servers
+	OriginTimestamp      uint64 // The local time the request was sent, in microseconds
+	ReceiveTimestamp     uint64 // The server time the request was received, in microseconds
+	TransmitTimestamp    uint64 // The server time the response was sent, in microseconds
+	DestinationTimestamp uint64 // The local time the response was received, in microseconds
+	Ignored              bool   // Whether the response was not used for synchronization
+	PacketCount          uint64
+	Jitter               uint64 // The observed jitter in microseconds
+}
+
+// Offset returns the estimated offset of the local clock against the server.
+func (m *NTPMessage) Offset() time.Duration {
+	offset := (int64(m.ReceiveTimestamp-m.OriginTimestamp) + int64(m.TransmitTimestamp-m.DestinationTimestamp)) / 2
+	return time.Duration(offset) * time.Microsecond
+}
+
+// Delay returns the round-trip delay to the server.
+func (m *NTPMessage) Delay() time.Duration {
+	delay := int64(m.DestinationTimestamp-m.OriginTimestamp) - int64(m.TransmitTimestamp-m.ReceiveTimestamp)
+	return time.Duration(delay) * time.Microsecond
+}
+
+// Conn is a connection to systemds dbus endpoint.
+type Conn struct {
+	conn   *dbus.Conn
+	object dbus.BusObject
+}
+
+// New establishes a connection to the system bus and authenticates.
+// Note: systemd-timesyncd will be activated via D-Bus, we don't need to check service status.
+func New() (*Conn, error) {
+	c := new(Conn)
+
+	if err := c.initConnection(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Close closes the dbus connection
+func (c *Conn) Close() {
+	if c == nil {
+		return
+	}
+
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
+
+// Connected returns whether conn is connected
+func (c *Conn) Connected() bool {
+	return c.conn.Connected()
+}
+
+func (c *Conn) initConnection() error {
+	var err error
+	c.conn, err = dbus.SystemBusPrivate()
+	if err != nil {
+		return err
+	}
+
+	// Only use EXTERNAL method, and hardcode the uid (not username)
+	// to avoid a username lookup (which requires a dynamically linked
+	// libc)
+	methods := []dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Getuid()))}
+
+	err = c.conn.Auth(methods)
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	err = c.conn.Hello()
+	if err != nil {
+		c.conn.Close()
+		return err
+	}
+
+	c.object = c.conn.Object(dbusDest, dbus.ObjectPath(dbusPath))
+
+	return nil
+}
+
+func (c *Conn) getProperty(property string) (dbus.Variant, error) {
+	return c.object.GetProperty(dbusInterface + "." + property)
+}
+
+func (c *Conn) getStringsProperty(property string) ([]string, error) {
+	v, err := c.getProperty(property)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.Value().([]string)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast %s property %s to []string", property, v)
+	}
+	return s, nil
+}
+
+func (c *Conn) getDurationProperty(property string) (time.Duration, error) {
+	v, err := c.getProperty(property)
+	if err != nil {
+		return 0, err
+	}
+	usec, ok := v.Value().(uint64)
+	if !ok {
+		return 0, fmt.Errorf("failed to typecast %s property %s to uint64", property, v)
+	}
+	return time.Duration(usec) * time.Microsecond, nil
+}
+
+// ServerName returns the name of the NTP server currently in use.
+func (c *Conn) ServerName() (string, error) {
+	v, err := c.getProperty("ServerName")
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.Value().(string)
+	if !ok {
+		return "", fmt.Errorf("failed to typecast ServerName property %s to string", v)
+	}
+	return s, nil
+}
+
+// ServerAddress returns the address of the NTP server currently in use, or
+// nil if there is none.
+func (c *Conn) ServerAddress() (net.IP, error) {
+	v, err := c.getProperty("ServerAddress")
+	if err != nil {
+		return nil, err
+	}
+	return serverAddressFromVariant(v)
+}
+
+// LinkNTPServers returns the NTP servers configured for network links, e.g.
+// via DHCP.
+func (c *Conn) LinkNTPServers() ([]string, error) {
+	return c.getStringsProperty("LinkNTPServers")
+}
+
+// SystemNTPServers returns the NTP servers configured in timesyncd.conf.
+func (c *Conn) SystemNTPServers() ([]string, error) {
+	return c.getStringsProperty("SystemNTPServers")
+}
+
+// RuntimeNTPServers returns the NTP servers set with SetRuntimeNTPServers.
+// Note: Requires systemd v248 or higher
+func (c *Conn) RuntimeNTPServers() ([]string, error) {
+	return c.getStringsProperty("RuntimeNTPServers")
+}
+
+// FallbackNTPServers returns the NTP servers used if no others are configured.
+func (c *Conn) FallbackNTPServers() ([]string, error) {
+	return c.getStringsProperty("FallbackNTPServers")
+}
+
+// SetRuntimeNTPServers sets the NTP servers used in addition to the
+// configured ones, until timesyncd is restarted.
+// Note: Requires systemd v248 or higher
+func (c *Conn) SetRuntimeNTPServers(servers []string) error {
+	if servers == nil {
+		servers = []string{}
+	}
+	return c.object.Call(dbusInterface+".SetRuntimeNTPServers", 0, servers).Err
+}
+
+// PollInterval returns the current interval between NTP requests.
+func (c *Conn) PollInterval() (time.Duration, error) {
+	return c.getDurationProperty("PollIntervalUSec")
+}
+
+// PollIntervalMin returns the lower bound of the interval between NTP requests.
+func (c *Conn) PollIntervalMin() (time.Duration, error) {
+	return c.getDurationProperty("PollIntervalMinUSec")
+}
+
+// PollIntervalMax returns the upper bound of the interval between NTP requests.
+func (c *Conn) PollIntervalMax() (time.Duration, error) {
+	return c.getDurationProperty("PollIntervalMaxUSec")
+}
+
+// RootDistanceMax returns the maximum root distance of servers accepted for synchronization.
+func (c *Conn) RootDistanceMax() (time.Duration, error) {
+	return c.getDurationProperty("RootDistanceMaxUSec")
+}
+
+// Frequency returns the frequency adjustment of the kernel clock, in 2^-16 ppm.
+func (c *Conn) Frequency() (int64, error) {
+	v, err := c.getProperty("Frequency")
+	if err != nil {
+		return 0, err
+	}
+	f, ok := v.Value().(int64)
+	if !ok {
+		return 0, fmt.Errorf("failed to typecast Frequency property %s to int64", v)
+	}
+	return f, nil
+}
+
+// NTPMessage returns the last NTP response received from the time server.
+func (c *Conn) NTPMessage() (*NTPMessage, error) {
+	v, err := c.getProperty("NTPMessage")
+	if err != nil {
+		return nil, err
+	}
+	message, ok := v.Value().([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast NTPMessage property %s", v)
+	}
+	return ntpMessageFromInterfaces(message)
+}
+
+func serverAddressFromVariant(v dbus.Variant) (net.IP, error) {
+	address, ok := v.Value().([]interface{})
+	if !ok || len(address) < 2 {
+		return nil, fmt.Errorf("failed to typecast ServerAddress property %s", v)
+	}
+	addr, ok := address[1].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast ServerAddress field 1 to []byte")
+	}
+	if len(addr) == 0 {
+		return nil, nil
+	}
+	if len(addr) != net.IPv4len && len(addr) != net.IPv6len {
+		return nil, fmt.Errorf("invalid ServerAddress length %d", len(addr))
+	}
+	return net.IP(addr), nil
+}
+
+func ntpMessageFromInterfaces(message []interface{}) (*NTPMessage, error) {
+	if len(message) < 15 {
+		return nil, fmt.Errorf("invalid number of NTPMessage fields: %d", len(message))
+	}
+
+	ret := &NTPMessage{}
+	u32 := []*uint32{&ret.Leap, &ret.Version, &ret.Mode, &ret.Stratum}
+	for i, dst := range u32 {
+		v, ok := message[i].(uint32)
+		if !ok {
+			return nil, fmt.Errorf("failed to typecast NTPMessage field %d to uint32", i)
+		}
+		*dst = v
+	}
+
+	var ok bool
+	ret.Precision, ok = message[4].(int32)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast NTPMessage field 4 to int32")
+	}
+	ret.RootDelay, ok = message[5].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast NTPMessage field 5 to uint64")
+	}
+	ret.RootDispersion, ok = message[6].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast NTPMessage field 6 to uint64")
+	}
+	ret.Reference, ok = message[7].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast NTPMessage field 7 to []byte")
+	}
+
+	u64 := []*uint64{&ret.OriginTimestamp, &ret.ReceiveTimestamp, &ret.TransmitTimestamp, &ret.DestinationTimestamp}
+	for i, dst := range u64 {
+		v, ok := message[8+i].(uint64)
+		if !ok {
+			return nil, fmt.Errorf("failed to typecast NTPMessage field %d to uint64", 8+i)
+		}
+		*dst = v
+	}
+
+	ret.Ignored, ok = message[12].(bool)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast NTPMessage field 12 to bool")
+	}
+	ret.PacketCount, ok = message[13].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast NTPMessage field 13 to uint64")
+	}
+	ret.Jitter, ok = message[14].(uint64)
+	if !ok {
+		return nil, fmt.Errorf("failed to typecast NTPMessage field 14 to uint64")
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timesync1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// TestNew ensures that New() works without errors.
+func TestNew(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+}
+
+func TestStatus(t *testing.T) {
+	conn, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ServerName(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.ServerAddress(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.NTPMessage(); err != nil {
+		t.Fatal(err)
+	}
+
+	min, err := conn.PollIntervalMin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	max, err := conn.PollIntervalMax()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min > max {
+		t.Fatalf("minimum poll interval %v exceeds maximum %v", min, max)
+	}
+}
+
+func TestNTPMessageFromInterfaces(t *testing.T) {
+	message, err := ntpMessageFromInterfaces([]interface{}{
+		uint32(0), uint32(4), uint32(4), uint32(2), int32(-23),
+		uint64(1000), uint64(2000), []byte{10, 0, 0, 1},
+		uint64(1000000), uint64(1005000), uint64(1005100), uint64(1000300),
+		false, uint64(12), uint64(150),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Stratum != 2 || message.Precision != -23 || message.PacketCount != 12 || message.Jitter != 150 {
+		t.Fatalf("unexpected NTP message %+v", message)
+	}
+	if offset := message.Offset(); offset != 4900*time.Microsecond {
+		t.Fatalf("expected offset 4.9ms but got %v", offset)
+	}
+	if delay := message.Delay(); delay != 200*time.Microsecond {
+		t.Fatalf("expected delay 200µs but got %v", delay)
+	}
+
+	if _, err := ntpMessageFromInterfaces([]interface{}{uint32(0)}); err == nil {
+		t.Fatal("expected error for truncated NTP message")
+	}
+}
+
+func TestServerAddressFromVariant(t *testing.T) {
+	addr, err := serverAddressFromVariant(dbus.MakeVariant([]interface{}{int32(2), []byte{192, 0, 2, 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.String() != "192.0.2.1" {
+		t.Fatalf("unexpected server address %s", addr)
+	}
+
+	addr, err = serverAddressFromVariant(dbus.MakeVariant([]interface{}{int32(0), []byte{}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr != nil {
+		t.Fatalf("expected no server address but got %s", addr)
+	}
+}
@@ -0,0 +1,800 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tmpfiles
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/sysusers"
+)
+
+// factoryDir holds the default sources of L and C lines without argument.
+const factoryDir = "/usr/share/factory"
+
+// Options selects what Apply does, like the flags of systemd-tmpfiles.
+type Options struct {
+	// Root is the directory all paths are relative to, "/" if empty.
+	Root string
+
+	Create bool // create and adjust paths
+	Clean  bool // remove files older than the age of their directory
+	Remove bool // remove the paths of r, R and the contents of D lines
+	Boot   bool // also apply lines with the "!" modifier
+
+	// Now is the time cleanup ages are relative to, time.Now() if zero.
+	Now time.Time
+}
+
+// deviceOf returns the device of the file system fi is on. Tests replace it,
+// as they cannot mount file systems.
+var deviceOf = fileDevice
+
+type applier struct {
+	opts     Options
+	db       *sysusers.Database
+	excludes []*Entry
+}
+
+// Apply carries out the entries as selected by opts. Failing entries do not
+// stop the remaining ones from being applied; the first error is returned.
+// Errors creating paths of lines with the "-" modifier are ignored.
+//
+// Symlinks are resolved inside the root as systemd-tmpfiles --root does:
+// absolute symlinks are relative to the root, and ".." never leaves it. A
+// symlink in the last component of a path is only followed by f, w, e, z, Z
+// and C lines; R lines remove the symlink itself and D lines leave it alone.
+// Neither cleanup nor recursive adjustments follow symlinks. Extended attributes,
+// file attributes and ACLs (t, T, h, H, a and A lines) are not supported.
+func Apply(entries []*Entry, opts Options) error {
+	if opts.Root == "" {
+		opts.Root = "/"
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	db, err := sysusers.Open(opts.Root)
+	if err != nil {
+		return err
+	}
+	a := &applier{opts: opts, db: db}
+	for _, e := range entries {
+		if e.Type == 'x' || e.Type == 'X' {
+			a.excludes = append(a.excludes, e)
+		}
+	}
+
+	var firstErr error
+	for _, e := range entries {
+		if e.Boot && !opts.Boot {
+			continue
+		}
+		if err := a.apply(e); err != nil {
+			err = fmt.Errorf("%s: %v", e.Path, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func (a *applier) apply(e *Entry) error {
+	paths, err := a.resolve(e)
+	if err != nil {
+		return err
+	}
+
+	if a.opts.Remove {
+		for _, p := range paths {
+			if err := a.remove(e, p); err != nil {
+				return err
+			}
+		}
+	}
+	if a.opts.Create {
+		for _, p := range paths {
+			if err := a.create(e, p); err != nil && !e.IgnoreErrors {
+				return err
+			}
+		}
+	}
+	if a.opts.Clean && e.HasAge {
+		for _, p := range paths {
+			if err := a.clean(e, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve returns the paths of an entry below the root, expanding globs.
+func (a *applier) resolve(e *Entry) ([]string, error) {
+	follow := strings.IndexByte(followTypes, e.Type) >= 0
+	if strings.IndexByte(globTypes, e.Type) < 0 || !strings.ContainsAny(e.Path, "*?[") {
+		p, err := a.chase(e.Path, follow)
+		if err != nil {
+			return nil, err
+		}
+		return []string{p}, nil
+	}
+
+	// Only the directories leading up to the first pattern are resolved
+	// before globbing, the matches are resolved again below.
+	prefix := e.Path[:strings.IndexAny(e.Path, "*?[")]
+	prefix = prefix[:strings.LastIndexByte(prefix, '/')+1]
+	dir, err := a.chase(prefix, true)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, e.Path[len(prefix):]))
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(matches))
+	for _, m := range matches {
+		p, err := a.chase(a.relative(m), follow)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, p)
+	}
+	return paths, nil
+}
+
+// followTypes are the types of lines which follow a symlink in the last
+// component of their path, as they write to, adjust or copy into its target.
+const followTypes = "fwezZC"
+
+// maxSymlinks is the number of symlinks chase follows before giving up, as
+// the kernel does.
+const maxSymlinks = 40
+
+// chase resolves the symlinks in path, which is relative to the root, like
+// systemd's chase() with CHASE_PREFIX_ROOT: absolute symlinks are resolved
+// relative to the root, and ".." never leaves it. A symlink in the last
+// component is only followed if last is set. Components which do not exist
+// are kept as they are.
+func (a *applier) chase(path string, last bool) (string, error) {
+	todo := strings.Split(filepath.ToSlash(path), "/")
+	done := "/"
+	links := 0
+	for len(todo) > 0 {
+		c := todo[0]
+		todo = todo[1:]
+		switch c {
+		case "", ".":
+			continue
+		case "..":
+			done = filepath.Dir(done)
+			continue
+		}
+
+		next := filepath.Join(done, c)
+		if len(todo) == 0 && !last {
+			done = next
+			break
+		}
+		fi, err := os.Lstat(filepath.Join(a.opts.Root, next))
+		if os.IsNotExist(err) {
+			// Cleaning the rooted path keeps ".." inside the root.
+			done = filepath.Join(append([]string{next}, todo...)...)
+			break
+		} else if err != nil {
+			return "", err
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			done = next
+			continue
+		}
+
+		if links++; links > maxSymlinks {
+			return "", fmt.Errorf("too many levels of symbolic links in %s", path)
+		}
+		target, err := os.Readlink(filepath.Join(a.opts.Root, next))
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			done = "/"
+		}
+		todo = append(strings.Split(filepath.ToSlash(target), "/"), todo...)
+	}
+	return filepath.Join(a.opts.Root, done), nil
+}
+
+func (a *applier) relative(p string) string {
+	rel, err := filepath.Rel(a.opts.Root, p)
+	if err != nil {
+		return p
+	}
+	return "/" + filepath.ToSlash(rel)
+}
+
+func (a *applier) create(e *Entry, p string) error {
+	switch e.Type {
+	case 'f':
+		return a.createFile(e, p)
+	case 'w':
+		return a.writeFile(e, p)
+	case 'd', 'D', 'v', 'q', 'Q':
+		return a.createDir(e, p)
+	case 'e':
+		if fi, err := os.Lstat(p); err == nil && fi.IsDir() {
+			return a.adjust(e, p, false)
+		}
+		return nil
+	case 'p':
+		return a.createNode(e, p, func() error { return mkfifo(p, e.modeOr(0644)) })
+	case 'c', 'b':
+		major, minor, err := parseDevice(e.Argument)
+		if err != nil {
+			return err
+		}
+		return a.createNode(e, p, func() error { return mknod(p, e.Type, e.modeOr(0644), major, minor) })
+	case 'L':
+		return a.createSymlink(e, p)
+	case 'C':
+		return a.copyTree(e, p)
+	case 'z', 'Z':
+		if _, err := os.Lstat(p); os.IsNotExist(err) {
+			return nil
+		}
+		return a.adjust(e, p, e.Type == 'Z')
+	case 'x', 'X', 'r', 'R':
+		return nil
+	}
+	return fmt.Errorf("type %c is not supported", e.Type)
+}
+
+func (e *Entry) modeOr(def os.FileMode) os.FileMode {
+	if e.HasMode {
+		return e.Mode
+	}
+	return def
+}
+
+func parseDevice(s string) (uint32, uint32, error) {
+	i := strings.IndexByte(s, ':')
+	if i < 0 {
+		return 0, 0, fmt.Errorf("invalid device number %q", s)
+	}
+	major, err1 := strconv.ParseUint(s[:i], 10, 32)
+	minor, err2 := strconv.ParseUint(s[i+1:], 10, 32)
+	if err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("invalid device number %q", s)
+	}
+	return uint32(major), uint32(minor), nil
+}
+
+// prepare creates the parent directories of p and, for lines with the "="
+// modifier or "+" replacing lines, removes an existing path of the wrong
+// type. It reports whether p exists afterwards.
+func (a *applier) prepare(e *Entry, p string, isType func(os.FileInfo) bool) (bool, error) {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return false, err
+	}
+	fi, err := os.Lstat(p)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if isType(fi) {
+		return true, nil
+	}
+	if !e.Replace && !e.Plus {
+		return true, fmt.Errorf("already exists and is not of the expected type")
+	}
+	if err := os.RemoveAll(p); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+func (a *applier) createFile(e *Entry, p string) error {
+	exists, err := a.prepare(e, p, func(fi os.FileInfo) bool { return fi.Mode().IsRegular() })
+	if err != nil {
+		return err
+	}
+	if !exists || e.Plus {
+		if err := ioutil.WriteFile(p, []byte(e.Argument), e.modeOr(0644).Perm()); err != nil {
+			return err
+		}
+	}
+	return a.adjustOnCreate(e, p, !exists)
+}
+
+func (a *applier) writeFile(e *Entry, p string) error {
+	flags := os.O_WRONLY | os.O_TRUNC
+	if e.Plus {
+		flags = os.O_WRONLY | os.O_APPEND
+	}
+	f, err := os.OpenFile(p, flags, 0)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.WriteString(f, e.Argument)
+	return err
+}
+
+func (a *applier) createDir(e *Entry, p string) error {
+	exists, err := a.prepare(e, p, func(fi os.FileInfo) bool { return fi.IsDir() })
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := os.Mkdir(p, e.modeOr(0755).Perm()); err != nil {
+			return err
+		}
+	}
+	return a.adjustOnCreate(e, p, !exists)
+}
+
+func (a *applier) createNode(e *Entry, p string, mk func() error) error {
+	exists, err := a.prepare(e, p, func(fi os.FileInfo) bool {
+		if e.Type == 'p' {
+			return fi.Mode()&os.ModeNamedPipe != 0
+		}
+		return fi.Mode()&os.ModeDevice != 0
+	})
+	if err != nil {
+		return err
+	}
+	if exists && e.Plus {
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+		exists = false
+	}
+	if !exists {
+		if err := mk(); err != nil {
+			return err
+		}
+	}
+	return a.adjustOnCreate(e, p, !exists)
+}
+
+func (a *applier) createSymlink(e *Entry, p string) error {
+	target := e.Argument
+	if target == "" {
+		target = filepath.Join(factoryDir, e.Path)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if existing, err := os.Readlink(p); err == nil && existing == target {
+		return nil
+	}
+	if _, err := os.Lstat(p); err == nil {
+		if !e.Plus {
+			return nil
+		}
+		if err := os.RemoveAll(p); err != nil {
+			return err
+		}
+	}
+	if err := os.Symlink(target, p); err != nil {
+		return err
+	}
+	return a.chown(e, p, true)
+}
+
+func (a *applier) copyTree(e *Entry, p string) error {
+	src := e.Argument
+	if src == "" {
+		src = filepath.Join(factoryDir, e.Path)
+	}
+	src, err := a.chase(src, false)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Lstat(p); err == nil && !e.Plus {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if err := copyPath(src, p); err != nil {
+		return err
+	}
+	return a.adjustOnCreate(e, p, true)
+}
+
+// copyPath copies src to dst recursively, merging into existing
+// directories and keeping existing files.
+func copyPath(src, dst string) error {
+	fi, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	switch {
+	case fi.IsDir():
+		if err := os.Mkdir(dst, fi.Mode().Perm()); err != nil && !os.IsExist(err) {
+			return err
+		}
+		entries, err := ioutil.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, c := range entries {
+			if err := copyPath(filepath.Join(src, c.Name()), filepath.Join(dst, c.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	case fi.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(target, dst); err != nil && !os.IsExist(err) {
+			return err
+		}
+		return nil
+	case fi.Mode().IsRegular():
+		in, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fi.Mode().Perm())
+		if os.IsExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+	return nil
+}
+
+// adjustOnCreate applies the mode and ownership of e to p, honoring the
+// ":" modifiers which restrict them to newly created paths.
+func (a *applier) adjustOnCreate(e *Entry, p string, created bool) error {
+	if e.HasMode && (created || !e.ModeOnCreate) {
+		if err := a.chmod(e, p); err != nil {
+			return err
+		}
+	}
+	return a.chown(e, p, created)
+}
+
+// adjust applies the mode and ownership of e to p and, if recursive, to
+// everything below it without following symlinks.
+func (a *applier) adjust(e *Entry, p string, recursive bool) error {
+	walk := func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink == 0 && e.HasMode && !e.ModeOnCreate {
+			if err := a.chmod(e, path); err != nil {
+				return err
+			}
+		}
+		if err := a.chown(e, path, false); err != nil {
+			return err
+		}
+		if !recursive && path != p && fi.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	}
+	if !recursive {
+		fi, err := os.Lstat(p)
+		return walk(p, fi, err)
+	}
+	return filepath.Walk(p, walk)
+}
+
+func (a *applier) chmod(e *Entry, p string) error {
+	fi, err := os.Lstat(p)
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+	mode := e.Mode
+	if e.ModeMask {
+		mode = maskMode(mode, fi.Mode())
+	}
+	if !fi.IsDir() {
+		mode &^= os.ModeSticky
+	}
+	if fi.Mode()&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky) == mode {
+		return nil
+	}
+	return os.Chmod(p, mode)
+}
+
+// maskMode removes the read, write and execute bits from mode for which the
+// existing mode has none set, as the "~" modifier does.
+func maskMode(mode, existing os.FileMode) os.FileMode {
+	for _, bits := range []os.FileMode{0444, 0222, 0111} {
+		if existing&bits == 0 {
+			mode &^= bits
+		}
+	}
+	if !existing.IsDir() {
+		mode &^= os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+	}
+	return mode
+}
+
+func (a *applier) chown(e *Entry, p string, created bool) error {
+	uid, gid := -1, -1
+	if e.User != "" && (created || !e.UserOnCreate) {
+		id, err := a.lookupID(e.User, true)
+		if err != nil {
+			return err
+		}
+		uid = id
+	}
+	if e.Group != "" && (created || !e.GroupOnCreate) {
+		id, err := a.lookupID(e.Group, false)
+		if err != nil {
+			return err
+		}
+		gid = id
+	}
+	if uid == -1 && gid == -1 {
+		return nil
+	}
+	return os.Lchown(p, uid, gid)
+}
+
+// lookupID resolves a user or group in the database of the root.
+func (a *applier) lookupID(name string, user bool) (int, error) {
+	if id, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return int(id), nil
+	}
+	if user {
+		if u := a.db.User(name); u != nil {
+			return int(u.UID), nil
+		}
+		return 0, fmt.Errorf("unknown user %q", name)
+	}
+	if g := a.db.Group(name); g != nil {
+		return int(g.GID), nil
+	}
+	return 0, fmt.Errorf("unknown group %q", name)
+}
+
+func (a *applier) remove(e *Entry, p string) error {
+	var err error
+	switch e.Type {
+	case 'r':
+		err = os.Remove(p)
+		if isNotEmpty(err) {
+			err = nil
+		}
+	case 'R':
+		if a.excluded(p) {
+			return nil
+		}
+		var fi os.FileInfo
+		fi, err = os.Lstat(p)
+		if err != nil {
+			break
+		}
+		// A symlink is removed itself, rather than what it points to.
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return os.Remove(p)
+		}
+		err = a.removeContents(p, fi)
+		if err == nil {
+			err = os.Remove(p)
+		}
+	case 'D':
+		var fi os.FileInfo
+		fi, err = os.Lstat(p)
+		if err != nil {
+			break
+		}
+		// The contents of a directory a symlink points to are left alone.
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		err = a.removeContents(p, fi)
+	}
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func isNotEmpty(err error) bool {
+	if pe, ok := err.(*os.PathError); ok {
+		err = pe.Err
+	}
+	return err == syscall.ENOTEMPTY || err == syscall.EEXIST
+}
+
+// removeContents removes everything below dir except excluded paths and
+// other file systems mounted below root.
+func (a *applier) removeContents(dir string, root os.FileInfo) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, c := range entries {
+		p := filepath.Join(dir, c.Name())
+		if a.excluded(p) || !sameDevice(root, c) {
+			continue
+		}
+		if c.IsDir() {
+			if err := a.removeContents(p, root); err != nil {
+				return err
+			}
+		}
+		if err := os.Remove(p); err != nil && !isNotEmpty(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// excluded reports whether p is excluded from cleanup and removal by an x
+// line covering it or an X line naming it.
+func (a *applier) excluded(p string) bool {
+	rel := a.relative(p)
+	for _, x := range a.excludes {
+		matched, _ := filepath.Match(x.Path, rel)
+		if matched {
+			return true
+		}
+		if x.Type == 'x' {
+			for dir := filepath.Dir(rel); dir != "/" && dir != "."; dir = filepath.Dir(dir) {
+				if matched, _ := filepath.Match(x.Path, dir); matched {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// clean removes the files and directories below p which are older than the
+// age of e.
+func (a *applier) clean(e *Entry, p string) error {
+	fi, err := os.Lstat(p)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return nil
+	}
+	cutoff := a.opts.Now.Add(-e.Age)
+	_, err = a.cleanDir(e, p, fi, cutoff, 1)
+	return err
+}
+
+// sameDevice reports whether fi is on the file system of root, or whether
+// this cannot be told.
+func sameDevice(root, fi os.FileInfo) bool {
+	rootDev, ok := deviceOf(root)
+	if !ok {
+		return true
+	}
+	dev, ok := deviceOf(fi)
+	return !ok || dev == rootDev
+}
+
+// cleanDir cleans dir at the given depth, reporting whether it is empty
+// afterwards. Like systemd-tmpfiles, it does not descend into other file
+// systems mounted below root.
+func (a *applier) cleanDir(e *Entry, dir string, root os.FileInfo, cutoff time.Time, depth int) (bool, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	empty := true
+	for _, fi := range entries {
+		p := filepath.Join(dir, fi.Name())
+		if a.excluded(p) || !sameDevice(root, fi) {
+			empty = false
+			continue
+		}
+
+		if fi.IsDir() {
+			childEmpty, err := a.cleanDir(e, p, root, cutoff, depth+1)
+			if err != nil {
+				return false, err
+			}
+			if childEmpty && !(e.AgeKeepFirstLevel && depth == 1) && a.old(e, fi, cutoff) {
+				if err := os.Remove(p); err != nil {
+					return false, err
+				}
+				continue
+			}
+			empty = false
+			continue
+		}
+
+		if (e.AgeKeepFirstLevel && depth == 1) || !a.old(e, fi, cutoff) {
+			empty = false
+			continue
+		}
+		if err := os.Remove(p); err != nil {
+			return false, err
+		}
+	}
+	return empty, nil
+}
+
+// old reports whether all timestamps of fi selected by the age-by field of
+// e are before cutoff.
+func (a *applier) old(e *Entry, fi os.FileInfo, cutoff time.Time) bool {
+	by := e.AgeBy
+	if by == "" {
+		by = "acmAM"
+	}
+	if fi.IsDir() {
+		by = strings.ToLower(strings.Map(func(r rune) rune {
+			if r >= 'A' && r <= 'Z' {
+				return r
+			}
+			return -1
+		}, by))
+	} else {
+		by = strings.Map(func(r rune) rune {
+			if r >= 'a' && r <= 'z' {
+				return r
+			}
+			return -1
+		}, by)
+	}
+
+	atime, ctime := fileTimes(fi)
+	compared := false
+	for _, c := range by {
+		var t time.Time
+		switch c {
+		case 'a':
+			t = atime
+		case 'c':
+			t = ctime
+		case 'm':
+			t = fi.ModTime()
+		default:
+			continue
+		}
+		if !t.Before(cutoff) {
+			return false
+		}
+		compared = true
+	}
+	// Without any timestamp to compare, nothing is known to be old.
+	return compared
+}
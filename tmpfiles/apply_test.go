@@ -0,0 +1,343 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tmpfiles
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApply(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-systemd-tmpfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	files := map[string]string{
+		"usr/share/factory/etc/skel/.profile": "factory",
+		"var/tmp/cache/old":                   "old",
+		"var/tmp/cache/new":                   "new",
+		"var/tmp/cache/keep/old":              "old",
+		"var/tmp/gone/file":                   "x",
+		"etc/existing":                        "existing",
+	}
+	for p, contents := range files {
+		p = filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	for _, p := range []string{"var/tmp/cache/old", "var/tmp/cache/keep/old", "var/tmp/cache/keep"} {
+		if err := os.Chtimes(filepath.Join(root, p), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("/", filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Parse(strings.NewReader(`
+d /run/foo/bar 0700
+f /etc/motd 0600 - - - hello
+f /etc/existing - - - - ignored
+w+ /etc/existing - - - - \n appended
+L /etc/localtime - - - - ../usr/share/zoneinfo/UTC
+C /etc/skel
+e /var/tmp/cache - - - amAM:1d
+x /var/tmp/cache/keep
+R /var/tmp/gone
+z /etc/motd 0640
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Apply(entries, Options{Root: root, Create: true, Clean: true, Remove: true, Now: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := []struct {
+		path     string
+		contents string
+		mode     os.FileMode
+	}{
+		{"etc/motd", "hello", 0640},
+		{"etc/existing", "existing\n appended", 0644},
+		{"etc/skel/.profile", "factory", 0644},
+		{"var/tmp/cache/new", "new", 0644},
+		{"var/tmp/cache/keep/old", "old", 0644},
+	}
+	for _, c := range checks {
+		p := filepath.Join(root, c.path)
+		b, err := ioutil.ReadFile(p)
+		if err != nil {
+			t.Errorf("%s: %v", c.path, err)
+			continue
+		}
+		if string(b) != c.contents {
+			t.Errorf("%s: expected %q, got %q", c.path, c.contents, string(b))
+		}
+		if fi, err := os.Stat(p); err != nil || fi.Mode().Perm() != c.mode {
+			t.Errorf("%s: expected mode %v, got %v", c.path, c.mode, fi.Mode())
+		}
+	}
+	if fi, err := os.Stat(filepath.Join(root, "run/foo/bar")); err != nil || !fi.IsDir() || fi.Mode().Perm() != 0700 {
+		t.Errorf("expected directory with mode 0700, got %v, %v", fi, err)
+	}
+	if target, err := os.Readlink(filepath.Join(root, "etc/localtime")); err != nil || target != "../usr/share/zoneinfo/UTC" {
+		t.Errorf("unexpected symlink %q, %v", target, err)
+	}
+	for _, p := range []string{"var/tmp/cache/old", "var/tmp/gone"} {
+		if _, err := os.Lstat(filepath.Join(root, p)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", p, err)
+		}
+	}
+
+	// Absolute symlinks are resolved relative to the root.
+	entries, err = Parse(strings.NewReader("f /escape/go-systemd-tmpfiles-test - - - - x\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(entries, Options{Root: root, Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat("/go-systemd-tmpfiles-test"); !os.IsNotExist(err) {
+		t.Errorf("expected no file outside of the root, got %v", err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(root, "go-systemd-tmpfiles-test")); err != nil || string(b) != "x" {
+		t.Errorf("expected file inside the root, got %q, %v", b, err)
+	}
+
+	// Boot only lines are skipped unless booting, and ignored errors do
+	// not fail.
+	entries, err = Parse(strings.NewReader("f! /etc/boot\nd- /etc/existing\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(entries, Options{Root: root, Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "etc/boot")); !os.IsNotExist(err) {
+		t.Errorf("expected boot only line to be skipped, got %v", err)
+	}
+}
+
+func TestApplySymlinkTarget(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-systemd-tmpfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	outside, err := ioutil.TempDir("", "go-systemd-tmpfiles-outside")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outside)
+
+	files := map[string]string{
+		filepath.Join(outside, "target"):       "original",
+		filepath.Join(outside, "dir/file"):     "original",
+		filepath.Join(root, "var/inside"):      "inside",
+		filepath.Join(root, "usr/share/a/new"): "copied",
+	}
+	for p, contents := range files {
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rel, err := filepath.Rel(filepath.Join(root, "var"), outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"var/link":     filepath.Join(rel, "target"),
+		"var/abs":      filepath.Join(outside, "target"),
+		"var/dir":      filepath.Join(outside, "dir"),
+		"var/internal": "inside",
+		"var/run":      "/run",
+	}
+	for p, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, p)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Neither relative nor absolute symlinks leave the root, the targets
+	// are resolved below it instead.
+	for _, line := range []string{
+		"w /var/link - - - - pwned",
+		"w+ /var/abs - - - - pwned",
+		"f /var/link 0600",
+		"f+ /var/abs 0600 - - - pwned",
+		"z /var/link 0600",
+		"Z /var/dir 0600",
+		"e /var/dir 0600",
+		"w /var/l* - - - - pwned",
+		"w /var/dir/file - - - - pwned",
+		"C+ /var/dir - - - - /usr/share/a",
+		"D- /var/dir",
+		"R /var/dir/file",
+		"R /var/dir",
+	} {
+		entries, err := Parse(strings.NewReader(line+"\n"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Apply(entries, Options{Root: root, Create: true, Remove: true}); err != nil {
+			t.Errorf("%q: %v", line, err)
+		}
+	}
+	for _, p := range []string{"target", "dir/file"} {
+		p = filepath.Join(outside, p)
+		if b, err := ioutil.ReadFile(p); err != nil || string(b) != "original" {
+			t.Errorf("expected %s outside the root to be unchanged, got %q, %v", p, b, err)
+		}
+		if fi, err := os.Stat(p); err != nil || fi.Mode().Perm() != 0644 {
+			t.Errorf("expected mode of %s to be unchanged, got %v, %v", p, fi, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outside, "dir/new")); !os.IsNotExist(err) {
+		t.Errorf("expected nothing to be copied outside the root, got %v", err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(root, outside, "dir/new")); err != nil || string(b) != "copied" {
+		t.Errorf("expected copy resolved inside the root, got %q, %v", b, err)
+	}
+	if _, err := os.Lstat(filepath.Join(root, "var/dir")); !os.IsNotExist(err) {
+		t.Errorf("expected R to remove the symlink itself, got %v", err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(root, outside, "target")); err != nil || string(b) != "pwned" {
+		t.Errorf("expected target resolved inside the root, got %q, %v", b, err)
+	}
+
+	// Symlinks resolving inside the root are followed, absolute ones
+	// relative to the root.
+	entries, err := Parse(strings.NewReader("w /var/internal - - - - written\nd /var/run/foo 0755\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(entries, Options{Root: root, Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(root, "var/inside")); err != nil || string(b) != "written" {
+		t.Errorf("expected write through symlink inside the root, got %q, %v", b, err)
+	}
+	if fi, err := os.Stat(filepath.Join(root, "run/foo")); err != nil || !fi.IsDir() {
+		t.Errorf("expected directory below the symlink inside the root, got %v, %v", fi, err)
+	}
+}
+
+func TestApplyAgeByUnsupported(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-systemd-tmpfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	p := filepath.Join(root, "var/cache/old")
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(p, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(p, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only birth times, which are not compared, never make a file old.
+	entries := []*Entry{{Type: 'e', Path: "/var/cache", AgeBy: "bB", Age: 24 * time.Hour, HasAge: true}}
+	if err := Apply(entries, Options{Root: root, Clean: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(p); err != nil {
+		t.Errorf("expected file to be kept, got %v", err)
+	}
+}
+
+func TestApplyOtherFileSystems(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-systemd-tmpfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	// Directories named mnt pretend to be other file systems mounted below
+	// the paths of the lines.
+	defer func(f func(os.FileInfo) (uint64, bool)) { deviceOf = f }(deviceOf)
+	deviceOf = func(fi os.FileInfo) (uint64, bool) {
+		if fi.Name() == "mnt" {
+			return 2, true
+		}
+		return 1, true
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	for _, p := range []string{
+		"var/cache/old", "var/cache/mnt/old",
+		"var/lib/empty/file", "var/lib/empty/mnt/file",
+		"var/lib/gone/file", "var/lib/gone/mnt/file",
+	} {
+		p = filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(p, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(filepath.Join(root, "var/cache/mnt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Parse(strings.NewReader(`
+e /var/cache - - - amAM:1d
+D /var/lib/empty
+R /var/lib/gone
+`), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Removing /var/lib/gone itself fails, as the mount point below it is
+	// kept.
+	Apply(entries, Options{Root: root, Clean: true, Remove: true})
+
+	for _, p := range []string{"var/cache/old", "var/lib/empty/file", "var/lib/gone/file"} {
+		if _, err := os.Lstat(filepath.Join(root, p)); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", p, err)
+		}
+	}
+	for _, p := range []string{"var/cache/mnt/old", "var/lib/empty/mnt/file", "var/lib/gone/mnt/file"} {
+		if _, err := os.Lstat(filepath.Join(root, p)); err != nil {
+			t.Errorf("expected %s on another file system to be kept, got %v", p, err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tmpfiles
+
+import (
+	"os"
+	"syscall"
+)
+
+func mknod(p string, typ byte, mode os.FileMode, major, minor uint32) error {
+	m := uint32(mode.Perm()) | syscall.S_IFCHR
+	if typ == 'b' {
+		m = uint32(mode.Perm()) | syscall.S_IFBLK
+	}
+	dev := (major&0xfff)<<8 | minor&0xff | (minor&^0xff)<<12
+	if err := syscall.Mknod(p, m, int(dev)); err != nil {
+		return &os.PathError{Op: "mknod", Path: p, Err: err}
+	}
+	return nil
+}
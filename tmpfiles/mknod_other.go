@@ -0,0 +1,29 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux && !windows
+// +build !linux,!windows
+
+package tmpfiles
+
+import (
+	"errors"
+	"os"
+)
+
+// Device numbers are encoded differently on every system, only creating
+// device nodes on Linux is supported.
+func mknod(p string, typ byte, mode os.FileMode, major, minor uint32) error {
+	return &os.PathError{Op: "mknod", Path: p, Err: errors.New("not supported on this system")}
+}
@@ -0,0 +1,34 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tmpfiles
+
+import (
+	"errors"
+	"os"
+)
+
+var errNotSupported = errors.New("not supported on windows")
+
+func mkfifo(p string, mode os.FileMode) error {
+	return &os.PathError{Op: "mkfifo", Path: p, Err: errNotSupported}
+}
+
+func mknod(p string, typ byte, mode os.FileMode, major, minor uint32) error {
+	return &os.PathError{Op: "mknod", Path: p, Err: errNotSupported}
+}
+
+func fileDevice(fi os.FileInfo) (uint64, bool) {
+	return 0, false
+}
@@ -0,0 +1,358 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tmpfiles parses tmpfiles.d configuration and applies it to a root
+// directory, like systemd-tmpfiles does. See
+// https://www.freedesktop.org/software/systemd/man/tmpfiles.d.html
+package tmpfiles
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+// Entry is a line of a tmpfiles.d file.
+type Entry struct {
+	// Type is the line type, e.g. 'f' or 'd'.
+	Type byte
+	// Plus is set for the "+" variants of types like f+ or L+.
+	Plus bool
+
+	Boot         bool // "!": only apply during boot
+	IgnoreErrors bool // "-": ignore errors creating the path
+	Replace      bool // "=": remove an existing path of the wrong type
+	Credential   bool // "^": the argument names a credential
+
+	Path string
+
+	// Mode is the access mode; HasMode is unset if the field was "-".
+	Mode    os.FileMode
+	HasMode bool
+	// ModeMask ("~") masks Mode with the existing permissions, so that
+	// only bits already set are kept.
+	ModeMask bool
+	// ModeOnCreate (":") only applies Mode when the path is created.
+	ModeOnCreate bool
+
+	// User and Group are the names or numeric IDs of the owner, empty if
+	// the field was "-".
+	User          string
+	Group         string
+	UserOnCreate  bool
+	GroupOnCreate bool
+
+	// Age is the cleanup age; HasAge is unset if the field was "-".
+	Age    time.Duration
+	HasAge bool
+	// AgeKeepFirstLevel ("~") only cleans up below the first level of the
+	// directory.
+	AgeKeepFirstLevel bool
+	// AgeBy selects the timestamps considered for cleanup, e.g. "am" for
+	// access and modification time. Lower case letters apply to files,
+	// upper case ones to directories. If empty, files are cleaned up by
+	// their access, status change and modification times, and directories
+	// by their access and modification times.
+	AgeBy string
+
+	// Argument is the last field, e.g. the contents of a file or the target
+	// of a symlink. Base64 encoded arguments ("~") are decoded.
+	Argument string
+}
+
+// Types which accept a glob as path.
+const globTypes = "wexXrRzZtThHaA"
+
+// Types which configure the cleanup of a directory.
+const cleanupTypes = "dDevqQCxX"
+
+// knownTypes are the supported line types.
+const knownTypes = "fFwdDevqQpLcbCxXrRzZtThHaA"
+
+// Parse reads tmpfiles.d configuration from r. If c is not nil, specifiers
+// in the path and argument are expanded with it, otherwise they are left as
+// they are.
+func Parse(r io.Reader, c *unit.SpecifierContext) ([]*Entry, error) {
+	entries := []*Entry{}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		e, err := ParseLine(line, c)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", n, err)
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParseLine parses a single tmpfiles.d line. See Parse.
+func ParseLine(line string, c *unit.SpecifierContext) (*Entry, error) {
+	fields, arg, err := splitFields(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("missing path in %q", line)
+	}
+
+	e := &Entry{}
+	base64Arg, err := e.parseType(fields[0])
+	if err != nil {
+		return nil, err
+	}
+	for len(fields) < 6 {
+		fields = append(fields, "-")
+	}
+
+	e.Path = fields[1]
+	if c != nil {
+		if e.Path, err = unit.ExpandSpecifiers(e.Path, c); err != nil {
+			return nil, err
+		}
+	}
+	if !strings.HasPrefix(e.Path, "/") {
+		return nil, fmt.Errorf("path %q is not absolute", e.Path)
+	}
+	for _, c := range strings.Split(e.Path, "/") {
+		if c == "." || c == ".." {
+			return nil, fmt.Errorf("path %q is not normalized", e.Path)
+		}
+	}
+	if strings.ContainsAny(e.Path, "*?[") && strings.IndexByte(globTypes, e.Type) < 0 {
+		return nil, fmt.Errorf("globs are not allowed for type %c in %q", e.Type, e.Path)
+	}
+
+	if err := e.parseMode(fields[2]); err != nil {
+		return nil, err
+	}
+	e.User, e.UserOnCreate = parseOwner(fields[3])
+	e.Group, e.GroupOnCreate = parseOwner(fields[4])
+	if err := e.parseAge(fields[5]); err != nil {
+		return nil, err
+	}
+	if e.HasAge && strings.IndexByte(cleanupTypes, e.Type) < 0 {
+		return nil, fmt.Errorf("age is not supported for type %c", e.Type)
+	}
+
+	if arg != "" && arg != "-" {
+		if c != nil && !e.Credential {
+			if arg, err = unit.ExpandSpecifiers(arg, c); err != nil {
+				return nil, err
+			}
+		}
+		switch {
+		case base64Arg:
+			b, err := base64.StdEncoding.DecodeString(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid base64 argument: %v", err)
+			}
+			arg = string(b)
+		case e.Type == 'f' || e.Type == 'w':
+			if arg, err = cunescape(arg); err != nil {
+				return nil, err
+			}
+		}
+		e.Argument = arg
+	}
+	if e.Argument == "" && strings.IndexByte("wtTaA", e.Type) >= 0 {
+		return nil, fmt.Errorf("type %c requires an argument", e.Type)
+	}
+	if e.Argument == "" && (e.Type == 'c' || e.Type == 'b') {
+		return nil, fmt.Errorf("type %c requires a device number argument", e.Type)
+	}
+	return e, nil
+}
+
+// splitFields splits the first six fields of a line, which may be quoted,
+// from the argument, which is the rest of the line.
+func splitFields(line string) ([]string, string, error) {
+	var fields []string
+	rest := line
+	for len(fields) < 6 {
+		rest = strings.TrimLeft(rest, " \t")
+		if rest == "" {
+			break
+		}
+		end := 0
+		var quote byte
+		for ; end < len(rest); end++ {
+			ch := rest[end]
+			if quote == 0 && (ch == ' ' || ch == '\t') {
+				break
+			}
+			switch {
+			case ch == '\\' && end+1 < len(rest):
+				end++
+			case quote != 0 && ch == quote:
+				quote = 0
+			case quote == 0 && (ch == '"' || ch == '\''):
+				quote = ch
+			}
+		}
+		words, err := unit.SplitWords(rest[:end])
+		if err != nil {
+			return nil, "", err
+		}
+		fields = append(fields, strings.Join(words, ""))
+		rest = rest[end:]
+	}
+	return fields, strings.TrimLeft(rest, " \t"), nil
+}
+
+// parseType parses the type and its modifiers, reporting whether the
+// argument is base64 encoded.
+func (e *Entry) parseType(t string) (bool, error) {
+	if t == "" || strings.IndexByte(knownTypes, t[0]) < 0 {
+		return false, fmt.Errorf("unknown type %q", t)
+	}
+	e.Type = t[0]
+	base64Arg := false
+	for _, m := range t[1:] {
+		switch m {
+		case '+':
+			e.Plus = true
+		case '!':
+			e.Boot = true
+		case '-':
+			e.IgnoreErrors = true
+		case '=':
+			e.Replace = true
+		case '~':
+			base64Arg = true
+		case '^':
+			e.Credential = true
+		default:
+			return false, fmt.Errorf("unknown modifier %q in type %q", m, t)
+		}
+	}
+	// F is the deprecated spelling of f+.
+	if e.Type == 'F' {
+		e.Type, e.Plus = 'f', true
+	}
+	return base64Arg, nil
+}
+
+func (e *Entry) parseMode(s string) error {
+	if s == "-" {
+		return nil
+	}
+	if strings.HasPrefix(s, ":") {
+		e.ModeOnCreate, s = true, s[1:]
+	}
+	if strings.HasPrefix(s, "~") {
+		e.ModeMask, s = true, s[1:]
+	}
+	m, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || m > 07777 {
+		return fmt.Errorf("invalid mode %q", s)
+	}
+	e.Mode, e.HasMode = fileMode(uint32(m)), true
+	return nil
+}
+
+// fileMode converts a numeric mode to an os.FileMode, including the setuid,
+// setgid and sticky bits.
+func fileMode(m uint32) os.FileMode {
+	mode := os.FileMode(m & 0777)
+	if m&04000 != 0 {
+		mode |= os.ModeSetuid
+	}
+	if m&02000 != 0 {
+		mode |= os.ModeSetgid
+	}
+	if m&01000 != 0 {
+		mode |= os.ModeSticky
+	}
+	return mode
+}
+
+func parseOwner(s string) (string, bool) {
+	if s == "-" {
+		return "", false
+	}
+	if strings.HasPrefix(s, ":") {
+		return s[1:], true
+	}
+	return s, false
+}
+
+func (e *Entry) parseAge(s string) error {
+	if s == "-" {
+		return nil
+	}
+	if i := strings.IndexByte(s, ':'); i >= 0 {
+		e.AgeBy, s = s[:i], s[i+1:]
+		for _, c := range e.AgeBy {
+			if !strings.ContainsRune("abcmABCM", c) {
+				return fmt.Errorf("invalid age-by specification %q", e.AgeBy)
+			}
+		}
+		// Birth times are not supported, which would leave nothing to
+		// compare.
+		if !strings.ContainsAny(e.AgeBy, "acmACM") {
+			return fmt.Errorf("unsupported age-by specification %q", e.AgeBy)
+		}
+	}
+	if strings.HasPrefix(s, "~") {
+		e.AgeKeepFirstLevel, s = true, s[1:]
+	}
+	age, err := unit.ParseTimeSpan(s)
+	if err != nil {
+		return err
+	}
+	e.Age, e.HasAge = age, true
+	return nil
+}
+
+// cunescape decodes C-style escapes in the argument of f and w lines.
+func cunescape(s string) (string, error) {
+	var b strings.Builder
+	for s != "" {
+		switch {
+		case s[0] != '\\':
+			b.WriteByte(s[0])
+			s = s[1:]
+			continue
+		case len(s) > 1 && (s[1] == '"' || s[1] == '\''):
+			b.WriteByte(s[1])
+			s = s[2:]
+			continue
+		}
+		v, multibyte, tail, err := strconv.UnquoteChar(s, '"')
+		if err != nil {
+			return "", fmt.Errorf("invalid escape sequence in %q", s)
+		}
+		if multibyte {
+			b.WriteRune(v)
+		} else {
+			b.WriteByte(byte(v))
+		}
+		s = tail
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tmpfiles
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-systemd/v22/unit"
+)
+
+func TestParse(t *testing.T) {
+	in := `# comment
+d     /run/foo        0755 root root 10d -
+f+!   /etc/motd       -    -    -    -   Hello\tworld\n
+L     /etc/localtime  -    -    -    -   ../usr/share/zoneinfo/UTC
+D-    /tmp/%b         :1777 :root - m:~1h
+z     /var/log/*.log  ~0640 - adm
+w~    /sys/foo        -    -    -    -   aGVsbG8=
+c     /dev/null2      0666 -    -    -   1:3
+F     /var/lib/f      -
+x     "/tmp/with space"
+`
+	c := &unit.SpecifierContext{BootID: "b00t"}
+	entries, err := Parse(strings.NewReader(in), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []*Entry{
+		{Type: 'd', Path: "/run/foo", Mode: 0755, HasMode: true, User: "root", Group: "root", Age: 10 * 24 * time.Hour, HasAge: true},
+		{Type: 'f', Plus: true, Boot: true, Path: "/etc/motd", Argument: "Hello\tworld\n"},
+		{Type: 'L', Path: "/etc/localtime", Argument: "../usr/share/zoneinfo/UTC"},
+		{Type: 'D', IgnoreErrors: true, Path: "/tmp/b00t", Mode: 0777 | os.ModeSticky, HasMode: true, ModeOnCreate: true,
+			User: "root", UserOnCreate: true, Age: time.Hour, HasAge: true, AgeKeepFirstLevel: true, AgeBy: "m"},
+		{Type: 'z', Path: "/var/log/*.log", Mode: 0640, HasMode: true, ModeMask: true, Group: "adm"},
+		{Type: 'w', Path: "/sys/foo", Argument: "hello"},
+		{Type: 'c', Path: "/dev/null2", Mode: 0666, HasMode: true, Argument: "1:3"},
+		{Type: 'f', Plus: true, Path: "/var/lib/f"},
+		{Type: 'x', Path: "/tmp/with space"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d", len(expected), len(entries))
+	}
+	for i := range expected {
+		if !reflect.DeepEqual(expected[i], entries[i]) {
+			t.Errorf("case %d: expected %+v, got %+v", i, expected[i], entries[i])
+		}
+	}
+}
+
+func TestParseFail(t *testing.T) {
+	tests := []string{
+		"y /foo",
+		"d",
+		"d relative",
+		"d /foo/../etc",
+		"d /foo/* 0755",
+		"d /foo 999",
+		"d /foo - - - forever",
+		"f /foo - - - 1d",
+		"w /foo",
+		"c /dev/foo 0644",
+		"f% /foo",
+		"d /foo - - - x:1d",
+		"d /foo - - - b:1d",
+		"e /foo - - - bB:1d",
+		`f /foo - - - - \q`,
+	}
+
+	for i, tt := range tests {
+		if e, err := ParseLine(tt, nil); err == nil {
+			t.Errorf("case %d: expected error for %q, got %+v", i, tt, e)
+		}
+	}
+}
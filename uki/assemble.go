@@ -0,0 +1,247 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uki
+
+import (
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// order is the order ukify places sections in. The kernel comes last so it
+// can grow in place when it is decompressed, sections not listed here are
+// placed before the initrds.
+var order = []string{
+	SectionOSRelease,
+	SectionCmdline,
+	SectionDTB,
+	SectionUname,
+	SectionSplash,
+	SectionPCRPKey,
+	SectionPCRSig,
+	"",
+	SectionInitrd,
+	SectionUcode,
+	SectionLinux,
+}
+
+func rank(name string) int {
+	for i, n := range order {
+		if n == name {
+			return i
+		}
+	}
+	for i, n := range order {
+		if n == "" {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// Characteristics of the added sections: initialized, read-only data.
+const sectionCharacteristics = pe.IMAGE_SCN_CNT_INITIALIZED_DATA | pe.IMAGE_SCN_MEM_READ
+
+// Assemble returns a unified kernel image made of the systemd-stub binary
+// stub and the given sections, typically read from
+// /usr/lib/systemd/boot/efi/linuxx64.efi.stub. Sections are placed in the
+// order ukify uses and a .uname section is derived from the kernel if none
+// is given. Several initrds must be concatenated into a single section, and
+// the stub must not be signed yet, as appending sections invalidates the
+// signature.
+func Assemble(stub []byte, sections []Section) ([]byte, error) {
+	sections = append([]Section(nil), sections...)
+	if !hasSection(sections, SectionUname) {
+		for _, s := range sections {
+			if s.Name != SectionLinux {
+				continue
+			}
+			if v := strings.Fields(KernelVersion(s.Data)); len(v) > 0 {
+				sections = append(sections, Section{Name: SectionUname, Data: []byte(v[0])})
+			}
+		}
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		return rank(sections[i].Name) < rank(sections[j].Name)
+	})
+	return AddSections(stub, sections)
+}
+
+func hasSection(sections []Section, name string) bool {
+	for _, s := range sections {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddSections returns a copy of the PE binary image with sections appended
+// in the given order, which is how unified kernel images and addons are
+// built from a stub. The sections must not exist in image yet.
+func AddSections(image []byte, sections []Section) ([]byte, error) {
+	if len(image) < 0x40 || image[0] != 'M' || image[1] != 'Z' {
+		return nil, errors.New("not a PE binary")
+	}
+	peOff := int(binary.LittleEndian.Uint32(image[0x3c:]))
+	fhOff := peOff + 4
+	optOff := fhOff + 20
+	if peOff < 0 || peOff > len(image) || optOff > len(image) || string(image[peOff:fhOff]) != "PE\x00\x00" {
+		return nil, errors.New("not a PE binary")
+	}
+	numSections := int(binary.LittleEndian.Uint16(image[fhOff+2:]))
+	optSize := int(binary.LittleEndian.Uint16(image[fhOff+16:]))
+	if optSize < 68 || optOff+optSize > len(image) {
+		return nil, errors.New("PE binary has no optional header")
+	}
+
+	var securityOff int
+	switch binary.LittleEndian.Uint16(image[optOff:]) {
+	case 0x10b: // PE32
+		securityOff = optOff + 96 + 4*8
+	case 0x20b: // PE32+
+		securityOff = optOff + 112 + 4*8
+	default:
+		return nil, errors.New("unknown optional header magic")
+	}
+	if securityOff+8 <= optOff+optSize && binary.LittleEndian.Uint64(image[securityOff:]) != 0 {
+		return nil, errors.New("PE binary is signed")
+	}
+	sectionAlignment := binary.LittleEndian.Uint32(image[optOff+32:])
+	fileAlignment := binary.LittleEndian.Uint32(image[optOff+36:])
+	sizeOfHeaders := int(binary.LittleEndian.Uint32(image[optOff+60:]))
+	if sectionAlignment == 0 || fileAlignment == 0 {
+		return nil, errors.New("invalid section alignment")
+	}
+	if sizeOfHeaders < 0 || sizeOfHeaders > len(image) {
+		return nil, errors.New("truncated PE header")
+	}
+
+	tableOff := optOff + optSize
+	tableEnd := tableOff + 40*numSections
+	if tableEnd > len(image) {
+		return nil, errors.New("truncated section table")
+	}
+
+	// New sections go after the memory and file contents of the existing
+	// ones, anything following them like debug data is dropped.
+	var virtEnd, rawEnd uint64
+	existing := map[string]bool{}
+	for i := 0; i < numSections; i++ {
+		var h pe.SectionHeader32
+		off := tableOff + 40*i
+		name := strings.TrimRight(string(image[off:off+8]), "\x00")
+		existing[name] = true
+		h.VirtualSize = binary.LittleEndian.Uint32(image[off+8:])
+		h.VirtualAddress = binary.LittleEndian.Uint32(image[off+12:])
+		h.SizeOfRawData = binary.LittleEndian.Uint32(image[off+16:])
+		h.PointerToRawData = binary.LittleEndian.Uint32(image[off+20:])
+		size := h.VirtualSize
+		if h.SizeOfRawData > size {
+			size = h.SizeOfRawData
+		}
+		if e := uint64(h.VirtualAddress) + uint64(size); e > virtEnd {
+			virtEnd = e
+		}
+		if e := uint64(h.PointerToRawData) + uint64(h.SizeOfRawData); e > rawEnd {
+			rawEnd = e
+		}
+	}
+	if virtEnd > math.MaxUint32 {
+		return nil, errors.New("section exceeds the address space")
+	}
+	if rawEnd > uint64(len(image)) {
+		return nil, errors.New("truncated section data")
+	}
+	if rawEnd < uint64(sizeOfHeaders) {
+		rawEnd = uint64(sizeOfHeaders)
+	}
+	if tableEnd+40*len(sections) > sizeOfHeaders {
+		return nil, errors.New("not enough space in PE header for additional sections")
+	}
+
+	out := make([]byte, align(rawEnd, fileAlignment))
+	copy(out, image[:rawEnd])
+	virt := align(virtEnd, sectionAlignment)
+	for i, s := range sections {
+		rawSize := align(uint64(len(s.Data)), fileAlignment)
+		if uint64(len(out))+rawSize > math.MaxUint32 || virt+uint64(len(s.Data)) > math.MaxUint32 {
+			return nil, errors.New("image too large")
+		}
+		if len(s.Name) == 0 || len(s.Name) > 8 {
+			return nil, fmt.Errorf("invalid section name %q", s.Name)
+		}
+		if existing[s.Name] {
+			return nil, fmt.Errorf("section %s already exists", s.Name)
+		}
+		existing[s.Name] = true
+
+		h := out[tableEnd+40*i:]
+		copy(h[:8], s.Name)
+		binary.LittleEndian.PutUint32(h[8:], uint32(len(s.Data)))
+		binary.LittleEndian.PutUint32(h[12:], uint32(virt))
+		binary.LittleEndian.PutUint32(h[16:], uint32(rawSize))
+		binary.LittleEndian.PutUint32(h[20:], uint32(len(out)))
+		binary.LittleEndian.PutUint32(h[36:], sectionCharacteristics)
+
+		out = append(out, s.Data...)
+		out = append(out, make([]byte, int(align(uint64(len(out)), fileAlignment))-len(out))...)
+		virt = align(virt+uint64(len(s.Data)), sectionAlignment)
+	}
+	if virt > math.MaxUint32 {
+		return nil, errors.New("image too large")
+	}
+
+	binary.LittleEndian.PutUint16(out[fhOff+2:], uint16(numSections+len(sections)))
+	binary.LittleEndian.PutUint32(out[optOff+56:], uint32(virt))
+	binary.LittleEndian.PutUint32(out[optOff+64:], Checksum(out))
+	return out, nil
+}
+
+func align(v uint64, a uint32) uint64 {
+	return (v + uint64(a) - 1) / uint64(a) * uint64(a)
+}
+
+// Checksum returns the checksum of the PE binary image, as stored in its
+// optional header. If image is too short to have the checksum field, all of
+// it is summed up.
+func Checksum(image []byte) uint32 {
+	csOff := -1
+	if len(image) >= 0x40 {
+		peOff := int64(binary.LittleEndian.Uint32(image[0x3c:]))
+		if off := peOff + 4 + 20 + 64; off+4 <= int64(len(image)) {
+			csOff = int(off)
+		}
+	}
+
+	var sum uint64
+	for i := 0; i < len(image); i += 2 {
+		if i == csOff || i == csOff+2 {
+			continue
+		}
+		w := uint64(image[i])
+		if i+1 < len(image) {
+			w |= uint64(image[i+1]) << 8
+		}
+		sum += w
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	sum = (sum & 0xffff) + (sum >> 16)
+	return uint32(sum) + uint32(len(image))
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uki
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// stub returns a minimal PE32+ binary with a single .text section and room
+// for sixteen more section headers.
+func stub(t *testing.T) []byte {
+	var b bytes.Buffer
+	dos := make([]byte, 0x40)
+	dos[0], dos[1] = 'M', 'Z'
+	binary.LittleEndian.PutUint32(dos[0x3c:], 0x40)
+	b.Write(dos)
+	b.WriteString("PE\x00\x00")
+
+	fh := pe.FileHeader{
+		Machine:              pe.IMAGE_FILE_MACHINE_AMD64,
+		NumberOfSections:     1,
+		SizeOfOptionalHeader: uint16(binary.Size(pe.OptionalHeader64{})),
+		Characteristics:      pe.IMAGE_FILE_EXECUTABLE_IMAGE,
+	}
+	oh := pe.OptionalHeader64{
+		Magic:               0x20b,
+		SectionAlignment:    0x1000,
+		FileAlignment:       0x200,
+		SizeOfImage:         0x2000,
+		SizeOfHeaders:       0x400,
+		Subsystem:           pe.IMAGE_SUBSYSTEM_EFI_APPLICATION,
+		NumberOfRvaAndSizes: 16,
+	}
+	sh := pe.SectionHeader32{
+		VirtualSize:      4,
+		VirtualAddress:   0x1000,
+		SizeOfRawData:    0x200,
+		PointerToRawData: 0x400,
+		Characteristics:  pe.IMAGE_SCN_CNT_CODE | pe.IMAGE_SCN_MEM_EXECUTE | pe.IMAGE_SCN_MEM_READ,
+	}
+	copy(sh.Name[:], ".text")
+	for _, v := range []interface{}{fh, oh, sh} {
+		if err := binary.Write(&b, binary.LittleEndian, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Write(make([]byte, 0x400-b.Len()))
+	b.WriteString("\xc3\xc3\xc3\xc3")
+	b.Write(make([]byte, 0x600-b.Len()))
+	return b.Bytes()
+}
+
+// kernel returns a fake x86 kernel image with a version string.
+func kernel() []byte {
+	b := make([]byte, 0x400)
+	copy(b[0x202:], "HdrS")
+	binary.LittleEndian.PutUint16(b[0x20e:], 0x100)
+	copy(b[0x300:], "6.1.0-test (builder@example) #1 SMP\x00")
+	return b
+}
+
+func TestAssemble(t *testing.T) {
+	s := stub(t)
+	osrel := "ID=test\nPRETTY_NAME=\"Test OS\"\n"
+	b, err := Assemble(s, []Section{
+		{Name: SectionLinux, Data: kernel()},
+		{Name: SectionInitrd, Data: []byte("initrd")},
+		{Name: SectionCmdline, Data: []byte("root=/dev/sda1 quiet\n")},
+		{Name: SectionOSRelease, Data: []byte(osrel)},
+		{Name: SectionSBAT, Data: []byte("sbat,1,SBAT Version,sbat,1,https://github.com/rhboot/shim/blob/main/SBAT.md\n")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := pe.NewFile(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, sec := range f.Sections {
+		names = append(names, sec.Name)
+		if sec.VirtualAddress%0x1000 != 0 || sec.Offset%0x200 != 0 {
+			t.Errorf("section %s is not aligned", sec.Name)
+		}
+	}
+	want := []string{".text", ".osrel", ".cmdline", ".uname", ".sbat", ".initrd", ".linux"}
+	if len(names) != len(want) {
+		t.Fatalf("unexpected sections %v", names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("unexpected sections %v", names)
+		}
+	}
+	last := f.Sections[len(f.Sections)-1]
+	oh := f.OptionalHeader.(*pe.OptionalHeader64)
+	if uint64(oh.SizeOfImage) != align(uint64(last.VirtualAddress+last.VirtualSize), 0x1000) {
+		t.Errorf("unexpected size of image %#x", oh.SizeOfImage)
+	}
+	if oh.CheckSum != Checksum(b) {
+		t.Errorf("checksum %#x does not match %#x", oh.CheckSum, Checksum(b))
+	}
+
+	dir, err := ioutil.TempDir("", "uki")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.efi")
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+	img, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := img.Cmdline(); v != "root=/dev/sda1 quiet" {
+		t.Errorf("unexpected cmdline %q", v)
+	}
+	if v := img.Uname(); v != "6.1.0-test" {
+		t.Errorf("unexpected uname %q", v)
+	}
+	if !bytes.Equal(img.Section(SectionLinux), kernel()) {
+		t.Error("kernel does not round trip")
+	}
+	if v := string(img.Section(SectionInitrd)); v != "initrd" {
+		t.Errorf("unexpected initrd %q", v)
+	}
+	rel, err := img.OSRelease()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel["PRETTY_NAME"] != "Test OS" {
+		t.Errorf("unexpected os-release %v", rel)
+	}
+	if img.HasSection(SectionSplash) {
+		t.Error("image has unexpected splash section")
+	}
+}
+
+func TestAddSectionsErrors(t *testing.T) {
+	s := stub(t)
+	signed := append([]byte(nil), s...)
+	binary.LittleEndian.PutUint32(signed[0x40+4+20+112+4*8:], 0x600)
+	binary.LittleEndian.PutUint32(signed[0x40+4+20+112+4*8+4:], 0x10)
+
+	// Without sections, the copied headers would reach past the end.
+	truncated := append([]byte(nil), s[:0x200]...)
+	binary.LittleEndian.PutUint16(truncated[0x40+4+2:], 0)
+
+	// The end of the section data overflows to 0x100.
+	overflow := append([]byte(nil), s...)
+	binary.LittleEndian.PutUint32(overflow[0x40+4+20+240+20:], 0xffffff00)
+
+	many := make([]Section, 17)
+	for i := range many {
+		many[i] = Section{Name: ".s" + string(rune('a'+i)), Data: []byte("x")}
+	}
+
+	for i, tt := range []struct {
+		image    []byte
+		sections []Section
+	}{
+		{[]byte("not a binary"), nil},
+		{signed, []Section{{Name: ".cmdline"}}},
+		{s, []Section{{Name: ".text"}}},
+		{s, []Section{{Name: ".cmdline"}, {Name: ".cmdline"}}},
+		{s, []Section{{Name: ".toolongname"}}},
+		{s, many},
+		{truncated, []Section{{Name: ".cmdline"}}},
+		{overflow, []Section{{Name: ".cmdline"}}},
+	} {
+		if _, err := AddSections(tt.image, tt.sections); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+
+	// Too short to have a checksum field.
+	Checksum([]byte("MZ"))
+	Checksum(s[:0x50])
+}
@@ -0,0 +1,269 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1" // register the PCR banks
+	"crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// PCRKernelBoot is the PCR systemd-stub measures the sections of a unified
+// kernel image and systemd-pcrphase the boot phases into.
+const PCRKernelBoot = 11
+
+// Boot phases systemd-pcrphase measures into PCRKernelBoot.
+const (
+	PhaseEnterInitrd = "enter-initrd"
+	PhaseLeaveInitrd = "leave-initrd"
+	PhaseSysinit     = "sysinit"
+	PhaseReady       = "ready"
+)
+
+// DefaultPhases are the phase paths systemd-measure signs by default,
+// each naming the phases measured so far separated by colons. A disk
+// unlocked with a signature limited to the first phase can only be
+// unlocked in the initrd.
+var DefaultPhases = []string{
+	PhaseEnterInitrd,
+	PhaseEnterInitrd + ":" + PhaseLeaveInitrd,
+	PhaseEnterInitrd + ":" + PhaseLeaveInitrd + ":" + PhaseSysinit,
+	PhaseEnterInitrd + ":" + PhaseLeaveInitrd + ":" + PhaseSysinit + ":" + PhaseReady,
+}
+
+// measured are the sections systemd-stub measures, in the order it
+// measures them regardless of their order in the image.
+var measured = []string{
+	SectionLinux,
+	SectionOSRelease,
+	SectionCmdline,
+	SectionInitrd,
+	SectionUcode,
+	SectionSplash,
+	SectionDTB,
+	SectionUname,
+	SectionSBAT,
+	SectionPCRPKey,
+}
+
+// banks maps the supported PCR banks to their TPM algorithm IDs and names.
+var banks = map[crypto.Hash]struct {
+	alg  uint16
+	name string
+}{
+	crypto.SHA1:   {0x0004, "sha1"},
+	crypto.SHA256: {0x000b, "sha256"},
+	crypto.SHA384: {0x000c, "sha384"},
+	crypto.SHA512: {0x000d, "sha512"},
+}
+
+// ErrNoMatchingSignature is returned by Signatures.Verify when no entry is
+// signed by the key for the given PCR value.
+var ErrNoMatchingSignature = errors.New("no matching PCR signature")
+
+func hash(h crypto.Hash, data ...[]byte) []byte {
+	w := h.New()
+	for _, d := range data {
+		w.Write(d)
+	}
+	return w.Sum(nil)
+}
+
+// ExtendPCR returns the value of a PCR of the given bank after extending
+// pcr with the measurement of data, as the TPM does.
+func ExtendPCR(bank crypto.Hash, pcr, data []byte) []byte {
+	return hash(bank, pcr, hash(bank, data))
+}
+
+// MeasureSections returns the value of PCRKernelBoot in the given bank
+// after systemd-stub booted the unified kernel image made of sections, as
+// systemd-measure calculates it. Other sections, like those of the stub and
+// .pcrsig, are not measured.
+func MeasureSections(bank crypto.Hash, sections []Section) ([]byte, error) {
+	if _, ok := banks[bank]; !ok {
+		return nil, fmt.Errorf("unsupported PCR bank %v", bank)
+	}
+	pcr := make([]byte, bank.Size())
+	for _, name := range measured {
+		for _, s := range sections {
+			if s.Name != name {
+				continue
+			}
+			pcr = ExtendPCR(bank, pcr, append([]byte(name), 0))
+			pcr = ExtendPCR(bank, pcr, s.Data)
+			break
+		}
+	}
+	return pcr, nil
+}
+
+// MeasurePhases returns pcr after extending it with the phases of the
+// colon separated phase path, as systemd-pcrphase does while booting.
+func MeasurePhases(bank crypto.Hash, pcr []byte, path string) []byte {
+	for _, phase := range strings.Split(path, ":") {
+		if phase != "" {
+			pcr = ExtendPCR(bank, pcr, []byte(phase))
+		}
+	}
+	return pcr
+}
+
+// PolicyPCR returns the SHA-256 digest of a TPM2 policy session that only
+// succeeds if PCRKernelBoot in the given bank has the value pcr, which is
+// what systemd-measure signs.
+func PolicyPCR(bank crypto.Hash, pcr []byte) ([]byte, error) {
+	b, ok := banks[bank]
+	if !ok {
+		return nil, fmt.Errorf("unsupported PCR bank %v", bank)
+	}
+
+	// TPM2_PolicyPCR extends the session digest with the command code,
+	// the TPML_PCR_SELECTION and the digest of the selected PCR values.
+	var sel [3]byte
+	sel[PCRKernelBoot/8] |= 1 << (PCRKernelBoot % 8)
+	buf := make([]byte, 0, 4+4+2+1+len(sel))
+	buf = append(buf, 0x00, 0x00, 0x01, 0x7f) // TPM2_CC_PolicyPCR
+	buf = append(buf, 0, 0, 0, 1)             // count
+	buf = append(buf, byte(b.alg>>8), byte(b.alg))
+	buf = append(buf, byte(len(sel)))
+	buf = append(buf, sel[:]...)
+
+	return hash(crypto.SHA256, make([]byte, sha256.Size), buf, hash(crypto.SHA256, pcr)), nil
+}
+
+// PublicKeyFingerprint returns the fingerprint systemd identifies PCR signing
+// keys by, the SHA-256 digest of the DER encoded public key.
+func PublicKeyFingerprint(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// PolicySignature is a signed policy for a set of PCR values.
+type PolicySignature struct {
+	PCRs                 []int  `json:"pcrs"`
+	PublicKeyFingerprint string `json:"pkfp"`
+	Policy               string `json:"pol"` // Hex encoded policy digest
+	Signature            string `json:"sig"` // Base64 encoded signature of Policy
+}
+
+// Signatures are the PCR signatures of a unified kernel image by bank name,
+// e.g. "sha256", in the JSON format systemd-measure produces, ukify embeds as
+// .pcrsig section and systemd-cryptenroll --tpm2-public-key consumes.
+type Signatures map[string][]PolicySignature
+
+// SignSections signs the expected values of PCRKernelBoot for the image made
+// of sections in all given banks and phase paths, like "systemd-measure
+// sign", using DefaultPhases if phases is empty. RSA keys are signed with
+// PKCS #1 v1.5 as systemd expects, ECDSA keys are supported by newer
+// versions of systemd.
+func SignSections(sections []Section, bankList []crypto.Hash, phases []string, key crypto.Signer) (Signatures, error) {
+	if len(phases) == 0 {
+		phases = DefaultPhases
+	}
+	fp, err := PublicKeyFingerprint(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	sigs := Signatures{}
+	for _, bank := range bankList {
+		base, err := MeasureSections(bank, sections)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range phases {
+			pol, err := PolicyPCR(bank, MeasurePhases(bank, base, path))
+			if err != nil {
+				return nil, err
+			}
+			sig, err := key.Sign(rand.Reader, pol, crypto.SHA256)
+			if err != nil {
+				return nil, err
+			}
+			name := banks[bank].name
+			sigs[name] = append(sigs[name], PolicySignature{
+				PCRs:                 []int{PCRKernelBoot},
+				PublicKeyFingerprint: fp,
+				Policy:               hex.EncodeToString(pol),
+				Signature:            base64.StdEncoding.EncodeToString(sig),
+			})
+		}
+	}
+	return sigs, nil
+}
+
+// Verify checks that s holds a policy for PCRKernelBoot having the value pcr
+// in the given bank, signed by pub.
+func (s Signatures) Verify(pub crypto.PublicKey, bank crypto.Hash, pcr []byte) error {
+	b, ok := banks[bank]
+	if !ok {
+		return fmt.Errorf("unsupported PCR bank %v", bank)
+	}
+	fp, err := PublicKeyFingerprint(pub)
+	if err != nil {
+		return err
+	}
+	want, err := PolicyPCR(bank, pcr)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range s[b.name] {
+		if e.PublicKeyFingerprint != fp || len(e.PCRs) != 1 || e.PCRs[0] != PCRKernelBoot {
+			continue
+		}
+		if pol, err := hex.DecodeString(e.Policy); err != nil || string(pol) != string(want) {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(e.Signature)
+		if err != nil {
+			continue
+		}
+		if verifySignature(pub, want, sig) {
+			return nil
+		}
+	}
+	return ErrNoMatchingSignature
+}
+
+func verifySignature(pub crypto.PublicKey, digest, sig []byte) bool {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		var es struct{ R, S *big.Int }
+		if rest, err := asn1.Unmarshal(sig, &es); err != nil || len(rest) != 0 {
+			return false
+		}
+		return ecdsa.Verify(k, digest, es.R, es.S)
+	}
+	return false
+}
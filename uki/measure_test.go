@@ -0,0 +1,144 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+func TestMeasureSections(t *testing.T) {
+	sections := []Section{
+		{Name: ".text", Data: []byte("stub")},
+		{Name: SectionCmdline, Data: []byte("quiet")},
+		{Name: SectionPCRSig, Data: []byte("{}")},
+		{Name: SectionLinux, Data: []byte("kernel")},
+	}
+
+	// The kernel is measured first, the stub's and the signature sections
+	// not at all.
+	want := make([]byte, sha256.Size)
+	for _, d := range []string{".linux\x00", "kernel", ".cmdline\x00", "quiet"} {
+		h := sha256.Sum256([]byte(d))
+		n := sha256.Sum256(append(want, h[:]...))
+		want = n[:]
+	}
+
+	got, err := MeasureSections(crypto.SHA256, sections)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("expected %x, got %x", want, got)
+	}
+	if _, err := MeasureSections(crypto.MD5, sections); err == nil {
+		t.Error("expected error for unsupported bank")
+	}
+
+	p := MeasurePhases(crypto.SHA256, got, "enter-initrd:leave-initrd")
+	if e := ExtendPCR(crypto.SHA256, ExtendPCR(crypto.SHA256, got, []byte("enter-initrd")), []byte("leave-initrd")); !bytes.Equal(p, e) {
+		t.Errorf("expected %x, got %x", e, p)
+	}
+}
+
+func TestPolicyPCR(t *testing.T) {
+	// A PolicyPCR for PCR 11 in the SHA-256 bank being all zeros.
+	pcr := make([]byte, sha256.Size)
+	pcrDigest := sha256.Sum256(pcr)
+	buf := append(make([]byte, 32), 0, 0, 1, 0x7f, 0, 0, 0, 1, 0, 0x0b, 3, 0, 8, 0)
+	want := sha256.Sum256(append(buf, pcrDigest[:]...))
+
+	got, err := PolicyPCR(crypto.SHA256, pcr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want[:]) {
+		t.Errorf("expected %x, got %x", want, got)
+	}
+}
+
+func TestSignSections(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sections := []Section{
+		{Name: SectionLinux, Data: []byte("kernel")},
+		{Name: SectionOSRelease, Data: []byte("ID=test\n")},
+	}
+	for _, key := range []crypto.Signer{rsaKey, ecKey} {
+		sigs, err := SignSections(sections, []crypto.Hash{crypto.SHA1, crypto.SHA256}, nil, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(sigs["sha1"]) != len(DefaultPhases) || len(sigs["sha256"]) != len(DefaultPhases) {
+			t.Fatalf("unexpected signatures %v", sigs)
+		}
+
+		// Round trip through the JSON systemd-cryptenroll reads.
+		b, err := json.Marshal(sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded Signatures
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			t.Fatal(err)
+		}
+
+		base, err := MeasureSections(crypto.SHA256, sections)
+		if err != nil {
+			t.Fatal(err)
+		}
+		initrd := MeasurePhases(crypto.SHA256, base, PhaseEnterInitrd)
+		if err := decoded.Verify(key.Public(), crypto.SHA256, initrd); err != nil {
+			t.Errorf("%T: %v", key, err)
+		}
+		if err := decoded.Verify(key.Public(), crypto.SHA256, base); err != ErrNoMatchingSignature {
+			t.Errorf("%T: expected no signature before the initrd, got %v", key, err)
+		}
+		if err := decoded.Verify(otherKey.Public(), crypto.SHA256, initrd); err != ErrNoMatchingSignature {
+			t.Errorf("%T: expected no signature for other key, got %v", key, err)
+		}
+
+		// A policy signed with the key's fingerprint but a bad signature
+		// does not verify.
+		decoded["sha256"][0].Signature = decoded["sha256"][1].Signature
+		if err := decoded.Verify(key.Public(), crypto.SHA256, initrd); err != ErrNoMatchingSignature {
+			t.Errorf("%T: expected bad signature to fail, got %v", key, err)
+		}
+
+		fp, _ := PublicKeyFingerprint(key.Public())
+		if _, err := hex.DecodeString(fp); err != nil || len(fp) != 64 {
+			t.Errorf("unexpected fingerprint %q", fp)
+		}
+	}
+}
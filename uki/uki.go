@@ -0,0 +1,159 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package uki inspects and assembles Unified Kernel Images, PE binaries made
+// of the systemd-stub UEFI stub with the kernel, initrd, command line and
+// other resources in additional sections, like ukify does.  See
+// https://uapi-group.org/specifications/specs/unified_kernel_image/
+package uki
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/coreos/go-systemd/v22/sysext"
+)
+
+// Sections of a unified kernel image.
+const (
+	SectionLinux     = ".linux"
+	SectionInitrd    = ".initrd"
+	SectionUcode     = ".ucode"
+	SectionCmdline   = ".cmdline"
+	SectionOSRelease = ".osrel"
+	SectionUname     = ".uname"
+	SectionSBAT      = ".sbat"
+	SectionSplash    = ".splash"
+	SectionDTB       = ".dtb"
+	SectionPCRSig    = ".pcrsig"
+	SectionPCRPKey   = ".pcrpkey"
+)
+
+// Section is a section of a PE binary.
+type Section struct {
+	Name string
+	Data []byte
+}
+
+// Image is a unified kernel image, or any other PE binary such as an addon.
+type Image struct {
+	// Sections are the sections of the image in file order, including
+	// those of the stub.
+	Sections []Section
+}
+
+// Open reads the unified kernel image at path.
+func Open(path string) (*Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read reads a unified kernel image from r.
+func Read(r io.ReaderAt) (*Image, error) {
+	f, err := pe.NewFile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	img := &Image{}
+	for _, s := range f.Sections {
+		b, err := s.Data()
+		if err != nil {
+			return nil, err
+		}
+		// The raw data is padded to the file alignment.
+		if s.VirtualSize != 0 && int(s.VirtualSize) < len(b) {
+			b = b[:s.VirtualSize]
+		}
+		img.Sections = append(img.Sections, Section{Name: s.Name, Data: b})
+	}
+	return img, nil
+}
+
+// Section returns the contents of the first section called name, or nil if
+// there is none.
+func (i *Image) Section(name string) []byte {
+	for _, s := range i.Sections {
+		if s.Name == name {
+			return s.Data
+		}
+	}
+	return nil
+}
+
+// HasSection returns whether the image has a section called name.
+func (i *Image) HasSection(name string) bool {
+	for _, s := range i.Sections {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// text returns a text section without the trailing NUL bytes and whitespace
+// some tools add.
+func (i *Image) text(name string) string {
+	return strings.TrimRight(string(i.Section(name)), "\x00 \t\r\n")
+}
+
+// Cmdline returns the embedded kernel command line.
+func (i *Image) Cmdline() string {
+	return strings.TrimSpace(i.text(SectionCmdline))
+}
+
+// Uname returns the kernel release of the embedded kernel, as "uname -r"
+// shows it.
+func (i *Image) Uname() string {
+	return i.text(SectionUname)
+}
+
+// OSRelease returns the fields of the embedded os-release file, which the
+// boot loader uses to show the image in its menu.
+func (i *Image) OSRelease() (map[string]string, error) {
+	return sysext.ParseRelease(bytes.NewReader(i.Section(SectionOSRelease)))
+}
+
+// SBAT returns the embedded SBAT metadata, a CSV list of components and
+// their generations used for revocation by shim.
+func (i *Image) SBAT() string {
+	return i.text(SectionSBAT)
+}
+
+// KernelVersion returns the version string embedded in the header of the
+// x86 Linux kernel image linux, or an empty string if it is not an x86
+// kernel image. The first word of the result is the kernel release.
+func KernelVersion(linux []byte) string {
+	const setupBase = 0x200
+	if len(linux) < 0x210 || string(linux[0x202:0x206]) != "HdrS" {
+		return ""
+	}
+	off := setupBase + int(binary.LittleEndian.Uint16(linux[0x20e:]))
+	if off >= len(linux) {
+		return ""
+	}
+	s := linux[off:]
+	if i := bytes.IndexByte(s, 0); i >= 0 {
+		s = s[:i]
+	}
+	return string(s)
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package uki
+
+import "testing"
+
+func TestKernelVersion(t *testing.T) {
+	if v := KernelVersion(kernel()); v != "6.1.0-test (builder@example) #1 SMP" {
+		t.Errorf("unexpected version %q", v)
+	}
+	if v := KernelVersion([]byte("not a kernel")); v != "" {
+		t.Errorf("unexpected version %q for garbage", v)
+	}
+}
@@ -0,0 +1,835 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	usecPerSec = 1000000
+	// calendarMaxYear is the last year systemd considers when looking for
+	// the next elapse of a calendar spec.
+	calendarMaxYear = 2199
+)
+
+var (
+	// ErrNoNextElapse is returned by CalendarSpec.Next if the spec does not
+	// elapse again.
+	ErrNoNextElapse = errors.New("calendar spec does not elapse again")
+
+	errInvalidCalendar = errors.New("invalid calendar spec")
+)
+
+var calendarShorthands = map[string]string{
+	"minutely":      "*-*-* *:*:00",
+	"hourly":        "*-*-* *:00:00",
+	"daily":         "*-*-* 00:00:00",
+	"monthly":       "*-*-01 00:00:00",
+	"weekly":        "Mon *-*-* 00:00:00",
+	"yearly":        "*-01-01 00:00:00",
+	"annually":      "*-01-01 00:00:00",
+	"quarterly":     "*-01,04,07,10-01 00:00:00",
+	"semiannually":  "*-01,07-01 00:00:00",
+	"semi-annually": "*-01,07-01 00:00:00",
+}
+
+var weekdayNames = []struct {
+	name string
+	nr   uint
+}{
+	{"Monday", 0}, {"Mon", 0},
+	{"Tuesday", 1}, {"Tue", 1},
+	{"Wednesday", 2}, {"Wed", 2},
+	{"Thursday", 3}, {"Thu", 3},
+	{"Friday", 4}, {"Fri", 4},
+	{"Saturday", 5}, {"Sat", 5},
+	{"Sunday", 6}, {"Sun", 6},
+}
+
+const allWeekdays = 1<<7 - 1
+
+// calendarComponent matches start, and if repeat is set every repeat after
+// it, up to stop if set.
+type calendarComponent struct {
+	start  int
+	stop   int // -1 if unbounded
+	repeat int // 0 if not repeating
+}
+
+// CalendarSpec is a parsed calendar event expression as used by OnCalendar=
+// in timer units, e.g. "Mon..Fri *-*-* 02:00/30:00".
+type CalendarSpec struct {
+	weekdays   uint8 // Bit 0 is Monday, 0 matches any day
+	endOfMonth bool  // The day counts from the end of the month
+	utc        bool
+	timezone   string
+	location   *time.Location
+
+	// The components are nil if any value matches. Seconds are stored as
+	// microseconds.
+	year, month, day, hour, minute, microsecond []calendarComponent
+}
+
+// ParseCalendar parses a calendar event expression as systemd does, see
+// systemd.time(7).
+func ParseCalendar(spec string) (*CalendarSpec, error) {
+	c, err := parseCalendar(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%v %q", err, spec)
+	}
+	return c, nil
+}
+
+func parseCalendar(spec string) (*CalendarSpec, error) {
+	c := &CalendarSpec{}
+	p := strings.TrimSpace(spec)
+
+	if len(p) > 4 && strings.EqualFold(p[len(p)-4:], " UTC") {
+		c.utc = true
+		c.location = time.UTC
+		p = strings.TrimRight(p[:len(p)-4], " ")
+	} else if i := strings.LastIndexByte(p, ' '); i >= 0 && isTimezoneName(p[i+1:]) {
+		loc, err := time.LoadLocation(p[i+1:])
+		if err == nil {
+			c.timezone = p[i+1:]
+			c.location = loc
+			p = strings.TrimRight(p[:i], " ")
+		}
+	}
+	if p == "" {
+		return nil, errInvalidCalendar
+	}
+
+	if shorthand, ok := calendarShorthands[strings.ToLower(p)]; ok {
+		p = shorthand
+	}
+
+	var err error
+	if p, err = c.parseWeekdays(p); err != nil {
+		return nil, err
+	}
+	if p, err = c.parseDate(p); err != nil {
+		return nil, err
+	}
+	if err = c.parseTime(p); err != nil {
+		return nil, err
+	}
+
+	c.normalize()
+	if !c.valid() {
+		return nil, errInvalidCalendar
+	}
+	return c, nil
+}
+
+// isTimezoneName returns whether s looks like a time zone like Europe/Berlin.
+func isTimezoneName(s string) bool {
+	if s == "" || s == "Local" || s[0] < 'A' || s[0] > 'Z' {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("/_-+", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *CalendarSpec) parseWeekdays(p string) (string, error) {
+	l := -1
+	first := true
+	for {
+		found := false
+		var nr uint
+		for _, d := range weekdayNames {
+			if len(p) < len(d.name) || !strings.EqualFold(p[:len(d.name)], d.name) {
+				continue
+			}
+			skip := len(d.name)
+			if skip < len(p) && !strings.ContainsRune("-., ", rune(p[skip])) {
+				return "", errInvalidCalendar
+			}
+			nr = d.nr
+			c.weekdays |= 1 << nr
+			if l >= 0 {
+				if uint(l) > nr {
+					return "", errInvalidCalendar
+				}
+				for j := uint(l) + 1; j < nr; j++ {
+					c.weekdays |= 1 << j
+				}
+			}
+			p = p[skip:]
+			found = true
+			break
+		}
+
+		// Without a weekday prefix, continue with the date.
+		if !found {
+			if first {
+				return p, nil
+			}
+			return "", errInvalidCalendar
+		}
+
+		if p == "" {
+			return p, nil
+		}
+		switch p[0] {
+		case ' ':
+			return strings.TrimLeft(p, " "), nil
+		case '.':
+			if l >= 0 || len(p) < 2 || p[1] != '.' {
+				return "", errInvalidCalendar
+			}
+			l = int(nr)
+			p = p[2:]
+		case '-':
+			// Ranges with a dash are supported for backwards compatibility.
+			if l >= 0 {
+				return "", errInvalidCalendar
+			}
+			l = int(nr)
+			p = p[1:]
+		case ',':
+			l = -1
+			p = p[1:]
+		}
+
+		// A trailing comma is allowed, an open range is not.
+		if p == "" || p[0] == ' ' {
+			if l >= 0 {
+				return "", errInvalidCalendar
+			}
+			return strings.TrimLeft(p, " "), nil
+		}
+		first = false
+	}
+}
+
+func (c *CalendarSpec) parseDate(p string) (string, error) {
+	if p == "" {
+		return p, nil
+	}
+
+	first, t, err := parseCalendarChain(p, false)
+	if err != nil {
+		return "", err
+	}
+	// A colon means this was a time, not a date.
+	if t == "" || t[0] == ':' {
+		return p, nil
+	}
+	if t[0] == '~' {
+		c.endOfMonth = true
+	} else if t[0] != '-' {
+		return "", errInvalidCalendar
+	}
+
+	second, t, err := parseCalendarChain(t[1:], false)
+	if err != nil {
+		return "", err
+	}
+	// Two parts are month and day.
+	if t == "" || t[0] == ' ' {
+		c.month, c.day = first, second
+		return strings.TrimLeft(t, " "), nil
+	}
+	if c.endOfMonth {
+		return "", errInvalidCalendar
+	}
+	if t[0] == '~' {
+		c.endOfMonth = true
+	} else if t[0] != '-' {
+		return "", errInvalidCalendar
+	}
+
+	third, t, err := parseCalendarChain(t[1:], false)
+	if err != nil {
+		return "", err
+	}
+	if t != "" && t[0] != ' ' {
+		return "", errInvalidCalendar
+	}
+	c.year, c.month, c.day = first, second, third
+	return strings.TrimLeft(t, " "), nil
+}
+
+func (c *CalendarSpec) parseTime(p string) error {
+	zero := []calendarComponent{{start: 0, stop: -1}}
+
+	// Without a time, the spec elapses at midnight.
+	if p == "" {
+		c.hour, c.minute, c.microsecond = zero, zero, zero
+		return nil
+	}
+
+	hour, t, err := parseCalendarChain(p, false)
+	if err != nil {
+		return err
+	}
+	if t == "" || t[0] != ':' {
+		return errInvalidCalendar
+	}
+	minute, t, err := parseCalendarChain(t[1:], false)
+	if err != nil {
+		return err
+	}
+	sec := zero
+	if t != "" {
+		if t[0] != ':' {
+			return errInvalidCalendar
+		}
+		sec, t, err = parseCalendarChain(t[1:], true)
+		if err != nil {
+			return err
+		}
+		if t != "" {
+			return errInvalidCalendar
+		}
+	}
+
+	c.hour, c.minute, c.microsecond = hour, minute, sec
+	return nil
+}
+
+// parseCalendarChain parses a comma separated list of components. A
+// wildcard results in a nil list, except for seconds where it matches every
+// full second.
+func parseCalendarChain(p string, usec bool) ([]calendarComponent, string, error) {
+	if strings.HasPrefix(p, "*") {
+		if usec {
+			return []calendarComponent{{start: 0, stop: -1, repeat: usecPerSec}}, p[1:], nil
+		}
+		return nil, p[1:], nil
+	}
+
+	var chain []calendarComponent
+	for {
+		cc, t, err := parseCalendarComponent(p, usec)
+		if err != nil {
+			return nil, "", err
+		}
+		chain = append(chain, cc)
+		if t == "" || t[0] != ',' {
+			return chain, t, nil
+		}
+		p = t[1:]
+	}
+}
+
+func parseCalendarComponent(p string, usec bool) (calendarComponent, string, error) {
+	cc := calendarComponent{stop: -1}
+
+	var err error
+	if cc.start, p, err = parseCalendarDecimal(p, usec); err != nil {
+		return cc, "", err
+	}
+	if strings.HasPrefix(p, "..") {
+		if cc.stop, p, err = parseCalendarDecimal(p[2:], usec); err != nil {
+			return cc, "", err
+		}
+		cc.repeat = 1
+		if usec {
+			cc.repeat = usecPerSec
+		}
+	}
+	if strings.HasPrefix(p, "/") {
+		if cc.repeat, p, err = parseCalendarDecimal(p[1:], usec); err != nil {
+			return cc, "", err
+		}
+		if cc.repeat == 0 {
+			return cc, "", errInvalidCalendar
+		}
+	}
+
+	if p != "" && !strings.ContainsRune(" ,-~:", rune(p[0])) {
+		return cc, "", errInvalidCalendar
+	}
+	return cc, p, nil
+}
+
+// parseCalendarDecimal parses a number, which for seconds may have a
+// fractional part and is returned in microseconds.
+func parseCalendarDecimal(p string, usec bool) (int, string, error) {
+	i := 0
+	for i < len(p) && p[i] >= '0' && p[i] <= '9' {
+		i++
+	}
+	if i == 0 || i > 9 {
+		return 0, "", errInvalidCalendar
+	}
+	value, err := strconv.Atoi(p[:i])
+	if err != nil {
+		return 0, "", errInvalidCalendar
+	}
+	p = p[i:]
+	if !usec {
+		return value, p, nil
+	}
+
+	value *= usecPerSec
+	if len(p) > 1 && p[0] == '.' && p[1] >= '0' && p[1] <= '9' {
+		p = p[1:]
+		scale := usecPerSec / 10
+		for len(p) > 0 && p[0] >= '0' && p[0] <= '9' {
+			value += int(p[0]-'0') * scale
+			scale /= 10
+			p = p[1:]
+		}
+	}
+	return value, p, nil
+}
+
+func (c *CalendarSpec) normalize() {
+	if c.weekdays == allWeekdays {
+		c.weekdays = 0
+	}
+	if c.endOfMonth && c.day == nil {
+		c.endOfMonth = false
+	}
+
+	// Turn two digit years into 19xx and 20xx.
+	for i := range c.year {
+		y := &c.year[i]
+		for _, v := range []*int{&y.start, &y.stop} {
+			if *v >= 0 && *v < 70 {
+				*v += 2000
+			} else if *v >= 70 && *v < 100 {
+				*v += 1900
+			}
+		}
+	}
+
+	for _, chain := range []*[]calendarComponent{&c.year, &c.month, &c.day, &c.hour, &c.minute, &c.microsecond} {
+		*chain = normalizeCalendarChain(*chain)
+	}
+}
+
+func normalizeCalendarChain(chain []calendarComponent) []calendarComponent {
+	if chain == nil {
+		return nil
+	}
+	sort.Slice(chain, func(i, j int) bool {
+		a, b := chain[i], chain[j]
+		if a.start != b.start {
+			return a.start < b.start
+		}
+		if a.stop != b.stop {
+			return a.stop < b.stop
+		}
+		return a.repeat < b.repeat
+	})
+	out := chain[:1]
+	for _, cc := range chain[1:] {
+		if cc != out[len(out)-1] {
+			out = append(out, cc)
+		}
+	}
+	return out
+}
+
+func (c *CalendarSpec) valid() bool {
+	return calendarChainValid(c.year, 1970, calendarMaxYear, false) &&
+		calendarChainValid(c.month, 1, 12, false) &&
+		calendarChainValid(c.day, 1, 31, c.endOfMonth) &&
+		calendarChainValid(c.hour, 0, 23, false) &&
+		calendarChainValid(c.minute, 0, 59, false) &&
+		calendarChainValid(c.microsecond, 0, 60*usecPerSec-1, false)
+}
+
+func calendarChainValid(chain []calendarComponent, from, to int, endOfMonth bool) bool {
+	// Days more than 28 days from the end of the month are not allowed.
+	if endOfMonth {
+		to -= 3
+	}
+	for _, cc := range chain {
+		if cc.start < from || cc.start > to {
+			return false
+		}
+		if cc.repeat > to-from {
+			return false
+		}
+		// Like systemd, reject repetitions which leave the range after
+		// the first match.
+		if cc.repeat > 0 {
+			if endOfMonth && cc.start-cc.repeat < from {
+				return false
+			}
+			if !endOfMonth && cc.start+cc.repeat > to {
+				return false
+			}
+		}
+		if cc.stop >= 0 && (cc.stop < from || cc.stop > to || cc.stop < cc.start) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the normalized form of the spec, as printed by
+// `systemd-analyze calendar`.
+func (c *CalendarSpec) String() string {
+	var b strings.Builder
+
+	if c.weekdays != 0 {
+		c.formatWeekdays(&b)
+		b.WriteByte(' ')
+	}
+
+	formatCalendarChain(&b, 4, c.year, false)
+	b.WriteByte('-')
+	formatCalendarChain(&b, 2, c.month, false)
+	if c.endOfMonth {
+		b.WriteByte('~')
+	} else {
+		b.WriteByte('-')
+	}
+	formatCalendarChain(&b, 2, c.day, false)
+	b.WriteByte(' ')
+	formatCalendarChain(&b, 2, c.hour, false)
+	b.WriteByte(':')
+	formatCalendarChain(&b, 2, c.minute, false)
+	b.WriteByte(':')
+	formatCalendarChain(&b, 2, c.microsecond, true)
+
+	if c.utc {
+		b.WriteString(" UTC")
+	} else if c.timezone != "" {
+		b.WriteByte(' ')
+		b.WriteString(c.timezone)
+	}
+
+	return b.String()
+}
+
+func (c *CalendarSpec) formatWeekdays(b *strings.Builder) {
+	days := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+	needComma := false
+	l := -1
+	x := 0
+	for ; x < len(days); x++ {
+		if c.weekdays&(1<<uint(x)) != 0 {
+			if l < 0 {
+				if needComma {
+					b.WriteByte(',')
+				}
+				needComma = true
+				b.WriteString(days[x])
+				l = x
+			}
+		} else if l >= 0 {
+			if x > l+1 {
+				if x > l+2 {
+					b.WriteString("..")
+				} else {
+					b.WriteByte(',')
+				}
+				b.WriteString(days[x-1])
+			}
+			l = -1
+		}
+	}
+	if l >= 0 && x > l+1 {
+		if x > l+2 {
+			b.WriteString("..")
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(days[x-1])
+	}
+}
+
+func formatCalendarValue(b *strings.Builder, width, v int, usec bool) {
+	if !usec {
+		fmt.Fprintf(b, "%0*d", width, v)
+		return
+	}
+	fmt.Fprintf(b, "%0*d", width, v/usecPerSec)
+	if v%usecPerSec != 0 {
+		fmt.Fprintf(b, ".%06d", v%usecPerSec)
+	}
+}
+
+func formatCalendarChain(b *strings.Builder, width int, chain []calendarComponent, usec bool) {
+	if chain == nil {
+		b.WriteByte('*')
+		return
+	}
+	d := 1
+	if usec {
+		d = usecPerSec
+		if len(chain) == 1 && chain[0] == (calendarComponent{start: 0, stop: -1, repeat: usecPerSec}) {
+			b.WriteByte('*')
+			return
+		}
+	}
+
+	for i, cc := range chain {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		formatCalendarValue(b, width, cc.start, usec)
+		if cc.stop > 0 {
+			b.WriteString("..")
+			formatCalendarValue(b, width, cc.stop, usec)
+		}
+		if cc.repeat > 0 && !(cc.stop > 0 && cc.repeat == d) {
+			b.WriteByte('/')
+			formatCalendarValue(b, 0, cc.repeat, usec)
+		}
+	}
+}
+
+// calendarTime is a broken down time like struct tm, which may hold values
+// out of range until normalized.
+type calendarTime struct {
+	year, month, day, hour, minute, second int
+}
+
+func (t calendarTime) time(loc *time.Location, usec int) time.Time {
+	return time.Date(t.year, time.Month(t.month), t.day, t.hour, t.minute, t.second, usec*1000, loc)
+}
+
+func calendarTimeOf(t time.Time) calendarTime {
+	return calendarTime{t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second()}
+}
+
+func compareInts(a, b int) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+// withinBounds normalizes t. It returns 1 if t was already normalized, 0 if
+// it was not and t was updated, and -1 if t is beyond the maximum year or
+// normalization would go backwards.
+func (t *calendarTime) withinBounds(loc *time.Location) int {
+	if t.year > calendarMaxYear {
+		return -1
+	}
+
+	n := calendarTimeOf(t.time(loc, 0))
+
+	// Normalization may skip the next elapse, e.g. 3-33 becomes 4-2 and
+	// skips 4-1, so reset the unit below the one that changed.
+	var cmp int
+	if cmp = compareInts(n.year, t.year); cmp != 0 {
+		n.month = 1
+	} else if cmp = compareInts(n.month, t.month); cmp != 0 {
+		n.day = 1
+	} else if cmp = compareInts(n.day, t.day); cmp != 0 {
+		n.hour = 0
+	} else if cmp = compareInts(n.hour, t.hour); cmp != 0 {
+		n.minute = 0
+	} else if cmp = compareInts(n.minute, t.minute); cmp != 0 {
+		n.second = 0
+	} else {
+		cmp = compareInts(n.second, t.second)
+	}
+
+	if cmp < 0 {
+		return -1
+	}
+	if cmp > 0 {
+		*t = n
+		return 0
+	}
+	return 1
+}
+
+// endOfMonth returns the day of the month day days before its end, or -1 if
+// there is no such day.
+func endOfMonth(t calendarTime, loc *time.Location, day int) int {
+	n := time.Date(t.year, time.Month(t.month+1), 1-day, 0, 0, 0, 0, loc)
+	if int(n.Month()) != t.month {
+		return -1
+	}
+	return n.Day()
+}
+
+// findMatching sets val to the first value not before it matching the chain.
+// It returns 0 if val already matched, 1 if it was advanced and -1 if no
+// value matches.
+func (c *CalendarSpec) findMatching(chain []calendarComponent, isDay bool, t calendarTime, loc *time.Location, val *int) int {
+	if chain == nil {
+		return 0
+	}
+
+	d := -1
+	for _, cc := range chain {
+		start, stop := cc.start, cc.stop
+		if c.endOfMonth && isDay {
+			start = endOfMonth(t, loc, start)
+			stop = endOfMonth(t, loc, stop)
+			if stop > 0 {
+				start, stop = stop, start
+			}
+		}
+
+		if start >= *val {
+			if d < 0 || start < d {
+				d = start
+			}
+		} else if cc.repeat > 0 {
+			k := start + cc.repeat*((*val-start+cc.repeat-1)/cc.repeat)
+			if (d < 0 || k < d) && (stop < 0 || k <= stop) {
+				d = k
+			}
+		}
+	}
+
+	if d < 0 {
+		return -1
+	}
+	r := 0
+	if *val != d {
+		r = 1
+	}
+	*val = d
+	return r
+}
+
+func (c *CalendarSpec) matchesWeekday(t calendarTime, loc *time.Location) bool {
+	if c.weekdays == 0 {
+		return true
+	}
+	wd := (int(t.time(loc, 0).Weekday()) + 6) % 7
+	return c.weekdays&(1<<uint(wd)) != 0
+}
+
+func (c *CalendarSpec) loc() *time.Location {
+	if c.location != nil {
+		return c.location
+	}
+	return time.Local
+}
+
+// Next returns the first time after the given one at which the spec
+// elapses, in the time zone of the spec or the local one. ErrNoNextElapse is
+// returned if there is none.
+func (c *CalendarSpec) Next(after time.Time) (time.Time, error) {
+	loc := c.loc()
+	start := after.In(loc).Truncate(time.Microsecond).Add(time.Microsecond)
+
+	t := calendarTimeOf(start)
+	usec := start.Nanosecond() / 1000
+
+	for {
+		// Normalize the current date.
+		t = calendarTimeOf(t.time(loc, 0))
+
+		r := c.findMatching(c.year, false, t, loc, &t.year)
+		if r > 0 {
+			t.month, t.day, t.hour, t.minute, t.second, usec = 1, 1, 0, 0, 0, 0
+		}
+		if r < 0 || t.withinBounds(loc) <= 0 {
+			return time.Time{}, ErrNoNextElapse
+		}
+
+		r = c.findMatching(c.month, false, t, loc, &t.month)
+		if r > 0 {
+			t.day, t.hour, t.minute, t.second, usec = 1, 0, 0, 0, 0
+		}
+		if r < 0 {
+			t.year, t.month, t.day, t.hour, t.minute, t.second, usec = t.year+1, 1, 1, 0, 0, 0, 0
+			continue
+		}
+		if r = t.withinBounds(loc); r < 0 {
+			t.year, t.month, t.day, t.hour, t.minute, t.second, usec = t.year+1, 1, 1, 0, 0, 0, 0
+			continue
+		} else if r == 0 {
+			continue
+		}
+
+		r = c.findMatching(c.day, true, t, loc, &t.day)
+		if r > 0 {
+			t.hour, t.minute, t.second, usec = 0, 0, 0, 0
+		}
+		if r < 0 {
+			t.month, t.day, t.hour, t.minute, t.second, usec = t.month+1, 1, 0, 0, 0, 0
+			continue
+		}
+		if r = t.withinBounds(loc); r < 0 {
+			t.month, t.day, t.hour, t.minute, t.second, usec = t.month+1, 1, 0, 0, 0, 0
+			continue
+		} else if r == 0 {
+			continue
+		}
+
+		if !c.matchesWeekday(t, loc) {
+			t.day, t.hour, t.minute, t.second, usec = t.day+1, 0, 0, 0, 0
+			continue
+		}
+
+		r = c.findMatching(c.hour, false, t, loc, &t.hour)
+		if r > 0 {
+			t.minute, t.second, usec = 0, 0, 0
+		}
+		if r < 0 {
+			t.day, t.hour, t.minute, t.second, usec = t.day+1, 0, 0, 0, 0
+			continue
+		}
+		if r = t.withinBounds(loc); r < 0 {
+			t.day, t.hour, t.minute, t.second, usec = t.day+1, 0, 0, 0, 0
+			continue
+		} else if r == 0 {
+			// The hour may not exist because of a time zone change,
+			// so try again with the normalized time.
+			continue
+		}
+
+		r = c.findMatching(c.minute, false, t, loc, &t.minute)
+		if r > 0 {
+			t.second, usec = 0, 0
+		}
+		if r < 0 {
+			t.hour, t.minute, t.second, usec = t.hour+1, 0, 0, 0
+			continue
+		}
+		if r = t.withinBounds(loc); r < 0 {
+			t.hour, t.minute, t.second, usec = t.hour+1, 0, 0, 0
+			continue
+		} else if r == 0 {
+			continue
+		}
+
+		sec := t.second*usecPerSec + usec
+		r = c.findMatching(c.microsecond, false, t, loc, &sec)
+		t.second, usec = sec/usecPerSec, sec%usecPerSec
+		if r < 0 {
+			t.minute, t.second, usec = t.minute+1, 0, 0
+			continue
+		}
+		if r = t.withinBounds(loc); r < 0 {
+			t.minute, t.second, usec = t.minute+1, 0, 0
+			continue
+		} else if r == 0 {
+			continue
+		}
+
+		return t.time(loc, usec), nil
+	}
+}
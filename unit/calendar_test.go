@@ -0,0 +1,199 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCalendar(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"Sat,Thu,Mon-Wed,Sat-Sun", "Mon..Thu,Sat,Sun *-*-* 00:00:00"},
+		{"Sat,Thu,Mon..Wed,Sat..Sun", "Mon..Thu,Sat,Sun *-*-* 00:00:00"},
+		{"Mon,Sun 12-*-* 2,1:23", "Mon,Sun 2012-*-* 01,02:23:00"},
+		{"Wed *-1", "Wed *-*-01 00:00:00"},
+		{"Wed-Wed,Wed *-1", "Wed *-*-01 00:00:00"},
+		{"Wed..Wed,Wed *-1", "Wed *-*-01 00:00:00"},
+		{"Wed, 17:48", "Wed *-*-* 17:48:00"},
+		{"Wed..Sat,Tue 12-10-15 1:2:3", "Tue..Sat 2012-10-15 01:02:03"},
+		{"*-*-7 0:0:0", "*-*-07 00:00:00"},
+		{"10-15", "*-10-15 00:00:00"},
+		{"monday *-12-* 17:00", "Mon *-12-* 17:00:00"},
+		{"Mon,Fri *-*-3,1,2 *:30:45", "Mon,Fri *-*-01,02,03 *:30:45"},
+		{"12,14,13,12:20,10,30", "*-*-* 12,13,14:10,20,30:00"},
+		{"mon,fri *-1/2-1,3 *:30:45", "Mon,Fri *-01/2-01,03 *:30:45"},
+		{"03-05 08:05:40", "*-03-05 08:05:40"},
+		{"08:05:40", "*-*-* 08:05:40"},
+		{"05:40", "*-*-* 05:40:00"},
+		{"Sat,Sun 12-05 08:05:40", "Sat,Sun *-12-05 08:05:40"},
+		{"Sat,Sun 08:05:40", "Sat,Sun *-*-* 08:05:40"},
+		{"2003-03-05 05:40", "2003-03-05 05:40:00"},
+		{"2003-02..04-05", "2003-02..04-05 00:00:00"},
+		{"2003-03-05 05:40 UTC", "2003-03-05 05:40:00 UTC"},
+		{"2015-10-25 01:00:00 uTc", "2015-10-25 01:00:00 UTC"},
+		{"2003-03-05", "2003-03-05 00:00:00"},
+		{"Mon..Fri *-*-* 02:00/30:00", "Mon..Fri *-*-* 02:00/30:00"},
+		{"Mon..Sun", "*-*-* 00:00:00"},
+		{"hourly", "*-*-* *:00:00"},
+		{"daily", "*-*-* 00:00:00"},
+		{"monthly", "*-*-01 00:00:00"},
+		{"weekly", "Mon *-*-* 00:00:00"},
+		{"minutely", "*-*-* *:*:00"},
+		{"quarterly", "*-01,04,07,10-01 00:00:00"},
+		{"semi-annually", "*-01,07-01 00:00:00"},
+		{"annually", "*-01-01 00:00:00"},
+		{"daily UTC", "*-*-* 00:00:00 UTC"},
+		{"*:2/3", "*-*-* *:02/3:00"},
+		{"*:*:0/15", "*-*-* *:*:00/15"},
+		{"*:4,30:0..3", "*-*-* *:04,30:00..03"},
+		{"*:4,30:0/1", "*-*-* *:04,30:*"},
+		{"*:*:1.5", "*-*-* *:*:01.500000"},
+		{"*-*~1 Utc", "*-*~01 00:00:00 UTC"},
+		{"*-*~05,3 ", "*-*~03,05 00:00:00"},
+		{"*-*~* 00:00:00", "*-*-* 00:00:00"},
+		{"Monday..Friday *-*-* 00:00:00", "Mon..Fri *-*-* 00:00:00"},
+	}
+
+	for i, tt := range tests {
+		c, err := ParseCalendar(tt.in)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if s := c.String(); s != tt.out {
+			t.Errorf("case %d: expected %q for %q, got %q", i, tt.out, tt.in, s)
+		}
+
+		// The normalized form parses to itself.
+		c, err = ParseCalendar(tt.out)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+		} else if s := c.String(); s != tt.out {
+			t.Errorf("case %d: expected %q for %q, got %q", i, tt.out, tt.out, s)
+		}
+	}
+}
+
+func TestParseCalendarFail(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"foo",
+		"Mon..",
+		"Fri..Mon",
+		"Mon..Wed..Fri",
+		"2016-13-01",
+		"*-*-32",
+		"*-*~29",
+		"24:00",
+		"00:60",
+		"00:00:60",
+		"1969-01-01",
+		"2200-01-01",
+		"*:*/0",
+		"*-*-* *:*:* *",
+		"00:00 garbage",
+		"*-02~01-03",
+		"*-*-28/4",
+		"*:1/59",
+		"*-*-29/3",
+		"*-*~3/3",
+		"Fri *-*~7/7",
+	} {
+		if _, err := ParseCalendar(in); err == nil {
+			t.Errorf("expected error parsing %q", in)
+		}
+	}
+}
+
+func TestCalendarNext(t *testing.T) {
+	tests := []struct {
+		spec  string
+		after string
+		next  string
+	}{
+		{"*-*-* 02:00/30:00 UTC", "2026-10-14T02:10:00Z", "2026-10-14T02:30:00Z"},
+		{"*-*-* 02:00/30:00 UTC", "2026-10-14T02:30:00Z", "2026-10-15T02:00:00Z"},
+		{"Mon..Fri *-*-* 09:00 UTC", "2026-10-16T10:00:00Z", "2026-10-19T09:00:00Z"},
+		{"*-02~01 UTC", "2024-01-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"*-02~01 UTC", "2024-03-01T00:00:00Z", "2025-02-28T00:00:00Z"},
+		{"*-*~03 UTC", "2026-04-01T00:00:00Z", "2026-04-28T00:00:00Z"},
+		{"*-*-31 UTC", "2026-02-01T00:00:00Z", "2026-03-31T00:00:00Z"},
+		{"*-*-31 UTC", "2026-03-31T00:00:00Z", "2026-05-31T00:00:00Z"},
+		{"*-02-29 UTC", "2025-01-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"Fri *-*-13 UTC", "2026-01-01T00:00:00Z", "2026-02-13T00:00:00Z"},
+		{"hourly UTC", "2026-12-31T23:59:59.5Z", "2027-01-01T00:00:00Z"},
+		{"*:*:0/15 UTC", "2026-10-14T00:00:44Z", "2026-10-14T00:00:45Z"},
+		{"*:*:1.5 UTC", "2026-10-14T00:00:00Z", "2026-10-14T00:00:01.5Z"},
+		{"quarterly UTC", "2026-10-14T00:00:00Z", "2027-01-01T00:00:00Z"},
+		{"2003-03-05 05:40 UTC", "2003-03-05T05:39:59Z", "2003-03-05T05:40:00Z"},
+	}
+
+	for i, tt := range tests {
+		c, err := ParseCalendar(tt.spec)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		after, err := time.Parse(time.RFC3339Nano, tt.after)
+		if err != nil {
+			t.Fatal(err)
+		}
+		next, err := c.Next(after)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if s := next.Format(time.RFC3339Nano); s != tt.next {
+			t.Errorf("case %d: expected next elapse of %q after %s at %s, got %s", i, tt.spec, tt.after, tt.next, s)
+		}
+	}
+
+	for _, spec := range []string{"*-02-30 UTC", "2003-03-05 05:40 UTC"} {
+		c, err := ParseCalendar(spec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); err != ErrNoNextElapse {
+			t.Errorf("expected ErrNoNextElapse for %q, got %v", spec, err)
+		}
+	}
+}
+
+func TestCalendarNextTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+
+	c, err := ParseCalendar("*-*-* 02:30 Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := c.String(); s != "*-*-* 02:30:00 Europe/Berlin" {
+		t.Fatalf("unexpected normalized spec %q", s)
+	}
+
+	next, err := c.Next(time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2026, 10, 15, 2, 30, 0, 0, loc); !next.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, next)
+	}
+}
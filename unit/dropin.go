@@ -0,0 +1,158 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// dependencySettings are list settings which cannot be reset by an empty
+// assignment; a drop-in can only add to them.
+var dependencySettings = map[string]bool{
+	"After": true, "Before": true, "Wants": true, "Requires": true, "Requisite": true,
+	"BindsTo": true, "PartOf": true, "Upholds": true, "Conflicts": true, "OnFailure": true,
+	"OnSuccess": true, "PropagatesReloadTo": true, "ReloadPropagatedFrom": true,
+	"PropagatesStopTo": true, "StopPropagatedFrom": true, "JoinsNamespaceOf": true,
+	"WantedBy": true, "RequiredBy": true, "UpheldBy": true, "Also": true, "Alias": true,
+}
+
+// listSettings are the settings accumulating a list, which an empty
+// assignment resets, e.g. ExecStart=.
+var listSettings = map[string]bool{
+	"ExecCondition": true, "ExecStartPre": true, "ExecStart": true, "ExecStartPost": true,
+	"ExecReload": true, "ExecStop": true, "ExecStopPost": true,
+	"Environment": true, "EnvironmentFile": true, "PassEnvironment": true, "UnsetEnvironment": true,
+	"ListenStream": true, "ListenDatagram": true, "ListenSequentialPacket": true, "ListenFIFO": true,
+	"ListenSpecial": true, "ListenNetlink": true, "ListenMessageQueue": true, "ListenUSBFunction": true,
+	"OnActiveSec": true, "OnBootSec": true, "OnStartupSec": true, "OnUnitActiveSec": true,
+	"OnUnitInactiveSec": true, "OnCalendar": true,
+	"PathExists": true, "PathExistsGlob": true, "PathChanged": true, "PathModified": true, "DirectoryNotEmpty": true,
+	"ReadWritePaths": true, "ReadOnlyPaths": true, "InaccessiblePaths": true, "ExecPaths": true, "NoExecPaths": true,
+	"BindPaths": true, "BindReadOnlyPaths": true, "TemporaryFileSystem": true,
+	"DeviceAllow": true, "IPAddressAllow": true, "IPAddressDeny": true,
+	"SupplementaryGroups": true, "SystemCallFilter": true, "RestrictAddressFamilies": true,
+	"RuntimeDirectory": true, "StateDirectory": true, "CacheDirectory": true, "LogsDirectory": true,
+	"ConfigurationDirectory": true, "LoadCredential": true, "SetCredential": true,
+	"ConditionPathExists": true, "AssertPathExists": true,
+	"Documentation": true, "RequiresMountsFor": true, "WantsMountsFor": true,
+}
+
+// IsListSetting returns whether the named setting accumulates values over
+// repeated assignments rather than being overridden by them.
+func IsListSetting(name string) bool {
+	return listSettings[name] || dependencySettings[name] || strings.HasPrefix(name, "Condition") || strings.HasPrefix(name, "Assert")
+}
+
+type optionKey struct {
+	section string
+	name    string
+}
+
+func groupOptions(opts []*UnitOption) ([]optionKey, map[optionKey][]string) {
+	keys := []optionKey{}
+	values := map[optionKey][]string{}
+	for _, opt := range opts {
+		k := optionKey{opt.Section, opt.Name}
+		if _, ok := values[k]; !ok {
+			keys = append(keys, k)
+		}
+		values[k] = append(values[k], opt.Value)
+	}
+	return keys, values
+}
+
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// DropIn returns the options of a drop-in turning the configuration of base
+// into the one of desired, containing only the changed settings. List
+// settings which changed are reset with an empty assignment before their new
+// values. Dependencies cannot be reset, so an error is returned if desired
+// lacks a dependency base has.
+func DropIn(base, desired []*UnitOption) ([]*UnitOption, error) {
+	baseKeys, baseValues := groupOptions(base)
+	desiredKeys, desiredValues := groupOptions(desired)
+
+	opts := []*UnitOption{}
+	for _, k := range desiredKeys {
+		want := desiredValues[k]
+		have := baseValues[k]
+		if equalValues(want, have) {
+			continue
+		}
+
+		switch {
+		case dependencySettings[k.name]:
+			present := map[string]bool{}
+			for _, v := range want {
+				present[v] = true
+			}
+			for _, v := range have {
+				if !present[v] {
+					return nil, fmt.Errorf("cannot remove %s=%s in section [%s] with a drop-in", k.name, v, k.section)
+				}
+				present[v] = false
+			}
+			for _, v := range want {
+				if present[v] {
+					opts = append(opts, NewUnitOption(k.section, k.name, v))
+				}
+			}
+		case IsListSetting(k.name) || len(want) > 1 || len(have) > 1:
+			if len(have) > 0 {
+				opts = append(opts, NewUnitOption(k.section, k.name, ""))
+			}
+			for _, v := range want {
+				opts = append(opts, NewUnitOption(k.section, k.name, v))
+			}
+		default:
+			opts = append(opts, NewUnitOption(k.section, k.name, want[len(want)-1]))
+		}
+	}
+
+	// Settings dropped entirely are reset to their defaults.
+	for _, k := range baseKeys {
+		if _, ok := desiredValues[k]; ok {
+			continue
+		}
+		if dependencySettings[k.name] {
+			return nil, fmt.Errorf("cannot remove %s= in section [%s] with a drop-in", k.name, k.section)
+		}
+		opts = append(opts, NewUnitOption(k.section, k.name, ""))
+	}
+
+	return opts, nil
+}
+
+// DropInPath returns the path of the drop-in with the given name for a unit
+// below dir, e.g. /etc/systemd/system/foo.service.d/override.conf for
+// DropInPath("/etc/systemd/system", "foo.service", "override").
+func DropInPath(dir, unit, name string) string {
+	if !strings.HasSuffix(name, ".conf") {
+		name += ".conf"
+	}
+	return filepath.Join(dir, unit+".d", name)
+}
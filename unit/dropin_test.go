@@ -0,0 +1,130 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"testing"
+)
+
+func TestDropIn(t *testing.T) {
+	tests := []struct {
+		base    []*UnitOption
+		desired []*UnitOption
+		output  []*UnitOption
+	}{
+		// unchanged options are omitted
+		{
+			[]*UnitOption{
+				&UnitOption{"Unit", "Description", "Foo"},
+				&UnitOption{"Service", "Type", "simple"},
+			},
+			[]*UnitOption{
+				&UnitOption{"Unit", "Description", "Foo"},
+				&UnitOption{"Service", "Type", "oneshot"},
+			},
+			[]*UnitOption{
+				&UnitOption{"Service", "Type", "oneshot"},
+			},
+		},
+
+		// list settings are reset
+		{
+			[]*UnitOption{
+				&UnitOption{"Service", "ExecStart", "/bin/foo"},
+				&UnitOption{"Service", "Environment", "A=1"},
+			},
+			[]*UnitOption{
+				&UnitOption{"Service", "ExecStart", "/bin/foo --bar"},
+				&UnitOption{"Service", "Environment", "A=1"},
+				&UnitOption{"Service", "Environment", "B=2"},
+			},
+			[]*UnitOption{
+				&UnitOption{"Service", "ExecStart", ""},
+				&UnitOption{"Service", "ExecStart", "/bin/foo --bar"},
+				&UnitOption{"Service", "Environment", ""},
+				&UnitOption{"Service", "Environment", "A=1"},
+				&UnitOption{"Service", "Environment", "B=2"},
+			},
+		},
+
+		// new list settings need no reset
+		{
+			[]*UnitOption{},
+			[]*UnitOption{
+				&UnitOption{"Service", "ExecStartPre", "/bin/true"},
+			},
+			[]*UnitOption{
+				&UnitOption{"Service", "ExecStartPre", "/bin/true"},
+			},
+		},
+
+		// dependencies are only added
+		{
+			[]*UnitOption{
+				&UnitOption{"Unit", "After", "a.service"},
+			},
+			[]*UnitOption{
+				&UnitOption{"Unit", "After", "a.service"},
+				&UnitOption{"Unit", "After", "b.service"},
+			},
+			[]*UnitOption{
+				&UnitOption{"Unit", "After", "b.service"},
+			},
+		},
+
+		// dropped settings are reset
+		{
+			[]*UnitOption{
+				&UnitOption{"Service", "User", "nobody"},
+			},
+			[]*UnitOption{},
+			[]*UnitOption{
+				&UnitOption{"Service", "User", ""},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		output, err := DropIn(tt.base, tt.desired)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !AllMatch(output, tt.output) {
+			t.Errorf("case %d: expected %v, got %v", i, tt.output, output)
+		}
+	}
+}
+
+func TestDropInFail(t *testing.T) {
+	base := []*UnitOption{
+		&UnitOption{"Unit", "Wants", "a.service"},
+		&UnitOption{"Unit", "Wants", "b.service"},
+	}
+	if _, err := DropIn(base, base[:1]); err == nil {
+		t.Error("expected error removing a dependency")
+	}
+	if _, err := DropIn(base, []*UnitOption{}); err == nil {
+		t.Error("expected error removing all dependencies")
+	}
+}
+
+func TestDropInPath(t *testing.T) {
+	for _, name := range []string{"override", "override.conf"} {
+		if p := DropInPath("/etc/systemd/system", "foo.service", name); p != "/etc/systemd/system/foo.service.d/override.conf" {
+			t.Errorf("unexpected drop-in path %s", p)
+		}
+	}
+}
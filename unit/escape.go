@@ -17,6 +17,7 @@
 package unit
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -24,8 +25,21 @@ import (
 
 const (
 	allowed = `:_.abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789`
+
+	// validChars are the characters allowed in the prefix and instance of
+	// a unit name.
+	validChars = allowed + `-\`
+	// unitNameMax is the maximum length of a unit name.
+	unitNameMax = 255
+	// nameMax and pathMax are the maximum lengths of a path component and
+	// a path.
+	nameMax = 255
+	pathMax = 4096
 )
 
+// unitTypes are the suffixes of the unit types systemd knows.
+var unitTypes = []string{"service", "mount", "swap", "socket", "target", "device", "automount", "timer", "path", "slice", "scope"}
+
 // If isPath is true:
 //
 //	We remove redundant '/'s, the leading '/', and trailing '/'.
@@ -57,7 +71,7 @@ func escape(unescaped string, isPath bool) string {
 		if c == '/' {
 			e = append(e, '-')
 		} else if start && c == '.' || strings.IndexByte(allowed, c) == -1 {
-			e = append(e, []byte(fmt.Sprintf(`\x%x`, c))...)
+			e = append(e, []byte(fmt.Sprintf(`\x%02x`, c))...)
 		} else {
 			e = append(e, c)
 		}
@@ -84,7 +98,7 @@ func unescape(escaped string, isPath bool) string {
 		if c == '-' {
 			c = '/'
 		} else if c == '\\' && len(escaped)-i >= 4 && escaped[i+1] == 'x' {
-			n, err := strconv.ParseInt(escaped[i+2:i+4], 16, 8)
+			n, err := strconv.ParseUint(escaped[i+2:i+4], 16, 8)
 			if err == nil {
 				c = byte(n)
 				i += 3
@@ -98,22 +112,240 @@ func unescape(escaped string, isPath bool) string {
 	return string(u)
 }
 
+// unescapeStrict is unescape, but rejects what systemd does: escapes other
+// than `\x%x`, and for paths empty names and names that do not stand for a
+// normalized path.
+func unescapeStrict(escaped string, isPath bool) (string, error) {
+	if isPath {
+		if escaped == "" {
+			return "", errors.New("empty path unit name")
+		}
+		if escaped == "-" {
+			return "/", nil
+		}
+	}
+
+	u := []byte{}
+	for i := 0; i < len(escaped); i++ {
+		c := escaped[i]
+		if c == '-' {
+			c = '/'
+		} else if c == '\\' {
+			if len(escaped)-i < 4 || escaped[i+1] != 'x' {
+				return "", fmt.Errorf("invalid escape in %q", escaped)
+			}
+			n, err := strconv.ParseUint(escaped[i+2:i+4], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape in %q", escaped)
+			}
+			c = byte(n)
+			i += 3
+		}
+		u = append(u, c)
+	}
+
+	if !isPath {
+		return string(u), nil
+	}
+	if len(u) == 0 || u[0] == '/' || u[len(u)-1] == '/' {
+		return "", fmt.Errorf("%q does not stand for a normalized path", escaped)
+	}
+	p := "/" + string(u)
+	if !pathIsNormalized(p) {
+		return "", fmt.Errorf("%q does not stand for a normalized path", escaped)
+	}
+	return p, nil
+}
+
+// pathIsNormalized returns whether p is a valid path without "." and ".."
+// components or duplicate slashes.
+func pathIsNormalized(p string) bool {
+	if p == "" || len(p) > pathMax || strings.Contains(p, "//") {
+		return false
+	}
+	for _, c := range strings.Split(p, "/") {
+		if c == "." || c == ".." || len(c) > nameMax {
+			return false
+		}
+	}
+	return true
+}
+
+// simplifyPath drops duplicate slashes, "." components and trailing slashes
+// from p, as systemd does before escaping a path.
+func simplifyPath(p string) string {
+	var parts []string
+	for _, c := range strings.Split(p, "/") {
+		if c != "" && c != "." {
+			parts = append(parts, c)
+		}
+	}
+	s := strings.Join(parts, "/")
+	if strings.HasPrefix(p, "/") {
+		s = "/" + s
+	}
+	return s
+}
+
 // UnitNameEscape escapes a string as `systemd-escape` would
 func UnitNameEscape(unescaped string) string {
 	return escape(unescaped, false)
 }
 
-// UnitNameUnescape unescapes a string as `systemd-escape --unescape` would
+// UnitNameUnescape unescapes a string as `systemd-escape --unescape` would.
+// Unlike systemd-escape it does not reject invalid escapes such as `x\y`,
+// but keeps them as they are.
 func UnitNameUnescape(escaped string) string {
 	return unescape(escaped, false)
 }
 
-// UnitNamePathEscape escapes a string as `systemd-escape --path` would
+// UnitNamePathEscape escapes a string as `systemd-escape --path` would.
+// Unlike systemd-escape it does not reject the empty path or paths with ".."
+// components, see UnitNameFromPath for a variant that does.
 func UnitNamePathEscape(unescaped string) string {
 	return escape(unescaped, true)
 }
 
-// UnitNamePathUnescape unescapes a string as `systemd-escape --path --unescape` would
+// UnitNamePathUnescape unescapes a string as `systemd-escape --path --unescape` would.
+// Unlike systemd-escape it keeps invalid escapes as they are and does not
+// reject names standing for paths with ".." components, see UnitNameToPath
+// for a variant that does.
 func UnitNamePathUnescape(escaped string) string {
 	return unescape(escaped, true)
 }
+
+// UnitNameIsValid returns whether name is a valid unit name, a template like
+// foo@.service or an instance of one like foo@bar.service.
+func UnitNameIsValid(name string) bool {
+	if name == "" || len(name) > unitNameMax {
+		return false
+	}
+	dot := strings.LastIndexByte(name, '.')
+	if dot <= 0 || !isUnitType(name[dot+1:]) {
+		return false
+	}
+
+	prefix := name[:dot]
+	at := strings.IndexByte(prefix, '@')
+	if at == 0 || strings.Count(prefix, "@") > 1 {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		if prefix[i] != '@' && strings.IndexByte(validChars, prefix[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isUnitType(t string) bool {
+	for _, u := range unitTypes {
+		if t == u {
+			return true
+		}
+	}
+	return false
+}
+
+// suffixWithDot returns suffix with a leading dot, defaulting to .service.
+func suffixWithDot(suffix string) string {
+	if suffix == "" {
+		return ".service"
+	}
+	if !strings.HasPrefix(suffix, ".") {
+		return "." + suffix
+	}
+	return suffix
+}
+
+// UnitNameFromPath returns the name of the unit for a path, as
+// `systemd-escape --path --suffix` would, e.g. home-foo.mount for /home/foo
+// and the mount suffix. Like systemd-escape it fails for the empty path,
+// paths with ".." components and if the name would be too long.
+func UnitNameFromPath(path, suffix string) (string, error) {
+	p := simplifyPath(path)
+	if !pathIsNormalized(p) {
+		return "", fmt.Errorf("path %q is not normalized", path)
+	}
+	name := escape(p, true) + suffixWithDot(suffix)
+	if !UnitNameIsValid(name) {
+		return "", fmt.Errorf("invalid unit name %q for path %q", name, path)
+	}
+	return name, nil
+}
+
+// UnitNameToPath returns the path a unit name such as home-foo.mount stands
+// for, stripping its suffix and any template prefix. Like
+// `systemd-escape --path --unescape` it fails for invalid escapes and names
+// not standing for a normalized path.
+func UnitNameToPath(name string) (string, error) {
+	if dot := strings.LastIndexByte(name, '.'); dot > 0 && isUnitType(name[dot+1:]) {
+		name = name[:dot]
+	}
+	if at := strings.IndexByte(name, '@'); at >= 0 {
+		name = name[at+1:]
+	}
+	return unescapeStrict(name, true)
+}
+
+// UnitNameEscapeTemplate escapes value and inserts it as the instance of the
+// template, as `systemd-escape --template` would, e.g. foo@bar.service for
+// foo@.service and bar.
+func UnitNameEscapeTemplate(template, value string, isPath bool) (string, error) {
+	at := strings.IndexByte(template, '@')
+	if !UnitNameIsValid(template) || at < 0 || template[at+1] != '.' {
+		return "", fmt.Errorf("invalid template unit name %q", template)
+	}
+	instance := escape(value, isPath)
+	name := template[:at+1] + instance + template[at+1:]
+	if len(name) > unitNameMax {
+		return "", errors.New("unit name too long")
+	}
+	return name, nil
+}
+
+// UnitNameMangle turns an arbitrary string into a valid unit name, as
+// systemctl does with its arguments. Valid unit names are returned
+// unchanged, paths below /dev and /sys are turned into device units, other
+// absolute paths into mount units. Otherwise invalid characters are escaped
+// and suffix (.service if empty) is appended unless the name has a valid one.
+// Paths which are not normalized are escaped like other strings. It fails for
+// the empty string and if the result would be too long.
+func UnitNameMangle(name, suffix string) (string, error) {
+	if name == "" {
+		return "", errors.New("empty unit name")
+	}
+	if UnitNameIsValid(name) {
+		return name, nil
+	}
+	if strings.HasPrefix(name, "/dev/") || strings.HasPrefix(name, "/sys/") {
+		if mangled, err := UnitNameFromPath(name, ".device"); err == nil {
+			return mangled, nil
+		}
+	} else if strings.HasPrefix(name, "/") {
+		if mangled, err := UnitNameFromPath(name, ".mount"); err == nil {
+			return mangled, nil
+		}
+	}
+
+	e := []byte{}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c == '/' {
+			e = append(e, '-')
+		} else if c != '@' && strings.IndexByte(validChars, c) < 0 {
+			e = append(e, []byte(fmt.Sprintf(`\x%02x`, c))...)
+		} else {
+			e = append(e, c)
+		}
+	}
+
+	mangled := string(e)
+	if dot := strings.LastIndexByte(mangled, '.'); dot < 0 || !isUnitType(mangled[dot+1:]) {
+		mangled += suffixWithDot(suffix)
+	}
+	if len(mangled) > unitNameMax {
+		return "", errors.New("unit name too long")
+	}
+	return mangled, nil
+}
@@ -15,6 +15,7 @@
 package unit
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -108,6 +109,12 @@ func TestUnitNameEscape(t *testing.T) {
 			out:    `\x2e.\x5c\x2d\x21\x23\x3f\x3f`,
 			isPath: true,
 		},
+		// escape control and non-ASCII characters with two hex digits
+		{
+			in:     "a\tb ä",
+			out:    `a\x09b\x20\xc3\xa4`,
+			isPath: false,
+		},
 		// escape real-world example
 		{
 			in:     `user-cloudinit@/var/lib/coreos/vagrant/vagrantfile-user-data.service`,
@@ -189,6 +196,12 @@ func TestUnitNameUnescape(t *testing.T) {
 			out:    `/..\\x-\xaZ\x.o!#??\`,
 			isPath: true,
 		},
+		// unescape non-ASCII characters
+		{
+			in:     `a\x09b\x20\xc3\xa4`,
+			out:    "a\tb ä",
+			isPath: false,
+		},
 		// unescape real-world example
 		{
 			in:     `user\x2dcloudinit\x40-var-lib-coreos-vagrant-vagrantfile\x2duser\x2ddata.service`,
@@ -209,3 +222,143 @@ func TestUnitNameUnescape(t *testing.T) {
 		}
 	}
 }
+
+func TestUnitNameIsValid(t *testing.T) {
+	tests := []struct {
+		in    string
+		valid bool
+	}{
+		{"foo.service", true},
+		{"foo@.service", true},
+		{"foo@bar.service", true},
+		{`home-foo\x2dbar.mount`, true},
+		{"foo", false},
+		{"foo.bar", false},
+		{".service", false},
+		{"@foo.service", false},
+		{"foo@bar@baz.service", false},
+		{"foo bar.service", false},
+	}
+
+	for i, tt := range tests {
+		if valid := UnitNameIsValid(tt.in); valid != tt.valid {
+			t.Errorf("case %d: expected %v for %q, got %v", i, tt.valid, tt.in, valid)
+		}
+	}
+}
+
+func TestUnitNameMangle(t *testing.T) {
+	tests := []struct {
+		in     string
+		suffix string
+		out    string
+	}{
+		{"foo.service", "", "foo.service"},
+		{"foo", "", "foo.service"},
+		{"foo", "target", "foo.target"},
+		{"foo.socket", ".service", "foo.socket"},
+		{"/home/foo", "", "home-foo.mount"},
+		{"/dev/sda1", "", "dev-sda1.device"},
+		{"foo bar", "", `foo\x20bar.service`},
+		{"foo@bar baz", "", `foo@bar\x20baz.service`},
+		{"/home/../foo", "", `-home-..-foo.service`},
+		{"../foo", "", `..-foo.service`},
+	}
+
+	for i, tt := range tests {
+		out, err := UnitNameMangle(tt.in, tt.suffix)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+	}
+
+	for _, in := range []string{"", strings.Repeat("a", 256)} {
+		if out, err := UnitNameMangle(in, ""); err == nil {
+			t.Errorf("expected error for %q, got %q", in, out)
+		}
+	}
+}
+
+func TestUnitNamePathConversion(t *testing.T) {
+	fromPath := []struct {
+		in  string
+		out string
+	}{
+		{"/var/lib/my-data", `var-lib-my\x2ddata.mount`},
+		{"/", "-.mount"},
+		{"//var/./lib/", "var-lib.mount"},
+		{"var/lib", "var-lib.mount"},
+	}
+	for i, tt := range fromPath {
+		name, err := UnitNameFromPath(tt.in, "mount")
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+		} else if name != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, name)
+		}
+	}
+
+	// systemd-escape --path rejects these.
+	for _, in := range []string{"", "../x", "/var/../lib", "/" + strings.Repeat("a", 300)} {
+		if name, err := UnitNameFromPath(in, "mount"); err == nil {
+			t.Errorf("expected error for %q, got %q", in, name)
+		}
+	}
+
+	toPath := []struct {
+		in  string
+		out string
+	}{
+		{`var-lib-my\x2ddata.mount`, "/var/lib/my-data"},
+		{`systemd-fsck@dev-disk-by\x2dlabel-root.service`, "/dev/disk/by-label/root"},
+		{"-.mount", "/"},
+	}
+	for i, tt := range toPath {
+		path, err := UnitNameToPath(tt.in)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+		} else if path != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, path)
+		}
+	}
+
+	// systemd-escape --path --unescape rejects these.
+	for _, in := range []string{"", `x\y`, `x\x4`, `x\xzz`, "-x", "x-", "x--y", "..-x", `\x2e\x2e`} {
+		if path, err := UnitNameToPath(in); err == nil {
+			t.Errorf("expected error for %q, got %q", in, path)
+		}
+	}
+}
+
+func TestUnitNameEscapeLenient(t *testing.T) {
+	// Unlike systemd-escape, these do not reject invalid input.
+	if s := UnitNameUnescape(`x\y`); s != `x\y` {
+		t.Errorf("unexpected unescaped name %q", s)
+	}
+	if s := UnitNamePathEscape("../x"); s != `\x2e.-x` {
+		t.Errorf("unexpected escaped path %q", s)
+	}
+	if s := UnitNamePathEscape(""); s != "-" {
+		t.Errorf("unexpected escaped path %q", s)
+	}
+}
+
+func TestUnitNameEscapeTemplate(t *testing.T) {
+	name, err := UnitNameEscapeTemplate("systemd-fsck@.service", "/dev/disk/by-label/root", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != `systemd-fsck@dev-disk-by\x2dlabel-root.service` {
+		t.Errorf("unexpected unit name %q", name)
+	}
+
+	for _, template := range []string{"foo.service", "foo@bar.service", "foo@"} {
+		if _, err := UnitNameEscapeTemplate(template, "x", false); err == nil {
+			t.Errorf("expected error for template %q", template)
+		}
+	}
+}
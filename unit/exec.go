@@ -0,0 +1,222 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ExecCommand is a command line of an Exec*= setting like ExecStart=.
+type ExecCommand struct {
+	// Path is the executable, either an absolute path or a name looked up
+	// in systemd's fixed search path.
+	Path string
+	// Argv are the arguments, starting with argv[0]. If argv[0] differs from
+	// Path, the command line is written with the "@" prefix.
+	Argv []string
+
+	IgnoreFailure bool // "-": a non-zero exit status is not a failure
+	NoEnvExpand   bool // ":": environment variables are not substituted
+	Privileged    bool // "+": run with full privileges
+	// NoSetuid ("!") runs the command with privileges elevated, but without
+	// changing user or group credentials.
+	NoSetuid bool
+	// AmbientCapabilities ("!!") is like NoSetuid, but only takes effect on
+	// systems lacking ambient capability support.
+	AmbientCapabilities bool
+}
+
+// NewExecCommand returns the ExecCommand running argv, whose first element
+// is the executable.
+func NewExecCommand(argv ...string) *ExecCommand {
+	c := &ExecCommand{Argv: argv}
+	if len(argv) > 0 {
+		c.Path = argv[0]
+	}
+	return c
+}
+
+// ParseExecCommand parses the value of an Exec*= setting holding a single
+// command line. See ParseExecCommandLine.
+func ParseExecCommand(value string) (*ExecCommand, error) {
+	cmds, err := ParseExecCommandLine(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(cmds) != 1 {
+		return nil, fmt.Errorf("expected a single command, got %d", len(cmds))
+	}
+	return cmds[0], nil
+}
+
+// ParseExecCommandLine parses the value of an Exec*= setting, which may hold
+// several commands separated by a lone semicolon. Words are unquoted and
+// unescaped like SplitWords does, and the prefixes of the first word are
+// decoded. Specifiers and environment variable references are kept as they
+// are, since systemd expands them at load and execution time.
+func ParseExecCommandLine(value string) ([]*ExecCommand, error) {
+	cmds := []*ExecCommand{}
+	value = UnfoldValue(value)
+
+	var cmd *ExecCommand
+	argv0 := false
+	for {
+		value = strings.TrimLeft(value, " \t\n\r")
+		if value == "" {
+			break
+		}
+
+		if cmd != nil && isExecWord(value, ";") {
+			if err := cmd.finish(argv0); err != nil {
+				return nil, err
+			}
+			cmds = append(cmds, cmd)
+			cmd = nil
+			value = value[1:]
+			continue
+		}
+
+		var word string
+		if isExecWord(value, `\;`) {
+			word, value = ";", value[2:]
+		} else {
+			var err error
+			word, value, err = nextWord(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if cmd == nil {
+			cmd = &ExecCommand{}
+			argv0, word = cmd.parsePrefixes(word)
+			if word == "" {
+				return nil, errors.New("empty executable path")
+			}
+			cmd.Path = word
+			if argv0 {
+				continue
+			}
+		}
+		cmd.Argv = append(cmd.Argv, word)
+	}
+
+	if cmd == nil {
+		if len(cmds) > 0 {
+			return nil, errors.New("trailing command separator")
+		}
+		return cmds, nil
+	}
+	if err := cmd.finish(argv0); err != nil {
+		return nil, err
+	}
+	return append(cmds, cmd), nil
+}
+
+// isExecWord returns whether value starts with the unquoted word w.
+func isExecWord(value, w string) bool {
+	return strings.HasPrefix(value, w) && (len(value) == len(w) || strings.IndexByte(" \t\n\r", value[len(w)]) >= 0)
+}
+
+func (c *ExecCommand) parsePrefixes(word string) (bool, string) {
+	argv0 := false
+	for word != "" {
+		switch {
+		case word[0] == '@' && !argv0:
+			argv0 = true
+		case word[0] == '-' && !c.IgnoreFailure:
+			c.IgnoreFailure = true
+		case word[0] == ':' && !c.NoEnvExpand:
+			c.NoEnvExpand = true
+		case word[0] == '+' && !c.Privileged && !c.NoSetuid && !c.AmbientCapabilities:
+			c.Privileged = true
+		case word[0] == '!' && !c.Privileged && !c.NoSetuid && !c.AmbientCapabilities:
+			c.NoSetuid = true
+		case word[0] == '!' && !c.Privileged && !c.AmbientCapabilities:
+			// A second "!" anywhere in the prefix upgrades to "!!".
+			c.NoSetuid = false
+			c.AmbientCapabilities = true
+		default:
+			return argv0, word
+		}
+		word = word[1:]
+	}
+	return argv0, word
+}
+
+func (c *ExecCommand) finish(argv0 bool) error {
+	if argv0 && len(c.Argv) == 0 {
+		return fmt.Errorf("missing argv[0] for %s", c.Path)
+	}
+	if strings.Contains(c.Path, "/") && !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("executable path %q is neither absolute nor a plain name", c.Path)
+	}
+	return nil
+}
+
+// String returns the command line as written in a unit file, quoting words
+// where needed.
+func (c *ExecCommand) String() string {
+	var prefix strings.Builder
+	argv := c.Argv
+	if len(argv) == 0 {
+		argv = []string{c.Path}
+	}
+	if argv[0] != c.Path {
+		prefix.WriteByte('@')
+	} else {
+		argv = argv[1:]
+	}
+	if c.IgnoreFailure {
+		prefix.WriteByte('-')
+	}
+	if c.NoEnvExpand {
+		prefix.WriteByte(':')
+	}
+	switch {
+	case c.Privileged:
+		prefix.WriteByte('+')
+	case c.AmbientCapabilities:
+		prefix.WriteString("!!")
+	case c.NoSetuid:
+		prefix.WriteByte('!')
+	}
+
+	words := []string{QuoteWord(prefix.String() + c.Path)}
+	for _, arg := range argv {
+		words = append(words, QuoteWord(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// FormatExecCommandLine returns the command lines separated by semicolons,
+// for the settings running several commands like ExecStartPre=.
+func FormatExecCommandLine(cmds ...*ExecCommand) string {
+	lines := make([]string, 0, len(cmds))
+	for _, c := range cmds {
+		lines = append(lines, c.String())
+	}
+	return strings.Join(lines, " ; ")
+}
+
+// ExecLiteral escapes the specifiers and environment variable references in
+// s, so that an Exec*= setting passes it to the command verbatim. Without
+// environment variable substitution (NoEnvExpand), only the specifiers need
+// escaping, which EscapeSpecifiers does.
+func ExecLiteral(s string) string {
+	return strings.Replace(EscapeSpecifiers(s), "$", "$$", -1)
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExecCommandLine(t *testing.T) {
+	tests := []struct {
+		in     string
+		output []*ExecCommand
+	}{
+		{
+			"/bin/echo hello world",
+			[]*ExecCommand{{Path: "/bin/echo", Argv: []string{"/bin/echo", "hello", "world"}}},
+		},
+		{
+			`-/usr/bin/foo "a b" 'c d' \x41`,
+			[]*ExecCommand{{Path: "/usr/bin/foo", Argv: []string{"/usr/bin/foo", "a b", "c d", "A"}, IgnoreFailure: true}},
+		},
+		{
+			"@-:/bin/sh sh -c ${FOO}",
+			[]*ExecCommand{{Path: "/bin/sh", Argv: []string{"sh", "-c", "${FOO}"}, IgnoreFailure: true, NoEnvExpand: true}},
+		},
+		{
+			"+/bin/true",
+			[]*ExecCommand{{Path: "/bin/true", Argv: []string{"/bin/true"}, Privileged: true}},
+		},
+		{
+			"!!-true",
+			[]*ExecCommand{{Path: "true", Argv: []string{"true"}, AmbientCapabilities: true, IgnoreFailure: true}},
+		},
+		{
+			"!:!/bin/true",
+			[]*ExecCommand{{Path: "/bin/true", Argv: []string{"/bin/true"}, AmbientCapabilities: true, NoEnvExpand: true}},
+		},
+		{
+			`!/bin/echo a ; /bin/echo b \; ";" ;c`,
+			[]*ExecCommand{
+				{Path: "/bin/echo", Argv: []string{"/bin/echo", "a"}, NoSetuid: true},
+				{Path: "/bin/echo", Argv: []string{"/bin/echo", "b", ";", ";", ";c"}},
+			},
+		},
+		{
+			"",
+			[]*ExecCommand{},
+		},
+	}
+
+	for i, tt := range tests {
+		output, err := ParseExecCommandLine(tt.in)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.output, output) {
+			t.Errorf("case %d: expected %+v, got %+v", i, tt.output, output)
+		}
+	}
+}
+
+func TestParseExecCommandLineFail(t *testing.T) {
+	tests := []string{
+		"-",
+		"@/bin/sh",
+		"+!/bin/true",
+		"!:!",
+		"!!!/bin/true",
+		"--/bin/true",
+		"bin/true",
+		"/bin/true ;",
+		`/bin/echo "unterminated`,
+	}
+
+	for i, tt := range tests {
+		if output, err := ParseExecCommandLine(tt); err == nil {
+			t.Errorf("case %d: expected error for %q, got %+v", i, tt, output)
+		}
+	}
+}
+
+func TestExecCommandString(t *testing.T) {
+	tests := []struct {
+		cmd    *ExecCommand
+		output string
+	}{
+		{NewExecCommand("/bin/echo", "hello", "world"), "/bin/echo hello world"},
+		{NewExecCommand("/bin/echo", "a b", `"q"`, `back\slash`, ";", "", "tab\there"),
+			`/bin/echo "a b" "\"q\"" "back\\slash" ";" "" "tab\there"`},
+		{&ExecCommand{Path: "/bin/sh", Argv: []string{"sh", "-c", "exit 1"}, IgnoreFailure: true, Privileged: true},
+			`@-+/bin/sh sh -c "exit 1"`},
+		{&ExecCommand{Path: "/opt/my app/run", NoEnvExpand: true, AmbientCapabilities: true},
+			`":!!/opt/my app/run"`},
+		{NewExecCommand("/bin/printf", ExecLiteral("100% $HOME")), `/bin/printf "100%% $$HOME"`},
+	}
+
+	for i, tt := range tests {
+		output := tt.cmd.String()
+		if output != tt.output {
+			t.Errorf("case %d: expected %q, got %q", i, tt.output, output)
+			continue
+		}
+
+		parsed, err := ParseExecCommand(output)
+		if err != nil {
+			t.Errorf("case %d: failed to parse %q: %v", i, output, err)
+			continue
+		}
+		if parsed.String() != output {
+			t.Errorf("case %d: round trip of %q returned %q", i, output, parsed.String())
+		}
+	}
+
+	line := FormatExecCommandLine(NewExecCommand("/bin/true"), NewExecCommand("/bin/echo", ";"))
+	if line != `/bin/true ; /bin/echo ";"` {
+		t.Errorf("unexpected command line %q", line)
+	}
+	if cmds, err := ParseExecCommandLine(line); err != nil || len(cmds) != 2 {
+		t.Errorf("failed to parse %q: %v", line, err)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build ignore
+// +build ignore
+
+// gen_directives generates lint_directives_systemd.go from the configuration
+// items a systemd binary dumps with --dump-configuration-items.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+func main() {
+	systemd := flag.String("systemd", "/usr/lib/systemd/systemd", "systemd binary to query")
+	out := flag.String("o", "lint_directives_systemd.go", "output file")
+	flag.Parse()
+
+	version, err := exec.Command(*systemd, "--version").Output()
+	if err != nil {
+		log.Fatal(err)
+	}
+	dump, err := exec.Command(*systemd, "--dump-configuration-items").Output()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	sections := map[string][]string{}
+	var section string
+	s := bufio.NewScanner(bytes.NewReader(dump))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = line[1 : len(line)-1]
+		case section != "":
+			if i := strings.IndexByte(line, '='); i > 0 {
+				sections[section] = append(sections[section], line[:i])
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	var names []string
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen_directives.go from %s; DO NOT EDIT.\n\n", strings.SplitN(string(version), "\n", 2)[0])
+	b.WriteString("package unit\n\n")
+	b.WriteString("// systemdDirectives lists the directives systemd knows, per section.\n")
+	b.WriteString("var systemdDirectives = map[string][]string{\n")
+	for _, name := range names {
+		keys := sections[name]
+		sort.Strings(keys)
+		fmt.Fprintf(&b, "\t%q: {\n", name)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "\t\t%q,\n", k)
+		}
+		b.WriteString("\t},\n")
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,381 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LintWarning is a problem found by Lint. Section, Name and Value are empty
+// for problems concerning the unit as a whole.
+type LintWarning struct {
+	Section string
+	Name    string
+	Value   string
+	Message string
+}
+
+func (w LintWarning) String() string {
+	if w.Name == "" && w.Section == "" {
+		return w.Message
+	}
+	if w.Name == "" {
+		return fmt.Sprintf("[%s]: %s", w.Section, w.Message)
+	}
+	return fmt.Sprintf("[%s] %s=%s: %s", w.Section, w.Name, w.Value, w.Message)
+}
+
+// Lint deserializes the unit file read from f and checks it like
+// systemd-analyze verify does, see LintOptions. The unit type is taken from
+// name, e.g. "foo.service". An error is only returned if the file cannot be
+// deserialized.
+func Lint(name string, f io.Reader) ([]LintWarning, error) {
+	opts, err := Deserialize(f)
+	if err != nil {
+		return nil, err
+	}
+	return LintOptions(name, opts), nil
+}
+
+// LintOptions checks the options of the unit name against the directives
+// known for its unit type. It reports unknown sections and keys, values
+// which do not parse as the type of their directive, e.g. a boolean or a
+// time span, and settings systemd would refuse to load the unit for, like a
+// service without ExecStart=. Sections and keys starting with "X-" are
+// ignored, and values containing specifiers are not validated.
+func LintOptions(name string, opts []*UnitOption) []LintWarning {
+	var warnings []LintWarning
+
+	_, _, unitType, err := UnitNameSplit(name)
+	if err != nil {
+		return []LintWarning{{Message: fmt.Sprintf("Invalid unit name %q", name)}}
+	}
+	typeSections, ok := lintSections[unitType]
+	if !ok {
+		return []LintWarning{{Message: fmt.Sprintf("Unknown unit type %q", unitType)}}
+	}
+
+	// Like systemd, the unit checks only consider the settings which were
+	// not ignored.
+	var valid []*UnitOption
+	unknownSections := map[string]bool{}
+	for _, opt := range opts {
+		var known map[string]lintValidator
+		switch opt.Section {
+		case "Unit":
+			known = unitDirectives
+		case "Install":
+			known = installDirectives
+		default:
+			known = typeSections[opt.Section]
+		}
+		if known == nil {
+			if !strings.HasPrefix(opt.Section, "X-") && !unknownSections[opt.Section] {
+				unknownSections[opt.Section] = true
+				warnings = append(warnings, LintWarning{
+					Section: opt.Section,
+					Message: fmt.Sprintf("Unknown section '%s'. Ignoring.", opt.Section),
+				})
+			}
+			continue
+		}
+
+		validate, ok := known[opt.Name]
+		if !ok {
+			if !strings.HasPrefix(opt.Name, "X-") {
+				warnings = append(warnings, LintWarning{
+					Section: opt.Section,
+					Name:    opt.Name,
+					Value:   opt.Value,
+					Message: fmt.Sprintf("Unknown key name '%s' in section '%s', ignoring.", opt.Name, opt.Section),
+				})
+			}
+			continue
+		}
+		// An empty value resets the setting, and specifiers are only known
+		// once the unit is loaded.
+		if opt.Value != "" && !strings.Contains(opt.Value, "%") {
+			if err := validate(opt.Value); err != nil {
+				warnings = append(warnings, LintWarning{
+					Section: opt.Section,
+					Name:    opt.Name,
+					Value:   opt.Value,
+					Message: fmt.Sprintf("Failed to parse %s=, ignoring: %v", opt.Name, err),
+				})
+				continue
+			}
+		}
+		valid = append(valid, opt)
+	}
+
+	return append(warnings, lintUnit(name, unitType, valid)...)
+}
+
+// lintUnit reports the settings systemd refuses to load a unit for.
+func lintUnit(name, unitType string, opts []*UnitOption) []LintWarning {
+	refuse := func(msg string) []LintWarning {
+		return []LintWarning{{Message: msg + " Refusing."}}
+	}
+
+	switch unitType {
+	case "service":
+		serviceType := lastValue(opts, "Service", "Type")
+		execStart := len(lintValues(opts, "Service", "ExecStart"))
+		if execStart == 0 && len(lintValues(opts, "Service", "ExecStop")) == 0 && lastValue(opts, "Unit", "SuccessAction") == "" {
+			return refuse("Service has no ExecStart=, ExecStop=, or SuccessAction=.")
+		}
+		if serviceType != "oneshot" && execStart > 1 {
+			return refuse("Service has more than one ExecStart= setting, which is only allowed for Type=oneshot services.")
+		}
+		if serviceType == "oneshot" {
+			switch lastValue(opts, "Service", "Restart") {
+			case "always", "on-success":
+				return refuse("Service has Restart= set to either always or on-success, which isn't allowed for Type=oneshot services.")
+			}
+		}
+	case "socket":
+		if !hasAnyValue(opts, "Socket", "ListenStream", "ListenDatagram", "ListenSequentialPacket", "ListenFIFO",
+			"ListenSpecial", "ListenNetlink", "ListenMessageQueue", "ListenUSBFunction") {
+			return refuse("Unit has no Listen setting (ListenStream=, ListenDatagram=, ListenFIFO=, ...).")
+		}
+	case "timer":
+		if !hasAnyValue(opts, "Timer", "OnActiveSec", "OnBootSec", "OnStartupSec", "OnUnitActiveSec",
+			"OnUnitInactiveSec", "OnCalendar") && lastValue(opts, "Timer", "OnClockChange") == "" &&
+			lastValue(opts, "Timer", "OnTimezoneChange") == "" {
+			return refuse("Timer unit lacks value setting.")
+		}
+	case "path":
+		if !hasAnyValue(opts, "Path", "PathExists", "PathExistsGlob", "PathChanged", "PathModified", "DirectoryNotEmpty") {
+			return refuse("Path unit lacks path setting.")
+		}
+	case "mount", "automount":
+		section := "Mount"
+		if unitType == "automount" {
+			section = "Automount"
+		}
+		where := lastValue(opts, section, "Where")
+		if where != "" && !strings.Contains(where, "%") {
+			if expected, err := UnitNameFromPath(where, "."+unitType); err != nil {
+				return refuse("Failed to generate unit name from mount path.")
+			} else if expected != name {
+				return refuse("Where= setting doesn't match unit name.")
+			}
+		}
+		if unitType == "mount" && lastValue(opts, section, "What") == "" {
+			return refuse("What= setting is missing.")
+		}
+	}
+	return nil
+}
+
+// lintValues returns the values of a list setting, honouring resets by
+// empty assignments.
+func lintValues(opts []*UnitOption, section, name string) []string {
+	var values []string
+	for _, opt := range opts {
+		if opt.Section != section || opt.Name != name {
+			continue
+		}
+		if opt.Value == "" {
+			values = nil
+			continue
+		}
+		values = append(values, opt.Value)
+	}
+	return values
+}
+
+func lastValue(opts []*UnitOption, section, name string) string {
+	var value string
+	for _, opt := range opts {
+		if opt.Section == section && opt.Name == name {
+			value = opt.Value
+		}
+	}
+	return value
+}
+
+func hasAnyValue(opts []*UnitOption, section string, names ...string) bool {
+	for _, name := range names {
+		if len(lintValues(opts, section, name)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// lintValidator checks a non-empty directive value.
+type lintValidator func(string) error
+
+func anyValue(string) error {
+	return nil
+}
+
+func boolValue(s string) error {
+	_, err := parseBool(s)
+	return err
+}
+
+func durationValue(s string) error {
+	_, err := ParseTimeSpan(s)
+	return err
+}
+
+func calendarValue(s string) error {
+	_, err := ParseCalendar(s)
+	return err
+}
+
+func intValue(s string) error {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err
+}
+
+func uintValue(s string) error {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err
+}
+
+// sizeValue accepts a byte size with an optional binary K, M, G, T, P or E
+// suffix, a percentage or "infinity", as the resource control settings do.
+func sizeValue(s string) error {
+	if s == "infinity" {
+		return nil
+	}
+	if strings.HasSuffix(s, "%") {
+		p, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+		if err != nil || p < 0 || p > 100 {
+			return fmt.Errorf("invalid percentage %q", s)
+		}
+		return nil
+	}
+	n := strings.TrimRight(s, "BKMGTPE")
+	if len(s)-len(n) > 1 {
+		return fmt.Errorf("invalid size %q", s)
+	}
+	if _, err := strconv.ParseFloat(n, 64); err != nil || strings.HasPrefix(n, "-") {
+		return fmt.Errorf("invalid size %q", s)
+	}
+	return nil
+}
+
+// weightValue accepts a cgroup weight, between 1 and 10000.
+func weightValue(s string) error {
+	w, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || w < 1 || w > 10000 {
+		return fmt.Errorf("invalid weight %q, must be between 1 and 10000", s)
+	}
+	return nil
+}
+
+func cpuWeightValue(s string) error {
+	if s == "idle" {
+		return nil
+	}
+	return weightValue(s)
+}
+
+// userValue accepts a numeric ID or a user or group name as systemd does in
+// its relaxed mode.
+func userValue(s string) error {
+	if _, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return nil
+	}
+	if len(s) > 31 {
+		return fmt.Errorf("user or group name %q too long", s)
+	}
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_':
+		case i > 0 && (c >= '0' && c <= '9' || c == '-' || c == '.'):
+		case c == '$' && i == len(s)-1 && i > 0:
+		default:
+			return fmt.Errorf("invalid user or group name %q", s)
+		}
+	}
+	return nil
+}
+
+func unitList(s string) error {
+	names, err := SplitWords(s)
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		if !UnitNameIsValid(n) {
+			return fmt.Errorf("invalid unit name %q", n)
+		}
+	}
+	return nil
+}
+
+func enumValue(values ...string) lintValidator {
+	return func(s string) error {
+		for _, v := range values {
+			if s == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value %q, expected one of %s", s, strings.Join(values, ", "))
+	}
+}
+
+// enumOrBool accepts a boolean or one of the given values.
+func enumOrBool(values ...string) lintValidator {
+	enum := enumValue(values...)
+	return func(s string) error {
+		if boolValue(s) == nil {
+			return nil
+		}
+		if len(values) == 0 {
+			return boolValue(s)
+		}
+		return enum(s)
+	}
+}
+
+func outputValue(s string) error {
+	for _, prefix := range []string{"file:", "append:", "truncate:", "fd:"} {
+		if strings.HasPrefix(s, prefix) {
+			if len(s) == len(prefix) {
+				return fmt.Errorf("missing argument in %q", s)
+			}
+			return nil
+		}
+	}
+	return enumValue("inherit", "null", "tty", "journal", "kmsg", "journal+console", "kmsg+console", "socket")(s)
+}
+
+var emergencyAction = enumValue("none", "reboot", "reboot-force", "reboot-immediate", "poweroff",
+	"poweroff-force", "poweroff-immediate", "exit", "exit-force", "soft-reboot", "soft-reboot-force",
+	"kexec", "kexec-force", "halt", "halt-force", "halt-immediate")
+
+var errInvalidBool = errors.New("invalid boolean")
+
+// parseBool parses a boolean the way systemd does, accepting 1, yes, y,
+// true, t and on, or 0, no, n, false, f and off, in any case.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "1", "yes", "y", "true", "t", "on":
+		return true, nil
+	case "0", "no", "n", "false", "f", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("%v %q", errInvalidBool, s)
+}
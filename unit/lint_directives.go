@@ -0,0 +1,275 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+//go:generate go run gen_directives.go
+
+// The directives known to Lint, per section. Sections shared by several unit
+// types, like the execution and resource control settings, are merged into
+// the type specific sections by lintSections.
+//
+// The tables below are maintained by hand and cover systemd 256.
+// systemdDirectives is generated from the configuration items of a systemd
+// binary, currently systemd 252, so it may lack newer directives; those
+// listed only there are accepted without validating their value.
+
+var unitDirectives = withSystemd("Unit", directives(
+	group(anyValue, "Description", "Documentation", "SourcePath", "RebootArgument",
+		"JobTimeoutRebootArgument", "FailureActionExitStatus", "SuccessActionExitStatus"),
+	group(unitList, "Wants", "Requires", "Requisite", "BindsTo", "PartOf", "Upholds", "Conflicts",
+		"Before", "After", "OnFailure", "OnSuccess", "PropagatesReloadTo", "ReloadPropagatedFrom",
+		"PropagatesStopTo", "StopPropagatedFrom", "JoinsNamespaceOf"),
+	group(anyValue, "RequiresMountsFor", "WantsMountsFor"),
+	group(boolValue, "IgnoreOnIsolate", "StopWhenUnneeded", "RefuseManualStart", "RefuseManualStop",
+		"AllowIsolate", "DefaultDependencies", "SurviveFinalKillSignal"),
+	group(enumValue("fail", "replace", "replace-irreversibly", "isolate", "flush", "ignore-dependencies", "ignore-requirements"), "OnFailureJobMode"),
+	group(enumValue("inactive", "inactive-or-failed"), "CollectMode"),
+	group(emergencyAction, "FailureAction", "SuccessAction", "JobTimeoutAction", "StartLimitAction"),
+	group(durationValue, "JobTimeoutSec", "JobRunningTimeoutSec", "StartLimitIntervalSec"),
+	group(uintValue, "StartLimitBurst"),
+	conditions("Condition"),
+	conditions("Assert"),
+))
+
+var installDirectives = withSystemd("Install", directives(
+	group(unitList, "Alias", "WantedBy", "RequiredBy", "UpheldBy", "Also"),
+	group(anyValue, "DefaultInstance"),
+))
+
+var execDirectives = directives(
+	group(anyValue, "WorkingDirectory", "RootDirectory", "RootImage", "RootImageOptions", "RootHash",
+		"RootVerity", "ExtensionImages", "ExtensionDirectories", "PAMName",
+		"Environment", "EnvironmentFile", "PassEnvironment", "UnsetEnvironment", "SupplementaryGroups",
+		"StandardInputText", "StandardInputData", "SyslogIdentifier", "SyslogFacility", "SyslogLevel",
+		"LogLevelMax", "LogExtraFields", "LogNamespace", "LogFilterPatterns", "UMask", "CPUAffinity",
+		"NUMAPolicy", "NUMAMask", "CapabilityBoundingSet", "AmbientCapabilities", "SecureBits",
+		"SystemCallFilter", "SystemCallArchitectures", "SystemCallErrorNumber", "SystemCallLog",
+		"RestrictAddressFamilies", "RestrictFileSystems", "RestrictNetworkInterfaces", "RestrictNamespaces",
+		"ReadWritePaths", "ReadOnlyPaths", "InaccessiblePaths", "ExecPaths", "NoExecPaths",
+		"BindPaths", "BindReadOnlyPaths", "TemporaryFileSystem", "MountImages", "RuntimeDirectory",
+		"StateDirectory", "CacheDirectory", "LogsDirectory", "ConfigurationDirectory",
+		"RuntimeDirectoryMode", "StateDirectoryMode", "CacheDirectoryMode", "LogsDirectoryMode",
+		"ConfigurationDirectoryMode", "Personality", "LoadCredential", "LoadCredentialEncrypted",
+		"SetCredential", "SetCredentialEncrypted", "ImportCredential", "TTYPath", "UtmpIdentifier",
+		"UtmpMode", "SELinuxContext", "AppArmorProfile", "SmackProcessLabel", "NetworkNamespacePath",
+		"IPCNamespacePath", "CoredumpFilter", "Nice", "OOMScoreAdjust", "CPUSchedulingPriority",
+		"IOSchedulingPriority", "TimerSlackNSec"),
+	group(userValue, "User", "Group"),
+	group(boolValue, "DynamicUser", "TTYReset", "TTYVHangup", "TTYVTDisallocate", "IgnoreSIGPIPE",
+		"PrivateTmp", "PrivateDevices", "PrivateNetwork", "PrivateIPC", "PrivateMounts", "PrivateUsers",
+		"ProtectKernelTunables", "ProtectKernelModules", "ProtectKernelLogs", "ProtectControlGroups",
+		"ProtectClock", "ProtectHostname", "NoNewPrivileges", "RestrictRealtime", "RestrictSUIDSGID",
+		"LockPersonality", "MemoryDenyWriteExecute", "RemoveIPC", "CPUSchedulingResetOnFork",
+		"SyslogLevelPrefix", "MountAPIVFS"),
+	group(enumOrBool("read-only", "tmpfs"), "ProtectHome"),
+	group(enumOrBool("full", "strict"), "ProtectSystem"),
+	group(enumValue("null", "tty", "tty-force", "tty-fail", "data", "socket"), "StandardInput"),
+	group(outputValue, "StandardOutput", "StandardError"),
+	group(enumValue("other", "batch", "idle", "fifo", "rr"), "CPUSchedulingPolicy"),
+	group(enumValue("realtime", "best-effort", "idle", "none", "0", "1", "2", "3"), "IOSchedulingClass"),
+	group(enumValue("inherit", "private", "shared"), "KeyringMode"),
+	group(enumValue("noaccess", "invisible", "ptraceable", "default"), "ProtectProc"),
+	group(enumValue("all", "pid"), "ProcSubset"),
+	group(enumValue("shared", "slave", "private"), "MountFlags"),
+	group(enumOrBool("restart"), "RuntimeDirectoryPreserve"),
+	group(durationValue, "LogRateLimitIntervalSec"),
+	group(uintValue, "LogRateLimitBurst"),
+	group(anyValue, "LimitCPU", "LimitFSIZE", "LimitDATA", "LimitSTACK", "LimitCORE", "LimitRSS",
+		"LimitNOFILE", "LimitAS", "LimitNPROC", "LimitMEMLOCK", "LimitLOCKS", "LimitSIGPENDING",
+		"LimitMSGQUEUE", "LimitNICE", "LimitRTPRIO", "LimitRTTIME"),
+)
+
+var killDirectives = directives(
+	group(enumValue("control-group", "mixed", "process", "none"), "KillMode"),
+	group(anyValue, "KillSignal", "RestartKillSignal", "FinalKillSignal", "WatchdogSignal"),
+	group(boolValue, "SendSIGKILL", "SendSIGHUP"),
+)
+
+var cgroupDirectives = directives(
+	group(boolValue, "CPUAccounting", "MemoryAccounting", "TasksAccounting", "IOAccounting",
+		"IPAccounting", "MemoryZSwapWriteback", "BlockIOAccounting"),
+	group(cpuWeightValue, "CPUWeight", "StartupCPUWeight"),
+	group(weightValue, "IOWeight", "StartupIOWeight"),
+	group(anyValue, "CPUQuota", "AllowedCPUs", "StartupAllowedCPUs", "AllowedMemoryNodes",
+		"StartupAllowedMemoryNodes", "IODeviceWeight", "IOReadBandwidthMax", "IOWriteBandwidthMax",
+		"IOReadIOPSMax", "IOWriteIOPSMax", "IODeviceLatencyTargetSec", "IPAddressAllow", "IPAddressDeny",
+		"IPIngressFilterPath", "IPEgressFilterPath", "BPFProgram", "SocketBindAllow", "SocketBindDeny",
+		"DeviceAllow", "DisableControllers", "ManagedOOMMemoryPressureLimit", "NFTSet",
+		"CPUShares", "StartupCPUShares", "BlockIOWeight", "StartupBlockIOWeight",
+		"BlockIODeviceWeight", "BlockIOReadBandwidth", "BlockIOWriteBandwidth", "MemoryLimit"),
+	group(durationValue, "CPUQuotaPeriodSec", "ManagedOOMMemoryPressureDurationSec"),
+	group(sizeValue, "MemoryMin", "MemoryLow", "StartupMemoryLow", "MemoryHigh", "StartupMemoryHigh",
+		"MemoryMax", "StartupMemoryMax", "MemorySwapMax", "StartupMemorySwapMax", "MemoryZSwapMax",
+		"StartupMemoryZSwapMax", "TasksMax"),
+	group(enumValue("auto", "closed", "strict"), "DevicePolicy"),
+	group(unitList, "Slice"),
+	group(enumOrBool(), "Delegate"),
+	group(anyValue, "DelegateSubgroup"),
+	group(enumValue("auto", "kill"), "ManagedOOMSwap", "ManagedOOMMemoryPressure"),
+	group(enumValue("none", "avoid", "omit"), "ManagedOOMPreference"),
+	group(enumOrBool("auto", "skip"), "MemoryPressureWatch"),
+	group(durationValue, "MemoryPressureThresholdSec"),
+)
+
+var serviceDirectives = directives(
+	group(enumValue("simple", "exec", "forking", "oneshot", "dbus", "notify", "notify-reload", "idle"), "Type"),
+	group(enumValue("main", "cgroup"), "ExitType"),
+	group(boolValue, "RemainAfterExit", "GuessMainPID", "RootDirectoryStartOnly", "NonBlocking",
+		"PermissionsStartOnly"),
+	group(anyValue, "PIDFile", "BusName", "ExecCondition", "ExecStartPre", "ExecStart", "ExecStartPost",
+		"ExecReload", "ExecStop", "ExecStopPost", "SuccessExitStatus", "RestartPreventExitStatus",
+		"RestartForceExitStatus", "Sockets", "USBFunctionDescriptors", "USBFunctionStrings",
+		"OpenFile", "ReloadSignal", "FileDescriptorStorePreserve"),
+	group(durationValue, "RestartSec", "RestartMaxDelaySec", "TimeoutStartSec", "TimeoutStopSec",
+		"TimeoutAbortSec", "TimeoutSec", "RuntimeMaxSec", "RuntimeRandomizedExtraSec", "WatchdogSec"),
+	group(uintValue, "RestartSteps", "FileDescriptorStoreMax"),
+	group(enumValue("terminate", "abort", "kill"), "TimeoutStartFailureMode", "TimeoutStopFailureMode"),
+	group(enumValue("no", "on-success", "on-failure", "on-abnormal", "on-watchdog", "on-abort", "always"), "Restart"),
+	group(enumValue("normal", "direct"), "RestartMode"),
+	group(enumValue("none", "main", "exec", "all"), "NotifyAccess"),
+	group(enumValue("continue", "stop", "kill"), "OOMPolicy"),
+)
+
+var socketDirectives = directives(
+	group(anyValue, "ListenStream", "ListenDatagram", "ListenSequentialPacket", "ListenFIFO",
+		"ListenSpecial", "ListenNetlink", "ListenMessageQueue", "ListenUSBFunction", "SocketProtocol",
+		"BindToDevice", "SocketMode", "DirectoryMode", "IPTOS", "IPTTL", "Mark", "SmackLabel",
+		"SmackLabelIPIn", "SmackLabelIPOut", "Timestamping", "TCPCongestion", "ExecStartPre",
+		"ExecStartPost", "ExecStopPre", "ExecStopPost", "Symlinks", "FileDescriptorName",
+		"Priority", "MessageQueueMaxMessages", "MessageQueueMessageSize", "KeepAliveProbes"),
+	group(userValue, "SocketUser", "SocketGroup"),
+	group(enumValue("default", "both", "ipv6-only"), "BindIPv6Only"),
+	group(uintValue, "Backlog", "MaxConnections", "MaxConnectionsPerSource", "TriggerLimitBurst",
+		"PollLimitBurst"),
+	group(boolValue, "Accept", "Writable", "FlushPending", "KeepAlive", "NoDelay", "ReusePort",
+		"SELinuxContextFromNet", "FreeBind", "Transparent", "Broadcast", "PassCredentials",
+		"PassSecurity", "PassPacketInfo", "PassFileDescriptorsToExec", "RemoveOnStop"),
+	group(durationValue, "KeepAliveTimeSec", "KeepAliveIntervalSec", "DeferAcceptSec",
+		"TriggerLimitIntervalSec", "PollLimitIntervalSec"),
+	group(durationValue, "TimeoutSec"),
+	group(sizeValue, "ReceiveBuffer", "SendBuffer", "PipeSize"),
+	group(unitList, "Service"),
+)
+
+var timerDirectives = directives(
+	group(durationValue, "OnActiveSec", "OnBootSec", "OnStartupSec", "OnUnitActiveSec",
+		"OnUnitInactiveSec", "AccuracySec", "RandomizedDelaySec"),
+	group(calendarValue, "OnCalendar"),
+	group(boolValue, "FixedRandomDelay", "OnClockChange", "OnTimezoneChange", "Persistent",
+		"WakeSystem", "RemainAfterElapse"),
+	group(unitList, "Unit"),
+)
+
+var mountDirectives = directives(
+	group(anyValue, "What", "Where", "Type", "Options", "DirectoryMode"),
+	group(boolValue, "SloppyOptions", "LazyUnmount", "ReadWriteOnly", "ForceUnmount"),
+	group(durationValue, "TimeoutSec"),
+)
+
+var automountDirectives = directives(
+	group(anyValue, "Where", "ExtraOptions", "DirectoryMode"),
+	group(durationValue, "TimeoutIdleSec"),
+)
+
+var swapDirectives = directives(
+	group(anyValue, "What", "Options"),
+	group(intValue, "Priority"),
+	group(durationValue, "TimeoutSec"),
+)
+
+var pathDirectives = directives(
+	group(anyValue, "PathExists", "PathExistsGlob", "PathChanged", "PathModified",
+		"DirectoryNotEmpty", "DirectoryMode"),
+	group(unitList, "Unit"),
+	group(boolValue, "MakeDirectory"),
+	group(durationValue, "TriggerLimitIntervalSec"),
+	group(uintValue, "TriggerLimitBurst"),
+)
+
+var scopeDirectives = directives(
+	group(enumValue("continue", "stop", "kill"), "OOMPolicy"),
+	group(durationValue, "RuntimeMaxSec", "RuntimeRandomizedExtraSec", "TimeoutStopSec"),
+)
+
+// lintSections maps unit types to the directives of their sections.
+var lintSections = map[string]map[string]map[string]lintValidator{
+	"service":   {"Service": withSystemd("Service", merge(serviceDirectives, execDirectives, killDirectives, cgroupDirectives))},
+	"socket":    {"Socket": withSystemd("Socket", merge(socketDirectives, execDirectives, killDirectives, cgroupDirectives))},
+	"mount":     {"Mount": withSystemd("Mount", merge(mountDirectives, execDirectives, killDirectives, cgroupDirectives))},
+	"swap":      {"Swap": withSystemd("Swap", merge(swapDirectives, execDirectives, killDirectives, cgroupDirectives))},
+	"scope":     {"Scope": withSystemd("Scope", merge(scopeDirectives, killDirectives, cgroupDirectives))},
+	"slice":     {"Slice": withSystemd("Slice", merge(cgroupDirectives))},
+	"timer":     {"Timer": withSystemd("Timer", merge(timerDirectives))},
+	"automount": {"Automount": withSystemd("Automount", merge(automountDirectives))},
+	"path":      {"Path": withSystemd("Path", merge(pathDirectives))},
+	"target":    {},
+	"device":    {},
+}
+
+var conditionNames = []string{"Architecture", "Firmware", "Virtualization", "Host",
+	"KernelCommandLine", "KernelVersion", "Credential", "Environment", "Security", "Capability",
+	"ACPower", "NeedsUpdate", "FirstBoot", "PathExists", "PathExistsGlob", "PathIsDirectory",
+	"PathIsSymbolicLink", "PathIsMountPoint", "PathIsReadWrite", "PathIsEncrypted",
+	"DirectoryNotEmpty", "FileNotEmpty", "FileIsExecutable", "User", "Group",
+	"ControlGroupController", "Memory", "CPUs", "CPUFeature", "OSRelease", "MemoryPressure",
+	"CPUPressure", "IOPressure"}
+
+type directiveGroup struct {
+	validator lintValidator
+	names     []string
+}
+
+func group(v lintValidator, names ...string) directiveGroup {
+	return directiveGroup{v, names}
+}
+
+func conditions(prefix string) directiveGroup {
+	names := make([]string, 0, len(conditionNames))
+	for _, n := range conditionNames {
+		names = append(names, prefix+n)
+	}
+	return group(anyValue, names...)
+}
+
+func directives(groups ...directiveGroup) map[string]lintValidator {
+	m := map[string]lintValidator{}
+	for _, g := range groups {
+		for _, n := range g.names {
+			m[n] = g.validator
+		}
+	}
+	return m
+}
+
+// withSystemd adds the directives of section in systemdDirectives missing
+// from m, without validating their value.
+func withSystemd(section string, m map[string]lintValidator) map[string]lintValidator {
+	for _, n := range systemdDirectives[section] {
+		if _, ok := m[n]; !ok {
+			m[n] = anyValue
+		}
+	}
+	return m
+}
+
+func merge(sets ...map[string]lintValidator) map[string]lintValidator {
+	m := map[string]lintValidator{}
+	for _, s := range sets {
+		for k, v := range s {
+			m[k] = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		warnings []string
+	}{
+		// a valid service
+		{
+			"foo.service",
+			`[Unit]
+Description=Foo
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=notify
+ExecStart=/usr/bin/foo --bar
+User=foo
+Restart=on-failure
+RestartSec=5s
+MemoryMax=512M
+CPUWeight=100
+ProtectSystem=strict
+TimeoutStopSec=infinity
+X-Foo=bar
+
+[Install]
+WantedBy=multi-user.target
+
+[X-Custom]
+Anything=goes
+`,
+			nil,
+		},
+		// directives only known from systemd's configuration items
+		{
+			"foo.service",
+			`[Unit]
+JobTimeoutRebootArgument=1
+BindTo=bar.service
+StartLimitInterval=10s
+
+[Service]
+ExecStart=/usr/bin/foo
+MemoryLimit=1G
+BlockIOWeight=500
+`,
+			nil,
+		},
+		// unknown sections and keys, and keys in the wrong section
+		{
+			"foo.service",
+			`[Unit]
+ExecStart=/usr/bin/foo
+
+[Service]
+ExecStart=/usr/bin/foo
+Frobnicate=yes
+
+[Timer]
+OnCalendar=daily
+`,
+			[]string{
+				"[Unit] ExecStart=/usr/bin/foo: Unknown key name 'ExecStart' in section 'Unit', ignoring.",
+				"[Service] Frobnicate=yes: Unknown key name 'Frobnicate' in section 'Service', ignoring.",
+				"[Timer]: Unknown section 'Timer'. Ignoring.",
+			},
+		},
+		// invalid values
+		{
+			"foo.service",
+			`[Unit]
+After=not a unit
+StartLimitBurst=-1
+
+[Service]
+ExecStart=/usr/bin/foo
+Type=simpel
+RemainAfterExit=maybe
+IOWeight=0
+MemoryHigh=12X
+User=-foo
+TimeoutStartSec=5 minutes and a bit
+`,
+			[]string{
+				`[Unit] After=not a unit: Failed to parse After=, ignoring: invalid unit name "not"`,
+				`[Unit] StartLimitBurst=-1: Failed to parse StartLimitBurst=, ignoring: strconv.ParseUint: parsing "-1": invalid syntax`,
+				`[Service] Type=simpel: Failed to parse Type=, ignoring: invalid value "simpel", expected one of simple, exec, forking, oneshot, dbus, notify, notify-reload, idle`,
+				`[Service] RemainAfterExit=maybe: Failed to parse RemainAfterExit=, ignoring: invalid boolean "maybe"`,
+				`[Service] IOWeight=0: Failed to parse IOWeight=, ignoring: invalid weight "0", must be between 1 and 10000`,
+				`[Service] MemoryHigh=12X: Failed to parse MemoryHigh=, ignoring: invalid size "12X"`,
+				`[Service] User=-foo: Failed to parse User=, ignoring: invalid user or group name "-foo"`,
+				`[Service] TimeoutStartSec=5 minutes and a bit: Failed to parse TimeoutStartSec=, ignoring: invalid time span "5 minutes and a bit"`,
+			},
+		},
+		// empty assignments and specifiers are not validated
+		{
+			"foo@.service",
+			`[Service]
+ExecStart=/usr/bin/foo %i
+User=%i
+IOWeight=
+`,
+			nil,
+		},
+		// services need something to run
+		{
+			"foo.service",
+			`[Service]
+ExecStart=/usr/bin/foo
+ExecStart=
+`,
+			[]string{"Service has no ExecStart=, ExecStop=, or SuccessAction=. Refusing."},
+		},
+		{
+			"foo.service",
+			`[Service]
+ExecStart=/usr/bin/foo
+ExecStart=/usr/bin/bar
+`,
+			[]string{"Service has more than one ExecStart= setting, which is only allowed for Type=oneshot services. Refusing."},
+		},
+		{
+			"foo.service",
+			`[Service]
+Type=oneshot
+ExecStart=/usr/bin/foo
+ExecStart=/usr/bin/bar
+Restart=always
+`,
+			[]string{"Service has Restart= set to either always or on-success, which isn't allowed for Type=oneshot services. Refusing."},
+		},
+		// other unit types
+		{
+			"foo.timer",
+			`[Timer]
+OnCalendar=Mon..Fri 25:00
+Persistent=true
+`,
+			[]string{
+				`[Timer] OnCalendar=Mon..Fri 25:00: Failed to parse OnCalendar=, ignoring: invalid calendar spec "Mon..Fri 25:00"`,
+				"Timer unit lacks value setting. Refusing.",
+			},
+		},
+		{
+			"foo.socket",
+			`[Socket]
+Accept=yes
+`,
+			[]string{"Unit has no Listen setting (ListenStream=, ListenDatagram=, ListenFIFO=, ...). Refusing."},
+		},
+		{
+			"foo.path",
+			`[Path]
+Unit=foo.service
+`,
+			[]string{"Path unit lacks path setting. Refusing."},
+		},
+		{
+			"var-lib-foo.mount",
+			`[Mount]
+What=/dev/sda1
+Where=/var/lib/foo
+Type=ext4
+`,
+			nil,
+		},
+		{
+			"var-lib-foo.mount",
+			`[Mount]
+What=/dev/sda1
+Where=/var/lib/bar
+`,
+			[]string{"Where= setting doesn't match unit name. Refusing."},
+		},
+		{
+			"foo.slice",
+			`[Slice]
+CPUWeight=idle
+TasksMax=50%
+`,
+			nil,
+		},
+		{
+			"foo.bar",
+			``,
+			[]string{`Invalid unit name "foo.bar"`},
+		},
+	}
+
+	for i, tt := range tests {
+		warnings, err := Lint(tt.name, strings.NewReader(tt.contents))
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		var output []string
+		for _, w := range warnings {
+			output = append(output, w.String())
+		}
+		if !reflect.DeepEqual(tt.warnings, output) {
+			t.Errorf("case %d: incorrect warnings", i)
+			t.Logf("Expected:")
+			for _, w := range tt.warnings {
+				t.Logf("\t%s", w)
+			}
+			t.Logf("Actual:")
+			for _, w := range output {
+				t.Logf("\t%s", w)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnitNameSplit splits a unit name into its prefix, instance and type, e.g.
+// foo, bar and service for foo@bar.service. The instance is empty for
+// non-template units and templates.
+func UnitNameSplit(name string) (prefix, instance, unitType string, err error) {
+	if !UnitNameIsValid(name) {
+		return "", "", "", fmt.Errorf("invalid unit name %q", name)
+	}
+	dot := strings.LastIndexByte(name, '.')
+	prefix, unitType = name[:dot], name[dot+1:]
+	if at := strings.IndexByte(prefix, '@'); at >= 0 {
+		prefix, instance = prefix[:at], prefix[at+1:]
+	}
+	return prefix, instance, unitType, nil
+}
+
+// UnitNameIsTemplate returns whether name is a template like foo@.service.
+func UnitNameIsTemplate(name string) bool {
+	return UnitNameIsValid(name) && strings.Contains(name, "@.")
+}
+
+// UnitNameIsInstance returns whether name is an instance of a template like
+// foo@bar.service.
+func UnitNameIsInstance(name string) bool {
+	return UnitNameIsValid(name) && strings.Contains(name, "@") && !strings.Contains(name, "@.")
+}
+
+// UnitNameInstantiate returns the instance of a template unit, e.g.
+// foo@bar.service for foo@.service and bar. The instance is used verbatim;
+// use UnitNameEscapeTemplate to escape arbitrary strings.
+func UnitNameInstantiate(template, instance string) (string, error) {
+	if !UnitNameIsTemplate(template) {
+		return "", fmt.Errorf("invalid template unit name %q", template)
+	}
+	at := strings.IndexByte(template, '@')
+	name := template[:at+1] + instance + template[at+1:]
+	if instance == "" || !UnitNameIsValid(name) {
+		return "", fmt.Errorf("invalid instance %q for template %s", instance, template)
+	}
+	return name, nil
+}
+
+// UnitNameTemplate returns the template an instance was created from, e.g.
+// foo@.service for foo@bar.service.
+func UnitNameTemplate(name string) (string, error) {
+	prefix, instance, unitType, err := UnitNameSplit(name)
+	if err != nil {
+		return "", err
+	}
+	if instance == "" {
+		return "", fmt.Errorf("unit %s is not a template instance", name)
+	}
+	return prefix + "@." + unitType, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"testing"
+)
+
+func TestUnitNameSplit(t *testing.T) {
+	tests := []struct {
+		in       string
+		prefix   string
+		instance string
+		unitType string
+	}{
+		{"foo.service", "foo", "", "service"},
+		{"foo@.service", "foo", "", "service"},
+		{"getty@tty1.service", "getty", "tty1", "service"},
+		{"dev-sda1.device", "dev-sda1", "", "device"},
+	}
+
+	for i, tt := range tests {
+		prefix, instance, unitType, err := UnitNameSplit(tt.in)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if prefix != tt.prefix || instance != tt.instance || unitType != tt.unitType {
+			t.Errorf("case %d: expected %q %q %q, got %q %q %q", i, tt.prefix, tt.instance, tt.unitType, prefix, instance, unitType)
+		}
+	}
+
+	if _, _, _, err := UnitNameSplit("foo"); err == nil {
+		t.Error("expected error for invalid unit name")
+	}
+}
+
+func TestUnitNameInstantiate(t *testing.T) {
+	name, err := UnitNameInstantiate("getty@.service", "tty1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "getty@tty1.service" {
+		t.Errorf("unexpected unit name %q", name)
+	}
+	if !UnitNameIsInstance(name) || UnitNameIsTemplate(name) {
+		t.Errorf("%s is not an instance", name)
+	}
+
+	template, err := UnitNameTemplate(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if template != "getty@.service" || !UnitNameIsTemplate(template) {
+		t.Errorf("unexpected template %q", template)
+	}
+
+	for _, tt := range [][2]string{
+		{"getty.service", "tty1"},
+		{"getty@.service", ""},
+		{"getty@.service", "tty 1"},
+	} {
+		if _, err := UnitNameInstantiate(tt[0], tt[1]); err == nil {
+			t.Errorf("expected error instantiating %q with %q", tt[0], tt[1])
+		}
+	}
+	if _, err := UnitNameTemplate("getty.service"); err == nil {
+		t.Error("expected error for non-instance")
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SystemUnitPaths are the directories the system manager loads units from,
+// in order of precedence.
+var SystemUnitPaths = []string{
+	"/etc/systemd/system.control",
+	"/run/systemd/system.control",
+	"/run/systemd/transient",
+	"/run/systemd/generator.early",
+	"/etc/systemd/system",
+	"/etc/systemd/system.attached",
+	"/run/systemd/system",
+	"/run/systemd/system.attached",
+	"/run/systemd/generator",
+	"/usr/local/lib/systemd/system",
+	"/usr/lib/systemd/system",
+	"/run/systemd/generator.late",
+}
+
+// EffectiveOption is an option of the effective configuration of a unit,
+// along with the path of the file which set it.
+type EffectiveOption struct {
+	UnitOption
+	Path string
+}
+
+// ResolvedUnit is the effective configuration of a unit, merged from its
+// fragment and drop-ins.
+type ResolvedUnit struct {
+	Name string
+	// FragmentPath is the path of the unit file, or of the template unit
+	// file for instances without their own file. It is empty for units only
+	// configured by drop-ins.
+	FragmentPath string
+	// Masked is set if the unit file is a symlink to /dev/null, in which
+	// case no options are loaded.
+	Masked bool
+	// DropInPaths are the drop-ins applied, in the order they were applied.
+	DropInPaths []string
+	Options     []*EffectiveOption
+}
+
+// Values returns the effective values of a setting.
+func (u *ResolvedUnit) Values(section, name string) []string {
+	var values []string
+	for _, opt := range u.Options {
+		if opt.Section == section && opt.Name == name {
+			values = append(values, opt.Value)
+		}
+	}
+	return values
+}
+
+// Value returns the effective value of a setting, the last one for list
+// settings. The boolean is false if the setting is not set.
+func (u *ResolvedUnit) Value(section, name string) (string, bool) {
+	values := u.Values(section, name)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// ResolveUnit finds the unit file and drop-ins of the unit name in the given
+// search paths, which are in order of precedence like SystemUnitPaths, and
+// merges them the way systemd does.
+//
+// The fragment is the first file named like the unit, or like its template
+// for instances. Drop-ins are the .conf files in the .d directories of the
+// unit, its template, each of its "-" separated name prefixes (e.g.
+// foo-.service.d for foo-bar.service) and its type (service.d). They are
+// applied in lexicographic order of their file names; a drop-in shadows the
+// ones with the same name in directories of lower precedence, and is ignored
+// if it is a symlink to /dev/null.
+//
+// Later assignments override earlier ones, list settings accumulate their
+// values and empty assignments reset a setting, except for dependencies
+// which can only be added to.
+func ResolveUnit(name string, searchPaths []string) (*ResolvedUnit, error) {
+	prefix, instance, unitType, err := UnitNameSplit(name)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{name}
+	if instance != "" {
+		names = append(names, prefix+"@."+unitType)
+	}
+
+	u := &ResolvedUnit{Name: name}
+	for _, n := range names {
+		for _, dir := range searchPaths {
+			p := filepath.Join(dir, n)
+			if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+				u.FragmentPath = p
+				break
+			}
+		}
+		if u.FragmentPath != "" {
+			break
+		}
+	}
+
+	dropInNames := append([]string(nil), names...)
+	for i := strings.LastIndexByte(prefix, '-'); i > 0; i = strings.LastIndexByte(prefix[:i], '-') {
+		dropInNames = append(dropInNames, prefix[:i+1]+"."+unitType)
+	}
+	dropInNames = append(dropInNames, unitType)
+	dropIns, err := findDropIns(searchPaths, dropInNames)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.FragmentPath == "" && len(dropIns) == 0 {
+		return nil, fmt.Errorf("unit %s not found", name)
+	}
+
+	if u.FragmentPath != "" {
+		if isNullLink(u.FragmentPath) {
+			u.Masked = true
+			return u, nil
+		}
+		if err := u.apply(u.FragmentPath); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range dropIns {
+		if err := u.apply(p); err != nil {
+			return nil, err
+		}
+		u.DropInPaths = append(u.DropInPaths, p)
+	}
+
+	return u, nil
+}
+
+// findDropIns returns the drop-ins for the given unit names, sorted by file
+// name. Of drop-ins with the same name, the first found wins.
+func findDropIns(searchPaths, names []string) ([]string, error) {
+	found := map[string]string{}
+	for _, dir := range searchPaths {
+		for _, n := range names {
+			entries, err := ioutil.ReadDir(filepath.Join(dir, n+".d"))
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				if !strings.HasSuffix(e.Name(), ".conf") || e.IsDir() {
+					continue
+				}
+				if _, ok := found[e.Name()]; !ok {
+					found[e.Name()] = filepath.Join(dir, n+".d", e.Name())
+				}
+			}
+		}
+	}
+
+	confs := make([]string, 0, len(found))
+	for conf, p := range found {
+		if !isNullLink(p) {
+			confs = append(confs, conf)
+		}
+	}
+	sort.Strings(confs)
+
+	paths := make([]string, 0, len(confs))
+	for _, conf := range confs {
+		paths = append(paths, found[conf])
+	}
+	return paths, nil
+}
+
+func isNullLink(p string) bool {
+	target, err := filepath.EvalSymlinks(p)
+	return err == nil && target == os.DevNull
+}
+
+func (u *ResolvedUnit) apply(p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	opts, err := Deserialize(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %v", p, err)
+	}
+
+	for _, opt := range opts {
+		switch {
+		case opt.Value == "" && dependencySettings[opt.Name]:
+			continue
+		case opt.Value == "":
+			u.remove(opt.Section, opt.Name)
+			continue
+		case !IsListSetting(opt.Name):
+			u.remove(opt.Section, opt.Name)
+		}
+		u.Options = append(u.Options, &EffectiveOption{*opt, p})
+	}
+	return nil
+}
+
+func (u *ResolvedUnit) remove(section, name string) {
+	opts := u.Options[:0]
+	for _, opt := range u.Options {
+		if opt.Section != section || opt.Name != name {
+			opts = append(opts, opt)
+		}
+	}
+	u.Options = opts
+}
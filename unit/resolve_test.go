@@ -0,0 +1,146 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeUnitFiles(t *testing.T, root string, files map[string]string) {
+	for p, contents := range files {
+		p = filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if contents == os.DevNull {
+			if err := os.Symlink(os.DevNull, p); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := ioutil.WriteFile(p, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestResolveUnit(t *testing.T) {
+	root, err := ioutil.TempDir("", "go-systemd-resolve")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	writeUnitFiles(t, root, map[string]string{
+		"usr/foo-bar@.service": `[Unit]
+Description=Foo %i
+After=network.target
+
+[Service]
+ExecStart=/usr/bin/foo
+ExecStartPre=/usr/bin/true
+Environment=A=1
+Restart=no
+`,
+		"usr/foo-bar@.service.d/10-env.conf": `[Service]
+Environment=B=2
+`,
+		"usr/foo-bar@.service.d/20-masked.conf": `[Service]
+Restart=always
+`,
+		"usr/foo-.service.d/30-prefix.conf": `[Service]
+Nice=5
+`,
+		"usr/service.d/40-type.conf": `[Unit]
+After=
+After=time-sync.target
+`,
+		"etc/foo-bar@.service.d/10-env.conf": `[Service]
+Environment=C=3
+`,
+		"etc/foo-bar@.service.d/20-masked.conf": os.DevNull,
+		"etc/foo-bar@baz.service.d/50-instance.conf": `[Service]
+ExecStart=
+ExecStart=/usr/bin/foo --baz
+ExecStartPre=
+Restart=on-failure
+`,
+		"etc/other.service": os.DevNull,
+	})
+
+	paths := []string{filepath.Join(root, "etc"), filepath.Join(root, "usr")}
+	u, err := ResolveUnit("foo-bar@baz.service", paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.FragmentPath != filepath.Join(root, "usr/foo-bar@.service") {
+		t.Errorf("unexpected fragment path %s", u.FragmentPath)
+	}
+	expectedDropIns := []string{
+		filepath.Join(root, "etc/foo-bar@.service.d/10-env.conf"),
+		filepath.Join(root, "usr/foo-.service.d/30-prefix.conf"),
+		filepath.Join(root, "usr/service.d/40-type.conf"),
+		filepath.Join(root, "etc/foo-bar@baz.service.d/50-instance.conf"),
+	}
+	if !reflect.DeepEqual(expectedDropIns, u.DropInPaths) {
+		t.Errorf("unexpected drop-ins %v", u.DropInPaths)
+	}
+
+	tests := []struct {
+		section string
+		name    string
+		values  []string
+		path    string
+	}{
+		{"Unit", "Description", []string{"Foo %i"}, "usr/foo-bar@.service"},
+		{"Unit", "After", []string{"network.target", "time-sync.target"}, "usr/service.d/40-type.conf"},
+		{"Service", "ExecStart", []string{"/usr/bin/foo --baz"}, "etc/foo-bar@baz.service.d/50-instance.conf"},
+		{"Service", "ExecStartPre", nil, ""},
+		{"Service", "Environment", []string{"A=1", "C=3"}, "etc/foo-bar@.service.d/10-env.conf"},
+		{"Service", "Restart", []string{"on-failure"}, "etc/foo-bar@baz.service.d/50-instance.conf"},
+		{"Service", "Nice", []string{"5"}, "usr/foo-.service.d/30-prefix.conf"},
+	}
+	for i, tt := range tests {
+		if values := u.Values(tt.section, tt.name); !reflect.DeepEqual(tt.values, values) {
+			t.Errorf("case %d: expected %v, got %v", i, tt.values, values)
+		}
+		var path string
+		for _, opt := range u.Options {
+			if opt.Section == tt.section && opt.Name == tt.name {
+				path = opt.Path
+			}
+		}
+		if tt.path != "" && path != filepath.Join(root, tt.path) {
+			t.Errorf("case %d: expected %s=%v set by %s, got %s", i, tt.name, tt.values, tt.path, path)
+		}
+	}
+
+	u, err = ResolveUnit("other.service", paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !u.Masked || len(u.Options) != 0 {
+		t.Errorf("expected other.service to be masked, got %+v", u)
+	}
+
+	if _, err := ResolveUnit("missing.socket", paths); err == nil {
+		t.Error("expected error for missing unit")
+	}
+}
@@ -42,3 +42,25 @@ func (s *UnitSection) String() string {
 	result += "}"
 	return result
 }
+
+// Values returns the values of all entries with the given name, in order.
+func (s *UnitSection) Values(name string) []string {
+	values := []string{}
+	for _, e := range s.Entries {
+		if e.Name == name {
+			values = append(values, e.Value)
+		}
+	}
+	return values
+}
+
+// Value returns the value of the last entry with the given name, which is
+// the one systemd uses for settings that take a single value.
+func (s *UnitSection) Value(name string) (string, bool) {
+	for i := len(s.Entries) - 1; i >= 0; i-- {
+		if s.Entries[i].Name == name {
+			return s.Entries[i].Value, true
+		}
+	}
+	return "", false
+}
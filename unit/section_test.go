@@ -0,0 +1,47 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSectionValues(t *testing.T) {
+	sections, err := DeserializeSections(strings.NewReader(`[Service]
+Environment=A=1
+Type=simple
+Environment=B=2
+Type=oneshot
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sections[0]
+
+	if values := s.Values("Environment"); !reflect.DeepEqual(values, []string{"A=1", "B=2"}) {
+		t.Errorf("unexpected Environment values %q", values)
+	}
+	if values := s.Values("ExecStart"); len(values) != 0 {
+		t.Errorf("unexpected ExecStart values %q", values)
+	}
+	if value, ok := s.Value("Type"); !ok || value != "oneshot" {
+		t.Errorf("expected Type oneshot, got %q", value)
+	}
+	if _, ok := s.Value("ExecStart"); ok {
+		t.Error("unexpected ExecStart value")
+	}
+}
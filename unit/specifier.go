@@ -0,0 +1,288 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// SpecifierContext holds the values unit specifiers such as %i or %H expand
+// to. The unit name derived specifiers (%n, %N, %p, %P, %i, %I, %j, %J and
+// %f) are computed from UnitName.
+type SpecifierContext struct {
+	UnitName     string
+	FragmentPath string // %y and %Y
+
+	Architecture   string // %a
+	BootID         string // %b
+	MachineID      string // %m
+	Hostname       string // %H, %l is derived from it
+	PrettyHostname string // %q
+	KernelRelease  string // %v
+
+	OSID           string // %o
+	OSVersionID    string // %w
+	OSVariantID    string // %W
+	OSBuildID      string // %B
+	OSImageID      string // %M
+	OSImageVersion string // %A
+
+	UserName  string // %u
+	UID       string // %U
+	GroupName string // %g
+	GID       string // %G
+	Home      string // %h
+	Shell     string // %s
+
+	RuntimeDirectory       string // %t
+	StateDirectory         string // %S
+	CacheDirectory         string // %C
+	LogsDirectory          string // %L
+	ConfigurationDirectory string // %E
+	CredentialsDirectory   string // %d
+	TempDirectory          string // %T
+	VarTempDirectory       string // %V
+}
+
+// systemdArchitectures maps GOARCH values to systemd's architecture names.
+var systemdArchitectures = map[string]string{
+	"386":      "x86",
+	"amd64":    "x86-64",
+	"arm":      "arm",
+	"arm64":    "arm64",
+	"loong64":  "loongarch64",
+	"mips64le": "mips64-le",
+	"mipsle":   "mips-le",
+	"ppc64":    "ppc64",
+	"ppc64le":  "ppc64-le",
+	"riscv64":  "riscv64",
+	"s390x":    "s390x",
+}
+
+// NewSystemSpecifierContext returns a SpecifierContext for a unit of the
+// system manager, filled in from the running system as far as possible.
+func NewSystemSpecifierContext(unitName string) *SpecifierContext {
+	c := &SpecifierContext{
+		UnitName:               unitName,
+		Architecture:           systemdArchitectures[runtime.GOARCH],
+		UserName:               "root",
+		UID:                    "0",
+		GroupName:              "root",
+		GID:                    "0",
+		Home:                   "/root",
+		Shell:                  "/bin/sh",
+		RuntimeDirectory:       "/run",
+		StateDirectory:         "/var/lib",
+		CacheDirectory:         "/var/cache",
+		LogsDirectory:          "/var/log",
+		ConfigurationDirectory: "/etc",
+		TempDirectory:          "/tmp",
+		VarTempDirectory:       "/var/tmp",
+	}
+	if unitName != "" {
+		c.CredentialsDirectory = "/run/credentials/" + unitName
+	}
+
+	c.Hostname, _ = os.Hostname()
+	c.MachineID = readFirstLine("/etc/machine-id")
+	c.BootID = strings.Replace(readFirstLine("/proc/sys/kernel/random/boot_id"), "-", "", -1)
+	c.KernelRelease = readFirstLine("/proc/sys/kernel/osrelease")
+
+	osRelease := readEnvFile("/etc/os-release")
+	if osRelease == nil {
+		osRelease = readEnvFile("/usr/lib/os-release")
+	}
+	c.OSID = osRelease["ID"]
+	c.OSVersionID = osRelease["VERSION_ID"]
+	c.OSVariantID = osRelease["VARIANT_ID"]
+	c.OSBuildID = osRelease["BUILD_ID"]
+	c.OSImageID = osRelease["IMAGE_ID"]
+	c.OSImageVersion = osRelease["IMAGE_VERSION"]
+	c.PrettyHostname = readEnvFile("/etc/machine-info")["PRETTY_HOSTNAME"]
+	if c.PrettyHostname == "" {
+		c.PrettyHostname = c.Hostname
+	}
+
+	return c
+}
+
+func readFirstLine(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0])
+}
+
+// readEnvFile reads a file of KEY=VALUE assignments like /etc/os-release,
+// returning nil if it cannot be read.
+func readEnvFile(path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	env := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.Index(line, "=")
+		if i < 0 {
+			continue
+		}
+		value := line[i+1:]
+		if words, err := SplitWords(value); err == nil && len(words) == 1 {
+			value = words[0]
+		}
+		env[line[:i]] = value
+	}
+	return env
+}
+
+// EscapeSpecifiers escapes the "%" characters in s, so that systemd does not
+// expand specifiers in it.
+func EscapeSpecifiers(s string) string {
+	return strings.Replace(s, "%", "%%", -1)
+}
+
+// ExpandSpecifiers expands the unit specifiers in s as systemd would for the
+// unit and system described by c. Unknown specifiers result in an error.
+func ExpandSpecifiers(s string, c *SpecifierContext) (string, error) {
+	if !strings.Contains(s, "%") {
+		return s, nil
+	}
+
+	var prefix, instance string
+	if c.UnitName != "" {
+		var err error
+		prefix, instance, _, err = UnitNameSplit(c.UnitName)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", fmt.Errorf("incomplete specifier at end of %q", s)
+		}
+
+		switch s[i] {
+		case '%':
+			b.WriteByte('%')
+		case 'n':
+			b.WriteString(c.UnitName)
+		case 'N':
+			b.WriteString(strings.TrimSuffix(c.UnitName, filepath.Ext(c.UnitName)))
+		case 'p':
+			b.WriteString(prefix)
+		case 'P':
+			b.WriteString(UnitNameUnescape(prefix))
+		case 'i':
+			b.WriteString(instance)
+		case 'I':
+			b.WriteString(UnitNameUnescape(instance))
+		case 'j':
+			b.WriteString(prefix[strings.LastIndexByte(prefix, '-')+1:])
+		case 'J':
+			b.WriteString(UnitNameUnescape(prefix[strings.LastIndexByte(prefix, '-')+1:]))
+		case 'f':
+			if instance != "" {
+				b.WriteString(UnitNamePathUnescape(instance))
+			} else {
+				b.WriteString(UnitNamePathUnescape(prefix))
+			}
+		case 'y':
+			b.WriteString(c.FragmentPath)
+		case 'Y':
+			if c.FragmentPath != "" {
+				b.WriteString(filepath.Dir(c.FragmentPath))
+			}
+		case 'a':
+			b.WriteString(c.Architecture)
+		case 'A':
+			b.WriteString(c.OSImageVersion)
+		case 'b':
+			b.WriteString(c.BootID)
+		case 'B':
+			b.WriteString(c.OSBuildID)
+		case 'H':
+			b.WriteString(c.Hostname)
+		case 'l':
+			b.WriteString(strings.SplitN(c.Hostname, ".", 2)[0])
+		case 'm':
+			b.WriteString(c.MachineID)
+		case 'M':
+			b.WriteString(c.OSImageID)
+		case 'o':
+			b.WriteString(c.OSID)
+		case 'q':
+			b.WriteString(c.PrettyHostname)
+		case 'v':
+			b.WriteString(c.KernelRelease)
+		case 'w':
+			b.WriteString(c.OSVersionID)
+		case 'W':
+			b.WriteString(c.OSVariantID)
+		case 'u':
+			b.WriteString(c.UserName)
+		case 'U':
+			b.WriteString(c.UID)
+		case 'g':
+			b.WriteString(c.GroupName)
+		case 'G':
+			b.WriteString(c.GID)
+		case 'h':
+			b.WriteString(c.Home)
+		case 's':
+			b.WriteString(c.Shell)
+		case 't':
+			b.WriteString(c.RuntimeDirectory)
+		case 'S':
+			b.WriteString(c.StateDirectory)
+		case 'C':
+			b.WriteString(c.CacheDirectory)
+		case 'L':
+			b.WriteString(c.LogsDirectory)
+		case 'E':
+			b.WriteString(c.ConfigurationDirectory)
+		case 'd':
+			b.WriteString(c.CredentialsDirectory)
+		case 'T':
+			b.WriteString(c.TempDirectory)
+		case 'V':
+			b.WriteString(c.VarTempDirectory)
+		default:
+			return "", fmt.Errorf("unknown specifier %%%c in %q", s[i], s)
+		}
+	}
+
+	return b.String(), nil
+}
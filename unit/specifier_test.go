@@ -0,0 +1,80 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"testing"
+)
+
+func TestExpandSpecifiers(t *testing.T) {
+	c := &SpecifierContext{
+		UnitName:         `systemd-fsck@dev-disk-by\x2dlabel-root.service`,
+		FragmentPath:     "/usr/lib/systemd/system/systemd-fsck@.service",
+		Hostname:         "node1.example.com",
+		MachineID:        "0123456789abcdef0123456789abcdef",
+		UserName:         "root",
+		UID:              "0",
+		Home:             "/root",
+		RuntimeDirectory: "/run",
+	}
+
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"no specifiers", "no specifiers"},
+		{"%n", `systemd-fsck@dev-disk-by\x2dlabel-root.service`},
+		{"%N", `systemd-fsck@dev-disk-by\x2dlabel-root`},
+		{"%p", "systemd-fsck"},
+		{"%P", "systemd/fsck"},
+		{"%i", `dev-disk-by\x2dlabel-root`},
+		{"%I", "dev/disk/by-label/root"},
+		{"%f", "/dev/disk/by-label/root"},
+		{"%j %J", "fsck fsck"},
+		{"%y %Y", "/usr/lib/systemd/system/systemd-fsck@.service /usr/lib/systemd/system"},
+		{"%H %l", "node1.example.com node1"},
+		{"%t/%m", "/run/0123456789abcdef0123456789abcdef"},
+		{"%u:%U %h", "root:0 /root"},
+		{"100%%", "100%"},
+	}
+
+	for i, tt := range tests {
+		out, err := ExpandSpecifiers(tt.in, c)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+	}
+
+	for _, in := range []string{"%z", "trailing %"} {
+		if _, err := ExpandSpecifiers(in, c); err == nil {
+			t.Errorf("expected error expanding %q", in)
+		}
+	}
+}
+
+func TestNewSystemSpecifierContext(t *testing.T) {
+	c := NewSystemSpecifierContext("foo.service")
+	out, err := ExpandSpecifiers("%n %u %t %d", c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "foo.service root /run /run/credentials/foo.service" {
+		t.Errorf("unexpected expansion %q", out)
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TimeSpanInfinity is the time span systemd calls infinity, e.g. for
+	// TimeoutSec=infinity.
+	TimeSpanInfinity = time.Duration(math.MaxInt64)
+
+	usecPerMinute = 60 * usecPerSec
+	usecPerHour   = 60 * usecPerMinute
+	usecPerDay    = 24 * usecPerHour
+	usecPerWeek   = 7 * usecPerDay
+	usecPerMonth  = 2629800 * usecPerSec  // 30.44 days
+	usecPerYear   = 31557600 * usecPerSec // 365.25 days
+)
+
+var errInvalidTimeSpan = errors.New("invalid time span")
+
+// timeSpanUnits lists the units accepted by systemd in matching order.
+var timeSpanUnits = []struct {
+	suffix string
+	usec   int64
+}{
+	{"seconds", usecPerSec}, {"second", usecPerSec}, {"sec", usecPerSec}, {"s", usecPerSec},
+	{"minutes", usecPerMinute}, {"minute", usecPerMinute}, {"min", usecPerMinute},
+	{"months", usecPerMonth}, {"month", usecPerMonth}, {"M", usecPerMonth},
+	{"msec", 1000}, {"ms", 1000}, {"m", usecPerMinute},
+	{"hours", usecPerHour}, {"hour", usecPerHour}, {"hr", usecPerHour}, {"h", usecPerHour},
+	{"days", usecPerDay}, {"day", usecPerDay}, {"d", usecPerDay},
+	{"weeks", usecPerWeek}, {"week", usecPerWeek}, {"w", usecPerWeek},
+	{"years", usecPerYear}, {"year", usecPerYear}, {"y", usecPerYear},
+	{"usec", 1}, {"us", 1}, {"μs", 1}, {"µs", 1},
+}
+
+// timeSpanFormatUnits lists the units FormatTimeSpan uses.
+var timeSpanFormatUnits = []struct {
+	suffix string
+	usec   int64
+}{
+	{"y", usecPerYear}, {"month", usecPerMonth}, {"w", usecPerWeek}, {"d", usecPerDay},
+	{"h", usecPerHour}, {"min", usecPerMinute}, {"s", usecPerSec}, {"ms", 1000}, {"us", 1},
+}
+
+// ParseTimeSpan parses a time span like "1h 2min 3us" as systemd does for
+// settings such as TimeoutSec=. Numbers without a unit are seconds.
+func ParseTimeSpan(s string) (time.Duration, error) {
+	return ParseTimeSpanWithUnit(s, time.Second)
+}
+
+// ParseTimeSpanWithUnit is like ParseTimeSpan, but numbers without a unit
+// are taken as multiples of defaultUnit, which must be at least a
+// microsecond.
+func ParseTimeSpanWithUnit(s string, defaultUnit time.Duration) (time.Duration, error) {
+	if defaultUnit < time.Microsecond {
+		return 0, fmt.Errorf("invalid default unit %v for time span %q", defaultUnit, s)
+	}
+	usec, err := parseTimeSpanUsec(s, int64(defaultUnit/time.Microsecond))
+	if err != nil {
+		return 0, fmt.Errorf("%v %q", err, s)
+	}
+	if usec == math.MaxInt64 {
+		return TimeSpanInfinity, nil
+	}
+	return time.Duration(usec) * time.Microsecond, nil
+}
+
+func parseTimeSpanUsec(s string, defaultUnit int64) (int64, error) {
+	p := strings.TrimSpace(s)
+	if p == "infinity" {
+		return math.MaxInt64, nil
+	}
+	if p == "" {
+		return 0, errInvalidTimeSpan
+	}
+
+	// The result is kept in microseconds and must fit a time.Duration.
+	const max = math.MaxInt64 / 1000
+	var total int64
+	for p != "" {
+		i := 0
+		for i < len(p) && p[i] >= '0' && p[i] <= '9' {
+			i++
+		}
+		whole := p[:i]
+		p = p[i:]
+
+		frac := ""
+		if strings.HasPrefix(p, ".") {
+			i = 1
+			for i < len(p) && p[i] >= '0' && p[i] <= '9' {
+				i++
+			}
+			frac = p[1:i]
+			p = p[i:]
+			// A decimal point must be followed by digits, as in "1.5s".
+			if frac == "" {
+				return 0, errInvalidTimeSpan
+			}
+		}
+		if whole == "" && frac == "" {
+			return 0, errInvalidTimeSpan
+		}
+
+		p = strings.TrimLeft(p, " \t\n")
+		multiplier := int64(0)
+		for _, u := range timeSpanUnits {
+			if strings.HasPrefix(p, u.suffix) {
+				multiplier = u.usec
+				p = p[len(u.suffix):]
+				break
+			}
+		}
+		// Only the last number may lack a unit, so "12.34.56" is rejected.
+		if multiplier == 0 {
+			if p != "" {
+				return 0, errInvalidTimeSpan
+			}
+			multiplier = defaultUnit
+		}
+
+		var n int64
+		if whole != "" {
+			var err error
+			if n, err = strconv.ParseInt(whole, 10, 64); err != nil || n > max/multiplier {
+				return 0, errInvalidTimeSpan
+			}
+		}
+		v := n * multiplier
+		m := multiplier
+		for _, d := range frac {
+			m /= 10
+			v += int64(d-'0') * m
+		}
+		if v > max-total {
+			return 0, errInvalidTimeSpan
+		}
+		total += v
+
+		p = strings.TrimLeft(p, " \t\n")
+	}
+
+	return total, nil
+}
+
+// FormatTimeSpan formats a time span as systemd does, e.g. "1h 2min 3s",
+// leaving out parts below accuracy.
+func FormatTimeSpan(d, accuracy time.Duration) string {
+	if d == TimeSpanInfinity {
+		return "infinity"
+	}
+	t := int64(d / time.Microsecond)
+	if t <= 0 {
+		return "0"
+	}
+	acc := int64(accuracy / time.Microsecond)
+	if acc <= 0 {
+		acc = 1
+	}
+
+	var b strings.Builder
+	something := false
+	for _, u := range timeSpanFormatUnits {
+		if t <= 0 || (t < acc && something) {
+			break
+		}
+		if t < u.usec {
+			continue
+		}
+
+		a, rest := t/u.usec, t%u.usec
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+
+		// Show seconds and below with fractions.
+		done := false
+		if t < usecPerMinute && rest > 0 {
+			j := 0
+			for cc := u.usec; cc > 1; cc /= 10 {
+				j++
+			}
+			for cc := acc; cc > 1; cc /= 10 {
+				rest /= 10
+				j--
+			}
+			if j > 0 {
+				fmt.Fprintf(&b, "%d.%0*d%s", a, j, rest, u.suffix)
+				t = 0
+				done = true
+			}
+		}
+		if !done {
+			fmt.Fprintf(&b, "%d%s", a, u.suffix)
+			t = rest
+		}
+		something = true
+	}
+
+	return b.String()
+}
+
+// TimeSpanFromUsec converts a time span in microseconds, as used on D-Bus,
+// to a time.Duration. The maximum value is systemd's infinity.
+func TimeSpanFromUsec(usec uint64) time.Duration {
+	if usec >= math.MaxInt64/1000 {
+		return TimeSpanInfinity
+	}
+	return time.Duration(usec) * time.Microsecond
+}
+
+// TimestampFromUsec converts a wallclock timestamp in microseconds since the
+// epoch, as used on D-Bus, to a time.Time. Zero and the maximum value, which
+// systemd uses for unset timestamps, return the zero time.
+func TimestampFromUsec(usec uint64) time.Time {
+	if usec == 0 || usec == math.MaxUint64 {
+		return time.Time{}
+	}
+	return time.Unix(int64(usec/usecPerSec), int64(usec%usecPerSec)*1000)
+}
+
+var timestampLayouts = []string{
+	"2006-1-2 15:4:5",
+	"06-1-2 15:4:5",
+	"2006-1-2T15:4:5",
+	"2006-1-2 15:4",
+	"06-1-2 15:4",
+	"2006-1-2",
+	"06-1-2",
+}
+
+var timeLayouts = []string{
+	"15:4:5",
+	"15:4",
+}
+
+// ParseTimestamp parses a timestamp as systemd does, e.g. for
+// `systemctl --since=`, relative to the current time.
+func ParseTimestamp(s string) (time.Time, error) {
+	return ParseTimestampAt(s, time.Now())
+}
+
+// ParseTimestampAt parses a timestamp as systemd does, relative to now. It
+// accepts dates and times like "2012-11-23 11:12:13.5", optionally prefixed
+// with the weekday and followed by UTC or a time zone, "@" followed by
+// seconds since the epoch, "now", "today", "yesterday", "tomorrow" and time
+// spans relative to now like "+3h", "-5min", "3h left" and "5min ago".
+func ParseTimestampAt(s string, now time.Time) (time.Time, error) {
+	t, err := parseTimestamp(strings.TrimSpace(s), now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%v %q", err, s)
+	}
+	return t, nil
+}
+
+func parseTimestamp(s string, now time.Time) (time.Time, error) {
+	errInvalid := errors.New("invalid timestamp")
+
+	midnight := func(days int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day()+days, 0, 0, 0, 0, now.Location())
+	}
+	relative := func(span string, sign time.Duration) (time.Time, error) {
+		d, err := ParseTimeSpan(span)
+		if err != nil || d == TimeSpanInfinity {
+			return time.Time{}, errInvalid
+		}
+		return now.Add(sign * d), nil
+	}
+
+	switch {
+	case s == "now":
+		return now, nil
+	case s == "today":
+		return midnight(0), nil
+	case s == "yesterday":
+		return midnight(-1), nil
+	case s == "tomorrow":
+		return midnight(1), nil
+	case strings.HasPrefix(s, "+"):
+		return relative(s[1:], 1)
+	case strings.HasPrefix(s, "-"):
+		return relative(s[1:], -1)
+	case strings.HasSuffix(s, " left"):
+		return relative(strings.TrimSuffix(s, " left"), 1)
+	case strings.HasSuffix(s, " ago"):
+		return relative(strings.TrimSuffix(s, " ago"), -1)
+	case strings.HasPrefix(s, "@"):
+		d, err := ParseTimeSpan(s[1:])
+		if err != nil || d == TimeSpanInfinity {
+			return time.Time{}, errInvalid
+		}
+		return time.Unix(0, 0).Add(d).In(now.Location()), nil
+	}
+
+	loc := now.Location()
+	if len(s) > 4 && strings.EqualFold(s[len(s)-4:], " UTC") {
+		loc = time.UTC
+		s = strings.TrimRight(s[:len(s)-4], " ")
+	} else if strings.HasSuffix(s, "Z") {
+		loc = time.UTC
+		s = s[:len(s)-1]
+	} else if i := strings.LastIndexByte(s, ' '); i >= 0 && isTimezoneName(s[i+1:]) {
+		if l, err := time.LoadLocation(s[i+1:]); err == nil {
+			loc = l
+			s = strings.TrimRight(s[:i], " ")
+		}
+	}
+
+	weekday := -1
+	if i := strings.IndexByte(s, ' '); i > 0 {
+		for _, d := range weekdayNames {
+			if strings.EqualFold(s[:i], d.name) {
+				weekday = int(d.nr)
+				s = strings.TrimLeft(s[i:], " ")
+				break
+			}
+		}
+	}
+
+	t, ok := time.Time{}, false
+	for _, layout := range timestampLayouts {
+		if p, err := time.ParseInLocation(layout, s, loc); err == nil {
+			t, ok = p, true
+			break
+		}
+	}
+	if !ok {
+		for _, layout := range timeLayouts {
+			if p, err := time.ParseInLocation(layout, s, loc); err == nil {
+				n := now.In(loc)
+				t = time.Date(n.Year(), n.Month(), n.Day(), p.Hour(), p.Minute(), p.Second(), p.Nanosecond(), loc)
+				ok = true
+				break
+			}
+		}
+	}
+	if !ok {
+		return time.Time{}, errInvalid
+	}
+
+	if weekday >= 0 && (int(t.Weekday())+6)%7 != weekday {
+		return time.Time{}, errors.New("weekday does not match date of timestamp")
+	}
+	return t, nil
+}
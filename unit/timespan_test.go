@@ -0,0 +1,162 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeSpan(t *testing.T) {
+	tests := []struct {
+		in  string
+		out time.Duration
+	}{
+		{"0", 0},
+		{"5", 5 * time.Second},
+		{"5s", 5 * time.Second},
+		{"1h 2min 3us", time.Hour + 2*time.Minute + 3*time.Microsecond},
+		{"1h2min", time.Hour + 2*time.Minute},
+		{" 2 days 5 hours ", 53 * time.Hour},
+		{"1.5h", 90 * time.Minute},
+		{".5s", 500 * time.Millisecond},
+		{"3.1s 500ms", 3600 * time.Millisecond},
+		{"10m", 10 * time.Minute},
+		{"1M", 2629800 * time.Second},
+		{"2y", 2 * 31557600 * time.Second},
+		{"1w", 7 * 24 * time.Hour},
+		{"7µs", 7 * time.Microsecond},
+		{"7μs", 7 * time.Microsecond},
+		{"1.0000009s", time.Second},
+		{"infinity", TimeSpanInfinity},
+	}
+
+	for i, tt := range tests {
+		out, err := ParseTimeSpan(tt.in)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if out != tt.out {
+			t.Errorf("case %d: expected %v for %q, got %v", i, tt.out, tt.in, out)
+		}
+	}
+
+	if d, err := ParseTimeSpanWithUnit("500", time.Millisecond); err != nil || d != 500*time.Millisecond {
+		t.Errorf("unexpected result %v, %v", d, err)
+	}
+	for _, unit := range []time.Duration{0, -time.Second, time.Nanosecond, 999 * time.Nanosecond} {
+		if _, err := ParseTimeSpanWithUnit("5", unit); err == nil {
+			t.Errorf("expected error parsing with default unit %v", unit)
+		}
+	}
+
+	for _, in := range []string{"", "-1s", "1x", "s", "1.2.3s", "5 3s", "999999999999y", "1.s", "1. s"} {
+		if _, err := ParseTimeSpan(in); err == nil {
+			t.Errorf("expected error parsing %q", in)
+		}
+	}
+}
+
+func TestFormatTimeSpan(t *testing.T) {
+	tests := []struct {
+		in       time.Duration
+		accuracy time.Duration
+		out      string
+	}{
+		{0, time.Microsecond, "0"},
+		{TimeSpanInfinity, time.Microsecond, "infinity"},
+		{time.Hour + 2*time.Minute + 3*time.Second, time.Microsecond, "1h 2min 3s"},
+		{1500 * time.Millisecond, time.Microsecond, "1.500000s"},
+		{1500 * time.Millisecond, time.Millisecond, "1.500s"},
+		{90*time.Second + 500*time.Millisecond, time.Millisecond, "1min 30.500s"},
+		{3 * time.Microsecond, time.Microsecond, "3us"},
+		{1500 * time.Microsecond, time.Microsecond, "1.500ms"},
+		{8*24*time.Hour + time.Second, time.Minute, "1w 1d"},
+		{2 * 31557600 * time.Second, time.Second, "2y"},
+	}
+
+	for i, tt := range tests {
+		if out := FormatTimeSpan(tt.in, tt.accuracy); out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+	}
+}
+
+func TestTimeFromUsec(t *testing.T) {
+	if d := TimeSpanFromUsec(1500000); d != 1500*time.Millisecond {
+		t.Errorf("unexpected time span %v", d)
+	}
+	if d := TimeSpanFromUsec(^uint64(0)); d != TimeSpanInfinity {
+		t.Errorf("expected infinity, got %v", d)
+	}
+	if ts := TimestampFromUsec(1353669133000001); !ts.Equal(time.Date(2012, 11, 23, 11, 12, 13, 1000, time.UTC)) {
+		t.Errorf("unexpected timestamp %v", ts)
+	}
+	if ts := TimestampFromUsec(0); !ts.IsZero() {
+		t.Errorf("expected zero timestamp, got %v", ts)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		in  string
+		out time.Time
+	}{
+		{"now", now},
+		{"today", time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow", time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)},
+		{"+3h", now.Add(3 * time.Hour)},
+		{"-5min", now.Add(-5 * time.Minute)},
+		{"3h left", now.Add(3 * time.Hour)},
+		{"5min ago", now.Add(-5 * time.Minute)},
+		{"@1353669133", time.Date(2012, 11, 23, 11, 12, 13, 0, time.UTC)},
+		{"@1353669133.5", time.Date(2012, 11, 23, 11, 12, 13, 500000000, time.UTC)},
+		{"2012-11-23 11:12:13", time.Date(2012, 11, 23, 11, 12, 13, 0, time.UTC)},
+		{"2012-11-23 11:12:13.123456", time.Date(2012, 11, 23, 11, 12, 13, 123456000, time.UTC)},
+		{"Fri 2012-11-23 11:12:13", time.Date(2012, 11, 23, 11, 12, 13, 0, time.UTC)},
+		{"friday 2012-11-23 11:12 UTC", time.Date(2012, 11, 23, 11, 12, 0, 0, time.UTC)},
+		{"12-11-23 11:12:13", time.Date(2012, 11, 23, 11, 12, 13, 0, time.UTC)},
+		{"2012-11-23T11:12:13Z", time.Date(2012, 11, 23, 11, 12, 13, 0, time.UTC)},
+		{"2012-11-23T11:12:13", time.Date(2012, 11, 23, 11, 12, 13, 0, time.UTC)},
+		{"2012-11-23 11:12:13Z", time.Date(2012, 11, 23, 11, 12, 13, 0, time.UTC)},
+		{"2012-11-23 11:12:13 Europe/Berlin", time.Date(2012, 11, 23, 10, 12, 13, 0, time.UTC)},
+		{"11:12 America/New_York", time.Date(2026, 10, 14, 15, 12, 0, 0, time.UTC)},
+		{"2012-11-23", time.Date(2012, 11, 23, 0, 0, 0, 0, time.UTC)},
+		{"2012-1-5 1:2", time.Date(2012, 1, 5, 1, 2, 0, 0, time.UTC)},
+		{"11:12:13", time.Date(2026, 10, 14, 11, 12, 13, 0, time.UTC)},
+		{"11:12", time.Date(2026, 10, 14, 11, 12, 0, 0, time.UTC)},
+	}
+
+	for i, tt := range tests {
+		out, err := ParseTimestampAt(tt.in, now)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !out.Equal(tt.out) {
+			t.Errorf("case %d: expected %v for %q, got %v", i, tt.out, tt.in, out)
+		}
+	}
+
+	for _, in := range []string{"", "foo", "Mon 2012-11-23 11:12:13", "2012-13-01", "+foo", "25:00"} {
+		if _, err := ParseTimestampAt(in, now); err == nil {
+			t.Errorf("expected error parsing %q", in)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrUnterminatedQuote is returned by SplitWords for a value ending inside quotes.
+	ErrUnterminatedQuote = errors.New("unterminated quote")
+	// ErrTrailingBackslash is returned by SplitWords for a value ending in a lone backslash.
+	ErrTrailingBackslash = errors.New("trailing backslash")
+)
+
+// UnfoldValue returns a value as systemd sees it, joining values continued
+// over several lines with a trailing backslash. As in systemd, each
+// continuation is replaced by a single space.
+func UnfoldValue(value string) string {
+	return strings.Replace(value, "\\\n", " ", -1)
+}
+
+// SplitWords splits a value into words following the quoting rules systemd
+// applies to settings taking a list of words, such as Environment= or the
+// arguments of ExecStart=. Words are separated by whitespace, single and
+// double quotes preserve whitespace and C-style escapes are decoded.
+func SplitWords(value string) ([]string, error) {
+	words := []string{}
+	value = UnfoldValue(value)
+
+	for {
+		value = strings.TrimLeft(value, " \t\n\r")
+		if value == "" {
+			return words, nil
+		}
+
+		word, rest, err := nextWord(value)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+		value = rest
+	}
+}
+
+// QuoteWord quotes w with C-style escapes if needed, so that SplitWords and
+// ParseExecCommandLine return it unchanged as a single word. A lone ";" is
+// quoted too, as it separates the commands of an Exec*= setting.
+func QuoteWord(w string) string {
+	if w != "" && w != ";" && !strings.ContainsAny(w, " \t\n\r\v\f\"'\\") && !hasControlChars(w) {
+		return w
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(w); i++ {
+		switch c := w[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if c < ' ' || c == 0x7f {
+				fmt.Fprintf(&b, `\x%02x`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func hasControlChars(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < ' ' || s[i] == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+// nextWord unquotes the word at the start of value, which must not start
+// with whitespace, returning it and the remainder of value.
+func nextWord(value string) (string, string, error) {
+	var word strings.Builder
+	var quote byte
+	for value != "" {
+		c := value[0]
+		switch {
+		case c == '\\':
+			r, n, err := unescapeC(value)
+			if err != nil {
+				return "", "", err
+			}
+			word.WriteString(r)
+			value = value[n:]
+			continue
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote == 0 && strings.IndexByte(" \t\n\r", c) >= 0:
+			return word.String(), value, nil
+		default:
+			word.WriteByte(c)
+		}
+		value = value[1:]
+	}
+	if quote != 0 {
+		return "", "", ErrUnterminatedQuote
+	}
+	return word.String(), value, nil
+}
+
+// unescapeC decodes the C-style escape sequence at the start of s, returning
+// the decoded string and the number of bytes consumed.
+func unescapeC(s string) (string, int, error) {
+	if len(s) < 2 {
+		return "", 0, ErrTrailingBackslash
+	}
+
+	switch s[1] {
+	case 'a':
+		return "\a", 2, nil
+	case 'b':
+		return "\b", 2, nil
+	case 'f':
+		return "\f", 2, nil
+	case 'n':
+		return "\n", 2, nil
+	case 'r':
+		return "\r", 2, nil
+	case 't':
+		return "\t", 2, nil
+	case 'v':
+		return "\v", 2, nil
+	case 's':
+		return " ", 2, nil
+	case '\\', '"', '\'':
+		return s[1:2], 2, nil
+	case 'x':
+		if len(s) < 4 {
+			return "", 0, errors.New("invalid \\x escape")
+		}
+		b, err := strconv.ParseUint(s[2:4], 16, 8)
+		if err != nil {
+			return "", 0, errors.New("invalid \\x escape")
+		}
+		return string([]byte{byte(b)}), 4, nil
+	case 'u', 'U':
+		n := 4
+		if s[1] == 'U' {
+			n = 8
+		}
+		if len(s) < 2+n {
+			return "", 0, errors.New("invalid unicode escape")
+		}
+		r, err := strconv.ParseUint(s[2:2+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return "", 0, errors.New("invalid unicode escape")
+		}
+		return string(rune(r)), 2 + n, nil
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		if len(s) < 4 {
+			return "", 0, errors.New("invalid octal escape")
+		}
+		b, err := strconv.ParseUint(s[1:4], 8, 8)
+		if err != nil {
+			return "", 0, errors.New("invalid octal escape")
+		}
+		return string([]byte{byte(b)}), 4, nil
+	}
+
+	// Unknown escapes are kept verbatim including the backslash, as
+	// systemd does in relaxed mode.
+	_, n := utf8.DecodeRuneInString(s[1:])
+	return s[:1+n], 1 + n, nil
+}
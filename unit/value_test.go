@@ -0,0 +1,114 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnfoldValue(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"", ""},
+		{"foo", "foo"},
+		{"/bin/foo \\\n--bar \\\n--baz", "/bin/foo  --bar  --baz"},
+		{"foo\\\n", "foo "},
+	}
+
+	for i, tt := range tests {
+		if out := UnfoldValue(tt.in); out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		in  string
+		out []string
+	}{
+		{"", []string{}},
+		{"  foo   bar ", []string{"foo", "bar"}},
+		{`"foo bar" 'baz  qux'`, []string{"foo bar", "baz  qux"}},
+		{`a"b c"d`, []string{"ab cd"}},
+		{`"it's" 'say "hi"'`, []string{"it's", `say "hi"`}},
+		{`VAR1=word1\sword2 "VAR2=a\tb" \x41\101ä`, []string{"VAR1=word1 word2", "VAR2=a\tb", "AAä"}},
+		{`'\''`, []string{"'"}},
+		{"/bin/echo \\\nfoo", []string{"/bin/echo", "foo"}},
+		{`""`, []string{""}},
+		{`\q "\d+" \ä`, []string{`\q`, `\d+`, `\ä`}},
+	}
+
+	for i, tt := range tests {
+		out, err := SplitWords(tt.in)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(out, tt.out) {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+	}
+}
+
+func TestSplitWordsFail(t *testing.T) {
+	tests := []struct {
+		in  string
+		err error
+	}{
+		{`"foo`, ErrUnterminatedQuote},
+		{`foo 'bar`, ErrUnterminatedQuote},
+		{`foo\`, ErrTrailingBackslash},
+	}
+
+	for i, tt := range tests {
+		if _, err := SplitWords(tt.in); err != tt.err {
+			t.Errorf("case %d: expected error %v, got %v", i, tt.err, err)
+		}
+	}
+
+	if _, err := SplitWords(`\xzz`); err == nil {
+		t.Error("expected error for invalid hex escape")
+	}
+}
+
+func TestQuoteWord(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"foo", "foo"},
+		{"", `""`},
+		{";", `";"`},
+		{"foo bar", `"foo bar"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`a\b`, `"a\\b"`},
+		{"tab\tnew\nline\x01", `"tab\tnew\nline\x01"`},
+		{"ä ö", `"ä ö"`},
+	}
+
+	for i, tt := range tests {
+		out := QuoteWord(tt.in)
+		if out != tt.out {
+			t.Errorf("case %d: expected %q, got %q", i, tt.out, out)
+		}
+		if words, err := SplitWords(out); err != nil || len(words) != 1 || words[0] != tt.in {
+			t.Errorf("case %d: %q does not split back to %q: %q, %v", i, out, tt.in, words, err)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !go1.13
+// +build !go1.13
+
+package userdb
+
+import "crypto"
+
+// isEd25519 returns false, as Ed25519 keys require crypto/ed25519 of Go 1.13.
+func isEd25519(pub crypto.PublicKey) bool {
+	return false
+}
+
+func verifyEd25519(pub crypto.PublicKey, data, sig []byte) bool {
+	return false
+}
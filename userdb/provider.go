@@ -0,0 +1,226 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userdb
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/coreos/go-systemd/v22/varlink"
+)
+
+// UserQuery is a lookup of users received by a Provider. If neither field
+// is set, all users are enumerated.
+type UserQuery struct {
+	UID      *uint32
+	UserName string
+}
+
+// GroupQuery is a lookup of groups received by a Provider. If neither field
+// is set, all groups are enumerated.
+type GroupQuery struct {
+	GID       *uint32
+	GroupName string
+}
+
+// MembershipQuery is a lookup of memberships received by a Provider. If
+// neither field is set, all memberships are enumerated.
+type MembershipQuery struct {
+	UserName  string
+	GroupName string
+}
+
+// Database provides the records a Provider serves. Each method returns the
+// records matching the query, either none or ErrNotFound if there are no
+// such records. Errors of type *varlink.Error are passed to the client.
+type Database interface {
+	LookupUsers(ctx context.Context, q UserQuery) ([]*UserRecord, error)
+	LookupGroups(ctx context.Context, q GroupQuery) ([]*GroupRecord, error)
+	LookupMemberships(ctx context.Context, q MembershipQuery) ([]Membership, error)
+}
+
+// Provider serves the records of a Database as a user database service,
+// which systemd's multiplexer, userdbctl and nss-systemd pick up.
+type Provider struct {
+	// Service is the name of the service, conventionally a reverse domain
+	// name, e.g. com.example.Directory. The socket is named after it.
+	Service  string
+	Database Database
+}
+
+// ListenAndServe creates the service's socket in SocketDir and serves
+// queries on it until ctx is done.
+func (p *Provider) ListenAndServe(ctx context.Context) error {
+	path := filepath.Join(SocketDir, p.Service)
+	l, err := varlink.Listen(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	return p.Serve(ctx, l)
+}
+
+// Serve serves queries on l, e.g. a socket passed by socket activation,
+// until ctx is done.
+func (p *Provider) Serve(ctx context.Context, l net.Listener) error {
+	s := &varlink.Server{Handler: p.handle}
+	return s.Serve(ctx, l)
+}
+
+func (p *Provider) handle(ctx context.Context, call *varlink.MethodCall) error {
+	var replies []interface{}
+	var err error
+	switch call.Method {
+	case "io.systemd.UserDatabase.GetUserRecord":
+		var q userQuery
+		if err := p.parse(call, &q, &q.Service); err != nil {
+			return err
+		}
+		if q.UID == nil && q.UserName == "" && !call.More {
+			return varlink.NewError(varlink.ErrorExpectedMore, nil)
+		}
+		var users []*UserRecord
+		users, err = p.Database.LookupUsers(ctx, UserQuery{UID: q.UID, UserName: q.UserName})
+		for _, u := range users {
+			if !idMatches(u.UID, q.UID) || (q.UserName != "" && u.UserName != q.UserName) {
+				return varlink.NewError(ErrorConflictingRecordFound, nil)
+			}
+			replies = append(replies, userReply{Record: u, Incomplete: u.Incomplete})
+		}
+
+	case "io.systemd.UserDatabase.GetGroupRecord":
+		var q groupQuery
+		if err := p.parse(call, &q, &q.Service); err != nil {
+			return err
+		}
+		if q.GID == nil && q.GroupName == "" && !call.More {
+			return varlink.NewError(varlink.ErrorExpectedMore, nil)
+		}
+		var groups []*GroupRecord
+		groups, err = p.Database.LookupGroups(ctx, GroupQuery{GID: q.GID, GroupName: q.GroupName})
+		for _, g := range groups {
+			if !idMatches(g.GID, q.GID) || (q.GroupName != "" && g.GroupName != q.GroupName) {
+				return varlink.NewError(ErrorConflictingRecordFound, nil)
+			}
+			replies = append(replies, groupReply{Record: g, Incomplete: g.Incomplete})
+		}
+
+	case "io.systemd.UserDatabase.GetMemberships":
+		var q membershipQuery
+		if err := p.parse(call, &q, &q.Service); err != nil {
+			return err
+		}
+		if (q.UserName == "" || q.GroupName == "") && !call.More {
+			return varlink.NewError(varlink.ErrorExpectedMore, nil)
+		}
+		var memberships []Membership
+		memberships, err = p.Database.LookupMemberships(ctx, MembershipQuery{UserName: q.UserName, GroupName: q.GroupName})
+		for _, m := range memberships {
+			replies = append(replies, m)
+		}
+
+	default:
+		return varlink.NewError(varlink.ErrorMethodNotFound, map[string]string{"method": call.Method})
+	}
+
+	if err != nil && err != ErrNotFound {
+		return err
+	}
+	if len(replies) == 0 {
+		return varlink.NewError(ErrorNoRecordFound, nil)
+	}
+	if !call.More {
+		return call.Reply(replies[0])
+	}
+	for _, r := range replies[:len(replies)-1] {
+		if err := call.ReplyContinues(r); err != nil {
+			return err
+		}
+	}
+	return call.Reply(replies[len(replies)-1])
+}
+
+// parse decodes the parameters of call into q and checks the service they
+// are addressed to.
+func (p *Provider) parse(call *varlink.MethodCall, q interface{}, service *string) error {
+	if err := json.Unmarshal(call.Parameters, q); err != nil {
+		return varlink.NewError(varlink.ErrorInvalidParameter, map[string]string{"parameter": err.Error()})
+	}
+	if *service != p.Service {
+		return varlink.NewError(ErrorBadService, nil)
+	}
+	return nil
+}
+
+// idMatches returns whether id is the ID asked for by query, if any.
+func idMatches(id, query *uint32) bool {
+	return query == nil || (id != nil && *id == *query)
+}
+
+// StaticDatabase is a Database serving a fixed set of records. Memberships
+// are derived from the MemberOf and Members fields of the records.
+type StaticDatabase struct {
+	Users  []*UserRecord
+	Groups []*GroupRecord
+}
+
+// LookupUsers implements Database.
+func (d *StaticDatabase) LookupUsers(ctx context.Context, q UserQuery) ([]*UserRecord, error) {
+	var users []*UserRecord
+	for _, u := range d.Users {
+		if idMatches(u.UID, q.UID) && (q.UserName == "" || u.UserName == q.UserName) {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// LookupGroups implements Database.
+func (d *StaticDatabase) LookupGroups(ctx context.Context, q GroupQuery) ([]*GroupRecord, error) {
+	var groups []*GroupRecord
+	for _, g := range d.Groups {
+		if idMatches(g.GID, q.GID) && (q.GroupName == "" || g.GroupName == q.GroupName) {
+			groups = append(groups, g)
+		}
+	}
+	return groups, nil
+}
+
+// LookupMemberships implements Database.
+func (d *StaticDatabase) LookupMemberships(ctx context.Context, q MembershipQuery) ([]Membership, error) {
+	var memberships []Membership
+	seen := map[Membership]bool{}
+	add := func(m Membership) {
+		if seen[m] || (q.UserName != "" && m.UserName != q.UserName) || (q.GroupName != "" && m.GroupName != q.GroupName) {
+			return
+		}
+		seen[m] = true
+		memberships = append(memberships, m)
+	}
+	for _, u := range d.Users {
+		for _, g := range u.MemberOf {
+			add(Membership{UserName: u.UserName, GroupName: g})
+		}
+	}
+	for _, g := range d.Groups {
+		for _, u := range g.Members {
+			add(Membership{UserName: u, GroupName: g.GroupName})
+		}
+	}
+	return memberships, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userdb
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-userdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d string) { SocketDir = d }(SocketDir)
+	SocketDir = dir
+
+	var alice UserRecord
+	if err := json.Unmarshal([]byte(userRecord), &alice); err != nil {
+		t.Fatal(err)
+	}
+	id := uint32(1001)
+	bob := &UserRecord{UserName: "bob", UID: &id, GID: &id}
+	wheel := uint32(10)
+	p := &Provider{
+		Service: "com.example.Directory",
+		Database: &StaticDatabase{
+			Users:  []*UserRecord{&alice, bob},
+			Groups: []*GroupRecord{{GroupName: "wheel", GID: &wheel, Members: []string{"alice", "bob"}}},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- p.ListenAndServe(ctx) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("unexpected error from ListenAndServe: %v", err)
+		}
+	}()
+
+	c := &Client{Service: p.Service}
+	var u *UserRecord
+	for i := 0; i < 100; i++ {
+		if u, err = c.LookupUser(ctx, "alice"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(u.Raw, &raw); err != nil || raw["storage"] != "luks" || u.RealName != "Alice" {
+		t.Errorf("expected record to be served completely, got %s", u.Raw)
+	}
+
+	if u, err := c.LookupUserByUID(ctx, 1001); err != nil || u.UserName != "bob" {
+		t.Errorf("unexpected record %+v, %v", u, err)
+	}
+	if _, err := c.LookupUserByUID(ctx, 1002); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if users, err := c.Users(ctx); err != nil || len(users) != 2 {
+		t.Errorf("unexpected users %+v, %v", users, err)
+	}
+	if g, err := c.LookupGroupByGID(ctx, 10); err != nil || g.GroupName != "wheel" {
+		t.Errorf("unexpected group %+v, %v", g, err)
+	}
+
+	memberships, err := c.Memberships(ctx, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(memberships, []Membership{{"alice", "wheel"}}) {
+		t.Errorf("unexpected memberships %+v", memberships)
+	}
+	if memberships, err := c.Memberships(ctx, "", "wheel"); err != nil || len(memberships) != 2 {
+		t.Errorf("unexpected memberships %+v, %v", memberships, err)
+	}
+
+	// Queries are checked against the service name.
+	if err := os.Symlink(p.Service, SocketDir+"/com.example.Other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (&Client{Service: "com.example.Other"}).LookupUser(ctx, "alice"); err == nil || err == ErrNotFound {
+		t.Errorf("expected BadService error, got %v", err)
+	}
+}
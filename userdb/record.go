@@ -0,0 +1,265 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userdb
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// UserRecord is a JSON user record, as described in
+// https://systemd.io/USER_RECORD/. The fields of the regular section are
+// embedded, the other sections are separate structs. Fields not covered by
+// the structs are kept in Raw, so decoding and encoding a record again
+// does not lose information.
+type UserRecord struct {
+	UserName     string `json:"userName"`
+	Realm        string `json:"realm,omitempty"`
+	RealName     string `json:"realName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	IconName     string `json:"iconName,omitempty"`
+	Location     string `json:"location,omitempty"`
+	// Disposition is e.g. intrinsic, system, dynamic or regular.
+	Disposition            string   `json:"disposition,omitempty"`
+	LastChangeUSec         uint64   `json:"lastChangeUSec,omitempty"`
+	LastPasswordChangeUSec uint64   `json:"lastPasswordChangeUSec,omitempty"`
+	Shell                  string   `json:"shell,omitempty"`
+	Environment            []string `json:"environment,omitempty"`
+	TimeZone               string   `json:"timeZone,omitempty"`
+	PreferredLanguage      string   `json:"preferredLanguage,omitempty"`
+	Locked                 bool     `json:"locked,omitempty"`
+	NotBeforeUSec          uint64   `json:"notBeforeUSec,omitempty"`
+	NotAfterUSec           uint64   `json:"notAfterUSec,omitempty"`
+	// Storage is the home directory storage of systemd-homed users, e.g.
+	// luks, directory, subvolume, fscrypt or cifs.
+	Storage       string   `json:"storage,omitempty"`
+	DiskSize      uint64   `json:"diskSize,omitempty"`
+	ImagePath     string   `json:"imagePath,omitempty"`
+	HomeDirectory string   `json:"homeDirectory,omitempty"`
+	UID           *uint32  `json:"uid,omitempty"` // Unset for records that let systemd-homed pick one
+	GID           *uint32  `json:"gid,omitempty"`
+	MemberOf      []string `json:"memberOf,omitempty"`
+	Service       string   `json:"service,omitempty"` // The service that provided the record
+
+	Privileged *UserPrivileged         `json:"privileged,omitempty"`
+	Binding    map[string]*UserBinding `json:"binding,omitempty"` // Keyed by machine ID
+	Status     map[string]*UserStatus  `json:"status,omitempty"`  // Keyed by machine ID
+	Signature  []Signature             `json:"signature,omitempty"`
+	Secret     *UserSecret             `json:"secret,omitempty"`
+
+	// Incomplete is set if the privileged section was left out because
+	// the caller may not see it.
+	Incomplete bool `json:"-"`
+	// Raw is the record as decoded.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UserPrivileged is the privileged section of a user record, which only
+// the user and root may see.
+type UserPrivileged struct {
+	PasswordHint      string   `json:"passwordHint,omitempty"`
+	HashedPassword    []string `json:"hashedPassword,omitempty"`
+	SSHAuthorizedKeys []string `json:"sshAuthorizedKeys,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// UserBinding is the binding section of a user record, with the settings
+// systemd-homed fixed for the user on a specific machine.
+type UserBinding struct {
+	ImagePath      string  `json:"imagePath,omitempty"`
+	HomeDirectory  string  `json:"homeDirectory,omitempty"`
+	PartitionUUID  string  `json:"partitionUuid,omitempty"`
+	LUKSUUID       string  `json:"luksUuid,omitempty"`
+	FileSystemUUID string  `json:"fileSystemUuid,omitempty"`
+	UID            *uint32 `json:"uid,omitempty"`
+	GID            *uint32 `json:"gid,omitempty"`
+	Storage        string  `json:"storage,omitempty"`
+	FileSystemType string  `json:"fileSystemType,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// UserStatus is the status section of a user record, with the runtime
+// state of the user on a specific machine.
+type UserStatus struct {
+	State                      string `json:"state,omitempty"`
+	Service                    string `json:"service,omitempty"`
+	DiskUsage                  uint64 `json:"diskUsage,omitempty"`
+	DiskFree                   uint64 `json:"diskFree,omitempty"`
+	DiskSize                   uint64 `json:"diskSize,omitempty"`
+	DiskCeiling                uint64 `json:"diskCeiling,omitempty"`
+	DiskFloor                  uint64 `json:"diskFloor,omitempty"`
+	SignedLocally              *bool  `json:"signedLocally,omitempty"`
+	GoodAuthenticationCounter  uint64 `json:"goodAuthenticationCounter,omitempty"`
+	BadAuthenticationCounter   uint64 `json:"badAuthenticationCounter,omitempty"`
+	LastGoodAuthenticationUSec uint64 `json:"lastGoodAuthenticationUSec,omitempty"`
+	LastBadAuthenticationUSec  uint64 `json:"lastBadAuthenticationUSec,omitempty"`
+	RateLimitBeginUSec         uint64 `json:"rateLimitBeginUSec,omitempty"`
+	RateLimitCount             uint64 `json:"rateLimitCount,omitempty"`
+	Removable                  bool   `json:"removable,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// UserSecret is the secret section of a user record, which is only passed
+// to systemd-homed along with a request and never stored.
+type UserSecret struct {
+	Password []string `json:"password,omitempty"`
+	TokenPIN []string `json:"tokenPin,omitempty"`
+
+	Raw json.RawMessage `json:"-"`
+}
+
+// Signature is an entry of the signature section of a user record. Data is
+// the signature of the record without its binding, status, signature and
+// secret sections, Key the PEM encoded public key it can be verified with.
+type Signature struct {
+	Data []byte `json:"data"`
+	Key  string `json:"key"`
+}
+
+// GroupRecord is a JSON group record, as described in
+// https://systemd.io/GROUP_RECORD/. Fields not covered by the struct are
+// kept in Raw.
+type GroupRecord struct {
+	GroupName      string   `json:"groupName"`
+	Realm          string   `json:"realm,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	Disposition    string   `json:"disposition,omitempty"`
+	GID            *uint32  `json:"gid,omitempty"`
+	Members        []string `json:"members,omitempty"`
+	Administrators []string `json:"administrators,omitempty"`
+	Service        string   `json:"service,omitempty"`
+
+	Incomplete bool            `json:"-"`
+	Raw        json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the record and keeps a copy in Raw.
+func (u *UserRecord) UnmarshalJSON(b []byte) error {
+	type plain UserRecord
+	return unmarshalRaw(b, (*plain)(u), &u.Raw)
+}
+
+// MarshalJSON encodes the record, including the fields only kept in Raw.
+func (u UserRecord) MarshalJSON() ([]byte, error) {
+	type plain UserRecord
+	return mergeRaw(u.Raw, plain(u))
+}
+
+// UnmarshalJSON decodes the section and keeps a copy in Raw.
+func (p *UserPrivileged) UnmarshalJSON(b []byte) error {
+	type plain UserPrivileged
+	return unmarshalRaw(b, (*plain)(p), &p.Raw)
+}
+
+// MarshalJSON encodes the section, including the fields only kept in Raw.
+func (p UserPrivileged) MarshalJSON() ([]byte, error) {
+	type plain UserPrivileged
+	return mergeRaw(p.Raw, plain(p))
+}
+
+// UnmarshalJSON decodes the section and keeps a copy in Raw.
+func (b *UserBinding) UnmarshalJSON(data []byte) error {
+	type plain UserBinding
+	return unmarshalRaw(data, (*plain)(b), &b.Raw)
+}
+
+// MarshalJSON encodes the section, including the fields only kept in Raw.
+func (b UserBinding) MarshalJSON() ([]byte, error) {
+	type plain UserBinding
+	return mergeRaw(b.Raw, plain(b))
+}
+
+// UnmarshalJSON decodes the section and keeps a copy in Raw.
+func (s *UserStatus) UnmarshalJSON(b []byte) error {
+	type plain UserStatus
+	return unmarshalRaw(b, (*plain)(s), &s.Raw)
+}
+
+// MarshalJSON encodes the section, including the fields only kept in Raw.
+func (s UserStatus) MarshalJSON() ([]byte, error) {
+	type plain UserStatus
+	return mergeRaw(s.Raw, plain(s))
+}
+
+// UnmarshalJSON decodes the section and keeps a copy in Raw.
+func (s *UserSecret) UnmarshalJSON(b []byte) error {
+	type plain UserSecret
+	return unmarshalRaw(b, (*plain)(s), &s.Raw)
+}
+
+// MarshalJSON encodes the section, including the fields only kept in Raw.
+func (s UserSecret) MarshalJSON() ([]byte, error) {
+	type plain UserSecret
+	return mergeRaw(s.Raw, plain(s))
+}
+
+// UnmarshalJSON decodes the record and keeps a copy in Raw.
+func (g *GroupRecord) UnmarshalJSON(b []byte) error {
+	type plain GroupRecord
+	return unmarshalRaw(b, (*plain)(g), &g.Raw)
+}
+
+// MarshalJSON encodes the record, including the fields only kept in Raw.
+func (g GroupRecord) MarshalJSON() ([]byte, error) {
+	type plain GroupRecord
+	return mergeRaw(g.Raw, plain(g))
+}
+
+func unmarshalRaw(b []byte, v interface{}, raw *json.RawMessage) error {
+	if err := json.Unmarshal(b, v); err != nil {
+		return err
+	}
+	*raw = append(json.RawMessage(nil), b...)
+	return nil
+}
+
+// mergeRaw marshals v, a struct, and adds the fields of the JSON object raw
+// that v has no field for. Fields v has are taken from v even if empty, so
+// clearing a field removes it from the encoded record.
+func mergeRaw(raw json.RawMessage, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || len(raw) == 0 {
+		return b, err
+	}
+	var record, fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			delete(record, name)
+		}
+	}
+	for k, v := range fields {
+		record[k] = v
+	}
+	return json.Marshal(record)
+}
+
+// Membership is a user's membership in a group.
+type Membership struct {
+	UserName  string `json:"userName"`
+	GroupName string `json:"groupName"`
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userdb
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const homedRecord = `{
+	"userName": "alice",
+	"realName": "Alice",
+	"uid": 60100,
+	"gid": 60100,
+	"storage": "luks",
+	"disposition": "regular",
+	"autoLogin": true,
+	"privileged": {"hashedPassword": ["$y$j9T$..."], "pkcs11EncryptedKey": []},
+	"binding": {"b08ef26c5e7a4c4a8a3e1a8f6d4c3b2a": {"imagePath": "/home/alice.home", "luksUuid": "0b2e6f7c-1111-4a4a-9c9c-123456789abc", "luksCipher": "aes"}},
+	"status": {"b08ef26c5e7a4c4a8a3e1a8f6d4c3b2a": {"state": "inactive", "diskSize": 1073741824, "service": "io.systemd.Home"}},
+	"signature": [{"data": "AAEC", "key": "-----BEGIN PUBLIC KEY-----\n-----END PUBLIC KEY-----\n"}]
+}`
+
+func TestUserRecordRoundTrip(t *testing.T) {
+	var u UserRecord
+	if err := json.Unmarshal([]byte(homedRecord), &u); err != nil {
+		t.Fatal(err)
+	}
+
+	if u.UID == nil || *u.UID != 60100 || u.Storage != "luks" {
+		t.Errorf("unexpected regular section %+v", u)
+	}
+	if u.Privileged == nil || len(u.Privileged.HashedPassword) != 1 {
+		t.Errorf("unexpected privileged section %+v", u.Privileged)
+	}
+	b := u.Binding["b08ef26c5e7a4c4a8a3e1a8f6d4c3b2a"]
+	if b == nil || b.ImagePath != "/home/alice.home" || b.LUKSUUID != "0b2e6f7c-1111-4a4a-9c9c-123456789abc" {
+		t.Errorf("unexpected binding section %+v", b)
+	}
+	s := u.Status["b08ef26c5e7a4c4a8a3e1a8f6d4c3b2a"]
+	if s == nil || s.State != "inactive" || s.DiskSize != 1073741824 {
+		t.Errorf("unexpected status section %+v", s)
+	}
+	if len(u.Signature) != 1 || string(u.Signature[0].Data) != "\x00\x01\x02" {
+		t.Errorf("unexpected signature section %+v", u.Signature)
+	}
+
+	// Encoding the record again keeps the fields unknown to the structs.
+	u.RealName = ""
+	out, err := json.Marshal(&u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(out, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["autoLogin"] != true {
+		t.Errorf("unknown field lost in %s", out)
+	}
+	if _, ok := m["realName"]; ok {
+		t.Errorf("cleared field kept in %s", out)
+	}
+	if _, ok := m["privileged"].(map[string]interface{})["pkcs11EncryptedKey"]; !ok {
+		t.Errorf("unknown privileged field lost in %s", out)
+	}
+	binding := m["binding"].(map[string]interface{})["b08ef26c5e7a4c4a8a3e1a8f6d4c3b2a"].(map[string]interface{})
+	if binding["luksCipher"] != "aes" {
+		t.Errorf("unknown binding field lost in %s", out)
+	}
+
+	// Records without a UID leave it to systemd-homed.
+	out, err = json.Marshal(&UserRecord{UserName: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"userName":"bob"}` {
+		t.Errorf("unexpected minimal record %s", out)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userdb
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+var (
+	// ErrNotSigned is returned by UserRecord.Verify if the record has no
+	// signature by the key.
+	ErrNotSigned = errors.New("record is not signed by the key")
+	// ErrBadSignature is returned by UserRecord.Verify if the signature by
+	// the key does not match the record.
+	ErrBadSignature = errors.New("signature does not match the record")
+)
+
+// CanonicalJSON returns the record in the normalized form systemd uses:
+// object keys sorted, without whitespace and with systemd's escaping of
+// strings.
+func (u *UserRecord) CanonicalJSON() ([]byte, error) {
+	b, err := json.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalJSON(b, nil)
+}
+
+// signedJSON returns the part of the record that is signed, which leaves out
+// the sections that differ between machines or are never stored.
+func (u *UserRecord) signedJSON() ([]byte, error) {
+	b, err := json.Marshal(u)
+	if err != nil {
+		return nil, err
+	}
+	return canonicalJSON(b, []string{"binding", "status", "signature", "secret"})
+}
+
+// Sign signs the record with signer, replacing any previous signatures, as
+// systemd-homed does with its local signing key. Ed25519 keys, which homed
+// uses, are supported when built with Go 1.13 or later, ECDSA and RSA keys
+// are signed with SHA-256.
+func (u *UserRecord) Sign(signer crypto.Signer) error {
+	data, err := u.signedJSON()
+	if err != nil {
+		return err
+	}
+
+	pub := signer.Public()
+	var sig []byte
+	switch pub.(type) {
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		sig, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		if !isEd25519(pub) {
+			return fmt.Errorf("unsupported key type %T", pub)
+		}
+		sig, err = signer.Sign(rand.Reader, data, crypto.Hash(0))
+	}
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	key := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	u.Signature = []Signature{{Data: sig, Key: string(key)}}
+	return nil
+}
+
+// SigningKeys returns the public keys of the record's signatures.
+func (u *UserRecord) SigningKeys() ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for _, s := range u.Signature {
+		key, err := parsePublicKey(s.Key)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Verify checks that the record carries a valid signature by pub, such as
+// the public key of systemd-homed in /var/lib/systemd/home/local.public.
+func (u *UserRecord) Verify(pub crypto.PublicKey) error {
+	want, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	data, err := u.signedJSON()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range u.Signature {
+		key, err := parsePublicKey(s.Key)
+		if err != nil {
+			return err
+		}
+		if der, err := x509.MarshalPKIXPublicKey(key); err != nil || !bytes.Equal(der, want) {
+			continue
+		}
+		if !verify(key, data, s.Data) {
+			return ErrBadSignature
+		}
+		return nil
+	}
+	return ErrNotSigned
+}
+
+func parsePublicKey(s string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, errors.New("invalid PEM public key in signature")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func verify(key crypto.PublicKey, data, sig []byte) bool {
+	digest := sha256.Sum256(data)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var rs struct{ R, S *big.Int }
+		if rest, err := asn1.Unmarshal(sig, &rs); err != nil || len(rest) > 0 {
+			return false
+		}
+		return ecdsa.Verify(k, digest[:], rs.R, rs.S)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	}
+	return verifyEd25519(key, data, sig)
+}
+
+// canonicalJSON normalizes the JSON object b as systemd does before signing
+// or comparing records, leaving out the top level fields in strip.
+func canonicalJSON(b []byte, strip []string) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var v map[string]interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	for _, s := range strip {
+		delete(v, s)
+	}
+
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		return writeNumber(buf, v)
+	case string:
+		writeString(buf, v)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := writeCanonical(buf, e); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeString(buf, k)
+			buf.WriteByte(':')
+			if err := writeCanonical(buf, v[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+	default:
+		return fmt.Errorf("unexpected JSON value %v", v)
+	}
+	return nil
+}
+
+// writeNumber writes integers in decimal and other numbers in the
+// exponential notation systemd uses.
+func writeNumber(buf *bytes.Buffer, n json.Number) error {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		buf.WriteString(strconv.FormatInt(i, 10))
+		return nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		buf.WriteString(strconv.FormatUint(u, 10))
+		return nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return err
+	}
+	buf.WriteString(strconv.FormatFloat(f, 'e', 21, 64))
+	return nil
+}
+
+// writeString writes s escaping only what JSON requires, like systemd.
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c == '\b':
+			buf.WriteString(`\b`)
+		case c == '\f':
+			buf.WriteString(`\f`)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c < ' ':
+			fmt.Fprintf(buf, `\u%04x`, c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+}
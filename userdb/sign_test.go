@@ -0,0 +1,131 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userdb
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{`{"b": 1, "a": [true, null, "x"]}`, `{"a":[true,null,"x"],"b":1}`},
+		{`{"s": "<a&b>é\"\\\n\u0001"}`, `{"s":"<a&b>` + "é" + `\"\\\n\u0001"}`},
+		{`{"n": 18446744073709551615, "m": -5, "f": 0.5}`, `{"f":5.000000000000000000000e-01,"m":-5,"n":18446744073709551615}`},
+		{`{"o": {"z": {}, "y": []}}`, `{"o":{"y":[],"z":{}}}`},
+	}
+
+	for i, tt := range tests {
+		out, err := canonicalJSON([]byte(tt.in), nil)
+		if err != nil {
+			t.Errorf("case %d: unexpected error: %v", i, err)
+			continue
+		}
+		if string(out) != tt.out {
+			t.Errorf("case %d: expected %s, got %s", i, tt.out, out)
+		}
+	}
+}
+
+func TestSignECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testSign(t, key, &key.PublicKey, &other.PublicKey)
+}
+
+// testSign signs a record with signer and checks that it verifies with pub
+// but not with other, and that only the signed sections are covered.
+func testSign(t *testing.T, signer crypto.Signer, pub, other crypto.PublicKey) {
+	uid := uint32(60100)
+	u := &UserRecord{
+		UserName: "alice",
+		UID:      &uid,
+		Status: map[string]*UserStatus{
+			"b08ef26c5e7a4c4a8a3e1a8f6d4c3b2a": {State: "inactive"},
+		},
+		Raw: json.RawMessage(`{"userName":"alice","uid":60100,"autoLogin":true}`),
+	}
+
+	if err := u.Verify(pub); err != ErrNotSigned {
+		t.Fatalf("expected %v for unsigned record, got %v", ErrNotSigned, err)
+	}
+	if err := u.Sign(signer); err != nil {
+		t.Fatal(err)
+	}
+	if err := u.Verify(pub); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := u.Verify(other); err != ErrNotSigned {
+		t.Errorf("expected %v for other key, got %v", ErrNotSigned, err)
+	}
+
+	keys, err := u.SigningKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("expected one signing key, got %d", len(keys))
+	}
+	if k, ok := keys[0].(interface{ Equal(crypto.PublicKey) bool }); ok && !k.Equal(pub) {
+		t.Errorf("unexpected signing key %v", keys[0])
+	}
+
+	// The signature survives a round trip through JSON.
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded UserRecord
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Verify(pub); err != nil {
+		t.Fatalf("unexpected error after round trip: %v", err)
+	}
+
+	// Status and binding sections are not covered by the signature.
+	decoded.Status["b08ef26c5e7a4c4a8a3e1a8f6d4c3b2a"].State = "active"
+	decoded.Binding = map[string]*UserBinding{
+		"b08ef26c5e7a4c4a8a3e1a8f6d4c3b2a": {ImagePath: "/home/alice.home"},
+	}
+	if err := decoded.Verify(pub); err != nil {
+		t.Errorf("unexpected error after changing status: %v", err)
+	}
+
+	// Regular fields, including unknown ones, are.
+	decoded.RealName = "Mallory"
+	if err := decoded.Verify(pub); err != ErrBadSignature {
+		t.Errorf("expected %v after changing realName, got %v", ErrBadSignature, err)
+	}
+	decoded.RealName = ""
+	decoded.Raw = bytes.Replace(decoded.Raw, []byte(`"autoLogin":true`), []byte(`"autoLogin":false`), 1)
+	if err := decoded.Verify(pub); err != ErrBadSignature {
+		t.Errorf("expected %v after changing autoLogin, got %v", ErrBadSignature, err)
+	}
+}
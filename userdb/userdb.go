@@ -0,0 +1,251 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package userdb provides a client for the io.systemd.UserDatabase varlink
+// interface, which resolves users and groups from systemd's user database
+// services such as systemd-homed, dynamic users and the NSS bridge.  See
+// https://systemd.io/USER_GROUP_API/
+package userdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+
+	"github.com/coreos/go-systemd/v22/varlink"
+)
+
+// SocketDir is where user database services place their sockets, a variable
+// so it can be changed by tests.
+var SocketDir = "/run/systemd/userdb"
+
+// MultiplexerService is the service that combines the records of all other
+// services, which the package level functions query.
+const MultiplexerService = "io.systemd.Multiplexer"
+
+// Errors of the io.systemd.UserDatabase interface.
+const (
+	ErrorNoRecordFound           = "io.systemd.UserDatabase.NoRecordFound"
+	ErrorBadService              = "io.systemd.UserDatabase.BadService"
+	ErrorServiceNotAvailable     = "io.systemd.UserDatabase.ServiceNotAvailable"
+	ErrorConflictingRecordFound  = "io.systemd.UserDatabase.ConflictingRecordFound"
+	ErrorEnumerationNotSupported = "io.systemd.UserDatabase.EnumerationNotSupported"
+)
+
+// ErrNotFound is returned for lookups of users and groups that do not exist.
+var ErrNotFound = errors.New("no record found")
+
+// Client queries a single user database service.
+type Client struct {
+	// Service is the name of the service and its socket in SocketDir.
+	Service string
+}
+
+var multiplexer = &Client{Service: MultiplexerService}
+
+type userQuery struct {
+	UID      *uint32 `json:"uid,omitempty"`
+	UserName string  `json:"userName,omitempty"`
+	Service  string  `json:"service"`
+}
+
+type groupQuery struct {
+	GID       *uint32 `json:"gid,omitempty"`
+	GroupName string  `json:"groupName,omitempty"`
+	Service   string  `json:"service"`
+}
+
+type membershipQuery struct {
+	UserName  string `json:"userName,omitempty"`
+	GroupName string `json:"groupName,omitempty"`
+	Service   string `json:"service"`
+}
+
+type userReply struct {
+	Record     *UserRecord `json:"record"`
+	Incomplete bool        `json:"incomplete"`
+}
+
+type groupReply struct {
+	Record     *GroupRecord `json:"record"`
+	Incomplete bool         `json:"incomplete"`
+}
+
+func (c *Client) socket() string {
+	return filepath.Join(SocketDir, c.Service)
+}
+
+// call calls method and returns ErrNotFound for NoRecordFound errors.
+func (c *Client) call(ctx context.Context, method string, parameters, reply interface{}) error {
+	err := varlink.Call(ctx, c.socket(), "io.systemd.UserDatabase."+method, parameters, reply)
+	if e, ok := err.(*varlink.Error); ok && e.Name == ErrorNoRecordFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// callMore is like call but asks for all records and unmarshals each reply
+// with fn. Services without records answer NoRecordFound, which yields no
+// replies.
+func (c *Client) callMore(ctx context.Context, method string, parameters interface{}, fn func(json.RawMessage) error) error {
+	conn, err := varlink.Dial(ctx, c.socket())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	err = conn.CallMore(ctx, "io.systemd.UserDatabase."+method, parameters, fn)
+	if e, ok := err.(*varlink.Error); ok && e.Name == ErrorNoRecordFound {
+		return nil
+	}
+	return err
+}
+
+func (c *Client) lookupUser(ctx context.Context, q userQuery) (*UserRecord, error) {
+	q.Service = c.Service
+	var r userReply
+	if err := c.call(ctx, "GetUserRecord", q, &r); err != nil {
+		return nil, err
+	}
+	if r.Record == nil {
+		return nil, ErrNotFound
+	}
+	r.Record.Incomplete = r.Incomplete
+	return r.Record, nil
+}
+
+// LookupUser returns the record of the user called name.
+func (c *Client) LookupUser(ctx context.Context, name string) (*UserRecord, error) {
+	return c.lookupUser(ctx, userQuery{UserName: name})
+}
+
+// LookupUserByUID returns the record of the user with the given UID.
+func (c *Client) LookupUserByUID(ctx context.Context, uid uint32) (*UserRecord, error) {
+	return c.lookupUser(ctx, userQuery{UID: &uid})
+}
+
+// Users returns the records of all users the service knows.
+func (c *Client) Users(ctx context.Context) ([]*UserRecord, error) {
+	var users []*UserRecord
+	err := c.callMore(ctx, "GetUserRecord", userQuery{Service: c.Service}, func(parameters json.RawMessage) error {
+		var r userReply
+		if err := json.Unmarshal(parameters, &r); err != nil {
+			return err
+		}
+		if r.Record != nil {
+			r.Record.Incomplete = r.Incomplete
+			users = append(users, r.Record)
+		}
+		return nil
+	})
+	return users, err
+}
+
+func (c *Client) lookupGroup(ctx context.Context, q groupQuery) (*GroupRecord, error) {
+	q.Service = c.Service
+	var r groupReply
+	if err := c.call(ctx, "GetGroupRecord", q, &r); err != nil {
+		return nil, err
+	}
+	if r.Record == nil {
+		return nil, ErrNotFound
+	}
+	r.Record.Incomplete = r.Incomplete
+	return r.Record, nil
+}
+
+// LookupGroup returns the record of the group called name.
+func (c *Client) LookupGroup(ctx context.Context, name string) (*GroupRecord, error) {
+	return c.lookupGroup(ctx, groupQuery{GroupName: name})
+}
+
+// LookupGroupByGID returns the record of the group with the given GID.
+func (c *Client) LookupGroupByGID(ctx context.Context, gid uint32) (*GroupRecord, error) {
+	return c.lookupGroup(ctx, groupQuery{GID: &gid})
+}
+
+// Groups returns the records of all groups the service knows.
+func (c *Client) Groups(ctx context.Context) ([]*GroupRecord, error) {
+	var groups []*GroupRecord
+	err := c.callMore(ctx, "GetGroupRecord", groupQuery{Service: c.Service}, func(parameters json.RawMessage) error {
+		var r groupReply
+		if err := json.Unmarshal(parameters, &r); err != nil {
+			return err
+		}
+		if r.Record != nil {
+			r.Record.Incomplete = r.Incomplete
+			groups = append(groups, r.Record)
+		}
+		return nil
+	})
+	return groups, err
+}
+
+// Memberships returns the group memberships of a user if userName is set,
+// the members of a group if groupName is set, or all memberships if neither
+// is.
+func (c *Client) Memberships(ctx context.Context, userName, groupName string) ([]Membership, error) {
+	var memberships []Membership
+	q := membershipQuery{UserName: userName, GroupName: groupName, Service: c.Service}
+	err := c.callMore(ctx, "GetMemberships", q, func(parameters json.RawMessage) error {
+		var m Membership
+		if err := json.Unmarshal(parameters, &m); err != nil {
+			return err
+		}
+		memberships = append(memberships, m)
+		return nil
+	})
+	return memberships, err
+}
+
+// LookupUser returns the record of the user called name from the
+// multiplexer.
+func LookupUser(ctx context.Context, name string) (*UserRecord, error) {
+	return multiplexer.LookupUser(ctx, name)
+}
+
+// LookupUserByUID returns the record of the user with the given UID from the
+// multiplexer.
+func LookupUserByUID(ctx context.Context, uid uint32) (*UserRecord, error) {
+	return multiplexer.LookupUserByUID(ctx, uid)
+}
+
+// Users returns the records of all users from the multiplexer.
+func Users(ctx context.Context) ([]*UserRecord, error) {
+	return multiplexer.Users(ctx)
+}
+
+// LookupGroup returns the record of the group called name from the
+// multiplexer.
+func LookupGroup(ctx context.Context, name string) (*GroupRecord, error) {
+	return multiplexer.LookupGroup(ctx, name)
+}
+
+// LookupGroupByGID returns the record of the group with the given GID from
+// the multiplexer.
+func LookupGroupByGID(ctx context.Context, gid uint32) (*GroupRecord, error) {
+	return multiplexer.LookupGroupByGID(ctx, gid)
+}
+
+// Groups returns the records of all groups from the multiplexer.
+func Groups(ctx context.Context) ([]*GroupRecord, error) {
+	return multiplexer.Groups(ctx)
+}
+
+// Memberships returns group memberships from the multiplexer, see
+// Client.Memberships.
+func Memberships(ctx context.Context, userName, groupName string) ([]Membership, error) {
+	return multiplexer.Memberships(ctx, userName, groupName)
+}
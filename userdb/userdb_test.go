@@ -0,0 +1,170 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package userdb
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// serve runs a fake user database service called service in SocketDir,
+// answering each request with the replies handler returns for it.
+func serve(t *testing.T, service string, handler func(method string, parameters map[string]interface{}, more bool) []string) func() {
+	l, err := net.Listen("unix", filepath.Join(SocketDir, service))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					msg, err := r.ReadBytes(0)
+					if err != nil {
+						return
+					}
+					var req struct {
+						Method     string                 `json:"method"`
+						Parameters map[string]interface{} `json:"parameters"`
+						More       bool                   `json:"more"`
+					}
+					if err := json.Unmarshal(msg[:len(msg)-1], &req); err != nil {
+						return
+					}
+					for _, reply := range handler(req.Method, req.Parameters, req.More) {
+						if _, err := conn.Write([]byte(reply + "\x00")); err != nil {
+							return
+						}
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return func() { l.Close() }
+}
+
+const (
+	rootRecord = `{"userName":"root","uid":0,"gid":0,"homeDirectory":"/root","shell":"/bin/sh","disposition":"intrinsic","service":"io.systemd.NameServiceSwitch"}`
+	userRecord = `{"userName":"alice","realName":"Alice","uid":1000,"gid":1000,"memberOf":["wheel"],"disposition":"regular","storage":"luks","service":"io.systemd.Home"}`
+)
+
+func TestLookup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-systemd-userdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer func(d string) { SocketDir = d }(SocketDir)
+	SocketDir = dir
+
+	stop := serve(t, MultiplexerService, func(method string, p map[string]interface{}, more bool) []string {
+		if p["service"] != MultiplexerService {
+			return []string{`{"error":"io.systemd.UserDatabase.BadService"}`}
+		}
+		switch method {
+		case "io.systemd.UserDatabase.GetUserRecord":
+			switch {
+			case more:
+				return []string{
+					`{"parameters":{"record":` + rootRecord + `,"incomplete":false},"continues":true}`,
+					`{"parameters":{"record":` + userRecord + `,"incomplete":true}}`,
+				}
+			case p["userName"] == "alice" || p["uid"] == 1000.0:
+				return []string{`{"parameters":{"record":` + userRecord + `,"incomplete":true}}`}
+			case p["uid"] == 0.0:
+				return []string{`{"parameters":{"record":` + rootRecord + `,"incomplete":false}}`}
+			}
+		case "io.systemd.UserDatabase.GetGroupRecord":
+			if more {
+				return []string{`{"error":"io.systemd.UserDatabase.NoRecordFound"}`}
+			}
+			if p["groupName"] == "wheel" {
+				return []string{`{"parameters":{"record":{"groupName":"wheel","gid":10,"members":["alice"]},"incomplete":false}}`}
+			}
+		case "io.systemd.UserDatabase.GetMemberships":
+			if p["userName"] == "alice" {
+				return []string{
+					`{"parameters":{"userName":"alice","groupName":"wheel"},"continues":true}`,
+					`{"parameters":{"userName":"alice","groupName":"audio"}}`,
+				}
+			}
+		}
+		return []string{`{"error":"io.systemd.UserDatabase.NoRecordFound"}`}
+	})
+	defer stop()
+
+	ctx := context.Background()
+	u, err := LookupUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.UserName != "alice" || u.RealName != "Alice" || u.UID == nil || *u.UID != 1000 || !reflect.DeepEqual(u.MemberOf, []string{"wheel"}) || !u.Incomplete || u.Service != "io.systemd.Home" {
+		t.Errorf("unexpected record %+v", u)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(u.Raw, &raw); err != nil || raw["storage"] != "luks" {
+		t.Errorf("expected raw record to keep all fields, got %s", u.Raw)
+	}
+
+	if u, err := LookupUserByUID(ctx, 0); err != nil || u.UserName != "root" || u.Incomplete {
+		t.Errorf("unexpected record %+v, %v", u, err)
+	}
+	if _, err := LookupUser(ctx, "nobody-here"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	users, err := Users(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].UserName != "root" || users[1].UserName != "alice" {
+		t.Errorf("unexpected users %+v", users)
+	}
+
+	g, err := LookupGroup(ctx, "wheel")
+	if err != nil || g.GID == nil || *g.GID != 10 || !reflect.DeepEqual(g.Members, []string{"alice"}) {
+		t.Errorf("unexpected group %+v, %v", g, err)
+	}
+	if groups, err := Groups(ctx); err != nil || len(groups) != 0 {
+		t.Errorf("expected no groups, got %+v, %v", groups, err)
+	}
+
+	memberships, err := Memberships(ctx, "alice", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Membership{{"alice", "wheel"}, {"alice", "audio"}}
+	if !reflect.DeepEqual(memberships, expected) {
+		t.Errorf("expected %+v, got %+v", expected, memberships)
+	}
+
+	if _, err := (&Client{Service: "io.systemd.Home"}).LookupUser(ctx, "alice"); err == nil {
+		t.Error("expected error for service without socket")
+	}
+}
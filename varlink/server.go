@@ -0,0 +1,228 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package varlink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// Errors of the org.varlink.service interface every service may reply with.
+const (
+	ErrorMethodNotFound   = "org.varlink.service.MethodNotFound"
+	ErrorInvalidParameter = "org.varlink.service.InvalidParameter"
+	ErrorExpectedMore     = "org.varlink.service.ExpectedMore"
+	ErrorPermissionDenied = "org.varlink.service.PermissionDenied"
+	ErrorInternal         = "org.varlink.service.InternalError"
+)
+
+// NewError returns an error reply called name with the given parameters,
+// which must marshal to a JSON object or be nil.
+func NewError(name string, parameters interface{}) *Error {
+	e := &Error{Name: name}
+	if parameters != nil {
+		e.Parameters, _ = json.Marshal(parameters)
+	}
+	return e
+}
+
+// MethodCall is a method call received by a Server.
+type MethodCall struct {
+	Method     string
+	Parameters json.RawMessage
+	More       bool // The caller accepts several replies
+	Oneway     bool // The caller does not want a reply
+
+	conn    net.Conn
+	replied bool
+}
+
+type outgoingReply struct {
+	Parameters interface{} `json:"parameters,omitempty"`
+	Continues  bool        `json:"continues,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Reply sends the last reply to the call, with parameters that must marshal
+// to a JSON object or be nil.
+func (c *MethodCall) Reply(parameters interface{}) error {
+	return c.send(outgoingReply{Parameters: parameters})
+}
+
+// ReplyContinues sends a reply that is followed by more replies, which is
+// only possible if the caller set More.
+func (c *MethodCall) ReplyContinues(parameters interface{}) error {
+	if !c.More {
+		return errors.New("caller does not accept more replies")
+	}
+	return c.send(outgoingReply{Parameters: parameters, Continues: true})
+}
+
+func (c *MethodCall) send(r outgoingReply) error {
+	if c.Oneway {
+		return nil
+	}
+	if c.replied {
+		return errors.New("call was already replied to")
+	}
+	if r.Parameters == nil {
+		r.Parameters = struct{}{}
+	}
+	msg, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	c.replied = !r.Continues
+	_, err = c.conn.Write(append(msg, 0))
+	return err
+}
+
+// Handler handles the calls received by a Server. A returned *Error is
+// sent as error reply, other errors as ErrorInternal without parameters.
+// If the handler returns nil without replying, an empty reply is sent.
+type Handler func(ctx context.Context, call *MethodCall) error
+
+// Server is a minimal varlink service, handling the calls of each
+// connection in order.
+type Server struct {
+	Handler Handler
+}
+
+// Listen listens on the unix socket at path, replacing a stale socket left
+// by an earlier instance of the service. It fails if another server is still
+// listening on path.
+func Listen(path string) (net.Listener, error) {
+	if fi, err := os.Lstat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			conn.Close()
+			return nil, &net.OpError{Op: "listen", Net: "unix", Addr: &net.UnixAddr{Name: path, Net: "unix"}, Err: syscall.EADDRINUSE}
+		}
+		if isConnRefused(err) {
+			os.Remove(path)
+		}
+	}
+	return net.Listen("unix", path)
+}
+
+// isConnRefused returns whether err is the error dialing a unix socket nobody
+// listens on.
+func isConnRefused(err error) bool {
+	if op, ok := err.(*net.OpError); ok {
+		err = op.Err
+	}
+	if sc, ok := err.(*os.SyscallError); ok {
+		err = sc.Err
+	}
+	return err == syscall.ECONNREFUSED
+}
+
+// Serve accepts connections on l and handles their calls until ctx is done,
+// then closes l and all connections. It returns the error that stopped it,
+// nil when ctx is done.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.serveConn(ctx, conn)
+		}()
+	}
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+		}
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		msg, err := r.ReadBytes(0)
+		if err != nil {
+			return
+		}
+		var req struct {
+			Method     string          `json:"method"`
+			Parameters json.RawMessage `json:"parameters"`
+			More       bool            `json:"more"`
+			Oneway     bool            `json:"oneway"`
+		}
+		if err := json.Unmarshal(msg[:len(msg)-1], &req); err != nil {
+			return
+		}
+		if len(req.Parameters) == 0 || string(req.Parameters) == "null" {
+			req.Parameters = json.RawMessage("{}")
+		}
+
+		call := &MethodCall{
+			Method:     req.Method,
+			Parameters: req.Parameters,
+			More:       req.More,
+			Oneway:     req.Oneway,
+			conn:       conn,
+		}
+		err = s.Handler(ctx, call)
+		if call.replied {
+			// Errors after the last reply cannot reach the caller.
+			continue
+		}
+		e, ok := err.(*Error)
+		if err != nil && !ok {
+			e = NewError(ErrorInternal, nil)
+		}
+		if e != nil {
+			var parameters interface{}
+			if len(e.Parameters) > 0 {
+				parameters = e.Parameters
+			}
+			err = call.send(outgoingReply{Parameters: parameters, Error: e.Name})
+		} else {
+			err = call.Reply(nil)
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package varlink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "varlink-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "socket")
+	l, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Server{Handler: func(ctx context.Context, call *MethodCall) error {
+		switch call.Method {
+		case "org.example.Echo":
+			var p struct {
+				Text string `json:"text"`
+			}
+			if err := json.Unmarshal(call.Parameters, &p); err != nil {
+				return NewError(ErrorInvalidParameter, nil)
+			}
+			return call.Reply(p)
+		case "org.example.Count":
+			if !call.More {
+				return NewError(ErrorExpectedMore, nil)
+			}
+			for n := 1; n < 3; n++ {
+				if err := call.ReplyContinues(map[string]int{"n": n}); err != nil {
+					return err
+				}
+			}
+			return call.Reply(map[string]int{"n": 3})
+		case "org.example.Nothing":
+			return nil
+		case "org.example.Fail":
+			return errors.New("disk on fire")
+		}
+		return NewError(ErrorMethodNotFound, map[string]string{"method": call.Method})
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Serve(ctx, l) }()
+
+	conn, err := Dial(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var echo struct {
+		Text string `json:"text"`
+	}
+	if err := conn.Call(context.Background(), "org.example.Echo", map[string]string{"text": "hello"}, &echo); err != nil || echo.Text != "hello" {
+		t.Errorf("unexpected echo %q, %v", echo.Text, err)
+	}
+
+	var got []int
+	err = conn.CallMore(context.Background(), "org.example.Count", nil, func(parameters json.RawMessage) error {
+		var reply struct {
+			N int `json:"n"`
+		}
+		if err := json.Unmarshal(parameters, &reply); err != nil {
+			return err
+		}
+		got = append(got, reply.N)
+		return nil
+	})
+	if err != nil || len(got) != 3 || got[2] != 3 {
+		t.Errorf("unexpected replies %v, %v", got, err)
+	}
+
+	if err := conn.Call(context.Background(), "org.example.Count", nil, nil); err == nil || err.(*Error).Name != ErrorExpectedMore {
+		t.Errorf("expected %s, got %v", ErrorExpectedMore, err)
+	}
+	err = conn.Call(context.Background(), "org.example.Unknown", nil, nil)
+	if e, ok := err.(*Error); !ok || e.Name != ErrorMethodNotFound || string(e.Parameters) != `{"method":"org.example.Unknown"}` {
+		t.Errorf("expected %s, got %v", ErrorMethodNotFound, err)
+	}
+	// Other errors are not passed on, but still answer the call and keep the
+	// connection open.
+	if err := conn.Call(context.Background(), "org.example.Fail", nil, nil); err == nil || err.Error() != ErrorInternal {
+		t.Errorf("expected %s, got %v", ErrorInternal, err)
+	}
+	if err := conn.Call(context.Background(), "org.example.Nothing", nil, nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := conn.CallOneway(context.Background(), "org.example.Echo", nil); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	// The oneway call got no reply, so the next reply belongs to this call.
+	if err := conn.Call(context.Background(), "org.example.Echo", map[string]string{"text": "again"}, &echo); err != nil || echo.Text != "again" {
+		t.Errorf("unexpected echo %q, %v", echo.Text, err)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error from Serve: %v", err)
+	}
+
+	// A stale socket is replaced.
+	l, err = net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+	l, err = Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	// A socket still listened on is not.
+	if l2, err := Listen(path); err == nil {
+		l2.Close()
+		t.Fatal("listening on a socket in use")
+	}
+	if conn, err := net.Dial("unix", path); err != nil {
+		t.Errorf("socket taken away from the running server: %v", err)
+	} else {
+		conn.Close()
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package varlink provides a minimal client and server for the varlink IPC
+// protocol spoken by many systemd services over unix sockets.  See
+// https://varlink.org/
+package varlink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Error is an error reply of a varlink service.
+type Error struct {
+	Name       string          // The qualified error name, e.g. org.varlink.service.MethodNotFound
+	Parameters json.RawMessage // The error parameters, if any
+}
+
+func (e *Error) Error() string {
+	if len(e.Parameters) == 0 || string(e.Parameters) == "{}" {
+		return e.Name
+	}
+	return fmt.Sprintf("%s: %s", e.Name, e.Parameters)
+}
+
+// ErrBroken is returned by calls on a connection that an earlier call left
+// without reading all of its replies, e.g. because it was cancelled.
+var ErrBroken = errors.New("varlink: connection broken by an interrupted call")
+
+// Conn is a connection to a varlink service.
+type Conn struct {
+	mu     sync.Mutex
+	conn   net.Conn
+	r      *bufio.Reader
+	broken bool
+}
+
+type request struct {
+	Method     string      `json:"method"`
+	Parameters interface{} `json:"parameters"`
+	More       bool        `json:"more,omitempty"`
+	Oneway     bool        `json:"oneway,omitempty"`
+}
+
+type reply struct {
+	Parameters json.RawMessage `json:"parameters"`
+	Continues  bool            `json:"continues"`
+	Error      string          `json:"error"`
+}
+
+// Dial connects to the varlink service listening on the unix socket at path.
+func Dial(ctx context.Context, path string) (*Conn, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Call calls method with the given parameters, which must marshal to a JSON
+// object or be nil, and unmarshals the reply parameters into reply unless it
+// is nil. Error replies are returned as *Error.
+func (c *Conn) Call(ctx context.Context, method string, parameters, reply interface{}) error {
+	return c.call(ctx, request{Method: method, Parameters: parameters}, func(parameters json.RawMessage) error {
+		if reply == nil || len(parameters) == 0 {
+			return nil
+		}
+		return json.Unmarshal(parameters, reply)
+	})
+}
+
+// CallMore calls method with the more flag set, asking the service for a
+// stream of replies, and calls fn with the parameters of every reply. It
+// returns once the service sent its last reply, fn returns an error or ctx
+// is done. When returning before the last reply, the connection is closed
+// and later calls fail with ErrBroken.
+func (c *Conn) CallMore(ctx context.Context, method string, parameters interface{}, fn func(parameters json.RawMessage) error) error {
+	return c.call(ctx, request{Method: method, Parameters: parameters, More: true}, fn)
+}
+
+// CallOneway calls method without waiting for, or receiving, a reply.
+func (c *Conn) CallOneway(ctx context.Context, method string, parameters interface{}) error {
+	return c.call(ctx, request{Method: method, Parameters: parameters, Oneway: true}, nil)
+}
+
+func (c *Conn) call(ctx context.Context, req request, fn func(json.RawMessage) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.broken {
+		return ErrBroken
+	}
+
+	// Replies left unread would be taken for the replies of the next call,
+	// so the connection is closed unless the exchange completes.
+	complete := false
+	defer func() {
+		if !complete {
+			c.broken = true
+			c.conn.Close()
+		}
+	}()
+
+	if req.Parameters == nil {
+		req.Parameters = struct{}{}
+	}
+	msg, err := json.Marshal(req)
+	if err != nil {
+		complete = true
+		return err
+	}
+
+	// Unblock reads and writes once ctx is done. The deadline is only
+	// cleared after the goroutine exited, so that a late cancellation
+	// cannot leave the connection timed out.
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-ctx.Done():
+			c.conn.SetDeadline(time.Unix(1, 0))
+		case <-stop:
+		}
+	}()
+	defer func() {
+		close(stop)
+		<-done
+		c.conn.SetDeadline(time.Time{})
+	}()
+
+	if _, err := c.conn.Write(append(msg, 0)); err != nil {
+		return ctxErr(ctx, err)
+	}
+	if req.Oneway {
+		complete = true
+		return nil
+	}
+
+	for {
+		msg, err := c.r.ReadBytes(0)
+		if err != nil {
+			return ctxErr(ctx, err)
+		}
+
+		var r reply
+		if err := json.Unmarshal(msg[:len(msg)-1], &r); err != nil {
+			return err
+		}
+		if r.Error != "" {
+			complete = true
+			return &Error{Name: r.Error, Parameters: r.Parameters}
+		}
+		err = fn(r.Parameters)
+		if !r.Continues {
+			complete = true
+			return err
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ctxErr prefers the context error over the I/O error it caused.
+func ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// Call dials the service at path, performs a single method call and closes
+// the connection again.
+func Call(ctx context.Context, path, method string, parameters, reply interface{}) error {
+	conn, err := Dial(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Call(ctx, method, parameters, reply)
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 CoreOS, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package varlink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// serve runs a fake varlink service answering requests with handler.
+func serve(t *testing.T, handler func(method string, more bool) []string) (string, func()) {
+	dir, err := ioutil.TempDir("", "varlink-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "socket")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					msg, err := r.ReadBytes(0)
+					if err != nil {
+						return
+					}
+					var req struct {
+						Method string `json:"method"`
+						More   bool   `json:"more"`
+					}
+					if err := json.Unmarshal(msg[:len(msg)-1], &req); err != nil {
+						return
+					}
+					for _, reply := range handler(req.Method, req.More) {
+						if _, err := conn.Write([]byte(reply + "\x00")); err != nil {
+							return
+						}
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return path, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestCall(t *testing.T) {
+	path, cleanup := serve(t, func(method string, more bool) []string {
+		switch method {
+		case "org.example.Ping":
+			return []string{`{"parameters":{"pong":true}}`}
+		default:
+			return []string{`{"error":"org.varlink.service.MethodNotFound","parameters":{"method":"` + method + `"}}`}
+		}
+	})
+	defer cleanup()
+
+	conn, err := Dial(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var reply struct {
+		Pong bool `json:"pong"`
+	}
+	if err := conn.Call(context.Background(), "org.example.Ping", nil, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Pong {
+		t.Fatal("reply parameters not decoded")
+	}
+
+	// The connection stays usable after an error reply.
+	err = conn.Call(context.Background(), "org.example.Unknown", nil, nil)
+	verr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected *Error but got %v", err)
+	}
+	if verr.Name != "org.varlink.service.MethodNotFound" {
+		t.Fatalf("unexpected error name %s", verr.Name)
+	}
+	if err := conn.Call(context.Background(), "org.example.Ping", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallMore(t *testing.T) {
+	path, cleanup := serve(t, func(method string, more bool) []string {
+		if !more {
+			return []string{`{"error":"org.varlink.service.ExpectedMore","parameters":{}}`}
+		}
+		return []string{
+			`{"parameters":{"n":1},"continues":true}`,
+			`{"parameters":{"n":2},"continues":true}`,
+			`{"parameters":{"n":3}}`,
+		}
+	})
+	defer cleanup()
+
+	conn, err := Dial(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	var got []int
+	err = conn.CallMore(context.Background(), "org.example.Count", nil, func(parameters json.RawMessage) error {
+		var reply struct {
+			N int `json:"n"`
+		}
+		if err := json.Unmarshal(parameters, &reply); err != nil {
+			return err
+		}
+		got = append(got, reply.N)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Fatalf("unexpected replies %v", got)
+	}
+}
+
+func TestCallCancel(t *testing.T) {
+	path, cleanup := serve(t, func(method string, more bool) []string {
+		return nil
+	})
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	err := Call(ctx, path, "org.example.Hang", nil, nil)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("expected %v but got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestCallCancelAfterReply(t *testing.T) {
+	path, cleanup := serve(t, func(method string, more bool) []string {
+		return []string{`{"parameters":{}}`}
+	})
+	defer cleanup()
+
+	conn, err := Dial(context.Background(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Cancelling a context once its call returned must not affect later
+	// calls on the same connection.
+	for i := 0; i < 100; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := conn.Call(ctx, "org.example.Ping", nil, nil)
+		cancel()
+		if err != nil {
+			t.Fatalf("call %d: %v", i, err)
+		}
+	}
+}
+
+func TestCallInterrupted(t *testing.T) {
+	path, cleanup := serve(t, func(method string, more bool) []string {
+		switch method {
+		case "org.example.Count":
+			return []string{
+				`{"parameters":{"n":1},"continues":true}`,
+				`{"parameters":{"n":2}}`,
+			}
+		case "org.example.Hang":
+			return nil
+		default:
+			return []string{`{"parameters":{}}`}
+		}
+	})
+	defer cleanup()
+
+	stop := errors.New("stop")
+	for i, interrupt := range []func(conn *Conn) error{
+		func(conn *Conn) error {
+			return conn.CallMore(context.Background(), "org.example.Count", nil, func(json.RawMessage) error {
+				return stop
+			})
+		},
+		func(conn *Conn) error {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			return conn.Call(ctx, "org.example.Hang", nil, nil)
+		},
+	} {
+		conn, err := Dial(context.Background(), path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := interrupt(conn); err != stop && err != context.DeadlineExceeded {
+			t.Errorf("case %d: unexpected error %v", i, err)
+		}
+		// Later calls must not read the replies left over by the
+		// interrupted one.
+		if err := conn.Call(context.Background(), "org.example.Ping", nil, nil); err != ErrBroken {
+			t.Errorf("case %d: expected %v but got %v", i, ErrBroken, err)
+		}
+		conn.Close()
+	}
+}